@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+var (
+	abortErrorRateFlag = flag.String("abort-on-error-rate", "", "Abort load phase once errors rate over the last -abort-window exceeds given percent like \"10%\", "+
+		"so collapsed target isn't loaded for the rest of -d. Windows with less than -min-samples requests aren't checked")
+	abortConsecutiveErrors = flag.Int("abort-on-consecutive-errors", 0, "Abort load phase once given number of errors is counted without successful response between them. "+
+		"Zero disables the check")
+	abortWindow = flag.Duration("abort-window", time.Second, "Period of checking stop conditions of -abort-on-error-rate and -abort-on-consecutive-errors")
+)
+
+// abortErrorRate is a parsed -abort-on-error-rate in percent. Is zero if it isn't set
+var abortErrorRate float64
+
+// aborted describes why load phase was stopped by stop condition. Is nil unless it was aborted
+var aborted *report.Abort
+
+// applyAbort validates stop conditions of load phase
+func applyAbort() {
+	if *abortErrorRateFlag != "" {
+		v, err := parseAbortErrorRate(*abortErrorRateFlag)
+		if err != nil {
+			usageAndExit(err.Error())
+		}
+		abortErrorRate = v
+	}
+	if *abortConsecutiveErrors < 0 {
+		usageAndExit("-abort-on-consecutive-errors can't be negative")
+	}
+	if *abortWindow <= 0 {
+		usageAndExit("-abort-window must be positive")
+	}
+}
+
+// parseAbortErrorRate parses percent of -abort-on-error-rate like "10%" or "2.5"
+func parseAbortErrorRate(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || v <= 0 || v > 100 {
+		return 0, fmt.Errorf("-abort-on-error-rate must be percent in range (0..100] like \"10%%\"; got %q", s)
+	}
+	return v, nil
+}
+
+// abortEnabled returns true if any stop condition of load phase is set
+func abortEnabled() bool {
+	return abortErrorRate > 0 || *abortConsecutiveErrors > 0
+}
+
+// abortChecker checks stop conditions every -abort-window of load phase
+type abortChecker struct {
+	requests, errors uint64
+	// qps is a qps limit at the previous check, when target was still healthy
+	qps float64
+}
+
+func newAbortChecker() *abortChecker {
+	return &abortChecker{requests: client.RequestSum(), errors: client.Errors(), qps: qpsLimit()}
+}
+
+// check returns violated stop condition or empty string if target is healthy
+func (ac *abortChecker) check() string {
+	requests, errs := client.RequestSum(), client.Errors()
+	defer func() {
+		ac.requests, ac.errors = requests, errs
+	}()
+	if n := *abortConsecutiveErrors; n > 0 && client.ConsecutiveErrors() >= uint64(n) {
+		return fmt.Sprintf("%d consecutive errors reached -abort-on-consecutive-errors %d", client.ConsecutiveErrors(), n)
+	}
+	if abortErrorRate > 0 && requests-ac.requests >= *minSamples {
+		rate := float64(errs-ac.errors) / float64(requests-ac.requests) * 100
+		if rate > abortErrorRate {
+			return fmt.Sprintf("errors rate %.2f %% exceeds -abort-on-error-rate %.2f %%", rate, abortErrorRate)
+		}
+	}
+	ac.qps = qpsLimit()
+	return ""
+}
+
+// abort records that load phase started at start was stopped by violated stop condition
+func (ac *abortChecker) abort(reason string, start time.Time) {
+	aborted = &report.Abort{Reason: reason, After: time.Since(start).Seconds(), QPS: ac.qps}
+	r.Abort = aborted
+	slog.Warn("Load phase is aborted", "reason", reason, "after", aborted.After, "qps", aborted.QPS)
+	fmt.Fprintf(out, "Abort load phase: %s\n", reason)
+}
+
+func printAbort() {
+	if aborted == nil {
+		return
+	}
+	fmt.Fprintln(out, "------ Abort ------")
+	fmt.Fprintf(out, "Load phase was aborted after %.1fs: %s\n", aborted.After, aborted.Reason)
+	fmt.Fprintf(out, "Collapse began at qps %.2f\n\n", aborted.QPS)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/ratelimiter"
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseAbortErrorRate(t *testing.T) {
+	f := func(s string, expected float64, ok bool) {
+		t.Helper()
+		v, err := parseAbortErrorRate(s)
+		if (err == nil) != ok {
+			t.Fatalf("Unexpected error for %q: %v", s, err)
+		}
+		if v != expected {
+			t.Errorf("Unexpected errors rate for %q. Got: %v; Expected: %v", s, v, expected)
+		}
+	}
+
+	f("10%", 10, true)
+	f(" 2.5 ", 2.5, true)
+	f("100%", 100, true)
+	f("0%", 0, false)
+	f("-5", 0, false)
+	f("100.1%", 0, false)
+	f("ten", 0, false)
+	f("", 0, false)
+}
+
+func TestAbortCheck(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	defer ln.Close()
+	var fail atomic.Bool
+	go fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {
+		if fail.Load() {
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		}
+	})
+
+	defer func(rate float64, consecutive int, samples uint64) {
+		abortErrorRate, *abortConsecutiveErrors, *minSamples = rate, consecutive, samples
+	}(abortErrorRate, *abortConsecutiveErrors, *minSamples)
+	defer func() { throttle = ratelimiter.NewLimiter() }()
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+
+	// window is a number of requests sent between checks at qps limit of throttle
+	type window struct {
+		requests int
+		fail     bool
+		qps      float64
+		// abort is true if check must find violated stop condition
+		abort bool
+	}
+	f := func(name string, rate float64, consecutive int, samples uint64, windows []window, expectedQPS float64) {
+		t.Helper()
+		abortErrorRate, *abortConsecutiveErrors, *minSamples = rate, consecutive, samples
+		throttle = ratelimiter.NewLimiter()
+		defer throttle.Stop()
+		throttle.SetLimit(windows[0].qps)
+		client = fastclient.New(req, time.Second, fasthttp.StatusOK)
+		client.ExpectedStatusCodes = map[int]bool{fasthttp.StatusOK: true}
+		defer client.Flush()
+		client.RunWorkers(1)
+		ac := newAbortChecker()
+		var sent uint64
+		for i, w := range windows {
+			fail.Store(w.fail)
+			throttle.SetLimit(w.qps)
+			for j := 0; j < w.requests; j++ {
+				client.Jobsch <- time.Now()
+			}
+			sent += uint64(w.requests)
+			deadline := time.Now().Add(5 * time.Second)
+			for client.RequestSum() < sent {
+				if time.Now().After(deadline) {
+					t.Fatalf("%s: requests of window %d weren't done in time", name, i)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if reason := ac.check(); (reason != "") != w.abort {
+				t.Fatalf("%s: unexpected result of check of window %d. Got: %q; Expected abort: %v", name, i, reason, w.abort)
+			}
+		}
+		if ac.qps != expectedQPS {
+			t.Errorf("%s: unexpected qps of the last healthy check. Got: %v; Expected: %v", name, ac.qps, expectedQPS)
+		}
+	}
+
+	f("consecutive errors below limit", 0, 3, 1, []window{
+		{requests: 2, fail: true, qps: 100},
+		{requests: 1, qps: 100},
+		{requests: 2, fail: true, qps: 100},
+	}, 100)
+	f("consecutive errors reach limit", 0, 3, 1, []window{
+		{requests: 2, fail: true, qps: 100},
+		{requests: 1, fail: true, qps: 100, abort: true},
+	}, 100)
+	f("window below min samples", 10, 0, 5, []window{
+		{requests: 4, fail: true, qps: 100},
+		{requests: 3, fail: true, qps: 100},
+	}, 100)
+	f("window over min samples", 10, 0, 5, []window{
+		{requests: 4, fail: true, qps: 100},
+		{requests: 5, fail: true, qps: 100, abort: true},
+	}, 100)
+	// qps of failed window isn't recorded, since collapse began before it
+	f("qps stays at last healthy limit", 10, 0, 5, []window{
+		{requests: 5, qps: 100},
+		{requests: 5, qps: 200},
+		{requests: 5, fail: true, qps: 400, abort: true},
+	}, 200)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/valyala/fasthttp"
+)
+
+// affinityKey is a cookie or header which contains stable per-worker key,
+// so load balancer routes requests of every worker to the same backend
+type affinityKey struct {
+	cookie bool
+	name   string
+}
+
+// parseAffinityKey parses affinity key in format "cookie:NAME" or "header:NAME"
+func parseAffinityKey(s string) (*affinityKey, error) {
+	n := strings.Index(s, ":")
+	if n < 0 || strings.TrimSpace(s[n+1:]) == "" {
+		return nil, fmt.Errorf("affinity key %q must be in format \"cookie:NAME\" or \"header:NAME\"", s)
+	}
+	ak := &affinityKey{name: strings.TrimSpace(s[n+1:])}
+	switch strings.ToLower(s[:n]) {
+	case "cookie":
+		ak.cookie = true
+	case "header":
+	default:
+		return nil, fmt.Errorf("unsupported kind %q of affinity key; supported kinds are cookie and header", s[:n])
+	}
+	return ak, nil
+}
+
+// value returns key of n-th worker. Keys depend on -seed,
+// so tests with the same seed send the same keys
+func (ak *affinityKey) value(n int) string {
+	return fmt.Sprintf("fasthttploader-%x-%d", uint32(*seed), n)
+}
+
+func (ak *affinityKey) set(req *fasthttp.Request, value string) {
+	if ak.cookie {
+		req.Header.SetCookie(ak.name, value)
+	} else {
+		req.Header.Set(ak.name, value)
+	}
+}
+
+// modifier returns fastclient.Client.NewModifier which sets affinity key
+// of worker after modifier returned by newModifier, if it isn't nil
+func (ak *affinityKey) modifier(newModifier func() fastclient.Modifier) func() fastclient.Modifier {
+	var workers int32
+	return func() fastclient.Modifier {
+		value := ak.value(int(atomic.AddInt32(&workers, 1)))
+		var modify fastclient.Modifier
+		if newModifier != nil {
+			modify = newModifier()
+		}
+		return func(req *fasthttp.Request) error {
+			if modify != nil {
+				if err := modify(req); err != nil {
+					return err
+				}
+			}
+			ak.set(req, value)
+			return nil
+		}
+	}
+}
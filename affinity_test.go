@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestParseAffinityKey(t *testing.T) {
+	f := func(s string, cookie bool, name string) {
+		t.Helper()
+		ak, err := parseAffinityKey(s)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if ak.cookie != cookie || ak.name != name {
+			t.Errorf("Unexpected result for %q. Got: %v, %q; Expected: %v, %q", s, ak.cookie, ak.name, cookie, name)
+		}
+	}
+
+	f("cookie:SESSIONID", true, "SESSIONID")
+	f("Header: X-Session-Id", false, "X-Session-Id")
+}
+
+func TestParseAffinityKeyError(t *testing.T) {
+	for _, s := range []string{"", "SESSIONID", "cookie:", "query:id"} {
+		if _, err := parseAffinityKey(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// annotation is an event from -annotate file
+type annotation struct {
+	// offset is a time since test start. Is used if at is zero
+	offset time.Duration
+	at     time.Time
+
+	label string
+}
+
+// testStart is a start time of report timeline
+var testStart time.Time
+
+// readAnnotations reads events in format "timestamp,label" from CSV file.
+// The first line is skipped if it is a header like "timestamp,label"
+func readAnnotations(path string) ([]annotation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open annotations file: %s", err)
+	}
+	defer f.Close()
+
+	cr := csv.NewReader(f)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read annotations file %q: %s", path, err)
+	}
+	var result []annotation
+	for i, rec := range records {
+		a, err := parseAnnotationTime(rec[0])
+		if err != nil {
+			if i == 0 {
+				// header
+				continue
+			}
+			return nil, fmt.Errorf("cannot parse line %d of annotations file %q: %s", i+1, path, err)
+		}
+		a.label = rec[1]
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+// parseAnnotationTime parses offset since test start like "120s", "t=2m" or "120",
+// or time in RFC3339 format like "2006-01-02T15:04:05Z"
+func parseAnnotationTime(s string) (annotation, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "t=")
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		// plain number is a number of seconds
+		s += "s"
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			return annotation{}, fmt.Errorf("offset %q can't be negative", s)
+		}
+		return annotation{offset: d}, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return annotation{}, fmt.Errorf("timestamp %q must be offset like \"120s\" or time in RFC3339 format", s)
+	}
+	return annotation{at: t}, nil
+}
+
+// applyAnnotations adds annotations to report. Annotations outside of test timeline are skipped
+func applyAnnotations(annotations []annotation) {
+	end := float64(len(r.Connections)) * r.Interval
+	if n := len(r.Timestamps); n > 0 {
+		end = r.Timestamps[n-1]
+	}
+	for _, a := range annotations {
+		d := a.offset
+		if !a.at.IsZero() {
+			d = a.at.Sub(testStart)
+		}
+		if d < 0 || d.Seconds() > end {
+			fmt.Fprintf(out, "Annotation %q is outside of test timeline and is skipped\n", a.label)
+			continue
+		}
+		r.Annotations = append(r.Annotations, report.Annotation{Time: d.Seconds(), Label: a.label})
+	}
+}
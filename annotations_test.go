@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAnnotationTime(t *testing.T) {
+	f := func(s string, offset time.Duration, at time.Time) {
+		t.Helper()
+		a, err := parseAnnotationTime(s)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if a.offset != offset || !a.at.Equal(at) {
+			t.Errorf("Unexpected result for %q. Got: %s, %s; Expected: %s, %s", s, a.offset, a.at, offset, at)
+		}
+	}
+
+	f("120s", 120*time.Second, time.Time{})
+	f("t=2m", 2*time.Minute, time.Time{})
+	f("1.5", 1500*time.Millisecond, time.Time{})
+	f("2006-01-02T15:04:05Z", 0, time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC))
+}
+
+func TestParseAnnotationTimeError(t *testing.T) {
+	for _, s := range []string{"", "-5s", "yesterday", "2006-01-02"} {
+		if _, err := parseAnnotationTime(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/valyala/fasthttp"
+)
+
+var assertHeaders headerList
+
+func init() {
+	flag.Var(&assertHeaders, "assert-header", "Assert that response header contains value, e.g. \"Content-Type: application/json\". "+
+		"Is set multiple times. Responses failed assertion aren't successful and are counted as errors")
+}
+
+// responseAssertions are assertions of responses set by -assert-body-contains, -assert-body-regex and -assert-header
+var responseAssertions []fastclient.Assertion
+
+// applyAssertions creates assertions of responses by -assert-* flags
+func applyAssertions() {
+	if *probeFile != "" {
+		usageAndExit("-assert-body-contains, -assert-body-regex and -assert-header can't be used with -probe")
+	}
+	if *assertBodyContains != "" {
+		substr := []byte(*assertBodyContains)
+		responseAssertions = append(responseAssertions, fastclient.Assertion{
+			Name: fmt.Sprintf("body contains %q", *assertBodyContains),
+			Check: func(resp *fasthttp.Response) bool {
+				return bytes.Contains(responseBody(resp), substr)
+			},
+		})
+	}
+	if *assertBodyRegex != "" {
+		re, err := regexp.Compile(*assertBodyRegex)
+		if err != nil {
+			usageAndExit(fmt.Sprintf("cannot parse -assert-body-regex: %s", err))
+		}
+		responseAssertions = append(responseAssertions, fastclient.Assertion{
+			Name: fmt.Sprintf("body matches %q", *assertBodyRegex),
+			Check: func(resp *fasthttp.Response) bool {
+				return re.Match(responseBody(resp))
+			},
+		})
+	}
+	for _, h := range assertHeaders {
+		n := strings.Index(h, ":")
+		name, value := strings.TrimSpace(h[:n]), []byte(strings.TrimSpace(h[n+1:]))
+		responseAssertions = append(responseAssertions, fastclient.Assertion{
+			Name: fmt.Sprintf("header %s contains %q", name, value),
+			Check: func(resp *fasthttp.Response) bool {
+				v := resp.Header.Peek(name)
+				return len(v) > 0 && bytes.Contains(v, value)
+			},
+		})
+	}
+}
+
+// responseBody returns body of resp decoded according to its Content-Encoding.
+// Body is returned as is if it can't be decoded, so assertion of content fails
+func responseBody(resp *fasthttp.Response) []byte {
+	var b []byte
+	var err error
+	switch string(bytes.ToLower(resp.Header.Peek(fasthttp.HeaderContentEncoding))) {
+	case "gzip", "x-gzip":
+		b, err = resp.BodyGunzip()
+	case "deflate":
+		b, err = resp.BodyInflate()
+	default:
+		return resp.Body()
+	}
+	if err != nil {
+		return resp.Body()
+	}
+	return b
+}
+
+// assertionFailures returns total number of failed assertions.
+// Response failed several assertions is counted by every of them
+func assertionFailures(failures map[string]uint64) uint64 {
+	var n uint64
+	for _, v := range failures {
+		n += v
+	}
+	return n
+}
+
+// printAssertionFailures prints number of responses failed every assertion
+func printAssertionFailures() {
+	failures := client.AssertionFailures()
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %d", name, failures[name]))
+	}
+	fmt.Fprintf(out, "Assertion failures: %s\n", strings.Join(parts, "; "))
+}
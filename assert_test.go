@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestResponseBody(t *testing.T) {
+	f := func(encoding string, body []byte, expected string) {
+		t.Helper()
+		var resp fasthttp.Response
+		if encoding != "" {
+			resp.Header.Set(fasthttp.HeaderContentEncoding, encoding)
+		}
+		resp.SetBody(body)
+		if got := string(responseBody(&resp)); got != expected {
+			t.Errorf("Unexpected body with encoding %q. Got: %q; Expected: %q", encoding, got, expected)
+		}
+	}
+
+	f("", []byte("ok"), "ok")
+	f("gzip", fasthttp.AppendGzipBytes(nil, []byte("ok")), "ok")
+	f("deflate", fasthttp.AppendDeflateBytes(nil, []byte("ok")), "ok")
+	// body which can't be decoded is asserted as is
+	f("gzip", []byte("ok"), "ok")
+}
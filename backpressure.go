@@ -0,0 +1,14 @@
+package main
+
+import "fmt"
+
+// printBackpressure prints share of jobs of stage, which waited for free client,
+// and number of ones, which waited for free space in full queue. Is no-op if clients kept up with jobs
+func printBackpressure() {
+	queued, waited := client.JobsQueued(), client.JobsWaited()
+	if waited == 0 {
+		return
+	}
+	fmt.Fprintf(out, "Backpressure: %.2f %% of jobs queued behind others; waited for full queue: %d (-queue-size %d)\n",
+		float64(waited)/float64(queued)*100, client.QueueFull(), *queueSize)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// prevStages are stats of stages of latency breakdown at previous sample. Is reset by new client
+var prevStages map[string]fastclient.StageStat
+
+// breakdownSample returns average time in seconds of every part of latency of requests
+// completed since previous sample. Setup of connections established since then is spread over requests
+// and is subtracted from their sending. Is empty if no requests were completed
+func breakdownSample() map[string]float64 {
+	stages := client.Breakdown()
+	if stages[fastclient.StageSend].Count < prevStages[fastclient.StageSend].Count {
+		// stats are reset after warmup
+		prevStages = nil
+	}
+	delta := func(stage string) (uint64, float64) {
+		return stages[stage].Count - prevStages[stage].Count, stages[stage].Sum - prevStages[stage].Sum
+	}
+	n, send := delta(fastclient.StageSend)
+	result := make(map[string]float64)
+	if n > 0 {
+		var setup float64
+		for _, stage := range []string{fastclient.StageDNS, fastclient.StageConnect, fastclient.StageHandshake} {
+			_, sum := delta(stage)
+			setup += sum
+		}
+		setup = math.Min(setup/float64(n), send/float64(n))
+		_, firstByte := delta(fastclient.StageFirstByte)
+		_, transfer := delta(fastclient.StageTransfer)
+		result[report.BreakdownSetup] = setup
+		result[report.BreakdownSend] = send/float64(n) - setup
+		result[report.BreakdownFirstByte] = firstByte / float64(n)
+		result[report.BreakdownTransfer] = transfer / float64(n)
+	}
+	prevStages = stages
+	return result
+}
+
+// stageLatencies returns latency of every stage of requests with observations in order of request
+func stageLatencies() []report.StageLatency {
+	stages := client.Breakdown()
+	var result []report.StageLatency
+	for _, stage := range fastclient.BreakdownStages {
+		st, ok := stages[stage]
+		if !ok {
+			continue
+		}
+		l := st.Latency
+		result = append(result, report.StageLatency{
+			Stage:   stage,
+			Count:   st.Count,
+			Latency: report.Latency{P50: l.P50, P90: l.P90, P95: l.P95, P99: l.P99, Max: l.Max},
+		})
+	}
+	return result
+}
+
+// printLatencyBreakdown prints percentiles of every stage of requests completed during stage
+func printLatencyBreakdown() {
+	fmt.Fprintln(out, "Latency breakdown:")
+	for _, sl := range stageLatencies() {
+		fmt.Fprintf(out, "  %s: count %d; p50: %s; p99: %s; max: %s\n", sl.Stage, sl.Count,
+			formatLatency(sl.P50), formatLatency(sl.P99), formatLatency(sl.Max))
+	}
+}
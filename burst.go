@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// burstPattern is an arrival pattern, when burst of requests
+// is sent at once and followed by idle period
+type burstPattern struct {
+	requests int
+	idle     time.Duration
+}
+
+// parseBurstPattern parses pattern in format "100req/50ms-idle"
+func parseBurstPattern(s string) (*burstPattern, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 || !strings.HasSuffix(parts[0], "req") {
+		return nil, fmt.Errorf("cannot parse burst pattern %q: must be in format \"100req/50ms-idle\"", s)
+	}
+	bp := &burstPattern{}
+	var err error
+	if bp.requests, err = strconv.Atoi(strings.TrimSuffix(parts[0], "req")); err != nil {
+		return nil, fmt.Errorf("cannot parse number of requests of burst pattern %q: %s", s, err)
+	}
+	if bp.idle, err = time.ParseDuration(strings.TrimSuffix(parts[1], "-idle")); err != nil {
+		return nil, fmt.Errorf("cannot parse idle period of burst pattern %q: %s", s, err)
+	}
+	if bp.requests < 1 || bp.idle <= 0 {
+		return nil, fmt.Errorf("number of requests and idle period of burst pattern %q must be positive", s)
+	}
+	return bp, nil
+}
+
+func (bp *burstPattern) String() string {
+	return fmt.Sprintf("%dreq/%s-idle", bp.requests, bp.idle)
+}
+
+// qps returns average rate of pattern
+func (bp *burstPattern) qps() float64 {
+	return float64(bp.requests) / bp.idle.Seconds()
+}
+
+// burstLoad sends bursts of requests until ctx is done.
+// Metrics of every burst, including following idle period, are stored to bursts
+func burstLoad(ctx context.Context, bp *burstPattern) {
+	meter := newStepMeter()
+	for {
+		for i := 0; i < bp.requests; i++ {
+			if !client.Enqueue(ctx, time.Now()) {
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bp.idle):
+		}
+		bursts = append(bursts, meter.next(bp.qps()))
+	}
+}
+
+func printBursts() {
+	if len(bursts) == 0 {
+		return
+	}
+
+	var latency, errorRate []float64
+	withErrors := 0
+	for _, b := range bursts {
+		if b.requests >= *minSamples {
+			latency = append(latency, b.p99)
+		}
+		errorRate = append(errorRate, b.errorRate)
+		if b.errorRate > 0 {
+			withErrors++
+		}
+	}
+	fmt.Fprintf(out, "------ Bursts (%s) ------\n", pattern)
+	fmt.Fprintf(out, "Bursts: %d; With errors: %d\n", len(bursts), withErrors)
+	if len(latency) > 0 {
+		med, max := medianAndMax(latency)
+		fmt.Fprintf(out, "p99 latency per burst: median %s; max %s\n", formatLatency(med), formatLatency(max))
+	} else {
+		fmt.Fprintf(out, "p99 latency per burst: insufficient data\n")
+	}
+	med, max := medianAndMax(errorRate)
+	fmt.Fprintf(out, "Errors rate per burst: median %.2f %%; max %.2f %%\n\n", med, max)
+}
+
+func medianAndMax(values []float64) (float64, float64) {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return sorted[len(sorted)/2], sorted[len(sorted)-1]
+}
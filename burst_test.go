@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBurstPattern(t *testing.T) {
+	f := func(s string, requests int, idle time.Duration) {
+		t.Helper()
+		bp, err := parseBurstPattern(s)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if bp.requests != requests || bp.idle != idle {
+			t.Errorf("Unexpected result for %q. Got: %d, %s; Expected: %d, %s", s, bp.requests, bp.idle, requests, idle)
+		}
+	}
+
+	f("100req/50ms-idle", 100, 50*time.Millisecond)
+	f("1req/1s", 1, time.Second)
+}
+
+func TestParseBurstPatternError(t *testing.T) {
+	for _, s := range []string{"", "100", "100/50ms", "req/50ms", "100req/", "0req/50ms-idle", "100req/-5ms-idle"} {
+		if _, err := parseBurstPattern(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
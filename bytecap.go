@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// byteUnits contains multipliers of supported size suffixes.
+// Suffixes are compared case-insensitively
+var byteUnits = []struct {
+	suffix string
+	size   float64
+}{
+	// longer suffixes go first, so "kib" isn't parsed as "b"
+	{"kib", 1 << 10}, {"mib", 1 << 20}, {"gib", 1 << 30}, {"tib", 1 << 40},
+	{"kb", 1e3}, {"mb", 1e6}, {"gb", 1e9}, {"tb", 1e12},
+	{"b", 1},
+}
+
+// parseBytes parses size like "10GB", "512MiB" or "1000".
+// Decimal suffixes (KB, MB, GB, TB) are powers of 1000, binary ones (KiB, MiB, GiB, TiB) are powers of 1024
+func parseBytes(s string) (uint64, error) {
+	str := strings.ToLower(strings.TrimSpace(s))
+	size := float64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(str, u.suffix) {
+			str, size = strings.TrimSpace(strings.TrimSuffix(str, u.suffix)), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse size %q: %s", s, err)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("size %q must be positive", s)
+	}
+	return uint64(n * size), nil
+}
+
+// formatBytes formats size with decimal suffix like "1.50 GB"
+func formatBytes(n uint64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	v, i := float64(n), 0
+	for v >= 1000 && i < len(units)-1 {
+		v /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.2f %s", v, units[i])
+}
+
+var (
+	// bytesSpent is a number of bytes transferred by clients of finished stages
+	bytesSpent uint64
+
+	// bytesCapReached is true if test was stopped by -max-bytes
+	bytesCapReached bool
+)
+
+// bytesTransferred returns number of bytes read and written since start of test
+func bytesTransferred() uint64 {
+	n := bytesSpent
+	if client != nil {
+		n += client.BytesRead() + client.BytesWritten()
+	}
+	return n
+}
+
+// isBytesCapReached returns true if bytes transferred by test reached -max-bytes
+func isBytesCapReached() bool {
+	if maxBytes > 0 && !bytesCapReached && bytesTransferred() >= maxBytes {
+		bytesCapReached = true
+	}
+	return bytesCapReached
+}
+
+// stageTimeout returns channel which fires after duration d,
+// once -max-bytes is reached or ctx is canceled, whichever comes first
+func stageTimeout(ctx context.Context, d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	go func() {
+		timeout := time.After(d)
+		var bytesCheck <-chan time.Time
+		if maxBytes > 0 {
+			ticker := time.NewTicker(bytesCheckPeriod)
+			defer ticker.Stop()
+			bytesCheck = ticker.C
+		}
+		for {
+			select {
+			case t := <-timeout:
+				ch <- t
+				return
+			case t := <-bytesCheck:
+				if isBytesCapReached() {
+					ch <- t
+					return
+				}
+			case <-ctx.Done():
+				ch <- time.Now()
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func printBytesCap() {
+	if maxBytes == 0 {
+		return
+	}
+
+	n := bytesTransferred()
+	fmt.Fprintf(out, "------ Bytes cap (%s) ------\n", formatBytes(maxBytes))
+	fmt.Fprintf(out, "Transferred: %s; %.2f %% of cap\n", formatBytes(n), float64(n)/float64(maxBytes)*100)
+	if !bytesCapReached {
+		fmt.Fprintln(out)
+		return
+	}
+	if loadElapsed == 0 {
+		fmt.Fprintln(out, "Test was stopped by cap before load phase")
+	} else if *totalRequests > 0 {
+		fmt.Fprintf(out, "Test was stopped by cap after %.1fs of load phase\n", loadElapsed.Seconds())
+	} else {
+		fmt.Fprintf(out, "Test was stopped by cap after %.1fs of %s load phase\n", loadElapsed.Seconds(), *d)
+	}
+	if *rampSteps > 0 {
+		fmt.Fprintf(out, "Ramp was stopped at step %d of %d\n", len(steps), *rampSteps)
+	}
+	fmt.Fprintln(out)
+}
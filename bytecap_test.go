@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseBytes(t *testing.T) {
+	f := func(s string, expected uint64) {
+		t.Helper()
+		n, err := parseBytes(s)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if n != expected {
+			t.Errorf("Unexpected result for %q. Got: %d; Expected: %d", s, n, expected)
+		}
+	}
+
+	f("1000", 1000)
+	f("10B", 10)
+	f("10GB", 10e9)
+	f("1.5 kb", 1500)
+	f("512MiB", 512<<20)
+}
+
+func TestParseBytesError(t *testing.T) {
+	for _, s := range []string{"", "GB", "-1GB", "0", "10XB"} {
+		if _, err := parseBytes(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	f := func(n uint64, expected string) {
+		t.Helper()
+		if s := formatBytes(n); s != expected {
+			t.Errorf("Unexpected result for %d. Got: %q; Expected: %q", n, s, expected)
+		}
+	}
+
+	f(999, "999 B")
+	f(1500, "1.50 KB")
+	f(10e9, "10.00 GB")
+}
+
+func TestStageTimeoutCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	timeout := stageTimeout(ctx, time.Hour)
+	cancel()
+	select {
+	case <-timeout:
+	case <-time.After(time.Second):
+		t.Fatalf("Stage wasn't finished after cancel")
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+	"time"
+)
+
+// savedCalibration is a result of burst and calibrate phases, which is reused
+// by following tests of the same target instead of calibrating again
+type savedCalibration struct {
+	// URL and Method identify target, which was calibrated
+	URL    string `json:"url"`
+	Method string `json:"method"`
+
+	QPS     float64 `json:"qps"`
+	Clients int     `json:"clients"`
+
+	// Time is a time of calibration
+	Time time.Time `json:"time"`
+}
+
+// loadedCalibration is a calibration read from -load-calibration. Is nil if -load-calibration isn't set
+var loadedCalibration *savedCalibration
+
+// readCalibration reads calibration from path and checks that it belongs to target
+func readCalibration(path, url, method string) (*savedCalibration, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read calibration file: %s", err)
+	}
+	sc := &savedCalibration{}
+	if err := json.Unmarshal(b, sc); err != nil {
+		return nil, fmt.Errorf("cannot parse calibration file %q: %s", path, err)
+	}
+	if sc.URL != url || sc.Method != method {
+		return nil, fmt.Errorf("calibration file %q belongs to %s %s instead of %s %s; remove -load-calibration to calibrate again",
+			path, sc.Method, sc.URL, method, url)
+	}
+	if !(sc.QPS > 0) || sc.Clients <= 0 {
+		return nil, fmt.Errorf("calibration file %q must contain positive qps and clients; got %v and %d", path, sc.QPS, sc.Clients)
+	}
+	return sc, nil
+}
+
+// writeCalibration writes calibration of target found by burst and calibrate phases to path
+func writeCalibration(path string, cfg *loadConfig) error {
+	sc := savedCalibration{
+		URL:     target,
+		Method:  strings.ToUpper(*method),
+		QPS:     cfg.qps,
+		Clients: cfg.c,
+		Time:    time.Now(),
+	}
+	b, err := json.MarshalIndent(sc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// applyCalibrationFiles checks -save-calibration and -load-calibration and reads the latter
+func applyCalibrationFiles() {
+	if *saveCalibration != "" && *loadCalibration != "" {
+		usageAndExit("-save-calibration and -load-calibration can't be used together")
+	}
+	if isFlagSet("q") || *profileFlag != "" || *mode != "" || *replayTiming == "original" {
+		usageAndExit("-save-calibration and -load-calibration can't be used with -q, -profile, -mode or -replay-timing original, since they skip calibration")
+	}
+	if *loadCalibration == "" {
+		return
+	}
+	sc, err := readCalibration(*loadCalibration, target, strings.ToUpper(*method))
+	if err != nil {
+		log.Fatalf("Error while reading calibration: %s", err)
+	}
+	loadedCalibration = sc
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteReadCalibration(t *testing.T) {
+	defer func(s string) { target = s }(target)
+	target = "http://example.com/items"
+	path := filepath.Join(t.TempDir(), "calibration.json")
+	if err := writeCalibration(path, &loadConfig{qps: 1234.5, c: 40}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	sc, err := readCalibration(path, "http://example.com/items", "GET")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if sc.QPS != 1234.5 || sc.Clients != 40 || sc.Time.IsZero() {
+		t.Errorf("Unexpected calibration: %+v", sc)
+	}
+
+	if _, err := readCalibration(path, "http://example.com/cart", "GET"); err == nil {
+		t.Errorf("Expected error for calibration of another url")
+	}
+	if _, err := readCalibration(path, "http://example.com/items", "POST"); err == nil {
+		t.Errorf("Expected error for calibration of another method")
+	}
+}
+
+func TestReadCalibrationError(t *testing.T) {
+	f := func(content string) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "calibration.json")
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("cannot write calibration file: %s", err)
+		}
+		if _, err := readCalibration(path, "http://example.com/", "GET"); err == nil {
+			t.Errorf("Expected error for %s", content)
+		}
+	}
+
+	f(``)
+	f(`[]`)
+	f(`{"url": "http://example.com/", "method": "GET", "qps": 100}`)
+	f(`{"url": "http://example.com/", "method": "GET", "qps": 0, "clients": 10}`)
+	f(`{"url": "http://example.com/", "method": "GET", "qps": "high", "clients": 10}`)
+	if _, err := readCalibration(filepath.Join(t.TempDir(), "missing.json"), "http://example.com/", "GET"); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+}
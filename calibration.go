@@ -0,0 +1,67 @@
+package main
+
+import (
+	"math"
+)
+
+// calibration is a state of adjustment of load during calibration stage.
+// Every -samplePeriod load grows by multiplier, unless errors grew since previous growth.
+// Then multiplier is divided by backoff, but not below floor, and growth pauses for penalty periods
+type calibration struct {
+	multiplier float64
+	backoff    float64
+	floor      float64
+	penalty    int
+
+	// await is a number of periods to skip before the next growth
+	await int
+}
+
+// calib is a calibration of current test
+var calib calibration
+
+// applyCalibration validates -calibStep, -calibBackoff, -calibFloor and -calibPenalty
+func applyCalibration() {
+	if *calibStep <= 0 {
+		usageAndExit("-calibStep must be positive")
+	}
+	if *calibBackoff <= 1 {
+		usageAndExit("-calibBackoff must be greater than 1")
+	}
+	if *calibFloor <= 0 || *calibFloor > *calibStep {
+		usageAndExit("-calibFloor must be positive and can't exceed -calibStep")
+	}
+	if *calibPenalty < 0 {
+		usageAndExit("-calibPenalty can't be negative")
+	}
+	calib = calibration{
+		multiplier: *calibStep,
+		backoff:    *calibBackoff,
+		floor:      *calibFloor,
+		penalty:    *calibPenalty,
+	}
+}
+
+// throttle backs off once target throttled requests. Multiplier is divided by backoff like after errors,
+// and growth pauses for penalty periods, but not less than given periods, like Retry-After of target
+func (c *calibration) throttle(periods int) {
+	c.multiplier = math.Max(c.multiplier/c.backoff, c.floor)
+	c.await = max(c.penalty, periods)
+}
+
+// next returns part, by which load must grow in current period. Zero means load isn't changed.
+// flawed reports whether errors grew since it was called last time, and is called only if growth isn't paused
+func (c *calibration) next(flawed func() bool) float64 {
+	if c.await > 0 {
+		c.await--
+		return 0
+	}
+	if flawed() {
+		c.multiplier = math.Max(c.multiplier/c.backoff, c.floor)
+		c.await += c.penalty
+		return 0
+	}
+	// growth takes effect with delay, so the next period is skipped
+	c.await++
+	return c.multiplier
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+)
+
+// calibrationPeriods returns number of periods, during which calibration with step
+// grows qps from 100 to capacity of target, which responds with errors above it
+func calibrationPeriods(step float64) int {
+	c := calibration{multiplier: step, backoff: 1.2, floor: 0.0001, penalty: 3}
+	qps, capacity := 100.0, 10000.0
+	for i := 1; i < 10000; i++ {
+		qps *= 1 + c.next(func() bool { return qps > capacity })
+		if qps >= capacity {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestCalibrationStep(t *testing.T) {
+	slow, fast := calibrationPeriods(0.1), calibrationPeriods(0.5)
+	if slow < 0 || fast < 0 {
+		t.Fatalf("Calibration didn't reach capacity. Periods with step 0.1: %d; with step 0.5: %d", slow, fast)
+	}
+	if fast >= slow {
+		t.Fatalf("Bigger step must converge faster. Periods with step 0.1: %d; with step 0.5: %d", slow, fast)
+	}
+}
+
+func TestCalibrationBackoff(t *testing.T) {
+	c := calibration{multiplier: 0.1, backoff: 2, floor: 0.02, penalty: 3}
+	flawed := func() bool { return true }
+	f := func(expectedMultiplier float64, expectedAwait int) {
+		t.Helper()
+		if k := c.next(flawed); k != 0 {
+			t.Fatalf("Load grew by %f despite of errors", k)
+		}
+		if c.multiplier != expectedMultiplier || c.await != expectedAwait {
+			t.Fatalf("Unexpected state. Got: multiplier %f, await %d; Expected: multiplier %f, await %d",
+				c.multiplier, c.await, expectedMultiplier, expectedAwait)
+		}
+	}
+
+	f(0.05, 3)
+	// growth is paused, so errors aren't checked
+	f(0.05, 2)
+	f(0.05, 1)
+	f(0.05, 0)
+	f(0.025, 3)
+	c.await = 0
+	// multiplier doesn't drop below floor
+	f(0.02, 3)
+
+	c.await = 0
+	if k := c.next(func() bool { return false }); k != 0.02 || c.await != 1 {
+		t.Fatalf("Unexpected growth without errors. Got: %f, await %d; Expected: 0.02, await 1", k, c.await)
+	}
+}
+
+func TestCalibrationThrottle(t *testing.T) {
+	c := calibration{multiplier: 0.1, backoff: 2, floor: 0.02, penalty: 3}
+	f := func(periods int, expectedMultiplier float64, expectedAwait int) {
+		t.Helper()
+		c.throttle(periods)
+		if c.multiplier != expectedMultiplier || c.await != expectedAwait {
+			t.Fatalf("Unexpected state. Got: multiplier %f, await %d; Expected: multiplier %f, await %d",
+				c.multiplier, c.await, expectedMultiplier, expectedAwait)
+		}
+	}
+
+	f(0, 0.05, 3)
+	// growth pauses until Retry-After of target
+	f(10, 0.025, 10)
+	f(1, 0.02, 3)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// capturedErrors are failed requests captured by clients of finished stages
+var capturedErrors []fastclient.Exchange
+
+// collectCaptured adds failed requests captured by client of finished stage
+func collectCaptured() {
+	capturedErrors = append(capturedErrors, client.Captured()...)
+}
+
+// failedRequests returns captured failed requests for report
+func failedRequests() []report.FailedRequest {
+	result := make([]report.FailedRequest, len(capturedErrors))
+	for i, e := range capturedErrors {
+		result[i] = report.FailedRequest{
+			Time:     e.Time.Sub(testStart).Seconds(),
+			Failure:  e.Failure,
+			Request:  e.Request,
+			Response: e.Response,
+		}
+	}
+	return result
+}
+
+// encodeCapturedErrors writes exchanges to w as text separated by blank lines
+func encodeCapturedErrors(w io.Writer, exchanges []fastclient.Exchange) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range exchanges {
+		fmt.Fprintf(bw, "=== %s: %s\n", e.Time.Format(time.RFC3339Nano), e.Failure)
+		fmt.Fprintf(bw, "--- request\n%s\n", e.Request)
+		if e.Response != "" {
+			fmt.Fprintf(bw, "--- response\n%s\n", e.Response)
+		} else {
+			fmt.Fprintln(bw, "--- response wasn't received")
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// writeCapturedErrors writes captured failed requests and their responses to file
+func writeCapturedErrors(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = encodeCapturedErrors(f, capturedErrors); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printCapturedErrors reports captured failed requests and writes them to -capture-errors-file
+func printCapturedErrors() {
+	if len(capturedErrors) == 0 {
+		return
+	}
+	if *captureErrorsFile == "" {
+		fmt.Fprintf(out, "Captured %d failed requests; they are shown in report\n", len(capturedErrors))
+		return
+	}
+	if err := writeCapturedErrors(*captureErrorsFile); err != nil {
+		slog.Error("Error while writing captured failed requests", "error", err)
+		return
+	}
+	fmt.Fprintf(out, "Captured %d failed requests; they are shown in report and written to %s\n", len(capturedErrors), *captureErrorsFile)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+func TestEncodeCapturedErrors(t *testing.T) {
+	start := time.Date(2026, 10, 14, 7, 0, 0, 0, time.UTC)
+	exchanges := []fastclient.Exchange{
+		{
+			Time:     start,
+			Failure:  "status code 503",
+			Request:  "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n",
+			Response: "HTTP/1.1 503 Service Unavailable\r\nContent-Length: 4\r\n\r\nbusy",
+		},
+		{
+			Time:    start.Add(1500 * time.Millisecond),
+			Failure: "timeout",
+			Request: "POST /a HTTP/1.1\r\nHost: example.com\r\n\r\nbody",
+		},
+	}
+	var buf bytes.Buffer
+	if err := encodeCapturedErrors(&buf, exchanges); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := "=== 2026-10-14T07:00:00Z: status code 503\n" +
+		"--- request\nGET / HTTP/1.1\r\nHost: example.com\r\n\r\n\n" +
+		"--- response\nHTTP/1.1 503 Service Unavailable\r\nContent-Length: 4\r\n\r\nbusy\n\n" +
+		"=== 2026-10-14T07:00:01.5Z: timeout\n" +
+		"--- request\nPOST /a HTTP/1.1\r\nHost: example.com\r\n\r\nbody\n" +
+		"--- response wasn't received\n\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Unexpected output.\nGot:\n%q\nExpected:\n%q", got, expected)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+const day = 24 * time.Hour
+
+// parseExpiryWindow parses window like "7d" or "36h"
+func parseExpiryWindow(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		var n float64
+		if n, err = strconv.ParseFloat(days, 64); err == nil {
+			d = time.Duration(n * float64(day))
+		}
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("certificate expiry window %q must be positive number of days like \"7d\" or duration like \"36h\"", s)
+	}
+	return d, nil
+}
+
+// checkCertExpiry prints expiry time of certificate of target and exits
+// if certificate expires within window
+func checkCertExpiry(window time.Duration) {
+	expiry, err := fastclient.CertExpiry(req, tlsConfig, *dialTimeout)
+	if err != nil {
+		log.Fatalf("Can't check certificate expiry of target: %s", err)
+	}
+	left := time.Until(expiry)
+	fmt.Fprintf(out, "Certificate expires at %s (%.1f days remaining)\n", expiry.UTC().Format(time.RFC3339), left.Hours()/24)
+	if left < window {
+		log.Fatalf("Certificate of target expires within -fail-if-cert-expires-within %s", *certExpiryWindow)
+	}
+}
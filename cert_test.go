@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseExpiryWindow(t *testing.T) {
+	f := func(s string, expected time.Duration) {
+		t.Helper()
+		d, err := parseExpiryWindow(s)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if d != expected {
+			t.Errorf("Unexpected result for %q. Got: %s; Expected: %s", s, d, expected)
+		}
+	}
+
+	f("7d", 7*day)
+	f("1.5d", 36*time.Hour)
+	f("36h", 36*time.Hour)
+}
+
+func TestParseExpiryWindowError(t *testing.T) {
+	for _, s := range []string{"", "d", "7", "-7d", "0d", "week"} {
+		if _, err := parseExpiryWindow(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
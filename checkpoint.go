@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// checkpoint contains state of load phase, which is enough
+// to resume test after crash or restart
+type checkpoint struct {
+	// Title is a host of target. Is used to avoid resuming of another test
+	Title string
+
+	QPS     float64
+	Clients int
+
+	// Elapsed is a duration of load phase made before checkpoint
+	Elapsed time.Duration
+
+	// Requests is a total number of requests made before checkpoint
+	Requests uint64
+
+	// BytesTransferred is a total number of bytes read and written before checkpoint, including all stages
+	BytesTransferred uint64
+
+	// Timestamps contains time of every sample in seconds since test start
+	Timestamps []float64
+	// SamplePhases contains phase of every sample
+	SamplePhases []string
+
+	Connections     []uint64
+	RequestSum      []uint64
+	RequestSuccess  []uint64
+	Errors          []uint64
+	Timeouts        []uint64
+	Qps             []uint64
+	BytesWritten    []uint64
+	BytesRead       []uint64
+	RequestDuration map[float64][]float64
+
+	// StatusCodes contains number of requests per status code
+	StatusCodes   map[string]float64
+	ErrorMessages map[string]int
+}
+
+var (
+	// resumed is a checkpoint which test was resumed from. Is nil if test wasn't resumed
+	resumed *checkpoint
+
+	// checkpointing is 1 while checkpoint is being written
+	checkpointing int32
+)
+
+// readCheckpoint reads checkpoint from path. Returns nil if file doesn't exist
+func readCheckpoint(path string) (*checkpoint, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot open checkpoint file: %s", err)
+	}
+	defer f.Close()
+
+	cp := &checkpoint{}
+	if err := gob.NewDecoder(f).Decode(cp); err != nil {
+		return nil, fmt.Errorf("cannot decode checkpoint file %q: %s", path, err)
+	}
+	return cp, nil
+}
+
+// restore fills page with series from checkpoint
+func (cp *checkpoint) restore(p *report.Page) {
+	p.Timestamps = cp.Timestamps
+	p.SamplePhases = cp.SamplePhases
+	p.Connections = cp.Connections
+	p.RequestSum = cp.RequestSum
+	p.RequestSuccess = cp.RequestSuccess
+	p.Errors = cp.Errors
+	p.Timeouts = cp.Timeouts
+	p.Qps = cp.Qps
+	p.BytesWritten = cp.BytesWritten
+	p.BytesRead = cp.BytesRead
+	if cp.RequestDuration != nil {
+		p.RequestDuration = cp.RequestDuration
+	}
+}
+
+// counters are cumulative counters of sample
+type counters struct {
+	requests     uint64
+	success      uint64
+	errors       uint64
+	timeouts     uint64
+	bytesWritten uint64
+	bytesRead    uint64
+}
+
+// lastCounters returns counters of the last sample of checkpoint.
+// Returns zero counters if cp is nil or has no samples
+func (cp *checkpoint) lastCounters() counters {
+	if cp == nil {
+		return counters{}
+	}
+	last := func(series []uint64) uint64 {
+		if len(series) == 0 {
+			return 0
+		}
+		return series[len(series)-1]
+	}
+	return counters{
+		requests:     last(cp.RequestSum),
+		success:      last(cp.RequestSuccess),
+		errors:       last(cp.Errors),
+		timeouts:     last(cp.Timeouts),
+		bytesWritten: last(cp.BytesWritten),
+		bytesRead:    last(cp.BytesRead),
+	}
+}
+
+// sampleCounters returns counters of client for sample. Counters of resumed test are offset by the last sample
+// of checkpoint, since counters of client start from zero, so series keep growing instead of dropping
+func sampleCounters() counters {
+	c := resumed.lastCounters()
+	c.requests += client.RequestSum()
+	c.success += client.RequestSuccess()
+	c.errors += client.Errors()
+	c.timeouts += client.Timeouts()
+	c.bytesWritten += client.BytesWritten()
+	c.bytesRead += client.BytesRead()
+	return c
+}
+
+// merge adds status codes and error messages from checkpoint
+// to ones of current session. requests is a number of requests of current session
+func (cp *checkpoint) merge(p *report.Page, requests uint64) {
+	total := float64(cp.Requests + requests)
+	if total == 0 {
+		return
+	}
+	codes := make(map[string]float64)
+	for k, v := range cp.StatusCodes {
+		codes[k] = v
+	}
+	for k, v := range p.StatusCodes {
+		codes[k] += v * float64(requests) / 100
+	}
+	for k, v := range codes {
+		codes[k] = v / total * 100
+	}
+	p.StatusCodes = codes
+
+	counts := make(map[int]uint64, len(p.StatusCounts))
+	for k, v := range p.StatusCounts {
+		counts[k] = v
+	}
+	for k, v := range cp.StatusCodes {
+		if code, err := strconv.Atoi(k); err == nil {
+			counts[code] += uint64(math.Round(v))
+		}
+	}
+	p.StatusCounts = counts
+
+	messages := make(map[string]int)
+	for k, v := range cp.ErrorMessages {
+		messages[k] = v
+	}
+	for k, v := range p.ErrorMessages {
+		messages[k] += v
+	}
+	p.ErrorMessages = messages
+}
+
+// saveCheckpoint snapshots state of load phase and writes it in background.
+// Snapshot is skipped if previous one is still being written
+func saveCheckpoint(cfg *loadConfig, elapsed time.Duration) {
+	if !atomic.CompareAndSwapInt32(&checkpointing, 0, 1) {
+		return
+	}
+
+	requests := client.RequestSum()
+	cp := &checkpoint{
+		Title:            r.Title,
+		QPS:              cfg.qps,
+		Clients:          cfg.c,
+		Elapsed:          elapsed,
+		Requests:         requests,
+		BytesTransferred: bytesTransferred(),
+		RequestDuration:  make(map[float64][]float64),
+		StatusCodes:      make(map[string]float64),
+	}
+	if resumed != nil {
+		cp.Elapsed += resumed.Elapsed
+		cp.Requests += resumed.Requests
+	}
+	r.Lock()
+	cp.Timestamps = r.Timestamps
+	cp.SamplePhases = r.SamplePhases
+	cp.Connections = r.Connections
+	cp.RequestSum = r.RequestSum
+	cp.RequestSuccess = r.RequestSuccess
+	cp.Errors = r.Errors
+	cp.Timeouts = r.Timeouts
+	cp.Qps = r.Qps
+	cp.BytesWritten = r.BytesWritten
+	cp.BytesRead = r.BytesRead
+	for k, v := range r.RequestDuration {
+		cp.RequestDuration[k] = v
+	}
+	for k, v := range r.StatusCodes {
+		cp.StatusCodes[k] = v * float64(cp.Requests) / 100
+	}
+	cp.ErrorMessages = r.ErrorMessages
+	r.Unlock()
+
+	go func() {
+		if err := writeCheckpoint(*checkpointFile, cp); err != nil {
+			slog.Error("Error while writing checkpoint", "error", err)
+		}
+		atomic.StoreInt32(&checkpointing, 0)
+	}()
+}
+
+// writeCheckpoint writes checkpoint to temporary file and renames it to path,
+// so path always contains complete checkpoint
+func writeCheckpoint(path string, cp *checkpoint) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err = gob.NewEncoder(f).Encode(cp); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// removeCheckpoint removes checkpoint of finished test,
+// so next test with the same file would start from scratch
+func removeCheckpoint() {
+	for atomic.LoadInt32(&checkpointing) != 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := os.Remove(*checkpointFile); err != nil && !os.IsNotExist(err) {
+		slog.Error("Error while removing checkpoint", "error", err)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/report"
+	"github.com/valyala/fasthttp"
+)
+
+func TestCheckpointReadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "soak.gob")
+	if cp, err := readCheckpoint(path); cp != nil || err != nil {
+		t.Fatalf("Unexpected result of reading missing checkpoint. Got: %v, %v; Expected: nil, nil", cp, err)
+	}
+
+	cp := &checkpoint{
+		Title:           "localhost",
+		QPS:             1500,
+		Clients:         40,
+		Elapsed:         time.Hour,
+		Requests:        42,
+		Timestamps:      []float64{0.5, 1},
+		SamplePhases:    []string{"steady", "steady"},
+		RequestSum:      []uint64{20, 42},
+		Errors:          []uint64{1, 2},
+		RequestDuration: map[float64][]float64{0.99: {0.1, 0.2}},
+		StatusCodes:     map[string]float64{"200": 40, "500": 2},
+		ErrorMessages:   map[string]int{"status code 500": 2},
+	}
+	if err := writeCheckpoint(path, cp); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("Temporary file must be renamed to checkpoint")
+	}
+	got, err := readCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(got, cp) {
+		t.Errorf("Unexpected checkpoint. Got: %+v; Expected: %+v", got, cp)
+	}
+
+	if err := os.WriteFile(path, []byte("garbage"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, err := readCheckpoint(path); err == nil {
+		t.Errorf("Corrupted checkpoint must be rejected")
+	}
+}
+
+func TestCheckpointRestore(t *testing.T) {
+	defer func(c *fastclient.Client, cp *checkpoint) { client, resumed = c, cp }(client, resumed)
+
+	resumed = &checkpoint{
+		Timestamps:     []float64{0.5, 1},
+		SamplePhases:   []string{"steady", "steady"},
+		Connections:    []uint64{10, 10},
+		RequestSum:     []uint64{500, 1000},
+		RequestSuccess: []uint64{490, 980},
+		Errors:         []uint64{10, 20},
+		Timeouts:       []uint64{1, 2},
+		Qps:            []uint64{1000, 1000},
+		BytesWritten:   []uint64{5000, 10000},
+		BytesRead:      []uint64{7000, 14000},
+	}
+	p := &report.Page{RequestDuration: make(map[float64][]float64)}
+	resumed.restore(p)
+	if !reflect.DeepEqual(p.RequestSum, resumed.RequestSum) || !reflect.DeepEqual(p.Timestamps, resumed.Timestamps) {
+		t.Fatalf("Unexpected restored series: %v at %v", p.RequestSum, p.Timestamps)
+	}
+
+	// counters of session after resume start from zero
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://127.0.0.1/")
+	client = fastclient.New(req, time.Second, fasthttp.StatusOK)
+	expected := counters{requests: 1000, success: 980, errors: 20, timeouts: 2, bytesWritten: 10000, bytesRead: 14000}
+	if got := sampleCounters(); got != expected {
+		t.Fatalf("Unexpected counters of resumed session. Got: %+v; Expected: %+v", got, expected)
+	}
+	// series continue from the last sample of checkpoint instead of dropping to zero
+	counts := sampleCounters()
+	p.RequestSum = append(p.RequestSum, counts.requests)
+	p.Errors = append(p.Errors, counts.errors)
+	for i := 1; i < len(p.RequestSum); i++ {
+		if p.RequestSum[i] < p.RequestSum[i-1] || p.Errors[i] < p.Errors[i-1] {
+			t.Errorf("Cumulative series decrease at sample %d: %v, %v", i, p.RequestSum, p.Errors)
+		}
+	}
+
+	resumed = nil
+	if got := sampleCounters(); got != (counters{}) {
+		t.Errorf("Unexpected counters without checkpoint. Got: %+v; Expected: zero", got)
+	}
+}
+
+func TestCheckpointMerge(t *testing.T) {
+	cp := &checkpoint{
+		Requests:      100,
+		StatusCodes:   map[string]float64{"200": 90, "500": 10},
+		ErrorMessages: map[string]int{"status code 500": 10},
+	}
+	// 100 requests of current session: 50% of 200 and 50% of 503
+	p := &report.Page{
+		StatusCodes:   map[string]float64{"200": 50, "503": 50},
+		StatusCounts:  map[int]uint64{200: 50, 503: 50},
+		ErrorMessages: map[string]int{"status code 503": 50},
+	}
+	cp.merge(p, 100)
+
+	if expected := map[string]float64{"200": 70, "500": 5, "503": 25}; !reflect.DeepEqual(p.StatusCodes, expected) {
+		t.Errorf("Unexpected status codes. Got: %v; Expected: %v", p.StatusCodes, expected)
+	}
+	if expected := map[int]uint64{200: 140, 500: 10, 503: 50}; !reflect.DeepEqual(p.StatusCounts, expected) {
+		t.Errorf("Unexpected status counts. Got: %v; Expected: %v", p.StatusCounts, expected)
+	}
+	if expected := map[string]int{"status code 500": 10, "status code 503": 50}; !reflect.DeepEqual(p.ErrorMessages, expected) {
+		t.Errorf("Unexpected error messages. Got: %v; Expected: %v", p.ErrorMessages, expected)
+	}
+	// checkpoint isn't changed by merge, so it could be merged with every sample
+	if cp.StatusCodes["200"] != 90 {
+		t.Errorf("Checkpoint must not be changed by merge")
+	}
+}
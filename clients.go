@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+const (
+	// clientsLatency is a median latency of target, which -workers-per-cpu is sized for.
+	// Slower targets need proportionally more clients to reach the same rate
+	clientsLatency = 20 * time.Millisecond
+
+	// maxClientsScale caps growth of default number of clients by latency of target
+	maxClientsScale = 4
+
+	// clientsProbe is a duration since start of burst phase,
+	// after which default number of clients is adjusted by median latency
+	clientsProbe = time.Second
+)
+
+// autoClients is true if -c isn't set, so number of clients is calculated by defaultClients
+var autoClients bool
+
+// defaultClients returns number of clients used if -c isn't set: perCPU clients per every of procs CPUs
+// scaled by median latency of target relative to clientsLatency up to maxClientsScale times.
+// Zero latency means that it isn't measured yet
+func defaultClients(perCPU, procs int, latency time.Duration) int {
+	n := perCPU * procs
+	if latency <= clientsLatency {
+		return n
+	}
+	scale := float64(latency) / float64(clientsLatency)
+	if scale > maxClientsScale {
+		scale = maxClientsScale
+	}
+	return int(float64(n) * scale)
+}
+
+// adjustAutoClients adds clients to burst phase, if default number of clients
+// is too small for median latency measured since its start
+func adjustAutoClients() {
+	latency := time.Duration(client.Latency().P50 * float64(time.Second))
+	n := defaultClients(*workersPerCPU, runtime.GOMAXPROCS(0), latency)
+	if n <= *c {
+		return
+	}
+	slog.Info("Clients are added by latency of target", "latency", latency, "clients", n)
+	fmt.Fprintf(out, "Median latency of target is %s, so number of clients is raised from %d to %d\n", formatLatency(latency.Seconds()), *c, n)
+	client.RunWorkers(n - *c)
+	*c = n
+}
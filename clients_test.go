@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultClients(t *testing.T) {
+	f := func(perCPU, procs int, latency time.Duration, expected int) {
+		t.Helper()
+		if got := defaultClients(perCPU, procs, latency); got != expected {
+			t.Errorf("Unexpected clients of %d per CPU on %d CPUs with latency %s. Got: %d; Expected: %d", perCPU, procs, latency, got, expected)
+		}
+	}
+
+	// latency isn't measured yet
+	f(250, 4, 0, 1000)
+	f(250, 1, 0, 250)
+	f(10, 8, 0, 80)
+	// fast target doesn't need more clients
+	f(250, 4, 5*time.Millisecond, 1000)
+	f(250, 4, clientsLatency, 1000)
+	f(250, 4, 30*time.Millisecond, 1500)
+	f(250, 4, 60*time.Millisecond, 3000)
+	// growth is capped
+	f(250, 4, time.Second, 4000)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+const compareUsage = `Usage: fasthttploader compare [options...] <baseline.json> <current.json>
+Compare load phase of two JSON reports made by -format json and exit with status 1 if current one regressed beyond tolerances.
+Options:
+`
+
+// defaultTolerances are default tolerances of compare command, which are used by -compare-protocols as well
+var defaultTolerances = report.Tolerances{RpsDrop: 5, ErrorRateRise: 0.5, LatencyRise: 10}
+
+// runCompareCommand runs `fasthttploader compare baseline.json current.json`, which prints deltas
+// of rps, error rate and latency percentiles of two JSON reports and writes them as HTML comparison
+func runCompareCommand(args []string) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, compareUsage)
+		fs.PrintDefaults()
+	}
+	var t report.Tolerances
+	fs.Float64Var(&t.RpsDrop, "max-rps-drop", defaultTolerances.RpsDrop, "Max decrease of rps in percent, which isn't a regression")
+	fs.Float64Var(&t.ErrorRateRise, "max-error-rate-rise", defaultTolerances.ErrorRateRise, "Max increase of error rate in percentage points, which isn't a regression")
+	fs.Float64Var(&t.LatencyRise, "max-latency-rise", defaultTolerances.LatencyRise, "Max increase of p50, p90, p95 and p99 latency in percent, which isn't a regression")
+	out := fs.String("o", "comparison.html", "Set file to write side-by-side HTML comparison to. Comparison isn't written if empty")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	baseline, current := readResult(fs.Arg(0)), readResult(fs.Arg(1))
+	c := report.Compare(baseline, current, t)
+	c.Baseline, c.Current = filepath.Base(fs.Arg(0)), filepath.Base(fs.Arg(1))
+	printDeltas(os.Stdout, c)
+	if *out != "" {
+		if err := ioutil.WriteFile(*out, []byte(report.PrintComparison(c)), 0644); err != nil {
+			log.Fatalf("Cannot write comparison: %s", err)
+		}
+		fmt.Printf("Comparison is written to %s\n", *out)
+	}
+	if n := len(c.Regressions()); n > 0 {
+		fmt.Fprintf(os.Stderr, "%d of metrics regressed beyond tolerances\n", n)
+		os.Exit(1)
+	}
+}
+
+// printDeltas prints baseline and current value, change and status of every delta of c
+func printDeltas(w io.Writer, c *report.Comparison) {
+	for _, d := range c.Deltas {
+		status := "ok"
+		if d.Regression {
+			status = "REGRESSION"
+		}
+		fmt.Fprintf(w, "%-11s  %12s  %12s  %10s  %s\n", d.Name, d.FormatValue(d.Baseline), d.FormatValue(d.Current), d.FormatChange(), status)
+	}
+}
+
+// readResult reads summary of load phase of JSON report at path
+func readResult(path string) *report.Result {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Cannot open report: %s", err)
+	}
+	defer f.Close()
+	res, err := report.ReadResult(f)
+	if err != nil {
+		log.Fatalf("Cannot read report %q: %s", path, err)
+	}
+	return res
+}
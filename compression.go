@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+)
+
+// maxDecodedSize is a parsed -max-decoded-size
+var maxDecodedSize uint64
+
+// applyCompression validates -acceptEncoding, -compress, -compress-body and -max-decoded-size.
+// -disable-compression is the same as empty -acceptEncoding
+func applyCompression() {
+	if *disableCompression {
+		if isFlagSet("acceptEncoding") || *compressFlag {
+			usageAndExit("-disable-compression can't be used with -acceptEncoding or -compress")
+		}
+		*acceptEncoding = ""
+	}
+	if *compressFlag {
+		if isFlagSet("acceptEncoding") {
+			usageAndExit("-compress can't be used with -acceptEncoding")
+		}
+		*acceptEncoding = "gzip, deflate, br"
+	}
+	switch *compressBody {
+	case "", "gzip", "deflate", "br":
+	default:
+		usageAndExit(fmt.Sprintf("unsupported -compress-body %q; supported encodings are gzip, deflate and br", *compressBody))
+	}
+	if *compressBody != "" && (*genBodySizeFlag != "" || *grpcMethod != "") {
+		usageAndExit("-compress-body can't be used with -gen-body-size or -grpc-method, since their bodies are streamed or framed")
+	}
+	var err error
+	if maxDecodedSize, err = parseBytes(*maxDecodedSizeFlag); err != nil {
+		usageAndExit(fmt.Sprintf("cannot parse -max-decoded-size: %s", err))
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// secretFlag matches names of flags which values must not be printed
+var secretFlag = regexp.MustCompile(`(?i)password|passwd|token|secret`)
+
+// secretHeader matches names of headers which values must not be printed
+var secretHeader = regexp.MustCompile(`(?i)^(authorization|proxy-authorization|cookie|x-api-key|x-auth-token)$`)
+
+// effectiveConfig returns values of all flags, including defaults,
+// and target url with secrets being redacted
+func effectiveConfig() map[string]interface{} {
+	cfg := make(map[string]interface{})
+	flag.VisitAll(func(f *flag.Flag) {
+		var v interface{} = f.Value.String()
+		if g, ok := f.Value.(flag.Getter); ok {
+			v = g.Get()
+		}
+		if d, ok := v.(time.Duration); ok {
+			v = d.String()
+		}
+
+		switch {
+		case f.Name == "h":
+			v = redactHeaders(f.Value.String())
+		case f.Name == "header":
+			hs := make([]string, len(headerFlags))
+			for i, h := range headerFlags {
+				hs[i] = redactHeaders(h)
+			}
+			v = hs
+		case f.Name == "curl":
+			v = redactCurl(f.Value.String())
+		case f.Name == "proxy":
+			v = redactURL(f.Value.String())
+		case f.Name == "proxy-auth" && f.Value.String() != "":
+			v = redacted
+		case secretFlag.MatchString(f.Name) && f.Value.String() != "":
+			v = redacted
+		}
+		cfg[f.Name] = v
+	})
+	if target != "" {
+		cfg["url"] = redactURL(target)
+	}
+
+	return cfg
+}
+
+func redactHeaders(headers string) string {
+	if headers == "" {
+		return headers
+	}
+	hs := strings.Split(headers, ";")
+	for i, h := range hs {
+		matches := re.FindStringSubmatch(h)
+		if len(matches) > 0 && secretHeader.MatchString(matches[1]) {
+			hs[i] = matches[1] + ": " + redacted
+		}
+	}
+	return strings.Join(hs, ";")
+}
+
+// redactCurl redacts values of secret headers and -u option of curl command
+func redactCurl(cmd string) string {
+	args, err := splitShellWords(cmd)
+	if err != nil {
+		return redacted
+	}
+	for i, a := range args {
+		if i > 0 {
+			switch args[i-1] {
+			case "-H", "--header":
+				a = redactHeaders(a)
+			case "-u", "--user":
+				a = strings.SplitN(a, ":", 2)[0] + ":" + redacted
+			}
+		}
+		args[i] = shellQuote(a)
+	}
+	return strings.Join(args, " ")
+}
+
+var shellSafe = regexp.MustCompile(`^[\w@%+=:,./-]+$`)
+
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	return u.String()
+}
+
+func dumpConfig() {
+	b, err := json.MarshalIndent(effectiveConfig(), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot marshal config: %s\n", err)
+		return
+	}
+	fmt.Println(string(b))
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+var configFile = flag.String("config", "", "Set YAML file with test definition like \"q: 100\" and \"d: 1m\", where keys are names of flags. "+
+	"Flags set in command line override values of file. See \"fasthttploader config init\" for template")
+
+// configSetting is a value of flag set by -config file.
+// Flags of flag.Var are lists, so they may have several values
+type configSetting struct {
+	name   string
+	values []string
+}
+
+// testConfig is a test definition of -config file
+type testConfig struct {
+	// target is an url argument
+	target   string
+	settings []configSetting
+	// scenario is an inline -scenario as YAML list of requests
+	scenario []byte
+}
+
+// parseConfig parses YAML mapping of flag names to values like
+//
+//	target: http://localhost:8080
+//	q: 100
+//	header: ["X-Foo: bar", "X-Bar: baz"]
+//	thresholds:
+//	  maxP99: 50ms
+//
+// Mappings with names, which aren't flags, are sections grouping flags, so their names don't matter.
+// Key scenario may contain list of requests of -scenario instead of path to file
+func parseConfig(b []byte) (*testConfig, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	cfg := &testConfig{}
+	if len(doc.Content) == 0 {
+		return cfg, nil
+	}
+	seen := make(map[string]bool)
+	if err := cfg.parseSection(doc.Content[0], seen); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (cfg *testConfig) parseSection(n *yaml.Node, seen map[string]bool) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: must be a mapping of flag names to values", n.Line)
+	}
+	for i := 0; i < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		name := k.Value
+		f := flag.Lookup(name)
+		if f == nil && name != "target" {
+			if v.Tag == "!!null" {
+				// section with all of its flags commented out
+				continue
+			}
+			if v.Kind != yaml.MappingNode {
+				return fmt.Errorf("line %d: unknown flag %q", k.Line, name)
+			}
+			if err := cfg.parseSection(v, seen); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "config" {
+			return fmt.Errorf("line %d: -config can't be set in config file", k.Line)
+		}
+		if seen[name] {
+			return fmt.Errorf("line %d: %q is set twice", k.Line, name)
+		}
+		seen[name] = true
+		if name == "scenario" && v.Kind == yaml.SequenceNode {
+			b, err := yaml.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("line %d: cannot marshal scenario: %s", k.Line, err)
+			}
+			cfg.scenario = b
+			continue
+		}
+		values, err := configValues(v)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid value of %q: %s", k.Line, name, err)
+		}
+		if name == "target" {
+			if len(values) != 1 {
+				return fmt.Errorf("line %d: target must be a single url", k.Line)
+			}
+			cfg.target = values[0]
+			continue
+		}
+		if _, ok := f.Value.(flag.Getter); ok && len(values) != 1 {
+			return fmt.Errorf("line %d: -%s can't have several values", k.Line, name)
+		}
+		cfg.settings = append(cfg.settings, configSetting{name: name, values: values})
+	}
+	return nil
+}
+
+// configValues returns values of scalar or list of scalars.
+// Values are taken as written, so numbers like 1e3 are passed to flags unchanged
+func configValues(n *yaml.Node) ([]string, error) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return []string{n.Value}, nil
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(n.Content))
+		for _, item := range n.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("list must contain only scalars")
+			}
+			values = append(values, item.Value)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("must be a scalar or list of scalars")
+}
+
+// configScenario is an inline scenario of -config file
+var configScenario []byte
+
+// applyConfigFile sets flags by -config file, except flags set in command line.
+// Url argument is taken from target of file if it isn't set
+func applyConfigFile() (target string) {
+	b, err := ioutil.ReadFile(*configFile)
+	if err != nil {
+		usageAndExit(fmt.Sprintf("cannot read -config file: %s", err))
+	}
+	cfg, err := parseConfig(b)
+	if err != nil {
+		usageAndExit(fmt.Sprintf("cannot parse -config file %q: %s", *configFile, err))
+	}
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	for _, s := range cfg.settings {
+		if set[s.name] {
+			continue
+		}
+		for _, v := range s.values {
+			if err := flag.Set(s.name, v); err != nil {
+				usageAndExit(fmt.Sprintf("invalid value %q of %q in -config file %q: %s", v, s.name, *configFile, err))
+			}
+		}
+	}
+	if cfg.scenario != nil && !set["scenario"] {
+		configScenario = cfg.scenario
+		// scenario is reported as read from config file
+		flag.Set("scenario", *configFile)
+	}
+	return cfg.target
+}
+
+// runConfigCommand runs `fasthttploader config init [file]`, which writes commented template
+// of -config file to file or to stdout. Existing file isn't overwritten
+func runConfigCommand(args []string) {
+	if len(args) == 0 || args[0] != "init" || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, "Usage: fasthttploader config init [file]")
+		os.Exit(1)
+	}
+	if len(args) == 1 {
+		fmt.Print(configTemplate)
+		return
+	}
+	f, err := os.OpenFile(args[1], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		log.Fatalf("Cannot create config file: %s", err)
+	}
+	if _, err := f.WriteString(configTemplate); err != nil {
+		log.Fatalf("Cannot write config file: %s", err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalf("Cannot write config file: %s", err)
+	}
+	fmt.Printf("Config template is written to %s; run test by `fasthttploader -config %s`\n", args[1], args[1])
+}
+
+// configTemplate is a commented template of -config file written by `fasthttploader config init`
+const configTemplate = `# Test definition of fasthttploader. Run it by "fasthttploader -config <file>".
+# Keys are names of flags without dash, see "fasthttploader -h" for all of them.
+# Flags set in command line override values of this file.
+# Mappings with names, which aren't flags, like "load" below, only group flags.
+
+# Url to test, unless -url, -urls or -curl is set
+target: http://localhost:8080/
+
+load:
+  # Rate limit and number of connections. Without them max qps and number
+  # of connections are calibrated by burst stages before test
+  # q: 100
+  # c: 10
+  # Duration of load phase
+  d: 1m
+  # Timeouts of dialing and of requests
+  # dialTimeout: 5s
+  # requestTimeout: 5s
+
+request:
+  # m: POST
+  # body: '{"id": 1}'
+  # T: application/json
+  # Lists set repeatable flags
+  # header:
+  #   - "Authorization: Bearer token"
+  #   - "X-Request-Source: fasthttploader"
+
+# Weighted requests instead of target path. May be a path to JSON or YAML file
+# scenario:
+#   - name: list
+#     method: GET
+#     path: /items
+#     weight: 80
+#   - name: cart
+#     method: POST
+#     path: /cart
+#     body: "{}"
+#     weight: 20
+
+rate:
+  # Shape of rate limit over -d like "ramp:100-1000" instead of -q
+  # profile: ramp:100-1000
+  # warmup: 10s
+
+thresholds:
+  # Exit with non-zero status if results of load phase violate them
+  # maxErrRate: 1
+  # maxP99: 200ms
+  # minRps: 90
+
+exporters:
+  # export:
+  #   - influx=http://influx:8086/write?db=load
+  #   - statsd=localhost:8125
+  # pushgateway: http://pushgateway:9091
+
+report:
+  # r: report.html
+  # format: json
+  # junit: junit.xml
+`
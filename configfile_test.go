@@ -0,0 +1,84 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseConfig(t *testing.T) {
+	f := func(s, target string, settings []configSetting) {
+		t.Helper()
+		cfg, err := parseConfig([]byte(s))
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if cfg.target != target {
+			t.Errorf("Unexpected target for %q. Got: %q; Expected: %q", s, cfg.target, target)
+		}
+		if !reflect.DeepEqual(cfg.settings, settings) {
+			t.Errorf("Unexpected settings for %q. Got: %v; Expected: %v", s, cfg.settings, settings)
+		}
+	}
+
+	f("", "", nil)
+	f("target: http://a/\nq: 1e3\nd: 1m\n", "http://a/", []configSetting{
+		{name: "q", values: []string{"1e3"}},
+		{name: "d", values: []string{"1m"}},
+	})
+	f("load:\n  c: 10\nthresholds:\n  maxP99: 50ms\nreport:\n", "", []configSetting{
+		{name: "c", values: []string{"10"}},
+		{name: "maxP99", values: []string{"50ms"}},
+	})
+	f("header:\n  - \"X-Foo: bar\"\n  - \"X-Bar: baz\"\nk: true\n", "", []configSetting{
+		{name: "header", values: []string{"X-Foo: bar", "X-Bar: baz"}},
+		{name: "k", values: []string{"true"}},
+	})
+}
+
+func TestParseConfigScenario(t *testing.T) {
+	cfg, err := parseConfig([]byte("scenario:\n  - path: /a\n    weight: 3\n  - path: /b\n    weight: 1\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var entries []scenarioEntry
+	if err := yaml.Unmarshal(cfg.scenario, &entries); err != nil {
+		t.Fatalf("Cannot parse scenario %q: %s", cfg.scenario, err)
+	}
+	expected := []scenarioEntry{{Path: "/a", Weight: 3}, {Path: "/b", Weight: 1}}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Unexpected scenario. Got: %+v; Expected: %+v", entries, expected)
+	}
+	if cfg.settings != nil {
+		t.Errorf("Unexpected settings: %v", cfg.settings)
+	}
+}
+
+func TestParseConfigError(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		if _, err := parseConfig([]byte(s)); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+
+	f("- q: 1")
+	f("qps: 100")
+	f("q: 100\nload:\n  q: 200\n")
+	f("q: [1, 2]")
+	f("header: {a: b}")
+	f("target: [http://a/, http://b/]")
+	f("config: other.yaml")
+	f("q: 1\n  d: 1m")
+}
+
+func TestConfigTemplate(t *testing.T) {
+	cfg, err := parseConfig([]byte(configTemplate))
+	if err != nil {
+		t.Fatalf("Cannot parse config template: %s", err)
+	}
+	if cfg.target == "" {
+		t.Errorf("Config template must set target")
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+var controlListen = flag.String("control-listen", "", "Set address like \":8090\" to serve control API, which steers load phase while it is running: "+
+	"POST /qps with {\"limit\": 5000} sets rate limit, POST /workers with {\"add\": 50} starts clients or stops them if negative, "+
+	"POST /stop finishes test with report like SIGINT does and GET /status returns current state")
+
+// controlTimeout is a max time of waiting for load phase to take command of control API
+const controlTimeout = time.Second
+
+// controlCommand is a command of control API, which is applied by loop of load phase,
+// so state of client and rate limiter isn't changed concurrently
+type controlCommand struct {
+	// qps is a new rate limit. Is zero if rate isn't changed
+	qps float64
+	// workers is a number of clients to start, or to stop if negative
+	workers int
+
+	reply chan controlReply
+}
+
+// controlReply is a result of controlCommand
+type controlReply struct {
+	status int
+	// err is returned with status instead of state if it isn't empty
+	err   string
+	state controlState
+}
+
+// controlState is a state of load phase returned by control API
+type controlState struct {
+	Phase    string  `json:"phase"`
+	QPSLimit float64 `json:"qps_limit"`
+	Workers  int     `json:"workers"`
+	Queued   int     `json:"queued"`
+	Requests uint64  `json:"requests"`
+	Errors   uint64  `json:"errors"`
+}
+
+// controlCh passes commands of control API to loop of load phase
+var controlCh = make(chan controlCommand)
+
+// controlServer serves control API at -control-listen. Is nil if -control-listen isn't set
+var controlServer *http.Server
+
+// startControlListener serves control API at addr like ":8090"
+func startControlListener(addr string) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Cannot listen -control-listen %s: %s", addr, err)
+	}
+	controlServer = &http.Server{Handler: newControlHandler()}
+	go func(s *http.Server) {
+		if err := s.Serve(ln); err != nil && err != http.ErrServerClosed {
+			slog.Error("Error while serving control API", "addr", addr, "error", err)
+		}
+	}(controlServer)
+	fmt.Fprintf(out, "Serving control API at http://%s\n", ln.Addr())
+}
+
+// stopControlListener shuts control API down. Is no-op if -control-listen isn't set
+func stopControlListener() {
+	if controlServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), controlTimeout)
+	defer cancel()
+	if err := controlServer.Shutdown(ctx); err != nil {
+		slog.Error("Error while shutting down control API", "error", err)
+	}
+	controlServer = nil
+}
+
+func newControlHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/qps", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Limit float64 `json:"limit"`
+		}
+		if !decodeControlRequest(w, r, &body) {
+			return
+		}
+		if !(body.Limit > 0) {
+			writeControlError(w, http.StatusBadRequest, "limit must be positive")
+			return
+		}
+		sendControlCommand(w, controlCommand{qps: body.Limit})
+	})
+	mux.HandleFunc("/workers", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Add int `json:"add"`
+		}
+		if !decodeControlRequest(w, r, &body) {
+			return
+		}
+		if body.Add == 0 {
+			writeControlError(w, http.StatusBadRequest, "add must be non-zero number of clients")
+			return
+		}
+		sendControlCommand(w, controlCommand{workers: body.Add})
+	})
+	mux.HandleFunc("/stop", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeControlError(w, http.StatusMethodNotAllowed, "use POST")
+			return
+		}
+		interrupt("\nStopped by control API: finishing current stage to write report")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeControlError(w, http.StatusMethodNotAllowed, "use GET")
+			return
+		}
+		sendControlCommand(w, controlCommand{})
+	})
+	return mux
+}
+
+// decodeControlRequest decodes JSON body of POST request to v. Returns false if error is written to w
+func decodeControlRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.Method != http.MethodPost {
+		writeControlError(w, http.StatusMethodNotAllowed, "use POST")
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeControlError(w, http.StatusBadRequest, fmt.Sprintf("cannot parse request: %s", err))
+		return false
+	}
+	return true
+}
+
+// sendControlCommand passes cmd to load phase and writes its reply to w
+func sendControlCommand(w http.ResponseWriter, cmd controlCommand) {
+	cmd.reply = make(chan controlReply, 1)
+	select {
+	case controlCh <- cmd:
+	case <-time.After(controlTimeout):
+		writeControlError(w, http.StatusServiceUnavailable, "load phase isn't running")
+		return
+	}
+	reply := <-cmd.reply
+	if reply.err != "" {
+		writeControlError(w, reply.status, reply.err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply.state)
+}
+
+func writeControlError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+// applyControl applies cmd to load phase. It must be called by loop of load phase
+func applyControl(cmd controlCommand) controlReply {
+	if cmd.qps > 0 {
+		if pattern != nil || *loadModel == loadModelConcurrency || profile != nil || *rampSteps > 0 || replayOffsets != nil {
+			return controlReply{status: http.StatusConflict,
+				err: "rate is set by -burst-pattern, -load-model concurrency, -profile, -ramp-steps or -replay-timing original"}
+		}
+		setLimit(cmd.qps)
+		annotateControl(fmt.Sprintf("qps limit set to %.2f", throttle.Limit()))
+		slog.Info("Rate limit changed by control API", "qps", throttle.Limit())
+	}
+	if cmd.workers > 0 {
+		client.RunWorkers(cmd.workers)
+		annotateControl(fmt.Sprintf("%d clients started", cmd.workers))
+		slog.Info("Clients started by control API", "clients", cmd.workers)
+	} else if cmd.workers < 0 {
+		n := client.StopWorkers(-cmd.workers)
+		annotateControl(fmt.Sprintf("%d clients stopped", n))
+		slog.Info("Clients stopped by control API", "clients", n)
+	}
+	return controlReply{state: controlState{
+		Phase:    samplePhase,
+		QPSLimit: qpsLimit(),
+		Workers:  client.Amount(),
+		Queued:   client.Overflow(),
+		Requests: client.RequestSum(),
+		Errors:   client.Errors(),
+	}}
+}
+
+// annotateControl marks change made by control API on charts of report
+func annotateControl(label string) {
+	annotations = append(annotations, annotation{at: time.Now(), label: label + " by control API"})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestControlHandler(t *testing.T) {
+	h := newControlHandler()
+	// commands are taken by fake loop of load phase
+	cmds := make(chan controlCommand, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case cmd := <-controlCh:
+				cmds <- cmd
+				cmd.reply <- controlReply{state: controlState{Phase: "load", Workers: 10}}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	f := func(method, path, body string, status int, expected controlCommand) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		if w.Code != status {
+			t.Fatalf("Unexpected status of %s %s. Got: %d; Expected: %d; body: %s", method, path, w.Code, status, w.Body)
+		}
+		if status != http.StatusOK {
+			return
+		}
+		cmd := <-cmds
+		if cmd.qps != expected.qps || cmd.workers != expected.workers {
+			t.Errorf("Unexpected command of %s %s. Got: %+v; Expected: %+v", method, path, cmd, expected)
+		}
+		if !strings.Contains(w.Body.String(), `"workers":10`) {
+			t.Errorf("Unexpected state: %s", w.Body)
+		}
+	}
+
+	f("POST", "/qps", `{"limit": 5000}`, http.StatusOK, controlCommand{qps: 5000})
+	f("POST", "/workers", `{"add": 50}`, http.StatusOK, controlCommand{workers: 50})
+	f("POST", "/workers", `{"add": -5}`, http.StatusOK, controlCommand{workers: -5})
+	f("GET", "/status", "", http.StatusOK, controlCommand{})
+	f("POST", "/qps", `{"limit": 0}`, http.StatusBadRequest, controlCommand{})
+	f("POST", "/qps", `limit=10`, http.StatusBadRequest, controlCommand{})
+	f("POST", "/workers", `{}`, http.StatusBadRequest, controlCommand{})
+	f("GET", "/qps", "", http.StatusMethodNotAllowed, controlCommand{})
+	f("POST", "/status", "", http.StatusMethodNotAllowed, controlCommand{})
+}
+
+func TestControlHandlerNotRunning(t *testing.T) {
+	w := httptest.NewRecorder()
+	newControlHandler().ServeHTTP(w, httptest.NewRequest("GET", "/status", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Unexpected status without load phase. Got: %d; Expected: %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
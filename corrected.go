@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/ratelimiter"
+)
+
+// schedule tracks intended send time of requests by rate limit, so latency could be measured
+// from it instead of time when request was actually queued. While target stalls and workers
+// are busy, tokens of throttle accumulate and intended time falls behind, like in wrk2
+type schedule struct {
+	at    time.Time
+	limit float64
+}
+
+// next returns intended send time of the next request at rate limit.
+// Schedule restarts from now once limit changes, since throttle is reset too.
+// Intended time never runs ahead of now, and lag shorter than tick of throttle
+// is its jitter rather than stall, so schedule catches up with now
+func (s *schedule) next(now time.Time, limit float64) time.Time {
+	if s.at.IsZero() || limit != s.limit {
+		s.at, s.limit = now, limit
+		return s.at
+	}
+	s.at = s.at.Add(time.Duration(float64(time.Second) / limit))
+	if now.Sub(s.at) < ratelimiter.TickPeriod {
+		s.at = now
+	}
+	return s.at
+}
+
+// printCorrectedLatency prints latency measured from intended send time of requests
+// and how much measured latency underestimates it
+func printCorrectedLatency() {
+	if client.RequestSum() == 0 {
+		return
+	}
+	l := client.CorrectedLatency()
+	fmt.Fprintf(out, "Corrected latency: p50: %s; p90: %s; p95: %s; p99: %s; max: %s; Underestimated by measured (p99): %s\n",
+		formatLatency(l.P50), formatLatency(l.P90), formatLatency(l.P95), formatLatency(l.P99), formatLatency(l.Max),
+		formatLatency(l.P99-client.Latency().P99))
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	start := time.Now()
+	var s schedule
+	f := func(now time.Duration, limit float64, expAt time.Duration) {
+		t.Helper()
+		if at := s.next(start.Add(now), limit); !at.Equal(start.Add(expAt)) {
+			t.Fatalf("expected intended time %s at %s; got %s", expAt, now, at.Sub(start))
+		}
+	}
+	f(0, 10, 0)
+	f(100*time.Millisecond, 10, 100*time.Millisecond)
+	// intended time isn't ahead of now
+	f(150*time.Millisecond, 10, 150*time.Millisecond)
+	// jitter of throttle isn't a lag
+	f(253*time.Millisecond, 10, 253*time.Millisecond)
+	// tokens accumulated during stall fall behind
+	f(time.Second, 10, 353*time.Millisecond)
+	f(time.Second, 10, 453*time.Millisecond)
+	f(time.Second, 10, 553*time.Millisecond)
+	// change of limit restarts schedule
+	f(2*time.Second, 20, 2*time.Second)
+	f(2*time.Second, 20, 2*time.Second)
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// curlCommand contains parts of request parsed from curl command
+type curlCommand struct {
+	method  string
+	url     string
+	headers [][2]string
+	body    string
+}
+
+// parseCurl parses curl command like the one copied from browser devtools.
+// Only options which define request are supported: -X, -H, -d (and its --data-* kinds), -u.
+// Any other option causes an error, since request would differ from the one sent by curl
+func parseCurl(s string) (*curlCommand, error) {
+	args, err := splitShellWords(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse curl command: %s", err)
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, fmt.Errorf("curl command must start with \"curl\"")
+	}
+
+	cc := &curlCommand{}
+	var data []string
+	for i := 1; i < len(args); i++ {
+		name, v := args[i], ""
+		hasValue := false
+		// short options may be followed by value without space, like -XPOST
+		if len(name) > 2 && name[0] == '-' && name[1] != '-' && strings.ContainsRune("XHdu", rune(name[1])) {
+			name, v, hasValue = name[:2], name[2:], true
+		}
+		value := func() (string, error) {
+			if hasValue {
+				return v, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("curl option %s requires value", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "-X", "--request":
+			if cc.method, err = value(); err != nil {
+				return nil, err
+			}
+			cc.method = strings.ToUpper(cc.method)
+		case "-H", "--header":
+			h, err := value()
+			if err != nil {
+				return nil, err
+			}
+			n := strings.Index(h, ":")
+			if n < 1 {
+				return nil, fmt.Errorf("cannot parse curl header %q", h)
+			}
+			cc.headers = append(cc.headers, [2]string{strings.TrimSpace(h[:n]), strings.TrimSpace(h[n+1:])})
+		case "-d", "--data", "--data-ascii", "--data-binary", "--data-raw":
+			d, err := value()
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(d, "@") && name != "--data-raw" {
+				if d, err = readCurlData(d[1:], name == "--data-binary"); err != nil {
+					return nil, err
+				}
+			}
+			data = append(data, d)
+		case "-u", "--user":
+			u, err := value()
+			if err != nil {
+				return nil, err
+			}
+			cc.headers = append(cc.headers, [2]string{"Authorization", "Basic " + base64.StdEncoding.EncodeToString([]byte(u))})
+		case "--url":
+			if cc.url, err = value(); err != nil {
+				return nil, err
+			}
+		case "--compressed":
+			// compression is enabled by default, see -disable-compression
+		default:
+			if strings.HasPrefix(name, "-") {
+				return nil, fmt.Errorf("curl option %s is not supported", name)
+			}
+			if cc.url != "" {
+				return nil, fmt.Errorf("curl command contains more than one url: %q and %q", cc.url, name)
+			}
+			cc.url = name
+		}
+	}
+
+	if cc.url == "" {
+		return nil, fmt.Errorf("curl command doesn't contain url")
+	}
+	if len(data) > 0 {
+		cc.body = strings.Join(data, "&")
+		if cc.method == "" {
+			cc.method = "POST"
+		}
+		if cc.header("Content-Type") == "" {
+			cc.headers = append(cc.headers, [2]string{"Content-Type", "application/x-www-form-urlencoded"})
+		}
+	}
+	if cc.method == "" {
+		cc.method = "GET"
+	}
+	return cc, nil
+}
+
+// readCurlData reads body from file like curl does for -d @file.
+// Newlines are stripped unless binary is true
+func readCurlData(path string, binary bool) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read curl data file: %s", err)
+	}
+	if binary {
+		return string(b), nil
+	}
+	return strings.NewReplacer("\r", "", "\n", "").Replace(string(b)), nil
+}
+
+// header returns value of header with given name, case-insensitive
+func (cc *curlCommand) header(name string) string {
+	for _, h := range cc.headers {
+		if strings.EqualFold(h[0], name) {
+			return h[1]
+		}
+	}
+	return ""
+}
+
+// splitShellWords splits command into words like POSIX shell does.
+// Supports single and double quotes, ANSI-C quotes ($'...'),
+// backslash escapes and line continuations
+func splitShellWords(s string) ([]string, error) {
+	var words []string
+	var w strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, w.String())
+				w.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			if i+1 >= len(s) {
+				return nil, fmt.Errorf("unexpected end after backslash")
+			}
+			i++
+			if s[i] == '\n' {
+				// line continuation
+				continue
+			}
+			w.WriteByte(s[i])
+			inWord = true
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			w.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '$' && i+1 < len(s) && s[i+1] == '\'':
+			n, err := readANSIQuoted(s[i+2:], &w)
+			if err != nil {
+				return nil, err
+			}
+			i += n + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				w.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		default:
+			w.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, w.String())
+	}
+	return words, nil
+}
+
+// readANSIQuoted reads content of $'...' quote from s into w.
+// Returns number of read bytes, including closing quote
+func readANSIQuoted(s string, w *strings.Builder) (int, error) {
+	escapes := map[byte]byte{'n': '\n', 't': '\t', 'r': '\r', '\\': '\\', '\'': '\'', '"': '"'}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			return i + 1, nil
+		case '\\':
+			if i+1 >= len(s) {
+				break
+			}
+			i++
+			if e, ok := escapes[s[i]]; ok {
+				w.WriteByte(e)
+			} else {
+				w.WriteByte('\\')
+				w.WriteByte(s[i])
+			}
+		default:
+			w.WriteByte(s[i])
+		}
+	}
+	return 0, fmt.Errorf("unterminated $' quote")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseCurl(t *testing.T) {
+	cc, err := parseCurl(`curl 'http://localhost/user?id=1' -XPUT \
+  -H 'Content-Type: application/json' -H "X-Name: \"quoted\"" \
+  --data-raw $'{"a":\n1}' -d b=2 --compressed -u user:pass`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if cc.url != "http://localhost/user?id=1" {
+		t.Errorf("Unexpected url. Got: %q", cc.url)
+	}
+	if cc.method != "PUT" {
+		t.Errorf("Unexpected method. Got: %q; Expected: %q", cc.method, "PUT")
+	}
+	if body := "{\"a\":\n1}&b=2"; cc.body != body {
+		t.Errorf("Unexpected body. Got: %q; Expected: %q", cc.body, body)
+	}
+	f := func(name, value string) {
+		t.Helper()
+		if v := cc.header(name); v != value {
+			t.Errorf("Unexpected value of header %s. Got: %q; Expected: %q", name, v, value)
+		}
+	}
+	f("content-type", "application/json")
+	f("X-Name", `"quoted"`)
+	f("Authorization", "Basic dXNlcjpwYXNz")
+}
+
+func TestParseCurlDefaults(t *testing.T) {
+	cc, err := parseCurl(`curl -d a=1 http://localhost/`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if cc.method != "POST" {
+		t.Errorf("Unexpected method. Got: %q; Expected: %q", cc.method, "POST")
+	}
+	if v := cc.header("Content-Type"); v != "application/x-www-form-urlencoded" {
+		t.Errorf("Unexpected content type. Got: %q", v)
+	}
+}
+
+func TestParseCurlError(t *testing.T) {
+	for _, s := range []string{"", "wget http://localhost/", "curl", "curl -k http://localhost/", "curl -H http://localhost/",
+		"curl 'http://localhost/", "curl http://localhost/ http://localhost/2", "curl http://localhost/ -X"} {
+		if _, err := parseCurl(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// curvePoint is a point of latency-throughput curve
+type curvePoint struct {
+	name string
+	rampStep
+
+	// p50 and connections are only reported by -summary-comparison-table
+	p50         float64
+	connections uint64
+}
+
+// stagePoints contains results of every finished stage
+var stagePoints []curvePoint
+
+// addStagePoint records results of stage started at t
+func addStagePoint(name string, t time.Time) {
+	s := rampStep{
+		qps:      qpsLimit(),
+		requests: client.RequestSum(),
+		p99:      client.RequestDuration()[0.99],
+	}
+	s.rps = float64(s.requests) / time.Since(t).Seconds()
+	if s.requests > 0 {
+		s.errorRate = float64(client.Errors()) / float64(s.requests) * 100
+	}
+	stagePoints = append(stagePoints, curvePoint{
+		name:        name,
+		rampStep:    s,
+		p50:         client.RequestDuration()[0.5],
+		connections: client.ConnOpen(),
+	})
+	slog.Info("Phase finished", "phase", phaseName(name), "rps", s.rps, "error_rate", s.errorRate, "p99", s.p99, "requests", s.requests)
+}
+
+// curvePoints returns points of latency-throughput curve.
+// Load phase is represented by its ramp steps if -ramp-steps is set
+// and by every number of clients if -concurrency-sweep is set
+func curvePoints() []curvePoint {
+	var points []curvePoint
+	for _, p := range stagePoints {
+		if p.name == "warmup" {
+			// warmup isn't measured, so it isn't a point of curve
+			continue
+		}
+		if p.name == "load" && len(steps) > 0 {
+			for i, s := range steps {
+				points = append(points, curvePoint{name: fmt.Sprintf("step %d", i+1), rampStep: s})
+			}
+			continue
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// writeCurve writes points of latency-throughput curve to CSV file.
+// p99 is written in unit of report and is empty if step has less than -min-samples requests
+func writeCurve(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	w.Write([]string{"rps", "p99_" + r.LatencyUnit, "error_rate_percent", "point", "qps_limit", "requests"})
+	for _, p := range curvePoints() {
+		p99 := ""
+		if p.requests >= *minSamples {
+			p99 = strconv.FormatFloat(p.p99*report.LatencyUnits[r.LatencyUnit], 'f', 3, 64)
+		}
+		w.Write([]string{
+			strconv.FormatFloat(p.rps, 'f', 2, 64),
+			p99,
+			strconv.FormatFloat(p.errorRate, 'f', 3, 64),
+			p.name,
+			strconv.FormatFloat(p.qps, 'f', 2, 64),
+			strconv.FormatUint(p.requests, 10),
+		})
+	}
+	w.Flush()
+	if err = w.Error(); err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	return err
+}
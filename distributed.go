@@ -0,0 +1,452 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// distJob is a share of load, which coordinator assigns to worker
+type distJob struct {
+	QPS      float64       `json:"qps"`
+	Clients  int           `json:"clients"`
+	Duration time.Duration `json:"duration"`
+}
+
+// distSample is a sample of metrics of load phase of worker, which is sent to coordinator.
+// Counters are cumulative since start of load phase
+type distSample struct {
+	Connections    uint64 `json:"connections"`
+	RequestSum     uint64 `json:"request_sum"`
+	RequestSuccess uint64 `json:"request_success"`
+	Errors         uint64 `json:"errors"`
+	Timeouts       uint64 `json:"timeouts"`
+	BytesWritten   uint64 `json:"bytes_written"`
+	BytesRead      uint64 `json:"bytes_read"`
+
+	StatusCounts  map[int]uint64 `json:"status_counts"`
+	ErrorMessages map[string]int `json:"error_messages"`
+
+	// RequestDuration maps quantile to latency in seconds. Quantiles without observations are null
+	RequestDuration map[string]*float64 `json:"request_duration"`
+
+	// Final is true for the sample sent once load phase of worker is finished.
+	// Latency and Elapsed are set only in final sample
+	Final   bool            `json:"final"`
+	Latency *report.Latency `json:"latency,omitempty"`
+	Elapsed time.Duration   `json:"elapsed"`
+}
+
+// applyDistributed validates -mode, -coordinator and -expectWorkers
+func applyDistributed() {
+	switch *mode {
+	case "":
+		return
+	case "coordinator":
+		if *q == 0 {
+			usageAndExit("-mode coordinator requires -q, since qps is split between workers")
+		}
+		if *expectWorkers < 1 {
+			usageAndExit("-expectWorkers must be positive")
+		}
+		if *c < *expectWorkers {
+			usageAndExit("-c can't be less than -expectWorkers, since every worker needs a client")
+		}
+		if *junitOut != "" || *summaryOnFailure || *liveFlag || *slaFlag != "" {
+			usageAndExit("-mode coordinator can't be used with -junit, -summary-only-on-failure, -live or -sla")
+		}
+	case "worker":
+		if isFlagSet("q") || isFlagSet("c") {
+			usageAndExit("-q and -c of worker are assigned by coordinator")
+		}
+	default:
+		usageAndExit(fmt.Sprintf("unsupported -mode %q; supported modes are coordinator and worker", *mode))
+	}
+	if *coordinatorAddr == "" {
+		usageAndExit(fmt.Sprintf("-mode %s requires -coordinator address", *mode))
+	}
+	if *burstFlag != "" || *rampSteps > 0 || *totalRequests > 0 || *checkpointFile != "" || len(sweepLevels) > 0 {
+		usageAndExit("-mode can't be used with -burst-pattern, -ramp-steps, -n, -checkpoint-file or sweeps")
+	}
+}
+
+// splitLoad splits qps and clients of cfg evenly between n workers
+func splitLoad(cfg loadConfig, n int, d time.Duration) []distJob {
+	jobs := make([]distJob, n)
+	for i := range jobs {
+		jobs[i] = distJob{QPS: cfg.qps / float64(n), Clients: cfg.c / n, Duration: d}
+		if i < cfg.c%n {
+			jobs[i].Clients++
+		}
+	}
+	return jobs
+}
+
+// mergeSamples sums counters of samples. Latency quantiles are averaged with weights
+// of numbers of requests, so they are approximate, while max is exact
+func mergeSamples(samples []distSample) distSample {
+	res := distSample{
+		StatusCounts:    make(map[int]uint64),
+		ErrorMessages:   make(map[string]int),
+		RequestDuration: make(map[string]*float64),
+		Final:           true,
+	}
+	var latency report.Latency
+	var latencyRequests uint64
+	weights := make(map[string]uint64)
+	for _, s := range samples {
+		res.Connections += s.Connections
+		res.RequestSum += s.RequestSum
+		res.RequestSuccess += s.RequestSuccess
+		res.Errors += s.Errors
+		res.Timeouts += s.Timeouts
+		res.BytesWritten += s.BytesWritten
+		res.BytesRead += s.BytesRead
+		for code, n := range s.StatusCounts {
+			res.StatusCounts[code] += n
+		}
+		for msg, n := range s.ErrorMessages {
+			res.ErrorMessages[msg] += n
+		}
+		for q, v := range s.RequestDuration {
+			if _, ok := res.RequestDuration[q]; !ok {
+				res.RequestDuration[q] = nil
+			}
+			if v == nil || s.RequestSum == 0 {
+				continue
+			}
+			sum := *v * float64(s.RequestSum)
+			if prev := res.RequestDuration[q]; prev != nil {
+				sum += *prev
+			}
+			res.RequestDuration[q] = &sum
+			weights[q] += s.RequestSum
+		}
+		res.Final = res.Final && s.Final
+		if s.Elapsed > res.Elapsed {
+			res.Elapsed = s.Elapsed
+		}
+		if s.Latency != nil {
+			w := float64(s.RequestSum)
+			latency.P50 += s.Latency.P50 * w
+			latency.P90 += s.Latency.P90 * w
+			latency.P95 += s.Latency.P95 * w
+			latency.P99 += s.Latency.P99 * w
+			latency.Max = math.Max(latency.Max, s.Latency.Max)
+			latencyRequests += s.RequestSum
+		}
+	}
+	for q, v := range res.RequestDuration {
+		if v != nil {
+			*v /= float64(weights[q])
+		}
+	}
+	if latencyRequests > 0 {
+		w := float64(latencyRequests)
+		latency.P50, latency.P90, latency.P95, latency.P99 = latency.P50/w, latency.P90/w, latency.P95/w, latency.P99/w
+		res.Latency = &latency
+	}
+	return res
+}
+
+// durations returns RequestDuration of s with quantiles parsed and missing values as NaN
+func (s distSample) durations() map[float64]float64 {
+	result := make(map[float64]float64, len(s.RequestDuration))
+	for k, v := range s.RequestDuration {
+		q, err := strconv.ParseFloat(k, 64)
+		if err != nil {
+			continue
+		}
+		result[q] = math.NaN()
+		if v != nil {
+			result[q] = *v
+		}
+	}
+	return result
+}
+
+// coordinatorConn is a connection of worker to coordinator
+type coordinatorConn struct {
+	mu       sync.Mutex
+	conn     net.Conn
+	enc      *json.Encoder
+	err      error
+	finished bool
+}
+
+// coordinator is a connection to coordinator. Is nil unless -mode is worker
+var coordinator *coordinatorConn
+
+// joinCoordinator connects to -coordinator and waits for share of load, which replaces cfg and -d.
+// Test is interrupted if coordinator closes connection before load phase is finished
+func joinCoordinator(cfg *loadConfig) {
+	conn, err := net.DialTimeout("tcp", *coordinatorAddr, *dialTimeout)
+	if err != nil {
+		log.Fatalf("Error while connecting to coordinator: %s", err)
+	}
+	fmt.Fprintf(out, "Connected to coordinator %s; waiting for other workers\n", *coordinatorAddr)
+	var job distJob
+	if err := json.NewDecoder(conn).Decode(&job); err != nil {
+		log.Fatalf("Error while reading share of load from coordinator: %s", err)
+	}
+	cfg.qps, cfg.c, *d = job.QPS, job.Clients, job.Duration
+	fmt.Fprintf(out, "Share of load: %.2f qps with %d clients for %s\n", job.QPS, job.Clients, job.Duration)
+
+	cc := &coordinatorConn{conn: conn, enc: json.NewEncoder(conn)}
+	coordinator = cc
+	go func() {
+		// coordinator sends nothing after job, so read returns once connection is closed
+		var b [1]byte
+		conn.Read(b[:])
+		cc.mu.Lock()
+		finished := cc.finished
+		cc.mu.Unlock()
+		if !finished {
+			interrupt("Coordinator closed connection: finishing load phase")
+		}
+	}()
+}
+
+// send sends current metrics of client to coordinator. Connection is closed after final sample
+func (cc *coordinatorConn) send(final bool) {
+	s := distSample{
+		Connections:     client.ConnOpen(),
+		RequestSum:      client.RequestSum(),
+		RequestSuccess:  client.RequestSuccess(),
+		Errors:          client.Errors(),
+		Timeouts:        client.Timeouts(),
+		BytesWritten:    client.BytesWritten(),
+		BytesRead:       client.BytesRead(),
+		StatusCounts:    client.StatusCounts(),
+		ErrorMessages:   client.ErrorMessages(),
+		RequestDuration: make(map[string]*float64),
+		Final:           final,
+	}
+	for q, v := range client.RequestDuration() {
+		v := v
+		k := strconv.FormatFloat(q, 'f', -1, 64)
+		// NaN can't be marshaled
+		s.RequestDuration[k] = nil
+		if !math.IsNaN(v) {
+			s.RequestDuration[k] = &v
+		}
+	}
+	if final {
+		s.Elapsed = loadElapsed
+		if s.RequestSum > 0 {
+			l := client.Latency()
+			s.Latency = &report.Latency{P50: l.P50, P90: l.P90, P95: l.P95, P99: l.P99, Max: l.Max}
+		}
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.err != nil || cc.finished {
+		return
+	}
+	if cc.err = cc.enc.Encode(s); cc.err != nil {
+		slog.Error("Error while sending sample to coordinator", "error", cc.err)
+		return
+	}
+	if final {
+		cc.finished = true
+		cc.conn.Close()
+	}
+}
+
+// distWorker is a worker connected to coordinator
+type distWorker struct {
+	addr string
+	conn net.Conn
+
+	// last is the latest sample of worker
+	last distSample
+
+	// err is set if connection was broken before final sample
+	err error
+}
+
+// receive reads samples of w until final one and notifies done
+func (w *distWorker) receive(mu *sync.Mutex, done chan<- struct{}) {
+	defer func() { done <- struct{}{} }()
+	dec := json.NewDecoder(w.conn)
+	for {
+		var s distSample
+		if err := dec.Decode(&s); err != nil {
+			mu.Lock()
+			w.err = err
+			mu.Unlock()
+			return
+		}
+		mu.Lock()
+		w.last = s
+		mu.Unlock()
+		if s.Final {
+			return
+		}
+	}
+}
+
+// distTotals are merged results of load phase of all workers. Is nil unless -mode is coordinator
+var distTotals *distSample
+
+// coordinate waits for -expectWorkers workers, assigns them equal shares of cfg
+// and merges their samples into report every -samplePeriod, until all of them finish load phase
+func coordinate(ctx context.Context, cfg loadConfig) {
+	distTotals = &distSample{}
+	ln, err := net.Listen("tcp", *coordinatorAddr)
+	if err != nil {
+		log.Fatalf("Error while starting coordinator: %s", err)
+	}
+	defer ln.Close()
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	fmt.Fprintf(out, "Waiting for %d workers on %s\n", *expectWorkers, ln.Addr())
+	var workers []*distWorker
+	for len(workers) < *expectWorkers {
+		conn, err := ln.Accept()
+		if err != nil {
+			if isInterrupted() {
+				fmt.Fprintln(out, "Load phase is skipped, since test was interrupted")
+				for _, w := range workers {
+					w.conn.Close()
+				}
+				return
+			}
+			log.Fatalf("Error while accepting worker: %s", err)
+		}
+		workers = append(workers, &distWorker{addr: conn.RemoteAddr().String(), conn: conn})
+		fmt.Fprintf(out, "Worker %s joined (%d of %d)\n", conn.RemoteAddr(), len(workers), *expectWorkers)
+	}
+	for i, job := range splitLoad(cfg, len(workers), *d) {
+		if err := json.NewEncoder(workers[i].conn).Encode(job); err != nil {
+			log.Fatalf("Error while sending share of load to worker %s: %s", workers[i].addr, err)
+		}
+	}
+
+	fmt.Fprintln(out, "Run distributed load phase")
+	startTime := time.Now()
+	var mu sync.Mutex
+	done := make(chan struct{}, len(workers))
+	for _, w := range workers {
+		go w.receive(&mu, done)
+	}
+	merge := func() distSample {
+		mu.Lock()
+		defer mu.Unlock()
+		samples := make([]distSample, len(workers))
+		for i, w := range workers {
+			samples[i] = w.last
+		}
+		return mergeSamples(samples)
+	}
+	tick := time.NewTicker(*samplePeriod)
+	defer tick.Stop()
+	stop := ctx.Done()
+	for finished := 0; finished < len(workers); {
+		select {
+		case <-done:
+			finished++
+		case <-tick.C:
+			appendDistSample(merge(), cfg.qps)
+		case <-stop:
+			// workers finish load phase once connection is closed for writing,
+			// and their final samples are still received
+			for _, w := range workers {
+				w.conn.(*net.TCPConn).CloseWrite()
+			}
+			stop = nil
+		}
+	}
+	for _, w := range workers {
+		w.conn.Close()
+	}
+	totals := merge()
+	appendDistSample(totals, cfg.qps)
+	distTotals = &totals
+	loadElapsed = totals.Elapsed
+	if loadElapsed == 0 {
+		loadElapsed = time.Since(startTime)
+	}
+	addStagePoint("load", startTime)
+	printDistSummary(workers, totals)
+
+	r.LatencyUnit = *latencyUnit
+	if totals.Latency != nil {
+		r.Latency = totals.Latency
+		if r.LatencyUnit == "auto" {
+			r.LatencyUnit = report.AutoLatencyUnit(totals.Latency.P50)
+		}
+	} else if r.LatencyUnit == "auto" {
+		r.LatencyUnit = "ms"
+	}
+}
+
+// appendDistSample appends merged sample of workers to report.
+// Sample is skipped until any worker sends its first sample, so series stay aligned
+func appendDistSample(s distSample, qps float64) {
+	if len(s.RequestDuration) == 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.Timestamps = append(r.Timestamps, time.Since(testStart).Seconds())
+	r.Connections = append(r.Connections, s.Connections)
+	r.Errors = append(r.Errors, s.Errors)
+	r.Timeouts = append(r.Timeouts, s.Timeouts)
+	r.RequestSum = append(r.RequestSum, s.RequestSum)
+	r.RequestSuccess = append(r.RequestSuccess, s.RequestSuccess)
+	r.BytesWritten = append(r.BytesWritten, s.BytesWritten)
+	r.BytesRead = append(r.BytesRead, s.BytesRead)
+	r.Qps = append(r.Qps, uint64(qps))
+	r.StatusCounts = s.StatusCounts
+	r.StatusCodes = make(map[string]float64, len(s.StatusCounts))
+	for code, n := range s.StatusCounts {
+		r.StatusCodes[strconv.Itoa(code)] = float64(n) / float64(s.RequestSum) * 100
+	}
+	r.ErrorMessages = s.ErrorMessages
+	r.UpdateRequestDuration(s.durations())
+	r.UpdateStatusCodeSeries(s.StatusCounts)
+}
+
+// printDistSummary prints merged results of load phase and requests of every worker
+func printDistSummary(workers []*distWorker, totals distSample) {
+	fmt.Fprintf(out, "\n------ Distributed load (%d workers) ------\n", len(workers))
+	fmt.Fprintf(out, "Elapsed time: %fs\n", loadElapsed.Seconds())
+	var success float64
+	if totals.RequestSum > 0 {
+		success = float64(totals.RequestSuccess) / float64(totals.RequestSum) * 100
+	}
+	fmt.Fprintf(out, "Req done: %d; Success: %.2f %%\n", totals.RequestSum, success)
+	fmt.Fprintf(out, "QPS: %f; Connections: %d\n", float64(totals.RequestSum)/loadElapsed.Seconds(), totals.Connections)
+	fmt.Fprintf(out, "Errors: %d; Timeouts: %d\n", totals.Errors, totals.Timeouts)
+	if l := totals.Latency; l != nil {
+		fmt.Fprintf(out, "Latency (approximate): p50: %s; p90: %s; p95: %s; p99: %s; max: %s\n",
+			formatLatency(l.P50), formatLatency(l.P90), formatLatency(l.P95), formatLatency(l.P99), formatLatency(l.Max))
+	} else if totals.RequestSum > 0 {
+		fmt.Fprintln(out, "Latency: unknown, since workers didn't send final samples")
+	} else {
+		fmt.Fprintln(out, "Latency: no requests completed")
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].addr < workers[j].addr })
+	for _, w := range workers {
+		fmt.Fprintf(out, "Worker %s: requests %d; errors %d", w.addr, w.last.RequestSum, w.last.Errors)
+		if !w.last.Final {
+			fmt.Fprintf(out, "; connection lost before load phase was finished: %v", w.err)
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+func TestSplitLoad(t *testing.T) {
+	f := func(qps float64, c, n int, expClients []int) {
+		t.Helper()
+		jobs := splitLoad(loadConfig{qps: qps, c: c}, n, time.Minute)
+		if len(jobs) != n {
+			t.Fatalf("expected %d jobs; got %d", n, len(jobs))
+		}
+		var sumQPS float64
+		for i, job := range jobs {
+			if job.Clients != expClients[i] {
+				t.Fatalf("expected %d clients of job %d; got %d", expClients[i], i, job.Clients)
+			}
+			if job.Duration != time.Minute {
+				t.Fatalf("expected duration %s of job %d; got %s", time.Minute, i, job.Duration)
+			}
+			sumQPS += job.QPS
+		}
+		if math.Abs(sumQPS-qps) > 1e-9 {
+			t.Fatalf("expected %f qps in total; got %f", qps, sumQPS)
+		}
+	}
+	f(100, 4, 1, []int{4})
+	f(100, 4, 2, []int{2, 2})
+	f(100, 5, 2, []int{3, 2})
+	f(1000, 10, 3, []int{4, 3, 3})
+}
+
+func TestMergeSamples(t *testing.T) {
+	v := func(f float64) *float64 { return &f }
+	samples := []distSample{
+		{
+			RequestSum:      100,
+			Errors:          1,
+			StatusCounts:    map[int]uint64{200: 99, 500: 1},
+			RequestDuration: map[string]*float64{"0.5": v(0.1), "0.99": v(0.2)},
+			Final:           true,
+			Latency:         &report.Latency{P50: 0.1, P99: 0.2, Max: 0.3},
+			Elapsed:         time.Second,
+		},
+		{
+			RequestSum:      300,
+			Errors:          2,
+			StatusCounts:    map[int]uint64{200: 300},
+			RequestDuration: map[string]*float64{"0.5": v(0.2), "0.99": nil},
+			Final:           true,
+			Latency:         &report.Latency{P50: 0.2, P99: 0.4, Max: 0.5},
+			Elapsed:         2 * time.Second,
+		},
+	}
+	s := mergeSamples(samples)
+	if s.RequestSum != 400 || s.Errors != 3 {
+		t.Fatalf("expected 400 requests and 3 errors; got %d and %d", s.RequestSum, s.Errors)
+	}
+	if s.StatusCounts[200] != 399 || s.StatusCounts[500] != 1 {
+		t.Fatalf("unexpected status counts %v", s.StatusCounts)
+	}
+	if !s.Final || s.Elapsed != 2*time.Second {
+		t.Fatalf("expected final sample with elapsed %s; got %v and %s", 2*time.Second, s.Final, s.Elapsed)
+	}
+	d := s.durations()
+	if math.Abs(d[0.5]-0.175) > 1e-9 || math.Abs(d[0.99]-0.2) > 1e-9 {
+		t.Fatalf("unexpected request duration %v", d)
+	}
+	if l := s.Latency; l == nil || math.Abs(l.P50-0.175) > 1e-9 || math.Abs(l.P99-0.35) > 1e-9 || l.Max != 0.5 {
+		t.Fatalf("unexpected latency %+v", s.Latency)
+	}
+
+	samples[1].Final = false
+	if mergeSamples(samples).Final {
+		t.Fatalf("expected sample to be not final while some worker isn't finished")
+	}
+	if d := mergeSamples([]distSample{{RequestDuration: map[string]*float64{"0.5": nil}}}).durations(); !math.IsNaN(d[0.5]) {
+		t.Fatalf("expected NaN for quantile without observations; got %f", d[0.5])
+	}
+}
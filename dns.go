@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+var (
+	dnsServer          = flag.String("dns-server", "", "Resolve hosts of targets by DNS server like \"1.1.1.1:53\" instead of system resolver")
+	dnsResolveInterval = flag.Duration("dns-resolve-interval", 0, "Re-resolve hosts of new connections once resolved IPs are older than given interval, "+
+		"so long tests follow DNS failovers. Set -maxConnDuration, so established connections are renewed. "+
+		"If zero, hosts are resolved by fasthttp, which caches them for a minute, or on every new connection if -dns-server is set")
+)
+
+// staticHosts maps addresses like "example.com:443" to addresses of -resolve
+type staticHosts map[string]string
+
+func (m staticHosts) String() string {
+	var l []string
+	for k, v := range m {
+		l = append(l, k+"->"+v)
+	}
+	return strings.Join(l, ",")
+}
+
+func (m staticHosts) Set(s string) error {
+	n := strings.Index(s, ":")
+	k := strings.Index(s[n+1:], ":") + n + 1
+	if n <= 0 || k <= n {
+		return fmt.Errorf("-resolve %q must be in format \"host:port:addr\" like \"example.com:443:10.0.0.1\"", s)
+	}
+	host, port, addr := strings.ToLower(s[:n]), s[n+1:k], strings.Trim(s[k+1:], "[]")
+	if net.ParseIP(addr) == nil {
+		return fmt.Errorf("-resolve %q must map host to IP address; got %q", s, addr)
+	}
+	m[net.JoinHostPort(host, port)] = net.JoinHostPort(addr, port)
+	return nil
+}
+
+var resolveFlags = staticHosts{}
+
+func init() {
+	flag.Var(resolveFlags, "resolve", "Establish connections to host and port of target at given IP like curl, e.g. \"example.com:443:10.0.0.1\", "+
+		"so target is loaded without DNS lookup while Host header and TLS server name are kept. Is set multiple times")
+}
+
+// resolver is a resolver of -dns-server. Is nil if system resolver is used
+var resolver *net.Resolver
+
+// applyDNSControl validates -dns-server, -dns-resolve-interval and -resolve
+func applyDNSControl() {
+	if proxy.addr != "" && (*dnsServer != "" || *dnsResolveInterval != 0 || len(resolveFlags) > 0) {
+		usageAndExit("-dns-server, -dns-resolve-interval and -resolve can't be used with -proxy, since target is resolved by proxy")
+	}
+	if *dnsResolveInterval < 0 {
+		usageAndExit("-dns-resolve-interval can't be negative")
+	}
+	if len(resolveFlags) > 0 && (*verifyDNS || *dnsRoundRobin) {
+		usageAndExit("-resolve can't be used with -verify-dns-distribution and -dns-round-robin, since target isn't resolved")
+	}
+	if *dnsServer != "" {
+		if _, _, err := net.SplitHostPort(*dnsServer); err != nil {
+			usageAndExit(fmt.Sprintf("-dns-server must be in format \"host:port\" like \"1.1.1.1:53\": %s", err))
+		}
+		resolver = fastclient.NewResolver(*dnsServer)
+	}
+}
+
+// lookupHost resolves host by resolver of -dns-server or by system resolver
+func lookupHost(host string) ([]string, error) {
+	r := resolver
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return r.LookupHost(ctx, host)
+}
+
+// applyDNS resolves host of target for -verify-dns-distribution and -dns-round-robin
+func applyDNS() {
+	if proxy.addr != "" {
+		usageAndExit("-verify-dns-distribution and -dns-round-robin can't be used with -proxy, since target is resolved by proxy")
+	}
+	host, port := string(req.URI().Host()), ""
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		host, port = h, p
+	} else if string(req.URI().Scheme()) == "https" {
+		port = "443"
+	} else {
+		port = "80"
+	}
+	if net.ParseIP(host) != nil {
+		usageAndExit("-verify-dns-distribution and -dns-round-robin require hostname in url")
+	}
+
+	var err error
+	if resolvedIPs, err = lookupHost(host); err != nil {
+		usageAndExit(fmt.Sprintf("Cannot resolve %s: %s", host, err))
+	}
+	fmt.Fprintf(out, "%s resolves to %s\n", host, strings.Join(resolvedIPs, ", "))
+	if *dnsRoundRobin {
+		for _, ip := range resolvedIPs {
+			dialAddrs = append(dialAddrs, net.JoinHostPort(ip, port))
+		}
+	}
+}
+
+// unusedIPs returns resolved IPs to which no connections were established.
+// conns maps IP to number of connections
+func unusedIPs(resolved []string, conns map[string]uint64) []string {
+	var result []string
+	for _, ip := range resolved {
+		if conns[ip] == 0 {
+			result = append(result, ip)
+		}
+	}
+	return result
+}
+
+// printDNSDistribution prints distribution of connections of load phase across resolved IPs of target
+func printDNSDistribution() {
+	if len(resolvedIPs) == 0 {
+		return
+	}
+
+	conns := client.ConnIPs()
+	var total uint64
+	for _, n := range conns {
+		total += n
+	}
+	fmt.Fprintf(out, "------ DNS distribution (%d resolved IPs) ------\n", len(resolvedIPs))
+	shares := make(map[string]float64, len(conns))
+	for ip, n := range conns {
+		shares[ip] = float64(n) / float64(total) * 100
+	}
+	for _, ip := range resolvedIPs {
+		if _, ok := shares[ip]; !ok {
+			shares[ip] = 0
+		}
+	}
+	fmt.Fprintf(out, "Connections by IP: %s\n", formatShares(shares))
+	if unused := unusedIPs(resolvedIPs, conns); len(unused) > 0 {
+		fmt.Fprintf(out, "Warning: no connections were established to %d of %d resolved IPs (%s), so load isn't spread by DNS",
+			len(unused), len(resolvedIPs), strings.Join(unused, ", "))
+		if !*dnsRoundRobin {
+			fmt.Fprintf(out, "; pass -dns-round-robin to establish connections to resolved IPs in round-robin")
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out)
+}
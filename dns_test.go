@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnusedIPs(t *testing.T) {
+	f := func(resolved []string, conns map[string]uint64, expected []string) {
+		t.Helper()
+		if got := unusedIPs(resolved, conns); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Unexpected result for %v and %v. Got: %v; Expected: %v", resolved, conns, got, expected)
+		}
+	}
+
+	f(nil, nil, nil)
+	f([]string{"10.0.0.1", "10.0.0.2"}, map[string]uint64{"10.0.0.1": 5, "10.0.0.2": 5}, nil)
+	f([]string{"10.0.0.1", "10.0.0.2"}, map[string]uint64{"10.0.0.1": 10}, []string{"10.0.0.2"})
+	f([]string{"10.0.0.1"}, map[string]uint64{"10.0.0.1": 0, "10.0.0.9": 3}, []string{"10.0.0.1"})
+}
+
+func TestStaticHostsSet(t *testing.T) {
+	f := func(s, key, addr string) {
+		t.Helper()
+		m := staticHosts{}
+		if err := m.Set(s); err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if m[key] != addr {
+			t.Errorf("Unexpected mapping of %q. Got: %v; Expected: %s -> %s", s, m, key, addr)
+		}
+	}
+	f("example.com:443:10.0.0.1", "example.com:443", "10.0.0.1:443")
+	f("Example.com:80:[::1]", "example.com:80", "[::1]:80")
+
+	for _, s := range []string{"example.com", "example.com:443", ":443:10.0.0.1", "example.com:443:backend"} {
+		if err := (staticHosts{}).Set(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
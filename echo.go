@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/valyala/fasthttp"
+)
+
+// requestIDModifier returns fastclient.Client.NewModifier which sets unique id of every request
+// to header name after modifier returned by newModifier, if it isn't nil.
+// Ids depend on -seed like "fasthttploader-1a2b-3-42", where 3 is a number of worker
+// and 42 is a number of its request
+func requestIDModifier(name string, newModifier func() fastclient.Modifier) func() fastclient.Modifier {
+	var workers int32
+	return func() fastclient.Modifier {
+		prefix := fmt.Sprintf("fasthttploader-%x-%d-", uint32(*seed), atomic.AddInt32(&workers, 1))
+		var modify fastclient.Modifier
+		if newModifier != nil {
+			modify = newModifier()
+		}
+		var n uint64
+		var buf []byte
+		return func(req *fasthttp.Request) error {
+			if modify != nil {
+				if err := modify(req); err != nil {
+					return err
+				}
+			}
+			n++
+			buf = strconv.AppendUint(append(buf[:0], prefix...), n, 10)
+			req.Header.SetBytesV(name, buf)
+			return nil
+		}
+	}
+}
+
+// echoFailures returns number of responses, which didn't echo -verify-echo-header
+// or echoed it with another value, and total number of checked responses
+func echoFailures(results map[string]uint64) (uint64, uint64) {
+	failed := results[fastclient.EchoMissing] + results[fastclient.EchoMismatch]
+	return failed, failed + results[fastclient.EchoOK]
+}
+
+// printEchoResults prints rate of responses which failed echo check of -verify-echo-header
+func printEchoResults() {
+	results := client.EchoResults()
+	failed, total := echoFailures(results)
+	var rate float64
+	if total > 0 {
+		rate = float64(failed) / float64(total) * 100
+	}
+	fmt.Fprintf(out, "Echo of %s failed: %.2f %% of %d responses (missing: %d; mismatched: %d)", *echoHeader,
+		rate, total, results[fastclient.EchoMissing], results[fastclient.EchoMismatch])
+	if failed > 0 {
+		fmt.Fprintf(out, "; header is dropped or rewritten by target or middleware")
+	}
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRequestIDModifier(t *testing.T) {
+	newModifier := requestIDModifier("X-Request-ID", nil)
+	first, second := newModifier(), newModifier()
+	f := func(modify fastclient.Modifier, expected string) {
+		t.Helper()
+		req := new(fasthttp.Request)
+		if err := modify(req); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if got := string(req.Header.Peek("X-Request-ID")); got != expected {
+			t.Errorf("Unexpected request id. Got: %q; Expected: %q", got, expected)
+		}
+	}
+
+	f(first, "fasthttploader-0-1-1")
+	f(first, "fasthttploader-0-1-2")
+	f(second, "fasthttploader-0-2-1")
+}
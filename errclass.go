@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// topErrors is a number of the most frequent error messages printed in summary
+const topErrors = 5
+
+// topErrorMessages returns up to n the most frequent messages like "timeout: 42".
+// Messages with the same count are ordered alphabetically
+func topErrorMessages(messages map[string]int, n int) []string {
+	msgs := make([]string, 0, len(messages))
+	for msg := range messages {
+		msgs = append(msgs, msg)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		if messages[msgs[i]] != messages[msgs[j]] {
+			return messages[msgs[i]] > messages[msgs[j]]
+		}
+		return msgs[i] < msgs[j]
+	})
+	if len(msgs) > n {
+		msgs = msgs[:n]
+	}
+	for i, msg := range msgs {
+		msgs[i] = fmt.Sprintf("%s: %d", msg, messages[msg])
+	}
+	return msgs
+}
+
+// printErrors prints errors by class and the most frequent error messages
+func printErrors() {
+	if client.Errors() == 0 {
+		return
+	}
+	classes := client.ErrorClasses()
+	names := make([]string, 0, len(classes))
+	for class := range classes {
+		names = append(names, class)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if classes[names[i]] != classes[names[j]] {
+			return classes[names[i]] > classes[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, class := range names {
+		parts[i] = fmt.Sprintf("%s: %d", class, classes[class])
+	}
+	fmt.Fprintf(out, "Error classes: %s\n", strings.Join(parts, ", "))
+	fmt.Fprintln(out, "Top errors:")
+	for _, msg := range topErrorMessages(client.ErrorMessages(), topErrors) {
+		fmt.Fprintf(out, " - %s\n", msg)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopErrorMessages(t *testing.T) {
+	f := func(messages map[string]int, n int, expected []string) {
+		t.Helper()
+		if got := topErrorMessages(messages, n); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Unexpected top messages. Got: %q; Expected: %q", got, expected)
+		}
+	}
+
+	f(map[string]int{}, 5, []string{})
+	f(map[string]int{"timeout": 3, "refused": 10, "reset": 3}, 5, []string{"refused: 10", "reset: 3", "timeout: 3"})
+	f(map[string]int{"timeout": 3, "refused": 10, "reset": 3}, 2, []string{"refused: 10", "reset: 3"})
+}
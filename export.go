@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/exporter"
+)
+
+// exportKinds are supported kinds of sinks of -export
+var exportKinds = map[string]bool{"influx": true, "statsd": true, "dogstatsd": true, "otlp": true, "pushgateway": true}
+
+// exportList is a list of sinks like "statsd=localhost:8125" set by repeated flag
+type exportList []string
+
+func (l *exportList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *exportList) Set(s string) error {
+	n := strings.Index(s, "=")
+	if n <= 0 || n == len(s)-1 {
+		return fmt.Errorf("sink %q must be in format \"kind=address\" like \"statsd=localhost:8125\"", s)
+	}
+	if !exportKinds[s[:n]] {
+		return fmt.Errorf("unsupported kind of sink %q; supported kinds are influx, statsd, dogstatsd, otlp and pushgateway", s[:n])
+	}
+	*l = append(*l, s)
+	return nil
+}
+
+var exportFlags exportList
+
+func init() {
+	flag.Var(&exportFlags, "export", "Export every sample to sink like \"influx=http://influx:8086/write?db=load\", \"statsd=localhost:8125\", "+
+		"\"dogstatsd=localhost:8125\", \"otlp=http://collector:4318\" or \"pushgateway=http://localhost:9091\" while test is running. "+
+		"Is set multiple times to export to several sinks")
+}
+
+// exporters fans out samples to sinks of -export. Is nil if samples aren't exported
+var exporters *exporter.Exporter
+
+// runName returns value of run tag of exported samples
+func runName() string {
+	if *influxRunName != "" {
+		return *influxRunName
+	}
+	return testStart.UTC().Format("20060102T150405Z")
+}
+
+// startExport creates sinks of -export. Samples are tagged by run and host of target
+func startExport() {
+	tags := map[string]string{"run": runName(), "target": r.Title}
+	exporters = new(exporter.Exporter)
+	for _, s := range exportFlags {
+		n := strings.Index(s, "=")
+		kind, addr := s[:n], s[n+1:]
+		switch kind {
+		case "influx":
+			exporters.Add(kind, exporter.NewInflux(addr, tags))
+		case "statsd", "dogstatsd":
+			sink, err := exporter.NewStatsD(addr, tags, kind == "dogstatsd")
+			if err != nil {
+				log.Fatalf("cannot export samples to %s: %s", kind, err)
+			}
+			exporters.Add(kind, sink)
+		case "otlp":
+			exporters.Add(kind, exporter.NewOTLP(addr, tags))
+		case "pushgateway":
+			exporters.Add(kind, exporter.NewPushgateway(addr, *jobName, pushgatewayLabels))
+		}
+	}
+}
+
+// exportState exports current sample to sinks of -export
+func exportState() {
+	s := exporter.Sample{
+		Time:         time.Now(),
+		Connections:  client.ConnOpen(),
+		Requests:     client.RequestSum(),
+		Success:      client.RequestSuccess(),
+		Errors:       client.Errors(),
+		Timeouts:     client.Timeouts(),
+		BytesWritten: client.BytesWritten(),
+		BytesRead:    client.BytesRead(),
+		QPSLimit:     qpsLimit(),
+	}
+	if s.Requests >= *minSamples {
+		s.Latency = client.RequestDuration()
+	}
+	exporters.Export(s)
+}
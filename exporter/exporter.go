@@ -0,0 +1,181 @@
+// Package exporter sends samples of running test to monitoring systems like InfluxDB,
+// StatsD, OpenTelemetry collector and Pushgateway, so test could be watched live
+// in observability stack of its team.
+package exporter
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// exportTimeout is a max duration of single export
+	exportTimeout = 5 * time.Second
+
+	// maxBackoff is a max period, during which exports to sink are skipped after failures
+	maxBackoff = time.Minute
+)
+
+// Sample is a snapshot of metrics of test. Counters are cumulative within stage
+// and are reset once new stage starts
+type Sample struct {
+	Time time.Time
+
+	Connections  uint64
+	Requests     uint64
+	Success      uint64
+	Errors       uint64
+	Timeouts     uint64
+	BytesWritten uint64
+	BytesRead    uint64
+
+	// QPSLimit is a current rate limit of requests
+	QPSLimit float64
+
+	// RPS is a rate of requests since previous sample. Is set by Exporter
+	RPS float64
+
+	// Latency maps quantile to latency in seconds. Is nil if there are too few requests
+	// to measure latency, and contains NaN for quantiles without observations
+	Latency map[float64]float64
+}
+
+// field is a named value of sample
+type field struct {
+	name  string
+	value float64
+	// isInt is true for counters and gauges with integer values
+	isInt bool
+}
+
+// fields returns values of s named like "requests" and "latency_p99".
+// Latency is in seconds, quantiles without observations are omitted
+func (s Sample) fields() []field {
+	fs := []field{
+		{name: "connections", value: float64(s.Connections), isInt: true},
+		{name: "requests", value: float64(s.Requests), isInt: true},
+		{name: "request_success", value: float64(s.Success), isInt: true},
+		{name: "errors", value: float64(s.Errors), isInt: true},
+		{name: "timeouts", value: float64(s.Timeouts), isInt: true},
+		{name: "bytes_written", value: float64(s.BytesWritten), isInt: true},
+		{name: "bytes_read", value: float64(s.BytesRead), isInt: true},
+		{name: "qps_limit", value: s.QPSLimit},
+		{name: "rps", value: s.RPS},
+	}
+	qs := make([]float64, 0, len(s.Latency))
+	for q, v := range s.Latency {
+		if !math.IsNaN(v) {
+			qs = append(qs, q)
+		}
+	}
+	sort.Float64s(qs)
+	for _, q := range qs {
+		fs = append(fs, field{name: "latency_p" + strconv.FormatFloat(q*100, 'f', -1, 64), value: s.Latency[q]})
+	}
+	return fs
+}
+
+// Sink sends samples to monitoring system
+type Sink interface {
+	// Export sends s. Isn't called concurrently
+	Export(s Sample) error
+}
+
+// sink is a Sink of Exporter with state of its failures
+type sink struct {
+	Sink
+	name string
+
+	mu sync.Mutex
+	// failures is a number of consecutive failed exports
+	failures int
+	// nextExport is a time before which exports are skipped because of failures
+	nextExport time.Time
+}
+
+// Exporter fans out samples to sinks
+type Exporter struct {
+	sinks []*sink
+	wg    sync.WaitGroup
+
+	// prev is a previous sample, by which rps is calculated
+	prev Sample
+}
+
+// Add adds sink s. Name identifies sink in log messages
+func (e *Exporter) Add(name string, s Sink) {
+	e.sinks = append(e.sinks, &sink{Sink: s, name: name})
+}
+
+// Export sends s to every sink in background, so slow sink doesn't delay samples.
+// Export to sink is skipped if previous one is still in progress. The first of consecutive
+// failures of sink is logged, and following exports to it are skipped for exponentially
+// growing period, so unavailable sink doesn't spam output. Export isn't concurrent
+func (e *Exporter) Export(s Sample) {
+	if dt := s.Time.Sub(e.prev.Time).Seconds(); !e.prev.Time.IsZero() && dt > 0 {
+		prev := e.prev.Requests
+		if s.Requests < prev {
+			// counters were reset by new stage
+			prev = 0
+		}
+		s.RPS = float64(s.Requests-prev) / dt
+	}
+	e.prev = s
+	for _, sk := range e.sinks {
+		if !sk.mu.TryLock() {
+			continue
+		}
+		e.wg.Add(1)
+		go func(sk *sink) {
+			defer e.wg.Done()
+			defer sk.mu.Unlock()
+			sk.export(s)
+		}(sk)
+	}
+}
+
+// Wait waits for exports in progress
+func (e *Exporter) Wait() {
+	e.wg.Wait()
+}
+
+func (sk *sink) export(s Sample) {
+	if time.Now().Before(sk.nextExport) {
+		return
+	}
+	if err := sk.Export(s); err != nil {
+		if sk.failures == 0 {
+			log.Printf("Error while exporting samples to %s: %s; further errors are suppressed until export succeeds", sk.name, err)
+		}
+		sk.failures++
+		backoff := time.Second
+		for i := 1; i < sk.failures && backoff < maxBackoff; i++ {
+			backoff *= 2
+		}
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		sk.nextExport = time.Now().Add(backoff)
+		return
+	}
+	if sk.failures > 0 {
+		log.Printf("Exporting samples to %s recovered after %d failures", sk.name, sk.failures)
+	}
+	sk.failures, sk.nextExport = 0, time.Time{}
+}
+
+// sortedTags returns names of non-empty tags sorted by name
+func sortedTags(tags map[string]string) []string {
+	names := make([]string, 0, len(tags))
+	for k, v := range tags {
+		if v != "" {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
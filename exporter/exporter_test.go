@@ -0,0 +1,63 @@
+package exporter
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testSink struct {
+	samples []Sample
+	err     error
+}
+
+func (ts *testSink) Export(s Sample) error {
+	ts.samples = append(ts.samples, s)
+	return ts.err
+}
+
+func TestExporterExport(t *testing.T) {
+	var e Exporter
+	ok, failing := &testSink{}, &testSink{err: fmt.Errorf("unavailable")}
+	e.Add("ok", ok)
+	e.Add("failing", failing)
+	start := time.Now()
+	f := func(offset time.Duration, requests uint64, expectedRPS float64) {
+		t.Helper()
+		e.Export(Sample{Time: start.Add(offset), Requests: requests})
+		e.Wait()
+		if got := ok.samples[len(ok.samples)-1].RPS; got != expectedRPS {
+			t.Fatalf("Unexpected rps of sample at %s. Got: %f; Expected: %f", offset, got, expectedRPS)
+		}
+	}
+
+	f(0, 0, 0)
+	f(time.Second, 100, 100)
+	f(2*time.Second, 300, 200)
+	// counters were reset by new stage
+	f(4*time.Second, 50, 25)
+
+	if len(ok.samples) != 4 {
+		t.Errorf("Unexpected number of exported samples. Got: %d; Expected: 4", len(ok.samples))
+	}
+	// exports after failure are skipped during backoff
+	if len(failing.samples) != 1 {
+		t.Errorf("Unexpected number of exports to failing sink. Got: %d; Expected: 1", len(failing.samples))
+	}
+}
+
+func TestSampleFields(t *testing.T) {
+	s := Sample{Requests: 10, RPS: 2.5, Latency: map[float64]float64{0.99: 0.2, 0.5: 0.1, 0.9: math.NaN()}}
+	var names []string
+	for _, f := range s.fields() {
+		names = append(names, f.name)
+	}
+	// quantiles without observations are omitted
+	expected := []string{"connections", "requests", "request_success", "errors", "timeouts",
+		"bytes_written", "bytes_read", "qps_limit", "rps", "latency_p50", "latency_p99"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Unexpected fields. Got: %v; Expected: %v", names, expected)
+	}
+}
@@ -0,0 +1,73 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// measurement is a name of measurement of InfluxDB and prefix of names of other sinks
+const measurement = "fasthttploader"
+
+var influxTagEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`)
+
+// Influx writes samples in InfluxDB line protocol to write endpoint
+type Influx struct {
+	url    string
+	prefix string
+}
+
+// NewInflux returns Influx, which writes samples to url like "http://influx:8086/write?db=load"
+// with tags added to every line
+func NewInflux(url string, tags map[string]string) *Influx {
+	prefix := measurement
+	for _, k := range sortedTags(tags) {
+		prefix += "," + influxTagEscaper.Replace(k) + "=" + influxTagEscaper.Replace(tags[k])
+	}
+	return &Influx{url: url, prefix: prefix}
+}
+
+// line returns s in InfluxDB line protocol
+func (i *Influx) line(s Sample) []byte {
+	buf := append([]byte(nil), i.prefix...)
+	for n, f := range s.fields() {
+		if n == 0 {
+			buf = append(buf, ' ')
+		} else {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, f.name...)
+		buf = append(buf, '=')
+		if f.isInt {
+			buf = strconv.AppendUint(buf, uint64(f.value), 10)
+			buf = append(buf, 'i')
+		} else {
+			buf = strconv.AppendFloat(buf, f.value, 'f', -1, 64)
+		}
+	}
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, s.Time.UnixNano(), 10)
+	return append(buf, '\n')
+}
+
+// Export writes s to write endpoint
+func (i *Influx) Export(s Sample) error {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI(i.url)
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.Header.SetContentType("text/plain; charset=utf-8")
+	req.SetBody(i.line(s))
+	if err := fasthttp.DoTimeout(req, resp, exportTimeout); err != nil {
+		return err
+	}
+	// influx responds with 204 No Content on success
+	if sc := resp.StatusCode(); sc < 200 || sc >= 300 {
+		return fmt.Errorf("unexpected status code %d: %s", sc, resp.Body())
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package exporter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInfluxLine(t *testing.T) {
+	i := NewInflux("http://localhost:8086/write?db=load", map[string]string{"target": "my host", "run": "1", "empty": ""})
+	s := Sample{
+		Time:        time.Unix(1, 5),
+		Connections: 3,
+		Requests:    10,
+		QPSLimit:    100,
+		RPS:         2.5,
+		Latency:     map[float64]float64{0.99: 0.25},
+	}
+	expected := `fasthttploader,run=1,target=my\ host connections=3i,requests=10i,request_success=0i,errors=0i,timeouts=0i,` +
+		"bytes_written=0i,bytes_read=0i,qps_limit=100,rps=2.5,latency_p99=0.25 1000000005\n"
+	if got := string(i.line(s)); got != expected {
+		t.Errorf("Unexpected line.\nGot:      %q\nExpected: %q", got, expected)
+	}
+}
@@ -0,0 +1,126 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+)
+
+// OTLP sends samples as gauges to OpenTelemetry collector by OTLP/HTTP with JSON encoding
+type OTLP struct {
+	url        string
+	attributes []otlpAttribute
+}
+
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+// otlpDataPoint is a point of gauge. Int64 values are strings in JSON encoding of OTLP
+type otlpDataPoint struct {
+	TimeUnixNano string   `json:"timeUnixNano"`
+	AsInt        string   `json:"asInt,omitempty"`
+	AsDouble     *float64 `json:"asDouble,omitempty"`
+}
+
+type otlpMetric struct {
+	Name  string `json:"name"`
+	Gauge struct {
+		DataPoints []otlpDataPoint `json:"dataPoints"`
+	} `json:"gauge"`
+}
+
+type otlpScopeMetrics struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+// NewOTLP returns OTLP, which sends samples to collector at url like "http://collector:4318".
+// Path "/v1/metrics" is used if url has no path. Tags are sent as attributes of resource
+// along with service.name "fasthttploader". Gauges are named like "fasthttploader.requests"
+func NewOTLP(url string, tags map[string]string) *OTLP {
+	var u fasthttp.URI
+	u.Update(url)
+	if p := string(u.Path()); p == "" || p == "/" {
+		u.SetPath("/v1/metrics")
+	}
+	o := &OTLP{url: u.String()}
+	o.attributes = append(o.attributes, newOTLPAttribute("service.name", measurement))
+	for _, k := range sortedTags(tags) {
+		o.attributes = append(o.attributes, newOTLPAttribute(k, tags[k]))
+	}
+	return o
+}
+
+func newOTLPAttribute(k, v string) otlpAttribute {
+	a := otlpAttribute{Key: k}
+	a.Value.StringValue = v
+	return a
+}
+
+// body returns s as JSON encoded export request
+func (o *OTLP) body(s Sample) ([]byte, error) {
+	ts := strconv.FormatInt(s.Time.UnixNano(), 10)
+	var metrics []otlpMetric
+	for _, f := range s.fields() {
+		m := otlpMetric{Name: measurement + "." + f.name}
+		p := otlpDataPoint{TimeUnixNano: ts}
+		if f.isInt {
+			p.AsInt = strconv.FormatUint(uint64(f.value), 10)
+		} else {
+			v := f.value
+			p.AsDouble = &v
+		}
+		m.Gauge.DataPoints = []otlpDataPoint{p}
+		metrics = append(metrics, m)
+	}
+	var sm otlpScopeMetrics
+	sm.Scope.Name = measurement
+	sm.Metrics = metrics
+	var rm otlpResourceMetrics
+	rm.Resource.Attributes = o.attributes
+	rm.ScopeMetrics = []otlpScopeMetrics{sm}
+	req := otlpRequest{ResourceMetrics: []otlpResourceMetrics{rm}}
+	return json.Marshal(req)
+}
+
+// Export sends s to collector
+func (o *OTLP) Export(s Sample) error {
+	body, err := o.body(s)
+	if err != nil {
+		return err
+	}
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI(o.url)
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.Header.SetContentType("application/json")
+	req.SetBody(body)
+	if err := fasthttp.DoTimeout(req, resp, exportTimeout); err != nil {
+		return err
+	}
+	if sc := resp.StatusCode(); sc < 200 || sc >= 300 {
+		return fmt.Errorf("unexpected status code %d: %s", sc, resp.Body())
+	}
+	return nil
+}
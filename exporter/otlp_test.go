@@ -0,0 +1,44 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOTLPExport(t *testing.T) {
+	var got otlpRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/metrics" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("Cannot parse request: %s", err)
+		}
+	}))
+	defer srv.Close()
+
+	o := NewOTLP(srv.URL, map[string]string{"run": "1"})
+	if err := o.Export(Sample{Time: time.Unix(1, 0), Requests: 10, RPS: 2.5}); err != nil {
+		t.Fatalf("Cannot export sample: %s", err)
+	}
+	if len(got.ResourceMetrics) != 1 || len(got.ResourceMetrics[0].ScopeMetrics) != 1 {
+		t.Fatalf("Unexpected structure of request: %+v", got)
+	}
+	rm := got.ResourceMetrics[0]
+	if attrs := rm.Resource.Attributes; len(attrs) != 2 || attrs[0].Value.StringValue != "fasthttploader" || attrs[1].Key != "run" {
+		t.Errorf("Unexpected attributes of resource: %+v", attrs)
+	}
+	metrics := rm.ScopeMetrics[0].Metrics
+	requests, rps := metrics[1].Gauge.DataPoints[0], metrics[8].Gauge.DataPoints[0]
+	if metrics[1].Name != "fasthttploader.requests" || requests.AsInt != "10" || requests.TimeUnixNano != "1000000000" {
+		t.Errorf("Unexpected gauge of requests: %s %+v", metrics[1].Name, requests)
+	}
+	if metrics[8].Name != "fasthttploader.rps" || rps.AsDouble == nil || *rps.AsDouble != 2.5 {
+		t.Errorf("Unexpected gauge of rps: %s %+v", metrics[8].Name, rps)
+	}
+}
@@ -0,0 +1,31 @@
+package exporter
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Pushgateway pushes all registered metrics to Pushgateway on every sample,
+// so values of sample itself aren't used
+type Pushgateway struct {
+	pusher *push.Pusher
+}
+
+// NewPushgateway returns Pushgateway, which replaces metrics of group of job and labels
+// at Pushgateway at addr like "http://localhost:9091"
+func NewPushgateway(addr, job string, labels map[string]string) *Pushgateway {
+	pusher := push.New(addr, job).
+		Gatherer(prometheus.DefaultGatherer).
+		Client(&http.Client{Timeout: exportTimeout})
+	for k, v := range labels {
+		pusher = pusher.Grouping(k, v)
+	}
+	return &Pushgateway{pusher: pusher}
+}
+
+// Export pushes current values of registered metrics
+func (p *Pushgateway) Export(Sample) error {
+	return p.pusher.Push()
+}
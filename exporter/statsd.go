@@ -0,0 +1,58 @@
+package exporter
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// StatsD sends samples as gauges to StatsD or DogStatsD agent over UDP
+type StatsD struct {
+	conn net.Conn
+	// suffix contains DogStatsD tags of every gauge. Is empty for plain StatsD
+	suffix string
+}
+
+// NewStatsD returns StatsD, which sends samples to agent at addr like "localhost:8125".
+// Gauges are named like "fasthttploader.requests". If dogstatsd is true, tags are added
+// to every gauge in DogStatsD format, since plain StatsD doesn't support tags
+func NewStatsD(addr string, tags map[string]string, dogstatsd bool) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &StatsD{conn: conn}
+	if dogstatsd {
+		var pairs []string
+		for _, k := range sortedTags(tags) {
+			pairs = append(pairs, k+":"+tags[k])
+		}
+		if len(pairs) > 0 {
+			s.suffix = "|#" + strings.Join(pairs, ",")
+		}
+	}
+	return s, nil
+}
+
+// packet returns gauges of s separated by newline
+func (sd *StatsD) packet(s Sample) []byte {
+	var buf []byte
+	for i, f := range s.fields() {
+		if i > 0 {
+			buf = append(buf, '\n')
+		}
+		buf = append(buf, measurement+"."...)
+		buf = append(buf, f.name...)
+		buf = append(buf, ':')
+		buf = strconv.AppendFloat(buf, f.value, 'f', -1, 64)
+		buf = append(buf, "|g"...)
+		buf = append(buf, sd.suffix...)
+	}
+	return buf
+}
+
+// Export sends gauges of s in single packet
+func (sd *StatsD) Export(s Sample) error {
+	_, err := sd.conn.Write(sd.packet(s))
+	return err
+}
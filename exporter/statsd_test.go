@@ -0,0 +1,42 @@
+package exporter
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsDExport(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	defer pc.Close()
+	f := func(dogstatsd bool, expectedLine string) {
+		t.Helper()
+		sd, err := NewStatsD(pc.LocalAddr().String(), map[string]string{"run": "1", "target": "host"}, dogstatsd)
+		if err != nil {
+			t.Fatalf("Cannot create StatsD: %s", err)
+		}
+		if err := sd.Export(Sample{Requests: 10, RPS: 2.5}); err != nil {
+			t.Fatalf("Cannot export sample: %s", err)
+		}
+		buf := make([]byte, 2048)
+		pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Cannot read packet: %s", err)
+		}
+		lines := strings.Split(string(buf[:n]), "\n")
+		if len(lines) != 9 {
+			t.Fatalf("Unexpected number of gauges. Got: %d; Expected: 9", len(lines))
+		}
+		if lines[1] != expectedLine {
+			t.Errorf("Unexpected gauge. Got: %q; Expected: %q", lines[1], expectedLine)
+		}
+	}
+
+	f(false, "fasthttploader.requests:10|g")
+	f(true, "fasthttploader.requests:10|g|#run:1,target:host")
+}
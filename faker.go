@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+var (
+	firstNames = []string{
+		"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda",
+		"William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica",
+		"Thomas", "Sarah", "Charles", "Karen", "Daniel", "Nancy", "Matthew", "Lisa",
+	}
+	lastNames = []string{
+		"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+		"Rodriguez", "Martinez", "Hernandez", "Lopez", "Wilson", "Anderson", "Taylor", "Thomas",
+		"Moore", "Jackson", "Martin", "Lee", "Thompson", "White", "Harris", "Clark",
+	}
+	emailDomains = []string{"example.com", "example.org", "example.net", "mail.test"}
+	loremWords   = strings.Fields("lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor " +
+		"incididunt ut labore et dolore magna aliqua enim ad minim veniam quis nostrud exercitation ullamco " +
+		"laboris nisi aliquip ex ea commodo consequat duis aute irure in reprehenderit voluptate velit esse " +
+		"cillum fugiat nulla pariatur excepteur sint occaecat cupidatat non proident sunt culpa qui officia " +
+		"deserunt mollit anim id est laborum")
+)
+
+// dateRange is a period before now, in which dates are generated
+const dateRange = 10 * 365 * 24 * time.Hour
+
+// lastSeq is the last number returned by seq. It is shared by all workers, so numbers are unique within test
+var lastSeq uint64
+
+// faker generates realistic-looking random data for request templates.
+// Is not thread-safe, so every worker has its own faker
+type faker struct {
+	rnd *rand.Rand
+	now time.Time
+}
+
+func newFaker(seed int64) *faker {
+	return &faker{
+		rnd: rand.New(rand.NewSource(seed)),
+		now: time.Now(),
+	}
+}
+
+func (f *faker) funcs() template.FuncMap {
+	return template.FuncMap{
+		"name":  f.name,
+		"email": f.email,
+		"ipv4":  f.ipv4,
+		"date":  f.date,
+		"lorem": f.lorem,
+
+		"uuid":      f.uuid,
+		"randint":   f.randint,
+		"seq":       seq,
+		"timestamp": timestamp,
+		"file":      fileLines,
+		"randline":  f.randline,
+	}
+}
+
+func (f *faker) pick(sl []string) string {
+	return sl[f.rnd.Intn(len(sl))]
+}
+
+// name returns full name like "Mary Smith"
+func (f *faker) name() string {
+	return f.pick(firstNames) + " " + f.pick(lastNames)
+}
+
+// email returns address like "mary.smith42@example.com"
+func (f *faker) email() string {
+	return strings.ToLower(f.pick(firstNames)+"."+f.pick(lastNames)) +
+		strconv.Itoa(f.rnd.Intn(100)) + "@" + f.pick(emailDomains)
+}
+
+// ipv4 returns address like "10.12.0.254"
+func (f *faker) ipv4() string {
+	b := make([]byte, 0, 15)
+	for i := 0; i < 4; i++ {
+		if i > 0 {
+			b = append(b, '.')
+		}
+		b = strconv.AppendInt(b, int64(f.rnd.Intn(256)), 10)
+	}
+	return string(b)
+}
+
+// date returns a date in format "2006-01-02" from last ten years
+func (f *faker) date() string {
+	d := time.Duration(f.rnd.Int63n(int64(dateRange)))
+	return f.now.Add(-d).Format("2006-01-02")
+}
+
+// lorem returns text of n lorem ipsum words
+func (f *faker) lorem(n int) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("number of lorem words can't be negative: %d", n)
+	}
+	words := make([]string, n)
+	for i := range words {
+		words[i] = f.pick(loremWords)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// uuid returns random UUID of version 4 like "3b241101-e2bb-4255-8caf-4136c566a962"
+func (f *faker) uuid() string {
+	var b [16]byte
+	binary.LittleEndian.PutUint64(b[:8], f.rnd.Uint64())
+	binary.LittleEndian.PutUint64(b[8:], f.rnd.Uint64())
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	var dst [36]byte
+	hex.Encode(dst[:8], b[:4])
+	hex.Encode(dst[9:13], b[4:6])
+	hex.Encode(dst[14:18], b[6:8])
+	hex.Encode(dst[19:23], b[8:10])
+	hex.Encode(dst[24:], b[10:])
+	dst[8], dst[13], dst[18], dst[23] = '-', '-', '-', '-'
+	return string(dst[:])
+}
+
+// randint returns random non-negative int without arguments,
+// int in range [0..max] with single argument and in range [min..max] with two ones
+func (f *faker) randint(args ...int) (int, error) {
+	min, max := 0, 0
+	switch len(args) {
+	case 0:
+		return f.rnd.Int(), nil
+	case 1:
+		max = args[0]
+	case 2:
+		min, max = args[0], args[1]
+	default:
+		return 0, fmt.Errorf("randint accepts up to 2 arguments; got %d", len(args))
+	}
+	if min > max {
+		return 0, fmt.Errorf("min %d of randint can't be bigger than max %d", min, max)
+	}
+	n := max - min + 1
+	if n <= 0 {
+		return 0, fmt.Errorf("range [%d..%d] of randint is too wide", min, max)
+	}
+	return min + f.rnd.Intn(n), nil
+}
+
+// randline returns random line of lines, which are usually read by file
+func (f *faker) randline(lines []string) (string, error) {
+	if len(lines) == 0 {
+		return "", fmt.Errorf("randline requires at least one line")
+	}
+	return f.pick(lines), nil
+}
+
+// timestamp returns current unix time in seconds
+func timestamp() int64 {
+	return time.Now().Unix()
+}
+
+// seq returns number of rendered request starting from 1
+func seq() uint64 {
+	return atomic.AddUint64(&lastSeq, 1)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestFakerUUID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	f := newFaker(1)
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := f.uuid()
+		if !re.MatchString(id) {
+			t.Fatalf("Unexpected format of UUID %q", id)
+		}
+		if seen[id] {
+			t.Fatalf("Duplicate UUID %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestFakerRandint(t *testing.T) {
+	f := newFaker(1)
+	for i := 0; i < 1000; i++ {
+		if n, err := f.randint(5, 7); err != nil || n < 5 || n > 7 {
+			t.Fatalf("Unexpected result of randint 5 7: %d, %v", n, err)
+		}
+		if n, err := f.randint(3); err != nil || n < 0 || n > 3 {
+			t.Fatalf("Unexpected result of randint 3: %d, %v", n, err)
+		}
+	}
+	for _, args := range [][]int{{7, 5}, {-1}, {1, 2, 3}} {
+		if _, err := f.randint(args...); err == nil {
+			t.Errorf("Expected error for randint %v", args)
+		}
+	}
+}
+
+func TestFileLines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ids.txt")
+	if err := os.WriteFile(path, []byte("1\r\n\n2\n3"), 0644); err != nil {
+		t.Fatalf("cannot write file: %s", err)
+	}
+	lines, err := fileLines(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(lines) != 3 || lines[0] != "1" || lines[1] != "2" || lines[2] != "3" {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+	f := newFaker(1)
+	for i := 0; i < 100; i++ {
+		if line, err := f.randline(lines); err != nil || (line != "1" && line != "2" && line != "3") {
+			t.Fatalf("unexpected result of randline: %q, %v", line, err)
+		}
+	}
+
+	empty := filepath.Join(dir, "empty.txt")
+	if err := os.WriteFile(empty, []byte("\n"), 0644); err != nil {
+		t.Fatalf("cannot write file: %s", err)
+	}
+	for _, p := range []string{empty, filepath.Join(dir, "missing.txt")} {
+		if _, err := fileLines(p); err == nil {
+			t.Fatalf("expected error for %q", p)
+		}
+	}
+}
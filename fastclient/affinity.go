@@ -0,0 +1,31 @@
+package fastclient
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// observeBackend counts request served by backend with given id.
+// prev is an id of backend which served previous request of worker.
+// Request is counted as affinity break if it was served by another backend.
+// Returns id of backend
+func (c *Client) observeBackend(prev string, id []byte) string {
+	name := string(id)
+	if name == "" {
+		name = "MISSING"
+	}
+	c.withBackend(name).Inc()
+	if prev != "" && prev != name {
+		metrics.Load().affinityBreaks.Inc()
+	}
+	return name
+}
+
+func (c *Client) withBackend(name string) prometheus.Counter {
+	var label prometheus.Labels
+	var ok bool
+	c.Lock()
+	if label, ok = c.backendLabels[name]; !ok {
+		label = prometheus.Labels{"backend": name}
+		c.backendLabels[name] = label
+	}
+	c.Unlock()
+	return metrics.Load().backends.With(label)
+}
@@ -0,0 +1,59 @@
+package fastclient
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// serveBackends runs server which responds with id of one of n backends in round-robin
+func serveBackends(t *testing.T, n int) net.Listener {
+	ln := listenTest(t)
+	var requests uint32
+	go http.Serve(ln, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := (atomic.AddUint32(&requests, 1) - 1) % uint32(n)
+		w.Header().Set("X-Backend", fmt.Sprintf("b%d", id))
+	}))
+	return ln
+}
+
+func TestClientBackends(t *testing.T) {
+	f := func(backends int, requests, breaks uint64, share float64) {
+		t.Helper()
+		ln := serveBackends(t, backends)
+		defer ln.Close()
+
+		req := new(fasthttp.Request)
+		req.SetRequestURI("http://" + ln.Addr().String() + "/")
+		c := New(req, time.Second, fasthttp.StatusOK)
+		c.BackendHeader = "X-Backend"
+		t.Cleanup(c.Flush)
+		c.RunWorkers(1)
+		for i := uint64(0); i < requests; i++ {
+			c.Jobsch <- time.Now()
+		}
+
+		waitRequests(t, c, requests)
+
+		if n := c.AffinityBreaks(); n != breaks {
+			t.Errorf("Unexpected number of affinity breaks. Got: %d; Expected: %d", n, breaks)
+		}
+		got := c.Backends()
+		if len(got) != backends {
+			t.Fatalf("Unexpected backends. Got: %v; Expected %d backends", got, backends)
+		}
+		for name, v := range got {
+			if v != share {
+				t.Errorf("Unexpected share of backend %s. Got: %.2f; Expected: %.2f", name, v, share)
+			}
+		}
+	}
+
+	f(1, 4, 0, 100)
+	f(2, 4, 3, 50)
+}
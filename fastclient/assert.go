@@ -0,0 +1,46 @@
+package fastclient
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+// Assertion is a check of content of complete response
+type Assertion struct {
+	// Name identifies assertion in error messages and AssertionFailures
+	Name string
+
+	// Check returns false if resp fails assertion
+	Check func(resp *fasthttp.Response) bool
+}
+
+// observeAssertions counts every assertion failed by resp.
+// Response failed any of them is counted as error once, by message of the first failed assertion,
+// which is returned. Returns empty string if resp passed all assertions
+func (c *Client) observeAssertions(resp *fasthttp.Response) string {
+	var failure string
+	for _, a := range c.Assertions {
+		if a.Check(resp) {
+			continue
+		}
+		metrics.Load().assertFailures.With(prometheus.Labels{"assertion": a.Name}).Inc()
+		if failure == "" {
+			failure = "assertion failed: " + a.Name
+			c.countError(ErrorClassResponse, failure)
+		}
+	}
+	return failure
+}
+
+// AssertionFailures returns map assertion:value for assertFailures-metric where value
+// is a number of responses failed assertion. Contains every assertion of Client.Assertions
+func (c *Client) AssertionFailures() map[string]uint64 {
+	result := make(map[string]uint64)
+	for _, a := range c.Assertions {
+		m := &dto.Metric{}
+		metrics.Load().assertFailures.With(prometheus.Labels{"assertion": a.Name}).Write(m)
+		result[a.Name] = uint64(*m.Counter.Value)
+	}
+	return result
+}
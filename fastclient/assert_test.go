@@ -0,0 +1,52 @@
+package fastclient
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientAssertionFailures(t *testing.T) {
+	// every second response misses body, every fourth one fails with status code
+	var requests uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddUint32(&requests, 1) % 4 {
+		case 0:
+			w.WriteHeader(http.StatusInternalServerError)
+		case 2:
+		default:
+			w.Write([]byte("ok"))
+		}
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.Assertions = []Assertion{
+		{Name: "body", Check: func(resp *fasthttp.Response) bool { return bytes.Equal(resp.Body(), []byte("ok")) }},
+		{Name: "always", Check: func(*fasthttp.Response) bool { return true }},
+	}
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 8; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 8)
+
+	// responses with unexpected status code aren't asserted
+	expected := map[string]uint64{"body": 2, "always": 0}
+	if got := c.AssertionFailures(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected assertion failures. Got: %v; Expected: %v", got, expected)
+	}
+	if n := c.RequestSuccess(); n != 4 {
+		t.Errorf("Unexpected number of successful requests. Got: %d; Expected: 4", n)
+	}
+	if n := c.Errors(); n != 2 {
+		t.Errorf("Unexpected number of errors. Got: %d; Expected: 2", n)
+	}
+}
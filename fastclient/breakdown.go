@@ -0,0 +1,121 @@
+package fastclient
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+// Stages of latency breakdown
+const (
+	StageDNS       = "dns lookup"
+	StageConnect   = "tcp connect"
+	StageHandshake = "tls handshake"
+	// StageSend is a time from start of request to its last byte written, including wait for connection
+	// and setup of new one
+	StageSend = "sending request"
+	// StageFirstByte is a time from the last byte of request written to the first byte of response read
+	StageFirstByte = "time to first byte"
+	// StageTransfer is a time from the first byte of response read to the complete response
+	StageTransfer = "body transfer"
+)
+
+// BreakdownStages are stages of latency breakdown in order of request
+var BreakdownStages = []string{StageDNS, StageConnect, StageHandshake, StageSend, StageFirstByte, StageTransfer}
+
+// maxDNSDuration, maxSendDuration, maxFirstByteDuration and maxTransferDuration contain bits
+// of max time in seconds of stages of latency breakdown
+var maxDNSDuration, maxSendDuration, maxFirstByteDuration, maxTransferDuration uint64
+
+// connTiming contains times in unix nanoseconds, at which the last request over connection
+// was written and the first byte of its response was read
+type connTiming struct {
+	written   int64
+	firstByte int64
+}
+
+// addConnTiming returns timing of connection with local address addr, so its requests are broken down.
+// Timings aren't removed once connections are closed, since response may be observed after that,
+// e.g. without keepalive. They are replaced by new connections with the same address,
+// so their number is limited by number of local ports
+func (c *Client) addConnTiming(addr string) *connTiming {
+	t := &connTiming{}
+	c.Lock()
+	c.connTimings[addr] = t
+	c.Unlock()
+	return t
+}
+
+// observeBreakdown measures stages of request started at s, which got complete resp
+func (c *Client) observeBreakdown(resp *fasthttp.Response, s time.Time) {
+	addr := resp.LocalAddr()
+	if addr == nil {
+		return
+	}
+	c.Lock()
+	t, ok := c.connTimings[addr.String()]
+	c.Unlock()
+	if !ok {
+		return
+	}
+	written, firstByte := atomic.LoadInt64(&t.written), atomic.LoadInt64(&t.firstByte)
+	if written < s.UnixNano() || firstByte < written {
+		return
+	}
+	now := time.Now().UnixNano()
+	m := metrics.Load()
+	observeStage(m.sendDuration, &maxSendDuration, written-s.UnixNano())
+	observeStage(m.firstByteDuration, &maxFirstByteDuration, firstByte-written)
+	observeStage(m.transferDuration, &maxTransferDuration, now-firstByte)
+}
+
+// observeStage observes d nanoseconds of stage by s, updating its max
+func observeStage(s prometheus.Summary, max *uint64, d int64) {
+	v := time.Duration(d).Seconds()
+	s.Observe(v)
+	observeMax(max, v)
+}
+
+// stageSummary is a summary of stage of latency breakdown with its max
+type stageSummary struct {
+	s   prometheus.Summary
+	max *uint64
+}
+
+// stageSummaries returns summary of every stage of latency breakdown by stage
+func stageSummaries() map[string]stageSummary {
+	m := metrics.Load()
+	return map[string]stageSummary{
+		StageDNS:       {m.dnsDuration, &maxDNSDuration},
+		StageConnect:   {m.connectDuration, &maxConnectDuration},
+		StageHandshake: {m.handshakeDuration, &maxHandshakeDuration},
+		StageSend:      {m.sendDuration, &maxSendDuration},
+		StageFirstByte: {m.firstByteDuration, &maxFirstByteDuration},
+		StageTransfer:  {m.transferDuration, &maxTransferDuration},
+	}
+}
+
+// StageStat contains number of observations of stage of latency breakdown,
+// their total time in seconds and their latency
+type StageStat struct {
+	Count   uint64
+	Sum     float64
+	Latency Latency
+}
+
+// Breakdown returns stats of every stage of latency breakdown by stage.
+// Stages without observations are omitted
+func (*Client) Breakdown() map[string]StageStat {
+	result := make(map[string]StageStat)
+	for stage, v := range stageSummaries() {
+		m := &dto.Metric{}
+		v.s.Write(m)
+		if n := m.Summary.GetSampleCount(); n > 0 {
+			result[stage] = StageStat{Count: n, Sum: m.Summary.GetSampleSum(), Latency: latencyOf(v.s, v.max)}
+		}
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package fastclient
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientLatencyBreakdown(t *testing.T) {
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// headers are sent after think time, and body is sent after transfer delay
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("head"))
+		w.(http.Flusher).Flush()
+		time.Sleep(30 * time.Millisecond)
+		w.Write([]byte("tail"))
+	})
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://localhost:" + port + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.LatencyBreakdown = true
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	const requests = 3
+	for i := 0; i < requests; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, requests)
+
+	stages := c.Breakdown()
+	if n := stages[StageDNS].Count; n != 1 {
+		t.Errorf("Unexpected number of DNS lookups. Got: %d; Expected: 1", n)
+	}
+	for _, stage := range []string{StageSend, StageFirstByte, StageTransfer} {
+		if n := stages[stage].Count; n != requests {
+			t.Errorf("Unexpected number of observations of %s. Got: %d; Expected: %d", stage, n, requests)
+		}
+	}
+	if d := stages[StageFirstByte].Latency.P50; d < 0.05 {
+		t.Errorf("Unexpected time to first byte. Got: %fs; Expected at least 0.05s", d)
+	}
+	if d := stages[StageTransfer].Latency.P50; d < 0.03 || d > 0.05 {
+		t.Errorf("Unexpected body transfer. Got: %fs; Expected about 0.03s", d)
+	}
+	if _, ok := stages[StageHandshake]; ok {
+		t.Errorf("Unexpected TLS handshakes of http target")
+	}
+}
+
+func TestClientResolve(t *testing.T) {
+	f := func(c *Client, addr string, expected string) {
+		t.Helper()
+		got, err := c.resolve(addr)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", addr, err)
+		}
+		if got != expected {
+			t.Errorf("Unexpected address of %q. Got: %q; Expected: %q", addr, got, expected)
+		}
+	}
+
+	c := &Client{LatencyBreakdown: true}
+	f(c, "10.0.0.1:80", "10.0.0.1:80")
+	f(c, "[::1]:443", "[::1]:443")
+	f(&Client{}, "localhost:80", "localhost:80")
+	f(&Client{LatencyBreakdown: true, ProxyAddr: "proxy:3128"}, "proxy:3128", "proxy:3128")
+}
@@ -0,0 +1,66 @@
+package fastclient
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// maxCapturedBody is a max size of body of captured request or response.
+// Longer bodies are truncated
+const maxCapturedBody = 4 << 10
+
+// Exchange is a captured failed request along with its response
+type Exchange struct {
+	Time time.Time
+
+	// Failure is an error message or reason why response isn't successful like "status code 503"
+	Failure string
+
+	// Request contains request line, headers and truncated body of request
+	Request string
+
+	// Response contains status line, headers and truncated body of response.
+	// Is empty if response wasn't received completely
+	Response string
+}
+
+// capture captures failed req and its resp until CaptureErrors exchanges are captured.
+// resp is nil if complete response wasn't received
+func (c *Client) capture(req *fasthttp.Request, resp *fasthttp.Response, failure string) {
+	if c.capturedFull.Load() {
+		return
+	}
+	c.capturedMu.Lock()
+	defer c.capturedMu.Unlock()
+	if len(c.captured) >= c.CaptureErrors {
+		// further failures don't contend for lock
+		c.capturedFull.Store(true)
+		return
+	}
+	e := Exchange{
+		Time:    time.Now(),
+		Failure: failure,
+		Request: req.Header.String() + truncatedBody(req.Body()),
+	}
+	if resp != nil {
+		e.Response = resp.Header.String() + truncatedBody(resp.Body())
+	}
+	c.captured = append(c.captured, e)
+}
+
+// truncatedBody returns body truncated to maxCapturedBody with note of number of truncated bytes
+func truncatedBody(body []byte) string {
+	if len(body) <= maxCapturedBody {
+		return string(body)
+	}
+	return fmt.Sprintf("%s\n... %d bytes truncated", body[:maxCapturedBody], len(body)-maxCapturedBody)
+}
+
+// Captured returns exchanges captured so far. Is empty unless CaptureErrors is set
+func (c *Client) Captured() []Exchange {
+	c.capturedMu.Lock()
+	defer c.capturedMu.Unlock()
+	return append([]Exchange(nil), c.captured...)
+}
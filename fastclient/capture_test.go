@@ -0,0 +1,68 @@
+package fastclient
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientCaptured(t *testing.T) {
+	// every second response fails with status code and long body
+	var requests uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddUint32(&requests, 1)%2 == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(strings.Repeat("x", maxCapturedBody+10)))
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/path")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.CaptureErrors = 2
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 8; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 8)
+
+	captured := c.Captured()
+	if len(captured) != 2 {
+		t.Fatalf("Unexpected number of captured requests. Got: %d; Expected: 2", len(captured))
+	}
+	for _, e := range captured {
+		if e.Failure != "status code 503" {
+			t.Errorf("Unexpected failure. Got: %q; Expected: %q", e.Failure, "status code 503")
+		}
+		if !strings.HasPrefix(e.Request, "GET /path HTTP/1.1\r\n") {
+			t.Errorf("Unexpected request: %q", e.Request)
+		}
+		if !strings.HasPrefix(e.Response, "HTTP/1.1 503 Service Unavailable\r\n") {
+			t.Errorf("Unexpected response: %q", e.Response)
+		}
+		if !strings.HasSuffix(e.Response, "\n... 10 bytes truncated") {
+			t.Errorf("Body of response isn't truncated: %q", e.Response[len(e.Response)-40:])
+		}
+	}
+}
+
+func TestTruncatedBody(t *testing.T) {
+	f := func(body, expected string) {
+		t.Helper()
+		if got := truncatedBody([]byte(body)); got != expected {
+			t.Errorf("Unexpected body. Got: %q; Expected: %q", got, expected)
+		}
+	}
+	f("", "")
+	f("abc", "abc")
+	long := strings.Repeat("a", maxCapturedBody)
+	f(long, long)
+	f(long+"bc", long+"\n... 2 bytes truncated")
+}
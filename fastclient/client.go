@@ -1,14 +1,20 @@
 package fastclient
 
 import (
+	"crypto/tls"
 	"flag"
+	"fmt"
 	"io"
 	"log"
+	"log/slog"
+	"math/rand"
+	randv2 "math/rand/v2"
 	"net"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -16,7 +22,7 @@ import (
 )
 
 var (
-	httpClientRequestTimeout  = flag.Duration("httpClientRequestTimeout", time.Second*10, "Maximum time to wait for http response")
+	httpClientRequestTimeout  = flag.Duration("httpClientRequestTimeout", time.Second*10, "Deprecated: use -dialTimeout. Maximum time to establish connection")
 	httpClientKeepAlivePeriod = flag.Duration("httpClientKeepAlivePeriod", time.Second*5, "Interval for sending keep-alive messages"+
 		"on keepalive connections. Zero disables keep-alive messages")
 	httpClientReadBufferSize  = flag.Int("httpClientReadBufferSize", 8*1024, "Per-connection read buffer size for httpclient")
@@ -30,14 +36,244 @@ const (
 )
 
 func init() {
-	registerMetrics()
+	flushMetrics()
 }
 
+// Modifier changes request before it would be sent. Request, which Modifier failed to change,
+// isn't sent and is counted as error of ErrorClassModifier
+type Modifier func(req *fasthttp.Request) error
+
 // Client is a wrapper for fasthttp.HostClient
 // It allows to send requests and collect metrics while sending
 type Client struct {
-	// Jobsch is a channel of tasks(requests) which should be done
-	Jobsch chan struct{}
+	// Jobsch is a channel of tasks(requests) which should be done.
+	// Every task is a time when it was queued
+	Jobsch chan time.Time
+
+	// QueueSize is a capacity of Jobsch, which is applied by the first RunWorkers.
+	// Zero means 10000 jobs
+	QueueSize int
+
+	// ArrivalRate, if true, makes Enqueue start new worker for job, which would wait for busy workers otherwise.
+	// So requests are sent on schedule of jobs regardless of responses (open-loop load)
+	ArrivalRate bool
+
+	// ClosedLoop, if true, makes workers send requests one after another without jobs of Jobsch
+	// until Flush or StopWorkers, so load is limited only by number of workers (closed-loop load)
+	ClosedLoop bool
+
+	// NewModifier, if set, is called once for every worker by RunWorkers to acquire Modifier
+	// which would be applied to each request of this worker. Workers acquire modifiers
+	// in order they are started, and returned Modifier is never called concurrently
+	NewModifier func() Modifier
+
+	// NewGenerator, if set, is called once by every worker to acquire RequestGenerator,
+	// which fills each request of this worker after Modifier. Requests failed to be generated
+	// aren't sent and are counted as failed requests with errors of ErrorClassGenerator
+	NewGenerator func() RequestGenerator
+
+	// StartJitter is a window over which start of workers is spread randomly,
+	// so they don't establish connections all at once. Zero means no jitter
+	StartJitter time.Duration
+
+	// Seed is a seed of random values of workers and connections: start jitter, weighted targets,
+	// retries, think time, regions and injected drops. Tests with the same Seed take the same values,
+	// though order, in which workers take jobs, still depends on scheduling of goroutines
+	Seed int64
+
+	// WebSocket, if true, makes every worker keep WebSocket connection to target and send body
+	// of request as message over it every 1/WSRate seconds instead of HTTP requests.
+	// Latency of request is time until the next message of target
+	WebSocket bool
+	// WSRate is a rate of messages of every WebSocket connection per second
+	WSRate float64
+
+	// ThinkTime is a pause of every worker after response before its next request,
+	// which simulates users instead of back-to-back requests. Zero means no pause
+	ThinkTime time.Duration
+	// ThinkTimeJitter is a max deviation of ThinkTime as its share in range [0..1].
+	// Every pause is chosen randomly within ThinkTime*(1±ThinkTimeJitter)
+	ThinkTimeJitter float64
+
+	// WarmupRequests is a number of throwaway requests which are sent
+	// over every new connection before it is used for measured requests
+	WarmupRequests int
+
+	// IncludeQueueWait, if true, adds time which request waited in Jobsch to its latency,
+	// so latency is measured from client perspective instead of server one
+	IncludeQueueWait bool
+
+	// RecordCorrected, if true, measures latency of every request from time sent to Jobsch
+	// as well, which is expected to be its intended send time by schedule of load.
+	// So latency isn't underestimated because of coordinated omission, when target stalls
+	// and requests due during stall are sent late
+	RecordCorrected bool
+
+	// ProxyAddr is an address of HTTP proxy like "host:port". Connections to target
+	// are tunneled via CONNECT requests to proxy. Empty value means direct connections
+	ProxyAddr string
+
+	// UnixSocket, if set, is a path of Unix domain socket, which every connection is dialed to
+	// instead of address of target. Host header of requests isn't changed
+	UnixSocket string
+
+	// ProxySOCKS5, if true, means ProxyAddr is an address of SOCKS5 proxy,
+	// so connections are tunneled via its CONNECT command
+	ProxySOCKS5 bool
+
+	// ProxyUser and ProxyPassword are credentials of SOCKS5 proxy.
+	// Username/password authentication isn't offered if ProxyUser is empty
+	ProxyUser, ProxyPassword string
+
+	// TLSConfig, if set, is a base of TLS configuration of connections to target.
+	// ServerName defaults to host of target
+	TLSConfig *tls.Config
+
+	// DialAddrs are resolved addresses of target like "10.0.0.1:80". If set, connections
+	// are established to them in round-robin instead of resolving address of target
+	DialAddrs []string
+
+	// Resolver, if set, resolves hosts of new connections instead of system resolver, e.g. via custom DNS server
+	Resolver *net.Resolver
+	// ResolveInterval, if positive, is a period during which resolved IPs of host are reused by new connections.
+	// Host is resolved again once it passes, so long tests follow DNS failovers.
+	// If zero, host is resolved on every new connection if hosts are resolved by Client
+	ResolveInterval time.Duration
+	// StaticHosts maps addresses like "example.com:443" to addresses like "10.0.0.1:443",
+	// to which connections are established without DNS lookup
+	StaticHosts map[string]string
+
+	// LocalAddrs, if set, are source IPs, to which connections are bound in round-robin,
+	// so every IP has its own range of ephemeral ports
+	LocalAddrs []net.IP
+
+	// ProxyAuthorization is a value of Proxy-Authorization header of CONNECT requests
+	ProxyAuthorization string
+
+	// InjectLatency is an artificial delay which is added to every request before it is written
+	// to connection. It is injected by client and isn't caused by target
+	InjectLatency time.Duration
+
+	// InjectDrop is a fraction of requests which are failed by client with ErrInjectedDrop
+	// instead of being written to connection. Connections of dropped requests are closed
+	InjectDrop float64
+
+	// UploadRate is a max rate of writing of requests in bytes per second, so slow uploaders are simulated.
+	// Zero means no limit
+	UploadRate float64
+
+	// RetryPolicy defines which responses are sent again by status code.
+	// Final response of request is measured, while latency includes all retries
+	RetryPolicy RetryPolicy
+
+	// MaxRetries is a max number of retries of request
+	MaxRetries int
+
+	// RetryTokens, if set, must allow every retry, so retries are limited by the same rate as requests
+	RetryTokens <-chan struct{}
+
+	// SeparateFirstRequests, if true, measures latency of the first request on every connection
+	// by firstRequestDuration instead of requestDuration, so setup cost of connections doesn't skew the latter
+	SeparateFirstRequests bool
+
+	// Regions, if set, simulate clients of different locations. Every connection is assigned
+	// to region with probability of its share, and RTT of region delays every request over it.
+	// Latency of responses is also measured per region
+	Regions []Region
+
+	// Targets, if set, are sent in round-robin instead of request of New. They must have
+	// the same scheme as request of New. Requests and errors are also counted per target
+	Targets []*fasthttp.Request
+
+	// TargetNames, if set, are names of Targets, by which their requests and errors are counted instead of urls
+	TargetNames []string
+	// TargetWeights, if set, are positive weights of Targets. Every request is sent to random target
+	// with probability proportional to its weight instead of round-robin
+	TargetWeights []float64
+	// TargetForms, if set, are multipart bodies of Targets, which replace their bodies.
+	// Targets with nil Form are sent with their own bodies
+	TargetForms []*Form
+	// TargetMaxQPS, if set, are max rates of Targets. Zero means rate of target isn't limited.
+	// Request picked for target, whose rate is reached, is sent to another target picked by TargetWeights
+	// among ones, whose rates aren't reached, so rate of throttle is kept. Is ignored if Steps is true
+	TargetMaxQPS []float64
+
+	// Form, if set, is a multipart body, which replaces body of every request
+	// unless TargetForms is set
+	Form *Form
+
+	// Steps, if true, means every worker sends Targets in order as steps of its session
+	// and starts over after the last one. Values extracted by StepExtractions from responses
+	// replace placeholders like "${token}" in path, query, headers and body of following steps.
+	// Session starts over once step fails. TargetWeights are ignored
+	Steps bool
+	// StepExtractions are extractions of values from responses of Targets by their index.
+	// Are used only if Steps is true
+	StepExtractions [][]Extraction
+
+	// BackendHeader is a name of response header with id of backend which served request.
+	// If set, distribution of requests across backends and affinity breaks are counted
+	BackendHeader string
+
+	// ExpectedStatusCodes, if set, are status codes of successful responses instead of status code of New.
+	// Responses with other status codes are counted as errors
+	ExpectedStatusCodes map[int]bool
+
+	// DecodeResponses, if true, means gzip, deflate and br responses are decoded,
+	// so latency includes decoding and decoded size of bodies is counted
+	DecodeResponses bool
+	// MaxDecodedSize is a max size of decoded body. Bigger responses are counted as errors.
+	// Zero means no limit
+	MaxDecodedSize int64
+	// CompressBody is an encoding of request bodies: "gzip", "deflate" or "br".
+	// Bodies are compressed before sending with Content-Encoding header. If empty, bodies are sent as is
+	CompressBody string
+
+	// DialTimeout is a max time of establishing connection, including TLS handshake and proxy tunnel.
+	// Timeouts of connecting are counted separately from timeouts of requests. If zero, -httpClientRequestTimeout is used
+	DialTimeout time.Duration
+
+	// Cookies, if true, means every worker keeps its own cookie jar. Cookies set by responses
+	// are sent with following requests of the same worker, but aren't shared between workers
+	Cookies bool
+
+	// MaxConnsPerHost limits number of connections to every host of target.
+	// Once limit is reached, workers wait for free connection up to request timeout,
+	// so jobs are queued in Jobsch. Zero means no limit
+	MaxConnsPerHost int
+
+	// MaxIdleConnDuration is a time after which idle keep-alive connections are closed.
+	// Zero means one second
+	MaxIdleConnDuration time.Duration
+
+	// MaxConnDuration is a max lifetime of keep-alive connection. The first request sent after it
+	// is sent with "Connection: close", so connection is closed after response. Zero means no limit
+	MaxConnDuration time.Duration
+
+	// MaxConnRequests is a max number of requests sent over connection. Connection is closed
+	// before the next request, which is sent over another one. Zero means no limit
+	MaxConnRequests int
+
+	// LatencyBreakdown enables measuring of stages of requests: DNS lookup of new connections,
+	// sending of request, time to first byte and body transfer. Host is resolved by Client then,
+	// so lookups aren't served by DNS cache of fasthttp
+	LatencyBreakdown bool
+
+	// EchoHeader is a name of request header, which value must be echoed by target
+	// in response header with the same name. Missing and mismatched echoes are counted
+	EchoHeader string
+
+	// CaptureErrors is a max number of failed requests, which are captured along with responses.
+	// Zero disables capturing
+	CaptureErrors int
+
+	// Assertions are checks of responses, which are successful otherwise, e.g. by status code.
+	// Response failed any of them isn't successful and is counted as error
+	Assertions []Assertion
+
+	// OnSample, if set, is called by worker with Sample of every completed request.
+	// It must not block, since the next request of worker waits for it
+	OnSample func(Sample)
 
 	*fasthttp.HostClient
 	wg                sync.WaitGroup
@@ -48,27 +284,152 @@ type Client struct {
 	workers          int
 	statusCodeLabels map[int]prometheus.Labels
 	errorMessages    map[string]prometheus.Labels
+	grpcStatusLabels map[string]prometheus.Labels
+	backendLabels    map[string]prometheus.Labels
+	connIPLabels     map[string]prometheus.Labels
+	localIPLabels    map[string]prometheus.Labels
+
+	// dials is a number of connections dialed to DialAddrs
+	dials uint32
+	// localDials is a number of connections bound to LocalAddrs
+	localDials uint32
+	// resolves is a number of connections dialed to IPs resolved by Client
+	resolves uint32
+	// resolved contains IPs of hosts looked up during ResolveInterval
+	resolved map[string]*resolvedHost
+
+	// transport sends requests if client was created by NewGRPC or NewHTTP2
+	transport transport
+
+	// grpc is true if client was created by NewGRPC
+	grpc bool
+
+	timeout time.Duration
+
+	// statusCounters are counters of statusCodes by code, so status codes are counted without locking client.
+	// Counter is cached with vector it belongs to, since vectors are replaced by ResetMetrics
+	statusCounters [statusCountersSize]atomic.Pointer[statusCounter]
+
+	// connMetrics are metrics, by which traffic of connections of client is counted.
+	// They are replaced by ResetMetrics, so open connections are counted by new metrics
+	connMetrics atomic.Pointer[connMetrics]
+
+	// freshConns contains local addresses of connections, which first response wasn't received yet.
+	// Is used only if SeparateFirstRequests is true
+	freshConns map[string]struct{}
+
+	// regionConns maps local address of connection to index of its region
+	regionConns  map[string]int
+	regionsOnce  sync.Once
+	regionLabels []prometheus.Labels
+
+	// connTimings maps local address of connection to timing of its last request.
+	// Is used only if LatencyBreakdown is true
+	connTimings map[string]*connTiming
+
+	targetsOnce   sync.Once
+	targetsNext   uint32
+	targetWeights *aliasTable
+	targetClients []*fasthttp.HostClient
+	targetLabels  []prometheus.Labels
+	// targetCaps limit rates of Targets by TargetMaxQPS. Are nil for targets with unlimited rate
+	targetCaps []*rateCap
+
+	// stop is closed by Flush, so workers don't wait for retries
+	stop chan struct{}
+
+	// inFlight is a number of requests taken from Jobsch, which metrics aren't observed yet
+	inFlight atomic.Int64
+	// consecutiveErrors is a number of errors since the last successful response
+	consecutiveErrors atomic.Uint64
+	// workerSeq and connSeq are numbers of workers and connections started since New or Flush,
+	// by which their random values are derived from Seed
+	workerSeq atomic.Uint64
+	connSeq   atomic.Uint64
+	// taken is a number of jobs taken by workers of ClosedLoop and WebSocket,
+	// after jobLimit of which they stop. Zero jobLimit means no limit
+	taken    atomic.Uint64
+	jobLimit atomic.Uint64
+
+	// quit stops workers removed by StopWorkers
+	quit      chan struct{}
+	queueOnce sync.Once
+	// jobs, waited, blocked and maxQueued are state of pool since previous Backpressure
+	jobs      atomic.Uint64
+	waited    atomic.Uint64
+	blocked   atomic.Uint64
+	maxQueued atomic.Int64
+
+	portExhaustedWarning sync.Once
+	connLimitOnce        sync.Once
+
+	warmupMu     sync.Mutex
+	warmupModify Modifier
+
+	// connRequests maps number of requests sent over connection
+	// to number of closed connections with such number of requests
+	connRequestsMu sync.Mutex
+	connRequests   map[int]uint64
+
+	capturedMu   sync.Mutex
+	captured     []Exchange
+	capturedFull atomic.Bool
 }
 
 // New creates new client
 func New(request *fasthttp.Request, timeout time.Duration, sc int) *Client {
 	flushMetrics()
 	addr, isTLS := acquireAddr(request)
-	return &Client{
-		Jobsch:            make(chan struct{}, jobCapacity),
+	c := &Client{
+		Jobsch:            make(chan time.Time, jobCapacity),
+		stop:              make(chan struct{}),
+		quit:              make(chan struct{}),
 		request:           request,
 		statusCodeLabels:  make(map[int]prometheus.Labels),
 		errorMessages:     make(map[string]prometheus.Labels),
+		grpcStatusLabels:  make(map[string]prometheus.Labels),
+		backendLabels:     make(map[string]prometheus.Labels),
+		connIPLabels:      make(map[string]prometheus.Labels),
+		localIPLabels:     make(map[string]prometheus.Labels),
 		successStatusCode: sc,
-		HostClient: &fasthttp.HostClient{
-			Addr:                addr,
-			IsTLS:               isTLS,
-			Dial:                dial,
-			MaxIdleConnDuration: maxIdleConnDuration,
-			MaxConns:            maxConns,
-			ReadTimeout:         timeout,
-			WriteTimeout:        timeout,
-		},
+		connRequests:      make(map[int]uint64),
+		freshConns:        make(map[string]struct{}),
+		regionConns:       make(map[string]int),
+		connTimings:       make(map[string]*connTiming),
+	}
+	c.timeout = timeout
+	c.connMetrics.Store(newConnMetrics())
+	c.HostClient = c.newHostClient(addr, isTLS)
+	return c
+}
+
+func (c *Client) newHostClient(addr string, isTLS bool) *fasthttp.HostClient {
+	hc := &fasthttp.HostClient{
+		Addr:                addr,
+		IsTLS:               isTLS,
+		Dial:                c.dial,
+		MaxIdleConnDuration: maxIdleConnDuration,
+		MaxConns:            maxConns,
+		ReadTimeout:         c.timeout,
+		WriteTimeout:        c.timeout,
+		RetryIfErr:          retryIfErr,
+		// path is sent as is, so encoded slashes and dot segments aren't changed
+		DisablePathNormalizing: true,
+	}
+	c.limitConns(hc)
+	return hc
+}
+
+// limitConns applies MaxConnsPerHost, MaxIdleConnDuration and MaxConnDuration to hc.
+// Without wait timeout fasthttp fails requests immediately once limit of connections is reached
+func (c *Client) limitConns(hc *fasthttp.HostClient) {
+	if c.MaxIdleConnDuration > 0 {
+		hc.MaxIdleConnDuration = c.MaxIdleConnDuration
+	}
+	hc.MaxConnDuration = c.MaxConnDuration
+	if c.MaxConnsPerHost > 0 {
+		hc.MaxConns = c.MaxConnsPerHost
+		hc.MaxConnWaitTimeout = c.timeout
 	}
 }
 
@@ -82,7 +443,8 @@ func (c *Client) Amount() int {
 }
 
 // Overflow return length of job-channel
-// after calling Flush(), channel would flushed too
+// after calling Flush(), channel would flushed too.
+// See Backpressure for state of pool since previous check
 func (c *Client) Overflow() int {
 	c.Lock()
 	defer c.Unlock()
@@ -90,13 +452,24 @@ func (c *Client) Overflow() int {
 	return len(c.Jobsch)
 }
 
-func drainChan(ch chan struct{}) {
+// InFlight returns number of requests which are being sent by workers
+func (c *Client) InFlight() int {
+	return int(c.inFlight.Load())
+}
+
+// DropQueued removes jobs from Jobsch, which weren't taken by workers yet, and returns their number
+func (c *Client) DropQueued() int {
+	return drainChan(c.Jobsch)
+}
+
+func drainChan(ch chan time.Time) int {
+	n := 0
 	for {
 		select {
 		case <-ch:
-			continue
+			n++
 		default:
-			return
+			return n
 		}
 	}
 }
@@ -105,10 +478,16 @@ func drainChan(ch chan struct{}) {
 func (c *Client) Flush() {
 	drainChan(c.Jobsch)
 	close(c.Jobsch)
+	close(c.stop)
 	c.wg.Wait()
 	flushMetrics()
 	c.workers = 0
-	c.Jobsch = make(chan struct{}, jobCapacity)
+	c.workerSeq.Store(0)
+	c.connSeq.Store(0)
+	c.taken.Store(0)
+	c.jobLimit.Store(0)
+	c.Jobsch = make(chan time.Time, c.queueSize())
+	c.stop = make(chan struct{})
 }
 
 // RunWorkers runs n goroutines to serve jobs from Jobsch
@@ -116,46 +495,308 @@ func (c *Client) RunWorkers(n int) {
 	if n < 1 {
 		n = 1
 	}
+	// host client of target is created before limits of connections are set
+	c.connLimitOnce.Do(func() { c.limitConns(c.HostClient) })
+	c.queueOnce.Do(func() {
+		if size := c.queueSize(); size != cap(c.Jobsch) {
+			c.Jobsch = make(chan time.Time, size)
+		}
+	})
+	// workers are counted at once, so pool could be resized by Amount right after RunWorkers
+	c.Lock()
+	c.workers += n
+	c.Unlock()
 	for i := 0; i < n; i++ {
 		c.wg.Add(1)
+		// random values and modifier are acquired before goroutine starts,
+		// so they follow order of workers rather than scheduling of goroutines
+		rnd := c.workerRand()
+		var modify Modifier
+		if c.NewModifier != nil {
+			modify = c.NewModifier()
+		}
 		go func() {
-			c.Lock()
-			c.workers++
-			c.Unlock()
-
-			c.run()
+			if c.StartJitter > 0 {
+				time.Sleep(time.Duration(rnd.Int63n(int64(c.StartJitter))))
+			}
+			c.run(rnd, modify)
 			c.wg.Done()
 		}()
 	}
 }
 
-func (c *Client) run() {
+func (c *Client) run(rnd *rand.Rand, modify Modifier) {
+	if c.WebSocket {
+		c.runWebSocket(modify)
+		return
+	}
 	var resp fasthttp.Response
+	var gen RequestGenerator
+	if c.NewGenerator != nil {
+		gen = c.NewGenerator()
+	}
 	r := new(fasthttp.Request)
 	c.request.CopyTo(r)
-	for range c.Jobsch {
+	// backend is an id of backend which served previous request of worker
+	var backend string
+	var jar cookieJar
+	if c.Cookies {
+		jar = make(cookieJar)
+	}
+	var sess *session
+	if c.Steps && len(c.Targets) > 0 {
+		sess = &session{vars: make(map[string]string)}
+	}
+	var enc *bodyEncoder
+	if c.CompressBody != "" {
+		enc = &bodyEncoder{encoding: c.CompressBody}
+	}
+	jobs := c.Jobsch
+	for {
+		queued, ok := c.nextJob(jobs)
+		if !ok {
+			return
+		}
+		// size is a size of response body. Is negative if response wasn't read completely
+		size := -1
+		// sc is a status code of response and class is a class of failure. Are used by OnSample
+		sc, class := 0, ""
+		hc, target := c.HostClient, -1
+		if len(c.Targets) > 0 {
+			if sess != nil {
+				target = c.nextStep(sess)
+			} else {
+				target = c.capTarget(c.nextTarget(rnd), rnd)
+			}
+			c.Targets[target].CopyTo(r)
+			hc = c.targetClients[target]
+			if sess != nil {
+				sess.apply(r)
+			}
+		}
+		if form := c.formOf(target); form != nil {
+			form.apply(r)
+		}
+		if modify != nil {
+			if err := modify(r); err != nil {
+				c.countError(ErrorClassModifier, "cannot modify request: "+err.Error())
+				if sess != nil {
+					sess.done(false, len(c.Targets))
+				}
+				// request is finished as failed one, so errors don't exceed requests
+				metrics.Load().requestSum.Inc()
+				metrics.Load().jobsDone.Inc()
+				continue
+			}
+		}
+		if gen != nil {
+			if err := gen.Next(r); err != nil {
+				c.countError(ErrorClassGenerator, "cannot generate request: "+err.Error())
+				if sess != nil {
+					sess.done(false, len(c.Targets))
+				}
+				metrics.Load().requestSum.Inc()
+				metrics.Load().jobsDone.Inc()
+				continue
+			}
+		}
+		if jar != nil {
+			jar.apply(r)
+		}
+		if enc != nil {
+			enc.apply(r)
+		}
 		s := time.Now()
-		err := c.Do(r, &resp)
+		wait := s.Sub(queued)
+		c.inFlight.Add(1)
+		err := c.send(hc, r, &resp, rnd)
+		if isPortExhausted(err) {
+			// request wasn't sent at all, so it must not be considered as target failure
+			metrics.Load().portExhausted.Inc()
+			c.portExhaustedWarning.Do(func() {
+				slog.Warn("generator ran out of local ports - reduce connections churn " +
+					"(enable keepalive, decrease number of clients) or add source IPs")
+			})
+			metrics.Load().jobsDone.Inc()
+			c.inFlight.Add(-1)
+			continue
+		}
 		if err != nil {
-			if err == fasthttp.ErrTimeout {
-				timeouts.Inc()
+			if err == fasthttp.ErrDialTimeout || isTimeout(err) {
+				metrics.Load().timeouts.Inc()
 			}
-			errors.Inc()
-			c.withErrorMessage(err.Error()).Inc()
-		}
+			if err == io.ErrUnexpectedEOF {
+				// connection was closed by server before response was read completely.
+				// Conn.Read got clean io.EOF, so read error wasn't registered yet
+				metrics.Load().readError.Inc()
+			}
+			class = errorClass(err)
+			c.countError(class, err.Error())
+			if c.CaptureErrors > 0 {
+				c.capture(r, nil, err.Error())
+			}
+			if sess != nil {
+				sess.done(false, len(c.Targets))
+			}
+		} else {
+			// resp contains status code of partially read or previous response
+			// if request failed, so it is checked only for complete responses
+			sc = resp.StatusCode()
+			success := c.successStatusCode == sc
+			// failure describes why response isn't successful
+			failure := "status code " + strconv.Itoa(sc)
+			if len(c.ExpectedStatusCodes) > 0 {
+				if success = c.ExpectedStatusCodes[sc]; !success {
+					failure = "unexpected status code " + strconv.Itoa(sc)
+					c.countError(ErrorClassStatus, failure)
+				}
+			}
+			if !success {
+				class = ErrorClassStatus
+			}
+			if c.grpc {
+				status := grpcStatusName(string(resp.Header.Peek(GRPCStatusHeader)))
+				c.withGRPCStatus(status).Inc()
+				if success && status != "OK" {
+					success, failure, class = false, "gRPC status "+status, ErrorClassGRPC
+					c.countError(class, failure)
+				}
+			}
+			if c.DecodeResponses {
+				// response is decoded even if it already failed, since real clients decode it too,
+				// but decoding error is counted only for otherwise successful response
+				if err := c.observeDecoded(&resp); err != nil && success {
+					success, failure, class = false, err.Error(), ErrorClassResponse
+					c.countError(class, failure)
+				}
+			}
+			if success && len(c.Assertions) > 0 {
+				if msg := c.observeAssertions(&resp); msg != "" {
+					success, failure, class = false, msg, ErrorClassResponse
+				}
+			}
+			if success && sess != nil {
+				if msg := c.extract(sess, target, &resp); msg != "" {
+					success, failure, class = false, msg, ErrorClassResponse
+				}
+			}
+			if sess != nil {
+				sess.done(success, len(c.Targets))
+			}
+			if success {
+				c.consecutiveErrors.Store(0)
+				metrics.Load().requestSuccess.Inc()
+			} else if c.CaptureErrors > 0 {
+				c.capture(r, &resp, failure)
+			}
+			if jar != nil {
+				jar.capture(r, &resp)
+			}
+			if c.BackendHeader != "" {
+				backend = c.observeBackend(backend, resp.Header.Peek(c.BackendHeader))
+			}
+			if c.EchoHeader != "" {
+				observeEcho(r.Header.Peek(c.EchoHeader), resp.Header.Peek(c.EchoHeader))
+			}
+			if !r.Header.IsHTTP11() {
+				observeHTTP10(&resp)
+			}
+			observeThrottled(&resp)
 
-		sc := resp.StatusCode()
-		if c.successStatusCode == sc {
-			requestSuccess.Inc()
+			c.withStatusCode(sc).Inc()
+			size = len(resp.Body())
+		}
+		d := time.Since(s)
+		metrics.Load().queueWait.Observe(wait.Seconds())
+		if c.RecordCorrected {
+			observeCorrectedDuration(time.Since(queued).Seconds())
+		}
+		if c.IncludeQueueWait {
+			d += wait
+		}
+		if c.OnSample != nil {
+			start := s
+			if c.IncludeQueueWait {
+				start = queued
+			}
+			c.OnSample(Sample{Start: start, Duration: d, StatusCode: sc, ErrorClass: class})
+		}
+		if target >= 0 {
+			c.observeTarget(target, err != nil, d.Seconds())
+		}
+		if c.LatencyBreakdown && err == nil {
+			c.observeBreakdown(&resp, s)
 		}
+		if len(c.Regions) > 0 && err == nil {
+			c.observeRegionDuration(&resp, d.Seconds())
+		}
+		if c.SeparateFirstRequests && err == nil && c.isFirstRequest(&resp) {
+			observeFirstDuration(d.Seconds())
+		} else {
+			observeDuration(d.Seconds())
+			if size >= 0 {
+				observeSizeDuration(size, d.Seconds())
+			}
+		}
+		metrics.Load().requestSum.Inc()
+		metrics.Load().jobsDone.Inc()
+		c.inFlight.Add(-1)
+		if c.ThinkTime > 0 && !c.think(rnd) {
+			return
+		}
+	}
+}
+
+// errConnRotated is returned for request, which would exceed Client.MaxConnRequests of connection.
+// Request isn't written and is retried over another connection
+var errConnRotated = fmt.Errorf("connection is rotated after max number of requests")
 
-		c.withStatusCode(sc).Inc()
-		requestDuration.Observe(float64(time.Since(s).Seconds()))
-		requestSum.Inc()
+// retryIfErr retries idempotent requests like fasthttp does by default, except of timed out ones.
+// Otherwise single timeout would be counted for every attempt and request duration would be multiplied
+func retryIfErr(req *fasthttp.Request, attempts int, err error) (bool, bool) {
+	if err == errConnRotated {
+		// request wasn't written to connection, so it's safe to send it over another one
+		return false, true
 	}
+	if err == fasthttp.ErrDialTimeout || isTimeout(err) {
+		return false, false
+	}
+	return false, req.Header.IsGet() || req.Header.IsHead() || req.Header.IsPut()
+}
+
+// isPortExhausted checks whether err was caused by lack of free local ports (EADDRNOTAVAIL)
+func isPortExhausted(err error) bool {
+	for err != nil {
+		if errno, ok := err.(syscall.Errno); ok {
+			return errno == syscall.EADDRNOTAVAIL
+		}
+		u, ok := err.(interface {
+			Unwrap() error
+		})
+		if !ok {
+			return false
+		}
+		err = u.Unwrap()
+	}
+	return false
+}
+
+// statusCountersSize is a number of status codes cached by Client.statusCounters
+const statusCountersSize = 1000
+
+// statusCounter is a counter of statusCodes vector vec
+type statusCounter struct {
+	vec     *prometheus.CounterVec
+	counter prometheus.Counter
 }
 
 func (c *Client) withStatusCode(code int) prometheus.Counter {
+	vec := metrics.Load().statusCodes
+	if code >= 0 && code < statusCountersSize {
+		if sc := c.statusCounters[code].Load(); sc != nil && sc.vec == vec {
+			return sc.counter
+		}
+	}
 	var label prometheus.Labels
 	var ok bool
 	c.Lock()
@@ -164,7 +805,11 @@ func (c *Client) withStatusCode(code int) prometheus.Counter {
 		c.statusCodeLabels[code] = label
 	}
 	c.Unlock()
-	return statusCodes.With(label)
+	counter := vec.With(label)
+	if code >= 0 && code < statusCountersSize {
+		c.statusCounters[code].Store(&statusCounter{vec: vec, counter: counter})
+	}
+	return counter
 }
 
 func (c *Client) withErrorMessage(msg string) prometheus.Counter {
@@ -176,41 +821,201 @@ func (c *Client) withErrorMessage(msg string) prometheus.Counter {
 		c.errorMessages[msg] = label
 	}
 	c.Unlock()
-	return errorMessages.With(label)
+	return metrics.Load().errorMessages.With(label)
+}
+
+func (c *Client) connClosed(hc *hostConn) {
+	c.connRequestsMu.Lock()
+	c.connRequests[hc.requests]++
+	c.connRequestsMu.Unlock()
+	if c.SeparateFirstRequests {
+		c.removeFreshConn(hc.LocalAddr().String())
+	}
+	if len(c.Regions) > 0 {
+		c.removeRegionConn(hc.LocalAddr().String())
+	}
+}
+
+// ConnRequests returns map number of requests:number of connections,
+// which were closed after sending such number of requests
+func (c *Client) ConnRequests() map[int]uint64 {
+	c.connRequestsMu.Lock()
+	defer c.connRequestsMu.Unlock()
+
+	result := make(map[int]uint64, len(c.connRequests))
+	for k, v := range c.connRequests {
+		result[k] = v
+	}
+	return result
 }
 
 type hostConn struct {
 	net.Conn
-	addr         string
-	closed       uint32
-	connOpen     prometheus.Gauge
-	readError    prometheus.Counter
-	writeError   prometheus.Counter
-	bytesWritten prometheus.Counter
-	bytesRead    prometheus.Counter
+	addr    string
+	closed  uint32
+	metrics *atomic.Pointer[connMetrics]
+
+	// awaitingResponse is true if request was written,
+	// but no bytes of response were read yet
+	awaitingResponse bool
+
+	// warmup is true while connection is warmed up, so traffic isn't counted
+	warmup bool
+
+	// handshaking is true during TLS handshake, so its timeouts are counted as timeouts of connecting
+	handshaking bool
+
+	// requests is a number of requests sent over connection
+	requests int
+	onClose  func(hc *hostConn)
+
+	// maxRequests is a max number of requests sent over connection. Zero means no limit
+	maxRequests int
+
+	// timing is a timing of the last request. Is nil unless Client.LatencyBreakdown is true
+	timing *connTiming
+	// lastWrite is a time of the last write in unix nanoseconds. Is set only if timing is set
+	lastWrite int64
+
+	injectLatency time.Duration
+	injectDrop    float64
+	// rnd drops requests by injectDrop
+	rnd *randv2.Rand
+
+	uploadRate float64
+
+	// uploadStart is a time of the first write of current request
+	// and uploaded is a number of its bytes written since then
+	uploadStart time.Time
+	uploaded    int
+}
+
+func (c *Client) dial(addr string) (net.Conn, error) {
+	hc, err := c.dialHost(addr)
+	if err != nil {
+		return nil, err
+	}
+	var tc net.Conn = hc
+	if c.IsTLS {
+		if tc, err = handshake(hc, c.TLSConfig, c.dialTimeout()); err != nil {
+			return nil, err
+		}
+	}
+	if c.WarmupRequests > 0 {
+		return c.warmup(hc, tc)
+	}
+	if c.SeparateFirstRequests {
+		c.addFreshConn(hc.LocalAddr().String())
+	}
+	return tc, nil
+}
+
+// dialTimeout returns DialTimeout or -httpClientRequestTimeout if it isn't set
+func (c *Client) dialTimeout() time.Duration {
+	if c.DialTimeout > 0 {
+		return c.DialTimeout
+	}
+	return *httpClientRequestTimeout
 }
 
-func dial(addr string) (net.Conn, error) {
-	conn, err := fasthttp.DialTimeout(addr, *httpClientRequestTimeout)
+// dialHost establishes TCP connection to addr, which traffic is counted by metrics
+func (c *Client) dialHost(addr string) (*hostConn, error) {
+	var conn net.Conn
+	var start time.Time
+	var dialAddr string
+	var err error
+	if c.UnixSocket != "" {
+		start = time.Now()
+		conn, err = net.DialTimeout("unix", c.UnixSocket, c.dialTimeout())
+	} else if dialAddr, err = c.resolve(c.dialAddr(addr)); err == nil {
+		start = time.Now()
+		conn, err = c.dialTCP(dialAddr, c.dialTimeout())
+	}
 	if err != nil {
+		if err == fasthttp.ErrDialTimeout || isTimeout(err) {
+			metrics.Load().connectTimeouts.Inc()
+		}
 		return nil, err
 	}
+	observeConnectDuration(time.Since(start).Seconds())
+	if c.ProxyAddr != "" {
+		// traffic of CONNECT isn't counted, like traffic of TLS handshake
+		if err = c.tunnel(conn, addr); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	} else if c.UnixSocket == "" {
+		c.observeConnIP(conn)
+	}
 	if err = setupTCPConn(conn); err != nil {
-		connError.Inc()
+		metrics.Load().connError.Inc()
+		conn.Close()
+		return nil, err
+	}
+
+	rnd := c.connRand()
+	injectLatency := c.InjectLatency
+	if len(c.Regions) > 0 {
+		injectLatency += c.Regions[c.pickRegion(conn.LocalAddr().String(), rnd.Float64())].RTT
+	}
+
+	c.connMetrics.Load().connOpen.Inc()
+	hc := &hostConn{
+		Conn:    conn,
+		addr:    addr,
+		metrics: &c.connMetrics,
+		onClose: c.connClosed,
+
+		injectLatency: injectLatency,
+		injectDrop:    c.InjectDrop,
+		rnd:           rnd,
+		uploadRate:    c.UploadRate,
+		maxRequests:   c.MaxConnRequests,
+	}
+	if c.LatencyBreakdown {
+		hc.timing = c.addConnTiming(conn.LocalAddr().String())
+	}
+	return hc, nil
+}
+
+// handshake establishes TLS connection over hc with configuration based on cfg.
+// It is made here instead of fasthttp, so handshake messages aren't considered as requests by hc.
+// protos are application protocols offered via ALPN
+func handshake(hc *hostConn, cfg *tls.Config, timeout time.Duration, protos ...string) (net.Conn, error) {
+	conn := tls.Client(hc, tlsConfig(cfg, hc.addr, protos))
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+	start := time.Now()
+	hc.handshaking = true
+	err := conn.Handshake()
+	hc.handshaking = false
+	if err != nil {
+		if isTimeout(err) {
+			metrics.Load().connectTimeouts.Inc()
+		}
 		conn.Close()
 		return nil, err
 	}
+	observeHandshakeDuration(time.Since(start).Seconds())
+	conn.SetDeadline(time.Time{})
+	hc.requests = 0
+	hc.awaitingResponse = false
+	return conn, nil
+}
 
-	connOpen.Inc()
-	return &hostConn{
-		Conn:         conn,
-		addr:         addr,
-		connOpen:     connOpen,
-		readError:    readError,
-		writeError:   writeError,
-		bytesWritten: bytesWritten,
-		bytesRead:    bytesRead,
-	}, nil
+// tlsConfig returns copy of cfg for connection to addr offering protos via ALPN.
+// ServerName is set to host of addr unless it is set by cfg
+func tlsConfig(cfg *tls.Config, addr string, protos []string) *tls.Config {
+	if cfg == nil {
+		cfg = &tls.Config{}
+	}
+	cfg = cfg.Clone()
+	if cfg.ServerName == "" {
+		cfg.ServerName = addr[:strings.LastIndex(addr, ":")]
+	}
+	cfg.NextProtos = protos
+	return cfg
 }
 
 func setupTCPConn(conn net.Conn) error {
@@ -243,30 +1048,82 @@ func setupTCPConn(conn net.Conn) error {
 
 func (hc *hostConn) Close() error {
 	if atomic.AddUint32(&hc.closed, 1) == 1 {
-		hc.connOpen.Dec()
+		hc.metrics.Load().connOpen.Dec()
+		hc.onClose(hc)
 	}
 
 	return hc.Conn.Close()
 }
 
 func (hc *hostConn) Write(p []byte) (int, error) {
-	n, err := hc.Conn.Write(p)
-	hc.bytesWritten.Add(float64(n))
+	if hc.warmup {
+		return hc.Conn.Write(p)
+	}
+	if !hc.awaitingResponse {
+		// the first write of request
+		if hc.maxRequests > 0 && hc.requests >= hc.maxRequests {
+			hc.Close()
+			return 0, errConnRotated
+		}
+		if err := hc.inject(); err != nil {
+			return 0, err
+		}
+		hc.requests++
+		hc.uploadStart, hc.uploaded = time.Now(), 0
+	}
+	hc.awaitingResponse = true
+	var n int
+	var err error
+	if hc.uploadRate > 0 {
+		n, err = hc.writeThrottled(p)
+	} else {
+		n, err = hc.Conn.Write(p)
+	}
+	m := hc.metrics.Load()
+	m.bytesWritten.Add(float64(n))
 	if err != nil {
-		hc.writeError.Inc()
+		m.writeError.Inc()
+	} else if hc.timing != nil {
+		hc.lastWrite = time.Now().UnixNano()
 	}
 	return n, err
 }
 
 func (hc *hostConn) Read(p []byte) (int, error) {
+	if hc.warmup {
+		return hc.Conn.Read(p)
+	}
 	n, err := hc.Conn.Read(p)
-	hc.bytesRead.Add(float64(n))
+	m := hc.metrics.Load()
+	m.bytesRead.Add(float64(n))
+	if isTimeout(err) && !hc.handshaking {
+		if hc.awaitingResponse && n == 0 {
+			metrics.Load().firstByteTimeouts.Inc()
+		} else {
+			metrics.Load().bodyReadTimeouts.Inc()
+		}
+	}
+	if n > 0 {
+		if hc.awaitingResponse && hc.timing != nil {
+			// writes after response like TLS close_notify don't change timing of request
+			atomic.StoreInt64(&hc.timing.written, hc.lastWrite)
+			atomic.StoreInt64(&hc.timing.firstByte, time.Now().UnixNano())
+		}
+		hc.awaitingResponse = false
+	}
 	if err != nil && err != io.EOF {
-		hc.readError.Inc()
+		m.readError.Inc()
 	}
 	return n, err
 }
 
+func isTimeout(err error) bool {
+	t, ok := err.(interface {
+		Timeout() bool
+	})
+	return ok && t.Timeout()
+}
+
 func acquireAddr(req *fasthttp.Request) (string, bool) {
 	addr := string(req.URI().Host())
 	if len(addr) == 0 {
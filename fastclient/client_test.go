@@ -0,0 +1,438 @@
+package fastclient
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const truncatedResponse = "HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\n0123456789"
+
+// serveTruncated runs server which closes connection in the middle of response body
+func serveTruncated(t *testing.T) net.Listener {
+	ln := listenTest(t)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			buf := make([]byte, 4096)
+			conn.Read(buf)
+			conn.Write([]byte(truncatedResponse))
+			conn.Close()
+		}
+	}()
+	return ln
+}
+
+func TestClientTruncatedResponse(t *testing.T) {
+	ln := serveTruncated(t)
+	defer ln.Close()
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	c.Jobsch <- time.Now()
+
+	waitRequests(t, c, 1)
+
+	if n := c.BytesRead(); n < uint64(len(truncatedResponse)) {
+		t.Errorf("Partially read bytes weren't counted. Got: %d; Expected at least: %d", n, len(truncatedResponse))
+	}
+	if n := c.Errors(); n != 1 {
+		t.Errorf("Unexpected number of errors. Got: %d; Expected: %d", n, 1)
+	}
+	if n := c.ReadErrors(); n < 1 {
+		t.Errorf("Truncated response wasn't classified as read error")
+	}
+	if n := c.RequestSuccess(); n != 0 {
+		t.Errorf("Unexpected number of success requests. Got: %d; Expected: %d", n, 0)
+	}
+}
+
+// serveStalled runs server which writes beginning of response and stalls
+func serveStalled(t *testing.T, response string) net.Listener {
+	ln := listenTest(t)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			buf := make([]byte, 4096)
+			conn.Read(buf)
+			// connection is left open, so client waits for the rest of response
+			conn.Write([]byte(response))
+		}
+	}()
+	return ln
+}
+
+func TestClientTimeouts(t *testing.T) {
+	f := func(response string, firstByte, bodyRead uint64) {
+		t.Helper()
+		ln := serveStalled(t, response)
+		defer ln.Close()
+
+		req := new(fasthttp.Request)
+		req.SetRequestURI("http://" + ln.Addr().String() + "/")
+		c := New(req, 200*time.Millisecond, fasthttp.StatusOK)
+		t.Cleanup(c.Flush)
+		c.RunWorkers(1)
+		c.Jobsch <- time.Now()
+
+		waitRequests(t, c, 1)
+
+		if n := c.Timeouts(); n != 1 {
+			t.Errorf("Unexpected number of timeouts. Got: %d; Expected: %d", n, 1)
+		}
+		if n := c.FirstByteTimeouts(); n != firstByte {
+			t.Errorf("Unexpected number of first byte timeouts. Got: %d; Expected: %d", n, firstByte)
+		}
+		if n := c.BodyReadTimeouts(); n != bodyRead {
+			t.Errorf("Unexpected number of body read timeouts. Got: %d; Expected: %d", n, bodyRead)
+		}
+	}
+
+	f("", 1, 0)
+	f("HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\n0123456789", 0, 1)
+}
+
+func TestClientHandshakeTimeout(t *testing.T) {
+	ln := listenTest(t)
+	// server never responds to handshake. Connections are kept referenced,
+	// so they aren't closed by finalizers
+	conns := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+		}
+	}()
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("https://" + ln.Addr().String() + "/")
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	c.DialTimeout = 200 * time.Millisecond
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	c.Jobsch <- time.Now()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for c.RequestSum() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Request wasn't done in time, so -requestTimeout was applied to handshake")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if n := c.Timeouts(); n != 1 {
+		t.Errorf("Unexpected number of timeouts. Got: %d; Expected: %d", n, 1)
+	}
+	if n := c.ConnectTimeouts(); n != 1 {
+		t.Errorf("Unexpected number of connect timeouts. Got: %d; Expected: %d", n, 1)
+	}
+	if n := c.FirstByteTimeouts(); n != 0 {
+		t.Errorf("Unexpected number of first byte timeouts. Got: %d; Expected: %d", n, 0)
+	}
+}
+
+func TestClientRequestURI(t *testing.T) {
+	ln := listenTest(t)
+	lines := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lines <- line
+		conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"))
+	}()
+
+	const uri = "/a%2Fb/./c?q=caf%C3%A9&x=a%20b"
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + uri)
+	c := New(req, time.Second, fasthttp.StatusOK)
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	c.Jobsch <- time.Now()
+
+	select {
+	case line := <-lines:
+		if expected := "GET " + uri + " HTTP/1.1\r\n"; line != expected {
+			t.Errorf("Unexpected request line. Got: %q; Expected: %q", line, expected)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Request wasn't received in time")
+	}
+	// metrics are global, so request must be finished before the next test
+	waitRequests(t, c, 1)
+}
+
+func TestClientMaxConnsPerHost(t *testing.T) {
+	ln := listenTest(t)
+	var conns int32
+	s := &fasthttp.Server{
+		Handler: func(ctx *fasthttp.RequestCtx) {
+			time.Sleep(10 * time.Millisecond)
+		},
+		ConnState: func(_ net.Conn, state fasthttp.ConnState) {
+			if state == fasthttp.StateNew {
+				atomic.AddInt32(&conns, 1)
+			}
+		},
+	}
+	go s.Serve(ln)
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	c.MaxConnsPerHost = 2
+	t.Cleanup(c.Flush)
+	c.RunWorkers(8)
+	const requests = 40
+	for i := 0; i < requests; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, requests)
+	if n := c.RequestSum(); n != requests {
+		t.Fatalf("Unexpected number of requests. Got: %d; Expected: %d", n, requests)
+	}
+	if n := c.Errors(); n != 0 {
+		t.Errorf("Unexpected number of errors. Got: %d; Expected: %d", n, 0)
+	}
+	if n := atomic.LoadInt32(&conns); n > 2 {
+		t.Errorf("Unexpected number of connections. Got: %d; Expected: at most %d", n, 2)
+	}
+}
+
+func TestClientMaxConnRequests(t *testing.T) {
+	f := func(method string, maxRequests int, requests, conns int32) {
+		t.Helper()
+		ln := listenTest(t)
+		var n int32
+		s := &fasthttp.Server{
+			Handler: func(ctx *fasthttp.RequestCtx) {},
+			ConnState: func(_ net.Conn, state fasthttp.ConnState) {
+				if state == fasthttp.StateNew {
+					atomic.AddInt32(&n, 1)
+				}
+			},
+		}
+		go s.Serve(ln)
+
+		req := new(fasthttp.Request)
+		req.SetRequestURI("http://" + ln.Addr().String() + "/")
+		req.Header.SetMethod(method)
+		c := New(req, 5*time.Second, fasthttp.StatusOK)
+		c.MaxConnRequests = maxRequests
+		t.Cleanup(c.Flush)
+		c.RunWorkers(1)
+		for i := int32(0); i < requests; i++ {
+			c.Jobsch <- time.Now()
+		}
+		waitRequests(t, c, uint64(requests))
+		if got := c.RequestSum(); got != uint64(requests) {
+			t.Fatalf("Unexpected number of requests. Got: %d; Expected: %d", got, requests)
+		}
+		if got := c.Errors(); got != 0 {
+			t.Errorf("Unexpected number of errors. Got: %d; Expected: %d", got, 0)
+		}
+		if got := atomic.LoadInt32(&n); got != conns {
+			t.Errorf("Unexpected number of connections. Got: %d; Expected: %d", got, conns)
+		}
+		for k := range c.ConnRequests() {
+			if maxRequests > 0 && k > maxRequests {
+				t.Errorf("Connection was closed after %d requests; Expected: at most %d", k, maxRequests)
+			}
+		}
+	}
+
+	f("GET", 0, 6, 1)
+	f("GET", 2, 6, 3)
+	// rotated requests aren't written, so non-idempotent ones are retried as well
+	f("POST", 3, 7, 3)
+}
+
+func TestClientUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	defer ln.Close()
+	var host atomic.Value
+	s := &fasthttp.Server{
+		Handler: func(ctx *fasthttp.RequestCtx) {
+			host.Store(string(ctx.Host()))
+		},
+	}
+	go s.Serve(ln)
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://app.local:8080/")
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	c.UnixSocket = path
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 3; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 3)
+	if got := c.RequestSuccess(); got != 3 {
+		t.Fatalf("Unexpected number of successful requests. Got: %d; Expected: 3; errors: %v", got, c.ErrorMessages())
+	}
+	if h := host.Load(); h != "app.local:8080" {
+		t.Errorf("Unexpected Host header: %q", h)
+	}
+	if len(c.ConnIPs()) != 0 {
+		t.Errorf("Connections to Unix socket mustn't be counted by remote IP: %v", c.ConnIPs())
+	}
+}
+
+func TestClientOnSample(t *testing.T) {
+	ln := listenTest(t)
+	var n atomic.Int32
+	s := &fasthttp.Server{
+		Handler: func(ctx *fasthttp.RequestCtx) {
+			if n.Add(1)%2 == 0 {
+				ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			}
+		},
+	}
+	go s.Serve(ln)
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	samples := make(chan Sample, 4)
+	c.OnSample = func(s Sample) { samples <- s }
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		c.Jobsch <- time.Now()
+	}
+	statuses := make(map[int]string)
+	for i := 0; i < 4; i++ {
+		select {
+		case s := <-samples:
+			if s.Start.Before(start) || s.Duration <= 0 {
+				t.Errorf("Unexpected start %s and duration %s of sample", s.Start, s.Duration)
+			}
+			statuses[s.StatusCode] = s.ErrorClass
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Samples weren't passed in time")
+		}
+	}
+	expected := map[int]string{fasthttp.StatusOK: "", fasthttp.StatusServiceUnavailable: ErrorClassStatus}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("Unexpected error classes by status codes. Got: %v; Expected: %v", statuses, expected)
+	}
+}
+
+func TestIsPortExhausted(t *testing.T) {
+	f := func(err error, expected bool) {
+		t.Helper()
+		if got := isPortExhausted(err); got != expected {
+			t.Errorf("Unexpected port exhaustion of %v. Got: %v; Expected: %v", err, got, expected)
+		}
+	}
+
+	dialErr := func(err error) error {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", err)}
+	}
+	f(nil, false)
+	f(syscall.EADDRNOTAVAIL, true)
+	f(dialErr(syscall.EADDRNOTAVAIL), true)
+	f(fmt.Errorf("cannot dial: %w", dialErr(syscall.EADDRNOTAVAIL)), true)
+	f(dialErr(syscall.ECONNREFUSED), false)
+	f(fasthttp.ErrDialTimeout, false)
+}
+
+func TestClientPortExhausted(t *testing.T) {
+	ln := startFastTestServer(t, func(ctx *fasthttp.RequestCtx) {})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	// address of TEST-NET-1 isn't assigned to local interfaces, so connection can't be bound to it
+	c.LocalAddrs = []net.IP{net.ParseIP("192.0.2.1")}
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 3; i++ {
+		c.Jobsch <- time.Now()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for c.PortExhausted() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Requests weren't rejected in time. Got: %d; Expected: 3; errors: %v", c.PortExhausted(), c.ErrorMessages())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// requests which weren't sent aren't failures of target
+	if n := c.Errors(); n != 0 {
+		t.Errorf("Unexpected number of errors. Got: %d; Expected: 0; errors: %v", n, c.ErrorMessages())
+	}
+	if n := c.RequestSum(); n != 0 {
+		t.Errorf("Unexpected number of requests. Got: %d; Expected: 0", n)
+	}
+}
+
+func TestClientInFlight(t *testing.T) {
+	release := make(chan struct{})
+	ln := startFastTestServer(t, func(ctx *fasthttp.RequestCtx) { <-release })
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	t.Cleanup(c.Flush)
+	c.RunWorkers(2)
+	for i := 0; i < 5; i++ {
+		c.Jobsch <- time.Now()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for c.InFlight() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Requests weren't sent in time. Got: %d in flight; Expected: 2", c.InFlight())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// jobs which weren't taken by busy workers are dropped
+	if n := c.DropQueued(); n != 3 {
+		t.Errorf("Unexpected number of dropped jobs. Got: %d; Expected: 3", n)
+	}
+	if n := c.Overflow(); n != 0 {
+		t.Errorf("Unexpected number of queued jobs after drop. Got: %d; Expected: 0", n)
+	}
+	close(release)
+	deadline = time.Now().Add(5 * time.Second)
+	for c.InFlight() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Requests are still in flight after responses. Got: %d; Expected: 0", c.InFlight())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := c.RequestSuccess(); n != 2 {
+		t.Errorf("Unexpected number of successful requests. Got: %d; Expected: 2", n)
+	}
+}
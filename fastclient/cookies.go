@@ -0,0 +1,44 @@
+package fastclient
+
+import (
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// cookieJar contains cookies set by responses to requests of single worker,
+// so every worker keeps its own session like distinct client.
+// Cookies are kept per host, while their domain and path attributes are ignored
+type cookieJar map[string]map[string]string
+
+// capture stores cookies from Set-Cookie headers of resp to r.
+// Expired cookies and cookies with empty values are removed from jar and r
+func (j cookieJar) capture(r *fasthttp.Request, resp *fasthttp.Response) {
+	host := r.Host()
+	var cookie fasthttp.Cookie
+	now := time.Now()
+	for key, value := range resp.Header.Cookies() {
+		if err := cookie.ParseBytes(value); err != nil {
+			continue
+		}
+		cookies := j[string(host)]
+		expire := cookie.Expire()
+		if len(cookie.Value()) == 0 || (expire != fasthttp.CookieExpireUnlimited && expire.Before(now)) {
+			delete(cookies, string(key))
+			r.Header.DelCookieBytes(key)
+			continue
+		}
+		if cookies == nil {
+			cookies = make(map[string]string)
+			j[string(host)] = cookies
+		}
+		cookies[string(key)] = string(cookie.Value())
+	}
+}
+
+// apply adds stored cookies for host of r to r
+func (j cookieJar) apply(r *fasthttp.Request) {
+	for name, value := range j[string(r.Host())] {
+		r.Header.SetCookie(name, value)
+	}
+}
@@ -0,0 +1,68 @@
+package fastclient
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientCookies(t *testing.T) {
+	// requests without session cookie get new session and 401,
+	// so only first request of every worker isn't successful
+	var sessions uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil {
+			id := strconv.Itoa(int(atomic.AddUint32(&sessions, 1)))
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: id})
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.Cookies = true
+	t.Cleanup(c.Flush)
+	c.RunWorkers(2)
+	for i := 0; i < 20; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 20)
+
+	n := atomic.LoadUint32(&sessions)
+	if n > 2 {
+		t.Fatalf("Unexpected number of sessions. Got: %d; Expected: at most 2", n)
+	}
+	if got, expected := c.RequestSuccess(), 20-uint64(n); got != expected {
+		t.Fatalf("Unexpected number of successful requests. Got: %d; Expected: %d", got, expected)
+	}
+}
+
+func TestCookieJarExpired(t *testing.T) {
+	jar := make(cookieJar)
+	var r fasthttp.Request
+	r.SetRequestURI("http://example.com/")
+	var resp fasthttp.Response
+	resp.Header.Add("Set-Cookie", "a=1")
+	resp.Header.Add("Set-Cookie", "b=2")
+	jar.capture(&r, &resp)
+	jar.apply(&r)
+	if got := string(r.Header.Cookie("b")); got != "2" {
+		t.Fatalf("Unexpected cookie. Got: %q; Expected: %q", got, "2")
+	}
+
+	resp.Reset()
+	resp.Header.Add("Set-Cookie", "b=; Expires=Thu, 01 Jan 1970 00:00:00 GMT")
+	jar.capture(&r, &resp)
+	jar.apply(&r)
+	if got := string(r.Header.Cookie("b")); got != "" {
+		t.Fatalf("Expired cookie is still sent: %q", got)
+	}
+	if got := string(r.Header.Cookie("a")); got != "1" {
+		t.Fatalf("Unexpected cookie. Got: %q; Expected: %q", got, "1")
+	}
+}
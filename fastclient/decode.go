@@ -0,0 +1,89 @@
+package fastclient
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ErrDecodedSizeExceeded means decoded body of compressed response exceeds Client.MaxDecodedSize
+var ErrDecodedSizeExceeded = fmt.Errorf("decoded body exceeds max decoded size")
+
+// decodedSize returns size of body of resp decoded according to its Content-Encoding.
+// Decoded body is discarded, so memory isn't spent on it. ok is false
+// if response isn't compressed or its encoding isn't supported
+func decodedSize(resp *fasthttp.Response, max int64) (n int64, ok bool, err error) {
+	body := bytes.NewReader(resp.Body())
+	var r io.ReadCloser
+	switch string(bytes.ToLower(resp.Header.ContentEncoding())) {
+	case "br":
+		// brotli decoder of fasthttp only writes decoded body, so limit is checked by writer
+		w := &discardWriter{max: max}
+		if _, err = fasthttp.WriteUnbrotli(w, resp.Body()); err != nil && err != ErrDecodedSizeExceeded {
+			err = fmt.Errorf("cannot decode body: %s", err)
+		}
+		return w.n, true, err
+	case "gzip", "x-gzip":
+		r, err = gzip.NewReader(body)
+	case "deflate":
+		// deflate is zlib stream by RFC 9110, but some servers send raw deflate
+		if r, err = zlib.NewReader(body); err == zlib.ErrHeader {
+			body.Reset(resp.Body())
+			r, err = flate.NewReader(body), nil
+		}
+	default:
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, true, fmt.Errorf("cannot decode body: %s", err)
+	}
+	defer r.Close()
+
+	if max > 0 {
+		n, err = io.Copy(ioutil.Discard, io.LimitReader(r, max+1))
+	} else {
+		n, err = io.Copy(ioutil.Discard, r)
+	}
+	if err != nil {
+		return n, true, fmt.Errorf("cannot decode body: %s", err)
+	}
+	if max > 0 && n > max {
+		return n, true, ErrDecodedSizeExceeded
+	}
+	return n, true, nil
+}
+
+// discardWriter counts and discards written bytes. Once more than max bytes are written,
+// it counts max+1 bytes and returns ErrDecodedSizeExceeded. Zero max means no limit
+type discardWriter struct {
+	max int64
+	n   int64
+}
+
+func (w *discardWriter) Write(p []byte) (int, error) {
+	if w.max > 0 && w.n+int64(len(p)) > w.max {
+		w.n = w.max + 1
+		return 0, ErrDecodedSizeExceeded
+	}
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// observeDecoded counts compressed response and sizes of its body on wire and decoded.
+// Returns error if body can't be decoded or exceeds MaxDecodedSize
+func (c *Client) observeDecoded(resp *fasthttp.Response) error {
+	n, ok, err := decodedSize(resp, c.MaxDecodedSize)
+	if !ok {
+		return nil
+	}
+	metrics.Load().compressedResponses.Inc()
+	metrics.Load().bytesCompressed.Add(float64(len(resp.Body())))
+	metrics.Load().bytesDecoded.Add(float64(n))
+	return err
+}
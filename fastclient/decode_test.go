@@ -0,0 +1,113 @@
+package fastclient
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestDecodedSize(t *testing.T) {
+	payload := strings.Repeat("a", 10000)
+	encode := func(w io.WriteCloser) {
+		w.Write([]byte(payload))
+		w.Close()
+	}
+	var gz, zl, fl bytes.Buffer
+	encode(gzip.NewWriter(&gz))
+	encode(zlib.NewWriter(&zl))
+	w, _ := flate.NewWriter(&fl, flate.DefaultCompression)
+	encode(w)
+
+	f := func(encoding string, body []byte, max, size int64, compressed bool, expectErr error) {
+		t.Helper()
+		var resp fasthttp.Response
+		resp.Header.Set("Content-Encoding", encoding)
+		resp.SetBody(body)
+		n, ok, err := decodedSize(&resp, max)
+		if ok != compressed {
+			t.Fatalf("Unexpected compressed for %q. Got: %v; Expected: %v", encoding, ok, compressed)
+		}
+		if expectErr != nil && err != expectErr {
+			t.Fatalf("Unexpected error for %q. Got: %v; Expected: %v", encoding, err, expectErr)
+		}
+		if expectErr == nil && err != nil {
+			t.Fatalf("Unexpected error for %q: %s", encoding, err)
+		}
+		if n != size {
+			t.Fatalf("Unexpected decoded size for %q. Got: %d; Expected: %d", encoding, n, size)
+		}
+	}
+	f("", []byte(payload), 0, 0, false, nil)
+	f("identity", []byte(payload), 0, 0, false, nil)
+	br := fasthttp.AppendBrotliBytes(nil, []byte(payload))
+	f("br", br, 0, 10000, true, nil)
+	f("br", br, 100, 101, true, ErrDecodedSizeExceeded)
+	f("gzip", gz.Bytes(), 0, 10000, true, nil)
+	f("GZIP", gz.Bytes(), 10000, 10000, true, nil)
+	f("deflate", zl.Bytes(), 0, 10000, true, nil)
+	// raw deflate without zlib header
+	f("deflate", fl.Bytes(), 0, 10000, true, nil)
+	// decoding of bomb stops right after limit
+	f("gzip", gz.Bytes(), 100, 101, true, ErrDecodedSizeExceeded)
+}
+
+func TestDecodedSizeError(t *testing.T) {
+	f := func(encoding string, body []byte) {
+		t.Helper()
+		var resp fasthttp.Response
+		resp.Header.Set("Content-Encoding", encoding)
+		resp.SetBody(body)
+		if _, ok, err := decodedSize(&resp, 0); !ok || err == nil {
+			t.Fatalf("Expected error for malformed %q body", encoding)
+		}
+	}
+	f("gzip", []byte("not gzip"))
+	f("br", []byte("not br"))
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(strings.Repeat("a", 10000)))
+	w.Close()
+	// truncated stream
+	f("gzip", gz.Bytes()[:gz.Len()/2])
+}
+
+func TestClientDecodeError(t *testing.T) {
+	f := func(sc int, class string) {
+		t.Helper()
+		ln := startFastTestServer(t, func(ctx *fasthttp.RequestCtx) {
+			ctx.SetStatusCode(sc)
+			ctx.Response.Header.Set("Content-Encoding", "gzip")
+			ctx.SetBodyString("not gzip")
+		})
+		req := new(fasthttp.Request)
+		req.SetRequestURI("http://" + ln.Addr().String() + "/")
+		c := New(req, time.Second, fasthttp.StatusOK)
+		c.ExpectedStatusCodes = map[int]bool{fasthttp.StatusOK: true}
+		c.DecodeResponses = true
+		defer c.Flush()
+		c.RunWorkers(1)
+		c.Jobsch <- time.Now()
+		waitRequests(t, c, 1)
+
+		// failed request is counted as single error of the class it failed first
+		if n := c.Errors(); n != 1 {
+			t.Fatalf("Unexpected number of errors for status %d. Got: %d; Expected: 1; errors: %v", sc, n, c.ErrorMessages())
+		}
+		if classes := c.ErrorClasses(); classes[class] != 1 {
+			t.Errorf("Unexpected error classes for status %d. Got: %v; Expected: 1 of %q", sc, classes, class)
+		}
+		if n := c.CompressedResponses(); n != 1 {
+			t.Errorf("Unexpected number of compressed responses for status %d. Got: %d; Expected: 1", sc, n)
+		}
+	}
+
+	f(fasthttp.StatusOK, ErrorClassResponse)
+	f(fasthttp.StatusServiceUnavailable, ErrorClassStatus)
+}
@@ -0,0 +1,53 @@
+package fastclient
+
+import (
+	"net"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// dialAddr returns address to dial for connection to addr. Addresses of
+// Client.DialAddrs are returned in round-robin if set, and addr otherwise
+func (c *Client) dialAddr(addr string) string {
+	if c.ProxyAddr != "" {
+		return c.ProxyAddr
+	}
+	if len(c.DialAddrs) == 0 {
+		return addr
+	}
+	n := atomic.AddUint32(&c.dials, 1) - 1
+	return c.DialAddrs[n%uint32(len(c.DialAddrs))]
+}
+
+// observeConnIP counts connection established to remote IP of conn
+func (c *Client) observeConnIP(conn net.Conn) {
+	ip := conn.RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	var label prometheus.Labels
+	var ok bool
+	c.Lock()
+	if label, ok = c.connIPLabels[ip]; !ok {
+		label = prometheus.Labels{"ip": ip}
+		c.connIPLabels[ip] = label
+	}
+	c.Unlock()
+	metrics.Load().connIPs.With(label).Inc()
+}
+
+// ConnIPs returns map ip:value for connIPs-metric where value
+// is a number of connections established to remote ip
+func (c *Client) ConnIPs() map[string]uint64 {
+	result := make(map[string]uint64)
+	c.Lock()
+	defer c.Unlock()
+	for ip, label := range c.connIPLabels {
+		m := &dto.Metric{}
+		metrics.Load().connIPs.With(label).Write(m)
+		result[ip] = uint64(*m.Counter.Value)
+	}
+	return result
+}
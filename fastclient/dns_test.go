@@ -0,0 +1,45 @@
+package fastclient
+
+import (
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientDialAddrs(t *testing.T) {
+	var addrs []string
+	for _, ip := range []string{"127.0.0.1", "127.0.0.2"} {
+		ln, err := net.Listen("tcp", ip+":0")
+		if err != nil {
+			t.Skipf("Cannot listen on %s: %s", ip, err)
+		}
+		defer ln.Close()
+		go http.Serve(ln, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		addrs = append(addrs, ln.Addr().String())
+	}
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://example.com/")
+	// every request is sent over new connection
+	req.SetConnectionClose()
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.DialAddrs = addrs
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 4; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 4)
+	if c.Errors() > 0 {
+		t.Fatalf("Unexpected errors: %v", c.ErrorMessages())
+	}
+
+	expected := map[string]uint64{"127.0.0.1": 2, "127.0.0.2": 2}
+	if got := c.ConnIPs(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected connections by ip. Got: %v; Expected: %v", got, expected)
+	}
+}
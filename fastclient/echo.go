@@ -0,0 +1,47 @@
+package fastclient
+
+import (
+	"bytes"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Results of echo check
+const (
+	EchoOK       = "ok"
+	EchoMissing  = "missing"
+	EchoMismatch = "mismatch"
+)
+
+var echoLabels = map[string]prometheus.Labels{
+	EchoOK:       {"result": EchoOK},
+	EchoMissing:  {"result": EchoMissing},
+	EchoMismatch: {"result": EchoMismatch},
+}
+
+// observeEcho counts result of comparison of sent value of Client.EchoHeader with echoed one
+func observeEcho(sent, echoed []byte) {
+	switch {
+	case len(echoed) == 0:
+		metrics.Load().echoResults.With(echoLabels[EchoMissing]).Inc()
+	case !bytes.Equal(sent, echoed):
+		metrics.Load().echoResults.With(echoLabels[EchoMismatch]).Inc()
+	default:
+		metrics.Load().echoResults.With(echoLabels[EchoOK]).Inc()
+	}
+}
+
+// EchoResults returns map result:value for echoResults-metric where value
+// is a number of responses with such result. Is empty if EchoHeader isn't set
+func (*Client) EchoResults() map[string]uint64 {
+	result := make(map[string]uint64)
+	for name, label := range echoLabels {
+		m := &dto.Metric{}
+		metrics.Load().echoResults.With(label).Write(m)
+		if n := uint64(*m.Counter.Value); n > 0 {
+			result[name] = n
+		}
+	}
+	return result
+}
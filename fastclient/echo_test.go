@@ -0,0 +1,41 @@
+package fastclient
+
+import (
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientEchoResults(t *testing.T) {
+	// every even request is echoed, other ones lose or rewrite header
+	var requests uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddUint32(&requests, 1) % 4 {
+		case 0, 2:
+			w.Header().Set("X-Request-ID", r.Header.Get("X-Request-ID"))
+		case 3:
+			w.Header().Set("X-Request-ID", "rewritten")
+		}
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	req.Header.Set("X-Request-ID", "id")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.EchoHeader = "X-Request-ID"
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 8; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 8)
+
+	expected := map[string]uint64{EchoOK: 4, EchoMissing: 2, EchoMismatch: 2}
+	if got := c.EchoResults(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected echo results. Got: %v; Expected: %v", got, expected)
+	}
+}
@@ -0,0 +1,47 @@
+package fastclient
+
+import (
+	"bytes"
+
+	"github.com/valyala/fasthttp"
+)
+
+// bodyEncoder compresses bodies of requests of single worker.
+// The last compressed body is kept, so static body isn't compressed for every request
+type bodyEncoder struct {
+	encoding string
+	// src is the last body before compression and dst is its compressed value
+	src, dst []byte
+}
+
+// apply compresses body of r and sets Content-Encoding header.
+// Streamed and empty bodies are sent as is
+func (e *bodyEncoder) apply(r *fasthttp.Request) {
+	if r.IsBodyStream() || len(r.Body()) == 0 {
+		return
+	}
+	body := r.Body()
+	// body of reused request is compressed already, unless modifier changed it
+	if !bytes.Equal(body, e.dst) {
+		if !bytes.Equal(body, e.src) {
+			e.src = append(e.src[:0], body...)
+			e.dst = compressBody(e.dst[:0], e.src, e.encoding)
+		}
+		r.SetBody(e.dst)
+	}
+	r.Header.Set(fasthttp.HeaderContentEncoding, e.encoding)
+	metrics.Load().compressedRequests.Inc()
+	metrics.Load().requestBytesCompressed.Add(float64(len(e.dst)))
+	metrics.Load().requestBytesUncompressed.Add(float64(len(e.src)))
+}
+
+func compressBody(dst, src []byte, encoding string) []byte {
+	switch encoding {
+	case "deflate":
+		return fasthttp.AppendDeflateBytes(dst, src)
+	case "br":
+		return fasthttp.AppendBrotliBytes(dst, src)
+	default:
+		return fasthttp.AppendGzipBytes(dst, src)
+	}
+}
@@ -0,0 +1,62 @@
+package fastclient
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestBodyEncoder(t *testing.T) {
+	payload := strings.Repeat("a", 10000)
+	f := func(encoding string) {
+		t.Helper()
+		flushMetrics()
+		e := &bodyEncoder{encoding: encoding}
+		var r fasthttp.Request
+		r.Header.SetMethod("POST")
+		r.SetBodyString(payload)
+		// reused request must not be compressed twice
+		for i := 0; i < 2; i++ {
+			e.apply(&r)
+		}
+		if s := string(r.Header.ContentEncoding()); s != encoding {
+			t.Fatalf("Unexpected Content-Encoding. Got: %q; Expected: %q", s, encoding)
+		}
+		var resp fasthttp.Response
+		resp.Header.Set("Content-Encoding", encoding)
+		resp.SetBody(r.Body())
+		if n, _, err := decodedSize(&resp, 0); err != nil || n != int64(len(payload)) {
+			t.Fatalf("Unexpected decoded size of %s body: %d; error: %v", encoding, n, err)
+		}
+		c := &Client{}
+		if n := c.CompressedRequests(); n != 2 {
+			t.Fatalf("Unexpected number of compressed requests. Got: %d; Expected: 2", n)
+		}
+		if n := c.RequestBytesUncompressed(); n != 2*uint64(len(payload)) {
+			t.Fatalf("Unexpected uncompressed size. Got: %d; Expected: %d", n, 2*len(payload))
+		}
+		if n := c.RequestBytesCompressed(); n != 2*uint64(len(r.Body())) {
+			t.Fatalf("Unexpected compressed size. Got: %d; Expected: %d", n, 2*len(r.Body()))
+		}
+
+		// body changed by modifier is compressed again
+		r.SetBodyString("b")
+		e.apply(&r)
+		resp.SetBody(r.Body())
+		if n, _, err := decodedSize(&resp, 0); err != nil || n != 1 {
+			t.Fatalf("Unexpected decoded size of modified %s body: %d; error: %v", encoding, n, err)
+		}
+	}
+	f("gzip")
+	f("deflate")
+	f("br")
+
+	// empty body isn't compressed
+	e := &bodyEncoder{encoding: "gzip"}
+	var r fasthttp.Request
+	e.apply(&r)
+	if len(r.Header.ContentEncoding()) > 0 {
+		t.Fatalf("Unexpected Content-Encoding of empty body")
+	}
+}
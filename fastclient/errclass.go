@@ -0,0 +1,117 @@
+package fastclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+// Classes of errors
+const (
+	ErrorClassTimeout = "timeout"
+	ErrorClassDNS     = "dns"
+	ErrorClassRefused = "connection refused"
+	// ErrorClassReset means connection was reset or closed by peer while request was in progress
+	ErrorClassReset = "connection reset"
+	ErrorClassTLS   = "tls"
+	// ErrorClassDial means connection wasn't established for other reason than DNS, refusal, TLS or timeout
+	ErrorClassDial = "dial"
+	// ErrorClassStatus means response has unexpected status code
+	ErrorClassStatus = "http status"
+	// ErrorClassResponse means body of response can't be decoded or response failed assertion
+	ErrorClassResponse = "invalid response"
+	// ErrorClassGenerator means RequestGenerator failed, so request wasn't sent
+	ErrorClassGenerator = "generator"
+	// ErrorClassModifier means Modifier failed, e.g. template couldn't be rendered, so request wasn't sent
+	ErrorClassModifier = "modifier"
+	// ErrorClassGRPC means gRPC call finished with status other than OK
+	ErrorClassGRPC  = "grpc status"
+	ErrorClassOther = "other"
+)
+
+var errorClassLabels = func() map[string]prometheus.Labels {
+	labels := make(map[string]prometheus.Labels)
+	for _, class := range []string{ErrorClassTimeout, ErrorClassDNS, ErrorClassRefused, ErrorClassReset,
+		ErrorClassTLS, ErrorClassDial, ErrorClassStatus, ErrorClassResponse, ErrorClassGenerator, ErrorClassModifier, ErrorClassGRPC, ErrorClassOther} {
+		labels[class] = prometheus.Labels{"class": class}
+	}
+	return labels
+}()
+
+// errorClass returns class of err returned by request.
+// Wrapped errors are inspected, since errors of dialing and of HTTP/2 are wrapped
+func errorClass(err error) string {
+	if err == fasthttp.ErrDialTimeout || err == fasthttp.ErrTimeout || isTimeout(err) {
+		return ErrorClassTimeout
+	}
+	dial := false
+	for ; err != nil; err = unwrapError(err) {
+		switch e := err.(type) {
+		case *net.DNSError:
+			return ErrorClassDNS
+		case tls.RecordHeaderError, tls.AlertError, *tls.CertificateVerificationError,
+			x509.UnknownAuthorityError, x509.HostnameError, x509.CertificateInvalidError:
+			return ErrorClassTLS
+		case syscall.Errno:
+			switch e {
+			case syscall.ECONNREFUSED:
+				return ErrorClassRefused
+			case syscall.ECONNRESET, syscall.EPIPE:
+				return ErrorClassReset
+			}
+		case *net.OpError:
+			dial = dial || e.Op == "dial"
+		}
+		if err == fasthttp.ErrConnectionClosed || err == io.EOF || err == io.ErrUnexpectedEOF {
+			return ErrorClassReset
+		}
+	}
+	if dial {
+		return ErrorClassDial
+	}
+	return ErrorClassOther
+}
+
+// unwrapError returns error wrapped by err or nil
+func unwrapError(err error) error {
+	u, ok := err.(interface {
+		Unwrap() error
+	})
+	if !ok {
+		return nil
+	}
+	return u.Unwrap()
+}
+
+// countError counts error of class with message msg
+func (c *Client) countError(class, msg string) {
+	metrics.Load().errors.Inc()
+	c.consecutiveErrors.Add(1)
+	metrics.Load().errorClasses.With(errorClassLabels[class]).Inc()
+	c.withErrorMessage(msg).Inc()
+}
+
+// ConsecutiveErrors returns number of errors counted since the last successful response
+func (c *Client) ConsecutiveErrors() uint64 {
+	return c.consecutiveErrors.Load()
+}
+
+// ErrorClasses returns map class:value for errorClasses-metric where value
+// is a number of errors of class. Classes without errors are omitted
+func (*Client) ErrorClasses() map[string]uint64 {
+	result := make(map[string]uint64)
+	for class, label := range errorClassLabels {
+		m := &dto.Metric{}
+		metrics.Load().errorClasses.With(label).Write(m)
+		if n := uint64(*m.Counter.Value); n > 0 {
+			result[class] = n
+		}
+	}
+	return result
+}
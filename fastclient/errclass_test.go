@@ -0,0 +1,98 @@
+package fastclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestErrorClass(t *testing.T) {
+	f := func(err error, expected string) {
+		t.Helper()
+		if got := errorClass(err); got != expected {
+			t.Errorf("Unexpected class of %q. Got: %q; Expected: %q", err, got, expected)
+		}
+	}
+
+	dialErr := func(err error) error {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", err)}
+	}
+	f(fasthttp.ErrDialTimeout, ErrorClassTimeout)
+	f(fasthttp.ErrTimeout, ErrorClassTimeout)
+	f(&net.DNSError{Err: "no such host", Name: "invalid", IsNotFound: true}, ErrorClassDNS)
+	f(dialErr(syscall.ECONNREFUSED), ErrorClassRefused)
+	f(dialErr(syscall.ENETUNREACH), ErrorClassDial)
+	f(&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, ErrorClassReset)
+	f(fasthttp.ErrConnectionClosed, ErrorClassReset)
+	f(io.ErrUnexpectedEOF, ErrorClassReset)
+	f(tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, ErrorClassTLS)
+	f(&tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}, ErrorClassTLS)
+	// errors of HTTP/2 are wrapped by net/http
+	f(&url.Error{Op: "Get", URL: "https://localhost", Err: dialErr(syscall.ECONNREFUSED)}, ErrorClassRefused)
+	f(fmt.Errorf("unexpected"), ErrorClassOther)
+}
+
+func TestClientErrorClasses(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	// nothing listens at address of closed listener
+	addr := ln.Addr().String()
+	ln.Close()
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + addr + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 3; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 3)
+
+	expected := map[string]uint64{ErrorClassRefused: 3}
+	if got := c.ErrorClasses(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected error classes. Got: %v; Expected: %v", got, expected)
+	}
+}
+
+func TestClientConsecutiveErrors(t *testing.T) {
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://127.0.0.1/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.countError(ErrorClassStatus, "unexpected status code 500")
+	c.countError(ErrorClassTimeout, "timeout")
+	if n := c.ConsecutiveErrors(); n != 2 {
+		t.Fatalf("Unexpected consecutive errors. Got: %d; Expected: 2", n)
+	}
+
+	// successful response resets streak
+	ln := startFastTestServer(t, func(ctx *fasthttp.RequestCtx) {})
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c = New(req, time.Second, fasthttp.StatusOK)
+	c.countError(ErrorClassStatus, "unexpected status code 500")
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	c.Jobsch <- time.Now()
+	deadline := time.Now().Add(5 * time.Second)
+	for c.RequestSuccess() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Request wasn't done in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := c.ConsecutiveErrors(); n != 0 {
+		t.Errorf("Unexpected consecutive errors after success. Got: %d; Expected: 0", n)
+	}
+}
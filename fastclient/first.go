@@ -0,0 +1,36 @@
+package fastclient
+
+import (
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+// addFreshConn marks connection with given local address as one, which first response wasn't received yet
+func (c *Client) addFreshConn(addr string) {
+	c.Lock()
+	c.freshConns[addr] = struct{}{}
+	c.Unlock()
+}
+
+// removeFreshConn unmarks connection with given local address as fresh
+// and returns true if it was marked
+func (c *Client) removeFreshConn(addr string) bool {
+	c.Lock()
+	_, ok := c.freshConns[addr]
+	delete(c.freshConns, addr)
+	c.Unlock()
+	return ok
+}
+
+// isFirstRequest returns true if resp is the first response received over its connection
+func (c *Client) isFirstRequest(resp *fasthttp.Response) bool {
+	addr := resp.LocalAddr()
+	return addr != nil && c.removeFreshConn(addr.String())
+}
+
+// FirstRequests returns number of requests measured by firstRequestDuration-metric
+func (*Client) FirstRequests() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().firstRequestDuration.Write(m)
+	return m.Summary.GetSampleCount()
+}
@@ -0,0 +1,44 @@
+package fastclient
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientSeparateFirstRequests(t *testing.T) {
+	// every connection serves two requests
+	var requests uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddUint32(&requests, 1)%2 == 0 {
+			w.Header().Set("Connection", "close")
+		}
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.SeparateFirstRequests = true
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 6; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 6)
+
+	if n := c.FirstRequests(); n != 3 {
+		t.Errorf("Unexpected number of first requests. Got: %d; Expected: 3", n)
+	}
+	if l := c.FirstRequestLatency(); l.Max <= 0 || l.P50 <= 0 {
+		t.Errorf("Unexpected latency of first requests: %+v", l)
+	}
+	m := &dto.Metric{}
+	metrics.Load().requestDuration.Write(m)
+	if n := m.Summary.GetSampleCount(); n != 3 {
+		t.Errorf("Unexpected number of subsequent requests. Got: %d; Expected: 3", n)
+	}
+}
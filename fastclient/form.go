@@ -0,0 +1,186 @@
+package fastclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// FormField is a field of multipart/form-data body.
+// Content of File is sent instead of Value if File isn't empty
+type FormField struct {
+	Name  string
+	Value string
+	File  string
+}
+
+// Form is a multipart/form-data body, which is built for every request with its own boundary.
+// Files are read while body is written, so their content is never kept in memory.
+// Sizes of files are read once by NewForm, so files must not change during the test
+type Form struct {
+	fields []FormField
+	// sizes are sizes of files of fields. Are zero for fields without file
+	sizes []int64
+	size  int64
+}
+
+// NewForm returns Form of fields. Files of fields must be regular files
+func NewForm(fields []FormField) (*Form, error) {
+	f := &Form{fields: fields, sizes: make([]int64, len(fields))}
+	for i, ff := range fields {
+		if ff.File == "" {
+			continue
+		}
+		fi, err := os.Stat(ff.File)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read file of form field %q: %s", ff.Name, err)
+		}
+		if !fi.Mode().IsRegular() {
+			return nil, fmt.Errorf("file %q of form field %q isn't a regular file", ff.File, ff.Name)
+		}
+		f.sizes[i] = fi.Size()
+	}
+	// length of boundary doesn't change, so every body has the same size
+	b, _ := f.body()
+	f.size = b.size()
+	return f, nil
+}
+
+// Size returns size of body of every request in bytes
+func (f *Form) Size() int64 {
+	return f.size
+}
+
+// apply sets body of r to stream of new multipart body and sets its content type
+func (f *Form) apply(r *fasthttp.Request) {
+	b, contentType := f.body()
+	r.Header.SetContentType(contentType)
+	r.SetBodyStream(b, int(f.size))
+}
+
+// formOf returns Form of target or Client.Form if target is negative or TargetForms isn't set
+func (c *Client) formOf(target int) *Form {
+	if target >= 0 && len(c.TargetForms) > 0 {
+		return c.TargetForms[target]
+	}
+	return c.Form
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// body returns stream of multipart body with new boundary and its content type
+func (f *Form) body() (*formBody, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	b := &formBody{}
+	// start is an offset of buf, which isn't added to segments yet
+	start := 0
+	for i, ff := range f.fields {
+		if ff.File == "" {
+			fw, _ := w.CreateFormField(ff.Name)
+			io.WriteString(fw, ff.Value)
+			continue
+		}
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(ff.Name), quoteEscaper.Replace(filepath.Base(ff.File))))
+		h.Set("Content-Type", fileContentType(ff.File))
+		w.CreatePart(h)
+		// buf only grows, so previous segments keep their data
+		b.segments = append(b.segments, formSegment{data: buf.Bytes()[start:]}, formSegment{file: ff.File, n: f.sizes[i]})
+		start = buf.Len()
+	}
+	w.Close()
+	b.segments = append(b.segments, formSegment{data: buf.Bytes()[start:]})
+	return b, w.FormDataContentType()
+}
+
+// fileContentType returns content type of file by its extension
+func fileContentType(path string) string {
+	if t := mime.TypeByExtension(filepath.Ext(path)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// formSegment is either data of multipart body or n bytes of file
+type formSegment struct {
+	data []byte
+	file string
+	n    int64
+}
+
+// formBody is a stream of multipart body, which opens files of its segments once they are read.
+// It is closed by fasthttp after body is written
+type formBody struct {
+	segments []formSegment
+	cur      formSegment
+	f        *os.File
+}
+
+func (b *formBody) size() int64 {
+	var n int64
+	for _, s := range b.segments {
+		n += int64(len(s.data)) + s.n
+	}
+	return n
+}
+
+func (b *formBody) Read(p []byte) (int, error) {
+	for {
+		if len(b.cur.data) > 0 {
+			n := copy(p, b.cur.data)
+			b.cur.data = b.cur.data[n:]
+			return n, nil
+		}
+		if b.cur.n > 0 {
+			return b.readFile(p)
+		}
+		if len(b.segments) == 0 {
+			return 0, io.EOF
+		}
+		b.cur, b.segments = b.segments[0], b.segments[1:]
+	}
+}
+
+func (b *formBody) readFile(p []byte) (int, error) {
+	if b.f == nil {
+		f, err := os.Open(b.cur.file)
+		if err != nil {
+			return 0, err
+		}
+		b.f = f
+	}
+	if int64(len(p)) > b.cur.n {
+		p = p[:b.cur.n]
+	}
+	n, err := b.f.Read(p)
+	b.cur.n -= int64(n)
+	if err == io.EOF && b.cur.n > 0 {
+		return n, fmt.Errorf("file %q was truncated after start of the test", b.cur.file)
+	}
+	if b.cur.n == 0 {
+		b.f.Close()
+		b.f = nil
+		err = nil
+	}
+	return n, err
+}
+
+// Close closes file of body, which wasn't read completely
+func (b *formBody) Close() error {
+	if b.f == nil {
+		return nil
+	}
+	err := b.f.Close()
+	b.f = nil
+	return err
+}
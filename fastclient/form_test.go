@@ -0,0 +1,91 @@
+package fastclient
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientForm(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "upload.bin")
+	content := strings.Repeat("0123456789", 100000)
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Cannot write file: %s", err)
+	}
+	form, err := NewForm([]FormField{{Name: "name", Value: "test"}, {Name: "file", File: file}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var mu sync.Mutex
+	var failures []string
+	boundaries := make(map[string]bool)
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.ContentLength != form.Size() {
+			failures = append(failures, "unexpected content length")
+		}
+		mr, err := r.MultipartReader()
+		if err != nil {
+			failures = append(failures, err.Error())
+			return
+		}
+		boundaries[r.Header.Get("Content-Type")] = true
+		name, _ := mr.NextPart()
+		if b, _ := ioutil.ReadAll(name); name.FormName() != "name" || string(b) != "test" {
+			failures = append(failures, "unexpected value of name field: "+string(b))
+		}
+		f, _ := mr.NextPart()
+		if b, _ := ioutil.ReadAll(f); f.FileName() != "upload.bin" || string(b) != content {
+			failures = append(failures, "unexpected file "+f.FileName())
+		}
+	})
+
+	flushMetrics()
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	req.Header.SetMethod(fasthttp.MethodPost)
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	c.Form = form
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 3; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 3)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failures) > 0 {
+		t.Fatalf("Unexpected requests: %v", failures)
+	}
+	if len(boundaries) != 3 {
+		t.Errorf("Every request must have its own boundary; got %d of them", len(boundaries))
+	}
+	if n, expected := c.BytesWritten(), uint64(3*form.Size()); n < expected {
+		t.Errorf("Streamed bodies aren't counted by BytesWritten. Got: %d; Expected at least %d", n, expected)
+	}
+	if c.RequestSuccess() != 3 {
+		t.Errorf("Unexpected number of successful requests: %d", c.RequestSuccess())
+	}
+}
+
+func TestNewFormError(t *testing.T) {
+	f := func(fields []FormField) {
+		t.Helper()
+		if _, err := NewForm(fields); err == nil {
+			t.Errorf("Expected error for form %v", fields)
+		}
+	}
+
+	f([]FormField{{Name: "file", File: filepath.Join(t.TempDir(), "missing")}})
+	f([]FormField{{Name: "dir", File: os.TempDir()}})
+}
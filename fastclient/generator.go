@@ -0,0 +1,22 @@
+package fastclient
+
+import (
+	"github.com/valyala/fasthttp"
+)
+
+// RequestGenerator fills requests, which can't be expressed by templates,
+// e.g. signed requests or protobuf bodies. See Client.NewGenerator
+type RequestGenerator interface {
+	// Next changes req before it would be sent. req contains request of target,
+	// which may be reused from previous request of the same worker.
+	// If error is returned, request isn't sent
+	Next(req *fasthttp.Request) error
+}
+
+// GeneratorFunc is an adapter to use ordinary function as RequestGenerator
+type GeneratorFunc func(req *fasthttp.Request) error
+
+// Next calls f(req)
+func (f GeneratorFunc) Next(req *fasthttp.Request) error {
+	return f(req)
+}
@@ -0,0 +1,131 @@
+package fastclient
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientGenerator(t *testing.T) {
+	var signed uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Signature") != "" {
+			atomic.AddUint32(&signed, 1)
+		}
+	})
+
+	flushMetrics()
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.NewGenerator = func() RequestGenerator {
+		var n int
+		return GeneratorFunc(func(req *fasthttp.Request) error {
+			n++
+			if n%4 == 0 {
+				return fmt.Errorf("signing failed")
+			}
+			req.Header.Set("X-Signature", strconv.Itoa(n))
+			return nil
+		})
+	}
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 8; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 8)
+
+	if n := atomic.LoadUint32(&signed); n != 6 {
+		t.Errorf("Unexpected number of generated requests. Got: %d; Expected: 6", n)
+	}
+	if n := c.ErrorClasses()[ErrorClassGenerator]; n != 2 {
+		t.Errorf("Unexpected number of generator errors. Got: %d; Expected: 2", n)
+	}
+	if msgs := c.ErrorMessages(); msgs["cannot generate request: signing failed"] != 2 {
+		t.Errorf("Unexpected error messages: %v", msgs)
+	}
+	if n := c.RequestSuccess(); n != 6 {
+		t.Errorf("Unexpected number of successful requests. Got: %d; Expected: 6", n)
+	}
+}
+
+func TestClientGeneratorAlwaysFails(t *testing.T) {
+	flushMetrics()
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://127.0.0.1/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.NewGenerator = func() RequestGenerator {
+		return GeneratorFunc(func(req *fasthttp.Request) error {
+			return fmt.Errorf("signing failed")
+		})
+	}
+	t.Cleanup(c.Flush)
+	c.RunWorkers(2)
+	for i := 0; i < 5; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 5)
+
+	// failed request is counted as finished one, so errors rate can't exceed 100%
+	if errs, requests := c.Errors(), c.RequestSum(); errs != 5 || requests != 5 {
+		t.Errorf("Unexpected errors of requests. Got: %d errors of %d requests; Expected: 5 of 5", errs, requests)
+	}
+}
+
+func TestClientModifierError(t *testing.T) {
+	var received uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(&received, 1)
+	})
+
+	// f sends 6 requests, every failEvery-th of which can't be modified
+	f := func(failEvery int, expectedSent uint32) {
+		t.Helper()
+		atomic.StoreUint32(&received, 0)
+		flushMetrics()
+		req := new(fasthttp.Request)
+		req.SetRequestURI("http://" + ln.Addr().String() + "/")
+		c := New(req, time.Second, fasthttp.StatusOK)
+		c.NewModifier = func() Modifier {
+			var n int
+			return func(req *fasthttp.Request) error {
+				n++
+				if n%failEvery == 0 {
+					return fmt.Errorf("rendering failed")
+				}
+				return nil
+			}
+		}
+		defer c.Flush()
+		c.RunWorkers(1)
+		for i := 0; i < 6; i++ {
+			c.Jobsch <- time.Now()
+		}
+		waitRequests(t, c, 6)
+
+		failed := 6 - uint64(expectedSent)
+		if n := atomic.LoadUint32(&received); n != expectedSent {
+			t.Errorf("Unexpected number of sent requests. Got: %d; Expected: %d", n, expectedSent)
+		}
+		if n := c.ErrorClasses()[ErrorClassModifier]; n != failed {
+			t.Errorf("Unexpected number of modifier errors. Got: %d; Expected: %d", n, failed)
+		}
+		if msgs := c.ErrorMessages(); uint64(msgs["cannot modify request: rendering failed"]) != failed {
+			t.Errorf("Unexpected error messages: %v", msgs)
+		}
+		// failed request is counted as finished one, so errors rate can't exceed 100%
+		if errs, requests := c.Errors(), c.RequestSum(); errs > requests {
+			t.Errorf("Errors exceed requests. Got: %d errors of %d requests", errs, requests)
+		}
+	}
+
+	f(2, 3)
+	// every request fails
+	f(1, 0)
+}
@@ -0,0 +1,99 @@
+package fastclient
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/valyala/fasthttp"
+)
+
+// GRPCStatusHeader is a name of header, which contains gRPC status of response.
+// Is set from trailers of gRPC response
+const GRPCStatusHeader = "Grpc-Status"
+
+// grpcCodes contains names of gRPC status codes, indexed by code
+var grpcCodes = []string{"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND",
+	"ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION", "ABORTED",
+	"OUT_OF_RANGE", "UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED"}
+
+// grpcTransport sends requests as unary gRPC calls over HTTP/2.
+// net/http is used, since fasthttp doesn't support HTTP/2
+type grpcTransport struct {
+	client  *http.Client
+	timeout time.Duration
+}
+
+// NewGRPC creates new client which sends request as unary gRPC call.
+// Body of request must contain length-prefixed message and
+// request is successful if its status code is sc and gRPC status is OK
+func NewGRPC(request *fasthttp.Request, timeout time.Duration, sc int) *Client {
+	c := New(request, timeout, sc)
+	c.grpc = true
+	c.transport = &grpcTransport{
+		client:  &http.Client{Transport: c.newHTTP2Transport()},
+		timeout: timeout,
+	}
+	return c
+}
+
+// do sends req as gRPC call and fills resp with status code, body and gRPC status of response
+func (t *grpcTransport) do(req *fasthttp.Request, resp *fasthttp.Response) error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+	hr, err := newHTTPRequest(ctx, http.MethodPost, req)
+	if err != nil {
+		return err
+	}
+	hr.Header.Set("Content-Type", "application/grpc")
+	hr.Header.Set("Te", "trailers")
+
+	res, err := t.client.Do(hr)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	resp.Reset()
+	resp.SetStatusCode(res.StatusCode)
+	resp.SetBody(body)
+	// trailers-only responses contain status in headers
+	status := res.Trailer.Get(GRPCStatusHeader)
+	if status == "" {
+		status = res.Header.Get(GRPCStatusHeader)
+	}
+	if status != "" {
+		resp.Header.Set(GRPCStatusHeader, status)
+	}
+	return nil
+}
+
+// grpcStatusName returns name of gRPC status like "UNAVAILABLE"
+func grpcStatusName(status string) string {
+	if status == "" {
+		return "MISSING"
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil || code < 0 || code >= len(grpcCodes) {
+		return status
+	}
+	return grpcCodes[code]
+}
+
+func (c *Client) withGRPCStatus(name string) prometheus.Counter {
+	var label prometheus.Labels
+	var ok bool
+	c.Lock()
+	if label, ok = c.grpcStatusLabels[name]; !ok {
+		label = prometheus.Labels{"status": name}
+		c.grpcStatusLabels[name] = label
+	}
+	c.Unlock()
+	return metrics.Load().grpcStatusCodes.With(label)
+}
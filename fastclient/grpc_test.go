@@ -0,0 +1,73 @@
+package fastclient
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// serveGRPC runs h2c server which responds to every call with given gRPC status
+func serveGRPC(t *testing.T, status string) net.Listener {
+	ln := listenTest(t)
+	s := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ProtoMajor != 2 || r.Header.Get("Content-Type") != "application/grpc" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			ioutil.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/grpc")
+			w.Header().Set("Trailer", GRPCStatusHeader)
+			w.Write([]byte{0, 0, 0, 0, 0})
+			w.Header().Set(GRPCStatusHeader, status)
+		}),
+		Protocols: new(http.Protocols),
+	}
+	s.Protocols.SetUnencryptedHTTP2(true)
+	go s.Serve(ln)
+	return ln
+}
+
+func TestClientGRPC(t *testing.T) {
+	f := func(status, name string, success, errs uint64) {
+		t.Helper()
+		ln := serveGRPC(t, status)
+		defer ln.Close()
+
+		req := new(fasthttp.Request)
+		req.SetRequestURI("http://" + ln.Addr().String() + "/test.Service/Method")
+		req.SetBody([]byte{0, 0, 0, 0, 0})
+		c := NewGRPC(req, time.Second, fasthttp.StatusOK)
+		t.Cleanup(c.Flush)
+		c.RunWorkers(1)
+		c.Jobsch <- time.Now()
+
+		waitRequests(t, c, 1)
+
+		if n := c.Errors(); n != errs {
+			t.Fatalf("Unexpected number of errors. Got: %d; Expected: %d; errors: %v", n, errs, c.ErrorMessages())
+		}
+		if n := c.ErrorClasses()[ErrorClassGRPC]; n != errs {
+			t.Errorf("Unexpected number of errors of class %q. Got: %d; Expected: %d", ErrorClassGRPC, n, errs)
+		}
+		if errs > 0 && c.ErrorMessages()["gRPC status "+name] != int(errs) {
+			t.Errorf("Unexpected error messages. Got: %v; Expected: %d of %q", c.ErrorMessages(), errs, "gRPC status "+name)
+		}
+		if n := c.RequestSuccess(); n != success {
+			t.Errorf("Unexpected number of success requests. Got: %d; Expected: %d", n, success)
+		}
+		if codes := c.GRPCStatusCodes(); codes[name] != 100 {
+			t.Errorf("Unexpected gRPC status codes. Got: %v; Expected: 100%% of %s", codes, name)
+		}
+		if n := c.BytesWritten(); n == 0 {
+			t.Errorf("Written bytes weren't counted")
+		}
+	}
+
+	f("0", "OK", 1, 0)
+	f("14", "UNAVAILABLE", 0, 1)
+}
@@ -0,0 +1,190 @@
+package fastclient
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	// histSubBits is a number of significant bits of latency in nanoseconds, which are kept by buckets,
+	// so every bucket is narrower than 1/64 of its values and quantiles are accurate within 0.8 %
+	histSubBits = 7
+	histSub     = 1 << histSubBits
+	histHalf    = histSub / 2
+
+	// histMaxExp is an exponent of width of the last buckets, which cover latency up to 2^40ns or ~18 minutes.
+	// Longer latency is counted by the last bucket
+	histMaxExp  = 33
+	histMaxNs   = 1 << 40
+	histBuckets = histSub + histMaxExp*histHalf
+)
+
+// histogram counts latency by log-linear buckets of nanoseconds: exact ones below histSub
+// and histHalf buckets of equal width for every next power of two
+type histogram struct {
+	counts [histBuckets]atomic.Uint64
+}
+
+// histBucket returns index of bucket of latency v in seconds
+func histBucket(v float64) int {
+	if !(v > 0) {
+		return 0
+	}
+	ns := v * 1e9
+	if ns >= histMaxNs {
+		return histBuckets - 1
+	}
+	n := uint64(ns)
+	if n < histSub {
+		return int(n)
+	}
+	k := bits.Len64(n) - histSubBits
+	return histSub + (k-1)*histHalf + int(n>>k) - histHalf
+}
+
+// histValue returns latency in seconds in the middle of bucket i
+func histValue(i int) float64 {
+	if i < histSub {
+		return float64(i) / 1e9
+	}
+	k := (i-histSub)/histHalf + 1
+	lower := uint64((i-histSub)%histHalf+histHalf) << k
+	return (float64(lower) + float64(uint64(1)<<k)/2) / 1e9
+}
+
+// durationSummary is a prometheus.Summary of latency, which is observed by every worker without locks:
+// observation only increments counters of its bucket, and quantiles are calculated on read.
+// Like prometheus.Summary with default MaxAge, quantiles cover requests of the last 10 minutes
+type durationSummary struct {
+	desc       *prometheus.Desc
+	objectives []float64
+
+	// count is a number of observations and sum is their sum in nanoseconds
+	count, sum shards
+
+	// windows are histograms started every prometheus.DefMaxAge/prometheus.DefAgeBuckets from the oldest one.
+	// Every observation is counted by all of them, and quantiles are read from the oldest one
+	windows atomic.Pointer[[prometheus.DefAgeBuckets]*histogram]
+
+	// mu serializes rotation of windows by readers
+	mu sync.Mutex
+	// expires is a time, when the oldest window is replaced by new one
+	expires time.Time
+}
+
+func newDurationSummary(opts prometheus.SummaryOpts) *durationSummary {
+	s := &durationSummary{
+		desc:    prometheus.NewDesc(prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name), opts.Help, nil, opts.ConstLabels),
+		count:   newShards(),
+		sum:     newShards(),
+		expires: time.Now().Add(prometheus.DefMaxAge / prometheus.DefAgeBuckets),
+	}
+	for q := range opts.Objectives {
+		s.objectives = append(s.objectives, q)
+	}
+	sort.Float64s(s.objectives)
+	var ws [prometheus.DefAgeBuckets]*histogram
+	for i := range ws {
+		ws[i] = new(histogram)
+	}
+	s.windows.Store(&ws)
+	return s
+}
+
+func (s *durationSummary) Observe(v float64) {
+	i := histBucket(v)
+	for _, h := range s.windows.Load() {
+		h.counts[i].Add(1)
+	}
+	s.count.add(1)
+	if v > 0 {
+		s.sum.add(uint64(v * 1e9))
+	}
+}
+
+// rotate replaces windows, which are older than prometheus.DefMaxAge at now.
+// Windows are rotated on read, which happens every sample of loader
+func (s *durationSummary) rotate(now time.Time) {
+	period := prometheus.DefMaxAge / prometheus.DefAgeBuckets
+	if now.Sub(s.expires) >= prometheus.DefMaxAge {
+		// all windows are expired
+		var ws [prometheus.DefAgeBuckets]*histogram
+		for i := range ws {
+			ws[i] = new(histogram)
+		}
+		s.windows.Store(&ws)
+		s.expires = now.Add(period)
+		return
+	}
+	for !now.Before(s.expires) {
+		old := s.windows.Load()
+		var ws [prometheus.DefAgeBuckets]*histogram
+		copy(ws[:], old[1:])
+		ws[len(ws)-1] = new(histogram)
+		// late observations of replaced window are lost, which is harmless
+		s.windows.Store(&ws)
+		s.expires = s.expires.Add(period)
+	}
+}
+
+// quantiles returns values of objectives for the oldest window. Values are NaN if window is empty
+func (s *durationSummary) quantiles() []float64 {
+	s.mu.Lock()
+	s.rotate(time.Now())
+	h := s.windows.Load()[0]
+	s.mu.Unlock()
+
+	var total uint64
+	for i := range h.counts {
+		total += h.counts[i].Load()
+	}
+	values := make([]float64, len(s.objectives))
+	// i is the next bucket to add to cum, since buckets are walked once for all sorted objectives
+	i, cum := 0, uint64(0)
+	for j, q := range s.objectives {
+		if total == 0 {
+			values[j] = math.NaN()
+			continue
+		}
+		rank := uint64(math.Ceil(q * float64(total)))
+		if rank == 0 {
+			rank = 1
+		}
+		for cum < rank && i < histBuckets {
+			cum += h.counts[i].Load()
+			i++
+		}
+		values[j] = histValue(max(i-1, 0))
+	}
+	return values
+}
+
+func (s *durationSummary) Desc() *prometheus.Desc {
+	return s.desc
+}
+
+func (s *durationSummary) Write(m *dto.Metric) error {
+	values := s.quantiles()
+	qs := make([]*dto.Quantile, len(values))
+	for i := range values {
+		qs[i] = &dto.Quantile{Quantile: &s.objectives[i], Value: &values[i]}
+	}
+	count, sum := s.count.value(), float64(s.sum.value())/1e9
+	m.Summary = &dto.Summary{SampleCount: &count, SampleSum: &sum, Quantile: qs}
+	return nil
+}
+
+func (s *durationSummary) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.desc
+}
+
+func (s *durationSummary) Collect(ch chan<- prometheus.Metric) {
+	ch <- s
+}
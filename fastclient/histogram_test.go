@@ -0,0 +1,153 @@
+package fastclient
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestHistBucket(t *testing.T) {
+	f := func(v float64) {
+		t.Helper()
+		i := histBucket(v)
+		if i < 0 || i >= histBuckets {
+			t.Fatalf("Unexpected bucket of %v: %d", v, i)
+		}
+		if got := histValue(i); math.Abs(got-v) > v/128+1e-9 {
+			t.Errorf("Unexpected value of bucket of %v. Got: %v", v, got)
+		}
+	}
+	f(0)
+	f(1e-9)
+	f(127e-9)
+	f(128e-9)
+	f(255e-9)
+	f(1e-6)
+	f(1.5e-3)
+	f(0.1)
+	f(1)
+	f(30)
+	f(1000)
+
+	if i := histBucket(math.NaN()); i != 0 {
+		t.Errorf("Unexpected bucket of NaN: %d", i)
+	}
+	if i := histBucket(1e6); i != histBuckets-1 {
+		t.Errorf("Unexpected bucket of too long latency: %d", i)
+	}
+	// buckets are ordered by values
+	for i := 1; i < histBuckets; i++ {
+		if histValue(i) <= histValue(i-1) {
+			t.Fatalf("Value of bucket %d is %v, which isn't bigger than %v of previous one", i, histValue(i), histValue(i-1))
+		}
+	}
+}
+
+func TestDurationSummary(t *testing.T) {
+	s := newDurationSummary(prometheus.SummaryOpts{Name: "test_duration", Help: "Test duration", Objectives: durationObjectives})
+	m := &dto.Metric{}
+	s.Write(m)
+	if m.GetSummary().GetSampleCount() != 0 || !math.IsNaN(m.GetSummary().GetQuantile()[0].GetValue()) {
+		t.Fatalf("Unexpected summary without observations: %v", m)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	vs := make([]float64, 100000)
+	var sum float64
+	for i := range vs {
+		vs[i] = rnd.ExpFloat64() / 100
+		sum += vs[i]
+		s.Observe(vs[i])
+	}
+	sort.Float64s(vs)
+
+	m = &dto.Metric{}
+	if err := s.Write(m); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if n := m.GetSummary().GetSampleCount(); n != uint64(len(vs)) {
+		t.Fatalf("Unexpected count. Got: %d; Expected: %d", n, len(vs))
+	}
+	if got := m.GetSummary().GetSampleSum(); math.Abs(got-sum) > 1e-3 {
+		t.Fatalf("Unexpected sum. Got: %v; Expected: %v", got, sum)
+	}
+	qs := m.GetSummary().GetQuantile()
+	if len(qs) != len(durationObjectives) {
+		t.Fatalf("Unexpected number of quantiles. Got: %d; Expected: %d", len(qs), len(durationObjectives))
+	}
+	for i, q := range qs {
+		if i > 0 && q.GetQuantile() <= qs[i-1].GetQuantile() {
+			t.Fatalf("Quantiles aren't sorted: %v", qs)
+		}
+		expected := vs[int(math.Ceil(q.GetQuantile()*float64(len(vs))))-1]
+		if got := q.GetValue(); math.Abs(got-expected) > expected/100 {
+			t.Errorf("Unexpected p%v. Got: %v; Expected: %v", q.GetQuantile()*100, got, expected)
+		}
+	}
+
+	r := prometheus.NewRegistry()
+	r.MustRegister(s)
+	mfs, err := r.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error while gathering: %s", err)
+	}
+	if len(mfs) != 1 || mfs[0].GetName() != "test_duration" || mfs[0].GetType() != dto.MetricType_SUMMARY {
+		t.Fatalf("Unexpected gathered metrics: %v", mfs)
+	}
+}
+
+func TestDurationSummaryRotate(t *testing.T) {
+	s := newDurationSummary(prometheus.SummaryOpts{Name: "test_duration", Help: "Test duration", Objectives: map[float64]float64{0.5: 0.05}})
+	f := func(after time.Duration, v float64, expected float64) {
+		t.Helper()
+		s.mu.Lock()
+		s.rotate(s.expires.Add(after))
+		s.mu.Unlock()
+		if v > 0 {
+			s.Observe(v)
+		}
+		got := s.quantiles()[0]
+		if math.IsNaN(expected) != math.IsNaN(got) || !math.IsNaN(expected) && math.Abs(got-expected) > expected/100 {
+			t.Fatalf("Unexpected p50 after rotation. Got: %v; Expected: %v", got, expected)
+		}
+	}
+	s.Observe(1)
+	// observation is kept by all windows, which were started before it
+	f(0, 0, 1)
+	f(0, 0, 1)
+	f(0, 0, 1)
+	f(0, 0, 1)
+	f(0, 2, 2)
+	// observations older than max age are dropped
+	f(prometheus.DefMaxAge, 0, math.NaN())
+	if n := s.count.value(); n != 2 {
+		t.Fatalf("Unexpected count after rotation. Got: %d; Expected: 2", n)
+	}
+}
+
+func BenchmarkSummary(b *testing.B) {
+	f := func(name string, s prometheus.Summary) {
+		b.Helper()
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				v := 0.001
+				for pb.Next() {
+					s.Observe(v)
+					// latency of requests varies, so they are counted by different buckets
+					v += 1e-6
+					if v > 0.1 {
+						v = 0.001
+					}
+				}
+			})
+		})
+	}
+	opts := prometheus.SummaryOpts{Name: "test_duration", Help: "Test duration", Objectives: durationObjectives}
+	f("durationSummary", newDurationSummary(opts))
+	f("prometheus", prometheus.NewSummary(opts))
+}
@@ -0,0 +1,44 @@
+package fastclient
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+// Violations of HTTP/1.0 by responses to HTTP/1.0 requests
+const (
+	// HTTP10Unsupported means target responded with 505 HTTP Version Not Supported
+	HTTP10Unsupported = "unsupported"
+	// HTTP10Chunked means target responded with chunked body, which HTTP/1.0 clients can't read
+	HTTP10Chunked = "chunked"
+)
+
+var http10Labels = map[string]prometheus.Labels{
+	HTTP10Unsupported: {"violation": HTTP10Unsupported},
+	HTTP10Chunked:     {"violation": HTTP10Chunked},
+}
+
+// observeHTTP10 counts violations of HTTP/1.0 by response to HTTP/1.0 request
+func observeHTTP10(resp *fasthttp.Response) {
+	if resp.StatusCode() == fasthttp.StatusHTTPVersionNotSupported {
+		metrics.Load().http10Errors.With(http10Labels[HTTP10Unsupported]).Inc()
+	}
+	if resp.Header.ContentLength() == -1 {
+		metrics.Load().http10Errors.With(http10Labels[HTTP10Chunked]).Inc()
+	}
+}
+
+// HTTP10Errors returns map violation:value for http10Errors-metric where value
+// is a number of responses with such violation. Is empty if requests are sent over HTTP/1.1
+func (*Client) HTTP10Errors() map[string]uint64 {
+	result := make(map[string]uint64)
+	for name, label := range http10Labels {
+		m := &dto.Metric{}
+		metrics.Load().http10Errors.With(label).Write(m)
+		if n := uint64(*m.Counter.Value); n > 0 {
+			result[name] = n
+		}
+	}
+	return result
+}
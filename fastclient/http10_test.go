@@ -0,0 +1,54 @@
+package fastclient
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientHTTP10Errors(t *testing.T) {
+	ln := listenTest(t)
+	responses := []string{
+		"HTTP/1.0 200 OK\r\nContent-Length: 2\r\n\r\nok",
+		"HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n2\r\nok\r\n0\r\n\r\n",
+		"HTTP/1.1 505 HTTP Version Not Supported\r\nContent-Length: 0\r\n\r\n",
+	}
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			br := bufio.NewReader(conn)
+			for {
+				line, err := br.ReadString('\n')
+				if err != nil || strings.TrimSpace(line) == "" {
+					break
+				}
+			}
+			conn.Write([]byte(responses[i%len(responses)]))
+			conn.Close()
+		}
+	}()
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	req.Header.SetProtocol("HTTP/1.0")
+	req.Header.SetConnectionClose()
+	c := New(req, time.Second, fasthttp.StatusOK)
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 6; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 6)
+
+	expected := map[string]uint64{HTTP10Chunked: 2, HTTP10Unsupported: 2}
+	if got := c.HTTP10Errors(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected HTTP/1.0 errors. Got: %v; Expected: %v", got, expected)
+	}
+}
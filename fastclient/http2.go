@@ -0,0 +1,116 @@
+package fastclient
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// transport sends requests instead of fasthttp.HostClient.
+// Connections are dialed by client, so they are counted by the same metrics
+type transport interface {
+	do(req *fasthttp.Request, resp *fasthttp.Response) error
+}
+
+// http2Transport sends requests over HTTP/2.
+// net/http is used, since fasthttp doesn't support HTTP/2
+type http2Transport struct {
+	client  *http.Client
+	timeout time.Duration
+}
+
+// NewHTTP2 creates new client which sends request over HTTP/2.
+// Protocol is negotiated via ALPN for https and is known in advance (h2c) for http
+func NewHTTP2(request *fasthttp.Request, timeout time.Duration, sc int) *Client {
+	c := New(request, timeout, sc)
+	t := c.newHTTP2Transport()
+	// responses are decoded by client, so compressed bodies are counted
+	t.DisableCompression = true
+	c.transport = &http2Transport{
+		client:  &http.Client{Transport: t},
+		timeout: timeout,
+	}
+	return c
+}
+
+// newHTTP2Transport returns net/http transport, which supports only HTTP/2
+// and dials connections like HostClient of c
+func (c *Client) newHTTP2Transport() *http.Transport {
+	t := &http.Transport{
+		MaxIdleConnsPerHost: maxConns,
+		IdleConnTimeout:     maxIdleConnDuration,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return c.dialHost(addr)
+		},
+		DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			hc, err := c.dialHost(addr)
+			if err != nil {
+				return nil, err
+			}
+			return handshake(hc, c.TLSConfig, c.dialTimeout(), "h2")
+		},
+		Protocols: new(http.Protocols),
+	}
+	if c.IsTLS {
+		t.Protocols.SetHTTP2(true)
+	} else {
+		// prior knowledge, as there is no upgrade from HTTP/1.1 in net/http
+		t.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return t
+}
+
+// newHTTPRequest converts req to net/http request. Headers, which are specific
+// to HTTP/1.1 or are set by net/http, are skipped
+func newHTTPRequest(ctx context.Context, method string, req *fasthttp.Request) (*http.Request, error) {
+	hr, err := http.NewRequestWithContext(ctx, method, req.URI().String(), bytes.NewReader(req.Body()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.VisitAll(func(k, v []byte) {
+		switch string(k) {
+		case "Host", "Content-Length", "Connection", "Transfer-Encoding":
+			return
+		}
+		hr.Header.Add(string(k), string(v))
+	})
+	return hr, nil
+}
+
+// do sends req over HTTP/2 and fills resp with status code, headers and body of response
+func (t *http2Transport) do(req *fasthttp.Request, resp *fasthttp.Response) error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+	hr, err := newHTTPRequest(ctx, string(req.Header.Method()), req)
+	if err != nil {
+		return err
+	}
+	hr.Host = string(req.Host())
+
+	res, err := t.client.Do(hr)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	resp.Reset()
+	resp.SetStatusCode(res.StatusCode)
+	for k, values := range res.Header {
+		if k == "Content-Length" {
+			continue
+		}
+		for _, v := range values {
+			resp.Header.Add(k, v)
+		}
+	}
+	resp.SetBody(body)
+	return nil
+}
@@ -0,0 +1,91 @@
+package fastclient
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// serveH2C runs h2c server which echoes method and body of request,
+// and responds with 400 Bad Request to requests over other protocols
+func serveH2C(t *testing.T, delay time.Duration) net.Listener {
+	ln := listenTest(t)
+	s := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ProtoMajor != 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			time.Sleep(delay)
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Header().Set("X-Method", r.Method)
+			w.Write(body)
+		}),
+		Protocols: new(http.Protocols),
+	}
+	s.Protocols.SetUnencryptedHTTP2(true)
+	go s.Serve(ln)
+	return ln
+}
+
+func TestClientHTTP2(t *testing.T) {
+	ln := serveH2C(t, 0)
+	defer ln.Close()
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/echo")
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.SetBodyString("hello")
+	c := NewHTTP2(req, time.Second, fasthttp.StatusOK)
+	var resp fasthttp.Response
+	if err := c.transport.do(req, &resp); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if sc := resp.StatusCode(); sc != fasthttp.StatusOK {
+		t.Fatalf("Unexpected status code. Got: %d; Expected: %d", sc, fasthttp.StatusOK)
+	}
+	if got := string(resp.Header.Peek("X-Method")); got != fasthttp.MethodPost {
+		t.Errorf("Unexpected method. Got: %q; Expected: %q", got, fasthttp.MethodPost)
+	}
+	if got := string(resp.Body()); got != "hello" {
+		t.Errorf("Unexpected body. Got: %q; Expected: %q", got, "hello")
+	}
+
+	t.Cleanup(c.Flush)
+	c.RunWorkers(4)
+	const requests = 20
+	for i := 0; i < requests; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, requests)
+	if n := c.RequestSuccess(); n != requests {
+		t.Fatalf("Unexpected number of success requests. Got: %d; Expected: %d; errors: %v", n, requests, c.ErrorMessages())
+	}
+	if c.BytesWritten() == 0 || c.BytesRead() == 0 {
+		t.Errorf("Bytes weren't counted. Written: %d; Read: %d", c.BytesWritten(), c.BytesRead())
+	}
+	// requests are multiplexed over single connection
+	if n := c.ConnOpen(); n != 1 {
+		t.Errorf("Unexpected number of connections. Got: %d; Expected: %d", n, 1)
+	}
+}
+
+func TestClientHTTP2Timeout(t *testing.T) {
+	ln := serveH2C(t, time.Second)
+	defer ln.Close()
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := NewHTTP2(req, 100*time.Millisecond, fasthttp.StatusOK)
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	c.Jobsch <- time.Now()
+	waitRequests(t, c, 1)
+	if n := c.Timeouts(); n != 1 {
+		t.Errorf("Unexpected number of timeouts. Got: %d; Expected: %d; errors: %v", n, 1, c.ErrorMessages())
+	}
+}
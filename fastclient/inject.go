@@ -0,0 +1,23 @@
+package fastclient
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrInjectedDrop is returned for requests dropped because of Client.InjectDrop
+var ErrInjectedDrop = fmt.Errorf("request dropped by client-injected fault")
+
+// inject delays request which is about to be written to connection
+// and fails it with probability of drop. Connection of failed request is closed
+func (hc *hostConn) inject() error {
+	if hc.injectLatency > 0 {
+		time.Sleep(hc.injectLatency)
+	}
+	if hc.injectDrop > 0 && hc.rnd.Float64() < hc.injectDrop {
+		metrics.Load().injectedDrops.Inc()
+		hc.Close()
+		return ErrInjectedDrop
+	}
+	return nil
+}
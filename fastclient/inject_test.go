@@ -0,0 +1,43 @@
+package fastclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientInject(t *testing.T) {
+	f := func(latency time.Duration, drop float64, errs uint64) {
+		t.Helper()
+		ln := serveBackends(t, 1)
+		defer ln.Close()
+
+		req := new(fasthttp.Request)
+		req.SetRequestURI("http://" + ln.Addr().String() + "/")
+		c := New(req, time.Second, fasthttp.StatusOK)
+		c.InjectLatency = latency
+		c.InjectDrop = drop
+		t.Cleanup(c.Flush)
+		c.RunWorkers(1)
+		const requests = 5
+		for i := 0; i < requests; i++ {
+			c.Jobsch <- time.Now()
+		}
+
+		waitRequests(t, c, requests)
+
+		if n := c.Errors(); n != errs {
+			t.Errorf("Unexpected number of errors. Got: %d; Expected: %d", n, errs)
+		}
+		if n := c.InjectedDrops(); errs > 0 && n < errs {
+			t.Errorf("Unexpected number of injected drops. Got: %d; Expected at least: %d", n, errs)
+		}
+		if d := c.RequestDuration()[0.5]; d < latency.Seconds() {
+			t.Errorf("Unexpected median latency. Got: %fs; Expected at least: %s", d, latency)
+		}
+	}
+
+	f(50*time.Millisecond, 0, 0)
+	f(0, 1, 5)
+}
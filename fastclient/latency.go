@@ -0,0 +1,69 @@
+package fastclient
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// maxDuration, maxFirstDuration and maxCorrectedDuration contain bits of max latency in seconds
+// of requests, of first requests on connections and of requests measured from their intended send time
+var maxDuration, maxFirstDuration, maxCorrectedDuration uint64
+
+// Latency contains percentiles and max of latency of requests in seconds
+type Latency struct {
+	P50, P90, P95, P99, Max float64
+}
+
+// observeMax stores v to max if it exceeds stored value
+func observeMax(max *uint64, v float64) {
+	for {
+		old := atomic.LoadUint64(max)
+		if v <= math.Float64frombits(old) || atomic.CompareAndSwapUint64(max, old, math.Float64bits(v)) {
+			return
+		}
+	}
+}
+
+// latencyOf returns percentiles of s and max stored in max. Is zero if s has no observations
+func latencyOf(s prometheus.Summary, max *uint64) Latency {
+	m := &dto.Metric{}
+	s.Write(m)
+	if m.Summary.GetSampleCount() == 0 {
+		return Latency{}
+	}
+	l := Latency{Max: math.Float64frombits(atomic.LoadUint64(max))}
+	for _, q := range m.Summary.Quantile {
+		switch q.GetQuantile() {
+		case 0.5:
+			l.P50 = q.GetValue()
+		case 0.9:
+			l.P90 = q.GetValue()
+		case 0.95:
+			l.P95 = q.GetValue()
+		case 0.99:
+			l.P99 = q.GetValue()
+		}
+	}
+	return l
+}
+
+// Latency returns percentiles and max of latency of requests
+// for requestDuration-metric. Is zero if no requests were done
+func (*Client) Latency() Latency {
+	return latencyOf(metrics.Load().requestDuration, &maxDuration)
+}
+
+// FirstRequestLatency returns percentiles and max of latency of the first requests
+// on connections for firstRequestDuration-metric. Is zero if they weren't measured
+func (*Client) FirstRequestLatency() Latency {
+	return latencyOf(metrics.Load().firstRequestDuration, &maxFirstDuration)
+}
+
+// CorrectedLatency returns percentiles and max of latency of requests measured from their intended
+// send time for correctedDuration-metric. Is zero unless Client.RecordCorrected is true
+func (*Client) CorrectedLatency() Latency {
+	return latencyOf(metrics.Load().correctedDuration, &maxCorrectedDuration)
+}
@@ -0,0 +1,35 @@
+package fastclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientLatency(t *testing.T) {
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://127.0.0.1/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	if l := c.Latency(); l != (Latency{}) {
+		t.Fatalf("Unexpected latency without requests. Got: %+v; Expected: zero", l)
+	}
+
+	for i := 100; i > 0; i-- {
+		observeDuration(float64(i) / 100)
+	}
+	l := c.Latency()
+	if l.Max != 1 {
+		t.Errorf("Unexpected max latency. Got: %f; Expected: 1", l.Max)
+	}
+	f := func(name string, got, expected float64) {
+		t.Helper()
+		if got < expected-0.05 || got > expected+0.05 {
+			t.Errorf("Unexpected %s latency. Got: %f; Expected: %f", name, got, expected)
+		}
+	}
+	f("p50", l.P50, 0.5)
+	f("p90", l.P90, 0.9)
+	f("p95", l.P95, 0.95)
+	f("p99", l.P99, 0.99)
+}
@@ -0,0 +1,55 @@
+package fastclient
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+// dialTCP establishes TCP connection to addr. Connections are bound to
+// Client.LocalAddrs in round-robin if they are set
+func (c *Client) dialTCP(addr string, timeout time.Duration) (net.Conn, error) {
+	if len(c.LocalAddrs) == 0 {
+		return fasthttp.DialTimeout(addr, timeout)
+	}
+	n := atomic.AddUint32(&c.localDials, 1) - 1
+	ip := c.LocalAddrs[n%uint32(len(c.LocalAddrs))]
+	d := net.Dialer{LocalAddr: &net.TCPAddr{IP: ip}, Timeout: timeout}
+	conn, err := d.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c.observeLocalConn(ip.String())
+	return conn, nil
+}
+
+// observeLocalConn counts connection established from local ip
+func (c *Client) observeLocalConn(ip string) {
+	var label prometheus.Labels
+	var ok bool
+	c.Lock()
+	if label, ok = c.localIPLabels[ip]; !ok {
+		label = prometheus.Labels{"ip": ip}
+		c.localIPLabels[ip] = label
+	}
+	c.Unlock()
+	metrics.Load().localConns.With(label).Inc()
+}
+
+// LocalConns returns map ip:value for localConns-metric where value
+// is a number of connections established from local ip. Is empty unless LocalAddrs are set
+func (c *Client) LocalConns() map[string]uint64 {
+	result := make(map[string]uint64)
+	c.Lock()
+	defer c.Unlock()
+	for ip, label := range c.localIPLabels {
+		m := &dto.Metric{}
+		metrics.Load().localConns.With(label).Write(m)
+		result[ip] = uint64(*m.Counter.Value)
+	}
+	return result
+}
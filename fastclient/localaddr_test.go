@@ -0,0 +1,48 @@
+package fastclient
+
+import (
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientLocalAddrs(t *testing.T) {
+	var mu sync.Mutex
+	sources := make(map[string]int)
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		mu.Lock()
+		sources[host]++
+		mu.Unlock()
+		w.Header().Set("Connection", "close")
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.LocalAddrs = []net.IP{net.ParseIP("127.0.0.2"), net.ParseIP("127.0.0.3")}
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 4; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 4)
+
+	if n := c.RequestSuccess(); n != 4 {
+		t.Fatalf("Unexpected number of successful requests. Got: %d; Expected: 4", n)
+	}
+	expected := map[string]uint64{"127.0.0.2": 2, "127.0.0.3": 2}
+	if got := c.LocalConns(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected connections by local IP. Got: %v; Expected: %v", got, expected)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(sources, map[string]int{"127.0.0.2": 2, "127.0.0.3": 2}) {
+		t.Errorf("Unexpected sources of requests: %v", sources)
+	}
+}
@@ -1,29 +1,111 @@
 package fastclient
 
 import (
+	"sync/atomic"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 )
 
-var (
-	connOpen        prometheus.Gauge
-	statusCodes     *prometheus.CounterVec
-	errorMessages   *prometheus.CounterVec
-	requestDuration prometheus.Summary
-
-	timeouts       prometheus.Counter
-	errors         prometheus.Counter
-	requestSum     prometheus.Counter
-	requestSuccess prometheus.Counter
-	connError      prometheus.Counter
-	bytesWritten   prometheus.Counter
-	bytesRead      prometheus.Counter
-	writeError     prometheus.Counter
-	readError      prometheus.Counter
-)
+// metricSet contains metrics of requests and connections. Set is replaced as a whole by flushMetrics,
+// so workers, which are running during reset, count requests by either old or new set without races
+type metricSet struct {
+	connOpen           prometheus.Gauge
+	statusCodes        *prometheus.CounterVec
+	errorMessages      *prometheus.CounterVec
+	errorClasses       *prometheus.CounterVec
+	grpcStatusCodes    *prometheus.CounterVec
+	backends           *prometheus.CounterVec
+	echoResults        *prometheus.CounterVec
+	assertFailures     *prometheus.CounterVec
+	connIPs            *prometheus.CounterVec
+	localConns         *prometheus.CounterVec
+	http10Errors       *prometheus.CounterVec
+	throttledResponses *prometheus.CounterVec
+	targetRequests     *prometheus.CounterVec
+	targetErrors       *prometheus.CounterVec
+	targetCapped       *prometheus.CounterVec
+	targetDuration     *prometheus.SummaryVec
+	requestDuration    prometheus.Summary
+	sizeDuration       *prometheus.SummaryVec
+	regionDuration     *prometheus.SummaryVec
+
+	// requestDurationHistogram duplicates requestDuration by buckets,
+	// so latency of scraped loaders could be aggregated by histogram_quantile
+	requestDurationHistogram prometheus.Histogram
+
+	timeouts        prometheus.Counter
+	errors          prometheus.Counter
+	requestSum      prometheus.Counter
+	requestSuccess  prometheus.Counter
+	connError       prometheus.Counter
+	bytesWritten    prometheus.Counter
+	bytesRead       prometheus.Counter
+	writeError      prometheus.Counter
+	readError       prometheus.Counter
+	portExhausted   prometheus.Counter
+	affinityBreaks  prometheus.Counter
+	proxyAuthErrors prometheus.Counter
+	injectedDrops   prometheus.Counter
+	uploadStalls    prometheus.Counter
+	retries         prometheus.Counter
+	jobsQueued      prometheus.Counter
+	jobsDone        prometheus.Counter
+	jobsWaited      prometheus.Counter
+	queueFull       prometheus.Counter
+
+	compressedResponses prometheus.Counter
+	bytesCompressed     prometheus.Counter
+	bytesDecoded        prometheus.Counter
+
+	dnsLookups prometheus.Counter
+	dnsChanges prometheus.Counter
+
+	compressedRequests       prometheus.Counter
+	requestBytesCompressed   prometheus.Counter
+	requestBytesUncompressed prometheus.Counter
+
+	thinkTime prometheus.Counter
+
+	wsConnects        prometheus.Counter
+	wsConnectFailures prometheus.Counter
+	wsDrops           prometheus.Counter
+
+	connectTimeouts   prometheus.Counter
+	firstByteTimeouts prometheus.Counter
+	bodyReadTimeouts  prometheus.Counter
+
+	warmedConns          prometheus.Counter
+	firstRequestDuration prometheus.Summary
+	queueWait            prometheus.Summary
+	correctedDuration    prometheus.Summary
 
-func initMetrics() {
-	statusCodes = prometheus.NewCounterVec(
+	connectDuration   prometheus.Summary
+	handshakeDuration prometheus.Summary
+	dnsDuration       prometheus.Summary
+	sendDuration      prometheus.Summary
+	firstByteDuration prometheus.Summary
+	transferDuration  prometheus.Summary
+}
+
+// metrics is a current set of metrics
+var metrics atomic.Pointer[metricSet]
+
+// stepDuration contains prometheus.Summary with latency of current step.
+// Is replaced by new summary on every step
+var stepDuration atomic.Value
+
+var durationObjectives = map[float64]float64{0.5: 0.05, 0.75: 0.025, 0.8: 0.02, 0.9: 0.01, 0.95: 0.005, 0.99: 0.001}
+
+// durationBuckets are buckets of requestDurationHistogram from 100us to ~52s
+var durationBuckets = prometheus.ExponentialBuckets(0.0001, 2, 20)
+
+// newMetricSet creates metrics. Counters and latency of every request are sharded ones and durationSummary,
+// so accounting of parallel workers doesn't contend on locks of prometheus metrics
+func newMetricSet() *metricSet {
+	m := &metricSet{}
+	m.statusCodes = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "status_codes",
 			Help: "Distribution by status codes counter",
@@ -31,7 +113,7 @@ func initMetrics() {
 		[]string{"code"},
 	)
 
-	errorMessages = prometheus.NewCounterVec(
+	m.errorMessages = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "errors",
 			Help: "Distribution by error messages",
@@ -39,169 +121,743 @@ func initMetrics() {
 		[]string{"message"},
 	)
 
-	timeouts = prometheus.NewCounter(
+	m.errorClasses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "error_classes",
+			Help: "Distribution of errors by class like timeout, dns, tls or connection refused",
+		},
+		[]string{"class"},
+	)
+
+	m.grpcStatusCodes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_status_codes",
+			Help: "Distribution by gRPC status codes counter",
+		},
+		[]string{"status"},
+	)
+
+	m.backends = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "backends",
+			Help: "Distribution by ids of backends which served requests",
+		},
+		[]string{"backend"},
+	)
+
+	m.connIPs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "conn_ips",
+			Help: "Distribution of established connections by remote IP",
+		},
+		[]string{"ip"},
+	)
+
+	m.localConns = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "local_conns",
+			Help: "Distribution of established connections by local IP",
+		},
+		[]string{"ip"},
+	)
+
+	m.targetRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "target_requests",
+			Help: "Distribution of requests by target url",
+		},
+		[]string{"target"},
+	)
+
+	m.targetErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "target_errors",
+			Help: "Distribution of failed requests by target url",
+		},
+		[]string{"target"},
+	)
+
+	m.targetCapped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "target_capped",
+			Help: "Distribution of requests sent to other targets, since max rate of target was reached, by target url",
+		},
+		[]string{"target"},
+	)
+
+	m.targetDuration = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:       "target_request_duration",
+			Help:       "Latency of requests by target url",
+			Objectives: durationObjectives,
+		},
+		[]string{"target"},
+	)
+
+	m.http10Errors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http10_errors",
+			Help: "Number of responses to HTTP/1.0 requests by violation: unsupported or chunked",
+		},
+		[]string{"violation"},
+	)
+
+	m.throttledResponses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "throttled_responses",
+			Help: "Number of throttled responses by status: 429 or 503 with Retry-After",
+		},
+		[]string{"status"},
+	)
+
+	m.echoResults = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "echo_results",
+			Help: "Distribution of responses by result of echo check: ok, missing or mismatch",
+		},
+		[]string{"result"},
+	)
+
+	m.assertFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "assertion_failures",
+			Help: "Number of complete responses which failed assertion by its name",
+		},
+		[]string{"assertion"},
+	)
+
+	m.timeouts = newShardedCounter(
 		prometheus.CounterOpts{
 			Name: "request_timeouts",
 			Help: "Number of timeouts returned by server",
 		},
 	)
 
-	errors = prometheus.NewCounter(
+	m.errors = newShardedCounter(
 		prometheus.CounterOpts{
 			Name: "request_errors",
 			Help: "Number of errors returned by server. Including amount of timeouts",
 		},
 	)
 
-	requestSum = prometheus.NewCounter(
+	m.requestSum = newShardedCounter(
 		prometheus.CounterOpts{
 			Name: "request_sum",
 			Help: "Total number of sent requests",
 		},
 	)
 
-	requestSuccess = prometheus.NewCounter(
+	m.requestSuccess = newShardedCounter(
 		prometheus.CounterOpts{
 			Name: "request_success",
 			Help: "Total number of sent success requests",
 		},
 	)
 
-	requestDuration = prometheus.NewSummary(
+	m.requestDuration = newDurationSummary(
 		prometheus.SummaryOpts{
 			Name:       "request_duration",
 			Help:       "Latency of sent requests",
-			Objectives: map[float64]float64{0.5: 0.05, 0.75: 0.025, 0.8: 0.02, 0.9: 0.01, 0.99: 0.001},
+			Objectives: durationObjectives,
+		},
+	)
+	m.requestDurationHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "request_duration_seconds",
+			Help:    "Histogram of latency of sent requests",
+			Buckets: durationBuckets,
+		},
+	)
+
+	m.sizeDuration = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:       "size_request_duration",
+			Help:       "Latency of requests by size of response body",
+			Objectives: durationObjectives,
+		},
+		[]string{"size"},
+	)
+
+	m.regionDuration = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:       "region_request_duration",
+			Help:       "Latency of requests by simulated region of client",
+			Objectives: durationObjectives,
 		},
+		[]string{"region"},
 	)
 
-	connOpen = prometheus.NewGauge(
+	m.warmedConns = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "warmed_conns",
+			Help: "Number of warmed up connections",
+		},
+	)
+
+	m.firstRequestDuration = newDurationSummary(
+		prometheus.SummaryOpts{
+			Name:       "first_request_duration",
+			Help:       "Latency of the first request on connection",
+			Objectives: durationObjectives,
+		},
+	)
+
+	m.queueWait = newDurationSummary(
+		prometheus.SummaryOpts{
+			Name:       "queue_wait",
+			Help:       "Time which requests waited in job queue before being sent",
+			Objectives: durationObjectives,
+		},
+	)
+
+	m.correctedDuration = newDurationSummary(
+		prometheus.SummaryOpts{
+			Name:       "corrected_request_duration",
+			Help:       "Latency of requests measured from their intended send time",
+			Objectives: durationObjectives,
+		},
+	)
+
+	m.connectDuration = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Name:       "connect_duration",
+			Help:       "Time of TCP connect",
+			Objectives: durationObjectives,
+		},
+	)
+	m.handshakeDuration = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Name:       "handshake_duration",
+			Help:       "Time of TLS handshake",
+			Objectives: durationObjectives,
+		},
+	)
+	m.dnsDuration = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Name:       "dns_duration",
+			Help:       "Time of DNS lookup of host of new connections",
+			Objectives: durationObjectives,
+		},
+	)
+	m.sendDuration = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Name:       "send_duration",
+			Help:       "Time from start of request to its last byte written, including setup of new connection",
+			Objectives: durationObjectives,
+		},
+	)
+	m.firstByteDuration = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Name:       "first_byte_duration",
+			Help:       "Time from the last byte of request written to the first byte of response read",
+			Objectives: durationObjectives,
+		},
+	)
+	m.transferDuration = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Name:       "transfer_duration",
+			Help:       "Time from the first byte of response read to the complete response",
+			Objectives: durationObjectives,
+		},
+	)
+
+	m.connectTimeouts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "connect_timeouts",
+			Help: "Number of timeouts while establishing connection",
+		},
+	)
+
+	m.firstByteTimeouts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "first_byte_timeouts",
+			Help: "Number of timeouts while waiting for the first byte of response",
+		},
+	)
+
+	m.bodyReadTimeouts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "body_read_timeouts",
+			Help: "Number of timeouts while reading the rest of response",
+		},
+	)
+
+	m.connOpen = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "conn_open",
 			Help: "Number of open connections",
 		},
 	)
 
-	connError = prometheus.NewCounter(
+	m.connError = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: "conn_errors",
 			Help: "Number of connections ended with error",
 		},
 	)
 
-	bytesWritten = prometheus.NewCounter(
+	m.bytesWritten = newShardedCounter(
 		prometheus.CounterOpts{
 			Name: "bytes_written",
 			Help: "Amount of written bytes",
 		},
 	)
 
-	bytesRead = prometheus.NewCounter(
+	m.bytesRead = newShardedCounter(
 		prometheus.CounterOpts{
 			Name: "bytes_read",
 			Help: "Amount of read bytes",
 		},
 	)
 
-	writeError = prometheus.NewCounter(
+	m.writeError = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: "request_write_errors",
 			Help: "Number of errors while writing",
 		},
 	)
 
-	readError = prometheus.NewCounter(
+	m.readError = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: "request_read_errors",
 			Help: "Number of errors while reading",
 		},
 	)
+
+	m.portExhausted = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "port_exhausted",
+			Help: "Number of requests not sent because of lack of free local ports",
+		},
+	)
+
+	m.proxyAuthErrors = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "proxy_auth_errors",
+			Help: "Number of connections declined by proxy because of credentials",
+		},
+	)
+
+	m.uploadStalls = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "upload_stalls",
+			Help: "Number of timeouts while writing requests throttled by upload rate",
+		},
+	)
+
+	m.retries = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "retries",
+			Help: "Number of requests sent again because of status code of response",
+		},
+	)
+
+	m.jobsQueued = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "jobs_queued",
+			Help: "Number of jobs sent to queue of workers",
+		},
+	)
+
+	m.jobsDone = newShardedCounter(
+		prometheus.CounterOpts{
+			Name: "jobs_done",
+			Help: "Number of jobs finished by workers: requests sent or failed before being sent",
+		},
+	)
+
+	m.jobsWaited = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "jobs_waited",
+			Help: "Number of jobs queued behind other jobs, since every worker was busy",
+		},
+	)
+
+	m.queueFull = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "job_queue_full",
+			Help: "Number of jobs, which waited for free space in full queue of workers",
+		},
+	)
+
+	m.injectedDrops = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "injected_drops",
+			Help: "Number of requests dropped by client-injected fault",
+		},
+	)
+
+	m.compressedResponses = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "compressed_responses",
+			Help: "Number of responses with compressed body",
+		},
+	)
+
+	m.bytesCompressed = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "bytes_compressed",
+			Help: "Size of compressed bodies of responses as they were read from wire",
+		},
+	)
+
+	m.bytesDecoded = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "bytes_decoded",
+			Help: "Size of decoded bodies of compressed responses",
+		},
+	)
+
+	m.dnsLookups = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "dns_lookups",
+			Help: "Number of DNS lookups of hosts of new connections made by client, including failed ones",
+		},
+	)
+
+	m.dnsChanges = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "dns_changes",
+			Help: "Number of times resolved IPs of host changed after re-resolution",
+		},
+	)
+
+	m.compressedRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "compressed_requests",
+			Help: "Number of requests with body compressed by client",
+		},
+	)
+
+	m.requestBytesCompressed = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "request_bytes_compressed",
+			Help: "Size of compressed bodies of requests as they were written to wire",
+		},
+	)
+
+	m.requestBytesUncompressed = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "request_bytes_uncompressed",
+			Help: "Size of bodies of compressed requests before compression",
+		},
+	)
+
+	m.thinkTime = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "think_time_seconds",
+			Help: "Total time workers paused between response and their next request",
+		},
+	)
+
+	m.wsConnects = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ws_connections",
+			Help: "Number of established WebSocket connections",
+		},
+	)
+
+	m.wsConnectFailures = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ws_connection_failures",
+			Help: "Number of WebSocket connections, which failed to be established or upgraded",
+		},
+	)
+
+	m.wsDrops = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ws_dropped_connections",
+			Help: "Number of WebSocket connections closed by target or broken while waiting for message",
+		},
+	)
+
+	m.affinityBreaks = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "affinity_breaks",
+			Help: "Number of requests served by other backend than previous request of the same worker",
+		},
+	)
+	return m
 }
 
-func registerMetrics() {
-	initMetrics()
-	prometheus.MustRegister(timeouts)
-	prometheus.MustRegister(errors)
-	prometheus.MustRegister(requestSum)
-	prometheus.MustRegister(requestDuration)
-	prometheus.MustRegister(connOpen)
-	prometheus.MustRegister(connError)
-	prometheus.MustRegister(bytesWritten)
-	prometheus.MustRegister(bytesRead)
-	prometheus.MustRegister(writeError)
-	prometheus.MustRegister(readError)
-	prometheus.MustRegister(statusCodes)
-	prometheus.MustRegister(errorMessages)
-}
-
-func unregisterMetrics() {
-	prometheus.Unregister(timeouts)
-	prometheus.Unregister(errors)
-	prometheus.Unregister(requestSum)
-	prometheus.Unregister(requestSuccess)
-	prometheus.Unregister(requestDuration)
-	prometheus.Unregister(connOpen)
-	prometheus.Unregister(connError)
-	prometheus.Unregister(bytesWritten)
-	prometheus.Unregister(bytesRead)
-	prometheus.Unregister(writeError)
-	prometheus.Unregister(readError)
-	prometheus.Unregister(statusCodes)
-	prometheus.Unregister(errorMessages)
+func registerMetrics(m *metricSet) {
+	prometheus.MustRegister(m.timeouts)
+	prometheus.MustRegister(m.errors)
+	prometheus.MustRegister(m.requestSum)
+	prometheus.MustRegister(m.requestDuration)
+	prometheus.MustRegister(m.requestDurationHistogram)
+	prometheus.MustRegister(m.sizeDuration)
+	prometheus.MustRegister(m.regionDuration)
+	prometheus.MustRegister(m.connOpen)
+	prometheus.MustRegister(m.connError)
+	prometheus.MustRegister(m.bytesWritten)
+	prometheus.MustRegister(m.bytesRead)
+	prometheus.MustRegister(m.writeError)
+	prometheus.MustRegister(m.readError)
+	prometheus.MustRegister(m.portExhausted)
+	prometheus.MustRegister(m.affinityBreaks)
+	prometheus.MustRegister(m.proxyAuthErrors)
+	prometheus.MustRegister(m.injectedDrops)
+	prometheus.MustRegister(m.compressedResponses)
+	prometheus.MustRegister(m.bytesCompressed)
+	prometheus.MustRegister(m.bytesDecoded)
+	prometheus.MustRegister(m.dnsLookups)
+	prometheus.MustRegister(m.dnsChanges)
+	prometheus.MustRegister(m.compressedRequests)
+	prometheus.MustRegister(m.thinkTime)
+	prometheus.MustRegister(m.wsConnects)
+	prometheus.MustRegister(m.wsConnectFailures)
+	prometheus.MustRegister(m.wsDrops)
+	prometheus.MustRegister(m.requestBytesCompressed)
+	prometheus.MustRegister(m.requestBytesUncompressed)
+	prometheus.MustRegister(m.uploadStalls)
+	prometheus.MustRegister(m.retries)
+	prometheus.MustRegister(m.jobsQueued)
+	prometheus.MustRegister(m.jobsDone)
+	prometheus.MustRegister(m.jobsWaited)
+	prometheus.MustRegister(m.queueFull)
+	prometheus.MustRegister(m.connectTimeouts)
+	prometheus.MustRegister(m.firstByteTimeouts)
+	prometheus.MustRegister(m.bodyReadTimeouts)
+	prometheus.MustRegister(m.warmedConns)
+	prometheus.MustRegister(m.firstRequestDuration)
+	prometheus.MustRegister(m.queueWait)
+	prometheus.MustRegister(m.correctedDuration)
+	prometheus.MustRegister(m.connectDuration)
+	prometheus.MustRegister(m.handshakeDuration)
+	prometheus.MustRegister(m.dnsDuration)
+	prometheus.MustRegister(m.sendDuration)
+	prometheus.MustRegister(m.firstByteDuration)
+	prometheus.MustRegister(m.transferDuration)
+	prometheus.MustRegister(m.statusCodes)
+	prometheus.MustRegister(m.errorMessages)
+	prometheus.MustRegister(m.errorClasses)
+	prometheus.MustRegister(m.grpcStatusCodes)
+	prometheus.MustRegister(m.backends)
+	prometheus.MustRegister(m.echoResults)
+	prometheus.MustRegister(m.assertFailures)
+	prometheus.MustRegister(m.connIPs)
+	prometheus.MustRegister(m.localConns)
+	prometheus.MustRegister(m.http10Errors)
+	prometheus.MustRegister(m.throttledResponses)
+	prometheus.MustRegister(m.targetRequests)
+	prometheus.MustRegister(m.targetErrors)
+	prometheus.MustRegister(m.targetCapped)
+	prometheus.MustRegister(m.targetDuration)
 }
 
-func flushMetrics() {
-	unregisterMetrics()
-	registerMetrics()
+func unregisterMetrics(m *metricSet) {
+	prometheus.Unregister(m.timeouts)
+	prometheus.Unregister(m.errors)
+	prometheus.Unregister(m.requestSum)
+	prometheus.Unregister(m.requestSuccess)
+	prometheus.Unregister(m.requestDuration)
+	prometheus.Unregister(m.requestDurationHistogram)
+	prometheus.Unregister(m.sizeDuration)
+	prometheus.Unregister(m.regionDuration)
+	prometheus.Unregister(m.connOpen)
+	prometheus.Unregister(m.connError)
+	prometheus.Unregister(m.bytesWritten)
+	prometheus.Unregister(m.bytesRead)
+	prometheus.Unregister(m.injectedDrops)
+	prometheus.Unregister(m.compressedResponses)
+	prometheus.Unregister(m.bytesCompressed)
+	prometheus.Unregister(m.bytesDecoded)
+	prometheus.Unregister(m.dnsLookups)
+	prometheus.Unregister(m.dnsChanges)
+	prometheus.Unregister(m.compressedRequests)
+	prometheus.Unregister(m.thinkTime)
+	prometheus.Unregister(m.wsConnects)
+	prometheus.Unregister(m.wsConnectFailures)
+	prometheus.Unregister(m.wsDrops)
+	prometheus.Unregister(m.requestBytesCompressed)
+	prometheus.Unregister(m.requestBytesUncompressed)
+	prometheus.Unregister(m.uploadStalls)
+	prometheus.Unregister(m.retries)
+	prometheus.Unregister(m.jobsQueued)
+	prometheus.Unregister(m.jobsDone)
+	prometheus.Unregister(m.jobsWaited)
+	prometheus.Unregister(m.queueFull)
+	prometheus.Unregister(m.writeError)
+	prometheus.Unregister(m.readError)
+	prometheus.Unregister(m.portExhausted)
+	prometheus.Unregister(m.affinityBreaks)
+	prometheus.Unregister(m.proxyAuthErrors)
+	prometheus.Unregister(m.connectTimeouts)
+	prometheus.Unregister(m.firstByteTimeouts)
+	prometheus.Unregister(m.bodyReadTimeouts)
+	prometheus.Unregister(m.warmedConns)
+	prometheus.Unregister(m.firstRequestDuration)
+	prometheus.Unregister(m.queueWait)
+	prometheus.Unregister(m.correctedDuration)
+	prometheus.Unregister(m.connectDuration)
+	prometheus.Unregister(m.handshakeDuration)
+	prometheus.Unregister(m.dnsDuration)
+	prometheus.Unregister(m.sendDuration)
+	prometheus.Unregister(m.firstByteDuration)
+	prometheus.Unregister(m.transferDuration)
+	prometheus.Unregister(m.statusCodes)
+	prometheus.Unregister(m.errorMessages)
+	prometheus.Unregister(m.errorClasses)
+	prometheus.Unregister(m.grpcStatusCodes)
+	prometheus.Unregister(m.backends)
+	prometheus.Unregister(m.echoResults)
+	prometheus.Unregister(m.assertFailures)
+	prometheus.Unregister(m.connIPs)
+	prometheus.Unregister(m.localConns)
+	prometheus.Unregister(m.http10Errors)
+	prometheus.Unregister(m.throttledResponses)
+	prometheus.Unregister(m.targetRequests)
+	prometheus.Unregister(m.targetErrors)
+	prometheus.Unregister(m.targetCapped)
+	prometheus.Unregister(m.targetDuration)
 }
 
-var m = &dto.Metric{}
+// flushMetrics replaces current set of metrics by new one
+// and resets max latency and latency of current step
+func flushMetrics() {
+	if old := metrics.Load(); old != nil {
+		unregisterMetrics(old)
+	}
+	m := newMetricSet()
+	registerMetrics(m)
+	stepDuration.Store(newStepDuration())
+	atomic.StoreUint64(&maxDuration, 0)
+	atomic.StoreUint64(&maxFirstDuration, 0)
+	atomic.StoreUint64(&maxCorrectedDuration, 0)
+	atomic.StoreInt64(&maxRetryAfter, 0)
+	atomic.StoreUint64(&maxConnectDuration, 0)
+	atomic.StoreUint64(&maxHandshakeDuration, 0)
+	atomic.StoreUint64(&maxDNSDuration, 0)
+	atomic.StoreUint64(&maxSendDuration, 0)
+	atomic.StoreUint64(&maxFirstByteDuration, 0)
+	atomic.StoreUint64(&maxTransferDuration, 0)
+	metrics.Store(m)
+}
 
 // Errors returns value of errors-metric
 func (*Client) Errors() uint64 {
-	errors.Write(m)
+	m := &dto.Metric{}
+	metrics.Load().errors.Write(m)
 	return uint64(*m.Counter.Value)
 }
 
 // Timeouts returns value of timeouts-metric
 func (*Client) Timeouts() uint64 {
-	timeouts.Write(m)
+	m := &dto.Metric{}
+	metrics.Load().timeouts.Write(m)
 	return uint64(*m.Counter.Value)
 }
 
 // RequestSum returns value of requestSum-metric
 func (*Client) RequestSum() uint64 {
-	requestSum.Write(m)
+	m := &dto.Metric{}
+	metrics.Load().requestSum.Write(m)
 	return uint64(*m.Counter.Value)
 }
 
 // RequestSuccess returns value of requestSuccess-metric
 func (*Client) RequestSuccess() uint64 {
-	requestSuccess.Write(m)
+	m := &dto.Metric{}
+	metrics.Load().requestSuccess.Write(m)
 	return uint64(*m.Counter.Value)
 }
 
 // BytesWritten returns value of bytesWritten-metric
 func (*Client) BytesWritten() uint64 {
-	bytesWritten.Write(m)
+	m := &dto.Metric{}
+	metrics.Load().bytesWritten.Write(m)
 	return uint64(*m.Counter.Value)
 }
 
 // BytesRead returns value of bytesRead-metric
 func (*Client) BytesRead() uint64 {
-	bytesRead.Write(m)
+	m := &dto.Metric{}
+	metrics.Load().bytesRead.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// ReadErrors returns value of readError-metric
+func (*Client) ReadErrors() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().readError.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// WriteErrors returns value of writeError-metric
+func (*Client) WriteErrors() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().writeError.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// PortExhausted returns value of portExhausted-metric
+func (*Client) PortExhausted() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().portExhausted.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// ConnectTimeouts returns value of connectTimeouts-metric
+func (*Client) ConnectTimeouts() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().connectTimeouts.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// FirstByteTimeouts returns value of firstByteTimeouts-metric
+func (*Client) FirstByteTimeouts() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().firstByteTimeouts.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// BodyReadTimeouts returns value of bodyReadTimeouts-metric
+func (*Client) BodyReadTimeouts() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().bodyReadTimeouts.Write(m)
 	return uint64(*m.Counter.Value)
 }
 
 // ConnOpen returns value of connOpen-metric
 func (*Client) ConnOpen() uint64 {
-	connOpen.Write(m)
+	m := &dto.Metric{}
+	metrics.Load().connOpen.Write(m)
 	return uint64(*m.Gauge.Value)
 }
 
 // RequestDuration returns map quantile:value for requestDuration-metric
 func (*Client) RequestDuration() map[float64]float64 {
-	requestDuration.Write(m)
+	return quantiles(metrics.Load().requestDuration)
+}
+
+// FirstRequestDuration returns map quantile:value for firstRequestDuration-metric.
+// Is measured only for warmed up connections or if Client.SeparateFirstRequests is true
+func (*Client) FirstRequestDuration() map[float64]float64 {
+	return quantiles(metrics.Load().firstRequestDuration)
+}
+
+// QueueWait returns map quantile:value for queueWait-metric
+func (*Client) QueueWait() map[float64]float64 {
+	return quantiles(metrics.Load().queueWait)
+}
+
+// CorrectedDuration returns map quantile:value for correctedDuration-metric.
+// Is measured only if Client.RecordCorrected is true
+func (*Client) CorrectedDuration() map[float64]float64 {
+	return quantiles(metrics.Load().correctedDuration)
+}
+
+// WarmedConns returns value of warmedConns-metric
+func (*Client) WarmedConns() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().warmedConns.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+func quantiles(s prometheus.Summary) map[float64]float64 {
+	m := &dto.Metric{}
+	s.Write(m)
 	result := make(map[float64]float64, len(m.Summary.Quantile))
 	for _, v := range m.Summary.Quantile {
 		result[*v.Quantile] = *v.Value
@@ -210,27 +866,233 @@ func (*Client) RequestDuration() map[float64]float64 {
 	return result
 }
 
+func newStepDuration() prometheus.Summary {
+	return newDurationSummary(
+		prometheus.SummaryOpts{
+			Name:       "step_request_duration",
+			Help:       "Latency of requests sent during current step",
+			Objectives: durationObjectives,
+		},
+	)
+}
+
+func observeFirstDuration(v float64) {
+	metrics.Load().firstRequestDuration.Observe(v)
+	observeMax(&maxFirstDuration, v)
+}
+
+func observeCorrectedDuration(v float64) {
+	metrics.Load().correctedDuration.Observe(v)
+	observeMax(&maxCorrectedDuration, v)
+}
+
+func observeDuration(v float64) {
+	m := metrics.Load()
+	m.requestDuration.Observe(v)
+	m.requestDurationHistogram.Observe(v)
+	observeMax(&maxDuration, v)
+	stepDuration.Load().(prometheus.Summary).Observe(v)
+}
+
+// NextStep returns map quantile:value of latency of requests
+// sent since previous call and starts measuring of the next step
+func (*Client) NextStep() map[float64]float64 {
+	s := stepDuration.Load().(prometheus.Summary)
+	stepDuration.Store(newStepDuration())
+	var sm dto.Metric
+	s.Write(&sm)
+	result := make(map[float64]float64, len(sm.Summary.Quantile))
+	for _, v := range sm.Summary.Quantile {
+		result[*v.Quantile] = *v.Value
+	}
+
+	return result
+}
+
 // StatusCodes returns map statusCode:value for statusCodes-metric
 // where value is an percent of total number of requests
 func (c *Client) StatusCodes() map[string]float64 {
 	result := make(map[string]float64)
 	total := float64(c.RequestSum())
+	c.Lock()
+	defer c.Unlock()
 	for _, label := range c.statusCodeLabels {
-		statusCodes.With(label).Write(m)
+		m := &dto.Metric{}
+		metrics.Load().statusCodes.With(label).Write(m)
 		result[m.GetLabel()[0].GetValue()] = (*m.Counter.Value / total) * 100
 
 	}
 	return result
 }
 
+// StatusCounts returns number of responses by status code
+func (c *Client) StatusCounts() map[int]uint64 {
+	result := make(map[int]uint64)
+	c.Lock()
+	defer c.Unlock()
+	for code, label := range c.statusCodeLabels {
+		m := &dto.Metric{}
+		metrics.Load().statusCodes.With(label).Write(m)
+		result[code] = uint64(*m.Counter.Value)
+	}
+	return result
+}
+
 // ErrorMessages returns map errorMessage:value for errorMessages-metric
 // where value is a number of errors with same message
 func (c *Client) ErrorMessages() map[string]int {
 	result := make(map[string]int)
 	for _, label := range c.errorMessages {
-		errorMessages.With(label).Write(m)
+		m := &dto.Metric{}
+		metrics.Load().errorMessages.With(label).Write(m)
 		result[m.GetLabel()[0].GetValue()] = int(*m.Counter.Value)
 
 	}
 	return result
 }
+
+// GRPCStatusCodes returns map status:value for grpcStatusCodes-metric
+// where value is an percent of total number of requests. Is empty if client wasn't created by NewGRPC
+func (c *Client) GRPCStatusCodes() map[string]float64 {
+	result := make(map[string]float64)
+	total := float64(c.RequestSum())
+	c.Lock()
+	defer c.Unlock()
+	for _, label := range c.grpcStatusLabels {
+		m := &dto.Metric{}
+		metrics.Load().grpcStatusCodes.With(label).Write(m)
+		result[label["status"]] = (*m.Counter.Value / total) * 100
+	}
+	return result
+}
+
+// Backends returns map backend:value for backends-metric where value is
+// an percent of requests, which response contained backend id. Is empty if BackendHeader isn't set
+func (c *Client) Backends() map[string]float64 {
+	counts := make(map[string]float64)
+	var total float64
+	c.Lock()
+	defer c.Unlock()
+	for _, label := range c.backendLabels {
+		m := &dto.Metric{}
+		metrics.Load().backends.With(label).Write(m)
+		counts[label["backend"]] = *m.Counter.Value
+		total += *m.Counter.Value
+	}
+	for name, n := range counts {
+		counts[name] = n / total * 100
+	}
+	return counts
+}
+
+// ProxyAuthErrors returns value of proxyAuthErrors-metric
+func (*Client) ProxyAuthErrors() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().proxyAuthErrors.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// UploadStalls returns value of uploadStalls-metric
+func (*Client) UploadStalls() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().uploadStalls.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// InjectedDrops returns value of injectedDrops-metric
+func (*Client) InjectedDrops() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().injectedDrops.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// CompressedResponses returns value of compressedResponses-metric
+func (*Client) CompressedResponses() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().compressedResponses.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// BytesCompressed returns value of bytesCompressed-metric
+func (*Client) BytesCompressed() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().bytesCompressed.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// BytesDecoded returns value of bytesDecoded-metric
+func (*Client) BytesDecoded() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().bytesDecoded.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// DNSLookups returns value of dnsLookups-metric
+func (*Client) DNSLookups() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().dnsLookups.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// DNSChanges returns value of dnsChanges-metric
+func (*Client) DNSChanges() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().dnsChanges.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// CompressedRequests returns value of compressedRequests-metric
+func (*Client) CompressedRequests() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().compressedRequests.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// RequestBytesCompressed returns value of requestBytesCompressed-metric
+func (*Client) RequestBytesCompressed() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().requestBytesCompressed.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// RequestBytesUncompressed returns value of requestBytesUncompressed-metric
+func (*Client) RequestBytesUncompressed() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().requestBytesUncompressed.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// ThinkTimeSpent returns value of thinkTime-metric
+func (*Client) ThinkTimeSpent() time.Duration {
+	m := &dto.Metric{}
+	metrics.Load().thinkTime.Write(m)
+	return time.Duration(*m.Counter.Value * float64(time.Second))
+}
+
+// WSConnects returns value of wsConnects-metric
+func (*Client) WSConnects() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().wsConnects.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// WSConnectFailures returns value of wsConnectFailures-metric
+func (*Client) WSConnectFailures() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().wsConnectFailures.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// WSDrops returns value of wsDrops-metric
+func (*Client) WSDrops() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().wsDrops.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// AffinityBreaks returns value of affinityBreaks-metric
+func (*Client) AffinityBreaks() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().affinityBreaks.Write(m)
+	return uint64(*m.Counter.Value)
+}
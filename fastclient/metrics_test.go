@@ -0,0 +1,34 @@
+package fastclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// BenchmarkAccounting measures accounting of request by worker, which must sustain millions of requests per second,
+// so loader itself doesn't limit rate of test
+func BenchmarkAccounting(b *testing.B) {
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://127.0.0.1/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		d := time.Millisecond
+		for pb.Next() {
+			metrics.Load().requestSum.Inc()
+			metrics.Load().requestSuccess.Inc()
+			metrics.Load().bytesWritten.Add(100)
+			metrics.Load().bytesRead.Add(1000)
+			c.withStatusCode(fasthttp.StatusOK).Inc()
+			observeDuration(d.Seconds())
+			d += time.Microsecond
+			if d > 100*time.Millisecond {
+				d = time.Millisecond
+			}
+		}
+	})
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "ops/s")
+}
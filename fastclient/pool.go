@@ -0,0 +1,187 @@
+package fastclient
+
+import (
+	"context"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// saturatedShare is a share of jobs queued behind other jobs,
+// exceeding which pool of workers is considered saturated
+const saturatedShare = 0.1
+
+// Backpressure is a state of pool of workers since previous call of Client.Backpressure
+type Backpressure struct {
+	Workers int
+	// Queued is a number of jobs waiting in Jobsch at the moment
+	Queued int
+	// MaxQueued is a max number of jobs waiting in Jobsch
+	MaxQueued int
+	// Jobs is a number of jobs queued by Enqueue
+	Jobs uint64
+	// Waited is a number of jobs queued behind other jobs, since every worker was busy
+	Waited uint64
+	// Blocked is a number of jobs, which waited for free space in full Jobsch
+	Blocked uint64
+}
+
+// Saturation returns share of jobs, which were queued behind other jobs
+func (b Backpressure) Saturation() float64 {
+	if b.Jobs == 0 {
+		return 0
+	}
+	return float64(b.Waited) / float64(b.Jobs)
+}
+
+// Saturated returns true if workers didn't keep up with jobs,
+// so more of them are needed to sustain rate of jobs
+func (b Backpressure) Saturated() bool {
+	return b.Blocked > 0 || b.Saturation() > saturatedShare
+}
+
+// Backpressure returns state of pool of workers since its previous call.
+// Jobs sent to Jobsch directly instead of Enqueue aren't counted
+func (c *Client) Backpressure() Backpressure {
+	return Backpressure{
+		Workers:   c.Amount(),
+		Queued:    len(c.Jobsch),
+		MaxQueued: int(c.maxQueued.Swap(0)),
+		Jobs:      c.jobs.Swap(0),
+		Waited:    c.waited.Swap(0),
+		Blocked:   c.blocked.Swap(0),
+	}
+}
+
+// Enqueue sends job queued at t to Jobsch. While Jobsch is full, Enqueue waits for free space,
+// so rate of jobs is limited by workers instead of growing queue.
+// Returns false if ctx is done before job is queued
+func (c *Client) Enqueue(ctx context.Context, t time.Time) bool {
+	if c.ArrivalRate && c.Amount()-c.InFlight()-len(c.Jobsch) < 1 {
+		c.RunWorkers(1)
+	}
+	behind := len(c.Jobsch) > 0
+	select {
+	case c.Jobsch <- t:
+	case <-ctx.Done():
+		return false
+	default:
+		metrics.Load().queueFull.Inc()
+		c.blocked.Add(1)
+		select {
+		case c.Jobsch <- t:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	metrics.Load().jobsQueued.Inc()
+	c.jobs.Add(1)
+	if behind {
+		metrics.Load().jobsWaited.Inc()
+		c.waited.Add(1)
+	}
+	n := int64(len(c.Jobsch))
+	for {
+		old := c.maxQueued.Load()
+		if n <= old || c.maxQueued.CompareAndSwap(old, n) {
+			break
+		}
+	}
+	return true
+}
+
+// StopWorkers stops n workers once they finish their current requests, so pool shrinks
+// once rate of jobs is reduced. At least one worker is kept. Returns number of stopped workers
+func (c *Client) StopWorkers(n int) int {
+	c.Lock()
+	if n > c.workers-1 {
+		n = c.workers - 1
+	}
+	if n < 1 {
+		c.Unlock()
+		return 0
+	}
+	c.workers -= n
+	c.Unlock()
+	stop := c.stop
+	go func() {
+		for i := 0; i < n; i++ {
+			select {
+			case c.quit <- struct{}{}:
+			case <-stop:
+				// workers are stopped by Flush
+				return
+			}
+		}
+	}()
+	return n
+}
+
+// nextJob waits for job of jobs. Returns false if jobs is closed or worker is stopped by StopWorkers.
+// With ClosedLoop job is created at once
+func (c *Client) nextJob(jobs chan time.Time) (time.Time, bool) {
+	if c.ClosedLoop {
+		select {
+		case <-c.stop:
+			return time.Time{}, false
+		case <-c.quit:
+			return time.Time{}, false
+		default:
+			return time.Now(), c.takeJob()
+		}
+	}
+	select {
+	case t, ok := <-jobs:
+		return t, ok
+	case <-c.quit:
+		return time.Time{}, false
+	}
+}
+
+// queueSize returns capacity of Jobsch
+func (c *Client) queueSize() int {
+	if c.QueueSize > 0 {
+		return c.QueueSize
+	}
+	return jobCapacity
+}
+
+// JobsQueued returns value of jobsQueued-metric
+func (*Client) JobsQueued() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().jobsQueued.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// JobsDone returns value of jobsDone-metric
+func (*Client) JobsDone() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().jobsDone.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// LimitJobs makes workers of ClosedLoop and WebSocket stop once n more jobs are taken by them,
+// so exactly n requests are sent. Jobs of Jobsch are limited by number of queued ones instead
+func (c *Client) LimitJobs(n uint64) {
+	c.jobLimit.Store(c.taken.Load() + n)
+}
+
+// takeJob returns false if limit set by LimitJobs is reached
+func (c *Client) takeJob() bool {
+	limit := c.jobLimit.Load()
+	return limit == 0 || c.taken.Add(1) <= limit
+}
+
+// JobsWaited returns value of jobsWaited-metric
+func (*Client) JobsWaited() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().jobsWaited.Write(m)
+	return uint64(*m.Counter.Value)
+}
+
+// QueueFull returns value of queueFull-metric
+func (*Client) QueueFull() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().queueFull.Write(m)
+	return uint64(*m.Counter.Value)
+}
@@ -0,0 +1,175 @@
+package fastclient
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientEnqueue(t *testing.T) {
+	ln := listenTest(t)
+	release := make(chan struct{})
+	s := &fasthttp.Server{
+		Handler: func(ctx *fasthttp.RequestCtx) {
+			<-release
+		},
+	}
+	go s.Serve(ln)
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	c.QueueSize = 2
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	if n := cap(c.Jobsch); n != 2 {
+		t.Fatalf("Unexpected capacity of queue. Got: %d; Expected: %d", n, 2)
+	}
+
+	ctx := context.Background()
+	c.Enqueue(ctx, time.Now())
+	deadline := time.Now().Add(5 * time.Second)
+	for c.InFlight() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Job wasn't taken by worker in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// worker is busy, so the second job waits in queue and the third one waits behind it
+	c.Enqueue(ctx, time.Now())
+	c.Enqueue(ctx, time.Now())
+	tctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	if c.Enqueue(tctx, time.Now()) {
+		t.Errorf("Job mustn't be queued to full queue")
+	}
+	cancel()
+
+	bp := c.Backpressure()
+	expected := Backpressure{Workers: 1, Queued: 2, MaxQueued: 2, Jobs: 3, Waited: 1, Blocked: 1}
+	if bp != expected {
+		t.Errorf("Unexpected backpressure. Got: %+v; Expected: %+v", bp, expected)
+	}
+	if !bp.Saturated() {
+		t.Errorf("Pool must be saturated")
+	}
+	if bp := c.Backpressure(); bp.Jobs != 0 || bp.Blocked != 0 || bp.MaxQueued != 0 {
+		t.Errorf("Backpressure must be reset by previous call. Got: %+v", bp)
+	}
+	if n := c.QueueFull(); n != 1 {
+		t.Errorf("Unexpected number of jobs waited for full queue. Got: %d; Expected: %d", n, 1)
+	}
+	close(release)
+	c.Flush()
+}
+
+func TestClientStopWorkers(t *testing.T) {
+	ln := startFastTestServer(t, func(ctx *fasthttp.RequestCtx) {})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	t.Cleanup(c.Flush)
+	c.RunWorkers(5)
+	if n := c.StopWorkers(3); n != 3 {
+		t.Errorf("Unexpected number of stopped workers. Got: %d; Expected: %d", n, 3)
+	}
+	if n := c.Amount(); n != 2 {
+		t.Errorf("Unexpected number of workers. Got: %d; Expected: %d", n, 2)
+	}
+	if n := c.StopWorkers(5); n != 1 {
+		t.Errorf("The last worker must be kept. Got stopped: %d; Expected: %d", n, 1)
+	}
+
+	// jobs are served by the remaining worker
+	const requests = 10
+	for i := 0; i < requests; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, requests)
+	if n := c.RequestSum(); n != requests {
+		t.Fatalf("Unexpected number of requests. Got: %d; Expected: %d", n, requests)
+	}
+	c.Flush()
+	if n := c.Amount(); n != 0 {
+		t.Errorf("Unexpected number of workers after Flush. Got: %d; Expected: %d", n, 0)
+	}
+}
+
+func TestClientArrivalRate(t *testing.T) {
+	release := make(chan struct{})
+	ln := startFastTestServer(t, func(ctx *fasthttp.RequestCtx) {
+		<-release
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	c.ArrivalRate = true
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	const jobs = 5
+	for i := 1; i <= jobs; i++ {
+		c.Enqueue(context.Background(), time.Now())
+		deadline := time.Now().Add(5 * time.Second)
+		for c.InFlight() < i {
+			if time.Now().After(deadline) {
+				t.Fatalf("Job %d wasn't sent while workers were busy", i)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if n := c.Amount(); n != jobs {
+		t.Errorf("Unexpected number of workers. Got: %d; Expected: %d", n, jobs)
+	}
+	close(release)
+	c.Flush()
+}
+
+func TestClientClosedLoop(t *testing.T) {
+	ln := startFastTestServer(t, func(ctx *fasthttp.RequestCtx) {})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	c.ClosedLoop = true
+	t.Cleanup(c.Flush)
+	c.RunWorkers(2)
+	// requests are sent without jobs
+	const requests = 50
+	waitRequests(t, c, requests)
+	// Flush waits for workers, so it hangs unless closed loop is stopped
+	c.Flush()
+}
+
+func TestClientLimitJobs(t *testing.T) {
+	var received atomic.Uint64
+	ln := startFastTestServer(t, func(ctx *fasthttp.RequestCtx) {
+		received.Add(1)
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	c.ClosedLoop = true
+	c.LimitJobs(30)
+	t.Cleanup(c.Flush)
+	c.RunWorkers(4)
+	deadline := time.Now().Add(5 * time.Second)
+	for c.JobsDone() < 30 || c.InFlight() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Jobs weren't done in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// workers stop once limit is reached, so no more requests are sent
+	time.Sleep(50 * time.Millisecond)
+	if n := received.Load(); n != 30 {
+		t.Errorf("Unexpected number of requests received by target. Got: %d; Expected: 30", n)
+	}
+	if n := c.JobsDone(); n != 30 {
+		t.Errorf("Unexpected number of done jobs. Got: %d; Expected: 30", n)
+	}
+}
@@ -0,0 +1,61 @@
+package fastclient
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// DetectProtocol performs TLS handshake with target of request
+// offering HTTP/2 and HTTP/1.1 via ALPN and returns negotiated protocol.
+// Returns "http/1.1" for plaintext targets without any handshake. cfg is a base of TLS configuration like Client.TLSConfig
+func DetectProtocol(request *fasthttp.Request, cfg *tls.Config, timeout time.Duration) (string, error) {
+	addr, isTLS := acquireAddr(request)
+	if !isTLS {
+		return "http/1.1", nil
+	}
+
+	conn, err := dialTLS(addr, cfg, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	proto := conn.ConnectionState().NegotiatedProtocol
+	if proto == "" {
+		proto = "http/1.1"
+	}
+	return proto, nil
+}
+
+// CertExpiry performs TLS handshake with target of request and returns
+// the earliest expiry time of certificates in the chain presented by target.
+// cfg is a base of TLS configuration like Client.TLSConfig
+func CertExpiry(request *fasthttp.Request, cfg *tls.Config, timeout time.Duration) (time.Time, error) {
+	addr, isTLS := acquireAddr(request)
+	if !isTLS {
+		return time.Time{}, fmt.Errorf("target %s doesn't use TLS", addr)
+	}
+
+	conn, err := dialTLS(addr, cfg, timeout)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer conn.Close()
+
+	var expiry time.Time
+	for _, cert := range conn.ConnectionState().PeerCertificates {
+		if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
+		}
+	}
+	return expiry, nil
+}
+
+// dialTLS establishes TLS connection with addr offering HTTP/2 and HTTP/1.1 via ALPN
+func dialTLS(addr string, cfg *tls.Config, timeout time.Duration) (*tls.Conn, error) {
+	return tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", addr, tlsConfig(cfg, addr, []string{"h2", "http/1.1"}))
+}
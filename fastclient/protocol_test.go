@@ -0,0 +1,60 @@
+package fastclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestDetectProtocol(t *testing.T) {
+	f := func(h2 bool, expected string) {
+		t.Helper()
+		s := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		s.EnableHTTP2 = h2
+		s.StartTLS()
+		defer s.Close()
+
+		req := new(fasthttp.Request)
+		req.SetRequestURI(s.URL + "/")
+		proto, err := DetectProtocol(req, &tls.Config{InsecureSkipVerify: true}, time.Second)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if proto != expected {
+			t.Errorf("Unexpected protocol of target with HTTP/2 %v. Got: %q; Expected: %q", h2, proto, expected)
+		}
+	}
+
+	f(true, "h2")
+	// target without ALPN support negotiates nothing
+	f(false, "http/1.1")
+}
+
+func TestDetectProtocolPlaintext(t *testing.T) {
+	// plaintext target isn't dialed at all
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://127.0.0.1:1/")
+	proto, err := DetectProtocol(req, nil, time.Second)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if proto != "http/1.1" {
+		t.Errorf("Unexpected protocol of plaintext target. Got: %q; Expected: %q", proto, "http/1.1")
+	}
+}
+
+func TestDetectProtocolError(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer s.Close()
+
+	// certificate of test server isn't trusted
+	req := new(fasthttp.Request)
+	req.SetRequestURI(s.URL + "/")
+	if _, err := DetectProtocol(req, nil, time.Second); err == nil {
+		t.Fatalf("Expected error of certificate verification")
+	}
+}
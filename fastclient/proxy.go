@@ -0,0 +1,86 @@
+package fastclient
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ErrProxyAuth is returned if proxy declined credentials of Client.ProxyAuthorization
+var ErrProxyAuth = fmt.Errorf("proxy authentication failed: 407 Proxy Authentication Required")
+
+// tunnel establishes tunnel to addr via proxy over conn
+func (c *Client) tunnel(conn net.Conn, addr string) error {
+	if c.ProxySOCKS5 {
+		return c.connectSOCKS5(conn, addr)
+	}
+	return c.connectProxy(conn, addr)
+}
+
+// CheckProxy establishes tunnel to target via proxy and closes it, so unreachable proxy
+// is detected before load. Connection of check isn't counted by metrics
+func (c *Client) CheckProxy() error {
+	conn, err := fasthttp.DialTimeout(c.ProxyAddr, c.dialTimeout())
+	if err != nil {
+		return fmt.Errorf("cannot connect to proxy: %s", err)
+	}
+	defer conn.Close()
+	return c.tunnel(conn, c.Addr)
+}
+
+// connectProxy establishes tunnel to addr via CONNECT request to HTTP proxy over conn
+func (c *Client) connectProxy(conn net.Conn, addr string) error {
+	conn.SetDeadline(time.Now().Add(c.dialTimeout()))
+	defer conn.SetDeadline(time.Time{})
+
+	req := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n", addr, addr)
+	if c.ProxyAuthorization != "" {
+		req += "Proxy-Authorization: " + c.ProxyAuthorization + "\r\n"
+	}
+	if _, err := conn.Write([]byte(req + "\r\n")); err != nil {
+		return fmt.Errorf("cannot send CONNECT to proxy: %s", err)
+	}
+	head, err := readResponseHead(conn)
+	if err != nil {
+		return fmt.Errorf("cannot read response of proxy: %s", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(head)), nil)
+	if err != nil {
+		return fmt.Errorf("cannot parse response of proxy: %s", err)
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusProxyAuthRequired:
+		metrics.Load().proxyAuthErrors.Inc()
+		return ErrProxyAuth
+	default:
+		return fmt.Errorf("proxy declined CONNECT to %s: %s", addr, resp.Status)
+	}
+}
+
+// maxProxyResponseSize is a max size of status line and headers of response to CONNECT
+const maxProxyResponseSize = 16 * 1024
+
+// readResponseHead reads status line and headers of response byte by byte,
+// so data of tunnel, which follows them, isn't consumed
+func readResponseHead(conn net.Conn) ([]byte, error) {
+	var head []byte
+	b := make([]byte, 1)
+	for !bytes.HasSuffix(head, []byte("\r\n\r\n")) {
+		if len(head) >= maxProxyResponseSize {
+			return nil, fmt.Errorf("response head exceeds %d bytes", maxProxyResponseSize)
+		}
+		if _, err := conn.Read(b); err != nil {
+			return nil, err
+		}
+		head = append(head, b[0])
+	}
+	return head, nil
+}
@@ -0,0 +1,77 @@
+package fastclient
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// serveProxy runs HTTP proxy which tunnels CONNECT requests with given Proxy-Authorization header
+func serveProxy(t *testing.T, auth string) net.Listener {
+	ln := listenTest(t)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil || req.Method != http.MethodConnect {
+					return
+				}
+				if req.Header.Get("Proxy-Authorization") != auth {
+					conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\n\r\n"))
+					return
+				}
+				target, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					conn.Write([]byte("HTTP/1.1 502 Bad Gateway\r\n\r\n"))
+					return
+				}
+				defer target.Close()
+				conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+				go io.Copy(target, conn)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+	return ln
+}
+
+func TestClientProxy(t *testing.T) {
+	target := serveBackends(t, 1)
+	defer target.Close()
+	proxy := serveProxy(t, "Basic dXNlcjpwYXNz")
+	defer proxy.Close()
+
+	f := func(auth string, success, authErrors uint64) {
+		t.Helper()
+		req := new(fasthttp.Request)
+		req.SetRequestURI("http://" + target.Addr().String() + "/")
+		c := New(req, time.Second, fasthttp.StatusOK)
+		c.ProxyAddr = proxy.Addr().String()
+		c.ProxyAuthorization = auth
+		t.Cleanup(c.Flush)
+		c.RunWorkers(1)
+		c.Jobsch <- time.Now()
+
+		waitRequests(t, c, 1)
+
+		if n := c.RequestSuccess(); n != success {
+			t.Errorf("Unexpected number of success requests. Got: %d; Expected: %d; Errors: %v", n, success, c.ErrorMessages())
+		}
+		if n := c.ProxyAuthErrors(); n != authErrors {
+			t.Errorf("Unexpected number of proxy auth errors. Got: %d; Expected: %d", n, authErrors)
+		}
+	}
+
+	f("Basic dXNlcjpwYXNz", 1, 0)
+	f("Basic d3Jvbmc6cGFzcw==", 0, 1)
+}
@@ -0,0 +1,57 @@
+package fastclient
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/ratelimiter"
+)
+
+// rateCap is a token bucket, which limits rate of requests to target.
+// Throttle releases jobs every ratelimiter.TickPeriod, so bucket holds tokens of one tick,
+// and idle target can't burst beyond its rate
+type rateCap struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateCap(rate float64) *rateCap {
+	return &rateCap{rate: rate, tokens: 1, last: time.Now()}
+}
+
+// refill adds tokens accumulated since the last call. Must be called under mu
+func (rc *rateCap) refill() {
+	now := time.Now()
+	burst := math.Max(1, rc.rate*ratelimiter.TickPeriod.Seconds())
+	rc.tokens = math.Min(burst, rc.tokens+now.Sub(rc.last).Seconds()*rc.rate)
+	rc.last = now
+}
+
+// ready reports whether request could be sent now. Rate of nil cap isn't limited
+func (rc *rateCap) ready() bool {
+	if rc == nil {
+		return true
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.refill()
+	return rc.tokens >= 1
+}
+
+// take takes token for request and reports whether it was available. Rate of nil cap isn't limited
+func (rc *rateCap) take() bool {
+	if rc == nil {
+		return true
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.refill()
+	if rc.tokens < 1 {
+		return false
+	}
+	rc.tokens--
+	return true
+}
@@ -0,0 +1,97 @@
+package fastclient
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+// Region is a simulated location of clients
+type Region struct {
+	Name string
+
+	// Share is a percent of connections established by clients of region
+	Share float64
+
+	// RTT is a round-trip time between region and target,
+	// which is injected as delay of every request
+	RTT time.Duration
+}
+
+// RegionLatency contains latency of responses received by clients of region
+type RegionLatency struct {
+	Region   string
+	Requests uint64
+
+	// Quantiles maps quantile to latency in seconds
+	Quantiles map[float64]float64
+}
+
+func (c *Client) initRegions() {
+	c.regionLabels = make([]prometheus.Labels, len(c.Regions))
+	for i, r := range c.Regions {
+		c.regionLabels[i] = prometheus.Labels{"region": r.Name}
+	}
+}
+
+// pickRegion returns index of region of new connection with given local address.
+// Regions are picked according to their shares by random value rnd in range [0..1)
+func (c *Client) pickRegion(addr string, rnd float64) int {
+	n := rnd * 100
+	i := 0
+	for ; i < len(c.Regions)-1; i++ {
+		if n < c.Regions[i].Share {
+			break
+		}
+		n -= c.Regions[i].Share
+	}
+	c.Lock()
+	c.regionConns[addr] = i
+	c.Unlock()
+	return i
+}
+
+// removeRegionConn forgets region of connection with given local address
+func (c *Client) removeRegionConn(addr string) {
+	c.Lock()
+	delete(c.regionConns, addr)
+	c.Unlock()
+}
+
+// observeRegionDuration measures latency of resp by region of its connection
+func (c *Client) observeRegionDuration(resp *fasthttp.Response, v float64) {
+	addr := resp.LocalAddr()
+	if addr == nil {
+		return
+	}
+	c.Lock()
+	i, ok := c.regionConns[addr.String()]
+	c.Unlock()
+	if ok {
+		c.regionsOnce.Do(c.initRegions)
+		metrics.Load().regionDuration.With(c.regionLabels[i]).Observe(v)
+	}
+}
+
+// LatencyByRegion returns latency of complete responses broken down by region in order of Regions.
+// Is empty if Regions aren't set
+func (c *Client) LatencyByRegion() []RegionLatency {
+	c.regionsOnce.Do(c.initRegions)
+	var result []RegionLatency
+	for _, label := range c.regionLabels {
+		m := &dto.Metric{}
+		metrics.Load().regionDuration.With(label).(prometheus.Metric).Write(m)
+		rl := RegionLatency{
+			Region:    label["region"],
+			Requests:  m.Summary.GetSampleCount(),
+			Quantiles: make(map[float64]float64, len(m.Summary.Quantile)),
+		}
+		for _, q := range m.Summary.Quantile {
+			rl.Quantiles[*q.Quantile] = *q.Value
+		}
+		result = append(result, rl)
+	}
+	return result
+}
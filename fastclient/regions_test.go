@@ -0,0 +1,35 @@
+package fastclient
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestPickRegion(t *testing.T) {
+	c := &Client{
+		Regions: []Region{
+			{Name: "US", Share: 20},
+			{Name: "EU", Share: 30},
+			{Name: "APAC", Share: 50},
+		},
+		regionConns: make(map[string]int),
+	}
+	const n = 10000
+	counts := make([]int, len(c.Regions))
+	for i := 0; i < n; i++ {
+		counts[c.pickRegion(fmt.Sprintf("127.0.0.1:%d", i), c.connRand().Float64())]++
+	}
+	for i, r := range c.Regions {
+		if share := float64(counts[i]) / n * 100; math.Abs(share-r.Share) > 3 {
+			t.Errorf("Unexpected share of region %s. Got: %.2f %%; Expected: %.2f %%", r.Name, share, r.Share)
+		}
+	}
+	if len(c.regionConns) != n {
+		t.Errorf("Unexpected number of connections with region. Got: %d; Expected: %d", len(c.regionConns), n)
+	}
+	c.removeRegionConn("127.0.0.1:0")
+	if _, ok := c.regionConns["127.0.0.1:0"]; ok {
+		t.Errorf("region of removed connection must be forgotten")
+	}
+}
@@ -0,0 +1,42 @@
+package fastclient
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// connMetrics are metrics, by which traffic of connections is counted
+type connMetrics struct {
+	connOpen     prometheus.Gauge
+	readError    prometheus.Counter
+	writeError   prometheus.Counter
+	bytesWritten prometheus.Counter
+	bytesRead    prometheus.Counter
+}
+
+func newConnMetrics() *connMetrics {
+	m := metrics.Load()
+	return &connMetrics{
+		connOpen:     m.connOpen,
+		readError:    m.readError,
+		writeError:   m.writeError,
+		bytesWritten: m.bytesWritten,
+		bytesRead:    m.bytesRead,
+	}
+}
+
+// ResetMetrics resets metrics like New does, but keeps workers and connections of client.
+// Connections opened before reset are still counted as open and their traffic is counted by new metrics,
+// so connections established during warmup are reused by measured requests.
+// Requests, which are in flight during reset, may be counted partially
+func (c *Client) ResetMetrics() {
+	m := &dto.Metric{}
+	metrics.Load().connOpen.Write(m)
+	flushMetrics()
+	metrics.Load().connOpen.Set(*m.Gauge.Value)
+	c.connMetrics.Store(newConnMetrics())
+
+	c.connRequestsMu.Lock()
+	c.connRequests = make(map[int]uint64)
+	c.connRequestsMu.Unlock()
+}
@@ -0,0 +1,49 @@
+package fastclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientResetMetrics(t *testing.T) {
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	send := func(n uint64) {
+		t.Helper()
+		for i := uint64(0); i < n; i++ {
+			c.Jobsch <- time.Now()
+		}
+		waitRequests(t, c, n)
+	}
+
+	send(5)
+	if n := c.ConnOpen(); n != 1 {
+		t.Fatalf("Unexpected number of open connections before reset. Got: %d; Expected: 1", n)
+	}
+	c.ResetMetrics()
+	if n := c.RequestSum(); n != 0 {
+		t.Fatalf("Requests weren't reset. Got: %d", n)
+	}
+	if n := c.ConnOpen(); n != 1 {
+		t.Fatalf("Open connections must be kept on reset. Got: %d; Expected: 1", n)
+	}
+
+	send(5)
+	// warmed connection is reused and its traffic is counted
+	if n := c.Connects(); n != 0 {
+		t.Errorf("Unexpected number of connects after reset. Got: %d; Expected: 0", n)
+	}
+	if n := c.BytesRead(); n == 0 {
+		t.Errorf("Traffic of connection opened before reset isn't counted")
+	}
+}
@@ -0,0 +1,114 @@
+package fastclient
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// NewResolver returns resolver, which sends DNS queries to server like "1.1.1.1:53"
+func NewResolver(server string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// resolvedHost contains IPs of host resolved at time
+type resolvedHost struct {
+	ips  []net.IP
+	time time.Time
+}
+
+// resolve returns address to dial for connection to addr. Addresses of StaticHosts are mapped
+// without lookup. Host is resolved by Client if LatencyBreakdown, Resolver or ResolveInterval is set,
+// and by dialer otherwise. Addresses of IPs, of proxy and of DialAddrs are returned as is.
+// Resolved IPs are dialed in round-robin
+func (c *Client) resolve(addr string) (string, error) {
+	if c.ProxyAddr != "" || len(c.DialAddrs) > 0 {
+		return addr, nil
+	}
+	if mapped, ok := c.StaticHosts[addr]; ok {
+		return mapped, nil
+	}
+	if !c.LatencyBreakdown && c.Resolver == nil && c.ResolveInterval <= 0 {
+		return addr, nil
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return addr, nil
+	}
+	ips, err := c.lookup(host)
+	if err != nil {
+		return "", err
+	}
+	n := atomic.AddUint32(&c.resolves, 1) - 1
+	return net.JoinHostPort(ips[n%uint32(len(ips))].String(), port), nil
+}
+
+// lookup returns IPv4 addresses of host. Addresses are reused during ResolveInterval
+func (c *Client) lookup(host string) ([]net.IP, error) {
+	var prev *resolvedHost
+	if c.ResolveInterval > 0 {
+		c.Lock()
+		prev = c.resolved[host]
+		c.Unlock()
+		if prev != nil && time.Since(prev.time) < c.ResolveInterval {
+			return prev.ips, nil
+		}
+	}
+	r := c.Resolver
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.dialTimeout())
+	defer cancel()
+	start := time.Now()
+	// fasthttp dials only IPv4 addresses
+	ips, err := r.LookupIP(ctx, "ip4", host)
+	metrics.Load().dnsLookups.Inc()
+	if err != nil {
+		return nil, err
+	}
+	if c.LatencyBreakdown {
+		v := time.Since(start).Seconds()
+		metrics.Load().dnsDuration.Observe(v)
+		observeMax(&maxDNSDuration, v)
+	}
+	if c.ResolveInterval > 0 {
+		if prev != nil && !sameIPs(prev.ips, ips) {
+			metrics.Load().dnsChanges.Inc()
+		}
+		c.Lock()
+		if c.resolved == nil {
+			c.resolved = make(map[string]*resolvedHost)
+		}
+		c.resolved[host] = &resolvedHost{ips: ips, time: time.Now()}
+		c.Unlock()
+	}
+	return ips, nil
+}
+
+// sameIPs returns true if a and b contain the same IPs regardless of order
+func sameIPs(a, b []net.IP) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, ip := range a {
+		found := false
+		for _, ip2 := range b {
+			if ip.Equal(ip2) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package fastclient
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClientResolveHosts(t *testing.T) {
+	flushMetrics()
+	c := &Client{StaticHosts: map[string]string{"example.com:443": "10.0.0.1:443"}}
+	f := func(addr, expected string) {
+		t.Helper()
+		got, err := c.resolve(addr)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", addr, err)
+		}
+		if got != expected {
+			t.Errorf("Unexpected address for %q. Got: %q; Expected: %q", addr, got, expected)
+		}
+	}
+	f("example.com:443", "10.0.0.1:443")
+	// host is resolved by dialer
+	f("example.org:80", "example.org:80")
+	f("10.0.0.2:80", "10.0.0.2:80")
+
+	// resolved IPs are reused during interval
+	c.ResolveInterval = time.Hour
+	f("localhost:80", "127.0.0.1:80")
+	f("localhost:80", "127.0.0.1:80")
+	if n := c.DNSLookups(); n != 1 {
+		t.Errorf("Unexpected number of lookups. Got: %d; Expected: 1", n)
+	}
+	c.resolved["localhost"].time = time.Now().Add(-2 * time.Hour)
+	c.resolved["localhost"].ips = []net.IP{net.ParseIP("127.0.0.2")}
+	f("localhost:80", "127.0.0.1:80")
+	if c.DNSLookups() != 2 || c.DNSChanges() != 1 {
+		t.Errorf("Unexpected lookups after interval: %d lookups; %d changes", c.DNSLookups(), c.DNSChanges())
+	}
+}
+
+func TestNewResolverError(t *testing.T) {
+	c := &Client{Resolver: NewResolver("127.0.0.1:1"), DialTimeout: time.Second}
+	_, err := c.resolve("example.com:80")
+	if err == nil {
+		t.Fatalf("Expected error of unreachable DNS server")
+	}
+	if class := errorClass(err); class != ErrorClassDNS {
+		t.Errorf("Unexpected class of %q. Got: %q; Expected: %q", err, class, ErrorClassDNS)
+	}
+}
+
+func TestSameIPs(t *testing.T) {
+	f := func(a, b []string, expected bool) {
+		t.Helper()
+		var ipsA, ipsB []net.IP
+		for _, s := range a {
+			ipsA = append(ipsA, net.ParseIP(s))
+		}
+		for _, s := range b {
+			ipsB = append(ipsB, net.ParseIP(s))
+		}
+		if got := sameIPs(ipsA, ipsB); got != expected {
+			t.Errorf("Unexpected result for %v and %v. Got: %v; Expected: %v", a, b, got, expected)
+		}
+	}
+	f(nil, nil, true)
+	f([]string{"10.0.0.1", "10.0.0.2"}, []string{"10.0.0.2", "10.0.0.1"}, true)
+	f([]string{"10.0.0.1"}, []string{"10.0.0.2"}, false)
+	f([]string{"10.0.0.1"}, []string{"10.0.0.1", "10.0.0.2"}, false)
+}
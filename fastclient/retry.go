@@ -0,0 +1,72 @@
+package fastclient
+
+import (
+	"math/rand"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+// RetryRule defines retries of responses with some status code
+type RetryRule struct {
+	// Probability of retry in range [0..1]
+	Probability float64
+
+	// Backoff is a base of exponential backoff with full jitter:
+	// n-th retry waits random duration up to Backoff*2^(n-1). Zero means no wait
+	Backoff time.Duration
+}
+
+// RetryPolicy maps status code to rule of its retries.
+// Responses with status codes, which aren't in policy, aren't retried
+type RetryPolicy map[int]RetryRule
+
+// retry returns true if request, which got response with status code
+// for the attempt-th time, must be sent again. It waits for backoff
+// and for token of RetryTokens before returning true. Retry and backoff are drawn from rnd
+func (c *Client) retry(code, attempt int, rnd *rand.Rand) bool {
+	rule, ok := c.RetryPolicy[code]
+	if !ok || attempt > c.MaxRetries || rnd.Float64() >= rule.Probability {
+		return false
+	}
+	if rule.Backoff > 0 {
+		max := int64(rule.Backoff) << uint(attempt-1)
+		select {
+		case <-time.After(time.Duration(rnd.Int63n(max) + 1)):
+		case <-c.stop:
+			return false
+		}
+	}
+	if c.RetryTokens != nil {
+		select {
+		case <-c.RetryTokens:
+		case <-c.stop:
+			return false
+		}
+	}
+	metrics.Load().retries.Inc()
+	return true
+}
+
+// send sends request via hc until it gets response, which mustn't be retried by RetryPolicy
+func (c *Client) send(hc *fasthttp.HostClient, r *fasthttp.Request, resp *fasthttp.Response, rnd *rand.Rand) error {
+	for attempt := 1; ; attempt++ {
+		var err error
+		if c.transport != nil {
+			err = c.transport.do(r, resp)
+		} else {
+			err = hc.Do(r, resp)
+		}
+		if err != nil || !c.retry(resp.StatusCode(), attempt, rnd) {
+			return err
+		}
+	}
+}
+
+// Retries returns value of retries-metric
+func (*Client) Retries() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().retries.Write(m)
+	return uint64(*m.Counter.Value)
+}
@@ -0,0 +1,74 @@
+package fastclient
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientRetries(t *testing.T) {
+	// every odd response is 503
+	var requests uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddUint32(&requests, 1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.RetryPolicy = RetryPolicy{http.StatusServiceUnavailable: {Probability: 1, Backoff: time.Millisecond}}
+	c.MaxRetries = 3
+	tokens := make(chan struct{}, 10)
+	c.RetryTokens = tokens
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 10; i++ {
+		tokens <- struct{}{}
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 10)
+
+	if n := c.Retries(); n != 10 {
+		t.Errorf("Unexpected number of retries. Got: %d; Expected: 10", n)
+	}
+	if n := c.RequestSuccess(); n != 10 {
+		t.Errorf("Unexpected number of successful requests. Got: %d; Expected: 10", n)
+	}
+	if n := atomic.LoadUint32(&requests); n != 20 {
+		t.Errorf("Unexpected number of attempts received by server. Got: %d; Expected: 20", n)
+	}
+}
+
+func TestClientRetriesAreStoppedByFlush(t *testing.T) {
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.RetryPolicy = RetryPolicy{http.StatusServiceUnavailable: {Probability: 1}}
+	c.MaxRetries = 3
+	// retry waits for token forever
+	c.RetryTokens = make(chan struct{})
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	c.Jobsch <- time.Now()
+	time.Sleep(100 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		c.Flush()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Flush is blocked by retry")
+	}
+}
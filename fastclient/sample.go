@@ -0,0 +1,15 @@
+package fastclient
+
+import "time"
+
+// Sample is a measurement of single request, which is passed to Client.OnSample
+type Sample struct {
+	// Start is a time when request was sent, or when it was queued if Client.IncludeQueueWait is true
+	Start time.Time
+	// Duration is a latency of request as it is measured by requestDuration-metric
+	Duration time.Duration
+	// StatusCode is a status code of response. Is zero if response wasn't received or request is WebSocket message
+	StatusCode int
+	// ErrorClass is a class of failure like ErrorClassTimeout. Is empty if request succeeded
+	ErrorClass string
+}
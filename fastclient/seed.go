@@ -0,0 +1,29 @@
+package fastclient
+
+import (
+	"math/rand"
+	randv2 "math/rand/v2"
+)
+
+// streamSeed returns seed of n-th stream of random values derived from seed by splitmix64,
+// so streams of adjacent n don't repeat each other
+func streamSeed(seed int64, n uint64) uint64 {
+	z := uint64(seed) + (n+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// workerRand returns source of random values of the next worker started since New or Flush.
+// Values depend only on Seed and order of worker, so workers of tests with the same Seed
+// take the same sequences of jitter, targets, retries and think time
+func (c *Client) workerRand() *rand.Rand {
+	n := c.workerSeq.Add(1)
+	return rand.New(rand.NewSource(int64(streamSeed(c.Seed, n))))
+}
+
+// connRand returns small source of random values of the next connection dialed by client
+func (c *Client) connRand() *randv2.Rand {
+	n := c.connSeq.Add(1)
+	return randv2.New(randv2.NewPCG(streamSeed(c.Seed, n), uint64(c.Seed)))
+}
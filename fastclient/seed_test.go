@@ -0,0 +1,48 @@
+package fastclient
+
+import (
+	"testing"
+)
+
+func TestWorkerRand(t *testing.T) {
+	f := func(seed int64) {
+		t.Helper()
+		a, b := &Client{Seed: seed}, &Client{Seed: seed}
+		first := make(map[int64]bool)
+		for i := 0; i < 10; i++ {
+			x, y := a.workerRand().Int63(), b.workerRand().Int63()
+			if x != y {
+				t.Errorf("Unexpected value of worker %d with seed %d. Got: %d; Expected: %d", i, seed, y, x)
+			}
+			if first[x] {
+				t.Errorf("Value of worker %d with seed %d repeats value of previous worker", i, seed)
+			}
+			first[x] = true
+			if x, y := a.connRand().Uint64(), b.connRand().Uint64(); x != y {
+				t.Errorf("Unexpected value of connection %d with seed %d. Got: %d; Expected: %d", i, seed, y, x)
+			}
+		}
+		a.workerSeq.Store(0)
+		if x := a.workerRand().Int63(); !first[x] {
+			t.Errorf("Workers with seed %d must repeat values after reset", seed)
+		}
+	}
+
+	f(0)
+	f(1)
+	f(42)
+	f(-7)
+}
+
+func TestStreamSeed(t *testing.T) {
+	seen := make(map[uint64]bool)
+	for _, seed := range []int64{0, 1, 2} {
+		for n := uint64(0); n < 100; n++ {
+			s := streamSeed(seed, n)
+			if seen[s] {
+				t.Fatalf("Stream %d of seed %d repeats seed of another stream", n, seed)
+			}
+			seen[s] = true
+		}
+	}
+}
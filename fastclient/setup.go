@@ -0,0 +1,55 @@
+package fastclient
+
+import (
+	dto "github.com/prometheus/client_model/go"
+)
+
+// maxConnectDuration and maxHandshakeDuration contain bits of max time in seconds
+// of TCP connect and of TLS handshake
+var maxConnectDuration, maxHandshakeDuration uint64
+
+func observeConnectDuration(v float64) {
+	metrics.Load().connectDuration.Observe(v)
+	observeMax(&maxConnectDuration, v)
+}
+
+func observeHandshakeDuration(v float64) {
+	metrics.Load().handshakeDuration.Observe(v)
+	observeMax(&maxHandshakeDuration, v)
+}
+
+// ConnectDuration returns map quantile:value for connectDuration-metric
+func (*Client) ConnectDuration() map[float64]float64 {
+	return quantiles(metrics.Load().connectDuration)
+}
+
+// HandshakeDuration returns map quantile:value for handshakeDuration-metric
+func (*Client) HandshakeDuration() map[float64]float64 {
+	return quantiles(metrics.Load().handshakeDuration)
+}
+
+// ConnectLatency returns percentiles and max of time of TCP connect
+// for connectDuration-metric. Is zero if no connections were established
+func (*Client) ConnectLatency() Latency {
+	return latencyOf(metrics.Load().connectDuration, &maxConnectDuration)
+}
+
+// HandshakeLatency returns percentiles and max of time of TLS handshake
+// for handshakeDuration-metric. Is zero if no handshakes were made
+func (*Client) HandshakeLatency() Latency {
+	return latencyOf(metrics.Load().handshakeDuration, &maxHandshakeDuration)
+}
+
+// Connects returns number of connections measured by connectDuration-metric
+func (*Client) Connects() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().connectDuration.Write(m)
+	return m.Summary.GetSampleCount()
+}
+
+// Handshakes returns number of handshakes measured by handshakeDuration-metric
+func (*Client) Handshakes() uint64 {
+	m := &dto.Metric{}
+	metrics.Load().handshakeDuration.Write(m)
+	return m.Summary.GetSampleCount()
+}
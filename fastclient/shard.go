@@ -0,0 +1,89 @@
+package fastclient
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// cacheLineSize is a size of cache line, by which shards are padded, so they don't share lines
+const cacheLineSize = 64
+
+// shard is a counter padded to cache line
+type shard struct {
+	n atomic.Uint64
+	_ [cacheLineSize - 8]byte
+}
+
+// shards is an integer counter, which is incremented by many goroutines without contention.
+// Every increment goes to random shard, and value is a sum of shards on read
+type shards struct {
+	s    []shard
+	mask uint32
+}
+
+func newShards() shards {
+	// random shards of many workers rarely collide if there are 4 shards per CPU
+	n := 1 << bits.Len(uint(4*runtime.GOMAXPROCS(0)-1))
+	return shards{s: make([]shard, n), mask: uint32(n - 1)}
+}
+
+func (s *shards) add(n uint64) {
+	// rand of runtime is per thread, so it doesn't contend
+	s.s[rand.Uint32()&s.mask].n.Add(n)
+}
+
+func (s *shards) value() uint64 {
+	var n uint64
+	for i := range s.s {
+		n += s.s[i].n.Load()
+	}
+	return n
+}
+
+// shardedCounter is a prometheus.Counter of integer values like number of requests or bytes,
+// which are counted by every worker. Fractions of values passed to Add are dropped
+type shardedCounter struct {
+	desc *prometheus.Desc
+	shards
+}
+
+func newShardedCounter(opts prometheus.CounterOpts) *shardedCounter {
+	return &shardedCounter{
+		desc:   prometheus.NewDesc(prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name), opts.Help, nil, opts.ConstLabels),
+		shards: newShards(),
+	}
+}
+
+func (c *shardedCounter) Inc() {
+	c.add(1)
+}
+
+func (c *shardedCounter) Add(v float64) {
+	if v < 0 {
+		panic("counter cannot decrease in value")
+	}
+	c.add(uint64(v))
+}
+
+func (c *shardedCounter) Desc() *prometheus.Desc {
+	return c.desc
+}
+
+func (c *shardedCounter) Write(m *dto.Metric) error {
+	v := float64(c.value())
+	m.Counter = &dto.Counter{Value: &v}
+	return nil
+}
+
+func (c *shardedCounter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *shardedCounter) Collect(ch chan<- prometheus.Metric) {
+	ch <- c
+}
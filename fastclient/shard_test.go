@@ -0,0 +1,68 @@
+package fastclient
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestShardedCounter(t *testing.T) {
+	c := newShardedCounter(prometheus.CounterOpts{Name: "test_counter", Help: "Test counter"})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc()
+				c.Add(2.5)
+			}
+		}()
+	}
+	wg.Wait()
+
+	m := &dto.Metric{}
+	if err := c.Write(m); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if v := m.GetCounter().GetValue(); v != 8*1000*3 {
+		t.Fatalf("Unexpected value. Got: %v; Expected: %v", v, 8*1000*3)
+	}
+
+	r := prometheus.NewRegistry()
+	r.MustRegister(c)
+	mfs, err := r.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error while gathering: %s", err)
+	}
+	if len(mfs) != 1 || mfs[0].GetName() != "test_counter" || mfs[0].GetType() != dto.MetricType_COUNTER {
+		t.Fatalf("Unexpected gathered metrics: %v", mfs)
+	}
+}
+
+func TestShardedCounterNegative(t *testing.T) {
+	c := newShardedCounter(prometheus.CounterOpts{Name: "test_counter", Help: "Test counter"})
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected panic on negative value")
+		}
+	}()
+	c.Add(-1)
+}
+
+func BenchmarkCounter(b *testing.B) {
+	f := func(name string, c prometheus.Counter) {
+		b.Helper()
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					c.Inc()
+				}
+			})
+		})
+	}
+	f("sharded", newShardedCounter(prometheus.CounterOpts{Name: "test_counter", Help: "Test counter"}))
+	f("prometheus", prometheus.NewCounter(prometheus.CounterOpts{Name: "test_counter", Help: "Test counter"}))
+}
@@ -0,0 +1,62 @@
+package fastclient
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// sizeBuckets are ranges of response body size, by which latency is broken down.
+// Bucket contains sizes below its bound and not below bound of previous bucket
+var sizeBuckets = []struct {
+	bound  int
+	labels prometheus.Labels
+}{
+	{1 << 10, prometheus.Labels{"size": "<1KB"}},
+	{10 << 10, prometheus.Labels{"size": "1KB-10KB"}},
+	{100 << 10, prometheus.Labels{"size": "10KB-100KB"}},
+	{1 << 20, prometheus.Labels{"size": "100KB-1MB"}},
+	{-1, prometheus.Labels{"size": ">=1MB"}},
+}
+
+// SizeLatency contains latency of responses with body size in range
+type SizeLatency struct {
+	// Size is a range of body size like "1KB-10KB"
+	Size string
+
+	Requests uint64
+
+	// Quantiles maps quantile to latency in seconds
+	Quantiles map[float64]float64
+}
+
+func observeSizeDuration(size int, v float64) {
+	for _, b := range sizeBuckets {
+		if size < b.bound || b.bound < 0 {
+			metrics.Load().sizeDuration.With(b.labels).Observe(v)
+			return
+		}
+	}
+}
+
+// LatencyBySize returns latency of complete responses broken down by size of body.
+// Ranges are ordered by size, and ones without responses are skipped
+func (*Client) LatencyBySize() []SizeLatency {
+	var result []SizeLatency
+	for _, b := range sizeBuckets {
+		m := &dto.Metric{}
+		metrics.Load().sizeDuration.With(b.labels).(prometheus.Metric).Write(m)
+		if m.Summary.GetSampleCount() == 0 {
+			continue
+		}
+		sl := SizeLatency{
+			Size:      b.labels["size"],
+			Requests:  m.Summary.GetSampleCount(),
+			Quantiles: make(map[float64]float64, len(m.Summary.Quantile)),
+		}
+		for _, q := range m.Summary.Quantile {
+			sl.Quantiles[*q.Quantile] = *q.Value
+		}
+		result = append(result, sl)
+	}
+	return result
+}
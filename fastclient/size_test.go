@@ -0,0 +1,49 @@
+package fastclient
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientLatencyBySize(t *testing.T) {
+	// every third response is 20KB, other ones are 10 bytes
+	var requests uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddUint32(&requests, 1)%3 == 0 {
+			w.Write([]byte(strings.Repeat("a", 20<<10)))
+			return
+		}
+		w.Write([]byte("0123456789"))
+	})
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 30; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 30)
+
+	got := c.LatencyBySize()
+	if len(got) != 2 {
+		t.Fatalf("Unexpected number of size ranges. Got: %v; Expected: 2", got)
+	}
+	f := func(sl SizeLatency, size string, requests uint64) {
+		t.Helper()
+		if sl.Size != size || sl.Requests != requests {
+			t.Errorf("Unexpected size range. Got: %s with %d requests; Expected: %s with %d requests", sl.Size, sl.Requests, size, requests)
+		}
+		if sl.Quantiles[0.99] <= 0 {
+			t.Errorf("Unexpected p99 of %s: %f", sl.Size, sl.Quantiles[0.99])
+		}
+	}
+	f(got[0], "<1KB", 20)
+	f(got[1], "10KB-100KB", 10)
+}
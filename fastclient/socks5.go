@@ -0,0 +1,154 @@
+package fastclient
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// ErrSOCKS5Auth is returned if SOCKS5 proxy rejected Client.ProxyUser and Client.ProxyPassword
+var ErrSOCKS5Auth = fmt.Errorf("proxy authentication failed: SOCKS5 proxy rejected username and password")
+
+const (
+	socks5Version = 5
+
+	socks5NoAuth       = 0
+	socks5PasswordAuth = 2
+	socks5NoAcceptable = 0xff
+
+	socks5Connect = 1
+
+	socks5IPv4   = 1
+	socks5Domain = 3
+	socks5IPv6   = 4
+)
+
+// socks5Replies are messages of SOCKS5 reply codes by RFC 1928
+var socks5Replies = []string{
+	1: "general failure",
+	2: "connection not allowed by ruleset",
+	3: "network unreachable",
+	4: "host unreachable",
+	5: "connection refused",
+	6: "TTL expired",
+	7: "command not supported",
+	8: "address type not supported",
+}
+
+// connectSOCKS5 establishes tunnel to addr via CONNECT command to SOCKS5 proxy over conn
+func (c *Client) connectSOCKS5(conn net.Conn, addr string) error {
+	conn.SetDeadline(time.Now().Add(c.dialTimeout()))
+	defer conn.SetDeadline(time.Time{})
+
+	greeting := []byte{socks5Version, 1, socks5NoAuth}
+	if c.ProxyUser != "" {
+		greeting = []byte{socks5Version, 2, socks5NoAuth, socks5PasswordAuth}
+	}
+	if _, err := conn.Write(greeting); err != nil {
+		return fmt.Errorf("cannot send greeting to SOCKS5 proxy: %s", err)
+	}
+	resp := make([]byte, 2)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return fmt.Errorf("cannot read response of SOCKS5 proxy: %s", err)
+	}
+	if resp[0] != socks5Version {
+		return fmt.Errorf("proxy responded with version %d instead of SOCKS5", resp[0])
+	}
+	switch resp[1] {
+	case socks5NoAuth:
+	case socks5PasswordAuth:
+		if err := c.authSOCKS5(conn); err != nil {
+			return err
+		}
+	case socks5NoAcceptable:
+		if c.ProxyUser == "" {
+			metrics.Load().proxyAuthErrors.Inc()
+			return fmt.Errorf("proxy authentication failed: SOCKS5 proxy requires authentication")
+		}
+		return fmt.Errorf("SOCKS5 proxy doesn't support username/password authentication")
+	default:
+		return fmt.Errorf("SOCKS5 proxy selected unsupported authentication method %d", resp[1])
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("cannot parse port of %q: %s", addr, err)
+	}
+	req := []byte{socks5Version, socks5Connect, 0}
+	if ip := net.ParseIP(host); ip == nil {
+		if len(host) > 255 {
+			return fmt.Errorf("host %q is too long for SOCKS5", host)
+		}
+		req = append(req, socks5Domain, byte(len(host)))
+		req = append(req, host...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		req = append(req, socks5IPv4)
+		req = append(req, ip4...)
+	} else {
+		req = append(req, socks5IPv6)
+		req = append(req, ip.To16()...)
+	}
+	req = append(req, byte(portNum>>8), byte(portNum))
+	if _, err := conn.Write(req); err != nil {
+		return fmt.Errorf("cannot send CONNECT to SOCKS5 proxy: %s", err)
+	}
+
+	// reply contains version, reply code, reserved byte and bound address
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return fmt.Errorf("cannot read response of SOCKS5 proxy: %s", err)
+	}
+	if code := int(head[1]); code != 0 {
+		msg := "unknown error"
+		if code < len(socks5Replies) {
+			msg = socks5Replies[code]
+		}
+		return fmt.Errorf("SOCKS5 proxy declined CONNECT to %s: %s", addr, msg)
+	}
+	var n int
+	switch head[3] {
+	case socks5IPv4:
+		n = net.IPv4len
+	case socks5IPv6:
+		n = net.IPv6len
+	case socks5Domain:
+		l := make([]byte, 1)
+		if _, err := io.ReadFull(conn, l); err != nil {
+			return fmt.Errorf("cannot read response of SOCKS5 proxy: %s", err)
+		}
+		n = int(l[0])
+	default:
+		return fmt.Errorf("SOCKS5 proxy responded with unsupported address type %d", head[3])
+	}
+	// bound address and port aren't needed
+	if _, err := io.ReadFull(conn, make([]byte, n+2)); err != nil {
+		return fmt.Errorf("cannot read response of SOCKS5 proxy: %s", err)
+	}
+	return nil
+}
+
+// authSOCKS5 performs username/password authentication by RFC 1929
+func (c *Client) authSOCKS5(conn net.Conn) error {
+	req := []byte{1, byte(len(c.ProxyUser))}
+	req = append(req, c.ProxyUser...)
+	req = append(req, byte(len(c.ProxyPassword)))
+	req = append(req, c.ProxyPassword...)
+	if _, err := conn.Write(req); err != nil {
+		return fmt.Errorf("cannot send credentials to SOCKS5 proxy: %s", err)
+	}
+	resp := make([]byte, 2)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return fmt.Errorf("cannot read response of SOCKS5 proxy: %s", err)
+	}
+	if resp[1] != 0 {
+		metrics.Load().proxyAuthErrors.Inc()
+		return ErrSOCKS5Auth
+	}
+	return nil
+}
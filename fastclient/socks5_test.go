@@ -0,0 +1,134 @@
+package fastclient
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// serveSOCKS5 runs SOCKS5 proxy which requires given username and password, or no authentication if user is empty
+func serveSOCKS5(t *testing.T, user, password string) net.Listener {
+	ln := listenTest(t)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				head := make([]byte, 2)
+				if _, err := io.ReadFull(conn, head); err != nil {
+					return
+				}
+				methods := make([]byte, head[1])
+				if _, err := io.ReadFull(conn, methods); err != nil {
+					return
+				}
+				if user == "" {
+					conn.Write([]byte{5, 0})
+				} else {
+					conn.Write([]byte{5, 2})
+					b := make([]byte, 2)
+					io.ReadFull(conn, b)
+					u := make([]byte, b[1])
+					io.ReadFull(conn, u)
+					io.ReadFull(conn, b[:1])
+					p := make([]byte, b[0])
+					io.ReadFull(conn, p)
+					if string(u) != user || string(p) != password {
+						conn.Write([]byte{1, 1})
+						return
+					}
+					conn.Write([]byte{1, 0})
+				}
+
+				req := make([]byte, 4)
+				if _, err := io.ReadFull(conn, req); err != nil || req[3] != socks5IPv4 {
+					return
+				}
+				addr := make([]byte, 6)
+				io.ReadFull(conn, addr)
+				port := int(addr[4])<<8 | int(addr[5])
+				target, err := net.Dial("tcp", net.JoinHostPort(net.IP(addr[:4]).String(), strconv.Itoa(port)))
+				if err != nil {
+					conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer target.Close()
+				conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0})
+				go io.Copy(target, conn)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+	return ln
+}
+
+func TestClientSOCKS5(t *testing.T) {
+	target := serveBackends(t, 1)
+	defer target.Close()
+
+	f := func(proxy net.Listener, user, password string, success, authErrors uint64) {
+		t.Helper()
+		req := new(fasthttp.Request)
+		req.SetRequestURI("http://" + target.Addr().String() + "/")
+		c := New(req, time.Second, fasthttp.StatusOK)
+		c.ProxyAddr = proxy.Addr().String()
+		c.ProxySOCKS5 = true
+		c.ProxyUser, c.ProxyPassword = user, password
+		t.Cleanup(c.Flush)
+		c.RunWorkers(1)
+		c.Jobsch <- time.Now()
+
+		waitRequests(t, c, 1)
+
+		if n := c.RequestSuccess(); n != success {
+			t.Errorf("Unexpected number of success requests. Got: %d; Expected: %d; Errors: %v", n, success, c.ErrorMessages())
+		}
+		if n := c.ProxyAuthErrors(); n != authErrors {
+			t.Errorf("Unexpected number of proxy auth errors. Got: %d; Expected: %d", n, authErrors)
+		}
+		c.Flush()
+	}
+
+	noAuth := serveSOCKS5(t, "", "")
+	defer noAuth.Close()
+	f(noAuth, "", "", 1, 0)
+
+	auth := serveSOCKS5(t, "user", "pass")
+	defer auth.Close()
+	f(auth, "user", "pass", 1, 0)
+	f(auth, "user", "wrong", 0, 1)
+}
+
+func TestClientCheckProxy(t *testing.T) {
+	target := serveBackends(t, 1)
+	defer target.Close()
+	proxy := serveSOCKS5(t, "", "")
+	defer proxy.Close()
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + target.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.ProxyAddr = proxy.Addr().String()
+	c.ProxySOCKS5 = true
+	if err := c.CheckProxy(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// closed listener is unreachable proxy
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	ln.Close()
+	c.ProxyAddr = ln.Addr().String()
+	if err := c.CheckProxy(); err == nil {
+		t.Errorf("Expected error for unreachable proxy")
+	}
+}
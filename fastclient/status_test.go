@@ -0,0 +1,42 @@
+package fastclient
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientExpectedStatusCodes(t *testing.T) {
+	codes := []int{http.StatusOK, http.StatusNoContent, http.StatusInternalServerError}
+	var n uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(codes[(atomic.AddUint32(&n, 1)-1)%uint32(len(codes))])
+	})
+
+	f := func(expected map[int]bool, success, errs uint64) {
+		t.Helper()
+		atomic.StoreUint32(&n, 0)
+		req := new(fasthttp.Request)
+		req.SetRequestURI("http://" + ln.Addr().String() + "/")
+		c := New(req, time.Second, fasthttp.StatusOK)
+		c.ExpectedStatusCodes = expected
+		defer c.Flush()
+		c.RunWorkers(1)
+		for i := 0; i < 6; i++ {
+			c.Jobsch <- time.Now()
+		}
+		waitRequests(t, c, 6)
+		if c.RequestSuccess() != success || c.Errors() != errs {
+			t.Errorf("Unexpected results for %v. Got: %d successful, %d errors; Expected: %d successful, %d errors",
+				expected, c.RequestSuccess(), c.Errors(), success, errs)
+		}
+	}
+
+	// without expected status codes responses aren't errors
+	f(nil, 2, 0)
+	f(map[int]bool{http.StatusOK: true, http.StatusNoContent: true}, 4, 2)
+	f(map[int]bool{http.StatusOK: true}, 2, 4)
+}
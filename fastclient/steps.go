@@ -0,0 +1,113 @@
+package fastclient
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Extraction extracts value of variable from response of step of scenario
+type Extraction struct {
+	// Name is a name of variable, which is referred by placeholder like "${token}"
+	Name string
+
+	// Extract returns value of variable and false if resp doesn't contain it
+	Extract func(resp *fasthttp.Response) (string, bool)
+}
+
+// placeholder matches placeholders of variables like "${token}"
+var placeholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// session is a state of scenario of single worker
+type session struct {
+	// step is an index of target, which is sent next
+	step int
+	// vars are variables extracted from responses of previous steps
+	vars map[string]string
+}
+
+// nextStep returns index of target, which is the next step of s
+func (c *Client) nextStep(s *session) int {
+	c.targetsOnce.Do(c.initTargets)
+	return s.step
+}
+
+// done moves s to the next step if step succeeded, otherwise session starts over.
+// Variables are dropped once session starts over, so every pass extracts them again
+func (s *session) done(ok bool, steps int) {
+	if ok {
+		s.step = (s.step + 1) % steps
+	} else {
+		s.step = 0
+	}
+	if s.step == 0 {
+		for k := range s.vars {
+			delete(s.vars, k)
+		}
+	}
+}
+
+// extract stores variables of StepExtractions of target from resp to s.
+// Returns message of error if any of them is missing, which is counted as error.
+// Returns empty string if all variables were extracted
+func (c *Client) extract(s *session, target int, resp *fasthttp.Response) string {
+	if target >= len(c.StepExtractions) {
+		return ""
+	}
+	for _, e := range c.StepExtractions[target] {
+		v, ok := e.Extract(resp)
+		if !ok {
+			msg := "cannot extract " + e.Name + " from response of " + c.targetLabels[target]["target"]
+			c.countError(ErrorClassResponse, msg)
+			return msg
+		}
+		s.vars[e.Name] = v
+	}
+	return ""
+}
+
+// apply replaces placeholders of variables of s in path, query, headers and body of r.
+// Placeholders of unknown variables are left as is
+func (s *session) apply(r *fasthttp.Request) {
+	if len(s.vars) == 0 {
+		return
+	}
+	uri := r.URI()
+	if path := uri.Path(); bytes.Contains(path, []byte("${")) {
+		uri.SetPathBytes(s.replace(path))
+	}
+	var replaced [][2][]byte
+	args := uri.QueryArgs()
+	args.VisitAll(func(k, v []byte) {
+		if bytes.Contains(v, []byte("${")) {
+			replaced = append(replaced, [2][]byte{append([]byte(nil), k...), s.replace(v)})
+		}
+	})
+	for _, kv := range replaced {
+		args.SetBytesKV(kv[0], kv[1])
+	}
+	replaced = replaced[:0]
+	r.Header.VisitAll(func(k, v []byte) {
+		if bytes.Contains(v, []byte("${")) {
+			replaced = append(replaced, [2][]byte{append([]byte(nil), k...), s.replace(v)})
+		}
+	})
+	for _, kv := range replaced {
+		r.Header.SetBytesKV(kv[0], kv[1])
+	}
+	if body := r.Body(); bytes.Contains(body, []byte("${")) {
+		r.SetBody(s.replace(body))
+	}
+}
+
+// replace returns b with placeholders of variables of s replaced by their values
+func (s *session) replace(b []byte) []byte {
+	return placeholder.ReplaceAllFunc(b, func(m []byte) []byte {
+		v, ok := s.vars[string(m[2:len(m)-1])]
+		if !ok {
+			return m
+		}
+		return []byte(v)
+	})
+}
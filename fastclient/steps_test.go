@@ -0,0 +1,110 @@
+package fastclient
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientSteps(t *testing.T) {
+	// login returns new token, which must be sent to item, while every third login returns no token
+	var logins, items uint32
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			if n := atomic.AddUint32(&logins, 1); n%3 != 0 {
+				w.Header().Set("X-Token", "t"+strconv.Itoa(int(n)))
+			}
+			return
+		}
+		token := r.URL.Query().Get("token")
+		if token == "" || r.Header.Get("Authorization") != "Bearer "+token || r.URL.Path != "/items/"+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		atomic.AddUint32(&items, 1)
+	})
+
+	login := new(fasthttp.Request)
+	login.SetRequestURI("http://" + ln.Addr().String() + "/login")
+	item := new(fasthttp.Request)
+	item.SetRequestURI("http://" + ln.Addr().String() + "/items/${token}?token=${token}")
+	item.Header.Set("Authorization", "Bearer ${token}")
+	c := New(login, time.Second, fasthttp.StatusOK)
+	c.Targets = []*fasthttp.Request{login, item}
+	c.TargetNames = []string{"login", "item"}
+	c.Steps = true
+	c.StepExtractions = [][]Extraction{{{Name: "token", Extract: func(resp *fasthttp.Response) (string, bool) {
+		v := resp.Header.Peek("X-Token")
+		return string(v), len(v) > 0
+	}}}}
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	for i := 0; i < 10; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 10)
+
+	// logins 1, 2, 4 and 5 are followed by items, while logins 3 and 6 start session over
+	if n := atomic.LoadUint32(&logins); n != 6 {
+		t.Errorf("Unexpected number of logins. Got: %d; Expected: 6", n)
+	}
+	if n := atomic.LoadUint32(&items); n != 4 {
+		t.Errorf("Unexpected number of authorized items. Got: %d; Expected: 4", n)
+	}
+	if n := c.Errors(); n != 2 {
+		t.Errorf("Unexpected number of errors. Got: %d; Expected: 2", n)
+	}
+	if n := c.ErrorMessages()["cannot extract token from response of login"]; n != 2 {
+		t.Errorf("Unexpected number of extraction errors. Got: %d; Expected: 2", n)
+	}
+}
+
+func TestSessionApply(t *testing.T) {
+	f := func(uri, header, body, expectedURI, expectedHeader, expectedBody string) {
+		t.Helper()
+		r := new(fasthttp.Request)
+		r.SetRequestURI(uri)
+		r.Header.Set("X-Token", header)
+		r.SetBodyString(body)
+		s := &session{vars: map[string]string{"id": "42", "token": "a b"}}
+		s.apply(r)
+		if got := r.URI().String(); got != expectedURI {
+			t.Errorf("Unexpected uri. Got: %q; Expected: %q", got, expectedURI)
+		}
+		if got := string(r.Header.Peek("X-Token")); got != expectedHeader {
+			t.Errorf("Unexpected header. Got: %q; Expected: %q", got, expectedHeader)
+		}
+		if got := string(r.Body()); got != expectedBody {
+			t.Errorf("Unexpected body. Got: %q; Expected: %q", got, expectedBody)
+		}
+	}
+
+	f("http://h/items", "x", "", "http://h/items", "x", "")
+	f("http://h/items/${id}?t=${token}&q=1", "Bearer ${token}", `{"id": ${id}}`,
+		"http://h/items/42?t=a+b&q=1", "Bearer a b", `{"id": 42}`)
+	// placeholders of unknown variables are left as is
+	f("http://h/?t=${other}", "${other}", "${other}", "http://h/?t=%24%7Bother%7D", "${other}", "${other}")
+}
+
+func TestSessionDone(t *testing.T) {
+	s := &session{vars: map[string]string{}}
+	s.vars["token"] = "t"
+	s.done(true, 3)
+	if s.step != 1 || s.vars["token"] != "t" {
+		t.Fatalf("Unexpected session after step: %+v", s)
+	}
+	s.done(false, 3)
+	if s.step != 0 || len(s.vars) != 0 {
+		t.Fatalf("Session must start over after failed step: %+v", s)
+	}
+	s.step = 2
+	s.vars["token"] = "t"
+	s.done(true, 3)
+	if s.step != 0 || len(s.vars) != 0 {
+		t.Fatalf("Session must start over after the last step: %+v", s)
+	}
+}
@@ -0,0 +1,167 @@
+package fastclient
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/ratelimiter"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+// TargetStat contains number of requests sent to target, number of them which failed and their latency
+type TargetStat struct {
+	URL      string
+	Requests uint64
+	Errors   uint64
+	// MaxQPS is a max rate of target by TargetMaxQPS. Is zero if rate isn't limited
+	MaxQPS float64
+	// Capped is a number of requests sent to other targets, since max rate of target was reached
+	Capped uint64
+
+	// Quantiles maps quantile to latency in seconds
+	Quantiles map[float64]float64
+}
+
+// initTargets creates host clients and labels of Targets.
+// Targets with the same address share host client, so they share connections
+func (c *Client) initTargets() {
+	clients := make(map[string]*fasthttp.HostClient)
+	c.targetClients = make([]*fasthttp.HostClient, len(c.Targets))
+	c.targetLabels = make([]prometheus.Labels, len(c.Targets))
+	for i, t := range c.Targets {
+		addr, _ := acquireAddr(t)
+		hc, ok := clients[addr]
+		if !ok {
+			hc = c.newHostClient(addr, c.IsTLS)
+			clients[addr] = hc
+		}
+		c.targetClients[i] = hc
+		name := t.URI().String()
+		if len(c.TargetNames) > 0 {
+			name = c.TargetNames[i]
+		}
+		c.targetLabels[i] = prometheus.Labels{"target": name}
+	}
+	if len(c.TargetWeights) > 0 {
+		c.targetWeights = newAliasTable(c.TargetWeights)
+	}
+	if len(c.TargetMaxQPS) > 0 {
+		c.targetCaps = make([]*rateCap, len(c.Targets))
+		for i, qps := range c.TargetMaxQPS {
+			if qps > 0 {
+				c.targetCaps[i] = newRateCap(qps)
+			}
+		}
+	}
+}
+
+// nextTarget returns index of target which must be requested next.
+// Targets are taken randomly by TargetWeights if they are set,
+// otherwise they are taken in round-robin by all workers
+func (c *Client) nextTarget(rnd *rand.Rand) int {
+	c.targetsOnce.Do(c.initTargets)
+	if c.targetWeights != nil {
+		return c.targetWeights.sample(rnd)
+	}
+	n := atomic.AddUint32(&c.targetsNext, 1) - 1
+	return int(n % uint32(len(c.Targets)))
+}
+
+// capTarget returns index of target, which request picked for i-th target must be sent to by TargetMaxQPS.
+// If rates of all targets are reached, request waits for i-th target
+func (c *Client) capTarget(i int, rnd *rand.Rand) int {
+	if c.targetCaps == nil || c.targetCaps[i].take() {
+		return i
+	}
+	metrics.Load().targetCapped.With(c.targetLabels[i]).Inc()
+	for {
+		var other []int
+		var sum float64
+		for j := range c.Targets {
+			if j != i && c.targetCaps[j].ready() {
+				other = append(other, j)
+				sum += c.targetWeight(j)
+			}
+		}
+		if len(other) == 0 {
+			for !c.targetCaps[i].take() {
+				select {
+				case <-time.After(ratelimiter.TickPeriod):
+				case <-c.stop:
+					// worker is stopped by Flush, so it doesn't wait
+					return i
+				}
+			}
+			return i
+		}
+		x := rnd.Float64() * sum
+		j := other[len(other)-1]
+		for _, k := range other {
+			if x -= c.targetWeight(k); x < 0 {
+				j = k
+				break
+			}
+		}
+		// rate of j could be reached by other workers meanwhile
+		if c.targetCaps[j].take() {
+			return j
+		}
+	}
+}
+
+// targetWeight returns weight of i-th target. Targets without TargetWeights are equal
+func (c *Client) targetWeight(i int) float64 {
+	if len(c.TargetWeights) == 0 {
+		return 1
+	}
+	return c.TargetWeights[i]
+}
+
+// observeTarget counts request to i-th target, which took d seconds
+func (c *Client) observeTarget(i int, failed bool, d float64) {
+	m := metrics.Load()
+	m.targetRequests.With(c.targetLabels[i]).Inc()
+	if failed {
+		m.targetErrors.With(c.targetLabels[i]).Inc()
+	}
+	m.targetDuration.With(c.targetLabels[i]).Observe(d)
+}
+
+// TargetStats returns number of requests, errors and latency for every target in order of Targets.
+// Targets with the same name are reported once. Is empty if Targets aren't set
+func (c *Client) TargetStats() []TargetStat {
+	c.targetsOnce.Do(c.initTargets)
+	var result []TargetStat
+	seen := make(map[string]bool, len(c.targetLabels))
+	for i, label := range c.targetLabels {
+		if seen[label["target"]] {
+			continue
+		}
+		seen[label["target"]] = true
+		requests, errs, duration := &dto.Metric{}, &dto.Metric{}, &dto.Metric{}
+		metrics.Load().targetRequests.With(label).Write(requests)
+		metrics.Load().targetErrors.With(label).Write(errs)
+		metrics.Load().targetDuration.With(label).(prometheus.Metric).Write(duration)
+		capped := &dto.Metric{}
+		metrics.Load().targetCapped.With(label).Write(capped)
+		ts := TargetStat{
+			URL:       label["target"],
+			Requests:  uint64(*requests.Counter.Value),
+			Errors:    uint64(*errs.Counter.Value),
+			Capped:    uint64(*capped.Counter.Value),
+			Quantiles: make(map[float64]float64, len(duration.Summary.Quantile)),
+		}
+		for _, q := range duration.Summary.Quantile {
+			ts.Quantiles[*q.Quantile] = *q.Value
+		}
+		if len(c.TargetMaxQPS) > 0 {
+			ts.MaxQPS = c.TargetMaxQPS[i]
+		}
+		result = append(result, ts)
+	}
+	return result
+}
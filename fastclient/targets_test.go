@@ -0,0 +1,115 @@
+package fastclient
+
+import (
+	"net"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientTargets(t *testing.T) {
+	// the first server fails all requests to /fail
+	var hits [2]uint32
+	var lns []net.Listener
+	for i := range hits {
+		n := &hits[i]
+		ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddUint32(n, 1)
+			if r.URL.Path == "/fail" {
+				// connection is closed without response
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+			}
+		})
+		lns = append(lns, ln)
+	}
+
+	var targets []*fasthttp.Request
+	for _, url := range []string{
+		"http://" + lns[0].Addr().String() + "/",
+		"http://" + lns[0].Addr().String() + "/fail",
+		"http://" + lns[1].Addr().String() + "/",
+	} {
+		r := new(fasthttp.Request)
+		r.SetRequestURI(url)
+		r.Header.SetMethod(fasthttp.MethodPost)
+		targets = append(targets, r)
+	}
+	c := New(targets[0], time.Second, fasthttp.StatusOK)
+	c.Targets = targets
+	t.Cleanup(c.Flush)
+	c.RunWorkers(3)
+	for i := 0; i < 9; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, 9)
+
+	expected := []TargetStat{
+		{URL: targets[0].URI().String(), Requests: 3},
+		{URL: targets[1].URI().String(), Requests: 3, Errors: 3},
+		{URL: targets[2].URI().String(), Requests: 3},
+	}
+	got := c.TargetStats()
+	for i := range got {
+		if d := got[i].Quantiles[0.5]; len(got[i].Quantiles) == 0 || d <= 0 {
+			t.Errorf("Unexpected median latency of %s: %f", got[i].URL, d)
+		}
+		got[i].Quantiles = nil
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected target stats. Got: %+v; Expected: %+v", got, expected)
+	}
+	if a, b := atomic.LoadUint32(&hits[0]), atomic.LoadUint32(&hits[1]); a != 6 || b != 3 {
+		t.Errorf("Unexpected number of requests received by servers. Got: %d and %d; Expected: 6 and 3", a, b)
+	}
+}
+
+func TestClientTargetMaxQPS(t *testing.T) {
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	var targets []*fasthttp.Request
+	for _, path := range []string{"/report", "/items"} {
+		r := new(fasthttp.Request)
+		r.SetRequestURI("http://" + ln.Addr().String() + path)
+		targets = append(targets, r)
+	}
+	f := func(maxQPS []float64, jobs int, period time.Duration) []TargetStat {
+		t.Helper()
+		c := New(targets[0], time.Second, fasthttp.StatusOK)
+		c.Targets = targets
+		c.TargetWeights = []float64{90, 10}
+		c.TargetMaxQPS = maxQPS
+		defer c.Flush()
+		c.RunWorkers(4)
+		for i := 0; i < jobs; i++ {
+			c.Jobsch <- time.Now()
+			time.Sleep(period)
+		}
+		waitRequests(t, c, uint64(jobs))
+		return c.TargetStats()
+	}
+
+	// 200 qps are offered for 1s, so the first target gets 20 qps and the rest of requests
+	// are sent to the second one instead of 10 % of them
+	got := f([]float64{20, 0}, 200, 5*time.Millisecond)
+	if got[0].Requests > 25 || got[0].Capped == 0 || got[0].MaxQPS != 20 {
+		t.Errorf("Max rate of the first target must be binding. Got: %+v", got[0])
+	}
+	if got[1].Requests+got[0].Requests != 200 || got[1].Capped != 0 || got[1].MaxQPS != 0 {
+		t.Errorf("Requests over max rate of the first target must be sent to the second one. Got: %+v", got[1])
+	}
+
+	// rates of both targets are reached, so requests wait for them
+	start := time.Now()
+	got = f([]float64{50, 50}, 20, 0)
+	if d := time.Since(start); d < 150*time.Millisecond {
+		t.Errorf("Requests must be limited by max rates of targets. Got: 20 requests in %s; Expected: at least 150ms", d)
+	}
+	if got[0].Requests+got[1].Requests != 20 {
+		t.Errorf("Unexpected number of requests. Got: %d and %d; Expected: 20 in total", got[0].Requests, got[1].Requests)
+	}
+}
@@ -0,0 +1,49 @@
+package fastclient
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// listenTest starts listener on random local port, which is closed once test finishes
+func listenTest(t *testing.T) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	return ln
+}
+
+// startTestServer starts net/http server with handler, which is stopped once test finishes
+func startTestServer(t *testing.T, handler http.HandlerFunc) net.Listener {
+	t.Helper()
+	ln := listenTest(t)
+	go http.Serve(ln, handler)
+	return ln
+}
+
+// startFastTestServer starts fasthttp server with handler, which is stopped once test finishes
+func startFastTestServer(t *testing.T, handler fasthttp.RequestHandler) net.Listener {
+	t.Helper()
+	ln := listenTest(t)
+	go fasthttp.Serve(ln, handler)
+	return ln
+}
+
+// waitRequests waits until n requests of c are finished. Test fails if they aren't finished in 5s
+func waitRequests(t *testing.T, c *Client, n uint64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for c.RequestSum() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Requests weren't done in time. Got: %d; Expected: %d", c.RequestSum(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
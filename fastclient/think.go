@@ -0,0 +1,31 @@
+package fastclient
+
+import (
+	"math/rand"
+	"time"
+)
+
+// think pauses worker for ThinkTime with jitter. Returns false if worker
+// is stopped by Flush or StopWorkers during the pause
+func (c *Client) think(rnd *rand.Rand) bool {
+	d := thinkDuration(c.ThinkTime, c.ThinkTimeJitter, rnd.Float64())
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-c.stop:
+		return false
+	case <-c.quit:
+		return false
+	}
+	metrics.Load().thinkTime.Add(d.Seconds())
+	return true
+}
+
+// thinkDuration returns pause within d*(1±jitter) for random value rnd in range [0..1)
+func thinkDuration(d time.Duration, jitter, rnd float64) time.Duration {
+	if jitter <= 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + jitter*(2*rnd-1)))
+}
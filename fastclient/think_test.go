@@ -0,0 +1,53 @@
+package fastclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestThinkDuration(t *testing.T) {
+	f := func(jitter, rnd float64, expected time.Duration) {
+		t.Helper()
+		if d := thinkDuration(100*time.Millisecond, jitter, rnd); d != expected {
+			t.Errorf("Unexpected think time for jitter %v and rnd %v. Got: %s; Expected: %s", jitter, rnd, d, expected)
+		}
+	}
+
+	f(0, 0.9, 100*time.Millisecond)
+	f(0.2, 0, 80*time.Millisecond)
+	f(0.2, 0.5, 100*time.Millisecond)
+	f(0.2, 0.75, 110*time.Millisecond)
+	f(1, 0, 0)
+}
+
+func TestClientThinkTime(t *testing.T) {
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	flushMetrics()
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.ClosedLoop = true
+	c.ThinkTime = 100 * time.Millisecond
+	t.Cleanup(c.Flush)
+	c.RunWorkers(2)
+	time.Sleep(550 * time.Millisecond)
+	n, think := c.RequestSum(), c.ThinkTimeSpent()
+	// workers must not wait for the end of pause once they are stopped
+	s := time.Now()
+	c.Flush()
+	if d := time.Since(s); d > 50*time.Millisecond {
+		t.Errorf("Flush waited for think time of workers: %s", d)
+	}
+
+	// every worker sends the first request right away and then one request per 100ms
+	if n < 8 || n > 12 {
+		t.Errorf("Unexpected number of requests of 2 workers thinking 100ms for 550ms: %d", n)
+	}
+	if expected := time.Duration(n-2) * c.ThinkTime; think < expected-c.ThinkTime || think > expected {
+		t.Errorf("Unexpected think time of %d requests. Got: %s; Expected about %s", n, think, expected)
+	}
+}
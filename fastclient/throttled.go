@@ -0,0 +1,96 @@
+package fastclient
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/valyala/fasthttp"
+)
+
+// Statuses of throttled responses
+const (
+	// ThrottledTooManyRequests means target responded with 429 Too Many Requests
+	ThrottledTooManyRequests = "429"
+	// ThrottledUnavailable means target responded with 503 Service Unavailable and Retry-After header
+	ThrottledUnavailable = "503"
+)
+
+var throttledLabels = map[string]prometheus.Labels{
+	ThrottledTooManyRequests: {"status": ThrottledTooManyRequests},
+	ThrottledUnavailable:     {"status": ThrottledUnavailable},
+}
+
+// maxRetryAfter is a max delay of Retry-After header of throttled responses in nanoseconds
+var maxRetryAfter int64
+
+// observeThrottled counts resp if target throttled request,
+// and updates max delay of its Retry-After header
+func observeThrottled(resp *fasthttp.Response) {
+	retryAfter := resp.Header.Peek(fasthttp.HeaderRetryAfter)
+	switch resp.StatusCode() {
+	case fasthttp.StatusTooManyRequests:
+		metrics.Load().throttledResponses.With(throttledLabels[ThrottledTooManyRequests]).Inc()
+	case fasthttp.StatusServiceUnavailable:
+		if len(retryAfter) == 0 {
+			return
+		}
+		metrics.Load().throttledResponses.With(throttledLabels[ThrottledUnavailable]).Inc()
+	default:
+		return
+	}
+	d, ok := parseRetryAfter(retryAfter, time.Now())
+	if !ok {
+		return
+	}
+	for {
+		old := atomic.LoadInt64(&maxRetryAfter)
+		if int64(d) <= old || atomic.CompareAndSwapInt64(&maxRetryAfter, old, int64(d)) {
+			return
+		}
+	}
+}
+
+// parseRetryAfter parses delay of Retry-After header, which is number of seconds or HTTP date.
+// Dates before now mean zero delay
+func parseRetryAfter(v []byte, now time.Time) (time.Duration, bool) {
+	if len(v) == 0 {
+		return 0, false
+	}
+	if n, err := strconv.Atoi(string(v)); err == nil {
+		if n < 0 {
+			return 0, false
+		}
+		return time.Duration(n) * time.Second, true
+	}
+	t, err := fasthttp.ParseHTTPDate(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// Throttled returns map status:value for throttledResponses-metric where value
+// is a number of throttled responses with such status. Statuses without responses are omitted
+func (*Client) Throttled() map[string]uint64 {
+	result := make(map[string]uint64)
+	for status, label := range throttledLabels {
+		m := &dto.Metric{}
+		metrics.Load().throttledResponses.With(label).Write(m)
+		if n := uint64(*m.Counter.Value); n > 0 {
+			result[status] = n
+		}
+	}
+	return result
+}
+
+// RetryAfter returns max delay of Retry-After header of throttled responses.
+// Is zero if they had no valid Retry-After
+func (*Client) RetryAfter() time.Duration {
+	return time.Duration(atomic.LoadInt64(&maxRetryAfter))
+}
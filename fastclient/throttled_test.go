@@ -0,0 +1,71 @@
+package fastclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := func(v string, expected time.Duration, expectedOK bool) {
+		t.Helper()
+		d, ok := parseRetryAfter([]byte(v), now)
+		if d != expected || ok != expectedOK {
+			t.Errorf("Unexpected delay of %q. Got: %s, %v; Expected: %s, %v", v, d, ok, expected, expectedOK)
+		}
+	}
+
+	f("", 0, false)
+	f("5", 5*time.Second, true)
+	f("0", 0, true)
+	f("-1", 0, false)
+	f("soon", 0, false)
+	f("Mon, 01 Jan 2024 00:00:30 GMT", 30*time.Second, true)
+	f("Sun, 31 Dec 2023 23:59:00 GMT", 0, true)
+}
+
+func TestClientThrottled(t *testing.T) {
+	ln := listenTest(t)
+	s := &fasthttp.Server{
+		Handler: func(ctx *fasthttp.RequestCtx) {
+			switch string(ctx.Path()) {
+			case "/429":
+				ctx.Response.Header.Set(fasthttp.HeaderRetryAfter, "2")
+				ctx.SetStatusCode(fasthttp.StatusTooManyRequests)
+			case "/503":
+				ctx.Response.Header.Set(fasthttp.HeaderRetryAfter, "7")
+				ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			case "/down":
+				// 503 without Retry-After isn't throttling
+				ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			}
+		},
+	}
+	go s.Serve(ln)
+
+	var targets []*fasthttp.Request
+	for _, path := range []string{"/", "/429", "/503", "/down"} {
+		r := new(fasthttp.Request)
+		r.SetRequestURI("http://" + ln.Addr().String() + path)
+		targets = append(targets, r)
+	}
+	c := New(targets[0], time.Second, fasthttp.StatusOK)
+	c.Targets = targets
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	const requests = 8
+	for i := 0; i < requests; i++ {
+		c.Jobsch <- time.Now()
+	}
+	waitRequests(t, c, requests)
+
+	got := c.Throttled()
+	if got[ThrottledTooManyRequests] != 2 || got[ThrottledUnavailable] != 2 || len(got) != 2 {
+		t.Errorf("Unexpected throttled responses. Got: %v; Expected: 2 of 429 and 2 of 503", got)
+	}
+	if d := c.RetryAfter(); d != 7*time.Second {
+		t.Errorf("Unexpected max Retry-After. Got: %s; Expected: 7s", d)
+	}
+}
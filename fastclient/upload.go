@@ -0,0 +1,40 @@
+package fastclient
+
+import (
+	"time"
+)
+
+// uploadChunkPeriod is a period of sending of every chunk of request throttled by Client.UploadRate
+const uploadChunkPeriod = 50 * time.Millisecond
+
+// writeThrottled writes p to connection not faster than uploadRate bytes per second
+// since the first write of request. Timeouts of such writes are counted as upload stalls,
+// since they mean that target doesn't read request at the rate it is sent
+func (hc *hostConn) writeThrottled(p []byte) (int, error) {
+	chunk := int(hc.uploadRate * uploadChunkPeriod.Seconds())
+	if chunk < 1 {
+		chunk = 1
+	}
+	var written int
+	for written < len(p) {
+		// sleep until chunk is allowed by rate
+		at := hc.uploadStart.Add(time.Duration(float64(hc.uploaded) / hc.uploadRate * float64(time.Second)))
+		if d := time.Until(at); d > 0 {
+			time.Sleep(d)
+		}
+		end := written + chunk
+		if end > len(p) {
+			end = len(p)
+		}
+		n, err := hc.Conn.Write(p[written:end])
+		written += n
+		hc.uploaded += n
+		if err != nil {
+			if isTimeout(err) {
+				metrics.Load().uploadStalls.Inc()
+			}
+			return written, err
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,36 @@
+package fastclient
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientUploadRate(t *testing.T) {
+	ln := serveBackends(t, 1)
+	defer ln.Close()
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.SetBody(bytes.Repeat([]byte("a"), 10<<10))
+	c := New(req, 5*time.Second, fasthttp.StatusOK)
+	// body alone takes 0.5s at this rate
+	c.UploadRate = 20 << 10
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	c.Jobsch <- time.Now()
+
+	waitRequests(t, c, 1)
+	if c.Errors() > 0 {
+		t.Fatalf("Unexpected errors: %v", c.ErrorMessages())
+	}
+	if d := c.RequestDuration()[0.5]; d < 0.5 || d > 1 {
+		t.Errorf("Unexpected latency of throttled request. Got: %fs; Expected: 0.5s", d)
+	}
+	if n := c.UploadStalls(); n != 0 {
+		t.Errorf("Unexpected number of upload stalls. Got: %d; Expected: 0", n)
+	}
+}
@@ -0,0 +1,64 @@
+package fastclient
+
+import (
+	"bufio"
+	"net"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// warmup sends WarmupRequests throwaway requests over conn, which is established over hc.
+// Latency of the first of them is stored to firstRequestDuration
+func (c *Client) warmup(hc *hostConn, conn net.Conn) (net.Conn, error) {
+	hc.warmup = true
+
+	req := new(fasthttp.Request)
+	c.request.CopyTo(req)
+	var resp fasthttp.Response
+	br := bufio.NewReader(conn)
+	bw := bufio.NewWriter(conn)
+	for i := 0; i < c.WarmupRequests; i++ {
+		if err := c.modifyWarmup(req); err != nil {
+			// warmup request is thrown away, so it is sent unmodified
+			c.request.CopyTo(req)
+		}
+		s := time.Now()
+		conn.SetDeadline(s.Add(c.ReadTimeout))
+		err := req.Write(bw)
+		if err == nil {
+			err = bw.Flush()
+		}
+		if err == nil {
+			err = resp.Read(br)
+		}
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if i == 0 {
+			observeFirstDuration(time.Since(s).Seconds())
+		}
+	}
+	conn.SetDeadline(time.Time{})
+	metrics.Load().warmedConns.Inc()
+	hc.requests = c.WarmupRequests
+
+	hc.warmup = false
+	return conn, nil
+}
+
+// modifyWarmup applies Modifier to warmup request.
+// Connections are dialed concurrently, so single Modifier is guarded by mutex
+func (c *Client) modifyWarmup(req *fasthttp.Request) error {
+	if c.NewModifier == nil {
+		return nil
+	}
+	c.warmupMu.Lock()
+	if c.warmupModify == nil {
+		c.warmupModify = c.NewModifier()
+	}
+	err := c.warmupModify(req)
+	c.warmupMu.Unlock()
+	return err
+}
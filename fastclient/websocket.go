@@ -0,0 +1,284 @@
+package fastclient
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// maxWSMessageSize is a max size of message received over WebSocket.
+// Bigger messages are counted as errors, so single response can't exhaust memory
+const maxWSMessageSize = 64 << 20
+
+// wsGUID is appended to Sec-WebSocket-Key to calculate Sec-WebSocket-Accept
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// opcodes of WebSocket frames
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// errWSClosed is returned once target sends close frame
+var errWSClosed = fmt.Errorf("WebSocket connection is closed by target")
+
+// wsConn is a WebSocket connection of single worker
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+	// buf is reused for masked payloads of written frames
+	buf []byte
+	msg []byte
+}
+
+// runWebSocket is a loop of worker, which keeps WebSocket connection to target and sends
+// message every 1/WSRate seconds over it, measuring time until the next message of target.
+// Messages are bodies of request after modify. Connection is dialed again after failures
+func (c *Client) runWebSocket(modify Modifier) {
+	r := new(fasthttp.Request)
+	var ws *wsConn
+	defer func() {
+		if ws != nil {
+			ws.close()
+		}
+	}()
+	t := time.NewTicker(time.Duration(float64(time.Second) / c.WSRate))
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-c.stop:
+			return
+		case <-c.quit:
+			return
+		}
+		if !c.takeJob() {
+			return
+		}
+		if ws == nil {
+			var err error
+			if ws, err = c.dialWebSocket(); err != nil {
+				metrics.Load().wsConnectFailures.Inc()
+				if err == fasthttp.ErrDialTimeout || isTimeout(err) {
+					metrics.Load().timeouts.Inc()
+				}
+				c.countError(errorClass(err), err.Error())
+				metrics.Load().requestSum.Inc()
+				metrics.Load().jobsDone.Inc()
+				continue
+			}
+			metrics.Load().wsConnects.Inc()
+		}
+		c.request.CopyTo(r)
+		if modify != nil {
+			if err := modify(r); err != nil {
+				c.countError(ErrorClassModifier, "cannot modify request: "+err.Error())
+				metrics.Load().requestSum.Inc()
+				metrics.Load().jobsDone.Inc()
+				continue
+			}
+		}
+		s := time.Now()
+		c.inFlight.Add(1)
+		err := ws.roundTrip(r.Body(), c.timeout)
+		d := time.Since(s)
+		class := ""
+		if err != nil {
+			if isTimeout(err) {
+				// late reply would be taken as reply to the next message, so connection is closed anyway
+				metrics.Load().timeouts.Inc()
+			} else {
+				metrics.Load().wsDrops.Inc()
+			}
+			class = errorClass(err)
+			c.countError(class, err.Error())
+			ws.close()
+			ws = nil
+		} else {
+			c.consecutiveErrors.Store(0)
+			metrics.Load().requestSuccess.Inc()
+		}
+		observeDuration(d.Seconds())
+		if c.OnSample != nil {
+			c.OnSample(Sample{Start: s, Duration: d, ErrorClass: class})
+		}
+		metrics.Load().requestSum.Inc()
+		metrics.Load().jobsDone.Inc()
+		c.inFlight.Add(-1)
+	}
+}
+
+// dialWebSocket establishes connection to target and upgrades it to WebSocket
+// with path and headers of request of c
+func (c *Client) dialWebSocket() (*wsConn, error) {
+	conn, err := c.dial(c.HostClient.Addr)
+	if err != nil {
+		return nil, err
+	}
+	ws := &wsConn{conn: conn, br: bufio.NewReader(conn)}
+	if err := ws.handshake(c.request, c.timeout); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+// handshake sends upgrade request with path and headers of req and checks response of target
+func (ws *wsConn) handshake(req *fasthttp.Request, timeout time.Duration) error {
+	key := make([]byte, 16)
+	rand.Read(key)
+	k := base64.StdEncoding.EncodeToString(key)
+
+	b := fmt.Appendf(nil, "GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n", req.URI().RequestURI(), req.Host(), k)
+	req.Header.VisitAll(func(key, value []byte) {
+		switch string(key) {
+		case "Host", "Content-Type", "Content-Length", "Connection", "Upgrade", "Accept-Encoding", "Transfer-Encoding":
+			return
+		}
+		b = fmt.Appendf(b, "%s: %s\r\n", key, value)
+	})
+	b = append(b, "\r\n"...)
+
+	ws.conn.SetDeadline(time.Now().Add(timeout))
+	defer ws.conn.SetDeadline(time.Time{})
+	if _, err := ws.conn.Write(b); err != nil {
+		return err
+	}
+	var h fasthttp.ResponseHeader
+	if err := h.Read(ws.br); err != nil {
+		return err
+	}
+	if h.StatusCode() != fasthttp.StatusSwitchingProtocols {
+		return fmt.Errorf("unexpected status code %d of WebSocket handshake", h.StatusCode())
+	}
+	sum := sha1.Sum([]byte(k + wsGUID))
+	if string(h.Peek("Sec-WebSocket-Accept")) != base64.StdEncoding.EncodeToString(sum[:]) {
+		return fmt.Errorf("invalid Sec-WebSocket-Accept of WebSocket handshake")
+	}
+	return nil
+}
+
+// roundTrip sends text message and waits for the next data message of target.
+// Pings of target are answered while waiting
+func (ws *wsConn) roundTrip(msg []byte, timeout time.Duration) error {
+	ws.conn.SetDeadline(time.Now().Add(timeout))
+	if err := ws.writeFrame(wsText, msg); err != nil {
+		return err
+	}
+	ws.msg = ws.msg[:0]
+	for {
+		fin, op, payload, err := ws.readFrame()
+		if err != nil {
+			return err
+		}
+		switch op {
+		case wsClose:
+			return errWSClosed
+		case wsPing:
+			if err := ws.writeFrame(wsPong, payload); err != nil {
+				return err
+			}
+			continue
+		case wsPong:
+			continue
+		}
+		ws.msg = append(ws.msg, payload...)
+		if fin {
+			return nil
+		}
+	}
+}
+
+// writeFrame writes single masked frame of payload, as client frames must be masked
+func (ws *wsConn) writeFrame(op byte, payload []byte) error {
+	b := append(ws.buf[:0], 0x80|op)
+	n := len(payload)
+	switch {
+	case n < 126:
+		b = append(b, 0x80|byte(n))
+	case n <= 0xFFFF:
+		b = append(b, 0x80|126)
+		b = binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0x80|127)
+		b = binary.BigEndian.AppendUint64(b, uint64(n))
+	}
+	mask := rand.Uint32()
+	b = binary.BigEndian.AppendUint32(b, mask)
+	start := len(b)
+	b = append(b, payload...)
+	maskBytes(b[start:], b[start-4:start])
+	ws.buf = b
+	_, err := ws.conn.Write(b)
+	return err
+}
+
+// readFrame reads single frame of target
+func (ws *wsConn) readFrame() (bool, byte, []byte, error) {
+	var h [2]byte
+	if _, err := io.ReadFull(ws.br, h[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, op, masked := h[0]&0x80 != 0, h[0]&0x0F, h[1]&0x80 != 0
+	n := uint64(h[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n+uint64(len(ws.msg)) > maxWSMessageSize {
+		return false, 0, nil, fmt.Errorf("WebSocket message exceeds %d bytes", maxWSMessageSize)
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(ws.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(ws.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		maskBytes(payload, mask[:])
+	}
+	if op != wsContinuation && op != wsText && op != wsBinary && op != wsClose && op != wsPing && op != wsPong {
+		return false, 0, nil, fmt.Errorf("unknown opcode %d of WebSocket frame", op)
+	}
+	return fin, op, payload, nil
+}
+
+func maskBytes(b, mask []byte) {
+	for i := range b {
+		b[i] ^= mask[i%4]
+	}
+}
+
+// close sends close frame without waiting for reply of target and closes connection
+func (ws *wsConn) close() {
+	ws.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	ws.writeFrame(wsClose, []byte{0x03, 0xE8})
+	ws.conn.Close()
+}
@@ -0,0 +1,112 @@
+package fastclient
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// serveWebSocket upgrades connection and echoes messages of client. Ping precedes every echo,
+// and connection is dropped after dropAfter messages
+func serveWebSocket(t *testing.T, dropAfter int, token *atomic.Value) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token.Store(r.Header.Get("Authorization") + " " + r.URL.RequestURI())
+		sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + wsGUID))
+		w.Header().Set("Upgrade", "websocket")
+		w.Header().Set("Connection", "Upgrade")
+		w.Header().Set("Sec-WebSocket-Accept", base64.StdEncoding.EncodeToString(sum[:]))
+		w.WriteHeader(http.StatusSwitchingProtocols)
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Cannot hijack connection: %s", err)
+			return
+		}
+		defer conn.Close()
+		ws := &wsConn{conn: conn, br: brw.Reader}
+		for i := 0; i < dropAfter; i++ {
+			_, op, payload, err := ws.readFrame()
+			if err != nil || op != wsText {
+				return
+			}
+			// frames of server aren't masked
+			conn.Write([]byte{0x80 | wsPing, 0})
+			if _, op, _, err := ws.readFrame(); err != nil || op != wsPong {
+				t.Errorf("Expected pong; got opcode %d: %v", op, err)
+				return
+			}
+			conn.Write(append([]byte{0x80 | wsText, byte(len(payload))}, payload...))
+		}
+	})
+}
+
+func TestClientWebSocket(t *testing.T) {
+	ln := listenTest(t)
+	var token atomic.Value
+	go http.Serve(ln, serveWebSocket(t, 3, &token))
+
+	flushMetrics()
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/chat?room=1")
+	req.Header.Set("Authorization", "Bearer 123")
+	req.SetBodyString(`{"text": "hello"}`)
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.ClosedLoop = true
+	c.WebSocket = true
+	c.WSRate = 100
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	deadline := time.Now().Add(5 * time.Second)
+	for c.WSConnects() < 2 || c.RequestSum() < 5 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Messages weren't sent in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer c.Flush()
+
+	if s := token.Load(); s != "Bearer 123 /chat?room=1" {
+		t.Errorf("Unexpected headers and path of handshake: %q", s)
+	}
+	if n := c.WSDrops(); n < 1 {
+		t.Errorf("Dropped connection isn't counted")
+	}
+	if c.RequestSuccess() < 3 || c.Errors() < 1 {
+		t.Errorf("Unexpected number of successful messages and errors: %d and %d", c.RequestSuccess(), c.Errors())
+	}
+	if c.BytesWritten() == 0 || c.BytesRead() == 0 {
+		t.Errorf("Bytes of WebSocket connections aren't counted")
+	}
+}
+
+func TestClientWebSocketHandshakeFailure(t *testing.T) {
+	ln := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	flushMetrics()
+	req := new(fasthttp.Request)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := New(req, time.Second, fasthttp.StatusOK)
+	c.ClosedLoop = true
+	c.WebSocket = true
+	c.WSRate = 100
+	t.Cleanup(c.Flush)
+	c.RunWorkers(1)
+	deadline := time.Now().Add(5 * time.Second)
+	for c.WSConnectFailures() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Failed connections weren't counted in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	msgs := c.ErrorMessages()
+	c.Flush()
+	if msgs["unexpected status code 403 of WebSocket handshake"] < 2 {
+		t.Errorf("Unexpected error messages: %v", msgs)
+	}
+}
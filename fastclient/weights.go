@@ -0,0 +1,61 @@
+package fastclient
+
+import (
+	"math/rand"
+)
+
+// aliasTable samples indexes with given weights in constant time by Vose's alias method
+type aliasTable struct {
+	// prob is a probability to take index of column instead of its alias
+	prob  []float64
+	alias []int
+}
+
+// newAliasTable creates table for positive weights
+func newAliasTable(weights []float64) *aliasTable {
+	n := len(weights)
+	t := &aliasTable{
+		prob:  make([]float64, n),
+		alias: make([]int, n),
+	}
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	// scaled weights are split into columns of height 1,
+	// so every column holds part of small weight and rest of large one
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / sum
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		t.prob[s], t.alias[s] = scaled[s], l
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+	// remaining columns are full up to rounding errors
+	for _, i := range append(small, large...) {
+		t.prob[i], t.alias[i] = 1, i
+	}
+	return t
+}
+
+// sample returns random index with probability proportional to its weight
+func (t *aliasTable) sample(rnd *rand.Rand) int {
+	i := rnd.Intn(len(t.prob))
+	if rnd.Float64() < t.prob[i] {
+		return i
+	}
+	return t.alias[i]
+}
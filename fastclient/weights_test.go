@@ -0,0 +1,36 @@
+package fastclient
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAliasTable(t *testing.T) {
+	f := func(weights []float64) {
+		t.Helper()
+		table := newAliasTable(weights)
+		rnd := rand.New(rand.NewSource(1))
+		const n = 100000
+		counts := make([]int, len(weights))
+		for i := 0; i < n; i++ {
+			counts[table.sample(rnd)]++
+		}
+		var sum float64
+		for _, w := range weights {
+			sum += w
+		}
+		for i, w := range weights {
+			expected := w / sum
+			if got := float64(counts[i]) / n; math.Abs(got-expected) > 0.01 {
+				t.Errorf("Unexpected share of index %d for weights %v. Got: %.3f; Expected: %.3f", i, weights, got, expected)
+			}
+		}
+	}
+
+	f([]float64{1})
+	f([]float64{1, 1})
+	f([]float64{80, 15, 5})
+	f([]float64{0.1, 0.2, 0.3, 0.4})
+	f([]float64{1000, 1, 1, 1})
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+// formFieldList is a list of fields of multipart body set by repeated -form flag
+type formFieldList []fastclient.FormField
+
+func (l *formFieldList) String() string {
+	var fields []string
+	for _, f := range *l {
+		fields = append(fields, f.Name)
+	}
+	return strings.Join(fields, ", ")
+}
+
+func (l *formFieldList) Set(s string) error {
+	f, err := parseFormField(s)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, f)
+	return nil
+}
+
+var formFields formFieldList
+
+func init() {
+	flag.Var(&formFields, "form", "Add field of multipart/form-data body like \"name=value\", or file field like \"file=@upload.bin\". "+
+		"Is set multiple times. Every request gets its own boundary, and files are streamed from disk instead of being kept in memory")
+}
+
+// form is a multipart body of -form. Is nil unless -form is set
+var form *fastclient.Form
+
+// parseFormField parses field of multipart body like "name=value" or "name=@path"
+func parseFormField(s string) (fastclient.FormField, error) {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fastclient.FormField{}, fmt.Errorf("form field %q must be in format \"name=value\" or \"name=@file\"", s)
+	}
+	if strings.HasPrefix(value, "@") {
+		if value == "@" {
+			return fastclient.FormField{}, fmt.Errorf("form field %q has no file name", s)
+		}
+		return fastclient.FormField{Name: name, File: value[1:]}, nil
+	}
+	return fastclient.FormField{Name: name, Value: value}, nil
+}
+
+// parseFormFields parses fields of multipart body of scenario entry
+func parseFormFields(fields []string) (*fastclient.Form, error) {
+	var ff []fastclient.FormField
+	for _, s := range fields {
+		f, err := parseFormField(s)
+		if err != nil {
+			return nil, err
+		}
+		ff = append(ff, f)
+	}
+	return fastclient.NewForm(ff)
+}
+
+// checkStreamedForm checks that multipart bodies, which are streamed, can be sent with other flags
+func checkStreamedForm(name string) {
+	if *retryPolicyFlag != "" || *compressBody != "" || *grpcMethod != "" {
+		// stream of body can't be sent twice or modified
+		usageAndExit(name + " can't be used with -retry-policy, -compress-body or -grpc-method, since multipart body is streamed")
+	}
+}
+
+// applyForm builds multipart body of -form, which replaces body of every request
+func applyForm() {
+	if isFlagSet("b") || *bodyFile != "" || *curlFlag != "" || *dataFile != "" || *genBodySizeFlag != "" || *bodySizeSweep != "" {
+		usageAndExit("-form can't be used with -b, -body-file, -curl, -data, -gen-body-size or -body-size-sweep, since body is multipart")
+	}
+	if *scenarioFile != "" || *probeFile != "" || *replayFile != "" || *replayHAR != "" {
+		usageAndExit("-form can't be used with -scenario, -probe or replay; set form of scenario entries instead")
+	}
+	if isFlagSet("T") {
+		usageAndExit("-form can't be used with -T, since content type contains boundary of multipart body")
+	}
+	checkStreamedForm("-form")
+	if !isFlagSet("m") {
+		*method = "POST"
+	} else if !methodAllowsBody(*method) {
+		usageAndExit(fmt.Sprintf("-form can't be used with %s method, since it can't send body", strings.ToUpper(*method)))
+	}
+	var err error
+	if form, err = fastclient.NewForm(formFields); err != nil {
+		usageAndExit(err.Error())
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+func TestParseFormField(t *testing.T) {
+	f := func(s string, expected fastclient.FormField, ok bool) {
+		t.Helper()
+		field, err := parseFormField(s)
+		if (err == nil) != ok {
+			t.Fatalf("Unexpected error for %q: %v", s, err)
+		}
+		if field != expected {
+			t.Errorf("Unexpected field of %q. Got: %+v; Expected: %+v", s, field, expected)
+		}
+	}
+
+	f("name=value", fastclient.FormField{Name: "name", Value: "value"}, true)
+	f("q=a=b", fastclient.FormField{Name: "q", Value: "a=b"}, true)
+	f("empty=", fastclient.FormField{Name: "empty"}, true)
+	f("file=@/tmp/upload.bin", fastclient.FormField{Name: "file", File: "/tmp/upload.bin"}, true)
+	f("file=@", fastclient.FormField{}, false)
+	f("=value", fastclient.FormField{}, false)
+	f("value", fastclient.FormField{}, false)
+}
+
+func TestReadScenarioForm(t *testing.T) {
+	path := writeScenario(t, `[{"name": "upload", "method": "POST", "path": "/upload", "form": ["title=photo", "file=@photo.jpg"], "weight": 1}]`)
+	entries, err := readScenario(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(entries[0].Form) != 2 || entries[0].Form[1] != "file=@photo.jpg" {
+		t.Errorf("Unexpected form of entry: %v", entries[0].Form)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/valyala/fasthttp"
+)
+
+// generatedBody is a stream of n random letters, which depend only on seed.
+// Letters are generated while body is written, so body is never kept in memory
+type generatedBody struct {
+	rnd *rand.Rand
+	n   int64
+}
+
+func newGeneratedBody(seed, n int64) *generatedBody {
+	return &generatedBody{rnd: rand.New(rand.NewSource(seed)), n: n}
+}
+
+func (gb *generatedBody) Read(p []byte) (int, error) {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	if gb.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > gb.n {
+		p = p[:gb.n]
+	}
+	// sequence of rand.Rand.Read doesn't depend on sizes of p
+	gb.rnd.Read(p)
+	for i, b := range p {
+		p[i] = letters[int(b)%len(letters)]
+	}
+	gb.n -= int64(len(p))
+	return len(p), nil
+}
+
+// genBodyModifier returns fastclient.Client.NewModifier which sets body of every request
+// to stream of size generated by -seed, after modifier returned by newModifier, if it isn't nil
+func genBodyModifier(size int64, newModifier func() fastclient.Modifier) func() fastclient.Modifier {
+	return func() fastclient.Modifier {
+		var modify fastclient.Modifier
+		if newModifier != nil {
+			modify = newModifier()
+		}
+		return func(req *fasthttp.Request) error {
+			if modify != nil {
+				if err := modify(req); err != nil {
+					return err
+				}
+			}
+			req.SetBodyStream(newGeneratedBody(*seed, size), int(size))
+			return nil
+		}
+	}
+}
+
+func applyGenBody() {
+	var err error
+	if genBodySize, err = parseBytes(*genBodySizeFlag); err != nil {
+		usageAndExit(fmt.Sprintf("cannot parse -gen-body-size: %s", err))
+	}
+	if genBodySize == 0 {
+		usageAndExit("-gen-body-size must be positive")
+	}
+	if isFlagSet("b") || *bodyFile != "" || *curlFlag != "" || *dataFile != "" || *grpcMethod != "" || *bodySizeSweep != "" {
+		usageAndExit("-gen-body-size can't be used with -b, -body-file, -curl, -data, -grpc-method or -body-size-sweep, since body is generated")
+	}
+	if *retryPolicyFlag != "" || *probeFile != "" {
+		// stream of generated body can't be sent twice
+		usageAndExit("-gen-body-size can't be used with -retry-policy or -probe")
+	}
+	if !isFlagSet("m") {
+		*method = "POST"
+	} else if !methodAllowsBody(*method) {
+		usageAndExit(fmt.Sprintf("-gen-body-size can't be used with %s method, since it can't send body", strings.ToUpper(*method)))
+	}
+	if !isFlagSet("T") {
+		*contentType = "application/octet-stream"
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestGeneratedBody(t *testing.T) {
+	f := func(seed, n int64, chunk int) []byte {
+		t.Helper()
+		gb := newGeneratedBody(seed, n)
+		var result []byte
+		buf := make([]byte, chunk)
+		for {
+			m, err := gb.Read(buf)
+			result = append(result, buf[:m]...)
+			if err == io.EOF {
+				break
+			}
+		}
+		if int64(len(result)) != n {
+			t.Fatalf("Unexpected size of body. Got: %d; Expected: %d", len(result), n)
+		}
+		return result
+	}
+
+	b := f(1, 10000, 4096)
+	if !bytes.Equal(b, f(1, 10000, 7)) {
+		t.Errorf("body must not depend on sizes of reads")
+	}
+	if bytes.Equal(b, f(2, 10000, 4096)) {
+		t.Errorf("bodies generated with different seeds must differ")
+	}
+	if rest := bytes.Trim(b, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"); len(rest) > 0 {
+		t.Errorf("body must contain only letters and digits")
+	}
+
+	body, err := ioutil.ReadAll(newGeneratedBody(1, 0))
+	if err != nil || len(body) != 0 {
+		t.Errorf("Unexpected empty body: %q, %v", body, err)
+	}
+}
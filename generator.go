@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"plugin"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+var generatorFlag = flag.String("generator", "", "Load Go plugin .so, which exports NewGenerator function of type func() fastclient.RequestGenerator. "+
+	"It is called once by every client, and returned generator fills every request before it is sent")
+
+// newGenerator is NewGenerator of -generator plugin. Is nil if -generator isn't set
+var newGenerator func() fastclient.RequestGenerator
+
+// applyGenerator loads -generator plugin. Plugin must be built by the same Go version
+// and with the same version of fastclient package as fasthttploader
+func applyGenerator() {
+	p, err := plugin.Open(*generatorFlag)
+	if err != nil {
+		log.Fatalf("Cannot open -generator plugin: %s", err)
+	}
+	sym, err := p.Lookup("NewGenerator")
+	if err != nil {
+		log.Fatalf("Cannot find NewGenerator in -generator plugin: %s", err)
+	}
+	switch f := sym.(type) {
+	case func() fastclient.RequestGenerator:
+		newGenerator = f
+	case *func() fastclient.RequestGenerator:
+		newGenerator = *f
+	default:
+		log.Fatalf("NewGenerator of -generator plugin must be func() fastclient.RequestGenerator; got %T", sym)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// findGRPCMethod finds method like "package.Service/Method" in descriptor set file,
+// which is produced by `protoc --include_imports --descriptor_set_out`
+func findGRPCMethod(path, name string) (protoreflect.MethodDescriptor, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read descriptor set: %s", err)
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("cannot parse descriptor set %q: %s", path, err)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load descriptor set %q: %s", path, err)
+	}
+
+	n := strings.LastIndexAny(name, "/.")
+	if n < 1 {
+		return nil, fmt.Errorf("gRPC method %q must be in format \"package.Service/Method\"", name)
+	}
+	service, method := name[:n], name[n+1:]
+	d, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find gRPC service %q in %q: %s", service, path, err)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a gRPC service", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return nil, fmt.Errorf("gRPC service %q has no method %q", service, method)
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, fmt.Errorf("gRPC method %q is streaming; only unary methods are supported", name)
+	}
+	return md, nil
+}
+
+// grpcMessage encodes JSON as input message of md and frames it
+// with gRPC length prefix. Empty JSON means message with default values
+func grpcMessage(md protoreflect.MethodDescriptor, json string) ([]byte, error) {
+	msg := dynamicpb.NewMessage(md.Input())
+	if json != "" {
+		if err := protojson.Unmarshal([]byte(json), msg); err != nil {
+			return nil, fmt.Errorf("cannot parse body as %s: %s", md.Input().FullName(), err)
+		}
+	}
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s: %s", md.Input().FullName(), err)
+	}
+	// uncompressed flag and big-endian length of message
+	frame := make([]byte, 5+len(b))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(b)))
+	copy(frame[5:], b)
+	return frame, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// writeDescriptorSet writes descriptor set of service test.Echo
+// with unary method Hello and streaming method Stream
+func writeDescriptorSet(t *testing.T) string {
+	msg := proto.String(".test.Req")
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("echo.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Req"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("name"),
+				JsonName: proto.String("name"),
+				Number:   proto.Int32(1),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			}},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Echo"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Hello"), InputType: msg, OutputType: msg},
+				{Name: proto.String("Stream"), InputType: msg, OutputType: msg, ServerStreaming: proto.Bool(true)},
+			},
+		}},
+	}}}
+	b, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatalf("Cannot marshal descriptor set: %s", err)
+	}
+	f, err := ioutil.TempFile("", "descriptor")
+	if err != nil {
+		t.Fatalf("Cannot create file: %s", err)
+	}
+	f.Write(b)
+	f.Close()
+	return f.Name()
+}
+
+func TestGRPCMessage(t *testing.T) {
+	path := writeDescriptorSet(t)
+	defer os.Remove(path)
+
+	md, err := findGRPCMethod(path, "test.Echo/Hello")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	msg, err := grpcMessage(md, `{"name": "bob"}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	// field 1 of wire type 2 with length 3
+	expected := "\x00\x00\x00\x00\x05\x0a\x03bob"
+	if string(msg) != expected {
+		t.Errorf("Unexpected message. Got: %q; Expected: %q", msg, expected)
+	}
+
+	for _, name := range []string{"test.Echo/Stream", "test.Echo/Missing", "test.Missing/Hello", "test.Req/Hello", "Hello"} {
+		if _, err := findGRPCMethod(path, name); err == nil {
+			t.Errorf("Expected error for %q", name)
+		}
+	}
+	if _, err := grpcMessage(md, `{"missing": 1}`); err == nil {
+		t.Errorf("Expected error for unknown field")
+	}
+}
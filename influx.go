@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// writeInflux writes samples of report in InfluxDB line protocol to file
+// or pushes them to Influx write endpoint, if dst is http or https url
+func writeInflux(dst string) error {
+	var buf bytes.Buffer
+	if err := r.WriteInflux(&buf, testStart, map[string]string{"run": runName(), "target": r.Title}); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(dst, "http://") && !strings.HasPrefix(dst, "https://") {
+		return ioutil.WriteFile(dst, buf.Bytes(), 0644)
+	}
+
+	pReq := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(pReq)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	pReq.SetRequestURI(dst)
+	pReq.Header.SetMethod(fasthttp.MethodPost)
+	pReq.Header.SetContentType("text/plain; charset=utf-8")
+	pReq.SetBody(buf.Bytes())
+	if err := fasthttp.DoTimeout(pReq, resp, *t); err != nil {
+		return err
+	}
+	// influx responds with 204 No Content on success
+	if sc := resp.StatusCode(); sc < 200 || sc >= 300 {
+		return fmt.Errorf("unexpected status code %d: %s", sc, resp.Body())
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// interrupted is closed on the first SIGINT or SIGTERM
+var interrupted = make(chan struct{})
+
+// handleSignals stops test on SIGINT or SIGTERM, so current stage is finished
+// by stageTimeout and report is written with samples collected so far.
+// The second signal exits immediately
+func handleSignals() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ch
+		interrupt("\nInterrupted: finishing current stage to write report; interrupt again to exit immediately")
+		<-ch
+		if tui != nil {
+			tui.stop()
+		}
+		os.Exit(1)
+	}()
+}
+
+var interruptOnce = new(sync.Once)
+
+// interrupt stops test like signal does and prints reason
+func interrupt(reason string) {
+	interruptOnce.Do(func() {
+		fmt.Fprintln(os.Stderr, reason)
+		close(interrupted)
+	})
+}
+
+// interruptContext returns context, which is canceled once test is interrupted
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-interrupted:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// isInterrupted returns true if test was stopped by signal
+func isInterrupted() bool {
+	select {
+	case <-interrupted:
+		return true
+	default:
+		return false
+	}
+}
+
+// drainPollPeriod is a period of checking whether in-flight requests are completed
+const drainPollPeriod = 10 * time.Millisecond
+
+// drainOnInterrupt drops queued requests of interrupted test and waits up to -shutdown-grace
+// for in-flight ones, so summary of stage covers them instead of losing them
+func drainOnInterrupt() {
+	if !isInterrupted() || *shutdownGrace <= 0 {
+		return
+	}
+	client.DropQueued()
+	deadline := time.Now().Add(*shutdownGrace)
+	for client.InFlight() > 0 {
+		if time.Now().After(deadline) {
+			fmt.Fprintf(os.Stderr, "Shutdown grace period %s is exceeded; %d requests are still in flight\n", *shutdownGrace, client.InFlight())
+			return
+		}
+		time.Sleep(drainPollPeriod)
+	}
+}
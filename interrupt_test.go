@@ -0,0 +1,150 @@
+package main
+
+import (
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/valyala/fasthttp"
+)
+
+// resetInterrupt makes test not interrupted once it finishes, so the rest of tests run as usual
+func resetInterrupt(t *testing.T) {
+	t.Cleanup(func() {
+		// signal handler may still be finishing interrupt with the previous once
+		interrupted = make(chan struct{})
+		interruptOnce = new(sync.Once)
+	})
+}
+
+// waitInterrupted waits until test is interrupted. Test fails if it isn't interrupted in 5s
+func waitInterrupted(t *testing.T) {
+	t.Helper()
+	select {
+	case <-interrupted:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Test wasn't interrupted in time")
+	}
+}
+
+func TestHandleSignals(t *testing.T) {
+	resetInterrupt(t)
+	// handler waiting for the second signal mustn't exit process on signals of other tests
+	t.Cleanup(func() { signal.Reset(os.Interrupt, syscall.SIGTERM) })
+	handleSignals()
+	if isInterrupted() {
+		t.Fatalf("Test is interrupted before signal")
+	}
+	// the first signal finishes test instead of killing process
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatalf("Cannot send SIGINT: %s", err)
+	}
+	waitInterrupted(t)
+}
+
+func TestInterrupt(t *testing.T) {
+	resetInterrupt(t)
+	interrupt("first")
+	// closing of already closed channel would panic
+	interrupt("second")
+	if !isInterrupted() {
+		t.Fatalf("Test isn't interrupted")
+	}
+}
+
+func TestStageTimeoutInterrupted(t *testing.T) {
+	resetInterrupt(t)
+	defer func(prev *fastclient.Client) { client = prev }(client)
+	cr := new(fasthttp.Request)
+	cr.SetRequestURI("http://127.0.0.1:1/")
+	client = fastclient.New(cr, time.Second, fasthttp.StatusOK)
+	defer client.Flush()
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+	ch := stageTimeout(ctx, time.Hour)
+	select {
+	case <-ch:
+		t.Fatalf("Stage is finished before interrupt")
+	case <-time.After(50 * time.Millisecond):
+	}
+	interrupt("interrupted")
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Stage isn't finished on interrupt")
+	}
+
+	// interrupted test fails, so CI doesn't treat partial results as passed
+	var completion string
+	for _, c := range testChecks() {
+		if c.name == "completion" {
+			completion = c.failure
+		}
+	}
+	if completion != "test was interrupted" {
+		t.Errorf("Unexpected failure of completion check. Got: %q; Expected: %q", completion, "test was interrupted")
+	}
+}
+
+func TestDrainOnInterrupt(t *testing.T) {
+	resetInterrupt(t)
+	release := make(chan struct{})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	defer ln.Close()
+	go fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) { <-release })
+
+	defer func(prev *fastclient.Client, grace time.Duration) { client, *shutdownGrace = prev, grace }(client, *shutdownGrace)
+	cr := new(fasthttp.Request)
+	cr.SetRequestURI("http://" + ln.Addr().String() + "/")
+	client = fastclient.New(cr, 5*time.Second, fasthttp.StatusOK)
+	defer client.Flush()
+	client.RunWorkers(1)
+	for i := 0; i < 3; i++ {
+		client.Jobsch <- time.Now()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for client.InFlight() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Request wasn't sent in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// nothing is awaited unless test is interrupted
+	*shutdownGrace = 5 * time.Second
+	drainOnInterrupt()
+	if n := client.Overflow(); n != 2 {
+		t.Fatalf("Unexpected number of queued jobs of not interrupted test. Got: %d; Expected: 2", n)
+	}
+
+	interrupt("interrupted")
+	// grace period is exceeded by request, which is still in flight
+	*shutdownGrace = 50 * time.Millisecond
+	drainOnInterrupt()
+	if n := client.InFlight(); n != 1 {
+		t.Fatalf("Unexpected number of requests in flight after grace period. Got: %d; Expected: 1", n)
+	}
+	if n := client.Overflow(); n != 0 {
+		t.Errorf("Unexpected number of queued jobs of interrupted test. Got: %d; Expected: 0", n)
+	}
+
+	*shutdownGrace = 5 * time.Second
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+	drainOnInterrupt()
+	if n := client.InFlight(); n != 0 {
+		t.Errorf("Unexpected number of requests in flight after drain. Got: %d; Expected: 0", n)
+	}
+	// in-flight request is counted by summary of stage instead of being lost
+	if n := client.RequestSuccess(); n != 1 {
+		t.Errorf("Unexpected number of successful requests. Got: %d; Expected: 1", n)
+	}
+}
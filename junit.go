@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// junitTestSuite is a root element of JUnit XML report
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// encodeJUnit writes checks of test of target as JUnit XML test cases.
+// Every test case takes elapsed time of load phase, and details
+// are added to failures, so they are actionable without HTML report
+func encodeJUnit(w io.Writer, target string, checks []testCheck, start time.Time, elapsed time.Duration, details string) error {
+	seconds := strconv.FormatFloat(elapsed.Seconds(), 'f', 3, 64)
+	suite := junitTestSuite{
+		Name:      "fasthttploader " + target,
+		Tests:     len(checks),
+		Time:      seconds,
+		Timestamp: start.UTC().Format("2006-01-02T15:04:05"),
+	}
+	for _, c := range checks {
+		tc := junitTestCase{Name: c.name, ClassName: "fasthttploader." + target, Time: seconds}
+		if c.failure != "" {
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: c.failure, Text: c.failure + "\n" + details}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeJUnit writes checks of test result to JUnit XML file
+func writeJUnit(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	requests := client.RequestSum()
+	var success float64
+	if requests > 0 {
+		success = float64(client.RequestSuccess()) / float64(requests) * 100
+	}
+	var rps float64
+	if loadElapsed > 0 {
+		rps = float64(requests) / loadElapsed.Seconds()
+	}
+	details := fmt.Sprintf("Requests: %d; Success: %.2f %%; Rps: %.2f; Errors: %d; Timeouts: %d; p99 latency: %s",
+		requests, success, rps, client.Errors(), client.Timeouts(), formatLatency(client.RequestDuration()[0.99]))
+	if err = encodeJUnit(f, r.Title, testChecks(), testStart, loadElapsed, details); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestEncodeJUnit(t *testing.T) {
+	checks := []testCheck{
+		{name: "errors"},
+		{name: "SLA qps", failure: "SLA qps 5000 wasn't sustained: got 4100.00"},
+	}
+	var buf bytes.Buffer
+	start := time.Date(2026, 10, 14, 7, 0, 0, 0, time.UTC)
+	if err := encodeJUnit(&buf, "example.com", checks, start, 1500*time.Millisecond, "Requests: 6150"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="fasthttploader example.com" tests="2" failures="1" time="1.500" timestamp="2026-10-14T07:00:00">
+  <testcase name="errors" classname="fasthttploader.example.com" time="1.500"></testcase>
+  <testcase name="SLA qps" classname="fasthttploader.example.com" time="1.500">
+    <failure message="SLA qps 5000 wasn&#39;t sustained: got 4100.00">SLA qps 5000 wasn&#39;t sustained: got 4100.00&#xA;Requests: 6150</failure>
+  </testcase>
+</testsuite>
+`
+	if got := buf.String(); got != expected {
+		t.Errorf("Unexpected XML.\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+const (
+	// minClosedConns is a min number of closed connections
+	// required to detect keep-alive requests limit of server
+	minClosedConns = 10
+
+	// keepAliveLimitShare is a min share of closed connections,
+	// which must be closed after the same number of requests
+	keepAliveLimitShare = 0.8
+)
+
+// keepAliveLimit detects number of requests after which server closes connections.
+// dist maps number of requests to number of connections closed after them.
+// Returns false if there is no sharp cutoff at specific number of requests
+func keepAliveLimit(dist map[int]uint64) (int, bool) {
+	var total, limitConns uint64
+	limit := 0
+	for n, conns := range dist {
+		if n == 0 {
+			// connection was closed before any request
+			continue
+		}
+		total += conns
+		if conns > limitConns || (conns == limitConns && n > limit) {
+			limit, limitConns = n, conns
+		}
+	}
+	if total < minClosedConns || float64(limitConns) < float64(total)*keepAliveLimitShare {
+		return 0, false
+	}
+	// connections may be closed earlier because of errors, but never later
+	for n, conns := range dist {
+		if n > limit && conns > 0 {
+			return 0, false
+		}
+	}
+	return limit, true
+}
+
+// requestsPerConn returns average number of requests per connection.
+// dist maps number of requests to number of connections closed after them,
+// and open is a number of connections, which are still open
+func requestsPerConn(requests uint64, dist map[int]uint64, open uint64) float64 {
+	conns := open
+	for _, n := range dist {
+		conns += n
+	}
+	if conns == 0 {
+		return 0
+	}
+	return float64(requests) / float64(conns)
+}
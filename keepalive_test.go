@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestKeepAliveLimit(t *testing.T) {
+	f := func(dist map[int]uint64, limit int, ok bool) {
+		t.Helper()
+		n, detected := keepAliveLimit(dist)
+		if detected != ok || n != limit {
+			t.Errorf("Unexpected result for %v. Got: %d, %v; Expected: %d, %v", dist, n, detected, limit, ok)
+		}
+	}
+
+	f(nil, 0, false)
+	f(map[int]uint64{100: 5}, 0, false)
+	f(map[int]uint64{100: 50}, 100, true)
+	f(map[int]uint64{0: 20, 3: 2, 100: 50}, 100, true)
+	f(map[int]uint64{3: 20, 100: 50}, 0, false)
+	f(map[int]uint64{100: 50, 101: 1}, 0, false)
+	f(map[int]uint64{7: 10, 54: 10, 120: 10}, 0, false)
+}
+
+func TestRequestsPerConn(t *testing.T) {
+	f := func(requests uint64, dist map[int]uint64, open uint64, expected float64) {
+		t.Helper()
+		if got := requestsPerConn(requests, dist, open); got != expected {
+			t.Errorf("Unexpected result for %d requests, %v and %d open. Got: %f; Expected: %f", requests, dist, open, got, expected)
+		}
+	}
+
+	f(0, nil, 0, 0)
+	f(1000, nil, 10, 100)
+	f(1000, map[int]uint64{50: 10}, 10, 50)
+	f(30, map[int]uint64{1: 30}, 0, 1)
+}
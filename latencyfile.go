@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+var latencyFileFlag = flag.String("latency-file", "", "Stream start time, duration, status code and error class of every request of load phase to file "+
+	"for offline analysis. File is CSV if its name ends with .csv and compact binary otherwise")
+
+const (
+	// latencyFileMagic starts binary -latency-file
+	latencyFileMagic = "FHLLAT01"
+	// latencyBufferSize is a number of samples buffered between workers and writer of -latency-file
+	latencyBufferSize = 1 << 16
+	// latencyCSVHeader is a header of CSV -latency-file
+	latencyCSVHeader = "start_unix_ns,duration_ns,status,error_class\n"
+)
+
+// latencyClasses are error classes by their codes in binary -latency-file. Code 0 means success.
+// New classes are appended, so codes of files written by previous versions don't change
+var latencyClasses = []string{"", fastclient.ErrorClassTimeout, fastclient.ErrorClassDNS, fastclient.ErrorClassRefused,
+	fastclient.ErrorClassReset, fastclient.ErrorClassTLS, fastclient.ErrorClassDial, fastclient.ErrorClassStatus,
+	fastclient.ErrorClassResponse, fastclient.ErrorClassGenerator, fastclient.ErrorClassOther, fastclient.ErrorClassModifier,
+	fastclient.ErrorClassGRPC}
+
+// latencyWriter writes samples of requests to -latency-file. Workers only pass samples to buffered channel,
+// so writing doesn't delay requests. Samples are dropped if writer can't keep up with them
+type latencyWriter struct {
+	path string
+	csv  bool
+	f    *os.File
+	w    *bufio.Writer
+	buf  []byte
+
+	samples chan fastclient.Sample
+	// from is a time in unix nanoseconds, before which samples are skipped, e.g. ones of warmup
+	from    atomic.Int64
+	dropped atomic.Uint64
+	written uint64
+	// err is the first error of writing, after which samples aren't written anymore
+	err error
+
+	// done is closed once writer must stop, and stopped is closed once file is closed
+	done, stopped chan struct{}
+}
+
+// latencyFile is a writer of -latency-file. Is nil if -latency-file isn't set
+var latencyFile *latencyWriter
+
+// startLatencyFile starts writing samples to -latency-file. Samples of resumed test are appended to it
+func startLatencyFile(path string, resume bool) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resume {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		log.Fatalf("Cannot open -latency-file: %s", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		log.Fatalf("Cannot open -latency-file: %s", err)
+	}
+	lw := &latencyWriter{
+		path:    path,
+		csv:     strings.HasSuffix(strings.ToLower(path), ".csv"),
+		f:       f,
+		w:       bufio.NewWriterSize(f, 1<<20),
+		samples: make(chan fastclient.Sample, latencyBufferSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if fi.Size() == 0 {
+		lw.writeHeader()
+	}
+	latencyFile = lw
+	go lw.run()
+}
+
+// writeHeader writes header of CSV or binary file. Header of binary file is followed by
+// number of latencyClasses and by every class prefixed by its length
+func (lw *latencyWriter) writeHeader() {
+	if lw.csv {
+		lw.w.WriteString(latencyCSVHeader)
+		return
+	}
+	b := append([]byte(latencyFileMagic), byte(len(latencyClasses)))
+	for _, class := range latencyClasses {
+		b = append(b, byte(len(class)))
+		b = append(b, class...)
+	}
+	lw.w.Write(b)
+}
+
+// record passes s to writer without blocking. It is used as fastclient.Client.OnSample
+func (lw *latencyWriter) record(s fastclient.Sample) {
+	select {
+	case lw.samples <- s:
+	default:
+		lw.dropped.Add(1)
+	}
+}
+
+// skip skips all samples until skipBefore is called
+func (lw *latencyWriter) skip() {
+	lw.from.Store(math.MaxInt64)
+}
+
+// skipBefore skips samples of requests started before t
+func (lw *latencyWriter) skipBefore(t time.Time) {
+	lw.from.Store(t.UnixNano())
+}
+
+func (lw *latencyWriter) run() {
+	defer close(lw.stopped)
+	for {
+		select {
+		case s := <-lw.samples:
+			lw.write(s)
+		case <-lw.done:
+			// samples buffered before stop are written
+			for {
+				select {
+				case s := <-lw.samples:
+					lw.write(s)
+				default:
+					if err := lw.w.Flush(); err != nil && lw.err == nil {
+						lw.err = err
+					}
+					if err := lw.f.Close(); err != nil && lw.err == nil {
+						lw.err = err
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+func (lw *latencyWriter) write(s fastclient.Sample) {
+	if lw.err != nil || s.Start.UnixNano() < lw.from.Load() {
+		return
+	}
+	lw.buf = appendLatencySample(lw.buf[:0], s, lw.csv)
+	if _, err := lw.w.Write(lw.buf); err != nil {
+		lw.err = err
+		return
+	}
+	lw.written++
+}
+
+// appendLatencySample appends s to dst as CSV line or as binary record of 19 bytes in little-endian order:
+// start in unix nanoseconds and duration in nanoseconds as int64, status code as uint16 and code of error class as uint8
+func appendLatencySample(dst []byte, s fastclient.Sample, csv bool) []byte {
+	if csv {
+		dst = strconv.AppendInt(dst, s.Start.UnixNano(), 10)
+		dst = append(dst, ',')
+		dst = strconv.AppendInt(dst, int64(s.Duration), 10)
+		dst = append(dst, ',')
+		dst = strconv.AppendInt(dst, int64(s.StatusCode), 10)
+		dst = append(dst, ',')
+		dst = append(dst, s.ErrorClass...)
+		return append(dst, '\n')
+	}
+	dst = binary.LittleEndian.AppendUint64(dst, uint64(s.Start.UnixNano()))
+	dst = binary.LittleEndian.AppendUint64(dst, uint64(s.Duration))
+	dst = binary.LittleEndian.AppendUint16(dst, uint16(s.StatusCode))
+	return append(dst, latencyClassCode(s.ErrorClass))
+}
+
+// latencyClassCode returns code of class in latencyClasses. Unknown classes are coded as ErrorClassOther
+func latencyClassCode(class string) byte {
+	for i, c := range latencyClasses {
+		if c == class {
+			return byte(i)
+		}
+	}
+	return latencyClassCode(fastclient.ErrorClassOther)
+}
+
+// stop writes buffered samples and closes file
+func (lw *latencyWriter) stop() {
+	close(lw.done)
+	<-lw.stopped
+	if lw.err != nil {
+		slog.Error("Error while writing -latency-file", "path", lw.path, "error", lw.err)
+	}
+}
+
+func printLatencyFile() {
+	if latencyFile == nil {
+		return
+	}
+	fmt.Fprintf(out, "Latency samples: %d are written to %s", latencyFile.written, latencyFile.path)
+	if n := latencyFile.dropped.Load(); n > 0 {
+		fmt.Fprintf(out, "; Dropped: %d, since writing couldn't keep up with requests", n)
+	}
+	fmt.Fprintf(out, "\n\n")
+}
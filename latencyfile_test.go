@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+func TestAppendLatencySample(t *testing.T) {
+	s := fastclient.Sample{
+		Start:      time.Unix(1700000000, 123),
+		Duration:   1500 * time.Microsecond,
+		StatusCode: 503,
+		ErrorClass: fastclient.ErrorClassStatus,
+	}
+	if got := string(appendLatencySample(nil, s, true)); got != "1700000000000000123,1500000,503,http status\n" {
+		t.Errorf("Unexpected CSV line: %q", got)
+	}
+
+	b := appendLatencySample(nil, s, false)
+	if len(b) != 19 {
+		t.Fatalf("Unexpected size of binary record. Got: %d; Expected: %d", len(b), 19)
+	}
+	start, d := int64(binary.LittleEndian.Uint64(b)), time.Duration(binary.LittleEndian.Uint64(b[8:]))
+	status, class := binary.LittleEndian.Uint16(b[16:]), latencyClasses[b[18]]
+	if start != s.Start.UnixNano() || d != s.Duration || status != 503 || class != fastclient.ErrorClassStatus {
+		t.Errorf("Unexpected binary record: %d %s %d %q", start, d, status, class)
+	}
+}
+
+func TestLatencyClassCode(t *testing.T) {
+	f := func(class string, expected byte) {
+		t.Helper()
+		if got := latencyClassCode(class); got != expected {
+			t.Errorf("Unexpected code of class %q. Got: %d; Expected: %d", class, got, expected)
+		}
+	}
+
+	f("", 0)
+	f(fastclient.ErrorClassTimeout, 1)
+	f(fastclient.ErrorClassOther, 10)
+	f(fastclient.ErrorClassModifier, 11)
+	f(fastclient.ErrorClassGRPC, 12)
+	f("unknown", 10)
+}
+
+func TestLatencyWriter(t *testing.T) {
+	defer func() { latencyFile = nil }()
+	path := filepath.Join(t.TempDir(), "latencies.csv")
+	write := func(resume bool, starts ...time.Time) {
+		t.Helper()
+		startLatencyFile(path, resume)
+		latencyFile.skip()
+		latencyFile.record(fastclient.Sample{Start: starts[0], Duration: time.Millisecond, StatusCode: 200})
+		latencyFile.skipBefore(starts[0].Add(time.Second))
+		for _, s := range starts {
+			latencyFile.record(fastclient.Sample{Start: s, Duration: time.Millisecond, StatusCode: 200})
+		}
+		latencyFile.stop()
+	}
+
+	now := time.Now()
+	// the first sample is skipped as one of warmup
+	write(false, now, now.Add(2*time.Second), now.Add(3*time.Second))
+	if latencyFile.written != 2 || latencyFile.dropped.Load() != 0 {
+		t.Errorf("Unexpected number of written and dropped samples: %d and %d", latencyFile.written, latencyFile.dropped.Load())
+	}
+	// resumed test appends samples without header
+	write(true, now, now.Add(4*time.Second))
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cannot read file: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 4 || lines[0]+"\n" != latencyCSVHeader || !strings.HasSuffix(lines[3], ",1000000,200,") {
+		t.Errorf("Unexpected content of file: %q", lines)
+	}
+}
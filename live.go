@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// liveStatus prints status line of every sample with -live
+type liveStatus struct {
+	// tty is true if stdout is a terminal, so status line is redrawn in place.
+	// Otherwise every sample is printed by separate line
+	tty bool
+	// w is stdout, to which status lines are printed
+	w io.Writer
+
+	requests uint64
+	at       time.Time
+}
+
+var live *liveStatus
+
+func newLiveStatus() *liveStatus {
+	fi, err := os.Stdout.Stat()
+	return &liveStatus{
+		tty: err == nil && fi.Mode()&os.ModeCharDevice != 0,
+		w:   os.Stdout,
+		at:  time.Now(),
+	}
+}
+
+// print prints current qps, workers, queued jobs, requests, errors rate and p99 latency.
+// Rps is measured since previous sample, while requests and errors rate are cumulative within stage
+func (ls *liveStatus) print() {
+	now := time.Now()
+	requests, errs := client.RequestSum(), client.Errors()
+	if requests < ls.requests {
+		// counters were reset by new stage
+		ls.requests = 0
+	}
+	var rps, errorRate float64
+	if d := now.Sub(ls.at).Seconds(); d > 0 {
+		rps = float64(requests-ls.requests) / d
+	}
+	if requests > 0 {
+		errorRate = float64(errs) / float64(requests) * 100
+	}
+	ls.requests, ls.at = requests, now
+
+	line := fmt.Sprintf("rps: %.2f; qps limit: %.2f; workers: %d; queued: %d; requests: %d; errors: %.2f %%; p99: %s",
+		rps, qpsLimit(), client.Amount(), client.Overflow(), requests, errorRate, formatLatency(client.RequestDuration()[0.99]))
+	if ls.tty {
+		// line is erased till the end, since previous one could be longer
+		fmt.Fprintf(ls.w, "\r%s\x1b[K", line)
+		return
+	}
+	fmt.Fprintln(ls.w, line)
+}
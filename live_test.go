@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/ratelimiter"
+	"github.com/hagen1778/fasthttploader/report"
+	"github.com/valyala/fasthttp"
+)
+
+func TestLiveStatusPrint(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	defer ln.Close()
+	var fail atomic.Bool
+	go fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {
+		if fail.Load() {
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		}
+	})
+
+	defer func(prev *fastclient.Client, page *report.Page) { client, r = prev, page }(client, r)
+	r = &report.Page{LatencyUnit: "ms"}
+	defer func() { throttle = ratelimiter.NewLimiter() }()
+	throttle = ratelimiter.NewLimiter()
+	defer throttle.Stop()
+	throttle.SetLimit(100)
+	cr := new(fasthttp.Request)
+	cr.SetRequestURI("http://" + ln.Addr().String() + "/")
+	client = fastclient.New(cr, time.Second, fasthttp.StatusOK)
+	client.ExpectedStatusCodes = map[int]bool{fasthttp.StatusOK: true}
+	// client is replaced by the next stage below
+	defer func() { client.Flush() }()
+	client.RunWorkers(2)
+	send := func(n int) {
+		t.Helper()
+		sent := client.RequestSum() + uint64(n)
+		for i := 0; i < n; i++ {
+			client.Jobsch <- time.Now()
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for client.RequestSum() < sent {
+			if time.Now().After(deadline) {
+				t.Fatalf("Requests weren't done in time. Got: %d; Expected: %d", client.RequestSum(), sent)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	f := func(ls *liveStatus, expected ...string) {
+		t.Helper()
+		var buf bytes.Buffer
+		ls.w = &buf
+		ls.print()
+		for _, s := range expected {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("Status line %q doesn't contain %q", buf.String(), s)
+			}
+		}
+	}
+
+	send(3)
+	fail.Store(true)
+	send(1)
+	ls := &liveStatus{at: time.Now().Add(-100 * time.Second)}
+	// every sample is printed by separate line unless stdout is a terminal
+	f(ls, "rps: 0.04; ", "qps limit: 100.00; workers: 2; queued: 0; requests: 4; errors: 25.00 %; p99: ", "ms\n")
+	// rps is measured since previous sample
+	ls.at = time.Now().Add(-100 * time.Second)
+	f(ls, "rps: 0.00; ", "requests: 4;")
+
+	// counters are reset by new stage
+	client.Flush()
+	client = fastclient.New(cr, time.Second, fasthttp.StatusOK)
+	client.ExpectedStatusCodes = map[int]bool{fasthttp.StatusOK: true}
+	client.RunWorkers(1)
+	send(1)
+	ls.at = time.Now().Add(-100 * time.Second)
+	ls.tty = true
+	// status line of terminal is redrawn in place
+	f(ls, "\rrps: 0.01; ", "requests: 1; errors: 100.00 %", "\x1b[K")
+}
@@ -1,27 +1,40 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
+	"log/slog"
+	"math"
 	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/cheggaaa/pb"
 	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/pushgateway"
 	"github.com/hagen1778/fasthttploader/ratelimiter"
 	"github.com/hagen1778/fasthttploader/report"
 )
 
 const (
-	// Duration of burst-testing, without qps-limit. Used to estimate start test conditions
-	calibrateDuration = 10 * time.Second
+	// significantQueueWait is a share of p99 latency, exceeding which
+	// by p99 of wait in job queue is warned about
+	significantQueueWait = 0.1
 
-	// Duration of adjustable testing, while trying to reach max qps with minimal lvl of errors
-	adjustmentDuration = 30 * time.Second
+	// Period of checking whether -max-bytes is reached
+	bytesCheckPeriod = 50 * time.Millisecond
 
-	// Period of sample taking, while testing
-	samplePeriod = 500 * time.Millisecond
+	// Period of checking whether -n requests are completed
+	requestsCheckPeriod = 10 * time.Millisecond
+
+	// maxStageDuration is a duration of load phase with -n, which is limited only by number of requests
+	maxStageDuration = time.Duration(math.MaxInt64)
 )
 
 var (
@@ -34,12 +47,54 @@ var (
 	// errors storage of errors amount in current step. Used to compare changes in errors-metric
 	errors uint64
 
-	// multiplier is a coefficient of qps multiplying during tests
-	multiplier = float64(0.1)
-
 	throttle = ratelimiter.NewLimiter()
+
+	// out is used to print results of test stages.
+	// Is replaced by outBuf if results must be printed only on failure
+	out    io.Writer = os.Stdout
+	outBuf bytes.Buffer
+
+	// quiet is true if results must be printed only on failure
+	quiet bool
+
+	// loadElapsed is a duration of load phase
+	loadElapsed time.Duration
+
+	// qpsClipped is true if rate limit was clipped by -max-allowed-qps
+	qpsClipped bool
+
+	// steps contains results of every ramp step of load phase
+	steps []rampStep
+
+	// bursting is true while load is sent by burst pattern
+	bursting bool
+
+	// bursts contains results of every burst of load phase
+	bursts []rampStep
+
+	// steadyFrom is an index of the first sample of load phase with constant offered load.
+	// Is negative if offered load of load phase isn't constant
+	steadyFrom = -1
 )
 
+// significantDegradation is a decline of throughput over steady phase in percents,
+// exceeding which is reported as degradation
+const significantDegradation = 10
+
+// rampStep contains metrics measured during dwell period of ramp step
+type rampStep struct {
+	// qps is the rate limit of step
+	qps float64
+	// workers is a number of clients of step
+	workers int
+
+	rps       float64
+	errorRate float64
+	p99       float64
+
+	requests uint64
+}
+
 type loadConfig struct {
 	// qps is the rate limit.
 	qps float64
@@ -48,168 +103,961 @@ type loadConfig struct {
 	c int
 }
 
-func run() {
+// run runs all test stages and returns true if report was written
+func run() bool {
+	handleSignals()
+	// ctx is canceled on interrupt, so current stage is finished
+	ctx, cancel := interruptContext()
+	defer cancel()
 	r = &report.Page{
-		Title:           string(req.URI().Host()),
-		RequestDuration: make(map[float64][]float64),
-		Interval:        samplePeriod.Seconds(),
+		Title:             string(req.URI().Host()),
+		RequestDuration:   make(map[float64][]float64),
+		Interval:          samplePeriod.Seconds(),
+		MinSamples:        *minSamples,
+		IncidentThreshold: *incidentThreshold,
+		IncludeRawSamples: *includeRawSamples,
+
+		LatencyPerspective: *latencyPerspective,
+		InjectedFaults:     injectedFaults(),
+
+		Metadata: &report.Metadata{
+			Version: buildVersion(),
+			Seed:    *seed,
+			Config:  effectiveConfig(),
+		},
+	}
+	// seed is printed, so test could be reproduced by -seed even without report
+	fmt.Fprintf(out, "fasthttploader %s; seed %d\n", r.Metadata.Version, *seed)
+	if *separateFirstRequests {
+		r.FirstRequestDuration = make(map[float64][]float64)
+	}
+	if *correctedLatency {
+		r.CorrectedDuration = make(map[float64][]float64)
+	}
+	if *latencyBreakdown {
+		r.BreakdownSeries = make(map[string][]float64)
+	}
+	if *connSetupLatency {
+		r.ConnectDuration = make(map[float64][]float64)
+		if string(req.URI().Scheme()) == "https" {
+			r.HandshakeDuration = make(map[float64][]float64)
+		}
 	}
 
 	cfg := loadConfig{}
-	if *q == 0 {
-		fmt.Println("Run burst-load phase")
-		burstThroughput(&cfg)
+	if *checkpointFile != "" {
+		resume(&cfg)
+	}
+	// timeline of resumed test continues series of checkpoint
+	testStart = time.Now().Add(-time.Duration(len(r.Connections)) * *samplePeriod)
+	if len(exportFlags) > 0 {
+		startExport()
+	}
+	if *latencyFileFlag != "" {
+		startLatencyFile(*latencyFileFlag, resumed != nil)
+	}
+	if *tuiFlag && live == nil {
+		// dashboard is shown after checkpoint is read, so its errors aren't captured
+		tui = newDashboard()
+	}
+	if resumed != nil {
+		fmt.Fprintf(out, "Resume load phase from checkpoint %q: %s of %s are done\n", *checkpointFile, resumed.Elapsed, *d)
+	} else if *mode == "coordinator" {
+		coordinate(ctx, loadConfig{qps: float64(*q), c: *c})
+		makeReport()
+		return true
+	} else if *mode == "worker" {
+		joinCoordinator(&cfg)
+	} else if *loadModel == loadModelConcurrency {
+		cfg.c = *c
+	} else if profile != nil {
+		cfg.qps = profile.peak()
+		cfg.c = *c
+	} else if replayOffsets != nil {
+		cfg.qps = replayRate()
+		cfg.c = *c
+	} else if loadedCalibration != nil {
+		cfg.qps = loadedCalibration.QPS
+		cfg.c = loadedCalibration.Clients
+		fmt.Fprintf(out, "Skip burst-load and calibrate phases: reuse calibration of %s from %q: qps %.2f; clients %d\n",
+			loadedCalibration.Time.Format(time.RFC3339), *loadCalibration, cfg.qps, cfg.c)
+	} else if *q == 0 {
+		fmt.Fprintln(out, "Run burst-load phase")
+		burstThroughput(ctx, &cfg)
 
-		fmt.Println("Run calibrate phase")
-		calibrateThroughput(&cfg)
+		if !isBytesCapReached() && !isInterrupted() {
+			fmt.Fprintln(out, "Run calibrate phase")
+			calibrateThroughput(ctx, &cfg)
+			// calibration of interrupted test is incomplete, so it isn't saved
+			if *saveCalibration != "" && !isInterrupted() {
+				if err := writeCalibration(*saveCalibration, &cfg); err != nil {
+					slog.Error("Error while saving calibration", "error", err)
+				} else {
+					fmt.Fprintf(out, "Calibration is saved to %q: qps %.2f; clients %d\n", *saveCalibration, cfg.qps, cfg.c)
+				}
+			}
+		}
 	} else {
 		cfg.qps = float64(*q)
 		cfg.c = *c
 	}
 
-	fmt.Println("Run load phase")
-	makeLoad(&cfg)
+	if isBytesCapReached() {
+		fmt.Fprintln(out, "Load phase is skipped, since -max-bytes is reached")
+	} else if isInterrupted() {
+		fmt.Fprintln(out, "Load phase is skipped, since test was interrupted")
+	} else if len(sweepLevels) > 0 {
+		runSweep(ctx, &cfg)
+	} else {
+		fmt.Fprintln(out, "Run load phase")
+		makeLoad(ctx, &cfg)
+	}
+	if tui != nil {
+		tui.stop()
+	}
+	if exporters != nil {
+		exporters.Wait()
+	}
+	if latencyFile != nil {
+		latencyFile.stop()
+	}
+	if *pushgatewayDelete {
+		pushgateway.Delete()
+	}
+	if coordinator != nil {
+		coordinator.send(true)
+	}
+	// checkpoint of interrupted test is kept, so it could be resumed
+	if *checkpointFile != "" && !isInterrupted() {
+		removeCheckpoint()
+	}
+	r.LatencyUnit = *latencyUnit
+	if r.LatencyUnit == "auto" {
+		r.LatencyUnit = report.AutoLatencyUnit(client.RequestDuration()[0.5])
+	}
+	if client.RequestSum() > 0 {
+		l := client.Latency()
+		r.Latency = &report.Latency{P50: l.P50, P90: l.P90, P95: l.P95, P99: l.P99, Max: l.Max}
+	}
+	if client.Connects() > 0 && *connSetupLatency {
+		l := client.ConnectLatency()
+		r.ConnectLatency = &report.Latency{P50: l.P50, P90: l.P90, P95: l.P95, P99: l.P99, Max: l.Max}
+	}
+	if client.Handshakes() > 0 && *connSetupLatency {
+		l := client.HandshakeLatency()
+		r.HandshakeLatency = &report.Latency{P50: l.P50, P90: l.P90, P95: l.P95, P99: l.P99, Max: l.Max}
+	}
+	if *latencyBreakdown {
+		r.LatencyBreakdown = stageLatencies()
+	}
+	if client.FirstRequests() > 0 && *separateFirstRequests {
+		l := client.FirstRequestLatency()
+		r.FirstRequestLatency = &report.Latency{P50: l.P50, P90: l.P90, P95: l.P95, P99: l.P99, Max: l.Max}
+	}
+	if client.RequestSum() > 0 && *correctedLatency {
+		l := client.CorrectedLatency()
+		r.CorrectedLatency = &report.Latency{P50: l.P50, P90: l.P90, P95: l.P95, P99: l.P99, Max: l.Max}
+	}
+	r.LatencyBySize = latencyBySize()
+	if len(regions) > 0 {
+		r.LatencyByRegion = latencyByRegion()
+	}
+	r.Phases = phases()
+	r.FailedRequests = failedRequests()
+	if len(targets) > 0 {
+		r.Targets = targetStats()
+	}
+	printSteps()
+	printThroughputTrend()
+	printSoakTrends()
+	printLatencyBySize()
+	printLatencyByRegion()
+	printSweep()
+	printBursts()
+	printKeepAliveLimit()
+	printConnReuse()
+	printDNSDistribution()
+	printHeadroom()
+	printIncidents()
+	printSLA()
+	printBytesCap()
+	printAbort()
+	printPhaseComparison()
+	printCapturedErrors()
+	printLatencyFile()
+	applyAnnotations(annotations)
+	if *junitOut != "" {
+		if err := writeJUnit(*junitOut); err != nil {
+			slog.Error("Error while writing JUnit report", "error", err)
+		}
+	}
+
+	if *summaryOnFailure {
+		failures := checkFailures()
+		if quiet && !flushOnFailure(os.Stdout, failures) {
+			return false
+		}
+		printFailures(failures)
+	}
+
+	if *curveOut != "" {
+		if err := writeCurve(*curveOut); err != nil {
+			slog.Error("Error while writing latency-throughput curve", "error", err)
+		}
+	}
+	if *influxOut != "" {
+		if err := writeInflux(*influxOut); err != nil {
+			slog.Error("Error while writing samples to influx", "error", err)
+		}
+	}
+
+	makeReport()
+	return true
+}
+
+// makeReport writes report to -r file in every format set by -format
+// and samples to -csv file if it is set
+func makeReport() {
+	for _, format := range reportFormats {
+		b, err := report.Marshal(r, format)
+		if err != nil {
+			log.Fatalf("Error while making %s report: %s", format, err)
+		}
+		if err := ioutil.WriteFile(reportFile(format), b, 0644); err != nil {
+			log.Fatalf("Error while trying to create file: %s", err)
+		}
+	}
 
-	f, err := os.Create(*fileName)
-	if err != nil {
-		log.Fatalf("Error while trying to create file: %s", err)
+	if *csvOut != "" {
+		if err := ioutil.WriteFile(*csvOut, []byte(report.PrintCSV(r)), 0644); err != nil {
+			slog.Error("Error while writing samples to CSV", "error", err)
+		}
 	}
-	f.WriteString(report.PrintPage(r))
-	f.Close()
 }
 
-func burstThroughput(cfg *loadConfig) {
-	client = fastclient.New(req, *t, *successStatusCode)
+// resume restores report and config of load phase from checkpoint file if it exists
+func resume(cfg *loadConfig) {
+	var err error
+	if resumed, err = readCheckpoint(*checkpointFile); err != nil {
+		log.Fatalf("Error while reading checkpoint: %s", err)
+	}
+	if resumed == nil {
+		return
+	}
+	if resumed.Title != r.Title {
+		log.Fatalf("Checkpoint %q belongs to test of %s; remove it to start new test", *checkpointFile, resumed.Title)
+	}
+	if resumed.Elapsed >= *d {
+		log.Fatalf("Checkpoint %q belongs to finished test; remove it to start new test", *checkpointFile)
+	}
+	resumed.restore(r)
+	// checkpoints made by previous versions have no timestamps
+	for i := len(r.Timestamps); i < len(r.Connections); i++ {
+		r.Timestamps = append(r.Timestamps, float64(i+1)*r.Interval)
+	}
+	// phases of samples of checkpoints made by previous versions are unknown
+	for len(r.SamplePhases) < len(r.Connections) {
+		r.SamplePhases = append(r.SamplePhases, "")
+	}
+	bytesSpent = resumed.BytesTransferred
+	cfg.qps = resumed.QPS
+	cfg.c = resumed.Clients
+}
+
+// testCheck is a criterion, which test result must meet
+type testCheck struct {
+	name string
+
+	// failure describes violation of criterion. Is empty if criterion is met
+	failure string
+}
+
+// testChecks evaluates every criterion of test result, which is enabled by flags
+func testChecks() []testCheck {
+	var checks []testCheck
+	errs := testCheck{name: "errors"}
+	if n := client.Errors(); n > 0 {
+		errs.failure = fmt.Sprintf("load phase finished with %d errors", n)
+	}
+	checks = append(checks, errs)
+	if isInterrupted() {
+		checks = append(checks, testCheck{name: "completion", failure: "test was interrupted"})
+	} else if aborted != nil {
+		checks = append(checks, testCheck{name: "completion", failure: "load phase was aborted: " + aborted.Reason})
+	}
+	if *echoHeader != "" {
+		echo := testCheck{name: "echo of " + *echoHeader}
+		if failed, _ := echoFailures(client.EchoResults()); failed > 0 {
+			echo.failure = fmt.Sprintf("%s wasn't echoed correctly in %d responses", *echoHeader, failed)
+		}
+		checks = append(checks, echo)
+	}
+	if responseAssertions != nil {
+		assert := testCheck{name: "assertions"}
+		if n := assertionFailures(client.AssertionFailures()); n > 0 {
+			assert.failure = fmt.Sprintf("responses failed assertions %d times", n)
+		}
+		checks = append(checks, assert)
+	}
+	if *minRequestsPerConn > 0 {
+		reuse := testCheck{name: "connection reuse"}
+		if n := connReuse(); n < *minRequestsPerConn {
+			reuse.failure = fmt.Sprintf("average requests per connection %.2f is below %.2f", n, *minRequestsPerConn)
+		}
+		checks = append(checks, reuse)
+	}
+	if contract != nil {
+		res := contract.check(client.RequestSum(), client.RequestSuccess(), loadElapsed)
+		checks = append(checks,
+			testCheck{name: "SLA qps", failure: res.qpsFailure(contract)},
+			testCheck{name: "SLA availability", failure: res.availabilityFailure(contract)},
+		)
+	}
+	if *soak {
+		trends := testCheck{name: "soak trends"}
+		if len(soakLeaks) > 0 {
+			trends.failure = "leak suspected: " + strings.Join(soakLeaks, "; ")
+		}
+		checks = append(checks, trends)
+	}
+	checks = append(checks, gate.checks(client.RequestSum(), client.Errors(), client.RequestDuration()[0.99], loadElapsed)...)
+
+	return checks
+}
+
+// checkFailures returns list of reasons why test is considered as failed
+func checkFailures() []string {
+	var failures []string
+	for _, c := range testChecks() {
+		if c.failure != "" {
+			failures = append(failures, c.failure)
+		}
+	}
+
+	return failures
+}
+
+// flushOnFailure writes results buffered in quiet mode to w if test failed,
+// so the rest of results is printed to w too. Returns false if test passed
+func flushOnFailure(w io.Writer, failures []string) bool {
+	if len(failures) == 0 {
+		fmt.Fprintln(w, "Test passed")
+		return false
+	}
+	w.Write(outBuf.Bytes())
+	out = w
+	return true
+}
+
+func printFailures(failures []string) {
+	if len(failures) == 0 {
+		fmt.Fprintln(out, "Test passed")
+		return
+	}
+
+	fmt.Fprintln(out, "Test failed:")
+	for _, f := range failures {
+		fmt.Fprintf(out, " - %s\n", f)
+	}
+	fmt.Fprintln(out)
+}
+
+func newClient() *fastclient.Client {
+	if client != nil {
+		// counters of new client start from zero
+		bytesSpent += client.BytesRead() + client.BytesWritten()
+	}
+	prevStages = nil
+	timeout := *requestTimeout
+	if tmpl != nil && tmpl.data != nil && tmpl.data.maxTimeout > timeout {
+		// connection timeouts must not interrupt requests with bigger per-request timeouts
+		timeout = tmpl.data.maxTimeout
+	}
+	var c *fastclient.Client
+	if *grpcMethod != "" {
+		c = fastclient.NewGRPC(req, timeout, *successStatusCode)
+	} else if *http2 {
+		c = fastclient.NewHTTP2(req, timeout, *successStatusCode)
+	} else {
+		c = fastclient.New(req, timeout, *successStatusCode)
+	}
+	c.StartJitter = *startJitter
+	c.Seed = derivedSeed("client", 0)
+	c.ThinkTime = thinkTime
+	c.WebSocket = *wsFlag
+	c.WSRate = *wsRate
+	c.ThinkTimeJitter = thinkTimeJitter
+	c.QueueSize = *queueSize
+	c.ArrivalRate = *loadModel == loadModelArrivalRate
+	c.ClosedLoop = *loadModel == loadModelConcurrency
+	c.WarmupRequests = *warmupRequests
+	c.IncludeQueueWait = *latencyPerspective == "client"
+	c.RecordCorrected = *correctedLatency
+	c.BackendHeader = *backendHeader
+	c.Cookies = *cookies
+	c.DialTimeout = *dialTimeout
+	c.UnixSocket = *unixSocket
+	c.MaxConnsPerHost = *maxConnsFlag
+	c.MaxIdleConnDuration = *maxIdleConnDuration
+	c.MaxConnDuration = *maxConnDuration
+	c.MaxConnRequests = *maxConnRequests
+	c.LatencyBreakdown = *latencyBreakdown
+	setProxy(c)
+	c.TLSConfig = tlsConfig
+	c.ExpectedStatusCodes = expectedStatusCodes
+	c.DecodeResponses = *acceptEncoding != "" && *grpcMethod == ""
+	c.MaxDecodedSize = int64(maxDecodedSize)
+	c.CompressBody = *compressBody
+	c.DialAddrs = dialAddrs
+	c.Resolver = resolver
+	c.ResolveInterval = *dnsResolveInterval
+	c.StaticHosts = resolveFlags
+	c.LocalAddrs = localAddrs
+	c.InjectLatency = *injectLatency
+	c.Regions = regions
+	c.InjectDrop = *injectDrop
+	c.UploadRate = uploadRate
+	c.Targets = targets
+	c.TargetNames = targetNames
+	c.TargetWeights = targetWeights
+	c.TargetMaxQPS = targetMaxQPS
+	c.TargetForms = targetForms
+	c.Form = form
+	c.Steps = *scenarioSteps
+	c.StepExtractions = stepExtractions
+	c.SeparateFirstRequests = *separateFirstRequests
+	c.RetryPolicy = retryPolicy
+	c.MaxRetries = *retryMax
+	c.RetryTokens = throttle.QPS()
+	c.Assertions = responseAssertions
+	c.CaptureErrors = *captureErrors - len(capturedErrors)
+	if tmpl != nil {
+		c.NewModifier = tmpl.Modifier
+	}
+	if affinity != nil {
+		c.NewModifier = affinity.modifier(c.NewModifier)
+	}
+	if rotation != nil {
+		c.NewModifier = rotation.modifier(c.NewModifier)
+	}
+	if genBodySize > 0 {
+		c.NewModifier = genBodyModifier(int64(genBodySize), c.NewModifier)
+	}
+	c.NewGenerator = newGenerator
+	if *echoHeader != "" {
+		c.EchoHeader = *echoHeader
+		if len(req.Header.Peek(*echoHeader)) == 0 {
+			c.NewModifier = requestIDModifier(*echoHeader, c.NewModifier)
+		}
+	}
+	return c
+}
+
+func burstThroughput(parent context.Context, cfg *loadConfig) {
+	client = newClient()
+	samplePhase = report.PhaseBurst
+	slog.Info("Phase started", "phase", samplePhase)
+	if *maxAllowedQPS == 0 {
+		// burst isn't limited by rate
+		client.RetryTokens = nil
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
 	startTime := time.Now()
-	timeout := time.After(calibrateDuration)
-	bar, progressTicker := acquireProgressBar(calibrateDuration)
+	timeout := stageTimeout(ctx, *burstDuration)
+	bar, progressTicker := acquireProgressBar(*burstDuration)
 
+	if *maxAllowedQPS > 0 {
+		throttle.SetLimit(*maxAllowedQPS)
+	}
 	client.RunWorkers(*c)
+	var probe <-chan time.Time
+	if autoClients {
+		probe = time.After(clientsProbe)
+	}
 	for {
 		select {
+		case <-probe:
+			probe = nil
+			adjustAutoClients()
 		case <-timeout:
 			finishProgressBar(bar)
-			cfg.qps = float64(client.RequestSum()) / calibrateDuration.Seconds()
-			cfg.c = client.Amount()
-			if (client.Errors()/client.RequestSum())*100 > 2 { // just more than 2% of errors
-				cfg.qps /= 2
-				cfg.c /= 2
-			}
+			drainOnInterrupt()
+			qps := float64(client.RequestSum()) / time.Since(startTime).Seconds()
+			cfg.qps, cfg.c = burstStart(qps, client.Amount(), client.RequestSum(), client.Errors())
+			addStagePoint("burst", startTime)
+			// samples aren't taken during burst, so its window is marked instead
+			r.Lock()
+			r.Annotations = append(r.Annotations, report.Annotation{
+				Time:     startTime.Sub(testStart).Seconds(),
+				Duration: time.Since(startTime).Seconds(),
+				Label:    "burst (not sampled)",
+			})
+			r.Unlock()
 			printSummary("Burst Throughput", startTime)
 			return
 		case <-progressTicker:
 			bar.Increment()
 		default:
-			client.Jobsch <- struct{}{}
+			if *maxAllowedQPS > 0 {
+				// burst isn't limited by anything except of the cap
+				select {
+				case <-throttle.QPS():
+				case <-ctx.Done():
+					continue
+				}
+			}
+			client.Enqueue(ctx, time.Now())
 		}
 	}
 }
 
-func calibrateThroughput(cfg *loadConfig) {
-	client = fastclient.New(req, *t, *successStatusCode)
+// burstStart returns qps and number of clients to start calibration with, given ones achieved by burst.
+// They are halved if errors rate of burst exceeds -max-error-rate, since target couldn't keep up
+func burstStart(qps float64, c int, requests, errs uint64) (float64, int) {
+	if requests > 0 && float64(errs)/float64(requests)*100 > *maxErrorRate {
+		if c > 1 {
+			c /= 2
+		}
+		return qps / 2, c
+	}
+	return qps, c
+}
+
+func calibrateThroughput(parent context.Context, cfg *loadConfig) {
+	client = newClient()
+	samplePhase = report.PhaseCalibrate
+	slog.Info("Phase started", "phase", samplePhase)
 	t := time.Now()
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(parent)
+	// done is closed once summary of stage is printed
+	done := make(chan struct{})
 
-	throttle.SetLimit(cfg.qps)
+	setLimit(cfg.qps)
 	client.RunWorkers(cfg.c)
 	go func() {
-		timeout := time.After(adjustmentDuration)
-		sampler := time.Tick(samplePeriod)
-		bar, progressTicker := acquireProgressBar(adjustmentDuration)
+		defer close(done)
+		timeout := stageTimeout(ctx, *adjustDuration)
+		s := newSampler()
+		sampleTick := s.next()
+		// calibration pace doesn't depend on adaptive sampling
+		calibrateTick := time.Tick(*samplePeriod)
+		bar, progressTicker := acquireProgressBar(*adjustDuration)
 		for {
 			select {
 			case <-timeout:
 				finishProgressBar(bar)
+				drainOnInterrupt()
 				cfg.qps = throttle.Limit()
 				cfg.c = client.Amount()
+				addStagePoint("adjustment", t)
 				printSummary("Adjustment test", t)
 				cancel()
 				return
 			case <-progressTicker:
 				bar.Increment()
-			case <-sampler:
-				printState()
+			case <-sampleTick:
+				s.sample()
+				sampleTick = s.next()
+			case <-calibrateTick:
 				calibrate()
 			}
 		}
 	}()
 
-	load(ctx)
+	load(ctx, 0)
+	<-done
 }
 
-var await = 0
+// setLimit sets rate limit of throttle.
+// Limit is clipped by -max-allowed-qps if it is set
+func setLimit(qps float64) {
+	if *maxAllowedQPS > 0 && qps > *maxAllowedQPS {
+		if !qpsClipped {
+			slog.Warn("qps exceeds -max-allowed-qps and is clipped", "qps", qps, "max_allowed_qps", *maxAllowedQPS)
+			qpsClipped = true
+		}
+		qps = *maxAllowedQPS
+	}
+	throttle.SetLimit(qps)
+}
+
+// qpsLimit returns current rate limit
+func qpsLimit() float64 {
+	if bursting {
+		return pattern.qps()
+	}
+	return throttle.Limit()
+}
 
 func calibrate() {
-	if await > 0 {
-		await -= 1
+	if *calibThrottling && calib.await == 0 && isThrottled() {
+		calib.throttle(retryAfterPeriods(client.RetryAfter()))
+		setLimit(throttle.Limit() / calib.backoff)
+		slog.Info("Calibration backed off, since target throttles requests", "qps", throttle.Limit())
 		return
 	}
-
-	if !isFlawed() {
-		if client.Overflow() > 0 {
-			n := int(float64(client.Amount()) * multiplier)
-			client.RunWorkers(n)
-			await += 1
-		} else {
-			throttle.SetLimit(throttle.Limit() * (1 + multiplier))
-			await += 1
+	k := calib.next(isFlawed)
+	if k == 0 {
+		return
+	}
+	if client.Backpressure().Saturated() {
+		if *maxConnsFlag > 0 && len(targets) == 0 && client.Amount() >= *maxConnsFlag {
+			// extra workers would only wait for free connections
+			return
 		}
+		n := int(float64(client.Amount()) * k)
+		client.RunWorkers(n)
+		slog.Info("Calibration added clients, since job queue is saturated", "added", n, "clients", client.Amount())
 	} else {
-		multiplier /= 1.2
-		await += 3
+		setLimit(throttle.Limit() * (1 + k))
+		slog.Info("Calibration changed qps", "multiplier", k, "qps", throttle.Limit())
 	}
 }
 
-func makeLoad(cfg *loadConfig) {
-	client = fastclient.New(req, *t, *successStatusCode)
+func makeLoad(parent context.Context, cfg *loadConfig) {
+	client = newClient()
+	if latencyFile != nil {
+		client.OnSample = latencyFile.record
+	}
+	if len(sweepLevels) == 0 {
+		// phase of level of sweep is set by runSweep
+		samplePhase = phaseName("load")
+	}
+	slog.Info("Phase started", "phase", samplePhase, "qps", cfg.qps, "clients", cfg.c)
 	startTime := time.Now()
-	ctx, cancel := context.WithCancel(context.Background())
-	throttle.SetLimit(cfg.qps)
-	client.RunWorkers(cfg.c)
+	ctx, cancel := context.WithCancel(parent)
+	// done is closed once summary of stage is printed
+	done := make(chan struct{})
+	duration := *d
+	if resumed != nil {
+		duration -= resumed.Elapsed
+	}
+	if pattern != nil {
+		// throttle isn't used by bursts, so it is stopped to not overflow
+		throttle.Stop()
+		bursting = true
+	} else if *loadModel == loadModelConcurrency {
+		// clients send requests one after another, so rate isn't limited
+	} else if profile != nil {
+		setLimit(profile.qps(0))
+	} else {
+		setLimit(stepQPS(cfg.qps, 0))
+	}
+	_, constant := profile.(constantProfile)
+	if pattern == nil && (*rampSteps == 0 || *rampMode == "constant") && len(sweepLevels) == 0 && (profile == nil || constant) && replayOffsets == nil {
+		r.Lock()
+		steadyFrom = len(r.Connections)
+		r.Unlock()
+	}
+	workers := stepWorkers(cfg.c, 0)
+	if profile != nil {
+		workers = profileWorkers(cfg.c, profile.qps(0), profile.peak())
+	}
+	if *totalRequests > 0 && *warmupDuration == 0 {
+		client.LimitJobs(*totalRequests)
+	}
+	client.RunWorkers(workers)
+	if *warmupDuration > 0 {
+		warmUp(ctx)
+		startTime = time.Now()
+		if *totalRequests > 0 {
+			// requests of warmup aren't counted by -n
+			client.LimitJobs(*totalRequests)
+		}
+	}
 	go func() {
-		stateTick := time.Tick(samplePeriod)
-		timeout := time.After(*d)
-		bar, progressTicker := acquireProgressBar(*d)
+		defer close(done)
+		s := newSampler()
+		stateTick := s.next()
+		timeout := stageTimeout(ctx, duration)
+		bar, progressTicker := acquireProgressBar(duration)
+		var stepTick, checkpointTick, requestsCheck <-chan time.Time
+		if *totalRequests > 0 {
+			// load phase lasts until -n requests are completed, and so do ramp steps
+			timeout = stageTimeout(ctx, maxStageDuration)
+			bar.Total = int64(*totalRequests)
+			requestsCheck = time.Tick(requestsCheckPeriod)
+		} else if *rampSteps > 0 {
+			stepTick = time.Tick(*d / time.Duration(*rampSteps))
+		}
+		if *checkpointFile != "" {
+			checkpointTick = time.Tick(*checkpointInterval)
+		}
+		var pushTick <-chan time.Time
+		if *pushgatewayAddr != "" {
+			pushTick = time.Tick(*pushInterval)
+		}
+		var abortTick <-chan time.Time
+		var abortCheck *abortChecker
+		if abortEnabled() {
+			abortTick = time.Tick(*abortWindow)
+			abortCheck = newAbortChecker()
+		}
+		var profileTick <-chan time.Time
+		var profileRate float64
+		if profile != nil {
+			profileTick = time.Tick(profileTickPeriod)
+			profileRate = profile.qps(0)
+		}
+		meter := newStepMeter()
+		monitor := newSelfMonitor()
+		nextStep := func() {
+			// last step is finished by timeout or -max-bytes
+			if len(steps)+1 < *rampSteps {
+				steps = append(steps, meter.next(throttle.Limit()))
+				setLimit(stepQPS(cfg.qps, len(steps)))
+				if n := stepWorkers(cfg.c, len(steps)) - stepWorkers(cfg.c, len(steps)-1); n > 0 {
+					client.RunWorkers(n)
+				}
+			}
+		}
+		finish := func() {
+			finishProgressBar(bar)
+			drainOnInterrupt()
+			loadElapsed = time.Since(startTime)
+			if *rampSteps > 0 {
+				steps = append(steps, meter.next(throttle.Limit()))
+			}
+			addStagePoint("load", startTime)
+			if pushTick != nil {
+				// final values of load phase
+				pushgateway.Push()
+			}
+			if *totalRequests > 0 {
+				printTotalRequests()
+			}
+			printSummary("Loading test", startTime)
+			printSelfMonitor(monitor)
+			if pattern == nil {
+				throttle.Stop()
+			}
+			cancel()
+		}
 		for {
 			select {
 			case <-timeout:
-				finishProgressBar(bar)
-				printSummary("Loading test", startTime)
-				throttle.Stop()
-				cancel()
+				finish()
 				return
+			case <-requestsCheck:
+				n := client.JobsDone()
+				if isJobsDone() {
+					finish()
+					return
+				}
+				if *rampSteps > 0 && n >= stepRequests(len(steps)) {
+					nextStep()
+				}
 			case <-progressTicker:
-				bar.Increment()
+				if *totalRequests > 0 {
+					bar.Set64(int64(client.JobsDone()))
+				} else {
+					bar.Increment()
+				}
 			case <-stateTick:
-				printState()
+				s.sample()
+				monitor.sample()
+				stateTick = s.next()
+			case <-pushTick:
+				// push must not delay samples
+				go pushgateway.Push()
+			case <-stepTick:
+				nextStep()
+			case <-profileTick:
+				profileRate = updateProfile(cfg, time.Since(startTime), profileRate)
+			case <-checkpointTick:
+				saveCheckpoint(cfg, time.Since(startTime))
+			case cmd := <-controlCh:
+				cmd.reply <- applyControl(cmd)
+			case <-abortTick:
+				if reason := abortCheck.check(); reason != "" {
+					abortCheck.abort(reason, startTime)
+					finish()
+					return
+				}
 			}
 		}
 	}()
-	load(ctx)
+	if pattern != nil {
+		burstLoad(ctx, pattern)
+	} else if replayOffsets != nil {
+		replayLoad(ctx)
+	} else {
+		load(ctx, *totalRequests)
+	}
+	<-done
+}
+
+// stepMeter measures metrics of consecutive periods of load phase
+type stepMeter struct {
+	start    time.Time
+	requests uint64
+	errors   uint64
+}
+
+func newStepMeter() *stepMeter {
+	return &stepMeter{
+		start:    time.Now(),
+		requests: client.RequestSum(),
+		errors:   client.Errors(),
+	}
+}
+
+// next returns metrics of period since previous call and starts next period.
+// qps is the rate limit of finished period
+func (m *stepMeter) next(qps float64) rampStep {
+	requests, errs := client.RequestSum(), client.Errors()
+	s := rampStep{
+		qps:      qps,
+		workers:  client.Amount(),
+		requests: requests - m.requests,
+		p99:      client.NextStep()[0.99],
+	}
+	s.rps = float64(s.requests) / time.Since(m.start).Seconds()
+	if s.requests > 0 {
+		s.errorRate = float64(errs-m.errors) / float64(s.requests) * 100
+	}
+	m.start, m.requests, m.errors = time.Now(), requests, errs
+	return s
+}
+
+// stepQPS returns rate limit of ramp step with index i.
+// Rate reaches qps at the last step
+func stepQPS(qps float64, i int) float64 {
+	return qps * stepShare(i)
+}
+
+// stepWorkers returns number of clients of ramp step with index i.
+// Number is rounded up, so every step has clients and the last one has all c of them
+func stepWorkers(c, i int) int {
+	return int(math.Ceil(float64(c) * stepShare(i)))
+}
+
+// stepShare returns part of qps and clients of load phase, which is offered at ramp step with index i
+// according to -ramp-mode: linear ramp grows from 1/N at first step, exp one doubles every step,
+// and constant one offers the whole load from the start
+func stepShare(i int) float64 {
+	if *rampSteps < 1 || i >= *rampSteps-1 {
+		return 1
+	}
+	switch *rampMode {
+	case "exp":
+		return math.Pow(2, float64(i+1-*rampSteps))
+	case "constant":
+		return 1
+	default:
+		return float64(i+1) / float64(*rampSteps)
+	}
+}
+
+// stepRequests returns number of completed requests of load phase with -n,
+// reaching which finishes ramp step with index i
+func stepRequests(i int) uint64 {
+	return *totalRequests * uint64(i+1) / uint64(*rampSteps)
+}
+
+// isJobsDone returns true once all -n jobs are done and none of them is still queued or in flight
+func isJobsDone() bool {
+	return client.JobsDone() >= *totalRequests && client.Overflow() == 0 && client.InFlight() == 0
+}
+
+// printTotalRequests prints time spent to complete -n requests
+func printTotalRequests() {
+	n := client.JobsDone()
+	fmt.Fprintf(out, "\n------ Fixed number of requests ------\n")
+	if n < *totalRequests {
+		fmt.Fprintf(out, "Load phase was stopped after %d of %d requests in %s\n", n, *totalRequests, loadElapsed)
+		return
+	}
+	n = *totalRequests
+	fmt.Fprintf(out, "Completed %d requests in %s; %.2f rps\n", n, loadElapsed, float64(n)/loadElapsed.Seconds())
+	if sent := client.RequestSum(); sent < n {
+		fmt.Fprintf(out, "%d of them failed before being sent\n", n-sent)
+	}
 }
 
 func printState() {
+	rate := perWorkerRate()
 	if *debug {
 		fmt.Println("------------")
-		fmt.Printf("[ Multiplier = %f ]\n", multiplier)
+		fmt.Printf("[ Multiplier = %f ]\n", calib.multiplier)
 		fmt.Printf("QPS was increased to: %f\nWorkers: %d\nJobsch len: %d\n", throttle.Limit(), client.Amount(), client.Overflow())
 		fmt.Printf(" >> Num of cons: %d; Req done: %d; Errors: %d; Timeouts: %d\n", client.ConnOpen(), client.RequestSum(), client.Errors(), client.Timeouts())
+		fmt.Printf(" >> Per-client rate: %.2f rps", rate)
+		if thinkTime > 0 {
+			fmt.Printf("; Think time spent: %s", client.ThinkTimeSpent().Round(time.Millisecond))
+		}
+		fmt.Println()
+		if len(localAddrs) > 0 {
+			fmt.Printf(" >> Conns by local IP: %s\n", formatLocalConns(client.LocalConns()))
+		}
 		fmt.Println("------------")
 	}
+	slog.Debug("Sample", "phase", samplePhase, "qps", throttle.Limit(), "clients", client.Amount(), "queued", client.Overflow(),
+		"connections", client.ConnOpen(), "requests", client.RequestSum(), "errors", client.Errors(), "timeouts", client.Timeouts(), "client_rps", rate)
 
 	r.Lock()
+	// samples aren't evenly spaced if printState was delayed under load
+	r.Timestamps = append(r.Timestamps, time.Since(testStart).Seconds())
+	r.SamplePhases = append(r.SamplePhases, samplePhase)
 	r.Connections = append(r.Connections, client.ConnOpen())
-	r.Errors = append(r.Errors, client.Errors())
-	r.Timeouts = append(r.Timeouts, client.Timeouts())
-	r.RequestSum = append(r.RequestSum, client.RequestSum())
-	r.RequestSuccess = append(r.RequestSuccess, client.RequestSuccess())
-	r.BytesWritten = append(r.BytesWritten, client.BytesWritten())
-	r.BytesRead = append(r.BytesRead, client.BytesRead())
-	r.Qps = append(r.Qps, uint64(throttle.Limit()))
+	counts := sampleCounters()
+	r.Errors = append(r.Errors, counts.errors)
+	r.Timeouts = append(r.Timeouts, counts.timeouts)
+	r.RequestSum = append(r.RequestSum, counts.requests)
+	r.RequestSuccess = append(r.RequestSuccess, counts.success)
+	r.BytesWritten = append(r.BytesWritten, counts.bytesWritten)
+	r.BytesRead = append(r.BytesRead, counts.bytesRead)
+	r.Qps = append(r.Qps, uint64(qpsLimit()))
 	r.StatusCodes = client.StatusCodes()
+	r.StatusCounts = client.StatusCounts()
+	r.GRPCStatusCodes = client.GRPCStatusCodes()
+	r.Backends = client.Backends()
+	if responseAssertions != nil {
+		r.AssertionFailures = client.AssertionFailures()
+	}
 	r.ErrorMessages = client.ErrorMessages()
+	if resumed != nil {
+		resumed.merge(r, client.RequestSum())
+	}
 	r.UpdateRequestDuration(client.RequestDuration())
+	r.UpdateStatusCodeSeries(r.StatusCounts)
+	r.UpdateErrorClassSeries(client.ErrorClasses())
+	if len(targets) > 1 {
+		r.UpdateTargetDuration(targetP99())
+	}
+	if *separateFirstRequests {
+		r.UpdateFirstRequestDuration(client.FirstRequestDuration())
+	}
+	if *correctedLatency {
+		r.UpdateCorrectedDuration(client.CorrectedDuration())
+	}
+	if *connSetupLatency {
+		var handshake map[float64]float64
+		if r.HandshakeDuration != nil {
+			handshake = client.HandshakeDuration()
+		}
+		r.UpdateConnSetupDuration(client.ConnectDuration(), handshake)
+	}
+	if *latencyBreakdown {
+		r.UpdateLatencyBreakdown(breakdownSample())
+	}
 	r.Unlock()
+	if coordinator != nil {
+		coordinator.send(false)
+	}
+	if promServer != nil {
+		updatePromGauges()
+	}
+	if exporters != nil {
+		exportState()
+	}
+	if live != nil {
+		live.print()
+	}
+	if tui != nil {
+		tui.draw()
+	}
 }
 
 func isFlawed() bool {
@@ -221,30 +1069,386 @@ func isFlawed() bool {
 	return false
 }
 
-func load(ctx context.Context) {
+// load queues jobs by rate limit until ctx is done or limit of jobs is queued.
+// Zero limit means that number of jobs isn't limited
+func load(ctx context.Context, limit uint64) {
+	if *loadModel == loadModelConcurrency {
+		// clients don't need jobs
+		<-ctx.Done()
+		return
+	}
+	var queued uint64
+	var sched schedule
 	for {
+		if limit > 0 && queued >= limit {
+			// the rest of phase waits for queued jobs to be done
+			<-ctx.Done()
+			return
+		}
 		select {
 		case <-ctx.Done():
 			return
 		case <-throttle.QPS():
-			client.Jobsch <- struct{}{}
+			queued++
+			t := time.Now()
+			if *correctedLatency {
+				t = sched.next(t, qpsLimit())
+			}
+			if !client.Enqueue(ctx, t) {
+				return
+			}
 		}
 	}
 }
 
 func printSummary(stage string, t time.Time) {
+	if *captureErrors > 0 {
+		collectCaptured()
+	}
 	since := time.Since(t).Seconds()
-	fmt.Printf("\n------ %s ------\n", stage)
-	fmt.Printf("Elapsed time: %fs\n", since)
-	fmt.Printf("Req done: %d; Success: %.2f %%\n", client.RequestSum(), (float64(client.RequestSuccess())/float64(client.RequestSum()))*100)
-	fmt.Printf("QPS: %f; Connections: %d\n", float64(client.RequestSum())/since, client.ConnOpen())
-	fmt.Printf("Errors: %d; Timeouts: %d\n\n", client.Errors(), client.Timeouts())
+	fmt.Fprintf(out, "\n------ %s ------\n", stage)
+	fmt.Fprintf(out, "Elapsed time: %fs\n", since)
+	fmt.Fprintf(out, "Req done: %d; Success: %.2f %%\n", client.RequestSum(), (float64(client.RequestSuccess())/float64(client.RequestSum()))*100)
+	fmt.Fprintf(out, "QPS: %f; Connections: %d\n", float64(client.RequestSum())/since, client.ConnOpen())
+	fmt.Fprintf(out, "Errors: %d; Timeouts: %d; Read errors: %d\n", client.Errors(), client.Timeouts(), client.ReadErrors())
+	printStatusCounts()
+	printErrors()
+	printThrottled()
+	printBackpressure()
+	if client.RequestSum() > 0 {
+		l := client.Latency()
+		fmt.Fprintf(out, "Latency: p50: %s; p90: %s; p95: %s; p99: %s; max: %s\n",
+			formatLatency(l.P50), formatLatency(l.P90), formatLatency(l.P95), formatLatency(l.P99), formatLatency(l.Max))
+	} else {
+		fmt.Fprintln(out, "Latency: no requests completed")
+	}
+	if client.Timeouts() > 0 {
+		fmt.Fprintf(out, "Timeouts while connecting: %d; Waiting for first byte: %d; Reading body: %d\n",
+			client.ConnectTimeouts(), client.FirstByteTimeouts(), client.BodyReadTimeouts())
+	}
+	if n := client.WarmedConns(); n > 0 {
+		first, measured := client.FirstRequestDuration()[0.5], client.RequestDuration()[0.5]
+		fmt.Fprintf(out, "Warmed up connections: %d; Median latency of first request on connection: %s; "+
+			"Of measured requests: %s; Warmup cost: %s\n", n, formatLatency(first), formatLatency(measured), formatLatency(first-measured))
+	}
+	if *separateFirstRequests {
+		printFirstRequests()
+	}
+	if *connSetupLatency {
+		printConnSetup()
+	}
+	if *latencyBreakdown {
+		printLatencyBreakdown()
+	}
+	printQueueWait()
+	if *correctedLatency {
+		printCorrectedLatency()
+	}
+	if uploadRate > 0 {
+		fmt.Fprintf(out, "Upload rate: %s; Upload stalls (timeouts while writing request): %d\n", *uploadRateFlag, client.UploadStalls())
+	}
+	if retryPolicy != nil {
+		printRetries()
+	}
+	if len(targets) > 0 {
+		printTargets(since)
+	}
+	if r.InjectedFaults != "" {
+		fmt.Fprintf(out, "Client-injected faults: %s; dropped requests: %d\n", r.InjectedFaults, client.InjectedDrops())
+	}
+	if n := client.ProxyAuthErrors(); n > 0 && proxy.socks5 {
+		fmt.Fprintf(out, "Connections with credentials rejected by SOCKS5 proxy: %d\n", n)
+	} else if n > 0 {
+		fmt.Fprintf(out, "Connections declined by proxy with 407 Proxy Authentication Required: %d\n", n)
+	}
+	if n := client.DNSLookups(); n > 0 && (*dnsServer != "" || *dnsResolveInterval > 0) {
+		fmt.Fprintf(out, "DNS lookups: %d; Failed: %d; Changes of resolved IPs: %d\n",
+			n, client.ErrorClasses()[fastclient.ErrorClassDNS], client.DNSChanges())
+	}
+	if n := client.CompressedResponses(); n > 0 {
+		fmt.Fprintf(out, "Compressed responses: %d; Body size on wire: %s; Decoded: %s\n",
+			n, formatBytes(client.BytesCompressed()), formatBytes(client.BytesDecoded()))
+	}
+	if n := client.CompressedRequests(); n > 0 {
+		fmt.Fprintf(out, "Compressed requests: %d; Body size on wire: %s; Uncompressed: %s\n",
+			n, formatBytes(client.RequestBytesCompressed()), formatBytes(client.RequestBytesUncompressed()))
+	}
+	if *http10 {
+		errs := client.HTTP10Errors()
+		fmt.Fprintf(out, "HTTP/1.0 violations: 505 HTTP Version Not Supported: %d; chunked responses: %d\n",
+			errs[fastclient.HTTP10Unsupported], errs[fastclient.HTTP10Chunked])
+	}
+	if *grpcMethod != "" {
+		fmt.Fprintf(out, "gRPC status codes: %s\n", formatShares(client.GRPCStatusCodes()))
+	}
+	if *wsFlag {
+		fmt.Fprintf(out, "WebSocket connections: %d (%.2f per second); Failed: %d; Dropped: %d\n",
+			client.WSConnects(), float64(client.WSConnects())/since, client.WSConnectFailures(), client.WSDrops())
+	}
+	if *backendHeader != "" {
+		fmt.Fprintf(out, "Backends: %s\n", formatShares(client.Backends()))
+		if affinity != nil {
+			printAffinityBreaks()
+		}
+	}
+	if *echoHeader != "" {
+		printEchoResults()
+	}
+	if responseAssertions != nil {
+		printAssertionFailures()
+	}
+	if n := client.PortExhausted(); n > 0 {
+		fmt.Fprintf(out, "Not sent because of local ports exhaustion: %d\n", n)
+	}
+	fmt.Fprintln(out)
+}
+
+// printThroughputTrend prints change of achieved rps over load phase with constant offered load
+// and marks report if throughput degraded significantly, e.g. because of memory leak of target
+func printThroughputTrend() {
+	if steadyFrom < 0 {
+		return
+	}
+	change, ok := r.ThroughputTrend(steadyFrom)
+	if !ok {
+		return
+	}
+	fmt.Fprintf(out, "Throughput trend over steady phase: %+.2f %%\n", change)
+	if -change > significantDegradation {
+		r.ThroughputDegradation = -change
+		fmt.Fprintf(out, "Warning: throughput degraded %.2f %% over the steady phase under constant offered load\n", -change)
+	}
+	fmt.Fprintln(out)
+}
+
+// injectedFaults returns description of faults injected by -inject-latency
+// and -inject-drop. Is empty if faults aren't injected
+func injectedFaults() string {
+	var faults []string
+	if *injectLatency > 0 {
+		faults = append(faults, fmt.Sprintf("latency %s per request", *injectLatency))
+	}
+	if *injectDrop > 0 {
+		faults = append(faults, fmt.Sprintf("drop of %.2f %% of requests", *injectDrop*100))
+	}
+	return strings.Join(faults, ", ")
+}
+
+func printSteps() {
+	if len(steps) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out, "------ Ramp steps ------")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Step\tQPS limit\tClients\tRps\tErrors\tp99")
+	for i, s := range steps {
+		p99 := "insufficient data"
+		if s.requests >= *minSamples {
+			p99 = formatLatency(s.p99)
+		}
+		fmt.Fprintf(w, "%d\t%.2f\t%d\t%.2f\t%.2f %%\t%s\n", i+1, s.qps, s.workers, s.rps, s.errorRate, p99)
+	}
+	w.Flush()
+	fmt.Fprintln(out)
+}
+
+// formatLatency formats latency measured in seconds in unit of report.
+// If unit of report isn't detected yet, it is detected by given latency
+func formatLatency(seconds float64) string {
+	unit := r.LatencyUnit
+	if unit == "" {
+		unit = *latencyUnit
+	}
+	if unit == "auto" {
+		unit = report.AutoLatencyUnit(math.Abs(seconds))
+	}
+	return report.FormatLatency(seconds, unit)
+}
+
+// formatShares formats map of names to percents like "OK 99.50 %, UNAVAILABLE 0.50 %".
+// Names are sorted by descending percent
+func formatShares(shares map[string]float64) string {
+	names := make([]string, 0, len(shares))
+	for name := range shares {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return shares[names[i]] > shares[names[j]]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %.2f %%", name, shares[name])
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printFirstRequests prints latency of the first requests on connections, which is excluded
+// from latency of subsequent requests, and penalty of cold connection compared to warm one
+func printFirstRequests() {
+	n := client.FirstRequests()
+	fmt.Fprintf(out, "First requests on connections: %d", n)
+	if n > 0 {
+		first := client.FirstRequestLatency()
+		fmt.Fprintf(out, "; p50: %s; p99: %s; max: %s", formatLatency(first.P50), formatLatency(first.P99), formatLatency(first.Max))
+		if client.RequestSum() > n {
+			fmt.Fprintf(out, "; Cold connection penalty (p50): %s", formatLatency(first.P50-client.Latency().P50))
+		}
+	}
+	fmt.Fprintln(out)
+}
+
+// printConnSetup prints time of TCP connect and TLS handshake of connections established during stage
+func printConnSetup() {
+	n := client.Connects()
+	fmt.Fprintf(out, "Connection setup: connects: %d", n)
+	if n > 0 {
+		l := client.ConnectLatency()
+		fmt.Fprintf(out, "; p50: %s; p99: %s; max: %s", formatLatency(l.P50), formatLatency(l.P99), formatLatency(l.Max))
+	}
+	if r.HandshakeDuration != nil {
+		n = client.Handshakes()
+		fmt.Fprintf(out, "; TLS handshakes: %d", n)
+		if n > 0 {
+			l := client.HandshakeLatency()
+			fmt.Fprintf(out, "; p50: %s; p99: %s; max: %s", formatLatency(l.P50), formatLatency(l.P99), formatLatency(l.Max))
+		}
+	}
+	fmt.Fprintln(out)
+}
+
+// printQueueWait prints wait of requests in job queue if it is included in latency
+// and warns if it isn't included, but is significant compared to latency
+func printQueueWait() {
+	if client.RequestSum() < *minSamples {
+		return
+	}
+	wait := client.QueueWait()[0.99]
+	if *latencyPerspective == "client" {
+		fmt.Fprintf(out, "Latency is measured from client perspective; p99 of wait in job queue: %s\n", formatLatency(wait))
+		return
+	}
+	if wait > client.RequestDuration()[0.99]*significantQueueWait {
+		fmt.Fprintf(out, "Warning: p99 of wait in job queue is %s, which isn't included in server perspective latency; "+
+			"set -latency-perspective client to include it\n", formatLatency(wait))
+	}
+}
+
+// printAffinityBreaks prints number of requests, which were served
+// by another backend than previous request of the same worker
+func printAffinityBreaks() {
+	n := client.AffinityBreaks()
+	fmt.Fprintf(out, "Affinity breaks: %d (%.2f %% of requests)", n, float64(n)/float64(client.RequestSum())*100)
+	if n > 0 {
+		fmt.Fprintf(out, "; affinity isn't honored by load balancer")
+	}
+	fmt.Fprintln(out)
+}
+
+func printKeepAliveLimit() {
+	// gRPC calls and HTTP/2 requests are multiplexed over connections, so requests per connection aren't counted.
+	// Connections of dropped and rotated requests are closed by client, so they don't show limit of server
+	if *disableKeepAlive || *grpcMethod != "" || *http2 || *injectDrop > 0 || *maxConnDuration > 0 || *maxConnRequests > 0 {
+		return
+	}
+	dist := client.ConnRequests()
+	limit, ok := keepAliveLimit(dist)
+	if !ok {
+		return
+	}
+	var total uint64
+	for _, conns := range dist {
+		total += conns
+	}
+	fmt.Fprintf(out, "Server appears to close connections after %d requests (%d of %d closed connections)\n\n",
+		limit, dist[limit], total)
+}
+
+// connReuse returns average number of requests per connection of load phase
+func connReuse() float64 {
+	return requestsPerConn(client.RequestSum(), client.ConnRequests(), client.ConnOpen())
+}
+
+func printConnReuse() {
+	if *minRequestsPerConn <= 0 {
+		return
+	}
+
+	reuse := connReuse()
+	fmt.Fprintf(out, "------ Connection reuse (min %.2f requests per connection) ------\n", *minRequestsPerConn)
+	fmt.Fprintf(out, "Average requests per connection: %.2f; %s\n\n", reuse, passedString(reuse >= *minRequestsPerConn))
+}
+
+func printIncidents() {
+	if *incidentThreshold <= 0 {
+		return
+	}
+
+	incidents := r.Incidents(*incidentThreshold)
+	fmt.Fprintf(out, "------ Incidents (errors rate above %.2f%%) ------\n", *incidentThreshold)
+	for _, i := range incidents {
+		fmt.Fprintf(out, "At %.1fs: lasted %.1fs; Peak errors rate: %.2f %%\n", i.Start, i.Duration, i.PeakErrorRate)
+	}
+	if len(incidents) == 0 {
+		fmt.Fprintln(out, "No incidents")
+	}
+	fmt.Fprintln(out)
+}
+
+// headroom returns sustained qps of load phase and
+// percent of its excess over target qps. Is negative if target wasn't reached
+func headroom(target float64) (float64, float64) {
+	if loadElapsed <= 0 {
+		return 0, -100
+	}
+	sustained := float64(client.RequestSuccess()) / loadElapsed.Seconds()
+	return sustained, (sustained - target) / target * 100
+}
+
+func printHeadroom() {
+	if *slaTargetQPS <= 0 {
+		return
+	}
+
+	sustained, h := headroom(*slaTargetQPS)
+	fmt.Fprintf(out, "------ Capacity headroom (target %.2f qps) ------\n", *slaTargetQPS)
+	fmt.Fprintf(out, "Sustained QPS (success requests only): %.2f\n", sustained)
+	if h >= 0 {
+		fmt.Fprintf(out, "Headroom: %.2f %%\n\n", h)
+		return
+	}
+	fmt.Fprintf(out, "Under capacity: sustained qps is %.2f %% below target\n\n", -h)
+}
+
+func printSLA() {
+	if contract == nil {
+		return
+	}
+
+	res := contract.check(client.RequestSum(), client.RequestSuccess(), loadElapsed)
+	fmt.Fprintf(out, "------ SLA: %s ------\n", contract)
+	fmt.Fprintf(out, "QPS: %.2f; %s\n", res.qps, passedString(res.qpsPassed(contract)))
+	fmt.Fprintf(out, "Availability: %.3f %%; %s\n", res.availability, passedString(res.availabilityPassed(contract)))
+	fmt.Fprintf(out, "Error budget consumed: %.2f %%; Remaining: %.2f %%\n", res.budgetConsumed, 100-res.budgetConsumed)
+	fmt.Fprintf(out, "Result: %s\n\n", passedString(len(res.failures(contract)) == 0))
+}
+
+func passedString(ok bool) string {
+	if ok {
+		return "PASSED"
+	}
+	return "FAILED"
 }
 
 func acquireProgressBar(t time.Duration) (*pb.ProgressBar, <-chan time.Time) {
 	pb := pb.New64(int64(t.Seconds()))
 	pb.ShowCounters = false
 	pb.ShowPercent = false
+	// progress bar would overwrite status line of -live and dashboard of -tui
+	pb.NotPrint = quiet || live != nil || tui != nil
 	pb.Start()
 	return pb, time.Tick(time.Second)
 }
@@ -253,3 +1457,33 @@ func finishProgressBar(pb *pb.ProgressBar) {
 	pb.Set64(pb.Total)
 	pb.Finish()
 }
+
+// warmUp sends load of load phase for -warmup, so connections are established before measuring.
+// Then metrics are reset, while connections are kept open for measured requests.
+// Warmup is summarized as separate phase and its window is marked on report charts
+func warmUp(ctx context.Context) {
+	fmt.Fprintf(out, "Warm up connections for %s\n", *warmupDuration)
+	if latencyFile != nil {
+		latencyFile.skip()
+	}
+	startTime := time.Now()
+	wctx, cancel := context.WithTimeout(ctx, *warmupDuration)
+	load(wctx, 0)
+	cancel()
+	// queued jobs of warmup must not be measured
+	client.DropQueued()
+	conns := client.ConnOpen()
+	addStagePoint("warmup", startTime)
+	client.ResetMetrics()
+	if latencyFile != nil {
+		latencyFile.skipBefore(time.Now())
+	}
+	r.Lock()
+	r.Annotations = append(r.Annotations, report.Annotation{
+		Time:     startTime.Sub(testStart).Seconds(),
+		Duration: time.Since(startTime).Seconds(),
+		Label:    "warmup (excluded)",
+	})
+	r.Unlock()
+	fmt.Fprintf(out, "Warmed up %d connections\n", conns)
+}
@@ -0,0 +1,224 @@
+// Package loader sends load with constant rate and number of clients to target,
+// so load tests could be embedded into Go code like integration tests
+// and their results could be inspected without parsing output of fasthttploader.
+//
+// Loader is a separate minimal engine rather than the one of fasthttploader command:
+// it runs only load phase with given rate and clients, without burst and calibration,
+// warmup, templates, assertions, thresholds and report. Jobs are queued by fastclient.Client.Enqueue
+// like the command does, so requests and queue accounting of the same load are the same.
+//
+// Metrics of fastclient are global, so Run of different loaders isn't concurrent.
+package loader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/ratelimiter"
+	"github.com/valyala/fasthttp"
+)
+
+// Config is a configuration of load
+type Config struct {
+	// URL is an url of target like "http://localhost:8080/items"
+	URL string
+
+	// Method is HTTP method of requests. GET is used if empty
+	Method string
+
+	// Headers are headers of requests
+	Headers map[string]string
+
+	// Body is a body of requests
+	Body []byte
+
+	// QPS is a rate limit of requests per second
+	QPS float64
+
+	// Clients is a number of clients sending requests concurrently
+	Clients int
+
+	// Duration is a duration of load
+	Duration time.Duration
+
+	// Timeout is a max time of establishing connection and of request. 5s is used if zero
+	Timeout time.Duration
+
+	// SuccessStatusCode is a status code of successful responses. 200 is used if zero
+	SuccessStatusCode int
+}
+
+// Results are metrics of load
+type Results struct {
+	Requests     uint64
+	Success      uint64
+	Errors       uint64
+	Timeouts     uint64
+	BytesWritten uint64
+	BytesRead    uint64
+
+	// StatusCounts maps status code to number of responses
+	StatusCounts map[int]uint64
+
+	// ErrorMessages maps error message to number of errors
+	ErrorMessages map[string]int
+
+	// Latency contains percentiles and max of latency of requests in seconds.
+	// Is zero if no requests were done
+	Latency fastclient.Latency
+
+	// QueueFull is a number of jobs, which waited for free clients, since all of them were busy.
+	// Load was limited by Clients rather than QPS if it isn't zero
+	QueueFull uint64
+
+	// Elapsed is an actual duration of load
+	Elapsed time.Duration
+}
+
+// RPS returns average number of requests per second
+func (r Results) RPS() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// ErrorRate returns percent of failed requests
+func (r Results) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests) * 100
+}
+
+// Loader sends load configured by Config
+type Loader struct {
+	cfg Config
+	req *fasthttp.Request
+
+	mu      sync.Mutex
+	results Results
+}
+
+// drainPollPeriod is a period of checking whether jobs in flight are done
+const drainPollPeriod = 5 * time.Millisecond
+
+// runMu serializes Run of all loaders, since metrics of fastclient are global
+var runMu sync.Mutex
+
+// New returns Loader for cfg
+func New(cfg Config) (*Loader, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("url must be set")
+	}
+	if cfg.QPS <= 0 {
+		return nil, fmt.Errorf("qps must be positive")
+	}
+	if cfg.Clients < 1 {
+		return nil, fmt.Errorf("number of clients must be positive")
+	}
+	if cfg.Duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+	if cfg.Timeout < 0 {
+		return nil, fmt.Errorf("timeout can't be negative")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.SuccessStatusCode == 0 {
+		cfg.SuccessStatusCode = fasthttp.StatusOK
+	}
+
+	req := new(fasthttp.Request)
+	req.SetRequestURI(cfg.URL)
+	if scheme := string(req.URI().Scheme()); scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme of url %q", cfg.URL)
+	}
+	if len(req.URI().Host()) == 0 {
+		return nil, fmt.Errorf("url %q has no host", cfg.URL)
+	}
+	if cfg.Method != "" {
+		req.Header.SetMethod(cfg.Method)
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	req.SetBody(cfg.Body)
+	return &Loader{cfg: cfg, req: req}, nil
+}
+
+// Run sends load for Config.Duration and stores its metrics, which are returned by Results.
+// Requests in flight at the end of load are awaited and counted, while queued ones are dropped.
+// If ctx is done earlier, load is stopped and ctx.Err() is returned, while metrics are still stored
+func (l *Loader) Run(ctx context.Context) error {
+	runMu.Lock()
+	defer runMu.Unlock()
+
+	client := fastclient.New(l.req, l.cfg.Timeout, l.cfg.SuccessStatusCode)
+	throttle := ratelimiter.NewLimiter()
+	throttle.SetLimit(l.cfg.QPS)
+	client.RunWorkers(l.cfg.Clients)
+
+	start := time.Now()
+	loadCtx, cancel := context.WithTimeout(ctx, l.cfg.Duration)
+	send(loadCtx, throttle, client)
+	cancel()
+	throttle.Stop()
+	l.drain(client)
+
+	results := Results{
+		Requests:      client.RequestSum(),
+		Success:       client.RequestSuccess(),
+		Errors:        client.Errors(),
+		Timeouts:      client.Timeouts(),
+		BytesWritten:  client.BytesWritten(),
+		BytesRead:     client.BytesRead(),
+		StatusCounts:  client.StatusCounts(),
+		ErrorMessages: client.ErrorMessages(),
+		Latency:       client.Latency(),
+		QueueFull:     client.QueueFull(),
+		Elapsed:       time.Since(start),
+	}
+	// stops workers and resets metrics
+	client.Flush()
+
+	l.mu.Lock()
+	l.results = results
+	l.mu.Unlock()
+	return ctx.Err()
+}
+
+// drain drops jobs, which weren't taken by workers yet, and waits for jobs in flight,
+// so their requests are counted by Results. Requests are limited by Timeout, so is waiting
+func (l *Loader) drain(client *fastclient.Client) {
+	dropped := uint64(client.DropQueued())
+	deadline := time.Now().Add(2 * l.cfg.Timeout)
+	for client.JobsDone()+dropped < client.JobsQueued() && time.Now().Before(deadline) {
+		time.Sleep(drainPollPeriod)
+	}
+}
+
+// send queues jobs by rate of throttle via Enqueue until ctx is done
+func send(ctx context.Context, throttle *ratelimiter.Limiter, client *fastclient.Client) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-throttle.QPS():
+			if !client.Enqueue(ctx, time.Now()) {
+				return
+			}
+		}
+	}
+}
+
+// Results returns metrics of the last Run
+func (l *Loader) Results() Results {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.results
+}
@@ -0,0 +1,136 @@
+package loader
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func serve(t *testing.T, h fasthttp.RequestHandler) net.Listener {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	go fasthttp.Serve(ln, h)
+	return ln
+}
+
+func TestNew(t *testing.T) {
+	f := func(cfg Config, expOK bool) {
+		t.Helper()
+		if _, err := New(cfg); (err == nil) != expOK {
+			t.Fatalf("unexpected error for %+v: %v", cfg, err)
+		}
+	}
+	valid := Config{URL: "http://localhost:8080", QPS: 10, Clients: 1, Duration: time.Second}
+	f(valid, true)
+
+	cfg := valid
+	cfg.URL = ""
+	f(cfg, false)
+	cfg.URL = "ftp://localhost"
+	f(cfg, false)
+
+	cfg = valid
+	cfg.QPS = 0
+	f(cfg, false)
+
+	cfg = valid
+	cfg.Clients = 0
+	f(cfg, false)
+
+	cfg = valid
+	cfg.Duration = 0
+	f(cfg, false)
+
+	cfg = valid
+	cfg.Timeout = -time.Second
+	f(cfg, false)
+}
+
+func TestLoaderRun(t *testing.T) {
+	ln := serve(t, func(ctx *fasthttp.RequestCtx) {
+		if string(ctx.Method()) != "POST" || string(ctx.PostBody()) != "body" || string(ctx.Request.Header.Peek("X-Test")) != "1" {
+			ctx.SetStatusCode(fasthttp.StatusBadRequest)
+			return
+		}
+		ctx.WriteString("OK")
+	})
+	defer ln.Close()
+
+	l, err := New(Config{
+		URL:      "http://" + ln.Addr().String() + "/",
+		Method:   "POST",
+		Headers:  map[string]string{"X-Test": "1"},
+		Body:     []byte("body"),
+		QPS:      100,
+		Clients:  2,
+		Duration: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := l.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res := l.Results()
+	if res.Requests < 50 || res.Requests > 120 {
+		t.Fatalf("expected about 100 requests; got %d", res.Requests)
+	}
+	if res.Errors > 0 || res.StatusCounts[fasthttp.StatusOK] != res.Requests {
+		t.Fatalf("expected all responses to be successful; got %d errors and status counts %v", res.Errors, res.StatusCounts)
+	}
+	if res.QueueFull != 0 {
+		t.Fatalf("expected clients to keep up with qps; got %d jobs waiting for free clients", res.QueueFull)
+	}
+	if res.Latency.P99 <= 0 || res.RPS() <= 0 || res.ErrorRate() != 0 {
+		t.Fatalf("unexpected results %+v", res)
+	}
+}
+
+func TestLoaderRunCanceled(t *testing.T) {
+	ln := serve(t, func(ctx *fasthttp.RequestCtx) {})
+	defer ln.Close()
+
+	l, err := New(Config{URL: "http://" + ln.Addr().String() + "/", QPS: 100, Clients: 1, Duration: time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := l.Run(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected %s; got %v", context.DeadlineExceeded, err)
+	}
+	if res := l.Results(); res.Elapsed >= time.Second {
+		t.Fatalf("expected load to be stopped by context; got elapsed %s", res.Elapsed)
+	}
+}
+
+func TestLoaderRunInFlight(t *testing.T) {
+	var received atomic.Uint64
+	// requests are still in flight once duration is over
+	ln := serve(t, func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(200 * time.Millisecond)
+		received.Add(1)
+	})
+	defer ln.Close()
+
+	l, err := New(Config{URL: "http://" + ln.Addr().String() + "/", QPS: 100, Clients: 4, Duration: 300 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := l.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res := l.Results()
+	if n := received.Load(); res.Requests != n || res.Success != n {
+		t.Fatalf("expected requests in flight to be counted; got %d requests and %d successful of %d received by target", res.Requests, res.Success, n)
+	}
+	if res.Requests == 0 || res.Elapsed < 400*time.Millisecond {
+		t.Fatalf("expected Run to wait for requests in flight; got %d requests in %s", res.Requests, res.Elapsed)
+	}
+}
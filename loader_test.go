@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/ratelimiter"
+	"github.com/hagen1778/fasthttploader/report"
+	"github.com/valyala/fasthttp"
+)
+
+func TestMakeReport(t *testing.T) {
+	defer func(name string, page *report.Page) { *fileName, r = name, page }(*fileName, r)
+	defer func() { reportFormats = nil }()
+
+	// report is written into temporary dir, so test run never leaves it in tree
+	*fileName = filepath.Join(t.TempDir(), "report.html")
+	reportFormats = []string{"html", "json"}
+	r = &report.Page{ThroughputDegradation: 12.5}
+	makeReport()
+	b, err := os.ReadFile(*fileName)
+	if err != nil {
+		t.Fatalf("Cannot read html report: %s", err)
+	}
+	if !strings.Contains(string(b), "Throughput degraded 12.50% over the steady phase") {
+		t.Errorf("Html report must contain throughput degradation")
+	}
+	if _, err := os.Stat(reportFile("json")); err != nil {
+		t.Errorf("JSON report must be written next to html report: %s", err)
+	}
+}
+
+func TestBurstStart(t *testing.T) {
+	f := func(requests, errs uint64, expectedQPS float64, expectedC int) {
+		t.Helper()
+		qps, c := burstStart(1000, 100, requests, errs)
+		if qps != expectedQPS || c != expectedC {
+			t.Errorf("Unexpected result for %d errors of %d requests. Got: %.2f qps, %d clients; Expected: %.2f qps, %d clients",
+				errs, requests, qps, c, expectedQPS, expectedC)
+		}
+	}
+
+	f(0, 0, 1000, 100)
+	f(5000, 0, 1000, 100)
+	f(5000, 100, 1000, 100)
+	// 50% of errors
+	f(5000, 2500, 500, 50)
+	f(5000, 101, 500, 50)
+}
+
+func TestRampSteps(t *testing.T) {
+	defer func(steps int, mode string) {
+		*rampSteps, *rampMode = steps, mode
+	}(*rampSteps, *rampMode)
+
+	f := func(mode string, steps int, expectedQPS []float64, expectedC []int) {
+		t.Helper()
+		*rampSteps, *rampMode = steps, mode
+		for i := range expectedQPS {
+			if qps := stepQPS(800, i); math.Abs(qps-expectedQPS[i]) > 1e-9 {
+				t.Errorf("Unexpected qps of step %d in %s mode. Got: %.2f; Expected: %.2f", i, mode, qps, expectedQPS[i])
+			}
+			if c := stepWorkers(10, i); c != expectedC[i] {
+				t.Errorf("Unexpected clients of step %d in %s mode. Got: %d; Expected: %d", i, mode, c, expectedC[i])
+			}
+		}
+	}
+
+	f("linear", 0, []float64{800}, []int{10})
+	f("linear", 4, []float64{200, 400, 600, 800}, []int{3, 5, 8, 10})
+	f("linear", 3, []float64{800.0 / 3, 1600.0 / 3, 800}, []int{4, 7, 10})
+	f("exp", 4, []float64{100, 200, 400, 800}, []int{2, 3, 5, 10})
+	f("constant", 4, []float64{800, 800, 800, 800}, []int{10, 10, 10, 10})
+}
+
+func TestMakeLoadTotalRequests(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	defer ln.Close()
+	var received atomic.Uint64
+	// every third request fails, so errors don't change number of sent requests
+	go fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {
+		if received.Add(1)%3 == 0 {
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		}
+	})
+
+	defer func(n uint64, model string, w io.Writer, page *report.Page) {
+		*totalRequests, *loadModel, out, r = n, model, w, page
+	}(*totalRequests, *loadModel, out, r)
+	defer func() { throttle = ratelimiter.NewLimiter() }()
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	f := func(model string, n uint64) {
+		t.Helper()
+		*totalRequests, *loadModel = n, model
+		received.Store(0)
+		var buf bytes.Buffer
+		out = &buf
+		r = &report.Page{RequestDuration: make(map[float64][]float64)}
+		// throttle can't be used after stop
+		throttle = ratelimiter.NewLimiter()
+		makeLoad(context.Background(), &loadConfig{qps: 5000, c: 8})
+		defer client.Flush()
+
+		if got := received.Load(); got != n {
+			t.Errorf("Unexpected number of requests received by target with -load-model %q. Got: %d; Expected: %d", model, got, n)
+		}
+		if got := client.RequestSum(); got != n {
+			t.Errorf("Unexpected number of sent requests with -load-model %q. Got: %d; Expected: %d", model, got, n)
+		}
+		if got := client.RequestSuccess(); got != n-n/3 {
+			t.Errorf("Unexpected number of successful requests with -load-model %q. Got: %d; Expected: %d", model, got, n-n/3)
+		}
+		if expected := fmt.Sprintf("Completed %d requests in", n); !strings.Contains(buf.String(), expected) {
+			t.Errorf("Summary with -load-model %q must contain %q; got:\n%s", model, expected, buf.String())
+		}
+	}
+
+	f("", 137)
+	f(loadModelConcurrency, 59)
+}
+
+func TestFlushOnFailure(t *testing.T) {
+	defer func(w io.Writer) {
+		out = w
+		outBuf.Reset()
+	}(out)
+	f := func(failures []string, expected string) {
+		t.Helper()
+		outBuf.Reset()
+		out = &outBuf
+		fmt.Fprintln(out, "Summary")
+		var buf bytes.Buffer
+		failed := len(failures) > 0
+		if ok := flushOnFailure(&buf, failures); ok != failed {
+			t.Fatalf("Unexpected result of flush for failures %q. Got: %v; Expected: %v", failures, ok, failed)
+		}
+		if failed {
+			// results are printed to w after flush
+			printFailures(failures)
+		}
+		if buf.String() != expected {
+			t.Errorf("Unexpected output for failures %q. Got: %q; Expected: %q", failures, buf.String(), expected)
+		}
+	}
+
+	// summary of passed test isn't printed
+	f(nil, "Test passed\n")
+	f([]string{"sla"}, "Summary\nTest failed:\n - sla\n\n")
+}
+
+func TestFormatLatency(t *testing.T) {
+	defer func(page *report.Page, unit string) {
+		r, *latencyUnit = page, unit
+	}(r, *latencyUnit)
+	f := func(pageUnit, flagUnit string, seconds float64, expected string) {
+		t.Helper()
+		r = &report.Page{LatencyUnit: pageUnit}
+		*latencyUnit = flagUnit
+		if got := formatLatency(seconds); got != expected {
+			t.Errorf("Unexpected latency with unit %q of page and %q of flag. Got: %q; Expected: %q", pageUnit, flagUnit, got, expected)
+		}
+	}
+
+	// unit of page is chosen once report is filled
+	f("ms", "auto", 1.5, "1500.00ms")
+	f("", "us", 0.002, "2000.00us")
+	// auto unit is chosen by magnitude of every value before report is filled
+	f("", "auto", 1.5, "1.50s")
+	f("", "auto", -0.002, "-2.00ms")
+}
+
+func TestSetLimit(t *testing.T) {
+	defer func(max float64, clipped bool) {
+		*maxAllowedQPS, qpsClipped = max, clipped
+		throttle = ratelimiter.NewLimiter()
+	}(*maxAllowedQPS, qpsClipped)
+	f := func(max, qps, expected float64, clipped bool) {
+		t.Helper()
+		*maxAllowedQPS, qpsClipped = max, false
+		throttle = ratelimiter.NewLimiter()
+		defer throttle.Stop()
+		setLimit(qps)
+		if got := throttle.Limit(); got != expected {
+			t.Errorf("Unexpected limit of %v qps with -max-allowed-qps %v. Got: %v; Expected: %v", qps, max, got, expected)
+		}
+		if qpsClipped != clipped {
+			t.Errorf("Unexpected clipping of %v qps with -max-allowed-qps %v. Got: %v; Expected: %v", qps, max, qpsClipped, clipped)
+		}
+	}
+
+	f(0, 5000, 5000, false)
+	f(100, 50, 50, false)
+	f(100, 100, 100, false)
+	f(100, 5000, 100, true)
+}
+
+func TestBurstThroughputMaxAllowedQPS(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	defer ln.Close()
+	go fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {})
+
+	defer func(max float64, d time.Duration, clients int, w io.Writer, page *report.Page, points []curvePoint) {
+		*maxAllowedQPS, *burstDuration, *c, out, r, stagePoints = max, d, clients, w, page, points
+		throttle = ratelimiter.NewLimiter()
+	}(*maxAllowedQPS, *burstDuration, *c, out, r, stagePoints)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	*maxAllowedQPS, *burstDuration, *c = 100, 500*time.Millisecond, 4
+	out = io.Discard
+	r = &report.Page{RequestDuration: make(map[float64][]float64)}
+	throttle = ratelimiter.NewLimiter()
+	defer throttle.Stop()
+	burstThroughput(context.Background(), &loadConfig{})
+	defer client.Flush()
+
+	// unlimited burst sends thousands of requests to local target in 500ms
+	if n := client.RequestSum(); n == 0 || n > 100 {
+		t.Errorf("Unexpected number of requests of burst capped by -max-allowed-qps 100 in 500ms. Got: %d; Expected: 1..100", n)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Models of load set by -load-model
+const (
+	// loadModelArrivalRate sends requests on schedule of rate limit regardless of responses,
+	// starting new clients once all of them are busy (open-loop load)
+	loadModelArrivalRate = "arrival-rate"
+	// loadModelConcurrency makes every of -c clients send requests one after another
+	// without rate limit (closed-loop load)
+	loadModelConcurrency = "concurrency"
+)
+
+var loadModel = flag.String("load-model", "", "Set model of load: \"arrival-rate\" sends requests on schedule of -q or -profile regardless of responses, "+
+	"starting new clients once all of them are busy, and \"concurrency\" makes every of -c clients send requests one after another without rate limit. "+
+	"By default requests wait for free client among -c ones, and -c grows during calibration")
+
+// applyLoadModel checks that flags fit -load-model
+func applyLoadModel() {
+	switch *loadModel {
+	case "":
+	case loadModelArrivalRate:
+		if *q == 0 && *profileFlag == "" && *replayTiming != "original" && *burstFlag == "" {
+			usageAndExit("-load-model arrival-rate requires -q, -profile, -burst-pattern or -replay-timing original, since calibration would start clients without limit")
+		}
+		if *mode != "" || *concurrencySweep != "" {
+			usageAndExit("-load-model arrival-rate can't be used with -mode or -concurrency-sweep")
+		}
+	case loadModelConcurrency:
+		if !isFlagSet("c") {
+			usageAndExit("-load-model concurrency requires -c")
+		}
+		if *q > 0 || *profileFlag != "" || *burstFlag != "" || *rampSteps > 0 || *slaFlag != "" || *concurrencySweep != "" ||
+			*bodySizeSweep != "" || *replayTiming == "original" || *mode != "" || *correctedLatency || *loadCalibration != "" {
+			usageAndExit("-load-model concurrency can't be used with -q, -profile, -burst-pattern, -ramp-steps, -sla, sweeps, " +
+				"-replay-timing original, -mode, -corrected-latency or -load-calibration, since rate isn't limited")
+		}
+	default:
+		usageAndExit(fmt.Sprintf("unsupported -load-model %q; supported models are arrival-rate and concurrency", *loadModel))
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// localAddrs are source IPs of connections set by -local-addrs. Is empty if -local-addrs isn't set
+var localAddrs []net.IP
+
+// parseLocalAddrs parses comma-separated list of IPs like "10.0.0.2,10.0.0.3"
+func parseLocalAddrs(s string) ([]net.IP, error) {
+	var result []net.IP
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		ip := net.ParseIP(part)
+		if ip == nil {
+			return nil, fmt.Errorf("cannot parse IP %q", part)
+		}
+		if seen[ip.String()] {
+			return nil, fmt.Errorf("IP %s is set twice", ip)
+		}
+		seen[ip.String()] = true
+		result = append(result, ip)
+	}
+	return result, nil
+}
+
+// applyLocalAddrs parses -local-addrs and checks that connections may be bound to them,
+// so IPs which aren't assigned to interfaces of host fail test before load
+func applyLocalAddrs() {
+	ips, err := parseLocalAddrs(*localAddrsFlag)
+	if err != nil {
+		usageAndExit(fmt.Sprintf("cannot parse -local-addrs: %s", err))
+	}
+	for _, ip := range ips {
+		ln, err := net.Listen("tcp", net.JoinHostPort(ip.String(), "0"))
+		if err != nil {
+			usageAndExit(fmt.Sprintf("cannot bind to %s of -local-addrs: %s", ip, err))
+		}
+		ln.Close()
+	}
+	localAddrs = ips
+}
+
+// formatLocalConns returns number of connections established from every IP of -local-addrs
+// like "10.0.0.2: 120, 10.0.0.3: 118"
+func formatLocalConns(conns map[string]uint64) string {
+	parts := make([]string, len(localAddrs))
+	for i, ip := range localAddrs {
+		parts[i] = fmt.Sprintf("%s: %d", ip, conns[ip.String()])
+	}
+	return strings.Join(parts, ", ")
+}
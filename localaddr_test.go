@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestParseLocalAddrs(t *testing.T) {
+	f := func(s string, expected []net.IP) {
+		t.Helper()
+		got, err := parseLocalAddrs(s)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Unexpected IPs for %q. Got: %v; Expected: %v", s, got, expected)
+		}
+	}
+
+	f("10.0.0.2", []net.IP{net.ParseIP("10.0.0.2")})
+	f("10.0.0.2, 10.0.0.3,::1", []net.IP{net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.3"), net.ParseIP("::1")})
+}
+
+func TestParseLocalAddrsError(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		if _, err := parseLocalAddrs(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+
+	f("")
+	f("10.0.0.2,")
+	f("10.0.0.2:80")
+	f("host")
+	f("10.0.0.2,10.0.0.2")
+}
+
+func TestFormatLocalConns(t *testing.T) {
+	defer func(ips []net.IP) { localAddrs = ips }(localAddrs)
+	localAddrs = []net.IP{net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.3")}
+	expected := "10.0.0.2: 120, 10.0.0.3: 0"
+	if got := formatLocalConns(map[string]uint64{"10.0.0.2": 120}); got != expected {
+		t.Errorf("Unexpected output. Got: %q; Expected: %q", got, expected)
+	}
+}
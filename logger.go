@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+var (
+	logLevel = flag.String("log-level", "warn", "Set level of log messages written to stderr: debug, info, warn or error. "+
+		"Info messages contain phase transitions and calibration decisions, and debug ones contain samples. Summaries are printed to stdout regardless of level")
+	logFormat = flag.String("log-format", "text", "Set format of log messages: text or json, so they could be collected by log aggregators")
+)
+
+// applyLogging configures default slog logger by -log-level and -log-format.
+// Messages of log package are logged as errors, so fatal errors aren't filtered out
+func applyLogging() {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		usageAndExit(fmt.Sprintf("unsupported -log-level %q; supported levels are debug, info, warn and error", *logLevel))
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch *logFormat {
+	case "text":
+		h = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		usageAndExit(fmt.Sprintf("unsupported -log-format %q; supported formats are text and json", *logFormat))
+	}
+	slog.SetDefault(slog.New(h))
+	slog.SetLogLoggerLevel(slog.LevelError)
+}
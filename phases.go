@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"text/tabwriter"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// samplePhase is a name of phase of samples taken by printState
+var samplePhase string
+
+// phaseName returns name of stage point to display in comparison table
+func phaseName(name string) string {
+	switch {
+	case name == "burst":
+		return report.PhaseBurst
+	case name == "adjustment":
+		return report.PhaseCalibrate
+	case name != "load":
+		// level of sweep
+		return name
+	case *rampSteps > 0 && *rampMode != "constant":
+		return "ramp"
+	case pattern != nil:
+		return "burst pattern"
+	case profile != nil:
+		return profile.String()
+	default:
+		return "steady"
+	}
+}
+
+// printPhaseComparison prints results of every finished phase in a single table
+func printPhaseComparison() {
+	if !*summaryComparisonTable || len(stagePoints) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out, "------ Phases ------")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Phase\tRps\tp50\tp99\tErrors\tConnections")
+	for _, p := range stagePoints {
+		p50, p99 := "insufficient data", "insufficient data"
+		if p.requests >= *minSamples {
+			p50, p99 = formatLatency(p.p50), formatLatency(p.p99)
+		}
+		fmt.Fprintf(w, "%s\t%.2f\t%s\t%s\t%.2f %%\t%d\n", phaseName(p.name), p.rps, p50, p99, p.errorRate, p.connections)
+	}
+	w.Flush()
+	fmt.Fprintln(out)
+}
+
+// phases returns summaries of every finished phase for report
+func phases() []report.Phase {
+	result := make([]report.Phase, 0, len(stagePoints))
+	for _, p := range stagePoints {
+		ph := report.Phase{
+			Name:        phaseName(p.name),
+			Requests:    p.requests,
+			Rps:         p.rps,
+			ErrorRate:   p.errorRate,
+			Connections: p.connections,
+		}
+		// NaN quantiles of phase without requests can't be marshaled
+		if p.requests >= *minSamples && !math.IsNaN(p.p50) && !math.IsNaN(p.p99) {
+			ph.P50, ph.P99 = p.p50, p.p99
+		}
+		result = append(result, ph)
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/report"
+	"github.com/valyala/fasthttp"
+)
+
+// probeResult is a result of single request to probed endpoint
+type probeResult struct {
+	url         string
+	statusCode  int
+	latency     time.Duration
+	contentType string
+	err         error
+}
+
+// verdict returns "ERROR" if request failed or wasn't successful,
+// "SLOW" if its latency exceeds slow and "OK" otherwise
+func (pr probeResult) verdict(slow time.Duration) string {
+	switch {
+	case pr.err != nil || !isExpectedStatus(pr.statusCode):
+		return "ERROR"
+	case slow > 0 && pr.latency > slow:
+		return "SLOW"
+	default:
+		return "OK"
+	}
+}
+
+// readProbePaths reads paths to probe from file, one per line.
+// Empty lines and lines starting with "#" are skipped
+func readProbePaths(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open probe file: %s", err)
+	}
+	defer f.Close()
+
+	var paths []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read probe file %q: %s", path, err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("probe file %q contains no paths", path)
+	}
+	return paths, nil
+}
+
+// probeURL joins base url and path. Absolute urls are returned as is
+func probeURL(base, path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// probeEndpoints sends req once to every path and prints status code,
+// latency and content-type of responses. Endpoints which respond
+// with errors or slower than -probe-slow are flagged
+func probeEndpoints(paths []string) {
+	hc := &fasthttp.Client{DisablePathNormalizing: true, TLSConfig: tlsConfig}
+	results := make([]probeResult, len(paths))
+	for i, path := range paths {
+		pr := &results[i]
+		pr.url = probeURL(target, path)
+
+		pReq := fasthttp.AcquireRequest()
+		req.CopyTo(pReq)
+		pReq.SetRequestURI(encodeURL(pr.url))
+		resp := fasthttp.AcquireResponse()
+		start := time.Now()
+		pr.err = hc.DoTimeout(pReq, resp, *requestTimeout)
+		pr.latency = time.Since(start)
+		if pr.err == nil {
+			pr.statusCode = resp.StatusCode()
+			pr.contentType = string(resp.Header.ContentType())
+		}
+		fasthttp.ReleaseRequest(pReq)
+		fasthttp.ReleaseResponse(resp)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "URL\tSTATUS\tLATENCY\tCONTENT-TYPE\tVERDICT\n")
+	var flagged int
+	for _, pr := range results {
+		verdict := pr.verdict(*probeSlow)
+		if verdict != "OK" {
+			flagged++
+		}
+		status, contentType := fmt.Sprintf("%d", pr.statusCode), pr.contentType
+		if pr.err != nil {
+			status, contentType = "-", pr.err.Error()
+		}
+		unit := *latencyUnit
+		if unit == "auto" {
+			unit = report.AutoLatencyUnit(pr.latency.Seconds())
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", pr.url, status, report.FormatLatency(pr.latency.Seconds(), unit), contentType, verdict)
+	}
+	tw.Flush()
+	fmt.Printf("Probed %d endpoints; flagged as failed or slow: %d\n", len(results), flagged)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestProbeURL(t *testing.T) {
+	f := func(base, path, expected string) {
+		t.Helper()
+		if got := probeURL(base, path); got != expected {
+			t.Errorf("Unexpected url for %q and %q. Got: %q; Expected: %q", base, path, got, expected)
+		}
+	}
+
+	f("http://localhost:8080", "/api/users", "http://localhost:8080/api/users")
+	f("http://localhost:8080/", "api/users?id=1", "http://localhost:8080/api/users?id=1")
+	f("http://localhost:8080/v1", "/health", "http://localhost:8080/v1/health")
+	f("http://localhost:8080", "https://example.com/ping", "https://example.com/ping")
+}
+
+func TestProbeVerdict(t *testing.T) {
+	f := func(pr probeResult, slow time.Duration, expected string) {
+		t.Helper()
+		if got := pr.verdict(slow); got != expected {
+			t.Errorf("Unexpected verdict for %+v. Got: %q; Expected: %q", pr, got, expected)
+		}
+	}
+
+	f(probeResult{statusCode: 200, latency: time.Millisecond}, time.Second, "OK")
+	f(probeResult{statusCode: 200, latency: 2 * time.Second}, time.Second, "SLOW")
+	f(probeResult{statusCode: 200, latency: 2 * time.Second}, 0, "OK")
+	f(probeResult{statusCode: 404, latency: time.Millisecond}, time.Second, "ERROR")
+	f(probeResult{err: fmt.Errorf("timeout"), latency: 2 * time.Second}, time.Second, "ERROR")
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// profileTickPeriod is a period of updating rate limit and number of clients by load profile.
+// Every update of rate limit resets throttle, so it isn't updated more often
+const profileTickPeriod = time.Second
+
+// loadProfile defines how rate limit of load phase changes over time
+type loadProfile interface {
+	// qps returns rate limit at elapsed time since start of load phase
+	qps(elapsed time.Duration) float64
+
+	// peak returns max rate limit of profile
+	peak() float64
+
+	String() string
+}
+
+// constantProfile keeps the same rate during whole load phase
+type constantProfile struct {
+	rate float64
+}
+
+func (p constantProfile) qps(time.Duration) float64 { return p.rate }
+func (p constantProfile) peak() float64             { return p.rate }
+func (p constantProfile) String() string            { return fmt.Sprintf("constant:%g", p.rate) }
+
+// rampProfile grows or lowers rate linearly from one rate to another over duration
+type rampProfile struct {
+	from, to float64
+	duration time.Duration
+}
+
+func (p rampProfile) qps(elapsed time.Duration) float64 {
+	if elapsed >= p.duration {
+		return p.to
+	}
+	return p.from + (p.to-p.from)*elapsed.Seconds()/p.duration.Seconds()
+}
+func (p rampProfile) peak() float64  { return math.Max(p.from, p.to) }
+func (p rampProfile) String() string { return fmt.Sprintf("ramp:%g-%g", p.from, p.to) }
+
+// stepProfile changes rate by steps of equal share of duration
+type stepProfile struct {
+	rates    []float64
+	duration time.Duration
+}
+
+func (p stepProfile) qps(elapsed time.Duration) float64 {
+	i := int(elapsed * time.Duration(len(p.rates)) / p.duration)
+	if i >= len(p.rates) {
+		i = len(p.rates) - 1
+	}
+	return p.rates[i]
+}
+
+func (p stepProfile) peak() float64 {
+	var max float64
+	for _, r := range p.rates {
+		max = math.Max(max, r)
+	}
+	return max
+}
+
+func (p stepProfile) String() string {
+	rates := make([]string, len(p.rates))
+	for i, r := range p.rates {
+		rates[i] = strconv.FormatFloat(r, 'g', -1, 64)
+	}
+	return "step:" + strings.Join(rates, ",")
+}
+
+// spikeProfile keeps base rate and raises it to peak rate for spike duration at the end of every period,
+// so every spike follows base load
+type spikeProfile struct {
+	base, top     float64
+	spike, period time.Duration
+}
+
+func (p spikeProfile) qps(elapsed time.Duration) float64 {
+	if elapsed%p.period >= p.period-p.spike {
+		return p.top
+	}
+	return p.base
+}
+func (p spikeProfile) peak() float64 { return math.Max(p.base, p.top) }
+func (p spikeProfile) String() string {
+	return fmt.Sprintf("spike:%g-%g/%s/%s", p.base, p.top, p.spike, p.period)
+}
+
+// sineProfile oscillates rate between min and max with period, starting at min
+type sineProfile struct {
+	min, max float64
+	period   time.Duration
+}
+
+func (p sineProfile) qps(elapsed time.Duration) float64 {
+	phase := 2 * math.Pi * elapsed.Seconds() / p.period.Seconds()
+	return p.min + (p.max-p.min)*(1-math.Cos(phase))/2
+}
+func (p sineProfile) peak() float64  { return p.max }
+func (p sineProfile) String() string { return fmt.Sprintf("sine:%g-%g/%s", p.min, p.max, p.period) }
+
+const profileFormats = `"constant:500", "ramp:100-1000", "step:100,500,1000", "spike:100-1000/10s/1m" or "sine:100-1000/2m"`
+
+// parseProfile parses load profile like "ramp:100-1000".
+// Ramp and steps of profile are spread over load phase of duration d
+func parseProfile(s string, d time.Duration) (loadProfile, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("cannot parse load profile %q: must be one of %s", s, profileFormats)
+	}
+	kind, args := parts[0], parts[1]
+	var p loadProfile
+	var err error
+	switch kind {
+	case "constant":
+		var rate float64
+		rate, err = parseProfileRate(args)
+		p = constantProfile{rate: rate}
+	case "ramp":
+		var from, to float64
+		from, to, err = parseProfileRange(args)
+		p = rampProfile{from: from, to: to, duration: d}
+	case "step":
+		sp := stepProfile{duration: d}
+		for _, arg := range strings.Split(args, ",") {
+			var rate float64
+			if rate, err = parseProfileRate(arg); err != nil {
+				break
+			}
+			sp.rates = append(sp.rates, rate)
+		}
+		p = sp
+	case "spike":
+		fields := strings.Split(args, "/")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("cannot parse spike profile %q: must be in format \"spike:100-1000/10s/1m\"", s)
+		}
+		sp := spikeProfile{}
+		if sp.base, sp.top, err = parseProfileRange(fields[0]); err == nil {
+			if sp.spike, sp.period, err = parseProfilePeriods(fields[1], fields[2]); err == nil && sp.spike >= sp.period {
+				err = fmt.Errorf("spike duration %s must be shorter than period %s", sp.spike, sp.period)
+			}
+		}
+		p = sp
+	case "sine":
+		fields := strings.Split(args, "/")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("cannot parse sine profile %q: must be in format \"sine:100-1000/2m\"", s)
+		}
+		sp := sineProfile{}
+		if sp.min, sp.max, err = parseProfileRange(fields[0]); err == nil {
+			if sp.min > sp.max {
+				err = fmt.Errorf("min rate %g exceeds max rate %g", sp.min, sp.max)
+			} else if sp.period, err = time.ParseDuration(fields[1]); err == nil && sp.period <= 0 {
+				err = fmt.Errorf("period must be positive")
+			}
+		}
+		p = sp
+	default:
+		return nil, fmt.Errorf("unsupported load profile %q: must be one of %s", s, profileFormats)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse load profile %q: %s", s, err)
+	}
+	if p.peak() == 0 {
+		return nil, fmt.Errorf("load profile %q must have positive rate", s)
+	}
+	return p, nil
+}
+
+func parseProfileRate(s string) (float64, error) {
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse rate %q", s)
+	}
+	if rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return 0, fmt.Errorf("rate %q must be non-negative", s)
+	}
+	return rate, nil
+}
+
+// parseProfileRange parses pair of rates like "100-1000"
+func parseProfileRange(s string) (float64, float64, error) {
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("cannot parse rates %q: must be in format \"100-1000\"", s)
+	}
+	from, err := parseProfileRate(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	to, err := parseProfileRate(parts[1])
+	return from, to, err
+}
+
+func parseProfilePeriods(spike, period string) (time.Duration, time.Duration, error) {
+	s, err := time.ParseDuration(spike)
+	if err != nil {
+		return 0, 0, err
+	}
+	p, err := time.ParseDuration(period)
+	if err != nil {
+		return 0, 0, err
+	}
+	if s <= 0 || p <= 0 {
+		return 0, 0, fmt.Errorf("spike duration and period must be positive")
+	}
+	return s, p, nil
+}
+
+// profileWorkers returns number of clients needed for rate of profile,
+// so c clients are reached at peak rate
+func profileWorkers(c int, rate, peak float64) int {
+	n := int(math.Ceil(float64(c) * rate / peak))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// updateProfile sets rate limit of profile at elapsed time unless it equals rate set previously,
+// and resizes pool of clients to number needed for rate, so surplus clients are stopped once rate decreases.
+// Returns rate of profile
+func updateProfile(cfg *loadConfig, elapsed time.Duration, prev float64) float64 {
+	rate := profile.qps(elapsed)
+	if rate != prev {
+		setLimit(rate)
+	}
+	if n := profileWorkers(cfg.c, rate, profile.peak()) - client.Amount(); n > 0 {
+		client.RunWorkers(n)
+	} else if n < 0 {
+		client.StopWorkers(-n)
+	}
+	return rate
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseProfile(t *testing.T) {
+	f := func(s string, expOK bool, expPeak float64) {
+		t.Helper()
+		p, err := parseProfile(s, time.Minute)
+		if (err == nil) != expOK {
+			t.Fatalf("unexpected error for %q: %v", s, err)
+		}
+		if err != nil {
+			return
+		}
+		if p.peak() != expPeak {
+			t.Fatalf("expected peak %f of %q; got %f", expPeak, s, p.peak())
+		}
+		if p.String() != s {
+			t.Fatalf("expected %q; got %q", s, p.String())
+		}
+	}
+	f("constant:500", true, 500)
+	f("ramp:100-1000", true, 1000)
+	f("ramp:1000-100", true, 1000)
+	f("step:100,500,200", true, 500)
+	f("spike:100-1000/10s/1m0s", true, 1000)
+	f("sine:100-1000/2m0s", true, 1000)
+
+	f("", false, 0)
+	f("constant", false, 0)
+	f("constant:-1", false, 0)
+	f("constant:0", false, 0)
+	f("ramp:100", false, 0)
+	f("step:100,,200", false, 0)
+	f("spike:100-1000/10s", false, 0)
+	f("spike:100-1000/1m/10s", false, 0)
+	f("sine:1000-100/2m", false, 0)
+	f("sine:100-1000/0s", false, 0)
+	f("square:100-1000/2m", false, 0)
+}
+
+func TestProfileQPS(t *testing.T) {
+	f := func(s string, elapsed time.Duration, expQPS float64) {
+		t.Helper()
+		p, err := parseProfile(s, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if qps := p.qps(elapsed); math.Abs(qps-expQPS) > 1e-6 {
+			t.Fatalf("expected qps %f of %q at %s; got %f", expQPS, s, elapsed, qps)
+		}
+	}
+	f("constant:500", 30*time.Second, 500)
+
+	f("ramp:100-1000", 0, 100)
+	f("ramp:100-1000", 30*time.Second, 550)
+	f("ramp:100-1000", 2*time.Minute, 1000)
+	f("ramp:1000-100", 30*time.Second, 550)
+
+	f("step:100,500,200", 0, 100)
+	f("step:100,500,200", 20*time.Second, 500)
+	f("step:100,500,200", 59*time.Second, 200)
+	f("step:100,500,200", 2*time.Minute, 200)
+
+	f("spike:100-1000/10s/30s", 0, 100)
+	f("spike:100-1000/10s/30s", 19*time.Second, 100)
+	f("spike:100-1000/10s/30s", 20*time.Second, 1000)
+	f("spike:100-1000/10s/30s", 31*time.Second, 100)
+
+	f("sine:100-1000/20s", 0, 100)
+	f("sine:100-1000/20s", 5*time.Second, 550)
+	f("sine:100-1000/20s", 10*time.Second, 1000)
+	f("sine:100-1000/20s", 20*time.Second, 100)
+}
+
+func TestProfileWorkers(t *testing.T) {
+	f := func(c int, rate, peak float64, expWorkers int) {
+		t.Helper()
+		if n := profileWorkers(c, rate, peak); n != expWorkers {
+			t.Fatalf("expected %d workers for rate %f of peak %f; got %d", expWorkers, rate, peak, n)
+		}
+	}
+	f(10, 1000, 1000, 10)
+	f(10, 500, 1000, 5)
+	f(10, 501, 1000, 6)
+	f(10, 0, 1000, 1)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// promShutdownTimeout is a max time of waiting for in-flight scrapes on shutdown
+const promShutdownTimeout = 5 * time.Second
+
+// promServer serves metrics at -promListen. Is nil if -promListen isn't set
+var promServer *http.Server
+
+// qpsLimitGauge, workersGauge and queueDepthGauge expose state of generator, which isn't tracked by metrics of client.
+// They are updated by every sample
+var (
+	qpsLimitGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "qps_limit",
+		Help: "Current rate limit of requests per second",
+	})
+	workersGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "workers",
+		Help: "Current number of clients sending requests",
+	})
+	queueDepthGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "job_queue_depth",
+		Help: "Current number of jobs waiting for free client",
+	})
+)
+
+// startPromListener serves registered metrics in Prometheus text format at /metrics of addr like ":9090",
+// so they could be scraped while test is running
+func startPromListener(addr string) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Cannot listen -promListen %s: %s", addr, err)
+	}
+	prometheus.MustRegister(qpsLimitGauge, workersGauge, queueDepthGauge)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	promServer = &http.Server{Handler: mux}
+	go func(s *http.Server) {
+		if err := s.Serve(ln); err != nil && err != http.ErrServerClosed {
+			slog.Error("Error while serving metrics", "addr", addr, "error", err)
+		}
+	}(promServer)
+	fmt.Fprintf(out, "Serving metrics at http://%s/metrics\n", ln.Addr())
+}
+
+// stopPromListener shuts metrics server down and unregisters gauges of generator,
+// so listener could be started again. Is no-op if -promListen isn't set
+func stopPromListener() {
+	if promServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), promShutdownTimeout)
+	defer cancel()
+	if err := promServer.Shutdown(ctx); err != nil {
+		slog.Error("Error while shutting down metrics server", "error", err)
+	}
+	prometheus.Unregister(qpsLimitGauge)
+	prometheus.Unregister(workersGauge)
+	prometheus.Unregister(queueDepthGauge)
+	promServer = nil
+}
+
+// updatePromGauges sets gauges of generator to current rate limit, number of workers and queued jobs
+func updatePromGauges() {
+	qpsLimitGauge.Set(qpsLimit())
+	workersGauge.Set(float64(client.Amount()))
+	queueDepthGauge.Set(float64(client.Overflow()))
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/ratelimiter"
+	"github.com/valyala/fasthttp"
+)
+
+// startTestPromListener starts -promListen at random local port and returns url of its metrics.
+// Listener is stopped once test finishes
+func startTestPromListener(t *testing.T) string {
+	t.Helper()
+	defer func(w io.Writer) { out = w }(out)
+	var buf bytes.Buffer
+	out = &buf
+	startPromListener("127.0.0.1:0")
+	t.Cleanup(stopPromListener)
+	s := strings.TrimSpace(buf.String())
+	if !strings.HasPrefix(s, "Serving metrics at ") {
+		t.Fatalf("Unexpected output of -promListen: %q", s)
+	}
+	return strings.TrimPrefix(s, "Serving metrics at ")
+}
+
+// scrape returns metrics served at url
+func scrape(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Cannot scrape %s: %s", url, err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Cannot read metrics of %s: %s", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected status code of %s. Got: %d; Expected: %d", url, resp.StatusCode, http.StatusOK)
+	}
+	return string(b)
+}
+
+func TestPromListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	defer ln.Close()
+	go fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {})
+
+	cr := new(fasthttp.Request)
+	cr.SetRequestURI("http://" + ln.Addr().String() + "/")
+	c := fastclient.New(cr, time.Second, fasthttp.StatusOK)
+	defer c.Flush()
+	c.RunWorkers(1)
+	for i := 0; i < 3; i++ {
+		c.Jobsch <- time.Now()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for c.RequestSum() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Requests weren't done in time. Got: %d; Expected: 3", c.RequestSum())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	url := startTestPromListener(t)
+	// metrics of client are scraped while test is running
+	if m := scrape(t, url); !strings.Contains(m, "\nrequest_sum 3\n") {
+		t.Errorf("Metrics don't contain request_sum of 3:\n%s", m)
+	}
+
+	stopPromListener()
+	if _, err := http.Get(url); err == nil {
+		t.Errorf("Metrics are served after listener is stopped")
+	}
+	// listener could be started again, since gauges of generator are unregistered
+	scrape(t, startTestPromListener(t))
+}
+
+func TestPromGauges(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	defer ln.Close()
+	var block atomic.Bool
+	release := make(chan struct{})
+	go fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {
+		if block.Load() {
+			<-release
+		}
+	})
+
+	defer func(prev *fastclient.Client) { client = prev }(client)
+	defer func() { throttle = ratelimiter.NewLimiter() }()
+	throttle = ratelimiter.NewLimiter()
+	defer throttle.Stop()
+	throttle.SetLimit(50)
+	cr := new(fasthttp.Request)
+	cr.SetRequestURI("http://" + ln.Addr().String() + "/")
+	client = fastclient.New(cr, 5*time.Second, fasthttp.StatusOK)
+	defer client.Flush()
+	// blocked requests are released before client is flushed
+	defer close(release)
+	client.RunWorkers(1)
+	client.Jobsch <- time.Now()
+	waitState(t, func() bool { return client.RequestSum() == 1 })
+
+	url := startTestPromListener(t)
+	block.Store(true)
+	client.RunWorkers(2)
+	for i := 0; i < 4; i++ {
+		client.Jobsch <- time.Now()
+	}
+	// every worker is busy, so the last job is queued
+	waitState(t, func() bool { return client.InFlight() == 3 && client.Overflow() == 1 })
+	updatePromGauges()
+	m := scrape(t, url)
+	for _, s := range []string{"\nqps_limit 50\n", "\nworkers 3\n", "\njob_queue_depth 1\n",
+		// latency is exposed by buckets, so latency of several loaders could be aggregated
+		"\nrequest_duration_seconds_count 1\n", `request_duration_seconds_bucket{le="+Inf"} 1`} {
+		if !strings.Contains(m, s) {
+			t.Errorf("Metrics don't contain %q:\n%s", s, m)
+		}
+	}
+}
+
+// waitState waits until ok returns true. Test fails if it doesn't in 5s
+func waitState(t *testing.T, ok func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !ok() {
+		if time.Now().After(deadline) {
+			t.Fatalf("State wasn't reached in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hagen1778/fasthttploader/pushgateway"
+)
+
+var labelNameRe = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+// labelMap is a set of labels like "instance=gen-1" set by repeated flag
+type labelMap map[string]string
+
+func (m labelMap) String() string {
+	var labels []string
+	for k, v := range m {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
+
+func (m labelMap) Set(s string) error {
+	n := strings.Index(s, "=")
+	if n <= 0 || n == len(s)-1 {
+		return fmt.Errorf("label %q must be in format \"name=value\" like \"instance=gen-1\"", s)
+	}
+	name := s[:n]
+	if !labelNameRe.MatchString(name) || name == "job" {
+		return fmt.Errorf("invalid name of label %q; job is set by -jobName", name)
+	}
+	if _, ok := m[name]; ok {
+		return fmt.Errorf("label %q is set twice", name)
+	}
+	m[name] = s[n+1:]
+	return nil
+}
+
+var pushgatewayLabels = labelMap{}
+
+var pushgatewayDelete = flag.Bool("pushgateway-delete", true, "Delete group of metrics from -pushgateway once test is finished, "+
+	"so Prometheus doesn't scrape stale values of finished test. If false, final values are kept")
+
+func init() {
+	flag.Var(pushgatewayLabels, "pushgateway-label", "Add grouping label like \"instance=gen-1\", \"test_id=42\" or \"git_sha=1a2b3c\" "+
+		"to metrics pushed to -pushgateway and to pushgateway sink of -export. Is set multiple times")
+}
+
+// applyPushgateway validates flags of Pushgateway and configures pushing to it
+func applyPushgateway() {
+	if *pushgatewayAddr != "" && *pushInterval <= 0 {
+		usageAndExit("-pushInterval must be positive")
+	}
+	pushgateway.Init(*pushgatewayAddr, *jobName, pushgatewayLabels)
+}
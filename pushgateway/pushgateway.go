@@ -0,0 +1,95 @@
+package pushgateway
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+const (
+	// pushTimeout is a max duration of single push
+	pushTimeout = 5 * time.Second
+
+	// maxBackoff is a max period, during which pushes are skipped after failures
+	maxBackoff = time.Minute
+)
+
+var (
+	mu     sync.Mutex
+	pusher *push.Pusher
+
+	// failures is a number of consecutive failed pushes
+	failures int
+	// nextPush is a time before which pushes are skipped because of failures
+	nextPush time.Time
+)
+
+// Init configures pushing of all registered metrics to Pushgateway at addr like "http://localhost:9091"
+// under group of given job and labels like "instance". Pushing is disabled if addr is empty
+func Init(addr, job string, labels map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	failures, nextPush = 0, time.Time{}
+	if addr == "" {
+		pusher = nil
+		return
+	}
+	pusher = push.New(addr, job).
+		Gatherer(prometheus.DefaultGatherer).
+		Client(&http.Client{Timeout: pushTimeout})
+	for k, v := range labels {
+		pusher = pusher.Grouping(k, v)
+	}
+}
+
+// Delete deletes group of metrics from Pushgateway and disables pushing,
+// so Prometheus doesn't scrape stale values once test is finished.
+// Waits for push in progress. Is no-op if pushing is disabled
+func Delete() {
+	mu.Lock()
+	defer mu.Unlock()
+	if pusher == nil {
+		return
+	}
+	if err := pusher.Delete(); err != nil {
+		log.Printf("Error while deleting metrics from Pushgateway: %s", err)
+	}
+	pusher = nil
+}
+
+// Push replaces metrics of job at Pushgateway by current values of registered metrics.
+// Is no-op if pushing is disabled. The first of consecutive failures is logged, and following
+// pushes are skipped for exponentially growing period, so unavailable Pushgateway doesn't spam output.
+// Push is skipped if previous one is still in progress, so it could be called without waiting for it
+func Push() {
+	if !mu.TryLock() {
+		return
+	}
+	defer mu.Unlock()
+	if pusher == nil || time.Now().Before(nextPush) {
+		return
+	}
+	if err := pusher.Push(); err != nil {
+		if failures == 0 {
+			log.Printf("Error while pushing metrics to Pushgateway: %s; further errors are suppressed until push succeeds", err)
+		}
+		failures++
+		backoff := time.Second
+		for i := 1; i < failures && backoff < maxBackoff; i++ {
+			backoff *= 2
+		}
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		nextPush = time.Now().Add(backoff)
+		return
+	}
+	if failures > 0 {
+		log.Printf("Pushing metrics to Pushgateway recovered after %d failures", failures)
+	}
+	failures, nextPush = 0, time.Time{}
+}
@@ -0,0 +1,64 @@
+package pushgateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPush(t *testing.T) {
+	var pushes, status int32 = 0, http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&pushes, 1)
+		if r.Method != http.MethodPut || r.URL.Path != "/metrics/job/test" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer srv.Close()
+
+	Init("", "test", nil)
+	Push()
+	if n := atomic.LoadInt32(&pushes); n != 0 {
+		t.Fatalf("Push must be no-op without address; got %d pushes", n)
+	}
+
+	Init(srv.URL, "test", nil)
+	Push()
+	if n := atomic.LoadInt32(&pushes); n != 1 {
+		t.Fatalf("Unexpected number of pushes. Got: %d; Expected: 1", n)
+	}
+
+	// pushes after failure are skipped during backoff
+	atomic.StoreInt32(&status, http.StatusInternalServerError)
+	Push()
+	Push()
+	if n := atomic.LoadInt32(&pushes); n != 2 {
+		t.Fatalf("Unexpected number of pushes after failure. Got: %d; Expected: 2", n)
+	}
+	if failures != 1 || nextPush.IsZero() {
+		t.Errorf("Unexpected backoff state: %d failures, next push at %s", failures, nextPush)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	Init(srv.URL, "test", map[string]string{"instance": "gen-1"})
+	Push()
+	Delete()
+	// pushing is disabled after delete
+	Push()
+	Delete()
+	expected := []string{"PUT /metrics/job/test/instance/gen-1", "DELETE /metrics/job/test/instance/gen-1"}
+	if strings.Join(requests, ";") != strings.Join(expected, ";") {
+		t.Errorf("Unexpected requests. Got: %q; Expected: %q", requests, expected)
+	}
+}
@@ -21,12 +21,16 @@ type Limiter struct {
 
 const bufferSize = 1e6
 
+// TickPeriod is a period of generating messages into QPS channel,
+// so messages are delivered with jitter up to TickPeriod
+const TickPeriod = 5 * time.Millisecond
+
 // NewLimiter inits and returns new Limiter obj
 func NewLimiter() *Limiter {
 	l := &Limiter{
 		ch:     make(chan struct{}, bufferSize),
 		doneCh: make(chan struct{}),
-		ticker: time.NewTicker(5 * time.Millisecond),
+		ticker: time.NewTicker(TickPeriod),
 	}
 	go l.start()
 
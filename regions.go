@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// regions are simulated locations of clients set by -regions. Is empty if -regions isn't set
+var regions []fastclient.Region
+
+// readRegions reads regions from JSON file like
+// [{"name": "US", "share": 20, "rtt": "30ms"}, {"name": "EU", "share": 80, "rtt": "90ms"}].
+// Shares are percents of connections and must sum up to 100
+func readRegions(path string) ([]fastclient.Region, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read regions file: %s", err)
+	}
+	var items []struct {
+		Name  string
+		Share float64
+		RTT   string
+	}
+	if err := json.Unmarshal(b, &items); err != nil {
+		return nil, fmt.Errorf("cannot parse regions file %q: %s", path, err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("regions file %q contains no regions", path)
+	}
+	var result []fastclient.Region
+	var total float64
+	names := make(map[string]bool)
+	for _, item := range items {
+		if item.Name == "" {
+			return nil, fmt.Errorf("region in %q has no name", path)
+		}
+		if names[item.Name] {
+			return nil, fmt.Errorf("region %q is set twice", item.Name)
+		}
+		names[item.Name] = true
+		if item.Share <= 0 {
+			return nil, fmt.Errorf("share of region %q must be positive", item.Name)
+		}
+		rtt, err := time.ParseDuration(item.RTT)
+		if err != nil || rtt < 0 {
+			return nil, fmt.Errorf("rtt of region %q must be non-negative duration like \"30ms\"", item.Name)
+		}
+		total += item.Share
+		result = append(result, fastclient.Region{Name: item.Name, Share: item.Share, RTT: rtt})
+	}
+	if math.Abs(total-100) > 0.01 {
+		return nil, fmt.Errorf("shares of regions in %q sum up to %.2f %% instead of 100 %%", path, total)
+	}
+	return result, nil
+}
+
+func applyRegions() {
+	var err error
+	if regions, err = readRegions(*regionsFile); err != nil {
+		usageAndExit(err.Error())
+	}
+	if *grpcMethod != "" || *probeFile != "" {
+		usageAndExit("-regions can't be used with -grpc-method or -probe")
+	}
+}
+
+// latencyByRegion returns latency of responses by simulated region of clients
+func latencyByRegion() []report.RegionLatency {
+	var result []report.RegionLatency
+	for i, rl := range client.LatencyByRegion() {
+		result = append(result, report.RegionLatency{
+			Region:   rl.Region,
+			RTT:      regions[i].RTT.String(),
+			Requests: rl.Requests,
+			P50:      rl.Quantiles[0.5],
+			P90:      rl.Quantiles[0.9],
+			P99:      rl.Quantiles[0.99],
+		})
+	}
+	return result
+}
+
+// printLatencyByRegion prints latency of responses by simulated region of clients
+func printLatencyByRegion() {
+	if len(r.LatencyByRegion) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out, "------ Latency by region ------")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Region\tRTT\tRequests\tp50\tp90\tp99")
+	for _, rl := range r.LatencyByRegion {
+		p50, p90, p99 := "insufficient data", "", ""
+		if rl.Requests >= *minSamples {
+			p50, p90, p99 = formatLatency(rl.P50), formatLatency(rl.P90), formatLatency(rl.P99)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", rl.Region, rl.RTT, rl.Requests, p50, p90, p99)
+	}
+	w.Flush()
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeRegions(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "regions.json")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("cannot write regions file: %s", err)
+	}
+	return path
+}
+
+func TestReadRegions(t *testing.T) {
+	path := writeRegions(t, `[{"name": "US", "share": 20, "rtt": "30ms"}, {"name": "EU", "share": 30, "rtt": "90ms"},
+		{"name": "APAC", "share": 50, "rtt": "200ms"}]`)
+	regions, err := readRegions(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(regions) != 3 || regions[0].Name != "US" || regions[1].Share != 30 || regions[2].RTT != 200*time.Millisecond {
+		t.Errorf("Unexpected regions: %+v", regions)
+	}
+}
+
+func TestReadRegionsError(t *testing.T) {
+	f := func(content string) {
+		t.Helper()
+		if _, err := readRegions(writeRegions(t, content)); err == nil {
+			t.Errorf("Expected error for %s", content)
+		}
+	}
+
+	f(`[]`)
+	f(`{"name": "US"}`)
+	f(`[{"name": "US", "share": 90, "rtt": "30ms"}]`)
+	f(`[{"name": "", "share": 100, "rtt": "30ms"}]`)
+	f(`[{"name": "US", "share": 0, "rtt": "30ms"}, {"name": "EU", "share": 100, "rtt": "30ms"}]`)
+	f(`[{"name": "US", "share": 50, "rtt": "30ms"}, {"name": "US", "share": 50, "rtt": "30ms"}]`)
+	f(`[{"name": "US", "share": 100, "rtt": "30"}]`)
+	f(`[{"name": "US", "share": 100, "rtt": "-30ms"}]`)
+
+	if _, err := readRegions(filepath.Join(os.TempDir(), "missing-regions.json")); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+}
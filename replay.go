@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// replayEntry is a recorded request of -replay or -replay-har
+type replayEntry struct {
+	// offset is a time of request since the first recorded one
+	offset time.Duration
+
+	method string
+	// uri is path and query of request
+	uri     string
+	headers [][2]string
+	body    string
+}
+
+// replayOffsets are offsets of targets created by -replay or -replay-har.
+// Are set only if they are sent with original timing
+var replayOffsets []time.Duration
+
+// replayPeriod is a period, after which recorded requests are repeated with original timing
+var replayPeriod time.Duration
+
+// accessLogLine matches line of common or combined log format like
+// `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 200 2326 "http://ref/" "Mozilla/5.0"`
+var accessLogLine = regexp.MustCompile(`^\S+ \S+ \S+ \[([^\]]+)\] "([^"]*)" \d{3} \S+(?: "([^"]*)" "([^"]*)")?`)
+
+const accessLogTime = "02/Jan/2006:15:04:05 -0700"
+
+// parseAccessLog parses requests of access log in common or combined log format.
+// Referer and User-Agent of combined format are sent as headers. Lines without request,
+// like `"-"` of connections closed before request, are skipped.
+// Time of access log has second precision, so requests of the same second are spread evenly over it
+func parseAccessLog(r io.Reader) ([]replayEntry, error) {
+	var entries []replayEntry
+	var start time.Time
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		m := accessLogLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("cannot parse line %d of access log: must be in common or combined log format", n)
+		}
+		t, err := time.Parse(accessLogTime, m[1])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse time at line %d of access log: %s", n, err)
+		}
+		fields := strings.Fields(m[2])
+		if len(fields) < 2 {
+			continue
+		}
+		if start.IsZero() {
+			start = t
+		}
+		e := replayEntry{offset: t.Sub(start), method: fields[0], uri: fields[1]}
+		if u, err := url.Parse(e.uri); err == nil && u.IsAbs() {
+			// requests to proxies are logged with absolute urls
+			e.uri = u.RequestURI()
+		}
+		if m[3] != "" && m[3] != "-" {
+			e.headers = append(e.headers, [2]string{"Referer", m[3]})
+		}
+		if m[4] != "" && m[4] != "-" {
+			e.headers = append(e.headers, [2]string{"User-Agent", m[4]})
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read access log: %s", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("access log contains no requests")
+	}
+	sortReplay(entries)
+	for i := 0; i < len(entries); {
+		j := i
+		for j < len(entries) && entries[j].offset == entries[i].offset {
+			j++
+		}
+		for k := i; k < j; k++ {
+			entries[k].offset += time.Duration(k-i) * time.Second / time.Duration(j-i)
+		}
+		i = j
+	}
+	return entries, nil
+}
+
+// harFile contains fields of HAR file, which are needed to replay its requests
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			StartedDateTime time.Time `json:"startedDateTime"`
+			Request         struct {
+				Method  string `json:"method"`
+				URL     string `json:"url"`
+				Headers []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"headers"`
+				PostData *struct {
+					Text string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// harSkippedHeaders are headers of HAR requests, which are set by client
+var harSkippedHeaders = map[string]bool{"host": true, "content-length": true, "connection": true}
+
+// parseHAR parses requests of HAR file. Pseudo-headers of HTTP/2 and headers set by client
+// like Host and Content-Length are skipped
+func parseHAR(r io.Reader) ([]replayEntry, error) {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("cannot parse HAR file: %s", err)
+	}
+	if len(har.Log.Entries) == 0 {
+		return nil, fmt.Errorf("HAR file contains no requests")
+	}
+	start := har.Log.Entries[0].StartedDateTime
+	for _, he := range har.Log.Entries {
+		if he.StartedDateTime.Before(start) {
+			start = he.StartedDateTime
+		}
+	}
+	entries := make([]replayEntry, 0, len(har.Log.Entries))
+	for i, he := range har.Log.Entries {
+		u, err := url.Parse(he.Request.URL)
+		if err != nil || he.Request.Method == "" {
+			return nil, fmt.Errorf("request %d of HAR file must have method and valid url", i+1)
+		}
+		e := replayEntry{
+			offset: he.StartedDateTime.Sub(start),
+			method: he.Request.Method,
+			uri:    u.RequestURI(),
+		}
+		for _, h := range he.Request.Headers {
+			if !strings.HasPrefix(h.Name, ":") && !harSkippedHeaders[strings.ToLower(h.Name)] {
+				e.headers = append(e.headers, [2]string{h.Name, h.Value})
+			}
+		}
+		if he.Request.PostData != nil {
+			e.body = he.Request.PostData.Text
+		}
+		entries = append(entries, e)
+	}
+	sortReplay(entries)
+	return entries, nil
+}
+
+// sortReplay sorts entries by offset, keeping recorded order of simultaneous ones
+func sortReplay(entries []replayEntry) {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].offset < entries[j].offset })
+}
+
+// readReplay reads recorded requests of -replay or -replay-har
+func readReplay() ([]replayEntry, error) {
+	path, parse := *replayFile, parseAccessLog
+	if *replayHAR != "" {
+		path, parse = *replayHAR, parseHAR
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open replay file: %s", err)
+	}
+	defer f.Close()
+	entries, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot replay %q: %s", path, err)
+	}
+	return entries, nil
+}
+
+// applyReplay creates target for every recorded request of -replay or -replay-har.
+// Requests are sent to url argument with recorded path, method, headers and body,
+// and are reported by method and path without query
+func applyReplay() {
+	if *replayFile != "" && *replayHAR != "" {
+		usageAndExit("-replay and -replay-har can't be used together")
+	}
+	if len(urls) > 0 || *scenarioFile != "" || *curlFlag != "" || *grpcMethod != "" || *probeFile != "" || *dataFile != "" {
+		usageAndExit("-replay and -replay-har can't be used with -url, -scenario, -curl, -grpc-method, -probe or -data")
+	}
+	if isTemplate(target) || isTemplate(*body) || isTemplate(*headers) {
+		usageAndExit("-replay and -replay-har can't be used with request templates")
+	}
+	if *verifyDNS || *dnsRoundRobin {
+		usageAndExit("-replay and -replay-har can't be used with -verify-dns-distribution or -dns-round-robin")
+	}
+	switch *replayTiming {
+	case "qps":
+	case "original":
+		if isFlagSet("q") || *profileFlag != "" || *burstFlag != "" || *rampSteps > 0 || *slaFlag != "" ||
+			*concurrencySweep != "" || *bodySizeSweep != "" || *totalRequests > 0 || *mode != "" {
+			usageAndExit("-replay-timing original can't be used with -q, -profile, -burst-pattern, -ramp-steps, -sla, sweeps, -n or -mode")
+		}
+	default:
+		usageAndExit(fmt.Sprintf("unsupported -replay-timing %q; supported timings are qps and original", *replayTiming))
+	}
+	entries, err := readReplay()
+	if err != nil {
+		usageAndExit(err.Error())
+	}
+	for _, e := range entries {
+		r := new(fasthttp.Request)
+		req.CopyTo(r)
+		r.SetRequestURI(encodeURL(probeURL(target, e.uri)))
+		r.Header.SetMethod(e.method)
+		for _, h := range e.headers {
+			r.Header.Set(h[0], h[1])
+		}
+		r.SetBodyString(e.body)
+		targets = append(targets, r)
+		targetNames = append(targetNames, e.method+" "+string(r.URI().Path()))
+	}
+	if *replayTiming == "original" {
+		for _, e := range entries {
+			replayOffsets = append(replayOffsets, e.offset)
+		}
+		replayPeriod = replayCycle(replayOffsets)
+	}
+}
+
+// replayCycle returns period of repeating recorded requests with original timing,
+// which is the last offset followed by average interval between requests
+func replayCycle(offsets []time.Duration) time.Duration {
+	last := offsets[len(offsets)-1]
+	if last <= 0 {
+		return time.Second
+	}
+	if len(offsets) == 1 {
+		return last
+	}
+	return last + last/time.Duration(len(offsets)-1)
+}
+
+// replayRate returns average rate of recorded requests with original timing
+func replayRate() float64 {
+	return float64(len(replayOffsets)) / replayPeriod.Seconds()
+}
+
+// replayAt returns offset of i-th request since start of load with original timing
+func replayAt(i int) time.Duration {
+	n := len(replayOffsets)
+	return time.Duration(i/n)*replayPeriod + replayOffsets[i%n]
+}
+
+// replayLoad queues jobs at recorded offsets until ctx is canceled.
+// Rate limit of throttle is only shown in report, while its tokens aren't used
+func replayLoad(ctx context.Context) {
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for i := 0; ; i++ {
+		at := start.Add(replayAt(i))
+		if wait := time.Until(at); wait > 0 {
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+		t := time.Now()
+		if *correctedLatency {
+			t = at
+		}
+		if !client.Enqueue(ctx, t) {
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAccessLog(t *testing.T) {
+	log := `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /a?x=1 HTTP/1.0" 200 2326
+127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "POST /b HTTP/1.1" 201 10 "http://ref/" "curl/8.0"
+127.0.0.1 - - [10/Oct/2000:13:55:37 -0700] "-" 400 0 "-" "-"
+
+127.0.0.1 - - [10/Oct/2000:13:55:38 -0700] "GET http://proxy.local/c HTTP/1.1" 200 5 "-" "-"
+`
+	entries, err := parseAccessLog(strings.NewReader(log))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries; got %d", len(entries))
+	}
+	f := func(i int, offset time.Duration, method, uri string, headers int) {
+		t.Helper()
+		e := entries[i]
+		if e.offset != offset || e.method != method || e.uri != uri || len(e.headers) != headers {
+			t.Fatalf("unexpected entry %d: %+v", i, e)
+		}
+	}
+	// requests of the same second are spread over it
+	f(0, 0, "GET", "/a?x=1", 0)
+	f(1, 500*time.Millisecond, "POST", "/b", 2)
+	f(2, 2*time.Second, "GET", "/c", 0)
+
+	for _, s := range []string{"", "not a log line", `127.0.0.1 - - [yesterday] "GET / HTTP/1.1" 200 1`} {
+		if _, err := parseAccessLog(strings.NewReader(s)); err == nil {
+			t.Fatalf("expected error for %q", s)
+		}
+	}
+}
+
+func TestParseHAR(t *testing.T) {
+	har := `{"log": {"entries": [
+		{"startedDateTime": "2024-01-01T00:00:01.500Z", "request": {"method": "POST", "url": "https://example.com/cart?id=1",
+			"headers": [{"name": ":authority", "value": "example.com"}, {"name": "Content-Type", "value": "application/json"},
+				{"name": "Content-Length", "value": "2"}], "postData": {"text": "{}"}}},
+		{"startedDateTime": "2024-01-01T00:00:01Z", "request": {"method": "GET", "url": "https://example.com/", "headers": []}}
+	]}}`
+	entries, err := parseHAR(strings.NewReader(har))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(entries) != 2 || entries[0].uri != "/" || entries[0].offset != 0 {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	e := entries[1]
+	if e.offset != 500*time.Millisecond || e.method != "POST" || e.uri != "/cart?id=1" || e.body != "{}" ||
+		len(e.headers) != 1 || e.headers[0] != [2]string{"Content-Type", "application/json"} {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+
+	for _, s := range []string{"", `{"log": {"entries": []}}`, `{"log": {"entries": [{"request": {"url": "/"}}]}}`} {
+		if _, err := parseHAR(strings.NewReader(s)); err == nil {
+			t.Fatalf("expected error for %q", s)
+		}
+	}
+}
+
+func TestReplayAt(t *testing.T) {
+	replayOffsets = []time.Duration{0, time.Second, 2 * time.Second}
+	defer func() { replayOffsets, replayPeriod = nil, 0 }()
+	replayPeriod = replayCycle(replayOffsets)
+	if replayPeriod != 3*time.Second {
+		t.Fatalf("expected period 3s; got %s", replayPeriod)
+	}
+	f := func(i int, exp time.Duration) {
+		t.Helper()
+		if at := replayAt(i); at != exp {
+			t.Fatalf("expected offset %s of request %d; got %s", exp, i, at)
+		}
+	}
+	f(0, 0)
+	f(2, 2*time.Second)
+	f(3, 3*time.Second)
+	f(7, 7*time.Second)
+	if r := replayRate(); r != 1 {
+		t.Fatalf("expected rate 1; got %f", r)
+	}
+}
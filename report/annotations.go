@@ -0,0 +1,75 @@
+package report
+
+import (
+	"encoding/json"
+)
+
+// Annotation is an external event, which is displayed
+// as a vertical line on charts with time axis
+type Annotation struct {
+	// Time is a number of seconds since test start
+	Time float64
+
+	// Duration is a number of seconds of annotated window, which starts at Time.
+	// Window is displayed as a band instead of line. Zero means event is instant
+	Duration float64
+
+	Label string
+}
+
+type plotLine struct {
+	Value     float64 `json:"value"`
+	Color     string  `json:"color"`
+	DashStyle string  `json:"dashStyle"`
+	Width     int     `json:"width"`
+	Label     struct {
+		Text string `json:"text"`
+	} `json:"label"`
+}
+
+type plotBand struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Color string  `json:"color"`
+	Label struct {
+		Text string `json:"text"`
+	} `json:"label"`
+}
+
+// annotationLines returns plot lines of instant annotations for xAxis of chart.
+// Labels are escaped by json, so they can't break out of script
+func (p *Page) annotationLines() string {
+	lines := make([]plotLine, 0, len(p.Annotations))
+	for _, a := range p.Annotations {
+		if a.Duration > 0 {
+			continue
+		}
+		l := plotLine{Value: a.Time, Color: "#888888", DashStyle: "Dash", Width: 1}
+		l.Label.Text = a.Label
+		lines = append(lines, l)
+	}
+	return marshalPlotOptions(lines)
+}
+
+// annotationBands returns plot bands of phases and of annotated windows for xAxis of chart
+func (p *Page) annotationBands() string {
+	bands := make([]plotBand, 0)
+	bands = append(bands, p.phaseBands()...)
+	for _, a := range p.Annotations {
+		if a.Duration <= 0 {
+			continue
+		}
+		b := plotBand{From: a.Time, To: a.Time + a.Duration, Color: "#f2f2f2"}
+		b.Label.Text = a.Label
+		bands = append(bands, b)
+	}
+	return marshalPlotOptions(bands)
+}
+
+func marshalPlotOptions(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "[]"
+	}
+	return string(b)
+}
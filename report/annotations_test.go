@@ -0,0 +1,21 @@
+package report
+
+import "testing"
+
+func TestAnnotationLinesAndBands(t *testing.T) {
+	p := &Page{Annotations: []Annotation{
+		{Time: 0, Duration: 5, Label: "warmup"},
+		{Time: 12.5, Label: "deploy"},
+	}}
+	expectedLines := `[{"value":12.5,"color":"#888888","dashStyle":"Dash","width":1,"label":{"text":"deploy"}}]`
+	if got := p.annotationLines(); got != expectedLines {
+		t.Errorf("Unexpected plot lines.\nGot:      %s\nExpected: %s", got, expectedLines)
+	}
+	expectedBands := `[{"from":0,"to":5,"color":"#f2f2f2","label":{"text":"warmup"}}]`
+	if got := p.annotationBands(); got != expectedBands {
+		t.Errorf("Unexpected plot bands.\nGot:      %s\nExpected: %s", got, expectedBands)
+	}
+	if got := (&Page{}).annotationBands(); got != "[]" {
+		t.Errorf("Unexpected plot bands without annotations: %s", got)
+	}
+}
@@ -0,0 +1,39 @@
+package report
+
+import "math"
+
+// Parts of latency, which are stacked on latency breakdown chart
+const (
+	// BreakdownSetup is a DNS lookup, TCP connect and TLS handshake of new connections spread over requests
+	BreakdownSetup     = "connection setup"
+	BreakdownSend      = "sending request"
+	BreakdownFirstByte = "time to first byte"
+	BreakdownTransfer  = "body transfer"
+)
+
+// breakdownParts are parts of latency in order of request
+var breakdownParts = []string{BreakdownSetup, BreakdownSend, BreakdownFirstByte, BreakdownTransfer}
+
+// StageLatency contains percentiles and max of time of stage of requests like "time to first byte"
+type StageLatency struct {
+	Stage string `json:"stage"`
+	Count uint64 `json:"count"`
+	Latency
+}
+
+// UpdateLatencyBreakdown appends average time in seconds of every part of latency of requests
+// completed since previous sample to BreakdownSeries. Parts without requests are NaN
+func (p *Page) UpdateLatencyBreakdown(parts map[string]float64) {
+	n := len(p.Connections)
+	for _, part := range breakdownParts {
+		s := p.BreakdownSeries[part]
+		for len(s)+1 < n {
+			s = append(s, math.NaN())
+		}
+		v, ok := parts[part]
+		if !ok {
+			v = math.NaN()
+		}
+		p.BreakdownSeries[part] = append(s, v)
+	}
+}
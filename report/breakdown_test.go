@@ -0,0 +1,33 @@
+package report
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUpdateLatencyBreakdown(t *testing.T) {
+	p := &Page{BreakdownSeries: make(map[string][]float64)}
+	p.Connections = []uint64{1, 1}
+	p.UpdateLatencyBreakdown(map[string]float64{BreakdownSend: 0.1, BreakdownFirstByte: 0.2})
+	p.Connections = append(p.Connections, 1)
+	p.UpdateLatencyBreakdown(nil)
+
+	f := func(part string, expected []float64) {
+		t.Helper()
+		s := p.BreakdownSeries[part]
+		if len(s) != len(expected) {
+			t.Fatalf("Unexpected series of %s: %v; Expected: %v", part, s, expected)
+		}
+		for i := range s {
+			if s[i] != expected[i] && !(math.IsNaN(s[i]) && math.IsNaN(expected[i])) {
+				t.Fatalf("Unexpected series of %s: %v; Expected: %v", part, s, expected)
+			}
+		}
+	}
+
+	nan := math.NaN()
+	f(BreakdownSetup, []float64{nan, nan, nan})
+	f(BreakdownSend, []float64{nan, 0.1, nan})
+	f(BreakdownFirstByte, []float64{nan, 0.2, nan})
+	f(BreakdownTransfer, []float64{nan, nan, nan})
+}
@@ -0,0 +1,195 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Result is a summary of load phase of JSON report
+type Result struct {
+	Rps       float64
+	ErrorRate float64
+	// Latency is nil if load phase has too few requests to measure latency
+	Latency *Latency
+	// Connections is a number of connections dialed during load phase. Is zero if unknown
+	Connections uint64
+}
+
+// ReadResult reads summary of JSON report. With several load phases, e.g. levels of sweep,
+// the last one is summarized. Burst, calibrate and warmup phases are skipped
+func ReadResult(r io.Reader) (*Result, error) {
+	var raw rawSamples
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("cannot parse JSON report: %s", err)
+	}
+	for i := len(raw.Phases) - 1; i >= 0; i-- {
+		ph := raw.Phases[i]
+		if ph.Name == PhaseBurst || ph.Name == PhaseCalibrate || ph.Name == "warmup" {
+			continue
+		}
+		return &Result{Rps: ph.Rps, ErrorRate: ph.ErrorRate, Latency: raw.Latency}, nil
+	}
+	return nil, fmt.Errorf("JSON report contains no load phase")
+}
+
+// Tolerances are max changes of current result compared to baseline, which aren't regressions
+type Tolerances struct {
+	// RpsDrop is a max decrease of rps in percent
+	RpsDrop float64
+	// ErrorRateRise is a max increase of error rate in percentage points
+	ErrorRateRise float64
+	// LatencyRise is a max increase of p50, p90, p95 and p99 latency in percent. Max latency isn't checked
+	LatencyRise float64
+}
+
+// Delta is a change of metric of current result compared to baseline
+type Delta struct {
+	Name     string
+	Baseline float64
+	Current  float64
+	// Change is a relative change in percent, except of error rate, which changes in percentage points.
+	// Is NaN if relative change can't be calculated, since baseline is zero
+	Change     float64
+	Regression bool
+
+	// unit is "rps", "%", "conns" or unit of latency
+	unit string
+}
+
+// Comparison contains deltas of metrics of current result compared to baseline one
+type Comparison struct {
+	// Baseline and Current are names of compared reports
+	Baseline string
+	Current  string
+
+	Tolerances Tolerances
+	Deltas     []Delta
+}
+
+// Compare compares current result with baseline one. Latency and connections are compared only if both results have them.
+// Change of number of connections is never a regression
+func Compare(baseline, current *Result, t Tolerances) *Comparison {
+	c := &Comparison{Tolerances: t}
+	rps := Delta{Name: "rps", Baseline: baseline.Rps, Current: current.Rps, Change: relativeChange(baseline.Rps, current.Rps), unit: "rps"}
+	rps.Regression = rps.Current < rps.Baseline*(1-t.RpsDrop/100)
+	errRate := Delta{Name: "error rate", Baseline: baseline.ErrorRate, Current: current.ErrorRate,
+		Change: current.ErrorRate - baseline.ErrorRate, unit: "%"}
+	errRate.Regression = errRate.Change > t.ErrorRateRise
+	c.Deltas = append(c.Deltas, rps, errRate)
+	if baseline.Latency != nil && current.Latency != nil {
+		c.Deltas = append(c.Deltas, latencyDeltas(baseline.Latency, current.Latency, t)...)
+	}
+	if baseline.Connections > 0 && current.Connections > 0 {
+		b, cur := float64(baseline.Connections), float64(current.Connections)
+		c.Deltas = append(c.Deltas, Delta{Name: "connections", Baseline: b, Current: cur, Change: relativeChange(b, cur), unit: "conns"})
+	}
+	return c
+}
+
+// latencyDeltas returns deltas of latency percentiles and max latency of current compared to baseline
+func latencyDeltas(baseline, current *Latency, t Tolerances) []Delta {
+	var result []Delta
+	unit := AutoLatencyUnit(baseline.P50)
+	for _, l := range []struct {
+		name              string
+		baseline, current float64
+	}{
+		{"p50", baseline.P50, current.P50},
+		{"p90", baseline.P90, current.P90},
+		{"p95", baseline.P95, current.P95},
+		{"p99", baseline.P99, current.P99},
+		{"max", baseline.Max, current.Max},
+	} {
+		d := Delta{Name: l.name, Baseline: l.baseline, Current: l.current, Change: relativeChange(l.baseline, l.current), unit: unit}
+		d.Regression = l.name != "max" && d.Current > d.Baseline*(1+t.LatencyRise/100)
+		result = append(result, d)
+	}
+	return result
+}
+
+// Regressions returns deltas, which exceed tolerances
+func (c *Comparison) Regressions() []Delta {
+	var result []Delta
+	for _, d := range c.Deltas {
+		if d.Regression {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func relativeChange(baseline, current float64) float64 {
+	if baseline == 0 {
+		return math.NaN()
+	}
+	return (current - baseline) / baseline * 100
+}
+
+// FormatValue formats v in unit of d
+func (d Delta) FormatValue(v float64) string {
+	switch d.unit {
+	case "rps":
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case "%":
+		return strconv.FormatFloat(v, 'f', 2, 64) + " %"
+	case "conns":
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	default:
+		return FormatLatency(v, d.unit)
+	}
+}
+
+// FormatChange formats change of d like "+12.50 %" or "+0.30 pp" for error rate
+func (d Delta) FormatChange() string {
+	if math.IsNaN(d.Change) {
+		return "-"
+	}
+	s := strconv.FormatFloat(d.Change, 'f', 2, 64)
+	if d.Change >= 0 {
+		s = "+" + s
+	}
+	if d.unit == "%" {
+		return s + " pp"
+	}
+	return s + " %"
+}
+
+// latencies returns deltas of latency percentiles of c
+func (c *Comparison) latencies() []Delta {
+	var result []Delta
+	for _, d := range c.Deltas {
+		if _, ok := LatencyUnits[d.unit]; ok {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// latencyCategories returns names of latency percentiles of c as JS array
+func (c *Comparison) latencyCategories() string {
+	var names []string
+	for _, d := range c.latencies() {
+		names = append(names, d.Name)
+	}
+	b, _ := json.Marshal(names)
+	return string(b)
+}
+
+// latencySeries returns baseline and current latency percentiles of c in their unit as series of column chart
+func (c *Comparison) latencySeries() string {
+	type series struct {
+		Name string    `json:"name"`
+		Data []float64 `json:"data"`
+	}
+	baseline, current := series{Name: c.Baseline}, series{Name: c.Current}
+	for _, d := range c.latencies() {
+		k := LatencyUnits[d.unit]
+		baseline.Data = append(baseline.Data, d.Baseline*k)
+		current.Data = append(current.Data, d.Current*k)
+	}
+	b, _ := json.Marshal([]series{baseline, current})
+	return string(b)
+}
@@ -0,0 +1,270 @@
+// This file is automatically generated by qtc from "compare.qtpl".
+// See https://github.com/valyala/quicktemplate for details.
+
+//line report/compare.qtpl:1
+package report
+
+//line report/compare.qtpl:1
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line report/compare.qtpl:1
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line report/compare.qtpl:1
+func StreamPrintComparison(qw422016 *qt422016.Writer, c *Comparison) {
+	//line report/compare.qtpl:1
+	qw422016.N().S(`
+<html>
+	<head>
+		<title>`)
+	//line report/compare.qtpl:4
+	qw422016.E().S(c.Baseline)
+	//line report/compare.qtpl:4
+	qw422016.N().S(` vs `)
+	//line report/compare.qtpl:4
+	qw422016.E().S(c.Current)
+	//line report/compare.qtpl:4
+	qw422016.N().S(`</title>
+		<script type="text/javascript" src="https://ajax.googleapis.com/ajax/libs/jquery/3.1.0/jquery.min.js"></script>
+		<script type="text/javascript" src="https://code.highcharts.com/highcharts.js"></script>
+		<style>`)
+	//line report/compare.qtpl:7
+	qw422016.N().Z(MustAsset("report/static/css/main.css"))
+	//line report/compare.qtpl:7
+	qw422016.N().S(`</style>
+	</head>
+	<body>
+		`)
+	//line report/compare.qtpl:10
+	if len(c.Regressions()) > 0 {
+		//line report/compare.qtpl:10
+		qw422016.N().S(`
+		<p style="text-align: center;">`)
+		//line report/compare.qtpl:11
+		qw422016.N().D(len(c.Regressions()))
+		//line report/compare.qtpl:11
+		qw422016.N().S(` of metrics regressed beyond tolerances</p>
+		`)
+		//line report/compare.qtpl:12
+	} else {
+		//line report/compare.qtpl:12
+		qw422016.N().S(`
+		<p style="text-align: center;">No regressions beyond tolerances</p>
+		`)
+		//line report/compare.qtpl:14
+	}
+	//line report/compare.qtpl:14
+	qw422016.N().S(`
+		`)
+	//line report/compare.qtpl:15
+	if len(c.latencies()) > 0 {
+		//line report/compare.qtpl:15
+		qw422016.N().S(`
+		`)
+		//line report/compare.qtpl:16
+		c.streamlatencyChart(qw422016)
+		//line report/compare.qtpl:16
+		qw422016.N().S(`
+		`)
+		//line report/compare.qtpl:17
+	}
+	//line report/compare.qtpl:17
+	qw422016.N().S(`
+		<div style = "margin: 0 auto; width:50%;">
+		 <!--[if lte IE 9]>
+		 <div class="old_ie_wrapper">
+		 <!--<![endif]-->
+		 <p class = "title">Load phase: `)
+	//line report/compare.qtpl:22
+	qw422016.E().S(c.Baseline)
+	//line report/compare.qtpl:22
+	qw422016.N().S(` vs `)
+	//line report/compare.qtpl:22
+	qw422016.E().S(c.Current)
+	//line report/compare.qtpl:22
+	qw422016.N().S(`</p>
+		 <table class="fixed_headers">
+			 <thead>
+				<tr>
+					<td>Metric</td>
+					<td>Baseline</td>
+					<td>Current</td>
+					<td>Change</td>
+					<td>Status</td>
+				</tr>
+			 </thead>
+			 <tbody>
+				`)
+	//line report/compare.qtpl:34
+	for _, d := range c.Deltas {
+		//line report/compare.qtpl:34
+		qw422016.N().S(`
+					<tr>
+						<td>`)
+		//line report/compare.qtpl:36
+		qw422016.E().S(d.Name)
+		//line report/compare.qtpl:36
+		qw422016.N().S(`</td>
+						<td>`)
+		//line report/compare.qtpl:37
+		qw422016.E().S(d.FormatValue(d.Baseline))
+		//line report/compare.qtpl:37
+		qw422016.N().S(`</td>
+						<td>`)
+		//line report/compare.qtpl:38
+		qw422016.E().S(d.FormatValue(d.Current))
+		//line report/compare.qtpl:38
+		qw422016.N().S(`</td>
+						<td>`)
+		//line report/compare.qtpl:39
+		qw422016.E().S(d.FormatChange())
+		//line report/compare.qtpl:39
+		qw422016.N().S(`</td>
+						`)
+		//line report/compare.qtpl:40
+		if d.Regression {
+			//line report/compare.qtpl:40
+			qw422016.N().S(`
+						<td style="color: #c0392b;">regression</td>
+						`)
+			//line report/compare.qtpl:42
+		} else {
+			//line report/compare.qtpl:42
+			qw422016.N().S(`
+						<td>ok</td>
+						`)
+			//line report/compare.qtpl:44
+		}
+		//line report/compare.qtpl:44
+		qw422016.N().S(`
+					</tr>
+				`)
+		//line report/compare.qtpl:46
+	}
+	//line report/compare.qtpl:46
+	qw422016.N().S(`
+			 </tbody>
+		 </table>
+		 <p>Tolerances: rps drop `)
+	//line report/compare.qtpl:49
+	qw422016.N().FPrec(c.Tolerances.RpsDrop, 2)
+	//line report/compare.qtpl:49
+	qw422016.N().S(` %; error rate rise `)
+	//line report/compare.qtpl:49
+	qw422016.N().FPrec(c.Tolerances.ErrorRateRise, 2)
+	//line report/compare.qtpl:49
+	qw422016.N().S(` pp; latency rise `)
+	//line report/compare.qtpl:49
+	qw422016.N().FPrec(c.Tolerances.LatencyRise, 2)
+	//line report/compare.qtpl:49
+	qw422016.N().S(` %</p>
+		 <!--[if lte IE 9]>
+	     </div>
+	     <!--<![endif]-->
+		</div>
+	</body>
+</html>
+`)
+//line report/compare.qtpl:56
+}
+
+//line report/compare.qtpl:56
+func WritePrintComparison(qq422016 qtio422016.Writer, c *Comparison) {
+	//line report/compare.qtpl:56
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/compare.qtpl:56
+	StreamPrintComparison(qw422016, c)
+	//line report/compare.qtpl:56
+	qt422016.ReleaseWriter(qw422016)
+//line report/compare.qtpl:56
+}
+
+//line report/compare.qtpl:56
+func PrintComparison(c *Comparison) string {
+	//line report/compare.qtpl:56
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/compare.qtpl:56
+	WritePrintComparison(qb422016, c)
+	//line report/compare.qtpl:56
+	qs422016 := string(qb422016.B)
+	//line report/compare.qtpl:56
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/compare.qtpl:56
+	return qs422016
+//line report/compare.qtpl:56
+}
+
+//line report/compare.qtpl:58
+func (c *Comparison) streamlatencyChart(qw422016 *qt422016.Writer) {
+	//line report/compare.qtpl:58
+	qw422016.N().S(`
+	<script>
+	$(function () {
+    			$('#latency-comparison').highcharts({
+					chart: {
+						type: 'column'
+					},
+					title: {
+						text: 'Latency Comparison',
+					},
+					xAxis: {
+						categories: `)
+	//line report/compare.qtpl:69
+	qw422016.N().S(c.latencyCategories())
+	//line report/compare.qtpl:69
+	qw422016.N().S(`
+					},
+					yAxis: {
+						title: {
+							text: 'Latency, `)
+	//line report/compare.qtpl:73
+	qw422016.N().S(c.latencies()[0].unit)
+	//line report/compare.qtpl:73
+	qw422016.N().S(`'
+						}
+					},
+					series: `)
+	//line report/compare.qtpl:76
+	qw422016.N().S(c.latencySeries())
+	//line report/compare.qtpl:76
+	qw422016.N().S(`
+				});
+    		});
+    </script>
+   	<div id="latency-comparison" style="min-width: 310px; height: 400px; margin: 0 auto"></div>
+`)
+//line report/compare.qtpl:81
+}
+
+//line report/compare.qtpl:81
+func (c *Comparison) writelatencyChart(qq422016 qtio422016.Writer) {
+	//line report/compare.qtpl:81
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/compare.qtpl:81
+	c.streamlatencyChart(qw422016)
+	//line report/compare.qtpl:81
+	qt422016.ReleaseWriter(qw422016)
+//line report/compare.qtpl:81
+}
+
+//line report/compare.qtpl:81
+func (c *Comparison) latencyChart() string {
+	//line report/compare.qtpl:81
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/compare.qtpl:81
+	c.writelatencyChart(qb422016)
+	//line report/compare.qtpl:81
+	qs422016 := string(qb422016.B)
+	//line report/compare.qtpl:81
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/compare.qtpl:81
+	return qs422016
+//line report/compare.qtpl:81
+}
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadResult(t *testing.T) {
+	p := &Page{
+		Latency: &Latency{P50: 0.1, P90: 0.15, P95: 0.18, P99: 0.2, Max: 0.3},
+		Phases: []Phase{
+			{Name: PhaseBurst, Rps: 1000},
+			{Name: PhaseCalibrate, Rps: 800},
+			{Name: "steady", Rps: 500, ErrorRate: 1.5},
+			{Name: "warmup", Rps: 400},
+		},
+	}
+	res, err := ReadResult(strings.NewReader(PrintJSON(p)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if res.Rps != 500 || res.ErrorRate != 1.5 || *res.Latency != *p.Latency {
+		t.Errorf("Unexpected result: %+v", res)
+	}
+
+	p.Phases = p.Phases[:2]
+	if _, err := ReadResult(strings.NewReader(PrintJSON(p))); err == nil {
+		t.Errorf("Expected error for report without load phase")
+	}
+	if _, err := ReadResult(strings.NewReader("<html>")); err == nil {
+		t.Errorf("Expected error for HTML report")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	f := func(baseline, current *Result, regressions ...string) {
+		t.Helper()
+		c := Compare(baseline, current, Tolerances{RpsDrop: 5, ErrorRateRise: 0.5, LatencyRise: 10})
+		var got []string
+		for _, d := range c.Regressions() {
+			got = append(got, d.Name)
+		}
+		if strings.Join(got, ",") != strings.Join(regressions, ",") {
+			t.Errorf("Unexpected regressions. Got: %v; Expected: %v", got, regressions)
+		}
+	}
+
+	l := &Latency{P50: 0.1, P90: 0.2, P95: 0.3, P99: 0.4, Max: 1}
+	f(&Result{Rps: 100, Latency: l}, &Result{Rps: 96, ErrorRate: 0.4, Latency: &Latency{P50: 0.105, P90: 0.2, P95: 0.3, P99: 0.44, Max: 5}})
+	f(&Result{Rps: 100, ErrorRate: 1, Latency: l}, &Result{Rps: 90, ErrorRate: 2, Latency: l}, "rps", "error rate")
+	f(&Result{Rps: 100, Latency: l}, &Result{Rps: 100, Latency: &Latency{P50: 0.1, P90: 0.2, P95: 0.3, P99: 0.5, Max: 1}}, "p99")
+	// latency isn't compared if one of results has no latency
+	f(&Result{Rps: 100, Latency: l}, &Result{Rps: 100})
+	// change of number of connections isn't a regression
+	f(&Result{Rps: 100, Latency: l, Connections: 2}, &Result{Rps: 100, Latency: l, Connections: 40})
+}
+
+func TestDeltaFormat(t *testing.T) {
+	f := func(d Delta, value, change string) {
+		t.Helper()
+		if s := d.FormatValue(d.Current); s != value {
+			t.Errorf("Unexpected value of %s. Got: %q; Expected: %q", d.Name, s, value)
+		}
+		if s := d.FormatChange(); s != change {
+			t.Errorf("Unexpected change of %s. Got: %q; Expected: %q", d.Name, s, change)
+		}
+	}
+
+	c := Compare(&Result{Rps: 200, ErrorRate: 0, Latency: &Latency{P50: 0.01}}, &Result{Rps: 150, ErrorRate: 0.3, Latency: &Latency{P50: 0.0125}}, Tolerances{})
+	f(c.Deltas[0], "150.00", "-25.00 %")
+	f(c.Deltas[1], "0.30 %", "+0.30 pp")
+	f(c.Deltas[2], "12.50ms", "+25.00 %")
+	// p90 of baseline is zero, so its relative change is unknown
+	f(c.Deltas[3], "0.00ms", "-")
+
+	c = Compare(&Result{Rps: 100, Connections: 40}, &Result{Rps: 100, Connections: 2}, Tolerances{})
+	f(c.Deltas[2], "2", "-95.00 %")
+	// connections aren't compared if one of results has no connections
+	if c = Compare(&Result{Rps: 100, Connections: 40}, &Result{Rps: 100}, Tolerances{}); len(c.Deltas) != 2 {
+		t.Errorf("Unexpected number of deltas. Got: %d; Expected: 2", len(c.Deltas))
+	}
+}
+
+func TestPrintComparison(t *testing.T) {
+	l := &Latency{P50: 0.1, P90: 0.2, P95: 0.3, P99: 0.4, Max: 1}
+	c := Compare(&Result{Rps: 100, Latency: l}, &Result{Rps: 50, Latency: l}, Tolerances{RpsDrop: 5})
+	c.Baseline, c.Current = "before.json", "after.json"
+	s := PrintComparison(c)
+	for _, substr := range []string{"before.json vs after.json", "latency-comparison", "regression", "1 of metrics regressed"} {
+		if !strings.Contains(s, substr) {
+			t.Errorf("Comparison doesn't contain %q", substr)
+		}
+	}
+
+	// connections are compared by table, but not by latency chart
+	c = Compare(&Result{Rps: 100, Latency: l, Connections: 40}, &Result{Rps: 100, Latency: l, Connections: 2}, Tolerances{})
+	if n := len(c.latencies()); n != 5 {
+		t.Errorf("Unexpected number of latencies. Got: %d; Expected: 5", n)
+	}
+	if s := PrintComparison(c); !strings.Contains(s, "connections") {
+		t.Errorf("Comparison doesn't contain connections")
+	}
+	if c = Compare(&Result{Rps: 100, Connections: 40}, &Result{Rps: 100, Connections: 2}, Tolerances{}); strings.Contains(PrintComparison(c), "latency-comparison") {
+		t.Errorf("Comparison without latency must not contain latency chart")
+	}
+}
@@ -0,0 +1,26 @@
+package report
+
+import (
+	"sort"
+)
+
+// UpdateCorrectedDuration appends latency of requests measured from their intended send time
+// to series of the last sample. Series which begin after the first sample are padded to align with samples
+func (p *Page) UpdateCorrectedDuration(d map[float64]float64) {
+	appendAligned(p.CorrectedDuration, d, len(p.Connections))
+}
+
+// correctedDurations returns series of quantile q of corrected latency
+func (p *Page) correctedDurations(q float64) []float64 {
+	return p.scaled(p.CorrectedDuration[q])
+}
+
+// correctedQuantiles returns sorted quantiles of CorrectedDuration
+func (p *Page) correctedQuantiles() []float64 {
+	var keys []float64
+	for k := range p.CorrectedDuration {
+		keys = append(keys, k)
+	}
+	sort.Float64s(keys)
+	return keys
+}
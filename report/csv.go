@@ -0,0 +1,57 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"strconv"
+)
+
+// PrintCSV returns every sample of report as CSV row with time of sample in seconds since test start.
+// Number of rows is the length of the longest series, and cells of shorter series are empty
+func PrintCSV(p *Page) string {
+	b, _ := p.MarshalCSV()
+	return string(b)
+}
+
+// MarshalCSV returns every sample of report as CSV row like PrintCSV.
+// Latency quantiles are in seconds, and their cells are empty for samples
+// with less than MinSamples requests
+func (p *Page) MarshalCSV() ([]byte, error) {
+	series := [][]uint64{p.Connections, p.RequestSum, p.RequestSuccess, p.Errors, p.Timeouts, p.Qps, p.BytesWritten, p.BytesRead}
+	var n int
+	for _, s := range series {
+		if len(s) > n {
+			n = len(s)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"time", "connections", "request_sum", "request_success", "errors", "timeouts", "qps", "bytes_written", "bytes_read"}
+	for _, q := range sampleQuantiles {
+		header = append(header, "latency_p"+strconv.FormatFloat(q*100, 'f', -1, 64))
+	}
+	w.Write(header)
+	row := make([]string, len(header))
+	for i := 0; i < n; i++ {
+		row[0] = strconv.FormatFloat(p.sampleTime(i), 'f', 3, 64)
+		for j, s := range series {
+			row[j+1] = ""
+			if i < len(s) {
+				row[j+1] = strconv.FormatUint(s[i], 10)
+			}
+		}
+		for j, q := range sampleQuantiles {
+			cell := &row[len(series)+1+j]
+			*cell = ""
+			d := p.RequestDuration[q]
+			if i < len(d) && !math.IsNaN(d[i]) && i < len(p.RequestSum) && p.RequestSum[i] >= p.MinSamples {
+				*cell = strconv.FormatFloat(d[i], 'f', -1, 64)
+			}
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
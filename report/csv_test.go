@@ -0,0 +1,43 @@
+package report
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPrintCSV(t *testing.T) {
+	p := &Page{
+		Interval:       0.5,
+		Connections:    []uint64{1, 2},
+		RequestSum:     []uint64{10, 20},
+		RequestSuccess: []uint64{10, 19},
+		Errors:         []uint64{0, 1},
+		Timeouts:       []uint64{0, 0},
+		Qps:            []uint64{100},
+		BytesWritten:   []uint64{1000, 2000},
+		BytesRead:      []uint64{3000, 4000},
+	}
+	expected := "time,connections,request_sum,request_success,errors,timeouts,qps,bytes_written,bytes_read,latency_p50,latency_p90,latency_p99\n" +
+		"0.000,1,10,10,0,0,100,1000,3000,,,\n" +
+		"0.500,2,20,19,1,0,,2000,4000,,,\n"
+	if got := PrintCSV(p); got != expected {
+		t.Errorf("Unexpected CSV.\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+
+	p.Timestamps = []float64{0.2, 1.1}
+	expected = "time,connections,request_sum,request_success,errors,timeouts,qps,bytes_written,bytes_read,latency_p50,latency_p90,latency_p99\n" +
+		"0.200,1,10,10,0,0,100,1000,3000,,,\n" +
+		"1.100,2,20,19,1,0,,2000,4000,,,\n"
+	if got := PrintCSV(p); got != expected {
+		t.Errorf("Unexpected CSV with timestamps.\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+
+	p.MinSamples = 15
+	p.RequestDuration = map[float64][]float64{0.5: {0.1, 0.2}, 0.99: {0.3, math.NaN()}}
+	expected = "time,connections,request_sum,request_success,errors,timeouts,qps,bytes_written,bytes_read,latency_p50,latency_p90,latency_p99\n" +
+		"0.200,1,10,10,0,0,100,1000,3000,,,\n" +
+		"1.100,2,20,19,1,0,,2000,4000,0.2,,\n"
+	if got := PrintCSV(p); got != expected {
+		t.Errorf("Unexpected CSV with latency.\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,28 @@
+package report
+
+import "sort"
+
+// UpdateErrorClassSeries appends numbers of errors of every class to series of the last sample.
+// Series of classes, which appear after the first sample, are padded with zeros
+func (p *Page) UpdateErrorClassSeries(counts map[string]uint64) {
+	if p.ErrorClassSeries == nil {
+		p.ErrorClassSeries = make(map[string][]uint64)
+	}
+	for class, v := range counts {
+		s := p.ErrorClassSeries[class]
+		for len(s)+1 < len(p.RequestSum) {
+			s = append(s, 0)
+		}
+		p.ErrorClassSeries[class] = append(s, v)
+	}
+}
+
+// errorClasses returns classes of ErrorClassSeries in alphabetical order
+func (p *Page) errorClasses() []string {
+	classes := make([]string, 0, len(p.ErrorClassSeries))
+	for class := range p.ErrorClassSeries {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	return classes
+}
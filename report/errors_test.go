@@ -0,0 +1,24 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpdateErrorClassSeries(t *testing.T) {
+	p := &Page{}
+	p.RequestSum = append(p.RequestSum, 5)
+	p.UpdateErrorClassSeries(map[string]uint64{})
+	p.RequestSum = append(p.RequestSum, 10)
+	p.UpdateErrorClassSeries(map[string]uint64{"timeout": 2})
+	p.RequestSum = append(p.RequestSum, 15)
+	p.UpdateErrorClassSeries(map[string]uint64{"timeout": 3, "dns": 1})
+
+	expected := map[string][]uint64{"timeout": {0, 2, 3}, "dns": {0, 0, 1}}
+	if !reflect.DeepEqual(p.ErrorClassSeries, expected) {
+		t.Errorf("Unexpected error class series. Got: %v; Expected: %v", p.ErrorClassSeries, expected)
+	}
+	if classes := p.errorClasses(); !reflect.DeepEqual(classes, []string{"dns", "timeout"}) {
+		t.Errorf("Unexpected classes of series. Got: %v", classes)
+	}
+}
@@ -0,0 +1,16 @@
+package report
+
+// FailedRequest is a captured failed request along with its response
+type FailedRequest struct {
+	// Time is a number of seconds since test start
+	Time float64 `json:"time"`
+
+	// Failure is an error message or reason why response isn't successful
+	Failure string `json:"failure"`
+
+	// Request contains request line, headers and truncated body
+	Request string `json:"request"`
+
+	// Response contains status line, headers and truncated body. Is empty if response wasn't received
+	Response string `json:"response,omitempty"`
+}
@@ -0,0 +1,27 @@
+package report
+
+import (
+	"sort"
+)
+
+// UpdateFirstRequestDuration appends latency of the first requests on connections
+// to series of the last sample. Series which begin after the first sample,
+// e.g. of resumed test, are padded to align with samples
+func (p *Page) UpdateFirstRequestDuration(d map[float64]float64) {
+	appendAligned(p.FirstRequestDuration, d, len(p.Connections))
+}
+
+// firstRequestDurations returns series of quantile q of latency of the first requests on connections
+func (p *Page) firstRequestDurations(q float64) []float64 {
+	return p.scaled(p.FirstRequestDuration[q])
+}
+
+// firstRequestQuantiles returns sorted quantiles of FirstRequestDuration
+func (p *Page) firstRequestQuantiles() []float64 {
+	var keys []float64
+	for k := range p.FirstRequestDuration {
+		keys = append(keys, k)
+	}
+	sort.Float64s(keys)
+	return keys
+}
@@ -0,0 +1,20 @@
+package report
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUpdateFirstRequestDuration(t *testing.T) {
+	p := &Page{FirstRequestDuration: make(map[float64][]float64)}
+	// series of resumed test begins with the third sample
+	p.Connections = []uint64{1, 1, 1}
+	p.UpdateFirstRequestDuration(map[float64]float64{0.5: 0.1})
+	p.Connections = append(p.Connections, 1)
+	p.UpdateFirstRequestDuration(map[float64]float64{0.5: 0.2})
+
+	s := p.FirstRequestDuration[0.5]
+	if len(s) != 4 || !math.IsNaN(s[0]) || !math.IsNaN(s[1]) || s[2] != 0.1 || s[3] != 0.2 {
+		t.Errorf("Unexpected series: %v; Expected: [NaN NaN 0.1 0.2]", s)
+	}
+}
@@ -0,0 +1,24 @@
+package report
+
+import "fmt"
+
+// Formats maps supported format of report to extension of its file
+var Formats = map[string]string{
+	"html": ".html",
+	"json": ".json",
+	"csv":  ".csv",
+}
+
+// Marshal returns report in format, which must be one of Formats
+func Marshal(p *Page, format string) ([]byte, error) {
+	switch format {
+	case "html":
+		return []byte(PrintPage(p)), nil
+	case "json":
+		return p.MarshalJSON()
+	case "csv":
+		return p.MarshalCSV()
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
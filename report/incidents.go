@@ -0,0 +1,51 @@
+package report
+
+// Incident represents a period of time
+// when errors rate exceeded threshold
+type Incident struct {
+	// Start is a number of seconds since test start
+	Start float64
+
+	// Duration is measured in seconds
+	Duration float64
+
+	// PeakErrorRate is a max percent of errors during incident
+	PeakErrorRate float64
+}
+
+// errorRate calculates percent of errors among requests for every sample
+func (p *Page) errorRate() []float64 {
+	e := p.rates(p.Errors)
+	r := p.rates(p.RequestSum)
+	result := make([]float64, len(e))
+	for i := range e {
+		if r[i] > 0 {
+			result[i] = e[i] / r[i] * 100
+		}
+	}
+
+	return result
+}
+
+// Incidents detects periods of load phase when errors rate exceeded threshold.
+// Threshold is measured in percents
+func (p *Page) Incidents(threshold float64) []Incident {
+	var result []Incident
+	var cur *Incident
+	for i, v := range p.errorRate() {
+		if v <= threshold || !p.isLoadSample(i) {
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			result = append(result, Incident{Start: p.sampleTime(i)})
+			cur = &result[len(result)-1]
+		}
+		cur.Duration += p.samplePeriod(i)
+		if v > cur.PeakErrorRate {
+			cur.PeakErrorRate = v
+		}
+	}
+
+	return result
+}
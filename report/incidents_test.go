@@ -0,0 +1,67 @@
+package report
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIncidents(t *testing.T) {
+	p := &Page{
+		Interval:   0.5,
+		RequestSum: []uint64{0, 100, 200, 300, 400, 500, 600, 700},
+		Errors:     []uint64{0, 0, 50, 60, 60, 60, 90, 90},
+	}
+
+	incidents := p.Incidents(5)
+	if len(incidents) != 2 {
+		t.Fatalf("Unexpected number of incidents. Got: %d; Expected: %d", len(incidents), 2)
+	}
+
+	exp := Incident{Start: 1, Duration: 1, PeakErrorRate: 50}
+	if incidents[0] != exp {
+		t.Errorf("Unexpected first incident. Got: %+v; Expected: %+v", incidents[0], exp)
+	}
+
+	exp = Incident{Start: 3, Duration: 0.5, PeakErrorRate: 30}
+	if incidents[1] != exp {
+		t.Errorf("Unexpected second incident. Got: %+v; Expected: %+v", incidents[1], exp)
+	}
+
+	if incidents = p.Incidents(60); len(incidents) != 0 {
+		t.Errorf("Unexpected number of incidents. Got: %d; Expected: %d", len(incidents), 0)
+	}
+}
+
+func TestIncidentsTimestamps(t *testing.T) {
+	p := &Page{
+		Interval:   0.5,
+		Timestamps: []float64{0.5, 2.5, 2.6, 2.7, 4.7},
+		RequestSum: []uint64{0, 400, 420, 440, 840},
+		Errors:     []uint64{0, 0, 10, 20, 20},
+	}
+
+	incidents := p.Incidents(5)
+	if len(incidents) != 1 {
+		t.Fatalf("Unexpected number of incidents. Got: %d; Expected: %d", len(incidents), 1)
+	}
+	exp := Incident{Start: 2.6, Duration: 0.2, PeakErrorRate: 50}
+	if math.Abs(incidents[0].Duration-exp.Duration) > 1e-9 || incidents[0].Start != exp.Start || incidents[0].PeakErrorRate != exp.PeakErrorRate {
+		t.Errorf("Unexpected incident. Got: %+v; Expected: %+v", incidents[0], exp)
+	}
+}
+
+func TestIncidentsLoadPhase(t *testing.T) {
+	p := &Page{
+		Interval:     0.5,
+		RequestSum:   []uint64{0, 100, 200, 300, 400, 500},
+		Errors:       []uint64{0, 50, 100, 100, 150, 150},
+		SamplePhases: []string{PhaseCalibrate, PhaseCalibrate, PhaseCalibrate, "steady", "steady", "steady"},
+	}
+
+	// errors of calibrate phase aren't incidents of target
+	incidents := p.Incidents(5)
+	exp := []Incident{{Start: 2, Duration: 0.5, PeakErrorRate: 50}}
+	if len(incidents) != 1 || incidents[0] != exp[0] {
+		t.Errorf("Unexpected incidents. Got: %+v; Expected: %+v", incidents, exp)
+	}
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// influxMeasurement is a name of measurement of samples in InfluxDB line protocol
+const influxMeasurement = "fasthttploader"
+
+var (
+	influxTagEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`)
+
+	// sampleQuantiles are latency quantiles written as fields of influx lines and columns of CSV like "latency_p99"
+	sampleQuantiles = []float64{0.5, 0.9, 0.99}
+)
+
+// WriteInflux writes every sample as line of InfluxDB line protocol to w.
+// Timestamps of lines are start plus time of sample. Tags are added to every line.
+// Latency is written in seconds and is omitted for samples with less than MinSamples requests
+func (p *Page) WriteInflux(w io.Writer, start time.Time, tags map[string]string) error {
+	names := make([]string, 0, len(tags))
+	for k := range tags {
+		names = append(names, k)
+	}
+	// influx recommends to sort tags by key
+	sort.Strings(names)
+	var prefix bytes.Buffer
+	prefix.WriteString(influxMeasurement)
+	for _, k := range names {
+		if tags[k] == "" {
+			continue
+		}
+		prefix.WriteString("," + influxTagEscaper.Replace(k) + "=" + influxTagEscaper.Replace(tags[k]))
+	}
+
+	rps := p.rates(p.RequestSum)
+	var buf []byte
+	for i := range p.Connections {
+		buf = append(buf[:0], prefix.Bytes()...)
+		buf = appendInfluxInt(buf, " connections=", p.Connections[i])
+		buf = appendInfluxInt(buf, ",requests=", p.RequestSum[i])
+		buf = appendInfluxInt(buf, ",request_success=", p.RequestSuccess[i])
+		buf = appendInfluxInt(buf, ",errors=", p.Errors[i])
+		buf = appendInfluxInt(buf, ",timeouts=", p.Timeouts[i])
+		buf = appendInfluxInt(buf, ",qps_limit=", p.Qps[i])
+		buf = appendInfluxInt(buf, ",bytes_written=", p.BytesWritten[i])
+		buf = appendInfluxInt(buf, ",bytes_read=", p.BytesRead[i])
+		buf = append(buf, ",rps="...)
+		buf = strconv.AppendFloat(buf, rps[i], 'f', -1, 64)
+		if p.RequestSum[i] >= p.MinSamples {
+			for _, q := range sampleQuantiles {
+				d := p.RequestDuration[q]
+				if i >= len(d) || math.IsNaN(d[i]) {
+					continue
+				}
+				buf = append(buf, ",latency_p"...)
+				buf = strconv.AppendFloat(buf, q*100, 'f', -1, 64)
+				buf = append(buf, '=')
+				buf = strconv.AppendFloat(buf, d[i], 'f', -1, 64)
+			}
+		}
+		ts := start.Add(time.Duration(p.sampleTime(i) * float64(time.Second)))
+		buf = append(buf, ' ')
+		buf = strconv.AppendInt(buf, ts.UnixNano(), 10)
+		buf = append(buf, '\n')
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendInfluxInt(dst []byte, name string, v uint64) []byte {
+	dst = append(dst, name...)
+	dst = strconv.AppendUint(dst, v, 10)
+	return append(dst, 'i')
+}
@@ -0,0 +1,41 @@
+package report
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWriteInflux(t *testing.T) {
+	p := &Page{
+		Interval:       0.5,
+		MinSamples:     100,
+		Connections:    []uint64{1, 2},
+		RequestSum:     []uint64{50, 250},
+		RequestSuccess: []uint64{50, 249},
+		Errors:         []uint64{0, 1},
+		Timeouts:       []uint64{0, 0},
+		Qps:            []uint64{400, 400},
+		BytesWritten:   []uint64{1000, 5000},
+		BytesRead:      []uint64{2000, 10000},
+		RequestDuration: map[float64][]float64{
+			0.5:  {0.001, 0.002},
+			0.9:  {0.003, math.NaN()},
+			0.99: {0.005, 0.01},
+		},
+	}
+	var buf bytes.Buffer
+	start := time.Unix(1700000000, 0)
+	err := p.WriteInflux(&buf, start, map[string]string{"target": "example.com", "run": "nightly run,1", "empty": ""})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `fasthttploader,run=nightly\ run\,1,target=example.com connections=1i,requests=50i,request_success=50i,errors=0i,timeouts=0i,qps_limit=400i,bytes_written=1000i,bytes_read=2000i,rps=0 1700000000000000000
+fasthttploader,run=nightly\ run\,1,target=example.com connections=2i,requests=250i,request_success=249i,errors=1i,timeouts=0i,qps_limit=400i,bytes_written=5000i,bytes_read=10000i,rps=400,latency_p50=0.002,latency_p99=0.01 1700000000500000000
+`
+	if got := buf.String(); got != expected {
+		t.Errorf("Unexpected lines.\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"sort"
+	"strconv"
+)
+
+// LatencyUnits maps names of supported latency units to number of units in second
+var LatencyUnits = map[string]float64{
+	"s":  1,
+	"ms": 1e3,
+	"us": 1e6,
+}
+
+// Latency contains percentiles and max of latency of requests in seconds
+type Latency struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// AutoLatencyUnit returns unit in which given latency
+// (measured in seconds) is displayed best
+func AutoLatencyUnit(seconds float64) string {
+	switch {
+	case seconds >= 1:
+		return "s"
+	case seconds < 1e-3:
+		return "us"
+	default:
+		return "ms"
+	}
+}
+
+// FormatLatency formats latency measured in seconds in given unit
+func FormatLatency(seconds float64, unit string) string {
+	return strconv.FormatFloat(seconds*LatencyUnits[unit], 'f', 2, 64) + unit
+}
+
+// latencyUnit returns unit of latency charts. Seconds are used by default
+func (p *Page) latencyUnit() string {
+	if _, ok := LatencyUnits[p.LatencyUnit]; !ok {
+		return "s"
+	}
+	return p.LatencyUnit
+}
+
+// latencyPerspective returns description of what latency charts include
+func (p *Page) latencyPerspective() string {
+	if p.LatencyPerspective == "client" {
+		return "Latency is shown from client perspective: wait in job queue of generator is included"
+	}
+	return "Latency is shown from server perspective: wait in job queue of generator isn't included"
+}
+
+// durationQuantiles returns sorted quantiles of RequestDuration
+func (p *Page) durationQuantiles() []float64 {
+	var keys []float64
+	for k := range p.RequestDuration {
+		keys = append(keys, k)
+	}
+	sort.Float64s(keys)
+	return keys
+}
+
+// quantileName returns name of quantile q like "p99.9"
+func quantileName(q float64) string {
+	return "p" + strconv.FormatFloat(q*100, 'g', 4, 64)
+}
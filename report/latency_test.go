@@ -0,0 +1,54 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutoLatencyUnit(t *testing.T) {
+	f := func(seconds float64, expected string) {
+		t.Helper()
+		if got := AutoLatencyUnit(seconds); got != expected {
+			t.Errorf("Unexpected unit of %v. Got: %q; Expected: %q", seconds, got, expected)
+		}
+	}
+
+	f(2.5, "s")
+	f(1, "s")
+	f(0.999, "ms")
+	f(1e-3, "ms")
+	f(0.0005, "us")
+	f(0, "us")
+}
+
+func TestFormatLatency(t *testing.T) {
+	f := func(seconds float64, unit, expected string) {
+		t.Helper()
+		if got := FormatLatency(seconds, unit); got != expected {
+			t.Errorf("Unexpected formatted latency %v in %q. Got: %q; Expected: %q", seconds, unit, got, expected)
+		}
+	}
+
+	f(1.5, "s", "1.50s")
+	f(0.0123, "ms", "12.30ms")
+	f(0.000042, "us", "42.00us")
+}
+
+func TestPageLatencyUnit(t *testing.T) {
+	f := func(unit, expected string) {
+		t.Helper()
+		p := &Page{LatencyUnit: unit, RequestDuration: map[float64][]float64{0.5: {0.01}}}
+		if got := p.latencyUnit(); got != expected {
+			t.Errorf("Unexpected unit of page with %q. Got: %q; Expected: %q", unit, got, expected)
+		}
+		if html := PrintPage(p); !strings.Contains(html, "valueSuffix: ' "+expected+"'") {
+			t.Errorf("Latency charts of page with %q aren't in %q", unit, expected)
+		}
+	}
+
+	f("ms", "ms")
+	f("us", "us")
+	// seconds are used unless unit is supported
+	f("", "s")
+	f("auto", "s")
+}
@@ -0,0 +1,33 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Metadata describes run of test, so it could be reproduced
+type Metadata struct {
+	// Version is a version of fasthttploader like "v1.2.0" or "(devel) 1a2b3c4"
+	Version string `json:"version"`
+
+	// Seed is a seed of all random values of test
+	Seed int64 `json:"seed"`
+
+	// Config maps names of flags to their effective values, including defaults. Secrets are redacted
+	Config map[string]interface{} `json:"config"`
+}
+
+// configEntry is a flag of Metadata.Config with formatted value
+type configEntry struct {
+	name, value string
+}
+
+// configEntries returns flags of Config sorted by name
+func (m *Metadata) configEntries() []configEntry {
+	entries := make([]configEntry, 0, len(m.Config))
+	for name, v := range m.Config {
+		entries = append(entries, configEntry{name: name, value: fmt.Sprint(v)})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	return entries
+}
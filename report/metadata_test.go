@@ -0,0 +1,43 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMetadata(t *testing.T) {
+	m := &Metadata{
+		Version: "v1.2.0",
+		Seed:    42,
+		Config:  map[string]interface{}{"q": 100, "d": "10s", "header": []string{"Authorization: REDACTED"}},
+	}
+	entries := m.configEntries()
+	expected := []configEntry{{"d", "10s"}, {"header", "[Authorization: REDACTED]"}, {"q", "100"}}
+	if len(entries) != len(expected) {
+		t.Fatalf("Unexpected entries. Got: %v; Expected: %v", entries, expected)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("Unexpected entry %d. Got: %v; Expected: %v", i, entries[i], expected[i])
+		}
+	}
+
+	p := &Page{Metadata: m}
+	var buf bytes.Buffer
+	WritePrintPage(&buf, p)
+	for _, s := range []string{"fasthttploader v1.2.0; seed 42", "<td>header</td>"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Report must contain %q", s)
+		}
+	}
+
+	var raw rawSamples
+	if err := json.Unmarshal([]byte(PrintJSON(p)), &raw); err != nil {
+		t.Fatalf("cannot parse JSON report: %s", err)
+	}
+	if raw.Metadata == nil || raw.Metadata.Seed != 42 || raw.Metadata.Version != "v1.2.0" || raw.Metadata.Config["d"] != "10s" {
+		t.Errorf("Unexpected metadata of JSON report: %+v", raw.Metadata)
+	}
+}
@@ -0,0 +1,78 @@
+package report
+
+import "math"
+
+// Phase contains summary of test phase like burst, calibrate or load
+type Phase struct {
+	Name string `json:"name"`
+
+	Requests  uint64  `json:"requests"`
+	Rps       float64 `json:"rps"`
+	ErrorRate float64 `json:"error_rate"`
+
+	// P50 and P99 are measured in seconds. They are zero if phase has less than MinSamples requests
+	P50 float64 `json:"p50"`
+	P99 float64 `json:"p99"`
+
+	Connections uint64 `json:"connections"`
+}
+
+// Names of phases, which search for load of test instead of measuring target
+const (
+	PhaseBurst     = "burst"
+	PhaseCalibrate = "calibrate"
+)
+
+// isLoadSample returns true if sample i was taken during load phase.
+// Samples without phase, e.g. of reports made by previous versions, belong to load phase
+func (p *Page) isLoadSample(i int) bool {
+	if i >= len(p.SamplePhases) {
+		return true
+	}
+	return p.SamplePhases[i] != PhaseBurst && p.SamplePhases[i] != PhaseCalibrate
+}
+
+// phaseBands returns plot bands of consecutive samples of the same phase, which adjoin each other.
+// Band of phase starts at the last sample of previous phase, since sample covers period before it.
+// Bands are omitted if all samples belong to the same phase
+func (p *Page) phaseBands() []plotBand {
+	if len(p.SamplePhases) == 0 {
+		return nil
+	}
+	var bands []plotBand
+	from := math.Max(p.sampleTime(0)-p.samplePeriod(0), 0)
+	loadBands := 0
+	for i := 0; i < len(p.SamplePhases); {
+		j := i
+		for j < len(p.SamplePhases) && p.SamplePhases[j] == p.SamplePhases[i] {
+			j++
+		}
+		b := plotBand{From: from, To: p.sampleTime(j - 1), Color: "#fcf3e3"}
+		if p.isLoadSample(i) {
+			// adjoining levels of sweep are distinguished by shade
+			b.Color = [...]string{"#eef5fb", "#f7fafd"}[loadBands%2]
+			loadBands++
+		}
+		b.Label.Text = p.SamplePhases[i]
+		bands = append(bands, b)
+		from, i = b.To, j
+	}
+	if len(bands) < 2 {
+		return nil
+	}
+	return bands
+}
+
+// loadLatencyOverConnections returns number of connections and p99 latency of samples of load phase
+func (p *Page) loadLatencyOverConnections() ([]uint64, []float64) {
+	durations := p.durations(0.99)
+	var conns []uint64
+	var p99 []float64
+	for i := range p.Connections {
+		if i < len(durations) && p.isLoadSample(i) {
+			conns = append(conns, p.Connections[i])
+			p99 = append(p99, durations[i])
+		}
+	}
+	return conns, p99
+}
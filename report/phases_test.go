@@ -0,0 +1,39 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPhaseBands(t *testing.T) {
+	f := func(timestamps []float64, phases []string, expected string) {
+		t.Helper()
+		p := &Page{Interval: 1, Timestamps: timestamps, SamplePhases: phases}
+		if got := marshalPlotOptions(p.phaseBands()); got != expected {
+			t.Errorf("Unexpected bands.\nGot:      %s\nExpected: %s", got, expected)
+		}
+	}
+
+	f(nil, nil, "null")
+	f([]float64{1, 2, 3}, []string{"steady", "steady", "steady"}, "null")
+	f([]float64{4, 5, 6, 7, 8}, []string{PhaseCalibrate, PhaseCalibrate, "steady", "steady", "steady"},
+		`[{"from":3,"to":5,"color":"#fcf3e3","label":{"text":"calibrate"}},`+
+			`{"from":5,"to":8,"color":"#eef5fb","label":{"text":"steady"}}]`)
+	f([]float64{1, 2, 3}, []string{"50 clients", "100 clients", "200 clients"},
+		`[{"from":0,"to":1,"color":"#eef5fb","label":{"text":"50 clients"}},`+
+			`{"from":1,"to":2,"color":"#f7fafd","label":{"text":"100 clients"}},`+
+			`{"from":2,"to":3,"color":"#eef5fb","label":{"text":"200 clients"}}]`)
+}
+
+func TestLoadLatencyOverConnections(t *testing.T) {
+	p := &Page{
+		Connections:     []uint64{10, 20, 30, 40},
+		RequestSum:      []uint64{100, 200, 300, 400},
+		RequestDuration: map[float64][]float64{0.99: {0.1, 0.2, 0.3, 0.4}},
+		SamplePhases:    []string{PhaseCalibrate, PhaseCalibrate, "steady"},
+	}
+	conns, p99 := p.loadLatencyOverConnections()
+	if !reflect.DeepEqual(conns, []uint64{30, 40}) || !reflect.DeepEqual(p99, []float64{0.3, 0.4}) {
+		t.Errorf("Unexpected samples of load phase. Got: %v, %v; Expected: [30 40], [0.3 0.4]", conns, p99)
+	}
+}
@@ -0,0 +1,196 @@
+package report
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// rawSamples contains all series of report, so they could be analyzed without re-running test
+type rawSamples struct {
+	// Interval is a period between samples in seconds
+	Interval float64 `json:"interval"`
+
+	// Timestamps contains time of every sample in seconds since test start.
+	// If omitted, samples are evenly spaced by interval
+	Timestamps []float64 `json:"timestamps,omitempty"`
+
+	// SamplePhases contains name of phase of every sample. Is omitted if phases weren't tracked
+	SamplePhases []string `json:"sample_phases,omitempty"`
+
+	Connections    []uint64 `json:"connections"`
+	RequestSum     []uint64 `json:"request_sum"`
+	RequestSuccess []uint64 `json:"request_success"`
+	Errors         []uint64 `json:"errors"`
+	Timeouts       []uint64 `json:"timeouts"`
+	Qps            []uint64 `json:"qps"`
+	BytesWritten   []uint64 `json:"bytes_written"`
+	BytesRead      []uint64 `json:"bytes_read"`
+
+	// RequestDuration maps quantile to its values in seconds.
+	// Quantiles without observations are null
+	RequestDuration map[string][]*float64 `json:"request_duration"`
+
+	// StatusCodes maps status code to percent of requests
+	StatusCodes   map[string]float64 `json:"status_codes"`
+	ErrorMessages map[string]int     `json:"error_messages"`
+
+	// StatusCounts maps status code to number of responses
+	StatusCounts map[int]uint64 `json:"status_counts"`
+
+	// StatusCodeSeries maps status code to number of its responses at every sample
+	StatusCodeSeries map[int][]uint64 `json:"status_code_series,omitempty"`
+
+	// ErrorClassSeries maps class of errors to number of its errors at every sample. Is omitted if there were no errors
+	ErrorClassSeries map[string][]uint64 `json:"error_class_series,omitempty"`
+
+	// FailedRequests are captured failed requests with their responses. Is omitted unless they are captured
+	FailedRequests []FailedRequest `json:"failed_requests,omitempty"`
+
+	// GRPCStatusCodes maps name of gRPC status to percent of requests. Is omitted if load isn't gRPC
+	GRPCStatusCodes map[string]float64 `json:"grpc_status_codes,omitempty"`
+
+	// Backends maps id of backend to percent of requests. Is omitted if backend ids aren't collected
+	Backends map[string]float64 `json:"backends,omitempty"`
+
+	// AssertionFailures maps assertion to number of responses failed it. Is omitted if responses aren't asserted
+	AssertionFailures map[string]uint64 `json:"assertion_failures,omitempty"`
+
+	// Latency contains percentiles and max of latency of load phase. Is omitted if no requests were done
+	Latency *Latency `json:"latency,omitempty"`
+
+	// Targets contains number of requests and errors of every url. Is omitted if single url is requested
+	Targets []TargetStat `json:"targets,omitempty"`
+
+	// TargetDuration maps url to its p99 latency in seconds over samples. Null values are samples without latency.
+	// Is omitted if single url is requested
+	TargetDuration map[string][]*float64 `json:"target_duration,omitempty"`
+
+	// LatencyBySize contains latency of responses by range of body size. Is omitted if it isn't collected
+	LatencyBySize []SizeLatency `json:"latency_by_size,omitempty"`
+
+	// FirstRequestLatency contains percentiles and max of latency of the first requests on connections.
+	// Is omitted unless first requests are separated
+	FirstRequestLatency *Latency `json:"first_request_latency,omitempty"`
+
+	// CorrectedLatency contains percentiles and max of latency measured from intended send time of requests.
+	// Is omitted unless it is recorded
+	CorrectedLatency *Latency `json:"corrected_latency,omitempty"`
+
+	// ConnectLatency and HandshakeLatency contain percentiles and max of time of TCP connect
+	// and TLS handshake. Are omitted unless connection setup is measured
+	ConnectLatency   *Latency `json:"connect_latency,omitempty"`
+	HandshakeLatency *Latency `json:"handshake_latency,omitempty"`
+
+	// LatencyBreakdown contains latency of every stage of requests. Is omitted unless latency is broken down
+	LatencyBreakdown []StageLatency `json:"latency_breakdown,omitempty"`
+
+	// LatencyByRegion contains latency of responses by simulated region of clients. Is omitted unless regions are simulated
+	LatencyByRegion []RegionLatency `json:"latency_by_region,omitempty"`
+
+	// Phases contains summaries of every finished phase of test
+	Phases []Phase `json:"phases"`
+
+	// Abort describes why load phase was stopped early. Is omitted if it wasn't aborted
+	Abort *Abort `json:"abort,omitempty"`
+
+	// Drifts are trends of latency percentiles and errors rate over steady phase. Is omitted unless trends are analyzed
+	Drifts []Drift `json:"drifts,omitempty"`
+
+	// GeneratorBottleneck describes why load generator rather than target limited load phase. Is omitted if loader kept up
+	GeneratorBottleneck string `json:"generator_bottleneck,omitempty"`
+
+	// Metadata contains version, seed and configuration of test. Is omitted if unknown
+	Metadata *Metadata `json:"metadata,omitempty"`
+}
+
+// Abort describes stop of load phase by stop condition
+type Abort struct {
+	// Reason is a violated stop condition like "errors rate 35.20 % exceeds 10.00 %"
+	Reason string `json:"reason"`
+	// After is a duration of load phase in seconds before it was aborted
+	After float64 `json:"after"`
+	// QPS is a qps limit, at which target began to collapse
+	QPS float64 `json:"qps"`
+}
+
+// PrintJSON returns all series, percentiles and summaries of phases of report as JSON
+func PrintJSON(p *Page) string {
+	return p.rawSamplesJSON()
+}
+
+// rawSamplesJSON returns all series of report as JSON
+func (p *Page) rawSamplesJSON() string {
+	b, err := p.MarshalJSON()
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+// MarshalJSON implements json.Marshaler. Result has the same schema as JSON report
+func (p *Page) MarshalJSON() ([]byte, error) {
+	raw := rawSamples{
+		Interval:        p.Interval,
+		Timestamps:      p.Timestamps,
+		SamplePhases:    p.SamplePhases,
+		Connections:     p.Connections,
+		RequestSum:      p.RequestSum,
+		RequestSuccess:  p.RequestSuccess,
+		Errors:          p.Errors,
+		Timeouts:        p.Timeouts,
+		Qps:             p.Qps,
+		BytesWritten:    p.BytesWritten,
+		BytesRead:       p.BytesRead,
+		RequestDuration: make(map[string][]*float64, len(p.RequestDuration)),
+		StatusCodes:     p.StatusCodes,
+		ErrorMessages:   p.ErrorMessages,
+		StatusCounts:    p.StatusCounts,
+		GRPCStatusCodes: p.GRPCStatusCodes,
+		Backends:        p.Backends,
+		Latency:         p.Latency,
+		Targets:         p.Targets,
+		LatencyBySize:   p.LatencyBySize,
+
+		StatusCodeSeries:  p.StatusCodeSeries,
+		AssertionFailures: p.AssertionFailures,
+		ErrorClassSeries:  p.ErrorClassSeries,
+		FailedRequests:    p.FailedRequests,
+
+		FirstRequestLatency: p.FirstRequestLatency,
+		CorrectedLatency:    p.CorrectedLatency,
+		ConnectLatency:      p.ConnectLatency,
+		HandshakeLatency:    p.HandshakeLatency,
+		LatencyBreakdown:    p.LatencyBreakdown,
+		LatencyByRegion:     p.LatencyByRegion,
+		Phases:              p.Phases,
+		Abort:               p.Abort,
+		Drifts:              p.Drifts,
+
+		GeneratorBottleneck: p.GeneratorBottleneck,
+		Metadata:            p.Metadata,
+	}
+	for q, values := range p.RequestDuration {
+		raw.RequestDuration[strconv.FormatFloat(q, 'f', -1, 64)] = nullableSeries(values)
+	}
+	if len(p.TargetDuration) > 0 {
+		raw.TargetDuration = make(map[string][]*float64, len(p.TargetDuration))
+		for name, values := range p.TargetDuration {
+			raw.TargetDuration[name] = nullableSeries(values)
+		}
+	}
+
+	// json escapes <, > and &, so result is safe to embed into script tag
+	return json.Marshal(raw)
+}
+
+// nullableSeries returns pointers to values of series, which are nil for NaN values, since NaN can't be marshaled
+func nullableSeries(values []float64) []*float64 {
+	sl := make([]*float64, len(values))
+	for i := range values {
+		if !math.IsNaN(values[i]) {
+			sl[i] = &values[i]
+		}
+	}
+	return sl
+}
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestPrintJSON(t *testing.T) {
+	p := &Page{
+		Interval:        0.5,
+		Qps:             []uint64{100, 200},
+		RequestDuration: map[float64][]float64{0.99: {math.NaN(), 0.2}},
+		Latency:         &Latency{P50: 0.1, P90: 0.15, P99: 0.2, Max: 0.3},
+		Phases:          []Phase{{Name: "steady", Requests: 300, Rps: 150, P50: 0.1, P99: 0.2, Connections: 10}},
+		TargetDuration:  map[string][]float64{"/items": {0.3, math.NaN()}},
+	}
+	var raw rawSamples
+	if err := json.Unmarshal([]byte(PrintJSON(p)), &raw); err != nil {
+		t.Fatalf("cannot parse JSON report: %s", err)
+	}
+	if raw.Interval != 0.5 || len(raw.Qps) != 2 || *raw.Latency != *p.Latency {
+		t.Errorf("Unexpected report: %+v", raw)
+	}
+	if d := raw.RequestDuration["0.99"]; len(d) != 2 || d[0] != nil || *d[1] != 0.2 {
+		t.Errorf("Unexpected p99 series: %v; Expected: [null 0.2]", d)
+	}
+	if d := raw.TargetDuration["/items"]; len(d) != 2 || *d[0] != 0.3 || d[1] != nil {
+		t.Errorf("Unexpected p99 series of /items: %v; Expected: [0.3 null]", d)
+	}
+	if len(raw.Phases) != 1 || raw.Phases[0] != p.Phases[0] {
+		t.Errorf("Unexpected phases: %+v; Expected: %+v", raw.Phases, p.Phases)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	p := &Page{Interval: 0.5, Connections: []uint64{1}, RequestSum: []uint64{10}}
+	for format := range Formats {
+		b, err := Marshal(p, format)
+		if err != nil {
+			t.Fatalf("cannot marshal report as %s: %s", format, err)
+		}
+		if len(b) == 0 {
+			t.Errorf("report as %s is empty", format)
+		}
+	}
+	if _, err := Marshal(p, "xml"); err == nil {
+		t.Errorf("expected error for unsupported format")
+	}
+}
+
+func TestPrintPageRawSamples(t *testing.T) {
+	p := &Page{
+		Interval:          0.5,
+		Qps:               []uint64{100, 200},
+		RequestDuration:   map[float64][]float64{0.5: {0.1, 0.1}, 0.999: {0.3, 0.4}},
+		ErrorMessages:     map[string]int{"</script><script>alert(1)</script>": 1},
+		IncludeRawSamples: true,
+	}
+	html := PrintPage(p)
+	m := regexp.MustCompile(`(?s)<script type="application/json" id="raw-samples">(.*?)</script>`).FindStringSubmatch(html)
+	if m == nil {
+		t.Fatalf("raw samples aren't embedded into report")
+	}
+	var raw rawSamples
+	if err := json.Unmarshal([]byte(m[1]), &raw); err != nil {
+		t.Fatalf("cannot parse embedded samples: %s", err)
+	}
+	if len(raw.Qps) != 2 || len(raw.ErrorMessages) != 1 {
+		t.Errorf("Unexpected embedded samples: %+v", raw)
+	}
+	for _, s := range []string{`<option value="0.5">p50</option>`, `<option value="0.999">p99.9</option>`, "raw-samples-download"} {
+		if !strings.Contains(html, s) {
+			t.Errorf("report doesn't contain %s", s)
+		}
+	}
+
+	p.IncludeRawSamples = false
+	if html := PrintPage(p); strings.Contains(html, "raw-samples") {
+		t.Errorf("raw samples are embedded into report, while they mustn't")
+	}
+}
@@ -0,0 +1,25 @@
+package report
+
+// RegionLatency contains latency of responses received by simulated region of clients
+type RegionLatency struct {
+	Region string `json:"region"`
+
+	// RTT is an injected round-trip time of region like "90ms"
+	RTT string `json:"rtt"`
+
+	Requests uint64 `json:"requests"`
+
+	// P50, P90 and P99 are measured in seconds
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+// formatRegionLatency formats latency of region in unit of report.
+// Latency of regions with less than MinSamples requests isn't displayed
+func (p *Page) formatRegionLatency(rl RegionLatency, seconds float64) string {
+	if rl.Requests < p.MinSamples {
+		return "insufficient data"
+	}
+	return FormatLatency(seconds, p.latencyUnit())
+}
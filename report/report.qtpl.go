@@ -13,18 +13,17 @@ import (
 
 //line report/report.qtpl:1
 import (
-	"sort"
 	"strings"
 	"sync"
 )
 
-//line report/report.qtpl:7
+//line report/report.qtpl:6
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line report/report.qtpl:8
+//line report/report.qtpl:7
 type Page struct {
 	// Title displayed in title of generated report
 	Title string
@@ -32,6 +31,14 @@ type Page struct {
 	// Step is measured in ms and used for TickInterval in charts
 	Interval float64
 
+	// Timestamps contains time of every sample in seconds since test start.
+	// Charts are plotted against it, since samples may be taken with delays.
+	// If empty, samples are considered as evenly spaced by Interval
+	Timestamps []float64
+
+	// SamplePhases contains name of phase of every sample like "burst", "calibrate" or "steady"
+	SamplePhases []string
+
 	sync.Mutex
 	Connections     []uint64
 	RequestSum      []uint64
@@ -43,49 +50,167 @@ type Page struct {
 	BytesRead       []uint64
 	RequestDuration map[float64][]float64
 	StatusCodes     map[string]float64
-	ErrorMessages   map[string]int
+	// StatusCounts maps status code to number of responses
+	StatusCounts map[int]uint64
+	// StatusCodeSeries maps status code to number of its responses at every sample
+	StatusCodeSeries map[int][]uint64
+	ErrorMessages    map[string]int
+
+	// ErrorClassSeries maps class of errors like "timeout" to number of its errors at every sample
+	ErrorClassSeries map[string][]uint64
+
+	// GRPCStatusCodes maps name of gRPC status to percent of requests. Is empty if load isn't gRPC
+	GRPCStatusCodes map[string]float64
+
+	// Sweep contains results of every level of concurrency or body size sweep or of comparison of protocols. Is empty if sweep isn't run
+	Sweep []SweepLevel
+
+	// SweepOf is "clients" for concurrency sweep, "body-size" for body size sweep and "protocol" for -compare-protocols
+	SweepOf string
+
+	// Backends maps id of backend to percent of requests served by it. Is empty if backend ids aren't collected
+	Backends map[string]float64
+
+	// AssertionFailures maps assertion to number of responses failed it. Is empty if responses aren't asserted
+	AssertionFailures map[string]uint64
+
+	// Latency contains percentiles and max of latency of load phase. Is nil if no requests were done
+	Latency *Latency
+
+	// FirstRequestLatency contains percentiles and max of latency of the first requests on connections
+	// of load phase, which are excluded from Latency. Is nil unless first requests are separated
+	FirstRequestLatency *Latency
+
+	// FirstRequestDuration maps quantile to its values in seconds for the first requests on connections,
+	// which are excluded from RequestDuration. Is empty unless first requests are separated
+	FirstRequestDuration map[float64][]float64
+
+	// CorrectedLatency contains percentiles and max of latency of load phase measured from intended
+	// send time of requests, so it isn't hidden by coordinated omission. Is nil unless it is recorded
+	CorrectedLatency *Latency
+
+	// CorrectedDuration maps quantile to its values in seconds of latency measured from intended
+	// send time of requests. Is empty unless corrected latency is recorded
+	CorrectedDuration map[float64][]float64
+
+	// ConnectDuration and HandshakeDuration map quantile to its values in seconds for TCP connect
+	// and TLS handshake of connections. Are empty unless connection setup is measured
+	ConnectDuration   map[float64][]float64
+	HandshakeDuration map[float64][]float64
+
+	// ConnectLatency and HandshakeLatency contain percentiles and max of time of TCP connect
+	// and TLS handshake. Are nil unless connection setup is measured or connections weren't established
+	ConnectLatency   *Latency
+	HandshakeLatency *Latency
+
+	// BreakdownSeries maps part of latency like "time to first byte" to its average values in seconds.
+	// Is empty unless latency is broken down
+	BreakdownSeries map[string][]float64
+
+	// LatencyBreakdown contains latency of every stage of requests in order of request.
+	// Is empty unless latency is broken down
+	LatencyBreakdown []StageLatency
+
+	// Targets contains number of requests and errors of every url. Is empty if single url is requested
+	Targets []TargetStat
+
+	// TargetDuration maps url to its p99 latency in seconds over samples. Is empty if single url is requested
+	TargetDuration map[string][]float64
+
+	// LatencyBySize contains latency of responses by range of body size, ordered by size
+	LatencyBySize []SizeLatency
+
+	// LatencyByRegion contains latency of responses by simulated region of clients. Is empty unless regions are simulated
+	LatencyByRegion []RegionLatency
+
+	// Phases contains summaries of every finished phase of test
+	Phases []Phase
+
+	// Abort describes why load phase was stopped early by stop condition. Is nil if it wasn't aborted
+	Abort *Abort
+
+	// InjectedFaults describes faults injected by client like "latency 50ms per request".
+	// Is empty if faults weren't injected
+	InjectedFaults string
+
+	// GeneratorBottleneck describes why load generator rather than target limited load phase,
+	// like "loader CPU usage exceeded 90 % in 12 of 40 samples". Is empty if loader kept up
+	GeneratorBottleneck string
+
+	// ThroughputDegradation is a decline of achieved rps in percents over load phase
+	// with constant offered load. Is zero if throughput didn't degrade significantly
+	ThroughputDegradation float64
+
+	// Drifts are trends of latency percentiles and errors rate over load phase with constant offered load.
+	// Is empty unless trends are analyzed
+	Drifts []Drift
+
+	// LatencyPerspective is "client" if latency includes wait in job queue and "server" otherwise
+	LatencyPerspective string
+
+	// MinSamples is a min number of requests, which is required to display latency percentiles
+	MinSamples uint64
+
+	// LatencyUnit is a unit in which latency is displayed: s, ms or us
+	LatencyUnit string
+
+	// IncludeRawSamples embeds all series as downloadable JSON into report
+	IncludeRawSamples bool
+
+	// IncidentThreshold is a percent of errors, exceeding which is reported as incident.
+	// Zero disables incidents reporting
+	IncidentThreshold float64
+
+	// FailedRequests are captured failed requests with their responses. Is empty unless they are captured
+	FailedRequests []FailedRequest
+
+	// Annotations are displayed as vertical lines on charts with time axis
+	Annotations []Annotation
+
+	// Metadata contains version, seed and configuration of test, so it could be reproduced. Is nil if unknown
+	Metadata *Metadata
 }
 
 type seriesFunc func() string
 
-//line report/report.qtpl:32
+//line report/report.qtpl:157
 func (p *Page) streamtitle(qw422016 *qt422016.Writer) {
-//line report/report.qtpl:32
+//line report/report.qtpl:157
 qw422016.E().S(p.Title) }
 
-//line report/report.qtpl:32
-//line report/report.qtpl:32
+//line report/report.qtpl:157
+//line report/report.qtpl:157
 func (p *Page) writetitle(qq422016 qtio422016.Writer) {
-	//line report/report.qtpl:32
+	//line report/report.qtpl:157
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:32
+	//line report/report.qtpl:157
 	p.streamtitle(qw422016)
-	//line report/report.qtpl:32
+	//line report/report.qtpl:157
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:32
+//line report/report.qtpl:157
 }
 
-//line report/report.qtpl:32
+//line report/report.qtpl:157
 func (p *Page) title() string {
-	//line report/report.qtpl:32
+	//line report/report.qtpl:157
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:32
+	//line report/report.qtpl:157
 	p.writetitle(qb422016)
-	//line report/report.qtpl:32
+	//line report/report.qtpl:157
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:32
+	//line report/report.qtpl:157
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:32
+	//line report/report.qtpl:157
 	return qs422016
-//line report/report.qtpl:32
+//line report/report.qtpl:157
 }
 
-//line report/report.qtpl:34
+//line report/report.qtpl:159
 func (p *Page) StreamUpdateRequestDuration(qw422016 *qt422016.Writer, d map[float64]float64) {
-	//line report/report.qtpl:34
+	//line report/report.qtpl:159
 	qw422016.N().S(`
 	`)
-	//line report/report.qtpl:36
+	//line report/report.qtpl:161
 	for k, v := range d {
 		if _, ok := p.RequestDuration[k]; !ok {
 			p.RequestDuration[k] = make([]float64, 0)
@@ -94,243 +219,735 @@ func (p *Page) StreamUpdateRequestDuration(qw422016 *qt422016.Writer, d map[floa
 		p.RequestDuration[k] = append(p.RequestDuration[k], v)
 	}
 
-	//line report/report.qtpl:43
+	//line report/report.qtpl:168
 	qw422016.N().S(`
 `)
-//line report/report.qtpl:44
+//line report/report.qtpl:169
 }
 
-//line report/report.qtpl:44
+//line report/report.qtpl:169
 func (p *Page) WriteUpdateRequestDuration(qq422016 qtio422016.Writer, d map[float64]float64) {
-	//line report/report.qtpl:44
+	//line report/report.qtpl:169
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:44
+	//line report/report.qtpl:169
 	p.StreamUpdateRequestDuration(qw422016, d)
-	//line report/report.qtpl:44
+	//line report/report.qtpl:169
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:44
+//line report/report.qtpl:169
 }
 
-//line report/report.qtpl:44
+//line report/report.qtpl:169
 func (p *Page) UpdateRequestDuration(d map[float64]float64) string {
-	//line report/report.qtpl:44
+	//line report/report.qtpl:169
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:44
+	//line report/report.qtpl:169
 	p.WriteUpdateRequestDuration(qb422016, d)
-	//line report/report.qtpl:44
+	//line report/report.qtpl:169
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:44
+	//line report/report.qtpl:169
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:44
+	//line report/report.qtpl:169
 	return qs422016
-//line report/report.qtpl:44
+//line report/report.qtpl:169
 }
 
-//line report/report.qtpl:46
+//line report/report.qtpl:171
 func StreamPrintPage(qw422016 *qt422016.Writer, p *Page) {
-	//line report/report.qtpl:46
+	//line report/report.qtpl:171
 	qw422016.N().S(`
 <html>
 	<head>
 		<title>`)
-	//line report/report.qtpl:49
+	//line report/report.qtpl:174
 	p.streamtitle(qw422016)
-	//line report/report.qtpl:49
+	//line report/report.qtpl:174
 	qw422016.N().S(`</title>
 		<script type="text/javascript" src="https://ajax.googleapis.com/ajax/libs/jquery/3.1.0/jquery.min.js"></script>
 		<script type="text/javascript" src="https://code.highcharts.com/highcharts.js"></script>
 		<script type="text/javascript" src="https://code.highcharts.com/modules/exporting.js"></script>
 		<script type="text/javascript">`)
-	//line report/report.qtpl:53
+	//line report/report.qtpl:178
 	qw422016.N().Z(MustAsset("report/static/js/utils.js"))
-	//line report/report.qtpl:53
+	//line report/report.qtpl:178
 	qw422016.N().S(`</script>
 		<style>`)
-	//line report/report.qtpl:54
+	//line report/report.qtpl:179
 	qw422016.N().Z(MustAsset("report/static/css/main.css"))
-	//line report/report.qtpl:54
+	//line report/report.qtpl:179
 	qw422016.N().S(`</style>
+		`)
+	//line report/report.qtpl:180
+	streamchartDefaults(qw422016)
+	//line report/report.qtpl:180
+	qw422016.N().S(`
 	</head>
 	 <body>
 		`)
-	//line report/report.qtpl:57
+	//line report/report.qtpl:183
+	if p.Metadata != nil {
+		//line report/report.qtpl:183
+		qw422016.N().S(`
+		<p style="text-align: center;">fasthttploader `)
+		//line report/report.qtpl:184
+		qw422016.E().S(p.Metadata.Version)
+		//line report/report.qtpl:184
+		qw422016.N().S(`; seed `)
+		//line report/report.qtpl:184
+		qw422016.N().D(int(p.Metadata.Seed))
+		//line report/report.qtpl:184
+		qw422016.N().S(`</p>
+		`)
+		//line report/report.qtpl:185
+	}
+	//line report/report.qtpl:185
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:186
+	if p.Abort != nil {
+		//line report/report.qtpl:186
+		qw422016.N().S(`
+		<p style="text-align: center; color: #c0392b;">Load phase was aborted after `)
+		//line report/report.qtpl:187
+		qw422016.N().FPrec(p.Abort.After, 1)
+		//line report/report.qtpl:187
+		qw422016.N().S(`s: `)
+		//line report/report.qtpl:187
+		qw422016.E().S(p.Abort.Reason)
+		//line report/report.qtpl:187
+		qw422016.N().S(`; collapse began at qps `)
+		//line report/report.qtpl:187
+		qw422016.N().FPrec(p.Abort.QPS, 2)
+		//line report/report.qtpl:187
+		qw422016.N().S(`</p>
+		`)
+		//line report/report.qtpl:188
+	}
+	//line report/report.qtpl:188
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:189
+	if p.InjectedFaults != "" {
+		//line report/report.qtpl:189
+		qw422016.N().S(`
+		<p style="text-align: center;">Faults were injected by client, not caused by target: `)
+		//line report/report.qtpl:190
+		qw422016.E().S(p.InjectedFaults)
+		//line report/report.qtpl:190
+		qw422016.N().S(`</p>
+		`)
+		//line report/report.qtpl:191
+	}
+	//line report/report.qtpl:191
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:192
+	if p.GeneratorBottleneck != "" {
+		//line report/report.qtpl:192
+		qw422016.N().S(`
+		<p style="text-align: center; color: #c0392b;">Load generator rather than target was the bottleneck, so results don't reflect capacity of target: `)
+		//line report/report.qtpl:193
+		qw422016.E().S(p.GeneratorBottleneck)
+		//line report/report.qtpl:193
+		qw422016.N().S(`</p>
+		`)
+		//line report/report.qtpl:194
+	}
+	//line report/report.qtpl:194
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:195
+	if p.ThroughputDegradation > 0 {
+		//line report/report.qtpl:195
+		qw422016.N().S(`
+		<p style="text-align: center;">Throughput degraded `)
+		//line report/report.qtpl:196
+		qw422016.N().FPrec(p.ThroughputDegradation, 2)
+		//line report/report.qtpl:196
+		qw422016.N().S(`% over the steady phase</p>
+		`)
+		//line report/report.qtpl:197
+	}
+	//line report/report.qtpl:197
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:198
 	p.streamsimpleChart(qw422016, "connections", p.connectionSeries)
-	//line report/report.qtpl:57
+	//line report/report.qtpl:198
 	qw422016.N().S(`
 		`)
-	//line report/report.qtpl:58
+	//line report/report.qtpl:199
 	p.streamsimpleChart(qw422016, "qps", p.qpsSeries)
-	//line report/report.qtpl:58
+	//line report/report.qtpl:199
 	qw422016.N().S(`
 		`)
-	//line report/report.qtpl:59
+	//line report/report.qtpl:200
 	p.streamsimpleChart(qw422016, "errors-vs-timeouts", p.errorSeries)
-	//line report/report.qtpl:59
+	//line report/report.qtpl:200
 	qw422016.N().S(`
 		`)
-	//line report/report.qtpl:60
+	//line report/report.qtpl:201
 	p.streamsimpleChart(qw422016, "latency", p.durationSeries)
-	//line report/report.qtpl:60
+	//line report/report.qtpl:201
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:202
+	p.streamquantileSelector(qw422016)
+	//line report/report.qtpl:202
+	qw422016.N().S(`
+		<p style="text-align: center;">`)
+	//line report/report.qtpl:203
+	qw422016.E().S(p.latencyPerspective())
+	//line report/report.qtpl:203
+	qw422016.N().S(`</p>
+		`)
+	//line report/report.qtpl:204
+	if p.hasInsufficientSamples() {
+		//line report/report.qtpl:204
+		qw422016.N().S(`
+		<p style="text-align: center;">Latency isn't displayed for samples with less than `)
+		//line report/report.qtpl:205
+		qw422016.N().D(int(p.MinSamples))
+		//line report/report.qtpl:205
+		qw422016.N().S(` requests: insufficient data</p>
+		`)
+		//line report/report.qtpl:206
+	}
+	//line report/report.qtpl:206
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:207
+	if len(p.ConnectDuration) > 0 {
+		//line report/report.qtpl:207
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:208
+		p.streamsimpleChart(qw422016, "connection-setup", p.connSetupSeries)
+		//line report/report.qtpl:208
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:209
+	}
+	//line report/report.qtpl:209
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:210
+	if len(p.BreakdownSeries) > 0 {
+		//line report/report.qtpl:210
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:211
+		p.streamsimpleChart(qw422016, "latency-breakdown", p.breakdownSeries)
+		//line report/report.qtpl:211
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:212
+	}
+	//line report/report.qtpl:212
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:213
+	p.streamscatterChart(qw422016, "latency-over-connections", "Connections", "p99 latency, "+p.latencyUnit(), p.latencyOverConnectionsSeries)
+	//line report/report.qtpl:213
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:214
+	if len(p.Sweep) > 0 && p.SweepOf != "protocol" {
+		//line report/report.qtpl:214
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:215
+		p.streamscatterChart(qw422016, "rps-over-"+p.SweepOf, p.sweepAxis(), "Rps", p.sweepRpsSeries)
+		//line report/report.qtpl:215
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:216
+		p.streamscatterChart(qw422016, "latency-over-"+p.SweepOf, p.sweepAxis(), "p99 latency, "+p.latencyUnit(), p.sweepLatencySeries)
+		//line report/report.qtpl:216
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:217
+	}
+	//line report/report.qtpl:217
 	qw422016.N().S(`
 		`)
-	//line report/report.qtpl:61
+	//line report/report.qtpl:218
 	p.streambytesChart(qw422016, "written-vs-read", p.bytesSeries)
-	//line report/report.qtpl:61
+	//line report/report.qtpl:218
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:219
+	if len(p.ErrorClassSeries) > 0 {
+		//line report/report.qtpl:219
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:220
+		p.streamstackedChart(qw422016, "error-classes-over-time", p.errorClassRateSeries)
+		//line report/report.qtpl:220
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:221
+	}
+	//line report/report.qtpl:221
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:222
+	if len(p.StatusCodeSeries) > 0 {
+		//line report/report.qtpl:222
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:223
+		p.streamstackedChart(qw422016, "status-codes-over-time", p.statusCodeRateSeries)
+		//line report/report.qtpl:223
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:224
+	}
+	//line report/report.qtpl:224
 	qw422016.N().S(`
 		`)
-	//line report/report.qtpl:62
+	//line report/report.qtpl:225
 	p.streampieChart(qw422016, "status-codes", p.statusCodesSeries)
-	//line report/report.qtpl:62
+	//line report/report.qtpl:225
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:226
+	if len(p.StatusCounts) > 0 {
+		//line report/report.qtpl:226
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:227
+		p.streamstatusCountsTable(qw422016)
+		//line report/report.qtpl:227
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:228
+	}
+	//line report/report.qtpl:228
 	qw422016.N().S(`
 		`)
-	//line report/report.qtpl:63
+	//line report/report.qtpl:229
 	p.streamerrorMessagesTable(qw422016)
-	//line report/report.qtpl:63
+	//line report/report.qtpl:229
 	qw422016.N().S(`
-	</body>
-</html>
-`)
-//line report/report.qtpl:66
-}
-
-//line report/report.qtpl:66
-func WritePrintPage(qq422016 qtio422016.Writer, p *Page) {
-	//line report/report.qtpl:66
-	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:66
-	StreamPrintPage(qw422016, p)
-	//line report/report.qtpl:66
-	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:66
-}
-
-//line report/report.qtpl:66
-func PrintPage(p *Page) string {
-	//line report/report.qtpl:66
-	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:66
-	WritePrintPage(qb422016, p)
-	//line report/report.qtpl:66
-	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:66
-	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:66
-	return qs422016
-//line report/report.qtpl:66
-}
-
-//line report/report.qtpl:68
-func (p *Page) streamsimpleChart(qw422016 *qt422016.Writer, title string, fn seriesFunc) {
-	//line report/report.qtpl:68
+		`)
+	//line report/report.qtpl:230
+	if len(p.AssertionFailures) > 0 {
+		//line report/report.qtpl:230
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:231
+		p.streamassertionFailuresTable(qw422016)
+		//line report/report.qtpl:231
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:232
+	}
+	//line report/report.qtpl:232
 	qw422016.N().S(`
-	<script>
-	$(function () {
-    			$('#`)
-	//line report/report.qtpl:71
-	qw422016.N().S(title)
-	//line report/report.qtpl:71
-	qw422016.N().S(`').highcharts({
-					title: {
-						text: '`)
-	//line report/report.qtpl:73
-	qw422016.N().S(strings.Title(title))
-	//line report/report.qtpl:73
-	qw422016.N().S(`',
-						x: -20 //center
-					},
-					xAxis: {
-						type: 'linear',
-					},
-					legend: {
-						layout: 'vertical',
-						align: 'right',
-						verticalAlign: 'middle',
-						borderWidth: 0
-					},
-					plotOptions: {
-						series: {
-							pointStart: 0,
-							pointInterval: `)
-	//line report/report.qtpl:88
-	qw422016.N().FPrec(p.Interval, 2)
-	//line report/report.qtpl:88
-	qw422016.N().S(`,
-						}
-					},
-					series: `)
-	//line report/report.qtpl:91
-	qw422016.N().S(fn())
-	//line report/report.qtpl:91
+		`)
+	//line report/report.qtpl:233
+	if p.Latency != nil {
+		//line report/report.qtpl:233
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:234
+		p.streamlatencyTable(qw422016)
+		//line report/report.qtpl:234
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:235
+	}
+	//line report/report.qtpl:235
 	qw422016.N().S(`
-				});
-    		});
-    </script>
-   	<div id="`)
-	//line report/report.qtpl:95
-	qw422016.N().S(title)
-	//line report/report.qtpl:95
-	qw422016.N().S(`" style="min-width: 310px; height: 400px; margin: 0 auto"></div>
-`)
-//line report/report.qtpl:96
-}
-
-//line report/report.qtpl:96
-func (p *Page) writesimpleChart(qq422016 qtio422016.Writer, title string, fn seriesFunc) {
-	//line report/report.qtpl:96
-	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:96
-	p.streamsimpleChart(qw422016, title, fn)
-	//line report/report.qtpl:96
-	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:96
-}
-
-//line report/report.qtpl:96
-func (p *Page) simpleChart(title string, fn seriesFunc) string {
-	//line report/report.qtpl:96
-	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:96
-	p.writesimpleChart(qb422016, title, fn)
-	//line report/report.qtpl:96
-	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:96
-	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:96
-	return qs422016
-//line report/report.qtpl:96
-}
-
-//line report/report.qtpl:98
-func (p *Page) streambytesChart(qw422016 *qt422016.Writer, title string, fn seriesFunc) {
-	//line report/report.qtpl:98
+		`)
+	//line report/report.qtpl:236
+	if len(p.Phases) > 1 {
+		//line report/report.qtpl:236
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:237
+		p.streamphasesTable(qw422016)
+		//line report/report.qtpl:237
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:238
+	}
+	//line report/report.qtpl:238
 	qw422016.N().S(`
-	<script>
-	$(function () {
+		`)
+	//line report/report.qtpl:239
+	if len(p.LatencyBreakdown) > 0 {
+		//line report/report.qtpl:239
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:240
+		p.streamlatencyBreakdownTable(qw422016)
+		//line report/report.qtpl:240
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:241
+	}
+	//line report/report.qtpl:241
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:242
+	if len(p.Targets) > 1 {
+		//line report/report.qtpl:242
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:243
+		p.streamtargetsTable(qw422016)
+		//line report/report.qtpl:243
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:244
+	}
+	//line report/report.qtpl:244
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:245
+	if len(p.TargetDuration) > 1 {
+		//line report/report.qtpl:245
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:246
+		p.streamsimpleChart(qw422016, "p99-latency-by-target", p.targetDurationSeries)
+		//line report/report.qtpl:246
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:247
+	}
+	//line report/report.qtpl:247
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:248
+	if len(p.Drifts) > 0 {
+		//line report/report.qtpl:248
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:249
+		p.streamdriftsTable(qw422016)
+		//line report/report.qtpl:249
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:250
+	}
+	//line report/report.qtpl:250
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:251
+	if len(p.LatencyBySize) > 1 {
+		//line report/report.qtpl:251
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:252
+		p.streamlatencyBySizeTable(qw422016)
+		//line report/report.qtpl:252
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:253
+	}
+	//line report/report.qtpl:253
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:254
+	if len(p.LatencyByRegion) > 0 {
+		//line report/report.qtpl:254
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:255
+		p.streamlatencyByRegionTable(qw422016)
+		//line report/report.qtpl:255
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:256
+	}
+	//line report/report.qtpl:256
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:257
+	if len(p.GRPCStatusCodes) > 0 {
+		//line report/report.qtpl:257
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:258
+		p.streampieChart(qw422016, "grpc-status-codes", p.grpcStatusCodesSeries)
+		//line report/report.qtpl:258
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:259
+	}
+	//line report/report.qtpl:259
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:260
+	if len(p.Backends) > 0 {
+		//line report/report.qtpl:260
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:261
+		p.streampieChart(qw422016, "backends", p.backendsSeries)
+		//line report/report.qtpl:261
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:262
+	}
+	//line report/report.qtpl:262
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:263
+	if p.IncidentThreshold > 0 {
+		//line report/report.qtpl:263
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:264
+		p.streamincidentsTable(qw422016)
+		//line report/report.qtpl:264
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:265
+	}
+	//line report/report.qtpl:265
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:266
+	if len(p.FailedRequests) > 0 {
+		//line report/report.qtpl:266
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:267
+		p.streamfailedRequests(qw422016)
+		//line report/report.qtpl:267
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:268
+	}
+	//line report/report.qtpl:268
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:269
+	if p.Metadata != nil {
+		//line report/report.qtpl:269
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:270
+		p.streamconfigTable(qw422016)
+		//line report/report.qtpl:270
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:271
+	}
+	//line report/report.qtpl:271
+	qw422016.N().S(`
+		`)
+	//line report/report.qtpl:272
+	if p.IncludeRawSamples {
+		//line report/report.qtpl:272
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:273
+		p.streamrawSamples(qw422016)
+		//line report/report.qtpl:273
+		qw422016.N().S(`
+		`)
+		//line report/report.qtpl:274
+	}
+	//line report/report.qtpl:274
+	qw422016.N().S(`
+	</body>
+</html>
+`)
+//line report/report.qtpl:277
+}
+
+//line report/report.qtpl:277
+func WritePrintPage(qq422016 qtio422016.Writer, p *Page) {
+	//line report/report.qtpl:277
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:277
+	StreamPrintPage(qw422016, p)
+	//line report/report.qtpl:277
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:277
+}
+
+//line report/report.qtpl:277
+func PrintPage(p *Page) string {
+	//line report/report.qtpl:277
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:277
+	WritePrintPage(qb422016, p)
+	//line report/report.qtpl:277
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:277
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:277
+	return qs422016
+//line report/report.qtpl:277
+}
+
+//line report/report.qtpl:279
+func streamchartDefaults(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:279
+	qw422016.N().S(`
+	<script>
+	Highcharts.setOptions({
+		chart: {
+			// dragging selects range to zoom, and dragging with shift pans zoomed chart
+			zoomType: 'x',
+			panning: {enabled: true, type: 'x'},
+			panKey: 'shift',
+			events: {load: addSeriesToggle}
+		}
+	});
+
+	// addSeriesToggle adds button, which hides or shows all series of chart at once.
+	// Single series are toggled by click on legend
+	function addSeriesToggle() {
+		var chart = this;
+		if (chart.series.length < 2) {
+			return;
+		}
+		var visible = true;
+		var button = chart.renderer.button('Hide all', 10, 10, function () {
+			visible = !visible;
+			$.each(chart.series, function (i, s) { s.setVisible(visible, false); });
+			chart.redraw();
+			button.attr({text: visible ? 'Hide all' : 'Show all'});
+		}).add();
+	}
+	</script>
+`)
+//line report/report.qtpl:307
+}
+
+//line report/report.qtpl:307
+func writechartDefaults(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:307
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:307
+	streamchartDefaults(qw422016)
+	//line report/report.qtpl:307
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:307
+}
+
+//line report/report.qtpl:307
+func chartDefaults() string {
+	//line report/report.qtpl:307
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:307
+	writechartDefaults(qb422016)
+	//line report/report.qtpl:307
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:307
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:307
+	return qs422016
+//line report/report.qtpl:307
+}
+
+//line report/report.qtpl:309
+func (p *Page) streamquantileSelector(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:309
+	qw422016.N().S(`
+	<p style="text-align: center;">
+		Percentile:
+		<select id="latency-quantile">
+			<option value="">all</option>
+			`)
+	//line report/report.qtpl:314
+	for _, k := range p.durationQuantiles() {
+		//line report/report.qtpl:314
+		qw422016.N().S(`
+			<option value="`)
+		//line report/report.qtpl:315
+		qw422016.N().F(k)
+		//line report/report.qtpl:315
+		qw422016.N().S(`">`)
+		//line report/report.qtpl:315
+		qw422016.E().S(quantileName(k))
+		//line report/report.qtpl:315
+		qw422016.N().S(`</option>
+			`)
+		//line report/report.qtpl:316
+	}
+	//line report/report.qtpl:316
+	qw422016.N().S(`
+		</select>
+	</p>
+	<script>
+	$(function () {
+		// series of selected quantile are shown, including ones of first requests and corrected latency
+		$('#latency-quantile').change(function () {
+			var q = $(this).val();
+			var chart = $('#latency').highcharts();
+			$.each(chart.series, function (i, s) { s.setVisible(q === '' || String(s.options.quantile) === q, false); });
+			chart.redraw();
+		});
+	});
+	</script>
+`)
+//line report/report.qtpl:330
+}
+
+//line report/report.qtpl:330
+func (p *Page) writequantileSelector(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:330
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:330
+	p.streamquantileSelector(qw422016)
+	//line report/report.qtpl:330
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:330
+}
+
+//line report/report.qtpl:330
+func (p *Page) quantileSelector() string {
+	//line report/report.qtpl:330
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:330
+	p.writequantileSelector(qb422016)
+	//line report/report.qtpl:330
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:330
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:330
+	return qs422016
+//line report/report.qtpl:330
+}
+
+//line report/report.qtpl:332
+func (p *Page) streamsimpleChart(qw422016 *qt422016.Writer, title string, fn seriesFunc) {
+	//line report/report.qtpl:332
+	qw422016.N().S(`
+	<script>
+	$(function () {
     			$('#`)
-	//line report/report.qtpl:101
+	//line report/report.qtpl:335
 	qw422016.N().S(title)
-	//line report/report.qtpl:101
+	//line report/report.qtpl:335
 	qw422016.N().S(`').highcharts({
 					title: {
 						text: '`)
-	//line report/report.qtpl:103
+	//line report/report.qtpl:337
 	qw422016.N().S(strings.Title(title))
-	//line report/report.qtpl:103
+	//line report/report.qtpl:337
 	qw422016.N().S(`',
 						x: -20 //center
 					},
 					xAxis: {
 						type: 'linear',
-					},
-					yAxis: {
-						labels: {
-							formatter: function() { return bytes(this.value, true); }
-						},
-						min: 0,
-					},
-					tooltip: {
-						enabled: true,
-						formatter: function() { return bytes(this.y, true);}
+						plotLines: `)
+	//line report/report.qtpl:342
+	qw422016.N().S(p.annotationLines())
+	//line report/report.qtpl:342
+	qw422016.N().S(`,
+						plotBands: `)
+	//line report/report.qtpl:343
+	qw422016.N().S(p.annotationBands())
+	//line report/report.qtpl:343
+	qw422016.N().S(`,
 					},
 					legend: {
 						layout: 'vertical',
@@ -342,431 +959,1317 @@ func (p *Page) streambytesChart(qw422016 *qt422016.Writer, title string, fn seri
 						series: {
 							pointStart: 0,
 							pointInterval: `)
-	//line report/report.qtpl:128
+	//line report/report.qtpl:354
 	qw422016.N().FPrec(p.Interval, 2)
-	//line report/report.qtpl:128
+	//line report/report.qtpl:354
 	qw422016.N().S(`,
 						}
 					},
 					series: `)
-	//line report/report.qtpl:131
+	//line report/report.qtpl:357
 	qw422016.N().S(fn())
-	//line report/report.qtpl:131
+	//line report/report.qtpl:357
 	qw422016.N().S(`
 				});
     		});
     </script>
    	<div id="`)
-	//line report/report.qtpl:135
+	//line report/report.qtpl:361
 	qw422016.N().S(title)
-	//line report/report.qtpl:135
+	//line report/report.qtpl:361
 	qw422016.N().S(`" style="min-width: 310px; height: 400px; margin: 0 auto"></div>
 `)
-//line report/report.qtpl:136
+//line report/report.qtpl:362
 }
 
-//line report/report.qtpl:136
-func (p *Page) writebytesChart(qq422016 qtio422016.Writer, title string, fn seriesFunc) {
-	//line report/report.qtpl:136
+//line report/report.qtpl:362
+func (p *Page) writesimpleChart(qq422016 qtio422016.Writer, title string, fn seriesFunc) {
+	//line report/report.qtpl:362
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:136
-	p.streambytesChart(qw422016, title, fn)
-	//line report/report.qtpl:136
+	//line report/report.qtpl:362
+	p.streamsimpleChart(qw422016, title, fn)
+	//line report/report.qtpl:362
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:136
+//line report/report.qtpl:362
 }
 
-//line report/report.qtpl:136
-func (p *Page) bytesChart(title string, fn seriesFunc) string {
-	//line report/report.qtpl:136
+//line report/report.qtpl:362
+func (p *Page) simpleChart(title string, fn seriesFunc) string {
+	//line report/report.qtpl:362
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:136
-	p.writebytesChart(qb422016, title, fn)
-	//line report/report.qtpl:136
+	//line report/report.qtpl:362
+	p.writesimpleChart(qb422016, title, fn)
+	//line report/report.qtpl:362
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:136
+	//line report/report.qtpl:362
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:136
+	//line report/report.qtpl:362
 	return qs422016
-//line report/report.qtpl:136
+//line report/report.qtpl:362
 }
 
-//line report/report.qtpl:138
-func (p *Page) streampieChart(qw422016 *qt422016.Writer, title string, fn seriesFunc) {
-	//line report/report.qtpl:138
+//line report/report.qtpl:364
+func (p *Page) streambytesChart(qw422016 *qt422016.Writer, title string, fn seriesFunc) {
+	//line report/report.qtpl:364
 	qw422016.N().S(`
 	<script>
 	$(function () {
     			$('#`)
-	//line report/report.qtpl:141
+	//line report/report.qtpl:367
 	qw422016.N().S(title)
-	//line report/report.qtpl:141
+	//line report/report.qtpl:367
 	qw422016.N().S(`').highcharts({
-					chart: {
-						plotBackgroundColor: null,
-						plotBorderWidth: null,
-						plotShadow: false,
-						type: 'pie'
-					},
 					title: {
 						text: '`)
-	//line report/report.qtpl:149
+	//line report/report.qtpl:369
 	qw422016.N().S(strings.Title(title))
-	//line report/report.qtpl:149
+	//line report/report.qtpl:369
 	qw422016.N().S(`',
+						x: -20 //center
 					},
-					 tooltip: {
-						pointFormat: '{series.name}: <b>{point.percentage:.1f}%</b>'
+					xAxis: {
+						type: 'linear',
+						plotLines: `)
+	//line report/report.qtpl:374
+	qw422016.N().S(p.annotationLines())
+	//line report/report.qtpl:374
+	qw422016.N().S(`,
+						plotBands: `)
+	//line report/report.qtpl:375
+	qw422016.N().S(p.annotationBands())
+	//line report/report.qtpl:375
+	qw422016.N().S(`,
+					},
+					yAxis: {
+						labels: {
+							formatter: function() { return bytes(this.value, true); }
+						},
+						min: 0,
+					},
+					tooltip: {
+						enabled: true,
+						formatter: function() { return bytes(this.y, true);}
+					},
+					legend: {
+						layout: 'vertical',
+						align: 'right',
+						verticalAlign: 'middle',
+						borderWidth: 0
 					},
 					plotOptions: {
-						pie: {
-							allowPointSelect: true,
-							cursor: 'pointer',
-							dataLabels: {
-								enabled: false
-							},
-							showInLegend: true
+						series: {
+							pointStart: 0,
+							pointInterval: `)
+	//line report/report.qtpl:396
+	qw422016.N().FPrec(p.Interval, 2)
+	//line report/report.qtpl:396
+	qw422016.N().S(`,
 						}
 					},
 					series: `)
-	//line report/report.qtpl:164
+	//line report/report.qtpl:399
 	qw422016.N().S(fn())
-	//line report/report.qtpl:164
+	//line report/report.qtpl:399
 	qw422016.N().S(`
 				});
     		});
     </script>
    	<div id="`)
-	//line report/report.qtpl:168
+	//line report/report.qtpl:403
 	qw422016.N().S(title)
-	//line report/report.qtpl:168
-	qw422016.N().S(`" style = "float: left; width:50%; height: 400px;"></div>
+	//line report/report.qtpl:403
+	qw422016.N().S(`" style="min-width: 310px; height: 400px; margin: 0 auto"></div>
 `)
-//line report/report.qtpl:169
+//line report/report.qtpl:404
 }
 
-//line report/report.qtpl:169
-func (p *Page) writepieChart(qq422016 qtio422016.Writer, title string, fn seriesFunc) {
-	//line report/report.qtpl:169
+//line report/report.qtpl:404
+func (p *Page) writebytesChart(qq422016 qtio422016.Writer, title string, fn seriesFunc) {
+	//line report/report.qtpl:404
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:169
-	p.streampieChart(qw422016, title, fn)
-	//line report/report.qtpl:169
+	//line report/report.qtpl:404
+	p.streambytesChart(qw422016, title, fn)
+	//line report/report.qtpl:404
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:169
+//line report/report.qtpl:404
 }
 
-//line report/report.qtpl:169
-func (p *Page) pieChart(title string, fn seriesFunc) string {
-	//line report/report.qtpl:169
+//line report/report.qtpl:404
+func (p *Page) bytesChart(title string, fn seriesFunc) string {
+	//line report/report.qtpl:404
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:169
-	p.writepieChart(qb422016, title, fn)
-	//line report/report.qtpl:169
+	//line report/report.qtpl:404
+	p.writebytesChart(qb422016, title, fn)
+	//line report/report.qtpl:404
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:169
+	//line report/report.qtpl:404
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:169
+	//line report/report.qtpl:404
 	return qs422016
-//line report/report.qtpl:169
+//line report/report.qtpl:404
 }
 
-//line report/report.qtpl:171
-func (p *Page) streamconnectionSeries(qw422016 *qt422016.Writer) {
-	//line report/report.qtpl:171
+//line report/report.qtpl:406
+func (p *Page) streamstackedChart(qw422016 *qt422016.Writer, title string, fn seriesFunc) {
+	//line report/report.qtpl:406
+	qw422016.N().S(`
+	<script>
+	$(function () {
+    			$('#`)
+	//line report/report.qtpl:409
+	qw422016.N().S(title)
+	//line report/report.qtpl:409
+	qw422016.N().S(`').highcharts({
+					chart: {
+						type: 'area'
+					},
+					title: {
+						text: '`)
+	//line report/report.qtpl:414
+	qw422016.N().S(strings.Title(title))
+	//line report/report.qtpl:414
+	qw422016.N().S(`',
+						x: -20 //center
+					},
+					xAxis: {
+						type: 'linear',
+						plotLines: `)
+	//line report/report.qtpl:419
+	qw422016.N().S(p.annotationLines())
+	//line report/report.qtpl:419
+	qw422016.N().S(`,
+						plotBands: `)
+	//line report/report.qtpl:420
+	qw422016.N().S(p.annotationBands())
+	//line report/report.qtpl:420
+	qw422016.N().S(`,
+					},
+					yAxis: {
+						min: 0,
+					},
+					tooltip: {
+						shared: true
+					},
+					legend: {
+						layout: 'vertical',
+						align: 'right',
+						verticalAlign: 'middle',
+						borderWidth: 0
+					},
+					plotOptions: {
+						area: {
+							stacking: 'normal',
+							marker: {
+								enabled: false
+							}
+						},
+						series: {
+							pointStart: 0,
+							pointInterval: `)
+	//line report/report.qtpl:443
+	qw422016.N().FPrec(p.Interval, 2)
+	//line report/report.qtpl:443
+	qw422016.N().S(`,
+						}
+					},
+					series: `)
+	//line report/report.qtpl:446
+	qw422016.N().S(fn())
+	//line report/report.qtpl:446
+	qw422016.N().S(`
+				});
+    		});
+    </script>
+   	<div id="`)
+	//line report/report.qtpl:450
+	qw422016.N().S(title)
+	//line report/report.qtpl:450
+	qw422016.N().S(`" style="min-width: 310px; height: 400px; margin: 0 auto"></div>
+`)
+//line report/report.qtpl:451
+}
+
+//line report/report.qtpl:451
+func (p *Page) writestackedChart(qq422016 qtio422016.Writer, title string, fn seriesFunc) {
+	//line report/report.qtpl:451
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:451
+	p.streamstackedChart(qw422016, title, fn)
+	//line report/report.qtpl:451
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:451
+}
+
+//line report/report.qtpl:451
+func (p *Page) stackedChart(title string, fn seriesFunc) string {
+	//line report/report.qtpl:451
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:451
+	p.writestackedChart(qb422016, title, fn)
+	//line report/report.qtpl:451
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:451
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:451
+	return qs422016
+//line report/report.qtpl:451
+}
+
+//line report/report.qtpl:453
+func (p *Page) streamscatterChart(qw422016 *qt422016.Writer, title, xTitle, yTitle string, fn seriesFunc) {
+	//line report/report.qtpl:453
+	qw422016.N().S(`
+	<script>
+	$(function () {
+    			$('#`)
+	//line report/report.qtpl:456
+	qw422016.N().S(title)
+	//line report/report.qtpl:456
+	qw422016.N().S(`').highcharts({
+					chart: {
+						type: 'scatter',
+						zoomType: 'xy'
+					},
+					title: {
+						text: '`)
+	//line report/report.qtpl:462
+	qw422016.N().S(strings.Title(title))
+	//line report/report.qtpl:462
+	qw422016.N().S(`',
+						x: -20 //center
+					},
+					xAxis: {
+						title: {
+							text: '`)
+	//line report/report.qtpl:467
+	qw422016.N().S(xTitle)
+	//line report/report.qtpl:467
+	qw422016.N().S(`'
+						}
+					},
+					yAxis: {
+						title: {
+							text: '`)
+	//line report/report.qtpl:472
+	qw422016.N().S(yTitle)
+	//line report/report.qtpl:472
+	qw422016.N().S(`'
+						}
+					},
+					legend: {
+						layout: 'vertical',
+						align: 'right',
+						verticalAlign: 'middle',
+						borderWidth: 0
+					},
+					series: `)
+	//line report/report.qtpl:481
+	qw422016.N().S(fn())
+	//line report/report.qtpl:481
+	qw422016.N().S(`
+				});
+    		});
+    </script>
+   	<div id="`)
+	//line report/report.qtpl:485
+	qw422016.N().S(title)
+	//line report/report.qtpl:485
+	qw422016.N().S(`" style="min-width: 310px; height: 400px; margin: 0 auto"></div>
+`)
+//line report/report.qtpl:486
+}
+
+//line report/report.qtpl:486
+func (p *Page) writescatterChart(qq422016 qtio422016.Writer, title, xTitle, yTitle string, fn seriesFunc) {
+	//line report/report.qtpl:486
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:486
+	p.streamscatterChart(qw422016, title, xTitle, yTitle, fn)
+	//line report/report.qtpl:486
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:486
+}
+
+//line report/report.qtpl:486
+func (p *Page) scatterChart(title, xTitle, yTitle string, fn seriesFunc) string {
+	//line report/report.qtpl:486
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:486
+	p.writescatterChart(qb422016, title, xTitle, yTitle, fn)
+	//line report/report.qtpl:486
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:486
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:486
+	return qs422016
+//line report/report.qtpl:486
+}
+
+//line report/report.qtpl:488
+func (p *Page) streampieChart(qw422016 *qt422016.Writer, title string, fn seriesFunc) {
+	//line report/report.qtpl:488
+	qw422016.N().S(`
+	<script>
+	$(function () {
+    			$('#`)
+	//line report/report.qtpl:491
+	qw422016.N().S(title)
+	//line report/report.qtpl:491
+	qw422016.N().S(`').highcharts({
+					chart: {
+						plotBackgroundColor: null,
+						plotBorderWidth: null,
+						plotShadow: false,
+						type: 'pie'
+					},
+					title: {
+						text: '`)
+	//line report/report.qtpl:499
+	qw422016.N().S(strings.Title(title))
+	//line report/report.qtpl:499
+	qw422016.N().S(`',
+					},
+					 tooltip: {
+						pointFormat: '{series.name}: <b>{point.percentage:.1f}%</b>'
+					},
+					plotOptions: {
+						pie: {
+							allowPointSelect: true,
+							cursor: 'pointer',
+							dataLabels: {
+								enabled: false
+							},
+							showInLegend: true
+						}
+					},
+					series: `)
+	//line report/report.qtpl:514
+	qw422016.N().S(fn())
+	//line report/report.qtpl:514
+	qw422016.N().S(`
+				});
+    		});
+    </script>
+   	<div id="`)
+	//line report/report.qtpl:518
+	qw422016.N().S(title)
+	//line report/report.qtpl:518
+	qw422016.N().S(`" style = "float: left; width:50%; height: 400px;"></div>
+`)
+//line report/report.qtpl:519
+}
+
+//line report/report.qtpl:519
+func (p *Page) writepieChart(qq422016 qtio422016.Writer, title string, fn seriesFunc) {
+	//line report/report.qtpl:519
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:519
+	p.streampieChart(qw422016, title, fn)
+	//line report/report.qtpl:519
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:519
+}
+
+//line report/report.qtpl:519
+func (p *Page) pieChart(title string, fn seriesFunc) string {
+	//line report/report.qtpl:519
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:519
+	p.writepieChart(qb422016, title, fn)
+	//line report/report.qtpl:519
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:519
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:519
+	return qs422016
+//line report/report.qtpl:519
+}
+
+//line report/report.qtpl:521
+func (p *Page) streamconnectionSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:521
 	qw422016.N().S(`
 	[{
 		name: 'Connections',
 		data: [`)
-	//line report/report.qtpl:174
-	qw422016.N().S(uint64SliceToString(p.Connections))
-	//line report/report.qtpl:174
+	//line report/report.qtpl:524
+	qw422016.N().S(p.uint64Series(p.Connections))
+	//line report/report.qtpl:524
 	qw422016.N().S(`]
 	}]
 `)
-//line report/report.qtpl:176
+//line report/report.qtpl:526
 }
 
-//line report/report.qtpl:176
+//line report/report.qtpl:526
 func (p *Page) writeconnectionSeries(qq422016 qtio422016.Writer) {
-	//line report/report.qtpl:176
+	//line report/report.qtpl:526
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:176
+	//line report/report.qtpl:526
 	p.streamconnectionSeries(qw422016)
-	//line report/report.qtpl:176
+	//line report/report.qtpl:526
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:176
+//line report/report.qtpl:526
 }
 
-//line report/report.qtpl:176
+//line report/report.qtpl:526
 func (p *Page) connectionSeries() string {
-	//line report/report.qtpl:176
+	//line report/report.qtpl:526
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:176
+	//line report/report.qtpl:526
 	p.writeconnectionSeries(qb422016)
-	//line report/report.qtpl:176
+	//line report/report.qtpl:526
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:176
+	//line report/report.qtpl:526
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:176
+	//line report/report.qtpl:526
 	return qs422016
-//line report/report.qtpl:176
+//line report/report.qtpl:526
 }
 
-//line report/report.qtpl:178
+//line report/report.qtpl:528
 func (p *Page) streamqpsSeries(qw422016 *qt422016.Writer) {
-	//line report/report.qtpl:178
+	//line report/report.qtpl:528
 	qw422016.N().S(`
 	[{
 		name: 'Load average',
 		data: [`)
-	//line report/report.qtpl:181
-	qw422016.N().S(uint64SliceToString(p.Qps))
-	//line report/report.qtpl:181
+	//line report/report.qtpl:531
+	qw422016.N().S(p.uint64Series(p.Qps))
+	//line report/report.qtpl:531
 	qw422016.N().S(`]
 	},
 	{
 		name: 'Req-per-second',
 		data: [`)
-	//line report/report.qtpl:185
-	qw422016.N().S(float64SliceToString(rate(p.RequestSum, p.Interval)))
-	//line report/report.qtpl:185
+	//line report/report.qtpl:535
+	qw422016.N().S(p.series(p.rates(p.RequestSum)))
+	//line report/report.qtpl:535
 	qw422016.N().S(`]
 	}]
 `)
-//line report/report.qtpl:187
+//line report/report.qtpl:537
 }
 
-//line report/report.qtpl:187
+//line report/report.qtpl:537
 func (p *Page) writeqpsSeries(qq422016 qtio422016.Writer) {
-	//line report/report.qtpl:187
+	//line report/report.qtpl:537
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:187
+	//line report/report.qtpl:537
 	p.streamqpsSeries(qw422016)
-	//line report/report.qtpl:187
+	//line report/report.qtpl:537
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:187
+//line report/report.qtpl:537
 }
 
-//line report/report.qtpl:187
+//line report/report.qtpl:537
 func (p *Page) qpsSeries() string {
-	//line report/report.qtpl:187
+	//line report/report.qtpl:537
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:187
+	//line report/report.qtpl:537
 	p.writeqpsSeries(qb422016)
-	//line report/report.qtpl:187
+	//line report/report.qtpl:537
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:187
+	//line report/report.qtpl:537
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:187
+	//line report/report.qtpl:537
 	return qs422016
-//line report/report.qtpl:187
+//line report/report.qtpl:537
 }
 
-//line report/report.qtpl:189
+//line report/report.qtpl:539
 func (p *Page) streamerrorSeries(qw422016 *qt422016.Writer) {
-	//line report/report.qtpl:189
+	//line report/report.qtpl:539
 	qw422016.N().S(`
 	[{
 		name: 'Errors',
 		data: [`)
-	//line report/report.qtpl:192
-	qw422016.N().S(float64SliceToString(rate(p.Errors, p.Interval)))
-	//line report/report.qtpl:192
+	//line report/report.qtpl:542
+	qw422016.N().S(p.series(p.rates(p.Errors)))
+	//line report/report.qtpl:542
 	qw422016.N().S(`]
 	},{
 		name: 'Timeouts',
 		data: [`)
-	//line report/report.qtpl:195
-	qw422016.N().S(float64SliceToString(rate(p.Timeouts, p.Interval)))
-	//line report/report.qtpl:195
+	//line report/report.qtpl:545
+	qw422016.N().S(p.series(p.rates(p.Timeouts)))
+	//line report/report.qtpl:545
 	qw422016.N().S(`]
 	}]
 `)
-//line report/report.qtpl:197
+//line report/report.qtpl:547
 }
 
-//line report/report.qtpl:197
+//line report/report.qtpl:547
 func (p *Page) writeerrorSeries(qq422016 qtio422016.Writer) {
-	//line report/report.qtpl:197
+	//line report/report.qtpl:547
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:197
+	//line report/report.qtpl:547
 	p.streamerrorSeries(qw422016)
-	//line report/report.qtpl:197
+	//line report/report.qtpl:547
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:197
+//line report/report.qtpl:547
 }
 
-//line report/report.qtpl:197
+//line report/report.qtpl:547
 func (p *Page) errorSeries() string {
-	//line report/report.qtpl:197
+	//line report/report.qtpl:547
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:197
+	//line report/report.qtpl:547
 	p.writeerrorSeries(qb422016)
-	//line report/report.qtpl:197
+	//line report/report.qtpl:547
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:197
+	//line report/report.qtpl:547
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:197
+	//line report/report.qtpl:547
 	return qs422016
-//line report/report.qtpl:197
+//line report/report.qtpl:547
 }
 
-//line report/report.qtpl:200
-func (p *Page) streamdurationSeries(qw422016 *qt422016.Writer) {
-	//line report/report.qtpl:200
+//line report/report.qtpl:550
+func (p *Page) streamconnSetupSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:550
 	qw422016.N().S(`[`)
-	//line report/report.qtpl:203
-	var keys []float64
-	for k := range p.RequestDuration {
-		keys = append(keys, k)
-	}
-	sort.Float64s(keys)
-
-	//line report/report.qtpl:209
-	for i, k := range keys {
-		//line report/report.qtpl:209
+	//line report/report.qtpl:552
+	for i, k := range connSetupQuantiles {
+		//line report/report.qtpl:553
+		if i > 0 {
+			//line report/report.qtpl:553
+			qw422016.N().S(`,`)
+			//line report/report.qtpl:553
+		}
+		//line report/report.qtpl:553
 		qw422016.N().S(`{name: '`)
-		//line report/report.qtpl:211
+		//line report/report.qtpl:555
+		qw422016.N().S("connect ")
+		//line report/report.qtpl:555
 		qw422016.N().F(k)
-		//line report/report.qtpl:211
+		//line report/report.qtpl:555
 		qw422016.N().S(`',data: [`)
-		//line report/report.qtpl:212
-		qw422016.N().S(float64SliceToString(p.RequestDuration[k]))
-		//line report/report.qtpl:212
-		qw422016.N().S(`],tooltip: {valueSuffix: ' s'}}`)
-		//line report/report.qtpl:215
-		if i+1 < len(keys) {
-			//line report/report.qtpl:215
-			qw422016.N().S(`,`)
-			//line report/report.qtpl:215
+		//line report/report.qtpl:556
+		qw422016.N().S(p.series(p.scaled(p.ConnectDuration[k])))
+		//line report/report.qtpl:556
+		qw422016.N().S(`],tooltip: {valueSuffix: '`)
+		//line report/report.qtpl:557
+		qw422016.N().S(" " + p.latencyUnit())
+		//line report/report.qtpl:557
+		qw422016.N().S(`'}}`)
+		//line report/report.qtpl:559
+	}
+	//line report/report.qtpl:560
+	if len(p.HandshakeDuration) > 0 {
+		//line report/report.qtpl:561
+		for _, k := range connSetupQuantiles {
+			//line report/report.qtpl:561
+			qw422016.N().S(`,{name: '`)
+			//line report/report.qtpl:563
+			qw422016.N().S("handshake ")
+			//line report/report.qtpl:563
+			qw422016.N().F(k)
+			//line report/report.qtpl:563
+			qw422016.N().S(`',data: [`)
+			//line report/report.qtpl:564
+			qw422016.N().S(p.series(p.scaled(p.HandshakeDuration[k])))
+			//line report/report.qtpl:564
+			qw422016.N().S(`],dashStyle: 'Dash',tooltip: {valueSuffix: '`)
+			//line report/report.qtpl:566
+			qw422016.N().S(" " + p.latencyUnit())
+			//line report/report.qtpl:566
+			qw422016.N().S(`'}}`)
+			//line report/report.qtpl:568
 		}
-		//line report/report.qtpl:216
+		//line report/report.qtpl:569
 	}
-	//line report/report.qtpl:216
+	//line report/report.qtpl:569
 	qw422016.N().S(`]`)
-//line report/report.qtpl:218
+//line report/report.qtpl:571
 }
 
-//line report/report.qtpl:218
-func (p *Page) writedurationSeries(qq422016 qtio422016.Writer) {
-	//line report/report.qtpl:218
+//line report/report.qtpl:571
+func (p *Page) writeconnSetupSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:571
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:218
-	p.streamdurationSeries(qw422016)
-	//line report/report.qtpl:218
+	//line report/report.qtpl:571
+	p.streamconnSetupSeries(qw422016)
+	//line report/report.qtpl:571
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:218
+//line report/report.qtpl:571
 }
 
-//line report/report.qtpl:218
-func (p *Page) durationSeries() string {
-	//line report/report.qtpl:218
+//line report/report.qtpl:571
+func (p *Page) connSetupSeries() string {
+	//line report/report.qtpl:571
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:218
-	p.writedurationSeries(qb422016)
-	//line report/report.qtpl:218
+	//line report/report.qtpl:571
+	p.writeconnSetupSeries(qb422016)
+	//line report/report.qtpl:571
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:218
+	//line report/report.qtpl:571
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:218
+	//line report/report.qtpl:571
 	return qs422016
-//line report/report.qtpl:218
+//line report/report.qtpl:571
 }
 
-//line report/report.qtpl:222
-func (p *Page) streambytesSeries(qw422016 *qt422016.Writer) {
-	//line report/report.qtpl:222
-	qw422016.N().S(`[{name: 'BytesWritten',data: [`)
-	//line report/report.qtpl:225
-	qw422016.N().S(float64SliceToString(rate(p.BytesWritten, p.Interval)))
-	//line report/report.qtpl:225
-	qw422016.N().S(`]},{name: 'BytesRead',data: [`)
-	//line report/report.qtpl:228
-	qw422016.N().S(float64SliceToString(rate(p.BytesRead, p.Interval)))
-	//line report/report.qtpl:228
-	qw422016.N().S(`]}]`)
-//line report/report.qtpl:230
+//line report/report.qtpl:573
+func (p *Page) streambreakdownSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:573
+	qw422016.N().S(`[`)
+	//line report/report.qtpl:575
+	for i, part := range breakdownParts {
+		//line report/report.qtpl:576
+		if i > 0 {
+			//line report/report.qtpl:576
+			qw422016.N().S(`,`)
+			//line report/report.qtpl:576
+		}
+		//line report/report.qtpl:576
+		qw422016.N().S(`{name: '`)
+		//line report/report.qtpl:578
+		qw422016.N().S(part)
+		//line report/report.qtpl:578
+		qw422016.N().S(`',type: 'area',stacking: 'normal',data: [`)
+		//line report/report.qtpl:581
+		qw422016.N().S(p.series(p.scaled(p.BreakdownSeries[part])))
+		//line report/report.qtpl:581
+		qw422016.N().S(`],tooltip: {valueSuffix: '`)
+		//line report/report.qtpl:582
+		qw422016.N().S(" " + p.latencyUnit())
+		//line report/report.qtpl:582
+		qw422016.N().S(`'}}`)
+		//line report/report.qtpl:584
+	}
+	//line report/report.qtpl:584
+	qw422016.N().S(`]`)
+//line report/report.qtpl:586
 }
 
-//line report/report.qtpl:230
-func (p *Page) writebytesSeries(qq422016 qtio422016.Writer) {
-	//line report/report.qtpl:230
+//line report/report.qtpl:586
+func (p *Page) writebreakdownSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:586
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:230
-	p.streambytesSeries(qw422016)
-	//line report/report.qtpl:230
+	//line report/report.qtpl:586
+	p.streambreakdownSeries(qw422016)
+	//line report/report.qtpl:586
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:230
+//line report/report.qtpl:586
 }
 
-//line report/report.qtpl:230
-func (p *Page) bytesSeries() string {
-	//line report/report.qtpl:230
+//line report/report.qtpl:586
+func (p *Page) breakdownSeries() string {
+	//line report/report.qtpl:586
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:230
-	p.writebytesSeries(qb422016)
-	//line report/report.qtpl:230
+	//line report/report.qtpl:586
+	p.writebreakdownSeries(qb422016)
+	//line report/report.qtpl:586
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:230
+	//line report/report.qtpl:586
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:230
+	//line report/report.qtpl:586
 	return qs422016
-//line report/report.qtpl:230
+//line report/report.qtpl:586
 }
 
-//line report/report.qtpl:234
-func (p *Page) streamstatusCodesSeries(qw422016 *qt422016.Writer) {
-	//line report/report.qtpl:234
-	qw422016.N().S(`[{name: 'Status codes',colorByPoint: true,data: [`)
-	//line report/report.qtpl:239
-	for k, v := range p.StatusCodes {
-		//line report/report.qtpl:239
+//line report/report.qtpl:588
+func (p *Page) streamdurationSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:588
+	qw422016.N().S(`[`)
+	//line report/report.qtpl:590
+	keys := p.durationQuantiles()
+	//line report/report.qtpl:591
+	for i, k := range keys {
+		//line report/report.qtpl:591
 		qw422016.N().S(`{name: '`)
-		//line report/report.qtpl:241
-		qw422016.N().S(k)
-		//line report/report.qtpl:241
-		qw422016.N().S(`',y:`)
-		//line report/report.qtpl:242
+		//line report/report.qtpl:593
+		qw422016.N().F(k)
+		//line report/report.qtpl:593
+		qw422016.N().S(`',quantile:`)
+		//line report/report.qtpl:594
+		qw422016.N().F(k)
+		//line report/report.qtpl:594
+		qw422016.N().S(`,data: [`)
+		//line report/report.qtpl:595
+		qw422016.N().S(p.series(p.durations(k)))
+		//line report/report.qtpl:595
+		qw422016.N().S(`],tooltip: {valueSuffix: '`)
+		//line report/report.qtpl:596
+		qw422016.N().S(" " + p.latencyUnit())
+		//line report/report.qtpl:596
+		qw422016.N().S(`'}}`)
+		//line report/report.qtpl:598
+		if i+1 < len(keys) {
+			//line report/report.qtpl:598
+			qw422016.N().S(`,`)
+			//line report/report.qtpl:598
+		}
+		//line report/report.qtpl:599
+	}
+	//line report/report.qtpl:600
+	for _, k := range p.firstRequestQuantiles() {
+		//line report/report.qtpl:600
+		qw422016.N().S(`,{name: '`)
+		//line report/report.qtpl:602
+		qw422016.N().S("first ")
+		//line report/report.qtpl:602
+		qw422016.N().F(k)
+		//line report/report.qtpl:602
+		qw422016.N().S(`',quantile:`)
+		//line report/report.qtpl:603
+		qw422016.N().F(k)
+		//line report/report.qtpl:603
+		qw422016.N().S(`,data: [`)
+		//line report/report.qtpl:604
+		qw422016.N().S(p.series(p.firstRequestDurations(k)))
+		//line report/report.qtpl:604
+		qw422016.N().S(`],dashStyle: 'Dash',tooltip: {valueSuffix: '`)
+		//line report/report.qtpl:606
+		qw422016.N().S(" " + p.latencyUnit())
+		//line report/report.qtpl:606
+		qw422016.N().S(`'}}`)
+		//line report/report.qtpl:608
+	}
+	//line report/report.qtpl:609
+	for _, k := range p.correctedQuantiles() {
+		//line report/report.qtpl:609
+		qw422016.N().S(`,{name: '`)
+		//line report/report.qtpl:611
+		qw422016.N().S("corrected ")
+		//line report/report.qtpl:611
+		qw422016.N().F(k)
+		//line report/report.qtpl:611
+		qw422016.N().S(`',quantile:`)
+		//line report/report.qtpl:612
+		qw422016.N().F(k)
+		//line report/report.qtpl:612
+		qw422016.N().S(`,data: [`)
+		//line report/report.qtpl:613
+		qw422016.N().S(p.series(p.correctedDurations(k)))
+		//line report/report.qtpl:613
+		qw422016.N().S(`],dashStyle: 'ShortDot',tooltip: {valueSuffix: '`)
+		//line report/report.qtpl:615
+		qw422016.N().S(" " + p.latencyUnit())
+		//line report/report.qtpl:615
+		qw422016.N().S(`'}}`)
+		//line report/report.qtpl:617
+	}
+	//line report/report.qtpl:617
+	qw422016.N().S(`]`)
+//line report/report.qtpl:619
+}
+
+//line report/report.qtpl:619
+func (p *Page) writedurationSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:619
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:619
+	p.streamdurationSeries(qw422016)
+	//line report/report.qtpl:619
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:619
+}
+
+//line report/report.qtpl:619
+func (p *Page) durationSeries() string {
+	//line report/report.qtpl:619
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:619
+	p.writedurationSeries(qb422016)
+	//line report/report.qtpl:619
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:619
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:619
+	return qs422016
+//line report/report.qtpl:619
+}
+
+//line report/report.qtpl:623
+func (p *Page) streamtargetDurationSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:623
+	qw422016.N().S(`[`)
+	//line report/report.qtpl:625
+	for i, name := range p.targetNames() {
+		//line report/report.qtpl:626
+		if i > 0 {
+			//line report/report.qtpl:626
+			qw422016.N().S(`,`)
+			//line report/report.qtpl:626
+		}
+		//line report/report.qtpl:626
+		qw422016.N().S(`{name:`)
+		//line report/report.qtpl:628
+		qw422016.N().Q(name)
+		//line report/report.qtpl:628
+		qw422016.N().S(`,data: [`)
+		//line report/report.qtpl:629
+		qw422016.N().S(p.series(p.targetDurations(name)))
+		//line report/report.qtpl:629
+		qw422016.N().S(`],tooltip: {valueSuffix: '`)
+		//line report/report.qtpl:630
+		qw422016.N().S(" " + p.latencyUnit())
+		//line report/report.qtpl:630
+		qw422016.N().S(`'}}`)
+		//line report/report.qtpl:632
+	}
+	//line report/report.qtpl:632
+	qw422016.N().S(`]`)
+//line report/report.qtpl:634
+}
+
+//line report/report.qtpl:634
+func (p *Page) writetargetDurationSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:634
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:634
+	p.streamtargetDurationSeries(qw422016)
+	//line report/report.qtpl:634
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:634
+}
+
+//line report/report.qtpl:634
+func (p *Page) targetDurationSeries() string {
+	//line report/report.qtpl:634
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:634
+	p.writetargetDurationSeries(qb422016)
+	//line report/report.qtpl:634
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:634
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:634
+	return qs422016
+//line report/report.qtpl:634
+}
+
+//line report/report.qtpl:638
+func (p *Page) streamlatencyOverConnectionsSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:638
+	qw422016.N().S(`[{name: 'p99',data: [`)
+	//line report/report.qtpl:641
+	qw422016.N().S(pairsToString(p.loadLatencyOverConnections()))
+	//line report/report.qtpl:641
+	qw422016.N().S(`],tooltip: {pointFormat: '{point.x} connections: {point.y}`)
+	//line report/report.qtpl:642
+	qw422016.N().S(" " + p.latencyUnit())
+	//line report/report.qtpl:642
+	qw422016.N().S(`'}}]`)
+//line report/report.qtpl:644
+}
+
+//line report/report.qtpl:644
+func (p *Page) writelatencyOverConnectionsSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:644
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:644
+	p.streamlatencyOverConnectionsSeries(qw422016)
+	//line report/report.qtpl:644
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:644
+}
+
+//line report/report.qtpl:644
+func (p *Page) latencyOverConnectionsSeries() string {
+	//line report/report.qtpl:644
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:644
+	p.writelatencyOverConnectionsSeries(qb422016)
+	//line report/report.qtpl:644
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:644
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:644
+	return qs422016
+//line report/report.qtpl:644
+}
+
+//line report/report.qtpl:648
+func (p *Page) streamsweepRpsSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:648
+	qw422016.N().S(`[{name: 'Rps',lineWidth: 1,data: [`)
+	//line report/report.qtpl:652
+	qw422016.N().S(pairsToString(p.sweepValues(), p.sweepRps()))
+	//line report/report.qtpl:652
+	qw422016.N().S(`],tooltip: {pointFormat: '{point.x}`)
+	//line report/report.qtpl:653
+	qw422016.N().S(" " + p.sweepUnit())
+	//line report/report.qtpl:653
+	qw422016.N().S(`: {point.y} rps'}}]`)
+//line report/report.qtpl:655
+}
+
+//line report/report.qtpl:655
+func (p *Page) writesweepRpsSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:655
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:655
+	p.streamsweepRpsSeries(qw422016)
+	//line report/report.qtpl:655
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:655
+}
+
+//line report/report.qtpl:655
+func (p *Page) sweepRpsSeries() string {
+	//line report/report.qtpl:655
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:655
+	p.writesweepRpsSeries(qb422016)
+	//line report/report.qtpl:655
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:655
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:655
+	return qs422016
+//line report/report.qtpl:655
+}
+
+//line report/report.qtpl:659
+func (p *Page) streamsweepLatencySeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:659
+	qw422016.N().S(`[{name: 'p99',lineWidth: 1,data: [`)
+	//line report/report.qtpl:663
+	qw422016.N().S(pairsToString(p.sweepValues(), p.sweepP99()))
+	//line report/report.qtpl:663
+	qw422016.N().S(`],tooltip: {pointFormat: '{point.x}`)
+	//line report/report.qtpl:664
+	qw422016.N().S(" " + p.sweepUnit())
+	//line report/report.qtpl:664
+	qw422016.N().S(`: {point.y}`)
+	//line report/report.qtpl:664
+	qw422016.N().S(" " + p.latencyUnit())
+	//line report/report.qtpl:664
+	qw422016.N().S(`'}}]`)
+//line report/report.qtpl:666
+}
+
+//line report/report.qtpl:666
+func (p *Page) writesweepLatencySeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:666
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:666
+	p.streamsweepLatencySeries(qw422016)
+	//line report/report.qtpl:666
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:666
+}
+
+//line report/report.qtpl:666
+func (p *Page) sweepLatencySeries() string {
+	//line report/report.qtpl:666
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:666
+	p.writesweepLatencySeries(qb422016)
+	//line report/report.qtpl:666
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:666
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:666
+	return qs422016
+//line report/report.qtpl:666
+}
+
+//line report/report.qtpl:670
+func (p *Page) streambytesSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:670
+	qw422016.N().S(`[{name: 'BytesWritten',data: [`)
+	//line report/report.qtpl:673
+	qw422016.N().S(p.series(p.rates(p.BytesWritten)))
+	//line report/report.qtpl:673
+	qw422016.N().S(`]},{name: 'BytesRead',data: [`)
+	//line report/report.qtpl:676
+	qw422016.N().S(p.series(p.rates(p.BytesRead)))
+	//line report/report.qtpl:676
+	qw422016.N().S(`]}]`)
+//line report/report.qtpl:678
+}
+
+//line report/report.qtpl:678
+func (p *Page) writebytesSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:678
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:678
+	p.streambytesSeries(qw422016)
+	//line report/report.qtpl:678
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:678
+}
+
+//line report/report.qtpl:678
+func (p *Page) bytesSeries() string {
+	//line report/report.qtpl:678
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:678
+	p.writebytesSeries(qb422016)
+	//line report/report.qtpl:678
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:678
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:678
+	return qs422016
+//line report/report.qtpl:678
+}
+
+//line report/report.qtpl:682
+func (p *Page) streamstatusCodeRateSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:682
+	qw422016.N().S(`[`)
+	//line report/report.qtpl:684
+	for i, code := range p.statusCodeSeriesCodes() {
+		//line report/report.qtpl:685
+		if i > 0 {
+			//line report/report.qtpl:685
+			qw422016.N().S(`,`)
+			//line report/report.qtpl:685
+		}
+		//line report/report.qtpl:685
+		qw422016.N().S(`{name: '`)
+		//line report/report.qtpl:687
+		qw422016.N().D(code)
+		//line report/report.qtpl:687
+		qw422016.N().S(`',data: [`)
+		//line report/report.qtpl:688
+		qw422016.N().S(p.series(p.rates(p.StatusCodeSeries[code])))
+		//line report/report.qtpl:688
+		qw422016.N().S(`],tooltip: {valueSuffix: ' rps'}}`)
+		//line report/report.qtpl:691
+	}
+	//line report/report.qtpl:691
+	qw422016.N().S(`]`)
+//line report/report.qtpl:693
+}
+
+//line report/report.qtpl:693
+func (p *Page) writestatusCodeRateSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:693
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:693
+	p.streamstatusCodeRateSeries(qw422016)
+	//line report/report.qtpl:693
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:693
+}
+
+//line report/report.qtpl:693
+func (p *Page) statusCodeRateSeries() string {
+	//line report/report.qtpl:693
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:693
+	p.writestatusCodeRateSeries(qb422016)
+	//line report/report.qtpl:693
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:693
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:693
+	return qs422016
+//line report/report.qtpl:693
+}
+
+//line report/report.qtpl:697
+func (p *Page) streamerrorClassRateSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:697
+	qw422016.N().S(`[`)
+	//line report/report.qtpl:699
+	for i, class := range p.errorClasses() {
+		//line report/report.qtpl:700
+		if i > 0 {
+			//line report/report.qtpl:700
+			qw422016.N().S(`,`)
+			//line report/report.qtpl:700
+		}
+		//line report/report.qtpl:700
+		qw422016.N().S(`{name: '`)
+		//line report/report.qtpl:702
+		qw422016.N().S(class)
+		//line report/report.qtpl:702
+		qw422016.N().S(`',data: [`)
+		//line report/report.qtpl:703
+		qw422016.N().S(p.series(p.rates(p.ErrorClassSeries[class])))
+		//line report/report.qtpl:703
+		qw422016.N().S(`],tooltip: {valueSuffix: ' errors/s'}}`)
+		//line report/report.qtpl:706
+	}
+	//line report/report.qtpl:706
+	qw422016.N().S(`]`)
+//line report/report.qtpl:708
+}
+
+//line report/report.qtpl:708
+func (p *Page) writeerrorClassRateSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:708
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:708
+	p.streamerrorClassRateSeries(qw422016)
+	//line report/report.qtpl:708
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:708
+}
+
+//line report/report.qtpl:708
+func (p *Page) errorClassRateSeries() string {
+	//line report/report.qtpl:708
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:708
+	p.writeerrorClassRateSeries(qb422016)
+	//line report/report.qtpl:708
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:708
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:708
+	return qs422016
+//line report/report.qtpl:708
+}
+
+//line report/report.qtpl:712
+func (p *Page) streamstatusCodesSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:712
+	qw422016.N().S(`[{name: 'Status codes',colorByPoint: true,data: [`)
+	//line report/report.qtpl:717
+	for k, v := range p.StatusCodes {
+		//line report/report.qtpl:717
+		qw422016.N().S(`{name: '`)
+		//line report/report.qtpl:719
+		qw422016.N().S(k)
+		//line report/report.qtpl:719
+		qw422016.N().S(`',y:`)
+		//line report/report.qtpl:720
 		qw422016.N().FPrec(v, 2)
-		//line report/report.qtpl:242
+		//line report/report.qtpl:720
 		qw422016.N().S(`},`)
-		//line report/report.qtpl:244
+		//line report/report.qtpl:722
 	}
-	//line report/report.qtpl:244
+	//line report/report.qtpl:722
 	qw422016.N().S(`]}]`)
-//line report/report.qtpl:247
+//line report/report.qtpl:725
 }
 
-//line report/report.qtpl:247
+//line report/report.qtpl:725
 func (p *Page) writestatusCodesSeries(qq422016 qtio422016.Writer) {
-	//line report/report.qtpl:247
+	//line report/report.qtpl:725
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:247
+	//line report/report.qtpl:725
 	p.streamstatusCodesSeries(qw422016)
-	//line report/report.qtpl:247
+	//line report/report.qtpl:725
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:247
+//line report/report.qtpl:725
 }
 
-//line report/report.qtpl:247
+//line report/report.qtpl:725
 func (p *Page) statusCodesSeries() string {
-	//line report/report.qtpl:247
+	//line report/report.qtpl:725
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:247
+	//line report/report.qtpl:725
 	p.writestatusCodesSeries(qb422016)
-	//line report/report.qtpl:247
+	//line report/report.qtpl:725
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:247
+	//line report/report.qtpl:725
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:247
+	//line report/report.qtpl:725
+	return qs422016
+//line report/report.qtpl:725
+}
+
+//line report/report.qtpl:729
+func (p *Page) streamgrpcStatusCodesSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:729
+	qw422016.N().S(`[{name: 'gRPC status codes',colorByPoint: true,data: [`)
+	//line report/report.qtpl:734
+	for k, v := range p.GRPCStatusCodes {
+		//line report/report.qtpl:734
+		qw422016.N().S(`{name: '`)
+		//line report/report.qtpl:736
+		qw422016.N().S(k)
+		//line report/report.qtpl:736
+		qw422016.N().S(`',y:`)
+		//line report/report.qtpl:737
+		qw422016.N().FPrec(v, 2)
+		//line report/report.qtpl:737
+		qw422016.N().S(`},`)
+		//line report/report.qtpl:739
+	}
+	//line report/report.qtpl:739
+	qw422016.N().S(`]}]`)
+//line report/report.qtpl:742
+}
+
+//line report/report.qtpl:742
+func (p *Page) writegrpcStatusCodesSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:742
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:742
+	p.streamgrpcStatusCodesSeries(qw422016)
+	//line report/report.qtpl:742
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:742
+}
+
+//line report/report.qtpl:742
+func (p *Page) grpcStatusCodesSeries() string {
+	//line report/report.qtpl:742
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:742
+	p.writegrpcStatusCodesSeries(qb422016)
+	//line report/report.qtpl:742
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:742
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:742
+	return qs422016
+//line report/report.qtpl:742
+}
+
+//line report/report.qtpl:746
+func (p *Page) streambackendsSeries(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:746
+	qw422016.N().S(`[{name: 'Backends',colorByPoint: true,data: [`)
+	//line report/report.qtpl:751
+	for k, v := range p.Backends {
+		//line report/report.qtpl:751
+		qw422016.N().S(`{name:`)
+		//line report/report.qtpl:753
+		qw422016.N().Q(k)
+		//line report/report.qtpl:753
+		qw422016.N().S(`,y:`)
+		//line report/report.qtpl:754
+		qw422016.N().FPrec(v, 2)
+		//line report/report.qtpl:754
+		qw422016.N().S(`},`)
+		//line report/report.qtpl:756
+	}
+	//line report/report.qtpl:756
+	qw422016.N().S(`]}]`)
+//line report/report.qtpl:759
+}
+
+//line report/report.qtpl:759
+func (p *Page) writebackendsSeries(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:759
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:759
+	p.streambackendsSeries(qw422016)
+	//line report/report.qtpl:759
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:759
+}
+
+//line report/report.qtpl:759
+func (p *Page) backendsSeries() string {
+	//line report/report.qtpl:759
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:759
+	p.writebackendsSeries(qb422016)
+	//line report/report.qtpl:759
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:759
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:759
 	return qs422016
-//line report/report.qtpl:247
+//line report/report.qtpl:759
 }
 
-//line report/report.qtpl:250
+//line report/report.qtpl:762
 func (p *Page) streamerrorMessagesTable(qw422016 *qt422016.Writer) {
-	//line report/report.qtpl:250
+	//line report/report.qtpl:762
 	qw422016.N().S(`
 	<div style = "float: left; width:50%; height: 400px;">
 	 <!-- IE < 10 does not like giving a tbody a height.  The workaround here applies the scrolling to a wrapped <div>. -->
@@ -783,40 +2286,40 @@ func (p *Page) streamerrorMessagesTable(qw422016 *qt422016.Writer) {
 		 </thead>
 		 <tbody>
 			`)
-	//line report/report.qtpl:265
+	//line report/report.qtpl:777
 	for k, v := range p.ErrorMessages {
-		//line report/report.qtpl:265
+		//line report/report.qtpl:777
 		qw422016.N().S(`
 				<tr>
 					<td>`)
-		//line report/report.qtpl:267
+		//line report/report.qtpl:779
 		qw422016.N().D(v)
-		//line report/report.qtpl:267
+		//line report/report.qtpl:779
 		qw422016.N().S(`</td>
 					<td>`)
-		//line report/report.qtpl:268
+		//line report/report.qtpl:780
 		qw422016.N().S(k)
-		//line report/report.qtpl:268
+		//line report/report.qtpl:780
 		qw422016.N().S(`</td>
 				</tr>
 			`)
-		//line report/report.qtpl:270
+		//line report/report.qtpl:782
 	}
-	//line report/report.qtpl:270
+	//line report/report.qtpl:782
 	qw422016.N().S(`
 			`)
-	//line report/report.qtpl:271
+	//line report/report.qtpl:783
 	if len(p.ErrorMessages) == 0 {
-		//line report/report.qtpl:271
+		//line report/report.qtpl:783
 		qw422016.N().S(`
 			<tr>
 				<td></td>
 				<td>No error messages</td>
 			</tr>
             `)
-		//line report/report.qtpl:276
+		//line report/report.qtpl:788
 	}
-	//line report/report.qtpl:276
+	//line report/report.qtpl:788
 	qw422016.N().S(`
 		 </tbody>
 	 </table>
@@ -825,31 +2328,1358 @@ func (p *Page) streamerrorMessagesTable(qw422016 *qt422016.Writer) {
      <!--<![endif]-->
      </div>
 `)
-//line report/report.qtpl:283
+//line report/report.qtpl:795
 }
 
-//line report/report.qtpl:283
+//line report/report.qtpl:795
 func (p *Page) writeerrorMessagesTable(qq422016 qtio422016.Writer) {
-	//line report/report.qtpl:283
+	//line report/report.qtpl:795
 	qw422016 := qt422016.AcquireWriter(qq422016)
-	//line report/report.qtpl:283
+	//line report/report.qtpl:795
 	p.streamerrorMessagesTable(qw422016)
-	//line report/report.qtpl:283
+	//line report/report.qtpl:795
 	qt422016.ReleaseWriter(qw422016)
-//line report/report.qtpl:283
+//line report/report.qtpl:795
 }
 
-//line report/report.qtpl:283
+//line report/report.qtpl:795
 func (p *Page) errorMessagesTable() string {
-	//line report/report.qtpl:283
+	//line report/report.qtpl:795
 	qb422016 := qt422016.AcquireByteBuffer()
-	//line report/report.qtpl:283
+	//line report/report.qtpl:795
 	p.writeerrorMessagesTable(qb422016)
-	//line report/report.qtpl:283
+	//line report/report.qtpl:795
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:795
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:795
+	return qs422016
+//line report/report.qtpl:795
+}
+
+//line report/report.qtpl:797
+func (p *Page) streamlatencyTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:797
+	qw422016.N().S(`
+	<div style = "float: left; width:50%; height: 400px;">
+	 <!--[if lte IE 9]>
+	 <div class="old_ie_wrapper">
+	 <!--<![endif]-->
+	 <p class = "title">Latency percentiles</p>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Requests</td>
+				<td>p50</td>
+				<td>p90</td>
+				<td>p95</td>
+				<td>p99</td>
+				<td>max</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			<tr>
+				`)
+	//line report/report.qtpl:816
+	if p.FirstRequestLatency != nil {
+		//line report/report.qtpl:816
+		qw422016.N().S(`
+				<td>subsequent</td>
+				`)
+		//line report/report.qtpl:818
+	} else {
+		//line report/report.qtpl:818
+		qw422016.N().S(`
+				<td>all</td>
+				`)
+		//line report/report.qtpl:820
+	}
+	//line report/report.qtpl:820
+	qw422016.N().S(`
+				<td>`)
+	//line report/report.qtpl:821
+	qw422016.E().S(FormatLatency(p.Latency.P50, p.latencyUnit()))
+	//line report/report.qtpl:821
+	qw422016.N().S(`</td>
+				<td>`)
+	//line report/report.qtpl:822
+	qw422016.E().S(FormatLatency(p.Latency.P90, p.latencyUnit()))
+	//line report/report.qtpl:822
+	qw422016.N().S(`</td>
+				<td>`)
+	//line report/report.qtpl:823
+	qw422016.E().S(FormatLatency(p.Latency.P95, p.latencyUnit()))
+	//line report/report.qtpl:823
+	qw422016.N().S(`</td>
+				<td>`)
+	//line report/report.qtpl:824
+	qw422016.E().S(FormatLatency(p.Latency.P99, p.latencyUnit()))
+	//line report/report.qtpl:824
+	qw422016.N().S(`</td>
+				<td>`)
+	//line report/report.qtpl:825
+	qw422016.E().S(FormatLatency(p.Latency.Max, p.latencyUnit()))
+	//line report/report.qtpl:825
+	qw422016.N().S(`</td>
+			</tr>
+			`)
+	//line report/report.qtpl:827
+	if p.FirstRequestLatency != nil {
+		//line report/report.qtpl:827
+		qw422016.N().S(`
+			<tr>
+				<td>first on connection</td>
+				<td>`)
+		//line report/report.qtpl:830
+		qw422016.E().S(FormatLatency(p.FirstRequestLatency.P50, p.latencyUnit()))
+		//line report/report.qtpl:830
+		qw422016.N().S(`</td>
+				<td>`)
+		//line report/report.qtpl:831
+		qw422016.E().S(FormatLatency(p.FirstRequestLatency.P90, p.latencyUnit()))
+		//line report/report.qtpl:831
+		qw422016.N().S(`</td>
+				<td>`)
+		//line report/report.qtpl:832
+		qw422016.E().S(FormatLatency(p.FirstRequestLatency.P95, p.latencyUnit()))
+		//line report/report.qtpl:832
+		qw422016.N().S(`</td>
+				<td>`)
+		//line report/report.qtpl:833
+		qw422016.E().S(FormatLatency(p.FirstRequestLatency.P99, p.latencyUnit()))
+		//line report/report.qtpl:833
+		qw422016.N().S(`</td>
+				<td>`)
+		//line report/report.qtpl:834
+		qw422016.E().S(FormatLatency(p.FirstRequestLatency.Max, p.latencyUnit()))
+		//line report/report.qtpl:834
+		qw422016.N().S(`</td>
+			</tr>
+			`)
+		//line report/report.qtpl:836
+	}
+	//line report/report.qtpl:836
+	qw422016.N().S(`
+			`)
+	//line report/report.qtpl:837
+	if p.CorrectedLatency != nil {
+		//line report/report.qtpl:837
+		qw422016.N().S(`
+			<tr>
+				<td>corrected</td>
+				<td>`)
+		//line report/report.qtpl:840
+		qw422016.E().S(FormatLatency(p.CorrectedLatency.P50, p.latencyUnit()))
+		//line report/report.qtpl:840
+		qw422016.N().S(`</td>
+				<td>`)
+		//line report/report.qtpl:841
+		qw422016.E().S(FormatLatency(p.CorrectedLatency.P90, p.latencyUnit()))
+		//line report/report.qtpl:841
+		qw422016.N().S(`</td>
+				<td>`)
+		//line report/report.qtpl:842
+		qw422016.E().S(FormatLatency(p.CorrectedLatency.P95, p.latencyUnit()))
+		//line report/report.qtpl:842
+		qw422016.N().S(`</td>
+				<td>`)
+		//line report/report.qtpl:843
+		qw422016.E().S(FormatLatency(p.CorrectedLatency.P99, p.latencyUnit()))
+		//line report/report.qtpl:843
+		qw422016.N().S(`</td>
+				<td>`)
+		//line report/report.qtpl:844
+		qw422016.E().S(FormatLatency(p.CorrectedLatency.Max, p.latencyUnit()))
+		//line report/report.qtpl:844
+		qw422016.N().S(`</td>
+			</tr>
+			`)
+		//line report/report.qtpl:846
+	}
+	//line report/report.qtpl:846
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	 <!--[if lte IE 9]>
+     </div>
+     <!--<![endif]-->
+     </div>
+`)
+//line report/report.qtpl:853
+}
+
+//line report/report.qtpl:853
+func (p *Page) writelatencyTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:853
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:853
+	p.streamlatencyTable(qw422016)
+	//line report/report.qtpl:853
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:853
+}
+
+//line report/report.qtpl:853
+func (p *Page) latencyTable() string {
+	//line report/report.qtpl:853
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:853
+	p.writelatencyTable(qb422016)
+	//line report/report.qtpl:853
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:853
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:853
+	return qs422016
+//line report/report.qtpl:853
+}
+
+//line report/report.qtpl:855
+func (p *Page) streamphasesTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:855
+	qw422016.N().S(`
+	<div style = "float: left; width:50%; height: 400px;">
+	 <!--[if lte IE 9]>
+	 <div class="old_ie_wrapper">
+	 <!--<![endif]-->
+	 <p class = "title">Phases</p>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Phase</td>
+				<td>Rps</td>
+				<td>p50</td>
+				<td>p99</td>
+				<td>Errors</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			`)
+	//line report/report.qtpl:872
+	for _, v := range p.Phases {
+		//line report/report.qtpl:872
+		qw422016.N().S(`
+				<tr>
+					<td>`)
+		//line report/report.qtpl:874
+		qw422016.E().S(v.Name)
+		//line report/report.qtpl:874
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:875
+		qw422016.N().FPrec(v.Rps, 2)
+		//line report/report.qtpl:875
+		qw422016.N().S(`</td>
+					`)
+		//line report/report.qtpl:876
+		if v.P99 > 0 {
+			//line report/report.qtpl:876
+			qw422016.N().S(`
+					<td>`)
+			//line report/report.qtpl:877
+			qw422016.E().S(FormatLatency(v.P50, p.latencyUnit()))
+			//line report/report.qtpl:877
+			qw422016.N().S(`</td>
+					<td>`)
+			//line report/report.qtpl:878
+			qw422016.E().S(FormatLatency(v.P99, p.latencyUnit()))
+			//line report/report.qtpl:878
+			qw422016.N().S(`</td>
+					`)
+			//line report/report.qtpl:879
+		} else {
+			//line report/report.qtpl:879
+			qw422016.N().S(`
+					<td>-</td>
+					<td>-</td>
+					`)
+			//line report/report.qtpl:882
+		}
+		//line report/report.qtpl:882
+		qw422016.N().S(`
+					<td>`)
+		//line report/report.qtpl:883
+		qw422016.N().FPrec(v.ErrorRate, 2)
+		//line report/report.qtpl:883
+		qw422016.N().S(` %</td>
+				</tr>
+			`)
+		//line report/report.qtpl:885
+	}
+	//line report/report.qtpl:885
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	 <!--[if lte IE 9]>
+     </div>
+     <!--<![endif]-->
+     </div>
+`)
+//line report/report.qtpl:892
+}
+
+//line report/report.qtpl:892
+func (p *Page) writephasesTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:892
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:892
+	p.streamphasesTable(qw422016)
+	//line report/report.qtpl:892
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:892
+}
+
+//line report/report.qtpl:892
+func (p *Page) phasesTable() string {
+	//line report/report.qtpl:892
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:892
+	p.writephasesTable(qb422016)
+	//line report/report.qtpl:892
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:892
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:892
+	return qs422016
+//line report/report.qtpl:892
+}
+
+//line report/report.qtpl:894
+func (p *Page) streamtargetsTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:894
+	qw422016.N().S(`
+	<div style = "float: left; width:50%; height: 400px;">
+	 <!--[if lte IE 9]>
+	 <div class="old_ie_wrapper">
+	 <!--<![endif]-->
+	 <p class = "title">Requests by target</p>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Url</td>
+				<td>Requests</td>
+				<td>Rps</td>
+				`)
+	//line report/report.qtpl:906
+	if p.hasTargetMaxQPS() {
+		//line report/report.qtpl:906
+		qw422016.N().S(`
+				<td>Max rps</td>
+				`)
+		//line report/report.qtpl:908
+	}
+	//line report/report.qtpl:908
+	qw422016.N().S(`
+				<td>Errors rate</td>
+				<td>p50</td>
+				<td>p90</td>
+				<td>p95</td>
+				<td>p99</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			`)
+	//line report/report.qtpl:917
+	slowest := p.slowestTarget()
+	//line report/report.qtpl:917
+	qw422016.N().S(`
+			`)
+	//line report/report.qtpl:918
+	for i, v := range p.Targets {
+		//line report/report.qtpl:918
+		qw422016.N().S(`
+				<tr`)
+		//line report/report.qtpl:919
+		if i == slowest {
+			//line report/report.qtpl:919
+			qw422016.N().S(` style="color: #c0392b;" title="the slowest target"`)
+			//line report/report.qtpl:919
+		}
+		//line report/report.qtpl:919
+		qw422016.N().S(`>
+					<td>`)
+		//line report/report.qtpl:920
+		qw422016.E().S(v.URL)
+		//line report/report.qtpl:920
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:921
+		qw422016.N().D(int(v.Requests))
+		//line report/report.qtpl:921
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:922
+		qw422016.N().FPrec(v.Rps, 2)
+		//line report/report.qtpl:922
+		qw422016.N().S(`</td>
+					`)
+		//line report/report.qtpl:923
+		if p.hasTargetMaxQPS() {
+			//line report/report.qtpl:923
+			qw422016.N().S(`
+					<td>`)
+			//line report/report.qtpl:924
+			qw422016.E().S(v.formatMaxQPS())
+			//line report/report.qtpl:924
+			qw422016.N().S(`</td>
+					`)
+			//line report/report.qtpl:925
+		}
+		//line report/report.qtpl:925
+		qw422016.N().S(`
+					<td>`)
+		//line report/report.qtpl:926
+		qw422016.N().FPrec(v.errorRate(), 2)
+		//line report/report.qtpl:926
+		qw422016.N().S(`%</td>
+					<td>`)
+		//line report/report.qtpl:927
+		qw422016.E().S(p.formatTargetLatency(v, v.P50))
+		//line report/report.qtpl:927
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:928
+		qw422016.E().S(p.formatTargetLatency(v, v.P90))
+		//line report/report.qtpl:928
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:929
+		qw422016.E().S(p.formatTargetLatency(v, v.P95))
+		//line report/report.qtpl:929
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:930
+		qw422016.E().S(p.formatTargetLatency(v, v.P99))
+		//line report/report.qtpl:930
+		qw422016.N().S(`</td>
+				</tr>
+			`)
+		//line report/report.qtpl:932
+	}
+	//line report/report.qtpl:932
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	 <!--[if lte IE 9]>
+     </div>
+     <!--<![endif]-->
+     </div>
+`)
+//line report/report.qtpl:939
+}
+
+//line report/report.qtpl:939
+func (p *Page) writetargetsTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:939
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:939
+	p.streamtargetsTable(qw422016)
+	//line report/report.qtpl:939
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:939
+}
+
+//line report/report.qtpl:939
+func (p *Page) targetsTable() string {
+	//line report/report.qtpl:939
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:939
+	p.writetargetsTable(qb422016)
+	//line report/report.qtpl:939
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:939
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:939
+	return qs422016
+//line report/report.qtpl:939
+}
+
+//line report/report.qtpl:941
+func (p *Page) streamlatencyBreakdownTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:941
+	qw422016.N().S(`
+	<div style = "float: left; width:50%; height: 400px;">
+	 <!--[if lte IE 9]>
+	 <div class="old_ie_wrapper">
+	 <!--<![endif]-->
+	 <p class = "title">Latency breakdown</p>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Stage</td>
+				<td>Count</td>
+				<td>p50</td>
+				<td>p99</td>
+				<td>Max</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			`)
+	//line report/report.qtpl:958
+	for _, v := range p.LatencyBreakdown {
+		//line report/report.qtpl:958
+		qw422016.N().S(`
+				<tr>
+					<td>`)
+		//line report/report.qtpl:960
+		qw422016.E().S(v.Stage)
+		//line report/report.qtpl:960
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:961
+		qw422016.N().D(int(v.Count))
+		//line report/report.qtpl:961
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:962
+		qw422016.E().S(FormatLatency(v.P50, p.latencyUnit()))
+		//line report/report.qtpl:962
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:963
+		qw422016.E().S(FormatLatency(v.P99, p.latencyUnit()))
+		//line report/report.qtpl:963
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:964
+		qw422016.E().S(FormatLatency(v.Max, p.latencyUnit()))
+		//line report/report.qtpl:964
+		qw422016.N().S(`</td>
+				</tr>
+			`)
+		//line report/report.qtpl:966
+	}
+	//line report/report.qtpl:966
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	 <!--[if lte IE 9]>
+     </div>
+     <!--<![endif]-->
+     </div>
+`)
+//line report/report.qtpl:973
+}
+
+//line report/report.qtpl:973
+func (p *Page) writelatencyBreakdownTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:973
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:973
+	p.streamlatencyBreakdownTable(qw422016)
+	//line report/report.qtpl:973
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:973
+}
+
+//line report/report.qtpl:973
+func (p *Page) latencyBreakdownTable() string {
+	//line report/report.qtpl:973
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:973
+	p.writelatencyBreakdownTable(qb422016)
+	//line report/report.qtpl:973
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:973
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:973
+	return qs422016
+//line report/report.qtpl:973
+}
+
+//line report/report.qtpl:975
+func (p *Page) streamlatencyByRegionTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:975
+	qw422016.N().S(`
+	<div style = "float: left; width:50%; height: 400px;">
+	 <!--[if lte IE 9]>
+	 <div class="old_ie_wrapper">
+	 <!--<![endif]-->
+	 <p class = "title">Latency by region</p>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Region</td>
+				<td>RTT</td>
+				<td>Requests</td>
+				<td>p50</td>
+				<td>p90</td>
+				<td>p99</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			`)
+	//line report/report.qtpl:993
+	for _, v := range p.LatencyByRegion {
+		//line report/report.qtpl:993
+		qw422016.N().S(`
+				<tr>
+					<td>`)
+		//line report/report.qtpl:995
+		qw422016.E().S(v.Region)
+		//line report/report.qtpl:995
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:996
+		qw422016.E().S(v.RTT)
+		//line report/report.qtpl:996
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:997
+		qw422016.N().D(int(v.Requests))
+		//line report/report.qtpl:997
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:998
+		qw422016.E().S(p.formatRegionLatency(v, v.P50))
+		//line report/report.qtpl:998
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:999
+		qw422016.E().S(p.formatRegionLatency(v, v.P90))
+		//line report/report.qtpl:999
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1000
+		qw422016.E().S(p.formatRegionLatency(v, v.P99))
+		//line report/report.qtpl:1000
+		qw422016.N().S(`</td>
+				</tr>
+			`)
+		//line report/report.qtpl:1002
+	}
+	//line report/report.qtpl:1002
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	 <!--[if lte IE 9]>
+     </div>
+     <!--<![endif]-->
+     </div>
+`)
+//line report/report.qtpl:1009
+}
+
+//line report/report.qtpl:1009
+func (p *Page) writelatencyByRegionTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:1009
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:1009
+	p.streamlatencyByRegionTable(qw422016)
+	//line report/report.qtpl:1009
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:1009
+}
+
+//line report/report.qtpl:1009
+func (p *Page) latencyByRegionTable() string {
+	//line report/report.qtpl:1009
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:1009
+	p.writelatencyByRegionTable(qb422016)
+	//line report/report.qtpl:1009
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:1009
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:1009
+	return qs422016
+//line report/report.qtpl:1009
+}
+
+//line report/report.qtpl:1011
+func (p *Page) streamdriftsTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:1011
+	qw422016.N().S(`
+	<div style = "float: left; width:50%; height: 400px;">
+	 <!--[if lte IE 9]>
+	 <div class="old_ie_wrapper">
+	 <!--<![endif]-->
+	 <p class = "title">Trends over steady phase</p>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Metric</td>
+				<td>Start</td>
+				<td>End</td>
+				<td>Growth</td>
+				<td>t</td>
+				<td>Significant</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			`)
+	//line report/report.qtpl:1029
+	for _, d := range p.Drifts {
+		//line report/report.qtpl:1029
+		qw422016.N().S(`
+				<tr>
+					<td>`)
+		//line report/report.qtpl:1031
+		qw422016.E().S(d.Metric)
+		//line report/report.qtpl:1031
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1032
+		qw422016.E().S(p.formatDrift(d, d.Start))
+		//line report/report.qtpl:1032
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1033
+		qw422016.E().S(p.formatDrift(d, d.End))
+		//line report/report.qtpl:1033
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1034
+		qw422016.E().S(d.FormatGrowth())
+		//line report/report.qtpl:1034
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1035
+		qw422016.N().FPrec(d.T, 2)
+		//line report/report.qtpl:1035
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1036
+		if d.Significant {
+			//line report/report.qtpl:1036
+			qw422016.N().S(`yes`)
+			//line report/report.qtpl:1036
+		} else {
+			//line report/report.qtpl:1036
+			qw422016.N().S(`no`)
+			//line report/report.qtpl:1036
+		}
+		//line report/report.qtpl:1036
+		qw422016.N().S(`</td>
+				</tr>
+			`)
+		//line report/report.qtpl:1038
+	}
+	//line report/report.qtpl:1038
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	 <!--[if lte IE 9]>
+     </div>
+     <!--<![endif]-->
+     </div>
+`)
+//line report/report.qtpl:1045
+}
+
+//line report/report.qtpl:1045
+func (p *Page) writedriftsTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:1045
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:1045
+	p.streamdriftsTable(qw422016)
+	//line report/report.qtpl:1045
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:1045
+}
+
+//line report/report.qtpl:1045
+func (p *Page) driftsTable() string {
+	//line report/report.qtpl:1045
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:1045
+	p.writedriftsTable(qb422016)
+	//line report/report.qtpl:1045
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:1045
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:1045
+	return qs422016
+//line report/report.qtpl:1045
+}
+
+//line report/report.qtpl:1047
+func (p *Page) streamassertionFailuresTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:1047
+	qw422016.N().S(`
+	<div style = "float: left; width:50%; height: 400px;">
+	 <!--[if lte IE 9]>
+	 <div class="old_ie_wrapper">
+	 <!--<![endif]-->
+	 <p class = "title">Assertion failures</p>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Failures</td>
+				<td>Assertion</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			`)
+	//line report/report.qtpl:1061
+	for k, v := range p.AssertionFailures {
+		//line report/report.qtpl:1061
+		qw422016.N().S(`
+				<tr>
+					<td>`)
+		//line report/report.qtpl:1063
+		qw422016.N().D(int(v))
+		//line report/report.qtpl:1063
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1064
+		qw422016.E().S(k)
+		//line report/report.qtpl:1064
+		qw422016.N().S(`</td>
+				</tr>
+			`)
+		//line report/report.qtpl:1066
+	}
+	//line report/report.qtpl:1066
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	 <!--[if lte IE 9]>
+     </div>
+     <!--<![endif]-->
+     </div>
+`)
+//line report/report.qtpl:1073
+}
+
+//line report/report.qtpl:1073
+func (p *Page) writeassertionFailuresTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:1073
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:1073
+	p.streamassertionFailuresTable(qw422016)
+	//line report/report.qtpl:1073
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:1073
+}
+
+//line report/report.qtpl:1073
+func (p *Page) assertionFailuresTable() string {
+	//line report/report.qtpl:1073
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:1073
+	p.writeassertionFailuresTable(qb422016)
+	//line report/report.qtpl:1073
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:1073
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:1073
+	return qs422016
+//line report/report.qtpl:1073
+}
+
+//line report/report.qtpl:1075
+func (p *Page) streamstatusCountsTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:1075
+	qw422016.N().S(`
+	<div style = "float: left; width:50%; height: 400px;">
+	 <!--[if lte IE 9]>
+	 <div class="old_ie_wrapper">
+	 <!--<![endif]-->
+	 <p class = "title">Responses by status</p>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Status</td>
+				<td>Responses</td>
+				<td>Percent</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			`)
+	//line report/report.qtpl:1090
+	for _, v := range StatusClasses(p.StatusCounts) {
+		//line report/report.qtpl:1090
+		qw422016.N().S(`
+				<tr>
+					<td><b>`)
+		//line report/report.qtpl:1092
+		qw422016.E().S(v.Status)
+		//line report/report.qtpl:1092
+		qw422016.N().S(`</b></td>
+					<td><b>`)
+		//line report/report.qtpl:1093
+		qw422016.N().D(int(v.Requests))
+		//line report/report.qtpl:1093
+		qw422016.N().S(`</b></td>
+					<td><b>`)
+		//line report/report.qtpl:1094
+		qw422016.N().FPrec(p.statusPercent(v.Requests), 2)
+		//line report/report.qtpl:1094
+		qw422016.N().S(` %</b></td>
+				</tr>
+			`)
+		//line report/report.qtpl:1096
+	}
+	//line report/report.qtpl:1096
+	qw422016.N().S(`
+			`)
+	//line report/report.qtpl:1097
+	for _, v := range SortedStatusCounts(p.StatusCounts) {
+		//line report/report.qtpl:1097
+		qw422016.N().S(`
+				<tr>
+					<td>`)
+		//line report/report.qtpl:1099
+		qw422016.E().S(v.Status)
+		//line report/report.qtpl:1099
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1100
+		qw422016.N().D(int(v.Requests))
+		//line report/report.qtpl:1100
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1101
+		qw422016.N().FPrec(p.statusPercent(v.Requests), 2)
+		//line report/report.qtpl:1101
+		qw422016.N().S(` %</td>
+				</tr>
+			`)
+		//line report/report.qtpl:1103
+	}
+	//line report/report.qtpl:1103
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	 <!--[if lte IE 9]>
+     </div>
+     <!--<![endif]-->
+     </div>
+`)
+//line report/report.qtpl:1110
+}
+
+//line report/report.qtpl:1110
+func (p *Page) writestatusCountsTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:1110
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:1110
+	p.streamstatusCountsTable(qw422016)
+	//line report/report.qtpl:1110
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:1110
+}
+
+//line report/report.qtpl:1110
+func (p *Page) statusCountsTable() string {
+	//line report/report.qtpl:1110
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:1110
+	p.writestatusCountsTable(qb422016)
+	//line report/report.qtpl:1110
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:1110
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:1110
+	return qs422016
+//line report/report.qtpl:1110
+}
+
+//line report/report.qtpl:1112
+func (p *Page) streamlatencyBySizeTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:1112
+	qw422016.N().S(`
+	<div style = "float: left; width:50%; height: 400px;">
+	 <!--[if lte IE 9]>
+	 <div class="old_ie_wrapper">
+	 <!--<![endif]-->
+	 <p class = "title">Latency by response size</p>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Body size</td>
+				<td>Requests</td>
+				<td>p50</td>
+				<td>p90</td>
+				<td>p99</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			`)
+	//line report/report.qtpl:1129
+	for _, v := range p.LatencyBySize {
+		//line report/report.qtpl:1129
+		qw422016.N().S(`
+				<tr>
+					<td>`)
+		//line report/report.qtpl:1131
+		qw422016.E().S(v.Size)
+		//line report/report.qtpl:1131
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1132
+		qw422016.N().D(int(v.Requests))
+		//line report/report.qtpl:1132
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1133
+		qw422016.E().S(p.formatSizeLatency(v, v.P50))
+		//line report/report.qtpl:1133
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1134
+		qw422016.E().S(p.formatSizeLatency(v, v.P90))
+		//line report/report.qtpl:1134
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1135
+		qw422016.E().S(p.formatSizeLatency(v, v.P99))
+		//line report/report.qtpl:1135
+		qw422016.N().S(`</td>
+				</tr>
+			`)
+		//line report/report.qtpl:1137
+	}
+	//line report/report.qtpl:1137
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	 <!--[if lte IE 9]>
+     </div>
+     <!--<![endif]-->
+     </div>
+`)
+//line report/report.qtpl:1144
+}
+
+//line report/report.qtpl:1144
+func (p *Page) writelatencyBySizeTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:1144
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:1144
+	p.streamlatencyBySizeTable(qw422016)
+	//line report/report.qtpl:1144
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:1144
+}
+
+//line report/report.qtpl:1144
+func (p *Page) latencyBySizeTable() string {
+	//line report/report.qtpl:1144
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:1144
+	p.writelatencyBySizeTable(qb422016)
+	//line report/report.qtpl:1144
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:1144
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:1144
+	return qs422016
+//line report/report.qtpl:1144
+}
+
+//line report/report.qtpl:1146
+func (p *Page) streamincidentsTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:1146
+	qw422016.N().S(`
+	<div style = "float: left; width:50%; height: 400px;">
+	 <!--[if lte IE 9]>
+	 <div class="old_ie_wrapper">
+	 <!--<![endif]-->
+	 <p class = "title">Incidents (errors rate above `)
+	//line report/report.qtpl:1151
+	qw422016.N().FPrec(p.IncidentThreshold, 2)
+	//line report/report.qtpl:1151
+	qw422016.N().S(`%)</p>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Start, s</td>
+				<td>Duration, s</td>
+				<td>Peak errors rate, %</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			`)
+	//line report/report.qtpl:1161
+	incidents := p.Incidents(p.IncidentThreshold)
+	//line report/report.qtpl:1161
+	qw422016.N().S(`
+			`)
+	//line report/report.qtpl:1162
+	for _, v := range incidents {
+		//line report/report.qtpl:1162
+		qw422016.N().S(`
+				<tr>
+					<td>`)
+		//line report/report.qtpl:1164
+		qw422016.N().FPrec(v.Start, 2)
+		//line report/report.qtpl:1164
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1165
+		qw422016.N().FPrec(v.Duration, 2)
+		//line report/report.qtpl:1165
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1166
+		qw422016.N().FPrec(v.PeakErrorRate, 2)
+		//line report/report.qtpl:1166
+		qw422016.N().S(`</td>
+				</tr>
+			`)
+		//line report/report.qtpl:1168
+	}
+	//line report/report.qtpl:1168
+	qw422016.N().S(`
+			`)
+	//line report/report.qtpl:1169
+	if len(incidents) == 0 {
+		//line report/report.qtpl:1169
+		qw422016.N().S(`
+			<tr>
+				<td></td>
+				<td>No incidents</td>
+				<td></td>
+			</tr>
+			`)
+		//line report/report.qtpl:1175
+	}
+	//line report/report.qtpl:1175
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	 <!--[if lte IE 9]>
+     </div>
+     <!--<![endif]-->
+     </div>
+`)
+//line report/report.qtpl:1182
+}
+
+//line report/report.qtpl:1182
+func (p *Page) writeincidentsTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:1182
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:1182
+	p.streamincidentsTable(qw422016)
+	//line report/report.qtpl:1182
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:1182
+}
+
+//line report/report.qtpl:1182
+func (p *Page) incidentsTable() string {
+	//line report/report.qtpl:1182
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:1182
+	p.writeincidentsTable(qb422016)
+	//line report/report.qtpl:1182
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:1182
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:1182
+	return qs422016
+//line report/report.qtpl:1182
+}
+
+//line report/report.qtpl:1184
+func (p *Page) streamfailedRequests(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:1184
+	qw422016.N().S(`
+	<div style = "clear: both; padding-top: 20px;">
+	 <p class = "title">Failed requests (`)
+	//line report/report.qtpl:1186
+	qw422016.N().D(len(p.FailedRequests))
+	//line report/report.qtpl:1186
+	qw422016.N().S(` captured)</p>
+	 `)
+	//line report/report.qtpl:1187
+	for _, v := range p.FailedRequests {
+		//line report/report.qtpl:1187
+		qw422016.N().S(`
+	 <details>
+		<summary>`)
+		//line report/report.qtpl:1189
+		qw422016.N().FPrec(v.Time, 2)
+		//line report/report.qtpl:1189
+		qw422016.N().S(`s: `)
+		//line report/report.qtpl:1189
+		qw422016.E().S(v.Failure)
+		//line report/report.qtpl:1189
+		qw422016.N().S(`</summary>
+		<pre>`)
+		//line report/report.qtpl:1190
+		qw422016.E().S(v.Request)
+		//line report/report.qtpl:1190
+		qw422016.N().S(`</pre>
+		`)
+		//line report/report.qtpl:1191
+		if v.Response != "" {
+			//line report/report.qtpl:1191
+			qw422016.N().S(`
+		<pre>`)
+			//line report/report.qtpl:1192
+			qw422016.E().S(v.Response)
+			//line report/report.qtpl:1192
+			qw422016.N().S(`</pre>
+		`)
+			//line report/report.qtpl:1193
+		} else {
+			//line report/report.qtpl:1193
+			qw422016.N().S(`
+		<p>Response wasn't received</p>
+		`)
+			//line report/report.qtpl:1195
+		}
+		//line report/report.qtpl:1195
+		qw422016.N().S(`
+	 </details>
+	 `)
+		//line report/report.qtpl:1197
+	}
+	//line report/report.qtpl:1197
+	qw422016.N().S(`
+	</div>
+`)
+//line report/report.qtpl:1199
+}
+
+//line report/report.qtpl:1199
+func (p *Page) writefailedRequests(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:1199
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:1199
+	p.streamfailedRequests(qw422016)
+	//line report/report.qtpl:1199
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:1199
+}
+
+//line report/report.qtpl:1199
+func (p *Page) failedRequests() string {
+	//line report/report.qtpl:1199
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:1199
+	p.writefailedRequests(qb422016)
+	//line report/report.qtpl:1199
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:1199
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:1199
+	return qs422016
+//line report/report.qtpl:1199
+}
+
+//line report/report.qtpl:1201
+func (p *Page) streamconfigTable(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:1201
+	qw422016.N().S(`
+	<details style="clear: both;">
+	 <summary class="title">Configuration</summary>
+	 <table class="fixed_headers">
+		 <thead>
+			<tr>
+				<td>Flag</td>
+				<td>Value</td>
+			</tr>
+		 </thead>
+		 <tbody>
+			`)
+	//line report/report.qtpl:1212
+	for _, e := range p.Metadata.configEntries() {
+		//line report/report.qtpl:1212
+		qw422016.N().S(`
+				<tr>
+					<td>`)
+		//line report/report.qtpl:1214
+		qw422016.E().S(e.name)
+		//line report/report.qtpl:1214
+		qw422016.N().S(`</td>
+					<td>`)
+		//line report/report.qtpl:1215
+		qw422016.E().S(e.value)
+		//line report/report.qtpl:1215
+		qw422016.N().S(`</td>
+				</tr>
+			`)
+		//line report/report.qtpl:1217
+	}
+	//line report/report.qtpl:1217
+	qw422016.N().S(`
+		 </tbody>
+	 </table>
+	</details>
+`)
+//line report/report.qtpl:1221
+}
+
+//line report/report.qtpl:1221
+func (p *Page) writeconfigTable(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:1221
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:1221
+	p.streamconfigTable(qw422016)
+	//line report/report.qtpl:1221
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:1221
+}
+
+//line report/report.qtpl:1221
+func (p *Page) configTable() string {
+	//line report/report.qtpl:1221
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:1221
+	p.writeconfigTable(qb422016)
+	//line report/report.qtpl:1221
+	qs422016 := string(qb422016.B)
+	//line report/report.qtpl:1221
+	qt422016.ReleaseByteBuffer(qb422016)
+	//line report/report.qtpl:1221
+	return qs422016
+//line report/report.qtpl:1221
+}
+
+//line report/report.qtpl:1223
+func (p *Page) streamrawSamples(qw422016 *qt422016.Writer) {
+	//line report/report.qtpl:1223
+	qw422016.N().S(`
+	<script type="application/json" id="raw-samples">`)
+	//line report/report.qtpl:1224
+	qw422016.N().S(p.rawSamplesJSON())
+	//line report/report.qtpl:1224
+	qw422016.N().S(`</script>
+	<p style="text-align: center;">
+		<button id="raw-samples-download">Download data (JSON)</button>
+	</p>
+	<script>
+	$(function () {
+		$('#raw-samples-download').click(function () {
+			var blob = new Blob([$('#raw-samples').text()], {type: 'application/json'});
+			var link = $('<a download="samples.json"></a>').attr('href', URL.createObjectURL(blob));
+			$('body').append(link);
+			link[0].click();
+			link.remove();
+		});
+	});
+	</script>
+`)
+//line report/report.qtpl:1239
+}
+
+//line report/report.qtpl:1239
+func (p *Page) writerawSamples(qq422016 qtio422016.Writer) {
+	//line report/report.qtpl:1239
+	qw422016 := qt422016.AcquireWriter(qq422016)
+	//line report/report.qtpl:1239
+	p.streamrawSamples(qw422016)
+	//line report/report.qtpl:1239
+	qt422016.ReleaseWriter(qw422016)
+//line report/report.qtpl:1239
+}
+
+//line report/report.qtpl:1239
+func (p *Page) rawSamples() string {
+	//line report/report.qtpl:1239
+	qb422016 := qt422016.AcquireByteBuffer()
+	//line report/report.qtpl:1239
+	p.writerawSamples(qb422016)
+	//line report/report.qtpl:1239
 	qs422016 := string(qb422016.B)
-	//line report/report.qtpl:283
+	//line report/report.qtpl:1239
 	qt422016.ReleaseByteBuffer(qb422016)
-	//line report/report.qtpl:283
+	//line report/report.qtpl:1239
 	return qs422016
-//line report/report.qtpl:283
+//line report/report.qtpl:1239
 }
@@ -0,0 +1,30 @@
+package report
+
+import (
+	"math"
+)
+
+// durations returns values of given quantile for every sample in latency unit of page.
+// Values calculated from less than MinSamples requests are replaced by NaN
+func (p *Page) durations(q float64) []float64 {
+	d := p.RequestDuration[q]
+	scale := LatencyUnits[p.latencyUnit()]
+	result := make([]float64, len(d))
+	for i, v := range d {
+		if i < len(p.RequestSum) && p.RequestSum[i] < p.MinSamples {
+			v = math.NaN()
+		}
+		result[i] = v * scale
+	}
+
+	return result
+}
+
+func (p *Page) hasInsufficientSamples() bool {
+	for _, v := range p.RequestSum {
+		if v < p.MinSamples {
+			return true
+		}
+	}
+	return false
+}
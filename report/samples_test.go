@@ -0,0 +1,49 @@
+package report
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDurations(t *testing.T) {
+	p := &Page{
+		LatencyUnit:     "ms",
+		MinSamples:      100,
+		RequestSum:      []uint64{50, 100, 200},
+		RequestDuration: map[float64][]float64{0.99: {0.5, 0.02, 0.03}},
+	}
+	d := p.durations(0.99)
+	if len(d) != 3 {
+		t.Fatalf("Unexpected number of durations. Got: %d; Expected: %d", len(d), 3)
+	}
+	// percentile of sample with too few requests isn't displayed
+	if !math.IsNaN(d[0]) {
+		t.Errorf("Unexpected duration of insufficient sample. Got: %v; Expected: NaN", d[0])
+	}
+	if d[1] != 20 || d[2] != 30 {
+		t.Errorf("Unexpected durations of sufficient samples. Got: %v; Expected: [20 30]", d[1:])
+	}
+	if s := p.series(d); s != "null,20.00000000,30.00000000" {
+		t.Errorf("Unexpected series of durations. Got: %q; Expected: %q", s, "null,20.00000000,30.00000000")
+	}
+}
+
+func TestHasInsufficientSamples(t *testing.T) {
+	f := func(requests []uint64, min uint64, expected bool) {
+		t.Helper()
+		p := &Page{RequestSum: requests, MinSamples: min}
+		if got := p.hasInsufficientSamples(); got != expected {
+			t.Errorf("Unexpected insufficient samples of %v with min %d. Got: %v; Expected: %v", requests, min, got, expected)
+		}
+		html := PrintPage(p)
+		if got := strings.Contains(html, "insufficient data"); got != expected {
+			t.Errorf("Unexpected note of insufficient data in report of %v with min %d. Got: %v; Expected: %v", requests, min, got, expected)
+		}
+	}
+
+	f([]uint64{100, 200}, 100, false)
+	f([]uint64{100, 99, 200}, 100, true)
+	// every sample is sufficient without min
+	f([]uint64{0, 1}, 0, false)
+}
@@ -0,0 +1,35 @@
+package report
+
+import "math"
+
+// connSetupQuantiles are quantiles of time of connection setup, which are charted
+var connSetupQuantiles = []float64{0.5, 0.99}
+
+// UpdateConnSetupDuration appends time of TCP connect and TLS handshake
+// to series of the last sample. Quantiles without observations are NaN
+func (p *Page) UpdateConnSetupDuration(connect, handshake map[float64]float64) {
+	appendAligned(p.ConnectDuration, connect, len(p.Connections))
+	appendAligned(p.HandshakeDuration, handshake, len(p.Connections))
+}
+
+// appendAligned appends values of d to series of sample n. Series which begin
+// after the first sample, e.g. of resumed test, are padded to align with samples
+func appendAligned(series map[float64][]float64, d map[float64]float64, n int) {
+	for k, v := range d {
+		s := series[k]
+		for len(s)+1 < n {
+			s = append(s, math.NaN())
+		}
+		series[k] = append(s, v)
+	}
+}
+
+// scaled returns values of s in latency unit of report
+func (p *Page) scaled(s []float64) []float64 {
+	scale := LatencyUnits[p.latencyUnit()]
+	result := make([]float64, len(s))
+	for i, v := range s {
+		result[i] = v * scale
+	}
+	return result
+}
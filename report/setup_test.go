@@ -0,0 +1,24 @@
+package report
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUpdateConnSetupDuration(t *testing.T) {
+	p := &Page{
+		ConnectDuration:   make(map[float64][]float64),
+		HandshakeDuration: make(map[float64][]float64),
+	}
+	p.Connections = []uint64{1, 1}
+	p.UpdateConnSetupDuration(map[float64]float64{0.5: 0.1}, nil)
+	p.Connections = append(p.Connections, 1)
+	p.UpdateConnSetupDuration(map[float64]float64{0.5: 0.2}, map[float64]float64{0.5: 0.3})
+
+	if s := p.ConnectDuration[0.5]; len(s) != 3 || !math.IsNaN(s[0]) || s[1] != 0.1 || s[2] != 0.2 {
+		t.Errorf("Unexpected connect series: %v; Expected: [NaN 0.1 0.2]", s)
+	}
+	if s := p.HandshakeDuration[0.5]; len(s) != 3 || !math.IsNaN(s[0]) || !math.IsNaN(s[1]) || s[2] != 0.3 {
+		t.Errorf("Unexpected handshake series: %v; Expected: [NaN NaN 0.3]", s)
+	}
+}
@@ -0,0 +1,23 @@
+package report
+
+// SizeLatency contains latency of responses with body size in range
+type SizeLatency struct {
+	// Size is a range of body size like "1KB-10KB"
+	Size string `json:"size"`
+
+	Requests uint64 `json:"requests"`
+
+	// P50, P90 and P99 are measured in seconds
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+// formatSizeLatency formats latency of size range in unit of report.
+// Latency of ranges with less than MinSamples requests isn't displayed
+func (p *Page) formatSizeLatency(sl SizeLatency, seconds float64) string {
+	if sl.Requests < p.MinSamples {
+		return "insufficient data"
+	}
+	return FormatLatency(seconds, p.latencyUnit())
+}
@@ -0,0 +1,76 @@
+package report
+
+import (
+	"sort"
+	"strconv"
+)
+
+// StatusCount is a number of responses with status code like "429" or of status class like "4xx"
+type StatusCount struct {
+	Status   string
+	Requests uint64
+}
+
+// SortedStatusCounts returns counts of status codes sorted by code
+func SortedStatusCounts(counts map[int]uint64) []StatusCount {
+	codes := make([]int, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	result := make([]StatusCount, len(codes))
+	for i, code := range codes {
+		result[i] = StatusCount{Status: strconv.Itoa(code), Requests: counts[code]}
+	}
+	return result
+}
+
+// StatusClasses returns counts of status classes like "2xx" sorted by class
+func StatusClasses(counts map[int]uint64) []StatusCount {
+	classes := make(map[int]uint64)
+	for code, n := range counts {
+		classes[code/100] += n
+	}
+	var result []StatusCount
+	for _, sc := range SortedStatusCounts(classes) {
+		result = append(result, StatusCount{Status: sc.Status + "xx", Requests: sc.Requests})
+	}
+	return result
+}
+
+// statusPercent returns percent of responses with status code among all responses
+func (p *Page) statusPercent(n uint64) float64 {
+	var total uint64
+	for _, v := range p.StatusCounts {
+		total += v
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total) * 100
+}
+
+// UpdateStatusCodeSeries appends numbers of responses of every status code to series of the last sample.
+// Series of status codes, which appear after the first sample, are padded with zeros
+func (p *Page) UpdateStatusCodeSeries(counts map[int]uint64) {
+	if p.StatusCodeSeries == nil {
+		p.StatusCodeSeries = make(map[int][]uint64)
+	}
+	for code, v := range counts {
+		s := p.StatusCodeSeries[code]
+		for len(s)+1 < len(p.RequestSum) {
+			s = append(s, 0)
+		}
+		p.StatusCodeSeries[code] = append(s, v)
+	}
+}
+
+// statusCodeSeriesCodes returns status codes of StatusCodeSeries in ascending order
+func (p *Page) statusCodeSeriesCodes() []int {
+	codes := make([]int, 0, len(p.StatusCodeSeries))
+	for code := range p.StatusCodeSeries {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
@@ -0,0 +1,40 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatusCounts(t *testing.T) {
+	counts := map[int]uint64{503: 1, 200: 5, 429: 3, 204: 2}
+
+	codes := SortedStatusCounts(counts)
+	expected := []StatusCount{{"200", 5}, {"204", 2}, {"429", 3}, {"503", 1}}
+	if !reflect.DeepEqual(codes, expected) {
+		t.Errorf("Unexpected status codes. Got: %v; Expected: %v", codes, expected)
+	}
+
+	classes := StatusClasses(counts)
+	expected = []StatusCount{{"2xx", 7}, {"4xx", 3}, {"5xx", 1}}
+	if !reflect.DeepEqual(classes, expected) {
+		t.Errorf("Unexpected status classes. Got: %v; Expected: %v", classes, expected)
+	}
+}
+
+func TestUpdateStatusCodeSeries(t *testing.T) {
+	p := &Page{}
+	p.RequestSum = append(p.RequestSum, 5)
+	p.UpdateStatusCodeSeries(map[int]uint64{200: 5})
+	p.RequestSum = append(p.RequestSum, 10)
+	p.UpdateStatusCodeSeries(map[int]uint64{200: 8})
+	p.RequestSum = append(p.RequestSum, 15)
+	p.UpdateStatusCodeSeries(map[int]uint64{200: 10, 429: 5})
+
+	expected := map[int][]uint64{200: {5, 8, 10}, 429: {0, 0, 5}}
+	if !reflect.DeepEqual(p.StatusCodeSeries, expected) {
+		t.Errorf("Unexpected status code series. Got: %v; Expected: %v", p.StatusCodeSeries, expected)
+	}
+	if codes := p.statusCodeSeriesCodes(); !reflect.DeepEqual(codes, []int{200, 429}) {
+		t.Errorf("Unexpected status codes of series. Got: %v; Expected: %v", codes, []int{200, 429})
+	}
+}
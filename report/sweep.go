@@ -0,0 +1,68 @@
+package report
+
+import (
+	"math"
+)
+
+// SweepLevel contains results of load phase run with specific number of clients, body size or protocol
+type SweepLevel struct {
+	// Value is a number of clients, body size in bytes or major version of HTTP
+	Value uint64
+
+	Rps       float64
+	ErrorRate float64
+	P99       float64
+
+	Requests uint64
+
+	// Latency and Connections are compared between protocols
+	Latency     Latency
+	Connections uint64
+}
+
+// sweepValues returns number of clients or body size of every sweep level
+func (p *Page) sweepValues() []uint64 {
+	result := make([]uint64, len(p.Sweep))
+	for i, l := range p.Sweep {
+		result[i] = l.Value
+	}
+	return result
+}
+
+// sweepAxis returns title of axis with sweep values
+func (p *Page) sweepAxis() string {
+	if p.SweepOf == "body-size" {
+		return "Body size, bytes"
+	}
+	return "Clients"
+}
+
+// sweepUnit returns unit of sweep values for tooltips
+func (p *Page) sweepUnit() string {
+	if p.SweepOf == "body-size" {
+		return "bytes"
+	}
+	return "clients"
+}
+
+func (p *Page) sweepRps() []float64 {
+	result := make([]float64, len(p.Sweep))
+	for i, l := range p.Sweep {
+		result[i] = l.Rps
+	}
+	return result
+}
+
+// sweepP99 returns p99 latency of every sweep level in latency unit of page.
+// Values calculated from less than MinSamples requests are replaced by NaN
+func (p *Page) sweepP99() []float64 {
+	scale := LatencyUnits[p.latencyUnit()]
+	result := make([]float64, len(p.Sweep))
+	for i, l := range p.Sweep {
+		result[i] = l.P99 * scale
+		if l.Requests < p.MinSamples {
+			result[i] = math.NaN()
+		}
+	}
+	return result
+}
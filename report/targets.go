@@ -0,0 +1,128 @@
+package report
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// TargetStat contains number of requests sent to url, number of them which failed and their latency
+type TargetStat struct {
+	URL      string `json:"url"`
+	Requests uint64 `json:"requests"`
+	Errors   uint64 `json:"errors"`
+
+	// Rps is an average rate of requests to url over load phase
+	Rps float64 `json:"rps"`
+	// MaxQPS is a max rate of url. Is zero if rate isn't limited
+	MaxQPS float64 `json:"max_qps,omitempty"`
+	// Capped is a number of requests sent to other urls, since MaxQPS was reached.
+	// MaxQPS was a binding constraint of rate if it is positive
+	Capped uint64 `json:"capped,omitempty"`
+
+	// P50, P90, P95 and P99 are measured in seconds
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// errorRate returns share of failed requests to url in percents
+func (ts TargetStat) errorRate() float64 {
+	if ts.Requests == 0 {
+		return 0
+	}
+	return float64(ts.Errors) / float64(ts.Requests) * 100
+}
+
+// hasTargetMaxQPS reports whether rate of any url is limited
+func (p *Page) hasTargetMaxQPS() bool {
+	for _, ts := range p.Targets {
+		if ts.MaxQPS > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// formatMaxQPS formats max rate of url like "10.00 (binding)". Is "-" if rate isn't limited
+func (ts TargetStat) formatMaxQPS() string {
+	if ts.MaxQPS == 0 {
+		return "-"
+	}
+	s := strconv.FormatFloat(ts.MaxQPS, 'f', 2, 64)
+	if ts.Capped > 0 {
+		s += " (binding)"
+	}
+	return s
+}
+
+// formatTargetLatency formats latency of url in unit of report.
+// Latency of urls with less than MinSamples requests isn't displayed
+func (p *Page) formatTargetLatency(ts TargetStat, seconds float64) string {
+	if ts.Requests < p.MinSamples {
+		return "insufficient data"
+	}
+	return FormatLatency(seconds, p.latencyUnit())
+}
+
+// slowestTarget returns index of url with the highest p99 latency among ones with at least MinSamples requests,
+// so the slowest route stands out in table. Is -1 if there is no such url
+func (p *Page) slowestTarget() int {
+	slowest := -1
+	for i, ts := range p.Targets {
+		if ts.Requests >= p.MinSamples && (slowest < 0 || ts.P99 > p.Targets[slowest].P99) {
+			slowest = i
+		}
+	}
+	return slowest
+}
+
+// UpdateTargetDuration appends p99 latency in seconds of every url to TargetDuration.
+// Series of urls, which appear after the first sample, are padded to align with samples
+func (p *Page) UpdateTargetDuration(p99 map[string]float64) {
+	if p.TargetDuration == nil {
+		p.TargetDuration = make(map[string][]float64)
+	}
+	for name, v := range p99 {
+		s := p.TargetDuration[name]
+		for len(s)+1 < len(p.Connections) {
+			s = append(s, math.NaN())
+		}
+		p.TargetDuration[name] = append(s, v)
+	}
+}
+
+// targetNames returns urls of TargetDuration in order of Targets. Urls missing in Targets follow in alphabetical order
+func (p *Page) targetNames() []string {
+	var names, rest []string
+	seen := make(map[string]bool, len(p.Targets))
+	for _, ts := range p.Targets {
+		if _, ok := p.TargetDuration[ts.URL]; ok && !seen[ts.URL] {
+			names = append(names, ts.URL)
+			seen[ts.URL] = true
+		}
+	}
+	for name := range p.TargetDuration {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// targetDurations returns p99 latency of url in unit of report.
+// Latency of samples with less than MinSamples requests is NaN
+func (p *Page) targetDurations(name string) []float64 {
+	d := p.TargetDuration[name]
+	scale := LatencyUnits[p.latencyUnit()]
+	result := make([]float64, len(d))
+	for i, v := range d {
+		if i < len(p.RequestSum) && p.RequestSum[i] < p.MinSamples {
+			v = math.NaN()
+		}
+		result[i] = v * scale
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUpdateTargetDuration(t *testing.T) {
+	p := &Page{}
+	p.Connections = append(p.Connections, 1)
+	p.UpdateTargetDuration(map[string]float64{"/items": 0.1})
+	p.Connections = append(p.Connections, 1)
+	p.UpdateTargetDuration(map[string]float64{"/items": 0.2, "/cart": 0.5})
+
+	if s := p.TargetDuration["/items"]; !reflect.DeepEqual(s, []float64{0.1, 0.2}) {
+		t.Errorf("Unexpected series of /items. Got: %v; Expected: [0.1 0.2]", s)
+	}
+	if s := p.TargetDuration["/cart"]; len(s) != 2 || !math.IsNaN(s[0]) || s[1] != 0.5 {
+		t.Errorf("Unexpected series of /cart. Got: %v; Expected: [NaN 0.5]", s)
+	}
+
+	p.Targets = []TargetStat{{URL: "/items"}, {URL: "/cart"}}
+	p.TargetDuration["/admin"] = []float64{0.3}
+	if names := p.targetNames(); !reflect.DeepEqual(names, []string{"/items", "/cart", "/admin"}) {
+		t.Errorf("Unexpected names of targets. Got: %v", names)
+	}
+}
+
+func TestSlowestTarget(t *testing.T) {
+	f := func(targets []TargetStat, expected int) {
+		t.Helper()
+		p := &Page{Targets: targets, MinSamples: 10}
+		if got := p.slowestTarget(); got != expected {
+			t.Errorf("Unexpected slowest target of %+v. Got: %d; Expected: %d", targets, got, expected)
+		}
+	}
+
+	f([]TargetStat{{URL: "/items", Requests: 100, P99: 0.1}, {URL: "/cart", Requests: 100, P99: 0.5}}, 1)
+	// latency of target with insufficient requests isn't compared
+	f([]TargetStat{{URL: "/items", Requests: 100, P99: 0.1}, {URL: "/cart", Requests: 5, P99: 0.5}}, 0)
+	f([]TargetStat{{URL: "/items", Requests: 5, P99: 0.1}}, -1)
+	f(nil, -1)
+}
+
+func TestTargetErrorRate(t *testing.T) {
+	if r := (TargetStat{Requests: 200, Errors: 5}).errorRate(); r != 2.5 {
+		t.Errorf("Unexpected errors rate. Got: %v; Expected: 2.5", r)
+	}
+	if r := (TargetStat{}).errorRate(); r != 0 {
+		t.Errorf("Unexpected errors rate without requests. Got: %v; Expected: 0", r)
+	}
+}
+
+func TestTargetMaxQPS(t *testing.T) {
+	f := func(ts TargetStat, expected string) {
+		t.Helper()
+		if s := ts.formatMaxQPS(); s != expected {
+			t.Errorf("Unexpected max rate of %+v. Got: %q; Expected: %q", ts, s, expected)
+		}
+	}
+
+	f(TargetStat{Rps: 100}, "-")
+	f(TargetStat{Rps: 8, MaxQPS: 10}, "10.00")
+	f(TargetStat{Rps: 10, MaxQPS: 10, Capped: 42}, "10.00 (binding)")
+
+	p := &Page{Targets: []TargetStat{{URL: "/items"}, {URL: "/report", MaxQPS: 10, Capped: 1}}}
+	if !p.hasTargetMaxQPS() {
+		t.Errorf("Rate of /report must be limited")
+	}
+	if s := PrintPage(p); !strings.Contains(s, "10.00 (binding)") {
+		t.Errorf("Report must contain binding max rate of /report")
+	}
+	if p.Targets[1].MaxQPS = 0; p.hasTargetMaxQPS() {
+		t.Errorf("Rates of targets must not be limited")
+	}
+}
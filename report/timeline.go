@@ -0,0 +1,67 @@
+package report
+
+import (
+	"strings"
+)
+
+// sampleTime returns time of sample i in seconds since test start.
+// Samples are evenly spaced by Interval if Timestamps aren't set
+func (p *Page) sampleTime(i int) float64 {
+	if i < len(p.Timestamps) {
+		return p.Timestamps[i]
+	}
+	return float64(i) * p.Interval
+}
+
+// samplePeriod returns duration of period which ends with sample i
+func (p *Page) samplePeriod(i int) float64 {
+	if i == 0 || len(p.Timestamps) == 0 {
+		return p.Interval
+	}
+	return p.sampleTime(i) - p.sampleTime(i-1)
+}
+
+// rates calculates per-second rates of counter between samples
+func (p *Page) rates(sl []uint64) []float64 {
+	if len(p.Timestamps) == 0 {
+		return rate(sl, p.Interval)
+	}
+
+	result := make([]float64, len(sl))
+	for i := 1; i < len(sl); i++ {
+		prev, cur := float64(sl[i-1]), float64(sl[i])
+		// avoid of unnatural gaps when counter metrics flushed
+		if cur < prev {
+			cur = prev
+		}
+		if period := p.samplePeriod(i); period > 0 {
+			result[i] = (cur - prev) / period
+		}
+	}
+	return result
+}
+
+// series formats values of samples for charts.
+// If Timestamps are set, values are formatted as [time,value] pairs,
+// so samples are plotted against real time instead of pointInterval
+func (p *Page) series(values []float64) string {
+	if len(p.Timestamps) == 0 {
+		return float64SliceToString(values)
+	}
+	str := make([]string, len(values))
+	for i, v := range values {
+		str[i] = "[" + float64ToString(p.sampleTime(i)) + "," + float64ToString(v) + "]"
+	}
+	return strings.Join(str, ",")
+}
+
+func (p *Page) uint64Series(values []uint64) string {
+	if len(p.Timestamps) == 0 {
+		return uint64SliceToString(values)
+	}
+	f := make([]float64, len(values))
+	for i, v := range values {
+		f[i] = float64(v)
+	}
+	return p.series(f)
+}
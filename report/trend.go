@@ -0,0 +1,207 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// minTrendSamples is a min number of samples, which is required to fit trend of throughput
+const minTrendSamples = 5
+
+// significantT is a t-statistic of slope, exceeding which growth of metric is statistically significant.
+// It exceeds one-sided critical value for p = 0.01 with 8 and more effective samples
+const significantT = 3
+
+// ErrorRateMetric is a Metric of Drift of errors rate
+const ErrorRateMetric = "errors rate"
+
+// Drift is a linear trend of metric over load phase with constant offered load
+type Drift struct {
+	// Metric is a percentile of latency like "p99" or ErrorRateMetric
+	Metric string `json:"metric"`
+
+	// Start and End are fitted values of metric at the first and the last sample.
+	// Latency is measured in seconds and errors rate in percents
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+
+	// PerHour is a change of fitted value per hour
+	PerHour float64 `json:"per_hour"`
+
+	// T is a t-statistic of slope, which is corrected for autocorrelation of samples
+	T float64 `json:"t"`
+
+	// Significant is true if metric grows with statistical significance
+	Significant bool `json:"significant"`
+}
+
+// Growth returns change of fitted value over the period. Change of errors rate is measured
+// in percentage points, since it usually grows from zero, and change of latency in percents
+// of its value at start. Returns +Inf if latency grows from non-positive value
+func (d Drift) Growth() float64 {
+	if d.Metric == ErrorRateMetric {
+		return d.End - d.Start
+	}
+	if d.Start <= 0 {
+		if d.End > d.Start {
+			return math.Inf(1)
+		}
+		return 0
+	}
+	return (d.End - d.Start) / d.Start * 100
+}
+
+// linearFit is a least squares fit of v = a + b*t
+type linearFit struct {
+	a, b float64
+	// t is a t-statistic of slope b
+	t float64
+}
+
+// fitLine fits linear trend to values vs at times ts. Standard error of slope is corrected for
+// lag-1 autocorrelation of residuals, since neighbouring samples aren't independent.
+// Returns false if there are too few samples or all times are equal
+func fitLine(ts, vs []float64) (linearFit, bool) {
+	if len(ts) < minTrendSamples {
+		return linearFit{}, false
+	}
+	n := float64(len(ts))
+	var sumT, sumV float64
+	for i := range ts {
+		sumT += ts[i]
+		sumV += vs[i]
+	}
+	meanT, meanV := sumT/n, sumV/n
+	var sxx, sxy float64
+	for i := range ts {
+		dt := ts[i] - meanT
+		sxx += dt * dt
+		sxy += dt * (vs[i] - meanV)
+	}
+	if sxx == 0 {
+		return linearFit{}, false
+	}
+	f := linearFit{b: sxy / sxx}
+	f.a = meanV - f.b*meanT
+
+	var sse, lagged, prev float64
+	for i := range ts {
+		e := vs[i] - f.a - f.b*ts[i]
+		sse += e * e
+		if i > 0 {
+			lagged += e * prev
+		}
+		prev = e
+	}
+	if sse == 0 {
+		// values lie exactly on the line
+		if f.b != 0 {
+			f.t = math.Copysign(math.MaxFloat64, f.b)
+		}
+		return f, true
+	}
+	// effective number of independent samples
+	neff := n
+	if r := lagged / sse; r > 0 {
+		neff = n * (1 - r) / (1 + r)
+	}
+	if neff > 2 {
+		f.t = f.b / math.Sqrt(sse/(neff-2)/sxx)
+	}
+	return f, true
+}
+
+// ThroughputTrend fits linear trend to achieved rps of samples since sample from
+// and returns change of fitted rps over this period in percents of fitted rps at its start.
+// Returns false if there are too few samples or fitted rps at start isn't positive
+func (p *Page) ThroughputTrend(from int) (float64, bool) {
+	rps := p.rates(p.RequestSum)
+	// rate of the first sample is measured before counters of the phase start from zero
+	from++
+	if from < 1 || from >= len(rps) {
+		return 0, false
+	}
+	var ts []float64
+	for i := from; i < len(rps); i++ {
+		ts = append(ts, p.sampleTime(i))
+	}
+	f, ok := fitLine(ts, rps[from:])
+	if !ok {
+		return 0, false
+	}
+
+	start, end := f.a+f.b*ts[0], f.a+f.b*ts[len(ts)-1]
+	if start <= 0 {
+		return 0, false
+	}
+	return (end - start) / start * 100, true
+}
+
+// LatencyTrend fits linear trend to quantile q of latency of samples since sample from.
+// Samples without observed latency are skipped. Returns false if there are too few samples
+func (p *Page) LatencyTrend(from int, q float64) (Drift, bool) {
+	var ts, vs []float64
+	values := p.RequestDuration[q]
+	for i := from; i >= 0 && i < len(values); i++ {
+		if math.IsNaN(values[i]) {
+			continue
+		}
+		ts = append(ts, p.sampleTime(i))
+		vs = append(vs, values[i])
+	}
+	return newDrift("p"+strconv.FormatFloat(q*100, 'f', -1, 64), ts, vs)
+}
+
+// ErrorRateTrend fits linear trend to percent of errors between samples since sample from.
+// Samples without requests are skipped. Returns false if there are too few samples
+func (p *Page) ErrorRateTrend(from int) (Drift, bool) {
+	var ts, vs []float64
+	// errors of the first sample are counted before counters of the phase start from zero
+	for i := from + 1; i > 0 && i < len(p.RequestSum) && i < len(p.Errors); i++ {
+		// counters are flushed between phases
+		if p.RequestSum[i] <= p.RequestSum[i-1] || p.Errors[i] < p.Errors[i-1] {
+			continue
+		}
+		requests, errs := p.RequestSum[i]-p.RequestSum[i-1], p.Errors[i]-p.Errors[i-1]
+		ts = append(ts, p.sampleTime(i))
+		vs = append(vs, float64(errs)/float64(requests)*100)
+	}
+	return newDrift(ErrorRateMetric, ts, vs)
+}
+
+func newDrift(metric string, ts, vs []float64) (Drift, bool) {
+	f, ok := fitLine(ts, vs)
+	if !ok {
+		return Drift{}, false
+	}
+	return Drift{
+		Metric:      metric,
+		Start:       f.a + f.b*ts[0],
+		End:         f.a + f.b*ts[len(ts)-1],
+		PerHour:     f.b * 3600,
+		T:           f.t,
+		Significant: f.t > significantT,
+	}, true
+}
+
+// FormatGrowth formats Growth of d with its unit
+func (d Drift) FormatGrowth() string {
+	g := d.Growth()
+	if math.IsInf(g, 1) {
+		return "from zero"
+	}
+	unit := " %"
+	if d.Metric == ErrorRateMetric {
+		unit = " pp"
+	}
+	return fmt.Sprintf("%+.2f%s", g, unit)
+}
+
+// formatDrift formats fitted value v of metric of d
+func (p *Page) formatDrift(d Drift, v float64) string {
+	if d.Metric == ErrorRateMetric {
+		return strconv.FormatFloat(v, 'f', 2, 64) + " %"
+	}
+	return FormatLatency(v, p.latencyUnit())
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"math"
+	"testing"
+)
+
+func TestThroughputTrend(t *testing.T) {
+	f := func(rps []uint64, from int, expected float64, expectedOK bool) {
+		t.Helper()
+		p := &Page{Interval: 1}
+		var sum uint64
+		for _, v := range rps {
+			sum += v
+			p.RequestSum = append(p.RequestSum, sum)
+		}
+		got, ok := p.ThroughputTrend(from)
+		if ok != expectedOK {
+			t.Fatalf("Unexpected ok. Got: %v; Expected: %v", ok, expectedOK)
+		}
+		if math.Abs(got-expected) > 1e-9 {
+			t.Errorf("Unexpected change. Got: %f; Expected: %f", got, expected)
+		}
+	}
+
+	f([]uint64{0, 100, 100, 100, 100, 100, 100}, 0, 0, true)
+	f([]uint64{0, 100, 95, 90, 85, 80, 75}, 0, -25, true)
+	f([]uint64{0, 50, 60, 70, 80, 90, 100}, 0, 100, true)
+	// samples before steady phase are ignored
+	f([]uint64{500, 20, 100, 100, 100, 100, 100, 100}, 1, 0, true)
+	f([]uint64{0, 100, 100, 100}, 0, 0, false)
+}
+
+func TestLatencyTrend(t *testing.T) {
+	f := func(p99 []float64, from int, expectedStart, expectedEnd float64, expectedSignificant bool) {
+		t.Helper()
+		p := &Page{Interval: 60, RequestDuration: map[float64][]float64{0.99: p99}}
+		d, ok := p.LatencyTrend(from, 0.99)
+		if !ok {
+			t.Fatalf("Trend isn't fitted")
+		}
+		if d.Metric != "p99" {
+			t.Errorf("Unexpected metric. Got: %q; Expected: %q", d.Metric, "p99")
+		}
+		if math.Abs(d.Start-expectedStart) > 1e-3 || math.Abs(d.End-expectedEnd) > 1e-3 {
+			t.Errorf("Unexpected fitted values. Got: %f and %f; Expected: %f and %f", d.Start, d.End, expectedStart, expectedEnd)
+		}
+		if d.Significant != expectedSignificant {
+			t.Errorf("Unexpected significance of trend with t %f. Got: %v; Expected: %v", d.T, d.Significant, expectedSignificant)
+		}
+	}
+
+	// steady growth with noise
+	f([]float64{0.100, 0.112, 0.118, 0.133, 0.139, 0.152, 0.158, 0.171, 0.179, 0.190}, 0, 0.1016, 0.1899, true)
+	// noise without trend
+	f([]float64{0.100, 0.120, 0.090, 0.110, 0.100, 0.080, 0.120, 0.100, 0.090, 0.110}, 0, 0.103636, 0.100364, false)
+	// decline isn't significant growth
+	f([]float64{0.200, 0.190, 0.180, 0.170, 0.160, 0.150}, 0, 0.2, 0.15, false)
+	// samples before steady phase and without observations are skipped
+	f([]float64{5, 0.1, math.NaN(), 0.1, 0.1, 0.1, 0.1}, 1, 0.1, 0.1, false)
+}
+
+func TestLatencyTrendAutocorrelation(t *testing.T) {
+	// slowly wandering latency has the same slope as noisy one, but its samples aren't independent
+	wandering := []float64{0.10, 0.11, 0.12, 0.13, 0.12, 0.11, 0.12, 0.13, 0.14, 0.13, 0.12, 0.13, 0.14, 0.15, 0.14}
+	p := &Page{Interval: 60, RequestDuration: map[float64][]float64{0.5: wandering}}
+	d, ok := p.LatencyTrend(0, 0.5)
+	if !ok {
+		t.Fatalf("Trend isn't fitted")
+	}
+	naive, _ := fitLine([]float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}, wandering)
+	if d.T >= naive.t {
+		t.Errorf("t-statistic %f isn't corrected for autocorrelation; uncorrected one is %f", d.T, naive.t)
+	}
+}
+
+func TestErrorRateTrend(t *testing.T) {
+	f := func(requests, errs []uint64, from int, expectedStart, expectedEnd float64, expectedOK bool) {
+		t.Helper()
+		p := &Page{Interval: 1}
+		var sumRequests, sumErrs uint64
+		for i := range requests {
+			sumRequests += requests[i]
+			sumErrs += errs[i]
+			p.RequestSum = append(p.RequestSum, sumRequests)
+			p.Errors = append(p.Errors, sumErrs)
+		}
+		d, ok := p.ErrorRateTrend(from)
+		if ok != expectedOK {
+			t.Fatalf("Unexpected ok. Got: %v; Expected: %v", ok, expectedOK)
+		}
+		if !ok {
+			return
+		}
+		if d.Metric != ErrorRateMetric {
+			t.Errorf("Unexpected metric. Got: %q; Expected: %q", d.Metric, ErrorRateMetric)
+		}
+		if math.Abs(d.Start-expectedStart) > 1e-9 || math.Abs(d.End-expectedEnd) > 1e-9 {
+			t.Errorf("Unexpected fitted values. Got: %f and %f; Expected: %f and %f", d.Start, d.End, expectedStart, expectedEnd)
+		}
+	}
+
+	f([]uint64{0, 100, 100, 100, 100, 100}, []uint64{0, 0, 1, 2, 3, 4}, 0, 0, 4, true)
+	// samples without requests are skipped
+	f([]uint64{0, 100, 0, 100, 100, 100, 100}, []uint64{0, 1, 0, 1, 1, 1, 1}, 0, 1, 1, true)
+	f([]uint64{0, 100, 100, 100}, []uint64{0, 0, 0, 0}, 0, 0, 0, false)
+}
+
+func TestDriftGrowth(t *testing.T) {
+	f := func(start, end, expected float64) {
+		t.Helper()
+		if got := (Drift{Start: start, End: end}).Growth(); got != expected {
+			t.Errorf("Unexpected growth from %f to %f. Got: %f; Expected: %f", start, end, got, expected)
+		}
+	}
+
+	f(0.25, 0.5, 100)
+	f(0.2, 0.1, -50)
+	f(0, 2, math.Inf(1))
+	f(0, 0, 0)
+}
+
+func TestDriftErrorRateGrowth(t *testing.T) {
+	f := func(start, end, expected float64, formatted string) {
+		t.Helper()
+		d := Drift{Metric: ErrorRateMetric, Start: start, End: end}
+		if got := d.Growth(); math.Abs(got-expected) > 1e-9 {
+			t.Errorf("Unexpected growth of errors rate from %f to %f. Got: %f; Expected: %f", start, end, got, expected)
+		}
+		if got := d.FormatGrowth(); got != formatted {
+			t.Errorf("Unexpected formatted growth of errors rate from %f to %f. Got: %q; Expected: %q", start, end, got, formatted)
+		}
+	}
+
+	// errors rate grows in percentage points, so growth from zero is finite
+	f(0, 2, 2, "+2.00 pp")
+	f(-0.1, 0.4, 0.5, "+0.50 pp")
+	f(1.5, 1, -0.5, "-0.50 pp")
+}
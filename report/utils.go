@@ -2,6 +2,7 @@ package report
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"runtime"
@@ -20,11 +21,32 @@ func uint64SliceToString(sl []uint64) string {
 func float64SliceToString(sl []float64) string {
 	str := []string{}
 	for _, v := range sl {
-		str = append(str, strconv.FormatFloat(v, 'f', 8, 64))
+		str = append(str, float64ToString(v))
 	}
 	return strings.Join(str[:], ",")
 }
 
+// float64ToString formats v for charts. NaN is formatted as null
+func float64ToString(v float64) string {
+	if math.IsNaN(v) {
+		return "null"
+	}
+	return strconv.FormatFloat(v, 'f', 8, 64)
+}
+
+// pairsToString joins x and y values into pairs, like [x,y],[x,y]
+func pairsToString(x []uint64, y []float64) string {
+	n := len(x)
+	if len(y) < n {
+		n = len(y)
+	}
+	str := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		str = append(str, fmt.Sprintf("[%d,%s]", x[i], float64ToString(y[i])))
+	}
+	return strings.Join(str, ",")
+}
+
 // rate calculate difference between current and previous value
 func rate(sl []uint64, step float64) []float64 {
 	result := make([]float64, len(sl))
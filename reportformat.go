@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// reportFormats are formats of final report set by -format
+var reportFormats []string
+
+// applyReportFormats validates -format list. Single non-html report
+// is written to file with extension of its format unless -r is set
+func applyReportFormats() {
+	seen := make(map[string]bool)
+	for _, format := range strings.Split(*reportFormat, ",") {
+		format = strings.TrimSpace(format)
+		if _, ok := report.Formats[format]; !ok {
+			usageAndExit(fmt.Sprintf("unsupported -format %q; supported formats are html, json and csv", format))
+		}
+		if seen[format] {
+			usageAndExit(fmt.Sprintf("-format %q is set twice", format))
+		}
+		seen[format] = true
+		reportFormats = append(reportFormats, format)
+	}
+	if *web && !seen["html"] {
+		usageAndExit("-web requires html in -format")
+	}
+	if len(reportFormats) == 1 && !isFlagSet("r") {
+		*fileName = "report" + report.Formats[reportFormats[0]]
+	}
+}
+
+// reportFile returns name of file of report in format. If several formats are set,
+// extension of -r is replaced by extension of format, so every report has its own file
+func reportFile(format string) string {
+	if len(reportFormats) == 1 {
+		return *fileName
+	}
+	return strings.TrimSuffix(*fileName, filepath.Ext(*fileName)) + report.Formats[format]
+}
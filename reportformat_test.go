@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestReportFile(t *testing.T) {
+	defer func(name string) { *fileName = name }(*fileName)
+	defer func() { reportFormats = nil }()
+
+	f := func(name string, formats []string, format, expected string) {
+		t.Helper()
+		*fileName = name
+		reportFormats = formats
+		if got := reportFile(format); got != expected {
+			t.Errorf("Unexpected file of %s report. Got: %q; Expected: %q", format, got, expected)
+		}
+	}
+	f("report.json", []string{"json"}, "json", "report.json")
+	f("out.txt", []string{"csv"}, "csv", "out.txt")
+	f("report.html", []string{"html", "json", "csv"}, "html", "report.html")
+	f("report.html", []string{"html", "json", "csv"}, "csv", "report.csv")
+	f("/tmp/run.1/result", []string{"html", "json"}, "json", "/tmp/run.1/result.json")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// headerList is a list of headers like "Key: Value" set by repeated flag
+type headerList []string
+
+func (l *headerList) String() string {
+	return strings.Join(*l, "; ")
+}
+
+func (l *headerList) Set(s string) error {
+	if !re.MatchString(s) {
+		return fmt.Errorf("header %q must be in format \"Key: Value\"", s)
+	}
+	*l = append(*l, s)
+	return nil
+}
+
+var headerFlags headerList
+
+func init() {
+	flag.Var(&headerFlags, "header", "Add header like \"Key: Value\". Is set multiple times, "+
+		"and headers with the same key are sent all instead of overwriting each other")
+}
+
+// applyBodyFile reads body of request from -body-file
+func applyBodyFile() {
+	if isFlagSet("b") || *curlFlag != "" {
+		usageAndExit("-body-file can't be used with -b or -curl")
+	}
+	b, err := ioutil.ReadFile(*bodyFile)
+	if err != nil {
+		usageAndExit(fmt.Sprintf("cannot read -body-file: %s", err))
+	}
+	*body = string(b)
+}
+
+// methodAllowsBody returns false for methods, requests of which can't contain body
+func methodAllowsBody(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "TRACE":
+		return false
+	default:
+		return true
+	}
+}
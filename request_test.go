@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestHeaderListSet(t *testing.T) {
+	f := func(headers []string, expected string) {
+		t.Helper()
+		var l headerList
+		for _, h := range headers {
+			if err := l.Set(h); err != nil {
+				t.Fatalf("unexpected error for %q: %s", h, err)
+			}
+		}
+		if got := l.String(); got != expected {
+			t.Errorf("Unexpected result for %q. Got: %q; Expected: %q", headers, got, expected)
+		}
+	}
+
+	f([]string{"Content-Type: application/json"}, "Content-Type: application/json")
+	f([]string{"Cookie: a=1", "Cookie: b=2"}, "Cookie: a=1; Cookie: b=2")
+}
+
+func TestHeaderListSetError(t *testing.T) {
+	f := func(h string) {
+		t.Helper()
+		var l headerList
+		if err := l.Set(h); err == nil {
+			t.Errorf("expected error for %q", h)
+		}
+	}
+
+	f("")
+	f("Content-Type")
+}
+
+func TestMethodAllowsBody(t *testing.T) {
+	f := func(method string, expected bool) {
+		t.Helper()
+		if got := methodAllowsBody(method); got != expected {
+			t.Errorf("Unexpected result for %q. Got: %v; Expected: %v", method, got, expected)
+		}
+	}
+
+	f("GET", false)
+	f("head", false)
+	f("TRACE", false)
+	f("POST", true)
+	f("put", true)
+	f("DELETE", true)
+}
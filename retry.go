@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+// retryPolicy is a policy of retries parsed from -retry-policy. Is nil if responses aren't retried
+var retryPolicy fastclient.RetryPolicy
+
+// parseRetryPolicy parses comma-separated rules in format "code:probability[:backoff]"
+// like "503:0.5:100ms,429:1:1s,400:0". Backoff is a base of exponential backoff with jitter
+func parseRetryPolicy(s string) (fastclient.RetryPolicy, error) {
+	policy := make(fastclient.RetryPolicy)
+	for _, rule := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(rule), ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("retry rule %q must be in format \"code:probability[:backoff]\" like \"503:0.5:100ms\"", rule)
+		}
+		code, err := strconv.Atoi(parts[0])
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("cannot parse status code of retry rule %q", rule)
+		}
+		if _, ok := policy[code]; ok {
+			return nil, fmt.Errorf("duplicate retry rule for status code %d", code)
+		}
+		var r fastclient.RetryRule
+		if r.Probability, err = strconv.ParseFloat(parts[1], 64); err != nil || r.Probability < 0 || r.Probability > 1 {
+			return nil, fmt.Errorf("probability of retry rule %q must be in range [0..1]", rule)
+		}
+		if len(parts) == 3 {
+			if r.Backoff, err = time.ParseDuration(parts[2]); err != nil || r.Backoff < 0 {
+				return nil, fmt.Errorf("cannot parse backoff of retry rule %q", rule)
+			}
+		}
+		policy[code] = r
+	}
+	return policy, nil
+}
+
+func applyRetryPolicy() {
+	var err error
+	if retryPolicy, err = parseRetryPolicy(*retryPolicyFlag); err != nil {
+		usageAndExit(err.Error())
+	}
+	if *retryMax < 1 {
+		usageAndExit("-retry-max must be positive")
+	}
+	if *grpcMethod != "" || *burstFlag != "" || *probeFile != "" {
+		usageAndExit("-retry-policy can't be used with -grpc-method, -burst-pattern or -probe")
+	}
+}
+
+// retryAmplification returns number of attempts per request
+func retryAmplification(requests, retries uint64) float64 {
+	if requests == 0 {
+		return 0
+	}
+	return float64(requests+retries) / float64(requests)
+}
+
+// printRetries prints number of retries and amplification of load, which target experiences because of them
+func printRetries() {
+	requests, retries := client.RequestSum(), client.Retries()
+	fmt.Fprintf(out, "Retries: %d; Retry amplification: %.2f attempts per request\n", retries, retryAmplification(requests, retries))
+}
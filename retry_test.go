@@ -0,0 +1,50 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+func TestParseRetryPolicy(t *testing.T) {
+	f := func(s string, expected fastclient.RetryPolicy) {
+		t.Helper()
+		policy, err := parseRetryPolicy(s)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if !reflect.DeepEqual(policy, expected) {
+			t.Errorf("Unexpected policy for %q. Got: %v; Expected: %v", s, policy, expected)
+		}
+	}
+
+	f("503:0.5:100ms", fastclient.RetryPolicy{503: {Probability: 0.5, Backoff: 100 * time.Millisecond}})
+	f("503:1, 429:0.25:1s,400:0", fastclient.RetryPolicy{
+		503: {Probability: 1},
+		429: {Probability: 0.25, Backoff: time.Second},
+		400: {},
+	})
+}
+
+func TestParseRetryPolicyError(t *testing.T) {
+	for _, s := range []string{"", "503", "503:", "abc:1", "99:1", "503:1.5", "503:-1", "503:1:fast", "503:1:-1s", "503:1:1s:2", "503:1,503:0"} {
+		if _, err := parseRetryPolicy(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestRetryAmplification(t *testing.T) {
+	f := func(requests, retries uint64, expected float64) {
+		t.Helper()
+		if got := retryAmplification(requests, retries); got != expected {
+			t.Errorf("Unexpected amplification for %d retries of %d requests. Got: %.2f; Expected: %.2f", retries, requests, got, expected)
+		}
+	}
+
+	f(0, 0, 0)
+	f(100, 0, 1)
+	f(100, 50, 1.5)
+}
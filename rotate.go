@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math/rand"
+	"net/netip"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/valyala/fasthttp"
+)
+
+var (
+	rotateUserAgent = flag.String("rotate-user-agent", "", "Set User-Agent of every request to random one from list: \"builtin\" for built-in list "+
+		"of desktop and mobile browsers, bots and HTTP libraries, or path to file with User-Agent per line")
+	spoofIPFlag = flag.String("spoof-ip", "", "Set X-Forwarded-For and X-Real-IP of every request to random address from CIDR range like \"10.0.0.0/8\", "+
+		"so rate limiters and caches of target see requests of many clients")
+)
+
+// builtinUserAgents are User-Agents of -rotate-user-agent builtin
+var builtinUserAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+	"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+	"Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+	"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+	"Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+	"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+	"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)",
+	"curl/8.7.1",
+	"python-requests/2.31.0",
+	"okhttp/4.12.0",
+	"Go-http-client/1.1",
+}
+
+// headerRotation sets User-Agent and client address headers of every request to random values
+type headerRotation struct {
+	// userAgents is a list of -rotate-user-agent. Is empty if User-Agent isn't rotated
+	userAgents []string
+	// spoofIP is a range of -spoof-ip. Is invalid if addresses aren't spoofed
+	spoofIP netip.Prefix
+}
+
+// rotation is a rotation of headers set by -rotate-user-agent and -spoof-ip. Is nil if headers aren't rotated
+var rotation *headerRotation
+
+func applyRotation() {
+	if *wsFlag {
+		usageAndExit("-rotate-user-agent and -spoof-ip can't be used with -ws, since headers are sent only by handshake")
+	}
+	rotation = &headerRotation{}
+	if *rotateUserAgent != "" {
+		var err error
+		if rotation.userAgents, err = loadUserAgents(*rotateUserAgent); err != nil {
+			usageAndExit(fmt.Sprintf("cannot load -rotate-user-agent: %s", err))
+		}
+	}
+	if *spoofIPFlag != "" {
+		var err error
+		if rotation.spoofIP, err = netip.ParsePrefix(*spoofIPFlag); err != nil {
+			usageAndExit(fmt.Sprintf("cannot parse -spoof-ip: %s", err))
+		}
+	}
+}
+
+// loadUserAgents returns built-in list if s is "builtin", or lines of file s otherwise.
+// Empty lines and lines starting with # are skipped
+func loadUserAgents(s string) ([]string, error) {
+	if s == "builtin" {
+		return builtinUserAgents, nil
+	}
+	f, err := os.Open(s)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var uas []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		uas = append(uas, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(uas) == 0 {
+		return nil, fmt.Errorf("file %q contains no User-Agents", s)
+	}
+	return uas, nil
+}
+
+// randomAddr returns random address of range p
+func randomAddr(p netip.Prefix, rnd *rand.Rand) netip.Addr {
+	b := p.Masked().Addr().AsSlice()
+	for i := range b {
+		// network bits of i-th byte are kept
+		if host := 8 - min(max(p.Bits()-i*8, 0), 8); host > 0 {
+			b[i] |= byte(rnd.Intn(256)) & (1<<host - 1)
+		}
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+// modifier returns fastclient.Client.NewModifier which sets rotated headers after modifier
+// returned by newModifier, if it isn't nil. Every worker has its own sequence of values,
+// which depends on -seed, so tests with the same seed send the same headers
+func (hr *headerRotation) modifier(newModifier func() fastclient.Modifier) func() fastclient.Modifier {
+	var workers int64
+	return func() fastclient.Modifier {
+		rnd := rand.New(rand.NewSource(*seed + atomic.AddInt64(&workers, 1)))
+		var modify fastclient.Modifier
+		if newModifier != nil {
+			modify = newModifier()
+		}
+		var buf []byte
+		return func(req *fasthttp.Request) error {
+			if modify != nil {
+				if err := modify(req); err != nil {
+					return err
+				}
+			}
+			if len(hr.userAgents) > 0 {
+				req.Header.SetUserAgent(hr.userAgents[rnd.Intn(len(hr.userAgents))])
+			}
+			if hr.spoofIP.IsValid() {
+				buf = randomAddr(hr.spoofIP, rnd).AppendTo(buf[:0])
+				req.Header.SetBytesV("X-Forwarded-For", buf)
+				req.Header.SetBytesV("X-Real-IP", buf)
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math/rand"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestLoadUserAgents(t *testing.T) {
+	uas, err := loadUserAgents("builtin")
+	if err != nil || len(uas) == 0 {
+		t.Fatalf("Unexpected built-in User-Agents %q: %v", uas, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "uas.txt")
+	if err := os.WriteFile(path, []byte("# browsers\nMozilla/5.0 (X11)\n\n  curl/8.7.1  \n"), 0644); err != nil {
+		t.Fatalf("Cannot write file: %s", err)
+	}
+	uas, err = loadUserAgents(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if exp := []string{"Mozilla/5.0 (X11)", "curl/8.7.1"}; !reflect.DeepEqual(uas, exp) {
+		t.Errorf("Unexpected User-Agents. Got: %q; Expected: %q", uas, exp)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	os.WriteFile(empty, []byte("# nothing\n"), 0644)
+	for _, s := range []string{empty, filepath.Join(t.TempDir(), "missing.txt")} {
+		if _, err := loadUserAgents(s); err == nil {
+			t.Errorf("Expected error while loading %q", s)
+		}
+	}
+}
+
+func TestRandomAddr(t *testing.T) {
+	f := func(cidr string, expectedDistinct bool) {
+		t.Helper()
+		p := netip.MustParsePrefix(cidr)
+		rnd := rand.New(rand.NewSource(1))
+		seen := make(map[netip.Addr]bool)
+		for i := 0; i < 100; i++ {
+			addr := randomAddr(p, rnd)
+			if !p.Contains(addr) {
+				t.Fatalf("Address %s is out of range %s", addr, cidr)
+			}
+			seen[addr] = true
+		}
+		if distinct := len(seen) > 1; distinct != expectedDistinct {
+			t.Errorf("Unexpected number of distinct addresses of %s: %d", cidr, len(seen))
+		}
+	}
+
+	f("10.0.0.0/8", true)
+	f("192.168.1.128/25", true)
+	f("172.16.5.4/32", false)
+	// host bits of range are ignored
+	f("10.1.2.3/30", true)
+	f("2001:db8::/32", true)
+}
+
+func TestHeaderRotationModifier(t *testing.T) {
+	hr := &headerRotation{userAgents: []string{"a", "b", "c"}, spoofIP: netip.MustParsePrefix("10.0.0.0/16")}
+	headers := func() []string {
+		modify := hr.modifier(nil)()
+		var req fasthttp.Request
+		var sent []string
+		for i := 0; i < 10; i++ {
+			if err := modify(&req); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			xff, realIP := string(req.Header.Peek("X-Forwarded-For")), string(req.Header.Peek("X-Real-IP"))
+			if xff != realIP || !netip.MustParsePrefix("10.0.0.0/16").Contains(netip.MustParseAddr(xff)) {
+				t.Fatalf("Unexpected spoofed addresses %q and %q", xff, realIP)
+			}
+			sent = append(sent, string(req.Header.UserAgent())+" "+xff)
+		}
+		return sent
+	}
+
+	// the first worker of every test with the same seed sends the same headers
+	first := headers()
+	if again := headers(); !reflect.DeepEqual(first, again) {
+		t.Errorf("Headers of workers with the same seed differ: %q and %q", first, again)
+	}
+}
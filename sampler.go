@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Bounds of sample period in adaptive mode as parts of -samplePeriod
+	minSamplePeriodDivisor    = 5
+	maxSamplePeriodMultiplier = 4
+
+	// maxSampleCredit is a max number of samples, which could be taken
+	// in adaptive mode above number of samples taken with fixed -samplePeriod
+	maxSampleCredit = 50
+
+	// Changes of metrics between samples, exceeding which are considered as instability
+	unstableErrorRate = 1   // percents
+	unstableLatency   = 0.1 // part of previous p99
+)
+
+// sampler schedules taking of samples. Period is fixed and equals to -samplePeriod,
+// unless adaptive sampling is enabled
+type sampler struct {
+	adaptive bool
+	ticker   <-chan time.Time
+
+	period time.Duration
+	last   time.Time
+
+	// credit is a number of samples, which could be taken above number of samples
+	// with fixed period. Is accumulated while sampling is slower than -samplePeriod,
+	// so total number of samples is bounded
+	credit float64
+
+	requests, errors uint64
+	errorRate, p99   float64
+}
+
+func newSampler() *sampler {
+	s := &sampler{
+		adaptive: *adaptiveSampling,
+		period:   *samplePeriod,
+		last:     time.Now(),
+	}
+	if !s.adaptive {
+		s.ticker = time.Tick(*samplePeriod)
+	}
+	return s
+}
+
+// next returns channel which fires when next sample must be taken
+func (s *sampler) next() <-chan time.Time {
+	if !s.adaptive {
+		return s.ticker
+	}
+	return time.After(s.period)
+}
+
+// sample takes sample of metrics and adjusts period of the next one.
+// Period is shortened while errors rate or latency are changing
+// and is extended while they are stable
+func (s *sampler) sample() {
+	printState()
+	if !s.adaptive {
+		return
+	}
+
+	now := time.Now()
+	s.credit = math.Min(s.credit+float64(now.Sub(s.last))/float64(*samplePeriod)-1, maxSampleCredit)
+	s.last = now
+
+	requests, errs := client.RequestSum(), client.Errors()
+	p99 := client.RequestDuration()[0.99]
+	var errorRate float64
+	if requests > s.requests && errs >= s.errors {
+		errorRate = float64(errs-s.errors) / float64(requests-s.requests) * 100
+	}
+	unstable := math.Abs(errorRate-s.errorRate) > unstableErrorRate ||
+		(s.p99 > 0 && math.Abs(p99-s.p99) > s.p99*unstableLatency)
+	s.requests, s.errors, s.errorRate, s.p99 = requests, errs, errorRate, p99
+
+	switch {
+	case unstable && s.credit > 0:
+		s.period = *samplePeriod / minSamplePeriodDivisor
+	case unstable:
+		// credit is spent, so samples can't be taken more often than with fixed period
+		s.period = *samplePeriod
+	default:
+		s.period *= 2
+		if max := *samplePeriod * maxSamplePeriodMultiplier; s.period > max {
+			s.period = max
+		}
+	}
+}
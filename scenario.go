@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/valyala/fasthttp"
+	"gopkg.in/yaml.v3"
+)
+
+// scenarioEntry is a weighted request of -scenario file
+type scenarioEntry struct {
+	// Name is a name, by which requests and errors of entry are reported. Is "METHOD url" if empty
+	Name string `json:"name" yaml:"name"`
+	// Method is -m if empty
+	Method string `json:"method" yaml:"method"`
+	// Path is relative to url argument unless it is absolute url
+	Path    string            `json:"path" yaml:"path"`
+	Headers map[string]string `json:"headers" yaml:"headers"`
+	Body    string            `json:"body" yaml:"body"`
+	// Form contains fields of multipart body like "name=value" or "file=@upload.bin", which replaces Body
+	Form   []string `json:"form" yaml:"form"`
+	Weight float64  `json:"weight" yaml:"weight"`
+	// MaxQPS is a max rate of requests of entry within rate of throttle. Is unlimited if zero
+	MaxQPS float64 `json:"maxQPS" yaml:"maxQPS"`
+	// Extract maps name of variable to its extraction from response like "json:$.token".
+	// Is used only with -scenario-steps
+	Extract map[string]string `json:"extract" yaml:"extract"`
+}
+
+var (
+	// targetNames are names of targets created from -scenario. Is empty unless -scenario is set
+	targetNames []string
+
+	// targetWeights are weights of targets created from -scenario or weighted -urls.
+	// Is empty unless one of them is set
+	targetWeights []float64
+
+	// targetForms are multipart bodies of targets created from -scenario.
+	// Is empty unless any entry of scenario has form
+	targetForms []*fastclient.Form
+
+	// targetMaxQPS are max rates of targets created from -scenario.
+	// Is empty unless any entry of scenario has maxQPS
+	targetMaxQPS []float64
+)
+
+// readScenario reads list of weighted requests from JSON file like
+// [{"method": "GET", "path": "/items", "weight": 80}, {"method": "POST", "path": "/cart", "body": "{}", "weight": 20}].
+// Files with .yaml or .yml extension are parsed as YAML list with the same fields.
+// Inline scenario of -config file is read instead of file if path is -config.
+// If steps is true, requests are steps of session, so they have no weights
+func readScenario(path string, steps bool) ([]scenarioEntry, error) {
+	var b []byte
+	var err error
+	ext := strings.ToLower(filepath.Ext(path))
+	if configScenario != nil && path == *configFile {
+		b, ext = configScenario, ".yaml"
+	} else if b, err = ioutil.ReadFile(path); err != nil {
+		return nil, fmt.Errorf("cannot read scenario file: %s", err)
+	}
+	var entries []scenarioEntry
+	switch ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &entries)
+	default:
+		err = json.Unmarshal(b, &entries)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse scenario file %q: %s", path, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("scenario file %q contains no requests", path)
+	}
+	for i, e := range entries {
+		if e.Path == "" {
+			return nil, fmt.Errorf("request %d of scenario file %q has no path", i+1, path)
+		}
+		if steps {
+			if e.Weight != 0 || e.MaxQPS != 0 {
+				return nil, fmt.Errorf("request %d of scenario file %q can't have weight or maxQPS with -scenario-steps", i+1, path)
+			}
+			continue
+		}
+		if e.MaxQPS < 0 {
+			return nil, fmt.Errorf("request %d of scenario file %q can't have negative maxQPS; got %v", i+1, path, e.MaxQPS)
+		}
+		if !(e.Weight > 0) {
+			return nil, fmt.Errorf("request %d of scenario file %q must have positive weight; got %v", i+1, path, e.Weight)
+		}
+		if len(e.Extract) > 0 {
+			return nil, fmt.Errorf("request %d of scenario file %q can't extract variables without -scenario-steps", i+1, path)
+		}
+	}
+	if steps {
+		if err := checkStepVars(entries); err != nil {
+			return nil, fmt.Errorf("invalid steps of scenario file %q: %s", path, err)
+		}
+	}
+	return entries, nil
+}
+
+// applyScenario creates target for every request of -scenario file.
+// Requests inherit headers of req, while method, headers and body of entry replace them.
+// With -scenario-steps requests are sent in order as steps of session of every client
+func applyScenario() {
+	if len(urls) > 0 || *curlFlag != "" || *grpcMethod != "" || *probeFile != "" || *dataFile != "" {
+		usageAndExit("-scenario can't be used with -url, -curl, -grpc-method, -probe or -data")
+	}
+	if isTemplate(target) || isTemplate(*body) || isTemplate(*headers) {
+		usageAndExit("-scenario can't be used with request templates")
+	}
+	if *verifyDNS || *dnsRoundRobin {
+		usageAndExit("-scenario can't be used with -verify-dns-distribution or -dns-round-robin")
+	}
+	entries, err := readScenario(*scenarioFile, *scenarioSteps)
+	if err != nil {
+		usageAndExit(err.Error())
+	}
+	scheme := string(req.URI().Scheme())
+	names := make(map[string]bool)
+	var forms []*fastclient.Form
+	var hasForms bool
+	var maxQPS []float64
+	var hasMaxQPS bool
+	for i, e := range entries {
+		r := new(fasthttp.Request)
+		req.CopyTo(r)
+		u := probeURL(target, e.Path)
+		r.SetRequestURI(encodeURL(u))
+		if s := string(r.URI().Scheme()); s != scheme {
+			usageAndExit(fmt.Sprintf("request %d of scenario must have the same scheme as url; got %s and %s", i+1, scheme, s))
+		}
+		m := strings.ToUpper(*method)
+		if e.Method != "" {
+			m = strings.ToUpper(e.Method)
+		}
+		r.Header.SetMethod(m)
+		for k, v := range e.Headers {
+			r.Header.Set(k, v)
+		}
+		r.SetBodyString(e.Body)
+		if (e.Body != "" || len(e.Form) > 0) && !methodAllowsBody(m) {
+			usageAndExit(fmt.Sprintf("body of request %d of scenario can't be sent with %s method", i+1, m))
+		}
+		var f *fastclient.Form
+		if len(e.Form) > 0 {
+			if e.Body != "" {
+				usageAndExit(fmt.Sprintf("request %d of scenario can't have both body and form", i+1))
+			}
+			if f, err = parseFormFields(e.Form); err != nil {
+				usageAndExit(fmt.Sprintf("invalid form of request %d of scenario: %s", i+1, err))
+			}
+			hasForms = true
+		}
+		forms = append(forms, f)
+		name := e.Name
+		if name == "" {
+			name = m + " " + u
+		}
+		if names[name] {
+			usageAndExit(fmt.Sprintf("requests of scenario must have unique names; %q is repeated", name))
+		}
+		names[name] = true
+		targets = append(targets, r)
+		targetNames = append(targetNames, name)
+		if !*scenarioSteps {
+			targetWeights = append(targetWeights, e.Weight)
+			maxQPS = append(maxQPS, e.MaxQPS)
+			hasMaxQPS = hasMaxQPS || e.MaxQPS > 0
+			continue
+		}
+		extractions, err := stepExtractionsOf(e)
+		if err != nil {
+			usageAndExit(fmt.Sprintf("invalid extraction of request %d of scenario: %s", i+1, err))
+		}
+		stepExtractions = append(stepExtractions, extractions)
+	}
+	if hasForms {
+		checkStreamedForm("form of scenario")
+		targetForms = forms
+	}
+	if hasMaxQPS {
+		targetMaxQPS = maxQPS
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func writeScenario(t *testing.T, content string) string {
+	t.Helper()
+	return writeScenarioFile(t, "scenario.json", content)
+}
+
+func writeScenarioFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("cannot write scenario file: %s", err)
+	}
+	return path
+}
+
+func TestReadScenario(t *testing.T) {
+	path := writeScenario(t, `[{"method": "GET", "path": "/items", "weight": 80},
+		{"name": "cart", "method": "POST", "path": "/cart", "headers": {"Content-Type": "application/json"}, "body": "{}", "weight": 15},
+		{"path": "/search?q=1", "weight": 5, "maxQPS": 10}]`)
+	entries, err := readScenario(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(entries) != 3 || entries[0].Weight != 80 || entries[1].Name != "cart" ||
+		entries[1].Headers["Content-Type"] != "application/json" || entries[2].Method != "" || entries[0].MaxQPS != 0 || entries[2].MaxQPS != 10 {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
+
+func TestReadScenarioYAML(t *testing.T) {
+	path := writeScenarioFile(t, "scenario.yaml", `
+- method: GET
+  path: /items
+  weight: 80
+- name: cart
+  method: POST
+  path: /cart
+  headers:
+    Content-Type: application/json
+  body: '{"id": 1}'
+  weight: 15
+- path: /search?q=1
+  weight: 5
+  maxQPS: 2.5
+`)
+	entries, err := readScenario(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(entries) != 3 || entries[0].Weight != 80 || entries[1].Name != "cart" || entries[1].Body != `{"id": 1}` ||
+		entries[1].Headers["Content-Type"] != "application/json" || entries[2].Method != "" || entries[2].MaxQPS != 2.5 {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	for _, content := range []string{"[]", "- path: /items\n", "- path: /items\n  weight: high\n", "path: /items\n"} {
+		if _, err := readScenario(writeScenarioFile(t, "scenario.yml", content), false); err == nil {
+			t.Errorf("Expected error for %q", content)
+		}
+	}
+}
+
+func TestReadScenarioError(t *testing.T) {
+	f := func(content string) {
+		t.Helper()
+		if _, err := readScenario(writeScenario(t, content), false); err == nil {
+			t.Errorf("Expected error for %s", content)
+		}
+	}
+
+	f(`[]`)
+	f(`{"path": "/items", "weight": 1}`)
+	f(`[{"path": "/items"}]`)
+	f(`[{"path": "/items", "weight": 0}]`)
+	f(`[{"path": "/items", "weight": -1}]`)
+	f(`[{"weight": 1}]`)
+	f(`[{"path": "/items", "weight": "high"}]`)
+	f(`[{"path": "/items", "weight": 1, "maxQPS": -1}]`)
+}
+
+func TestReadScenarioSteps(t *testing.T) {
+	path := writeScenario(t, `[{"name": "login", "method": "POST", "path": "/login", "extract": {"token": "json:$.token"}},
+		{"path": "/items", "headers": {"Authorization": "Bearer ${token}"}}]`)
+	entries, err := readScenario(path, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(entries) != 2 || entries[0].Extract["token"] != "json:$.token" || entries[1].Weight != 0 {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	f := func(content string, steps bool) {
+		t.Helper()
+		if _, err := readScenario(writeScenario(t, content), steps); err == nil {
+			t.Errorf("Expected error for %s", content)
+		}
+	}
+	f(`[{"path": "/items", "weight": 1}]`, true)
+	f(`[{"path": "/items", "maxQPS": 10}]`, true)
+	f(`[{"path": "/items?t=${token}"}]`, true)
+	f(`[{"path": "/login", "extract": {"token": "xpath://token"}}]`, true)
+	f(`[{"path": "/login", "weight": 1, "extract": {"token": "json:$.token"}}]`, false)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"hash/fnv"
+	"math/rand"
+)
+
+// derivedSeed returns seed of n-th source of random values of purpose like "faker".
+// Sources of different purposes don't repeat each other and depend only on -seed,
+// so tests with the same seed take the same values
+func derivedSeed(purpose string, n int64) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(purpose))
+	// splitmix64 spreads adjacent seeds and n over all bits
+	z := (uint64(*seed) ^ h.Sum64()) + uint64(n+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// seededRand returns n-th source of random values of purpose, see derivedSeed
+func seededRand(purpose string, n int64) *rand.Rand {
+	return rand.New(rand.NewSource(derivedSeed(purpose, n)))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestDerivedSeed(t *testing.T) {
+	defer func(s int64) { *seed = s }(*seed)
+	*seed = 42
+	seen := make(map[int64]string)
+	for _, purpose := range []string{"faker", "data-shuffle", "client"} {
+		for n := int64(0); n < 3; n++ {
+			s := derivedSeed(purpose, n)
+			if s != derivedSeed(purpose, n) {
+				t.Fatalf("Seed %d of %q must not change", n, purpose)
+			}
+			if p, ok := seen[s]; ok {
+				t.Fatalf("Seed %d of %q repeats seed of %q", n, purpose, p)
+			}
+			seen[s] = purpose
+		}
+	}
+	*seed = 43
+	if _, ok := seen[derivedSeed("faker", 0)]; ok {
+		t.Fatalf("Seed of another -seed must differ")
+	}
+}
+
+func TestTemplateSeed(t *testing.T) {
+	defer func(s int64) { *seed = s }(*seed)
+	render := func(s int64) []string {
+		t.Helper()
+		*seed = s
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+		req.SetBodyString(`{{name}} {{email}}`)
+		rt, err := newRequestTemplate(req, "http://localhost/", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		var bodies []string
+		for i := 0; i < 3; i++ {
+			modify := rt.Modifier()
+			r := new(fasthttp.Request)
+			req.CopyTo(r)
+			if err := modify(r); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			bodies = append(bodies, string(r.Body()))
+		}
+		return bodies
+	}
+
+	a, b := render(42), render(42)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("Unexpected body of worker %d with the same seed. Got: %q; Expected: %q", i, b[i], a[i])
+		}
+	}
+	if a[0] == a[1] {
+		t.Errorf("Workers must get different data, got %q", a[0])
+	}
+	if c := render(43); c[0] == a[0] {
+		t.Errorf("Tests with different seeds must get different data, got %q", c[0])
+	}
+}
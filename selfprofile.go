@@ -0,0 +1,188 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	httppprof "net/http/pprof"
+	"runtime"
+	runtimedebug "runtime/debug"
+	"strings"
+	"time"
+)
+
+var pprofListen = flag.String("pprof", "", "Set address like \":6060\" to serve net/http/pprof of loader at /debug/pprof/, "+
+	"so CPU, heap and goroutines of fasthttploader itself could be profiled while test is running")
+
+const (
+	// saturatedCPU is a share of CPU time available to GOMAXPROCS threads,
+	// using which loader is considered saturated
+	saturatedCPU = 0.9
+	// saturatedGCPause is a share of time of load phase spent in GC pauses,
+	// exceeding which loader is considered saturated
+	saturatedGCPause = 0.05
+	// starvedRate is a share of rate limit, below which jobs are considered starved,
+	// if clients were free to take them
+	starvedRate = 0.9
+	// saturatedSamples is a share of samples of load phase, which must be saturated or starved to warn about loader
+	saturatedSamples = 0.1
+)
+
+// startPprofListener serves net/http/pprof at addr like ":6060" until fasthttploader exits
+func startPprofListener(addr string) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Cannot listen -pprof %s: %s", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", httppprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", httppprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", httppprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", httppprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", httppprof.Trace)
+	go func() {
+		if err := http.Serve(ln, mux); err != nil {
+			slog.Error("Error while serving pprof", "addr", addr, "error", err)
+		}
+	}()
+	fmt.Fprintf(out, "Serving pprof at http://%s/debug/pprof/\n", ln.Addr())
+}
+
+// selfMonitor tracks CPU usage and GC pauses of loader and starvation of job queue over samples of load phase,
+// so results limited by load generator aren't mistaken for capacity of target
+type selfMonitor struct {
+	// limited is true if jobs are generated by rate limiter, so they could be starved
+	limited bool
+	procs   int
+
+	last               time.Time
+	lastCPU, lastPause time.Duration
+	lastJobs, lastFull uint64
+	lastLimit          float64
+
+	samples int
+	// cpuSaturated and starved are numbers of samples, at which CPU was saturated and jobs were starved
+	cpuSaturated, starved int
+	// cpuMeasured is false if CPU time of process can't be measured on this platform
+	cpuMeasured         bool
+	maxCPU              float64
+	elapsed, cpu, pause time.Duration
+}
+
+func newSelfMonitor() *selfMonitor {
+	m := &selfMonitor{
+		limited: pattern == nil && *loadModel != loadModelConcurrency && replayOffsets == nil,
+		procs:   runtime.GOMAXPROCS(0),
+	}
+	m.last = time.Now()
+	m.lastCPU, m.cpuMeasured = processCPUTime()
+	m.lastPause = gcPauseTotal()
+	m.lastJobs, m.lastFull = client.JobsQueued(), client.QueueFull()
+	m.lastLimit = qpsLimit()
+	return m
+}
+
+// gcPauseTotal returns total time of GC pauses since start of process
+func gcPauseTotal() time.Duration {
+	var stats runtimedebug.GCStats
+	runtimedebug.ReadGCStats(&stats)
+	return stats.PauseTotal
+}
+
+// sample accounts usage of resources and rate of jobs since previous sample
+func (m *selfMonitor) sample() {
+	now := time.Now()
+	elapsed := now.Sub(m.last)
+	if elapsed <= 0 {
+		return
+	}
+	cpu, _ := processCPUTime()
+	pause := gcPauseTotal()
+	jobs, full, limit := client.JobsQueued(), client.QueueFull(), qpsLimit()
+
+	m.samples++
+	m.elapsed += elapsed
+	m.pause += pause - m.lastPause
+	if m.cpuMeasured {
+		m.cpu += cpu - m.lastCPU
+		usage := float64(cpu-m.lastCPU) / float64(elapsed) / float64(m.procs)
+		m.maxCPU = math.Max(m.maxCPU, usage)
+		if usage >= saturatedCPU {
+			m.cpuSaturated++
+		}
+	}
+	// -n requests may be queued before the end of load phase
+	done := *totalRequests > 0 && jobs >= *totalRequests
+	// limit could change during sample, so the lower one is expected
+	if m.limited && !done && isStarved(jobs-m.lastJobs, math.Min(limit, m.lastLimit), elapsed, client.Overflow(), full-m.lastFull) {
+		m.starved++
+	}
+	m.last, m.lastCPU, m.lastPause = now, cpu, pause
+	m.lastJobs, m.lastFull, m.lastLimit = jobs, full, limit
+}
+
+// isStarved returns true if number of jobs queued during elapsed is below rate limit,
+// while jobs didn't wait for free clients
+func isStarved(jobs uint64, limit float64, elapsed time.Duration, queued int, blocked uint64) bool {
+	if queued > 0 || blocked > 0 || !(limit > 0) || math.IsInf(limit, 1) {
+		return false
+	}
+	return float64(jobs) < limit*elapsed.Seconds()*starvedRate
+}
+
+// cpuUsage returns average share of CPU time available to GOMAXPROCS threads, which was used by loader
+func (m *selfMonitor) cpuUsage() float64 {
+	if m.elapsed <= 0 {
+		return 0
+	}
+	return float64(m.cpu) / float64(m.elapsed) / float64(m.procs)
+}
+
+// bottlenecks returns reasons to consider load generator as bottleneck of test. Is empty if loader kept up
+func (m *selfMonitor) bottlenecks() []string {
+	if m.samples == 0 {
+		return nil
+	}
+	var reasons []string
+	if float64(m.cpuSaturated) >= float64(m.samples)*saturatedSamples {
+		reasons = append(reasons, fmt.Sprintf("loader CPU usage exceeded %.0f %% in %d of %d samples", saturatedCPU*100, m.cpuSaturated, m.samples))
+	}
+	if share := float64(m.pause) / float64(m.elapsed); share > saturatedGCPause {
+		reasons = append(reasons, fmt.Sprintf("GC pauses of loader took %.2f %% of load phase", share*100))
+	}
+	if float64(m.starved) >= float64(m.samples)*saturatedSamples {
+		reasons = append(reasons, fmt.Sprintf("job queue was starved below %.0f %% of qps limit while clients were free in %d of %d samples",
+			starvedRate*100, m.starved, m.samples))
+	}
+	return reasons
+}
+
+// printSelfMonitor prints usage of resources by loader during load phase and warns
+// if load generator rather than target was the bottleneck. Warning is marked in report
+func printSelfMonitor(m *selfMonitor) {
+	if m.samples == 0 {
+		return
+	}
+	fmt.Fprintf(out, "Loader: ")
+	if m.cpuMeasured {
+		fmt.Fprintf(out, "CPU usage: %.2f %% (max %.2f %%) of %d CPUs; ", m.cpuUsage()*100, m.maxCPU*100, m.procs)
+	}
+	fmt.Fprintf(out, "GC pauses: %s (%.2f %%)", m.pause.Round(time.Microsecond), float64(m.pause)/float64(m.elapsed)*100)
+	if m.limited {
+		fmt.Fprintf(out, "; Starved samples: %d of %d", m.starved, m.samples)
+	}
+	fmt.Fprintln(out)
+	reasons := m.bottlenecks()
+	if len(reasons) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "Warning: load generator rather than target is the bottleneck: %s; "+
+		"results don't reflect capacity of target, so give loader more CPUs or distribute load by -mode coordinator\n", strings.Join(reasons, "; "))
+	r.Lock()
+	r.GeneratorBottleneck = strings.Join(reasons, "; ")
+	r.Unlock()
+}
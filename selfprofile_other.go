@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "time"
+
+// processCPUTime returns false, since CPU time of process isn't measured on this platform
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
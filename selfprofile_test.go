@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+func TestIsStarved(t *testing.T) {
+	f := func(jobs uint64, limit float64, queued int, blocked uint64, expected bool) {
+		t.Helper()
+		if got := isStarved(jobs, limit, time.Second, queued, blocked); got != expected {
+			t.Errorf("Unexpected starvation of %d jobs at limit %v with %d queued and %d blocked. Got: %v; Expected: %v",
+				jobs, limit, queued, blocked, got, expected)
+		}
+	}
+
+	f(1000, 1000, 0, 0, false)
+	f(950, 1000, 0, 0, false)
+	f(500, 1000, 0, 0, true)
+	// clients didn't keep up with jobs
+	f(500, 1000, 10, 0, false)
+	f(500, 1000, 0, 3, false)
+	// rate isn't limited
+	f(0, 0, 0, 0, false)
+}
+
+func TestSelfMonitorBottlenecks(t *testing.T) {
+	f := func(m *selfMonitor, expected string) {
+		t.Helper()
+		m.procs, m.elapsed = 4, 10*time.Second
+		if got := strings.Join(m.bottlenecks(), "; "); got != expected {
+			t.Errorf("Unexpected bottlenecks of %+v.\nGot: %q\nExpected: %q", m, got, expected)
+		}
+	}
+
+	f(&selfMonitor{samples: 20, cpuSaturated: 1, starved: 1, pause: 10 * time.Millisecond}, "")
+	f(&selfMonitor{samples: 20, cpuSaturated: 5}, "loader CPU usage exceeded 90 % in 5 of 20 samples")
+	f(&selfMonitor{samples: 20, pause: time.Second}, "GC pauses of loader took 10.00 % of load phase")
+	f(&selfMonitor{samples: 20, cpuSaturated: 20, starved: 10},
+		"loader CPU usage exceeded 90 % in 20 of 20 samples; job queue was starved below 90 % of qps limit while clients were free in 10 of 20 samples")
+	f(&selfMonitor{}, "")
+}
+
+func TestPrintSelfMonitor(t *testing.T) {
+	defer func(page *report.Page, w io.Writer) {
+		r, out = page, w
+	}(r, out)
+	var buf bytes.Buffer
+	out = &buf
+	r = &report.Page{}
+
+	m := &selfMonitor{limited: true, procs: 2, cpuMeasured: true, samples: 10, starved: 5,
+		elapsed: 10 * time.Second, cpu: 5 * time.Second, maxCPU: 0.6, pause: 10 * time.Millisecond}
+	printSelfMonitor(m)
+	expected := "Loader: CPU usage: 25.00 % (max 60.00 %) of 2 CPUs; GC pauses: 10ms (0.10 %); Starved samples: 5 of 10\n" +
+		"Warning: load generator rather than target is the bottleneck: job queue was starved below 90 % of qps limit while clients were free in 5 of 10 samples; " +
+		"results don't reflect capacity of target, so give loader more CPUs or distribute load by -mode coordinator\n"
+	if got := buf.String(); got != expected {
+		t.Fatalf("Unexpected output.\nGot:\n%s\nExpected:\n%s", got, expected)
+	}
+	if r.GeneratorBottleneck == "" {
+		t.Fatalf("Bottleneck isn't marked in report")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// latencyBySize returns latency of responses by range of body size
+func latencyBySize() []report.SizeLatency {
+	var result []report.SizeLatency
+	for _, sl := range client.LatencyBySize() {
+		result = append(result, report.SizeLatency{
+			Size:     sl.Size,
+			Requests: sl.Requests,
+			P50:      sl.Quantiles[0.5],
+			P90:      sl.Quantiles[0.9],
+			P99:      sl.Quantiles[0.99],
+		})
+	}
+	return result
+}
+
+// slowSmallResponses returns ranges of body size, p99 latency of which exceeds
+// p99 latency of some range of bigger responses. Such latency isn't explained
+// by size of responses. Ranges with less than minSamples requests are ignored
+func slowSmallResponses(sizes []report.SizeLatency, minSamples uint64) []string {
+	var result []string
+	for i, small := range sizes {
+		if small.Requests < minSamples {
+			continue
+		}
+		for _, big := range sizes[i+1:] {
+			if big.Requests >= minSamples && small.P99 > big.P99 {
+				result = append(result, small.Size)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// printLatencyBySize prints latency of responses by range of body size
+// if responses of different sizes were received
+func printLatencyBySize() {
+	if len(r.LatencyBySize) < 2 {
+		return
+	}
+
+	fmt.Fprintln(out, "------ Latency by response size ------")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Body size\tRequests\tp50\tp90\tp99")
+	for _, sl := range r.LatencyBySize {
+		p50, p90, p99 := "insufficient data", "", ""
+		if sl.Requests >= *minSamples {
+			p50, p90, p99 = formatLatency(sl.P50), formatLatency(sl.P90), formatLatency(sl.P99)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", sl.Size, sl.Requests, p50, p90, p99)
+	}
+	w.Flush()
+	for _, size := range slowSmallResponses(r.LatencyBySize, *minSamples) {
+		fmt.Fprintf(out, "Warning: p99 latency of %s responses exceeds one of bigger responses; it isn't explained by response size\n", size)
+	}
+	fmt.Fprintln(out)
+}
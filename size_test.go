@@ -0,0 +1,29 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+func TestSlowSmallResponses(t *testing.T) {
+	f := func(sizes []report.SizeLatency, expected []string) {
+		t.Helper()
+		got := slowSmallResponses(sizes, 10)
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Unexpected result. Got: %v; Expected: %v", got, expected)
+		}
+	}
+
+	small := report.SizeLatency{Size: "<1KB", Requests: 100, P99: 0.1}
+	medium := report.SizeLatency{Size: "1KB-10KB", Requests: 100, P99: 0.05}
+	big := report.SizeLatency{Size: ">=1MB", Requests: 100, P99: 0.5}
+	rare := report.SizeLatency{Size: ">=1MB", Requests: 5, P99: 0.01}
+
+	f(nil, nil)
+	f([]report.SizeLatency{small, big}, nil)
+	f([]report.SizeLatency{small, medium, big}, []string{"<1KB"})
+	// ranges with insufficient data aren't compared
+	f([]report.SizeLatency{medium, rare}, nil)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slaQPSTolerance is a part of target qps which must be reached
+// to consider throughput as sustained, since rate limiting isn't exact
+const slaQPSTolerance = 0.95
+
+// sla represents a contract like "sustain 5000 qps for 10 minutes with 99.9% of success requests"
+type sla struct {
+	qps float64
+
+	// duration is zero if duration wasn't set in contract
+	duration time.Duration
+
+	// availability is a percent of requests which must be successful
+	availability float64
+}
+
+// parseSLA parses contract in format "5000qps,10m,99.9%".
+// Parts order doesn't matter, duration part is optional
+func parseSLA(s string) (*sla, error) {
+	res := &sla{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		var err error
+		switch {
+		case strings.HasSuffix(part, "qps"):
+			res.qps, err = strconv.ParseFloat(strings.TrimSuffix(part, "qps"), 64)
+		case strings.HasSuffix(part, "%"):
+			res.availability, err = strconv.ParseFloat(strings.TrimSuffix(part, "%"), 64)
+			if err == nil && (res.availability <= 0 || res.availability > 100) {
+				err = fmt.Errorf("availability must be in range (0, 100]")
+			}
+		default:
+			res.duration, err = time.ParseDuration(part)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q of SLA %q: %s", part, s, err)
+		}
+	}
+	if res.qps <= 0 {
+		return nil, fmt.Errorf("SLA %q must contain qps like \"5000qps\"", s)
+	}
+	if res.availability == 0 {
+		return nil, fmt.Errorf("SLA %q must contain availability like \"99.9%%\"", s)
+	}
+
+	return res, nil
+}
+
+func (s *sla) String() string {
+	str := fmt.Sprintf("%.0f qps with %.3f%% availability", s.qps, s.availability)
+	if s.duration > 0 {
+		str = fmt.Sprintf("%.0f qps for %s with %.3f%% availability", s.qps, s.duration, s.availability)
+	}
+	return str
+}
+
+// slaResult contains results of checking test against SLA
+type slaResult struct {
+	qps          float64
+	availability float64
+
+	// budgetConsumed is a percent of allowed failed requests which were spent
+	budgetConsumed float64
+}
+
+func (s *sla) check(requests, success uint64, elapsed time.Duration) slaResult {
+	res := slaResult{
+		availability: 100,
+	}
+	if elapsed > 0 {
+		res.qps = float64(requests) / elapsed.Seconds()
+	}
+	if requests == 0 {
+		return res
+	}
+
+	failed := float64(requests - success)
+	res.availability = float64(success) / float64(requests) * 100
+	allowed := (100 - s.availability) / 100 * float64(requests)
+	switch {
+	case allowed > 0:
+		res.budgetConsumed = failed / allowed * 100
+	case failed > 0:
+		res.budgetConsumed = 100
+	}
+
+	return res
+}
+
+func (r slaResult) qpsPassed(s *sla) bool {
+	return r.qps >= s.qps*slaQPSTolerance
+}
+
+func (r slaResult) availabilityPassed(s *sla) bool {
+	return r.availability >= s.availability
+}
+
+// qpsFailure describes violation of SLA qps. Is empty if qps was sustained
+func (r slaResult) qpsFailure(s *sla) string {
+	if r.qpsPassed(s) {
+		return ""
+	}
+	return fmt.Sprintf("SLA qps %.0f wasn't sustained: got %.2f", s.qps, r.qps)
+}
+
+// availabilityFailure describes violation of SLA availability. Is empty if availability was met
+func (r slaResult) availabilityFailure(s *sla) string {
+	if r.availabilityPassed(s) {
+		return ""
+	}
+	return fmt.Sprintf("SLA availability %.3f%% wasn't met: got %.3f%%", s.availability, r.availability)
+}
+
+// failures returns list of SLA violations
+func (r slaResult) failures(s *sla) []string {
+	var failures []string
+	for _, f := range []string{r.qpsFailure(s), r.availabilityFailure(s)} {
+		if f != "" {
+			failures = append(failures, f)
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSLA(t *testing.T) {
+	s, err := parseSLA("5000qps,10m,99.9%")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	exp := sla{qps: 5000, duration: 10 * time.Minute, availability: 99.9}
+	if *s != exp {
+		t.Errorf("Unexpected SLA. Got: %+v; Expected: %+v", *s, exp)
+	}
+
+	for _, str := range []string{"", "10m,99.9%", "5000qps,10m", "5000qps,101%", "5000qps,foo,99%"} {
+		if _, err := parseSLA(str); err == nil {
+			t.Errorf("Expected error while parsing %q", str)
+		}
+	}
+}
+
+func TestSLACheck(t *testing.T) {
+	s := &sla{qps: 100, availability: 99}
+	res := s.check(1000, 995, 10*time.Second)
+	if res.qps != 100 {
+		t.Errorf("Unexpected qps. Got: %f; Expected: %f", res.qps, 100.0)
+	}
+	if res.budgetConsumed != 50 {
+		t.Errorf("Unexpected consumed budget. Got: %f; Expected: %f", res.budgetConsumed, 50.0)
+	}
+	if f := res.failures(s); len(f) != 0 {
+		t.Errorf("Unexpected failures: %v", f)
+	}
+
+	res = s.check(500, 400, 10*time.Second)
+	if f := res.failures(s); len(f) != 2 {
+		t.Errorf("Unexpected number of failures. Got: %d; Expected: %d", len(f), 2)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+var (
+	soak = flag.Bool("soak", false, "Hold constant rate of -q for the whole -d, which is 1h unless set, and fit linear trends to latency percentiles "+
+		"and errors rate. Test fails if any of them grows significantly, which is a signature of leak on target")
+	soakMaxDrift = flag.Float64("soak-max-drift", 10, "Max growth of latency percentile over -soak test in percents of its fitted value at start. "+
+		"Statistically significant growth above it fails the test")
+	soakMaxErrorsDrift = flag.Float64("soak-max-errors-drift", 1, "Max growth of errors rate over -soak test in percentage points, e.g. 1 allows growth from 0.5% to 1.5%. "+
+		"Statistically significant growth above it fails the test")
+)
+
+const (
+	// soakDuration is a default -d of -soak test
+	soakDuration = time.Hour
+	// soakSamplePeriod is a default -samplePeriod of -soak test, so hours of samples don't bloat report
+	soakSamplePeriod = 5 * time.Second
+)
+
+// soakQuantiles are quantiles of latency, trends of which are analyzed by -soak test
+var soakQuantiles = []float64{0.5, 0.9, 0.99}
+
+// soakLeaks describes metrics, which grew significantly over -soak test
+var soakLeaks []string
+
+// applySoak checks flags of -soak mode, where load phase keeps rate of -q constant for hours
+func applySoak() {
+	if *q == 0 {
+		usageAndExit("-soak requires -q, since rate must be constant")
+	}
+	if *soakMaxDrift <= 0 {
+		usageAndExit("-soak-max-drift must be positive")
+	}
+	if *soakMaxErrorsDrift <= 0 {
+		usageAndExit("-soak-max-errors-drift must be positive")
+	}
+	if *loadModel == loadModelConcurrency || *wsFlag || *profileFlag != "" || *burstFlag != "" || *rampSteps > 0 || *concurrencySweep != "" ||
+		*bodySizeSweep != "" || *replayTiming == "original" || *mode != "" || *totalRequests > 0 || *controlListen != "" {
+		usageAndExit("-soak can't be used with -load-model concurrency, -ws, -profile, -burst-pattern, -ramp-steps, sweeps, " +
+			"-replay-timing original, -mode, -n or -control-listen, since rate must stay constant for the whole -d")
+	}
+	if !isFlagSet("d") {
+		*d = soakDuration
+	}
+	if !isFlagSet("samplePeriod") {
+		*samplePeriod = soakSamplePeriod
+	}
+}
+
+// soakDrifts fits trends of latency percentiles and errors rate over steady phase
+func soakDrifts() []report.Drift {
+	var drifts []report.Drift
+	for _, q := range soakQuantiles {
+		if d, ok := r.LatencyTrend(steadyFrom, q); ok {
+			drifts = append(drifts, d)
+		}
+	}
+	if d, ok := r.ErrorRateTrend(steadyFrom); ok {
+		drifts = append(drifts, d)
+	}
+	return drifts
+}
+
+// isLeak reports whether d grows significantly above -soak-max-drift,
+// or above -soak-max-errors-drift for errors rate
+func isLeak(d report.Drift) bool {
+	max := *soakMaxDrift
+	if d.Metric == report.ErrorRateMetric {
+		max = *soakMaxErrorsDrift
+	}
+	return d.Significant && d.Growth() > max
+}
+
+// printSoakTrends prints trends of latency and errors rate over -soak test and collects leaks
+func printSoakTrends() {
+	if !*soak || steadyFrom < 0 {
+		return
+	}
+	fmt.Fprintln(out, "------ Soak trends ------")
+	r.Drifts = soakDrifts()
+	if len(r.Drifts) == 0 {
+		fmt.Fprintf(out, "Too few samples to fit trends\n\n")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Metric\tStart\tEnd\tPer hour\tGrowth\tt\t")
+	for _, d := range r.Drifts {
+		format := formatLatency
+		if d.Metric == report.ErrorRateMetric {
+			format = func(v float64) string { return fmt.Sprintf("%.2f %%", v) }
+		}
+		mark := ""
+		if isLeak(d) {
+			mark = "leak suspected"
+			soakLeaks = append(soakLeaks, fmt.Sprintf("%s grew from %s to %s over soak test", d.Metric, format(d.Start), format(d.End)))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%.2f\t%s\n", d.Metric, format(d.Start), format(d.End), format(d.PerHour), d.FormatGrowth(), d.T, mark)
+	}
+	w.Flush()
+	for _, l := range soakLeaks {
+		fmt.Fprintf(out, "Warning: %s\n", l)
+	}
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+func TestIsLeak(t *testing.T) {
+	f := func(d report.Drift, expected bool) {
+		t.Helper()
+		if got := isLeak(d); got != expected {
+			t.Errorf("Unexpected leak of %+v. Got: %v; Expected: %v", d, got, expected)
+		}
+	}
+
+	f(report.Drift{Start: 0.1, End: 0.2, Significant: true}, true)
+	// growth below -soak-max-drift
+	f(report.Drift{Start: 0.1, End: 0.105, Significant: true}, false)
+	f(report.Drift{Start: 0.1, End: 0.2}, false)
+	// errors rate growth is measured in percentage points, so errors appeared by -soak-max-errors-drift from zero
+	f(report.Drift{Metric: report.ErrorRateMetric, Start: 0, End: 2, Significant: true}, true)
+	f(report.Drift{Metric: report.ErrorRateMetric, Start: 0, End: 0.05, Significant: true}, false)
+	f(report.Drift{Metric: report.ErrorRateMetric, Start: -0.2, End: 0.3, Significant: true}, false)
+	// 50% relative growth of errors rate is below -soak-max-errors-drift
+	f(report.Drift{Metric: report.ErrorRateMetric, Start: 1, End: 1.5, Significant: true}, false)
+}
+
+func TestPrintSoakTrends(t *testing.T) {
+	defer func(page *report.Page, from int, w io.Writer) {
+		r, steadyFrom, out = page, from, w
+		*soak = false
+		soakLeaks = nil
+	}(r, steadyFrom, out)
+	var buf bytes.Buffer
+	out = &buf
+	*soak = true
+	steadyFrom = 0
+	r = &report.Page{
+		Interval:    60,
+		LatencyUnit: "ms",
+		RequestSum:  []uint64{0, 100, 200, 300, 400, 500, 600, 700},
+		Errors:      []uint64{0, 0, 0, 0, 0, 0, 0, 0},
+		RequestDuration: map[float64][]float64{
+			0.5:  {0.010, 0.010, 0.011, 0.010, 0.010, 0.011, 0.010, 0.010},
+			0.9:  {0.020, 0.021, 0.020, 0.020, 0.021, 0.020, 0.020, 0.021},
+			0.99: {0.050, 0.061, 0.069, 0.082, 0.089, 0.101, 0.109, 0.122},
+		},
+	}
+	printSoakTrends()
+
+	if len(r.Drifts) != 4 {
+		t.Fatalf("Unexpected number of trends. Got: %d; Expected: %d", len(r.Drifts), 4)
+	}
+	if len(soakLeaks) != 1 || !strings.HasPrefix(soakLeaks[0], "p99 grew from") {
+		t.Errorf("Unexpected leaks: %q", soakLeaks)
+	}
+	if !strings.Contains(buf.String(), "leak suspected") {
+		t.Errorf("Leak isn't printed: %s", buf.String())
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// expectedStatusCodes are status codes of successful responses set by -expectStatus
+var expectedStatusCodes map[int]bool
+
+// parseExpectStatus parses comma-separated list of status codes like "200,204"
+func parseExpectStatus(s string) (map[int]bool, error) {
+	codes := make(map[int]bool)
+	for _, v := range strings.Split(s, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("cannot parse status code %q of -expectStatus; list like \"200,204\" is expected", v)
+		}
+		codes[code] = true
+	}
+	return codes, nil
+}
+
+// isExpectedStatus returns true if response with status code sc is successful
+func isExpectedStatus(sc int) bool {
+	if len(expectedStatusCodes) > 0 {
+		return expectedStatusCodes[sc]
+	}
+	return sc == *successStatusCode
+}
+
+// printStatusCounts prints number of responses by status class and by status code
+func printStatusCounts() {
+	counts := client.StatusCounts()
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "Status classes: %s; Status codes: %s\n",
+		formatStatusCounts(report.StatusClasses(counts)), formatStatusCounts(report.SortedStatusCounts(counts)))
+}
+
+func formatStatusCounts(counts []report.StatusCount) string {
+	a := make([]string, len(counts))
+	for i, sc := range counts {
+		a[i] = fmt.Sprintf("%s: %d", sc.Status, sc.Requests)
+	}
+	return strings.Join(a, ", ")
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExpectStatus(t *testing.T) {
+	f := func(s string, expected map[int]bool) {
+		t.Helper()
+		codes, err := parseExpectStatus(s)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if !reflect.DeepEqual(codes, expected) {
+			t.Errorf("Unexpected result for %q. Got: %v; Expected: %v", s, codes, expected)
+		}
+	}
+
+	f("200", map[int]bool{200: true})
+	f("200,204", map[int]bool{200: true, 204: true})
+	f("200, 204,200", map[int]bool{200: true, 204: true})
+}
+
+func TestParseExpectStatusError(t *testing.T) {
+	for _, s := range []string{"", "200,", "ok", "99", "600", "200-204"} {
+		if _, err := parseExpectStatus(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
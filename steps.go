@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/valyala/fasthttp"
+)
+
+// stepExtractions are extractions of responses of -scenario steps by index of target.
+// Is empty unless -scenario-steps is set
+var stepExtractions [][]fastclient.Extraction
+
+var (
+	// stepVarName matches valid names of variables of steps
+	stepVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// stepPlaceholder matches placeholders of variables like "${token}"
+	stepPlaceholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// checkStepVars checks extractions of steps and that placeholders of every step
+// refer to variables extracted by previous steps
+func checkStepVars(entries []scenarioEntry) error {
+	extracted := make(map[string]bool)
+	for i, e := range entries {
+		parts := []string{e.Path, e.Body}
+		for _, v := range e.Headers {
+			parts = append(parts, v)
+		}
+		for _, part := range parts {
+			for _, m := range stepPlaceholder.FindAllStringSubmatch(part, -1) {
+				if !extracted[m[1]] {
+					return fmt.Errorf("request %d refers to variable %q, which isn't extracted by previous requests", i+1, m[1])
+				}
+			}
+		}
+		if _, err := stepExtractionsOf(e); err != nil {
+			return fmt.Errorf("request %d: %s", i+1, err)
+		}
+		for name := range e.Extract {
+			extracted[name] = true
+		}
+	}
+	return nil
+}
+
+// stepExtractionsOf returns extractions of e sorted by name of variable
+func stepExtractionsOf(e scenarioEntry) ([]fastclient.Extraction, error) {
+	names := make([]string, 0, len(e.Extract))
+	for name := range e.Extract {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var result []fastclient.Extraction
+	for _, name := range names {
+		ex, err := parseExtraction(name, e.Extract[name])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ex)
+	}
+	return result, nil
+}
+
+// parseExtraction parses extraction of variable name from response like "json:$.data.token",
+// which is JSONPath of decoded body, or "regex:token=(\w+)", which is the first capture group
+// of regular expression matched against decoded body, or the whole match if there are no groups
+func parseExtraction(name, spec string) (fastclient.Extraction, error) {
+	if !stepVarName.MatchString(name) {
+		return fastclient.Extraction{}, fmt.Errorf("invalid name of variable %q; it must contain only letters, digits and underscores", name)
+	}
+	kind, expr := spec, ""
+	if n := strings.Index(spec, ":"); n >= 0 {
+		kind, expr = spec[:n], spec[n+1:]
+	}
+	var extract func(resp *fasthttp.Response) (string, bool)
+	switch kind {
+	case "json":
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			return fastclient.Extraction{}, fmt.Errorf("cannot parse JSONPath of %s: %s", name, err)
+		}
+		extract = func(resp *fasthttp.Response) (string, bool) {
+			return jsonPathValue(responseBody(resp), path)
+		}
+	case "regex":
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fastclient.Extraction{}, fmt.Errorf("cannot parse regex of %s: %s", name, err)
+		}
+		group := 0
+		if re.NumSubexp() > 0 {
+			group = 1
+		}
+		extract = func(resp *fasthttp.Response) (string, bool) {
+			m := re.FindSubmatch(responseBody(resp))
+			if m == nil {
+				return "", false
+			}
+			return string(m[group]), true
+		}
+	default:
+		return fastclient.Extraction{}, fmt.Errorf("unsupported extraction %q of %s; it must be like \"json:$.token\" or \"regex:token=(\\\\w+)\"", spec, name)
+	}
+	return fastclient.Extraction{Name: name, Extract: extract}, nil
+}
+
+// parseJSONPath parses JSONPath like "$.items[0].id" into keys of objects and indexes of arrays,
+// which are strings and ints. Leading "$." may be omitted
+func parseJSONPath(path string) ([]interface{}, error) {
+	s := path
+	if strings.HasPrefix(s, "$") {
+		s = s[1:]
+	} else if s != "" && s[0] != '[' {
+		s = "." + s
+	}
+	var result []interface{}
+	for s != "" {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			n := strings.IndexAny(s, ".[")
+			if n < 0 {
+				n = len(s)
+			}
+			if n == 0 {
+				return nil, fmt.Errorf("empty key in %q", path)
+			}
+			result = append(result, s[:n])
+			s = s[n:]
+		case '[':
+			n := strings.Index(s, "]")
+			if n < 0 {
+				return nil, fmt.Errorf("missing ] in %q", path)
+			}
+			idx, err := strconv.Atoi(s[1:n])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("invalid index %q in %q", s[1:n], path)
+			}
+			result = append(result, idx)
+			s = s[n+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q; path must be like $.items[0].id", s[0], path)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("path %q refers to the whole body", path)
+	}
+	return result, nil
+}
+
+// jsonPathValue returns value of JSON body at path. Strings are returned unquoted,
+// while other values are returned as JSON. Returns false if body has no such value
+func jsonPathValue(body []byte, path []interface{}) (string, bool) {
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return "", false
+	}
+	for _, p := range path {
+		switch p := p.(type) {
+		case string:
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return "", false
+			}
+			if v, ok = m[p]; !ok {
+				return "", false
+			}
+		case int:
+			a, ok := v.([]interface{})
+			if !ok || p >= len(a) {
+				return "", false
+			}
+			v = a[p]
+		}
+	}
+	switch v := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
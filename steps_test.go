@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseExtraction(t *testing.T) {
+	f := func(spec, body, expected string, expectedOK bool) {
+		t.Helper()
+		e, err := parseExtraction("v", spec)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", spec, err)
+		}
+		var resp fasthttp.Response
+		resp.SetBodyString(body)
+		got, ok := e.Extract(&resp)
+		if got != expected || ok != expectedOK {
+			t.Errorf("Unexpected value of %q. Got: %q, %v; Expected: %q, %v", spec, got, ok, expected, expectedOK)
+		}
+	}
+
+	body := `{"data": {"token": "abc", "items": [{"id": 7}, {"id": 8, "tags": ["x"]}], "ok": true, "none": null}}`
+	f("json:$.data.token", body, "abc", true)
+	f("json:data.token", body, "abc", true)
+	f("json:$.data.items[1].id", body, "8", true)
+	f("json:$.data.items[1].tags", body, `["x"]`, true)
+	f("json:$.data.ok", body, "true", true)
+	f("json:$.data.none", body, "", false)
+	f("json:$.data.items[2].id", body, "", false)
+	f("json:$.data.token.value", body, "", false)
+	f("json:$.data.missing", body, "", false)
+	f("json:$.data.token", "not json", "", false)
+	f(`regex:token=(\w+)`, "id=1&token=xyz&a=b", "xyz", true)
+	f(`regex:[0-9]+`, "id=123", "123", true)
+	f(`regex:token=(\w+)`, "id=1", "", false)
+}
+
+func TestParseExtractionError(t *testing.T) {
+	f := func(name, spec string) {
+		t.Helper()
+		if _, err := parseExtraction(name, spec); err == nil {
+			t.Errorf("Expected error for %q: %q", name, spec)
+		}
+	}
+
+	f("v", "token")
+	f("v", "xpath://token")
+	f("v", "json:$")
+	f("v", "json:$.")
+	f("v", "json:$.items[x]")
+	f("v", "json:$.items[0")
+	f("v", "json:$items")
+	f("v", "regex:(")
+	f("1v", "json:$.token")
+	f("v-1", "json:$.token")
+}
+
+func TestParseJSONPath(t *testing.T) {
+	path, err := parseJSONPath("$.items[0].tags[12]")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []interface{}{"items", 0, "tags", 12}; !reflect.DeepEqual(path, expected) {
+		t.Errorf("Unexpected path. Got: %v; Expected: %v", path, expected)
+	}
+}
+
+func TestCheckStepVars(t *testing.T) {
+	f := func(entries []scenarioEntry, expectedErr bool) {
+		t.Helper()
+		if err := checkStepVars(entries); (err != nil) != expectedErr {
+			t.Errorf("Unexpected error for %+v: %v", entries, err)
+		}
+	}
+
+	login := scenarioEntry{Path: "/login", Extract: map[string]string{"token": "json:$.token"}}
+	f([]scenarioEntry{login, {Path: "/items?t=${token}"}}, false)
+	f([]scenarioEntry{login, {Path: "/items", Headers: map[string]string{"Authorization": "Bearer ${token}"}}}, false)
+	f([]scenarioEntry{login, {Path: "/items", Body: `{"t": "${token}"}`}}, false)
+	f([]scenarioEntry{{Path: "/items?t=${token}"}, login}, true)
+	f([]scenarioEntry{{Path: "/login", Body: "${token}", Extract: map[string]string{"token": "json:$.token"}}}, true)
+	f([]scenarioEntry{login, {Path: "/items?t=${id}"}}, true)
+	f([]scenarioEntry{{Path: "/login", Extract: map[string]string{"token": "token"}}}, true)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hagen1778/fasthttploader/ratelimiter"
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+// sweepRpsShare is a min share of the best rps, which level of concurrency sweep
+// must achieve to be considered as optimal
+const sweepRpsShare = 0.99
+
+// sweepLevel is a number of clients of -concurrency-sweep, size of body of -body-size-sweep
+// or major version of HTTP of -compare-protocols
+type sweepLevel struct {
+	value uint64
+	label string
+}
+
+// protocolLevels are levels of -compare-protocols. HTTP/1.1 is a baseline of comparison
+var protocolLevels = []sweepLevel{{1, "HTTP/1.1"}, {2, "HTTP/2"}}
+
+// parseConcurrencySweep parses list of numbers of clients like "50,100,200,500"
+func parseConcurrencySweep(s string) ([]sweepLevel, error) {
+	return parseSweep(s, "number of clients", func(v string) (uint64, error) {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("number of clients %q of concurrency sweep must be a positive integer", v)
+		}
+		return n, nil
+	}, "clients")
+}
+
+// parseBodySizeSweep parses list of body sizes like "1KB,10KB,100KB,1MB"
+func parseBodySizeSweep(s string) ([]sweepLevel, error) {
+	return parseSweep(s, "body size", func(v string) (uint64, error) {
+		n, err := parseBytes(v)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("body size %q of body size sweep must be a positive size like \"10KB\"", v)
+		}
+		return n, nil
+	}, "body")
+}
+
+// parseSweep parses comma-separated list of at least two distinct values by parse.
+// Label of level is a value as it is written in list followed by suffix, like "10KB body"
+func parseSweep(s, name string, parse func(string) (uint64, error), suffix string) ([]sweepLevel, error) {
+	var levels []sweepLevel
+	seen := make(map[uint64]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		n, err := parse(part)
+		if err != nil {
+			return nil, err
+		}
+		if seen[n] {
+			return nil, fmt.Errorf("%s %s is repeated in sweep", name, part)
+		}
+		seen[n] = true
+		levels = append(levels, sweepLevel{value: n, label: part + " " + suffix})
+	}
+	if len(levels) < 2 {
+		return nil, fmt.Errorf("sweep %q must contain at least two values of %s", s, name)
+	}
+	return levels, nil
+}
+
+// syntheticBody returns body of n random letters
+func syntheticBody(n uint64) []byte {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	rnd := seededRand("sweep-body", int64(n))
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[rnd.Intn(len(letters))]
+	}
+	return b
+}
+
+// runSweep runs load phase with the same qps for every level of sweep:
+// with given number of clients, given size of body or over given protocol.
+// Start of every level is annotated on report charts
+func runSweep(ctx context.Context, cfg *loadConfig) {
+	r.SweepOf = "clients"
+	if *bodySizeSweep != "" {
+		r.SweepOf = "body-size"
+	} else if *compareProtocols {
+		r.SweepOf = "protocol"
+	}
+	for i, level := range sweepLevels {
+		if isBytesCapReached() {
+			fmt.Fprintf(out, "Sweep is stopped before %s, since -max-bytes is reached\n", level.label)
+			return
+		}
+		if isInterrupted() {
+			fmt.Fprintf(out, "Sweep is stopped before %s, since test was interrupted\n", level.label)
+			return
+		}
+		if aborted != nil {
+			fmt.Fprintf(out, "Sweep is stopped before %s, since load phase was aborted\n", level.label)
+			return
+		}
+		if i > 0 {
+			// workers of previous level must not send requests during the next one
+			bytesSpent += client.BytesRead() + client.BytesWritten()
+			client.Flush()
+			client = nil
+			// throttle can't be used after stop
+			throttle = ratelimiter.NewLimiter()
+		}
+		fmt.Fprintf(out, "Run load phase with %s (%d of %d)\n", level.label, i+1, len(sweepLevels))
+		samplePhase = level.label
+		switch {
+		case *bodySizeSweep != "":
+			req.SetBody(syntheticBody(level.value))
+		case *compareProtocols:
+			// client of level is created by makeLoad, so it picks protocol up
+			*http2 = level.value == 2
+		default:
+			cfg.c = int(level.value)
+		}
+		makeLoad(ctx, cfg)
+		stagePoints[len(stagePoints)-1].name = level.label
+
+		lat := client.Latency()
+		l := report.SweepLevel{
+			Value:       level.value,
+			Requests:    client.RequestSum(),
+			Rps:         float64(client.RequestSum()) / loadElapsed.Seconds(),
+			P99:         client.RequestDuration()[0.99],
+			Latency:     report.Latency{P50: lat.P50, P90: lat.P90, P95: lat.P95, P99: lat.P99, Max: lat.Max},
+			Connections: client.Connects(),
+		}
+		if l.Requests > 0 {
+			l.ErrorRate = float64(client.Errors()) / float64(l.Requests) * 100
+		}
+		r.Sweep = append(r.Sweep, l)
+	}
+}
+
+// optimalSweepLevel returns index of level with the lowest p99 latency among levels
+// with the least errors rate, which achieved at least sweepRpsShare of their best rps
+func optimalSweepLevel(levels []report.SweepLevel) int {
+	minErrorRate := levels[0].ErrorRate
+	for _, l := range levels {
+		if l.ErrorRate < minErrorRate {
+			minErrorRate = l.ErrorRate
+		}
+	}
+	var bestRps float64
+	for _, l := range levels {
+		if l.ErrorRate == minErrorRate && l.Rps > bestRps {
+			bestRps = l.Rps
+		}
+	}
+	optimal := -1
+	for i, l := range levels {
+		if l.ErrorRate > minErrorRate || l.Rps < bestRps*sweepRpsShare {
+			continue
+		}
+		if optimal < 0 || l.P99 < levels[optimal].P99 {
+			optimal = i
+		}
+	}
+	return optimal
+}
+
+func printSweep() {
+	if len(r.Sweep) == 0 {
+		return
+	}
+
+	switch r.SweepOf {
+	case "body-size":
+		fmt.Fprintln(out, "------ Body size sweep ------")
+	case "protocol":
+		fmt.Fprintln(out, "------ Comparison of protocols ------")
+	default:
+		fmt.Fprintln(out, "------ Concurrency sweep ------")
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	switch r.SweepOf {
+	case "body-size":
+		fmt.Fprintln(w, "Body size\tRps\tUpload\tErrors\tp99")
+	case "protocol":
+		fmt.Fprintln(w, "Protocol\tRps\tErrors\tp99\tConnections")
+	default:
+		fmt.Fprintln(w, "Clients\tRps\tErrors\tp99")
+	}
+	for i, l := range r.Sweep {
+		p99 := "insufficient data"
+		if l.Requests >= *minSamples {
+			p99 = formatLatency(l.P99)
+		}
+		switch r.SweepOf {
+		case "body-size":
+			upload := formatBytes(uint64(l.Rps*float64(l.Value))) + "/s"
+			fmt.Fprintf(w, "%s\t%.2f\t%s\t%.2f %%\t%s\n", formatBytes(l.Value), l.Rps, upload, l.ErrorRate, p99)
+		case "protocol":
+			fmt.Fprintf(w, "%s\t%.2f\t%.2f %%\t%s\t%d\n", protocolLevels[i].label, l.Rps, l.ErrorRate, p99, l.Connections)
+		default:
+			fmt.Fprintf(w, "%d\t%.2f\t%.2f %%\t%s\n", l.Value, l.Rps, l.ErrorRate, p99)
+		}
+	}
+	w.Flush()
+	switch r.SweepOf {
+	case "protocol":
+		printProtocolComparison()
+	case "clients":
+		fmt.Fprintf(out, "Optimal number of clients: %d\n", r.Sweep[optimalSweepLevel(r.Sweep)].Value)
+	}
+	fmt.Fprintln(out)
+}
+
+// protocolResult returns result of level of -compare-protocols, whose latency is known
+// only if level has at least -min-samples requests
+func protocolResult(l report.SweepLevel) *report.Result {
+	res := &report.Result{Rps: l.Rps, ErrorRate: l.ErrorRate, Connections: l.Connections}
+	if l.Requests >= *minSamples {
+		lat := l.Latency
+		res.Latency = &lat
+	}
+	return res
+}
+
+// printProtocolComparison prints deltas of HTTP/2 compared to HTTP/1.1 and writes them
+// to -compare-protocols-out as HTML comparison
+func printProtocolComparison() {
+	if len(r.Sweep) < len(protocolLevels) {
+		fmt.Fprintln(out, "Protocols aren't compared, since HTTP/2 wasn't run")
+		return
+	}
+	c := report.Compare(protocolResult(r.Sweep[0]), protocolResult(r.Sweep[1]), defaultTolerances)
+	c.Baseline, c.Current = protocolLevels[0].label, protocolLevels[1].label
+	fmt.Fprintf(out, "%s compared to %s:\n", c.Current, c.Baseline)
+	printDeltas(out, c)
+	if *compareProtocolsOut == "" {
+		return
+	}
+	if err := ioutil.WriteFile(*compareProtocolsOut, []byte(report.PrintComparison(c)), 0644); err != nil {
+		slog.Error("Error while writing comparison of protocols", "error", err)
+		return
+	}
+	fmt.Fprintf(out, "Comparison of protocols is written to %s\n", *compareProtocolsOut)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/ratelimiter"
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+func TestParseConcurrencySweep(t *testing.T) {
+	f := func(s string, expected []sweepLevel) {
+		t.Helper()
+		levels, err := parseConcurrencySweep(s)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if !reflect.DeepEqual(levels, expected) {
+			t.Errorf("Unexpected result for %q. Got: %v; Expected: %v", s, levels, expected)
+		}
+	}
+
+	f("50,100", []sweepLevel{{50, "50 clients"}, {100, "100 clients"}})
+	f("200, 50", []sweepLevel{{200, "200 clients"}, {50, "50 clients"}})
+}
+
+func TestParseConcurrencySweepError(t *testing.T) {
+	for _, s := range []string{"", "50", "50,", "50,0", "50,-1", "50,abc", "50,100,50"} {
+		if _, err := parseConcurrencySweep(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestParseBodySizeSweep(t *testing.T) {
+	levels, err := parseBodySizeSweep("1KB, 10KiB,1MB")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []sweepLevel{{1000, "1KB body"}, {10240, "10KiB body"}, {1000000, "1MB body"}}
+	if !reflect.DeepEqual(levels, expected) {
+		t.Errorf("Unexpected result. Got: %v; Expected: %v", levels, expected)
+	}
+
+	for _, s := range []string{"", "1KB", "1KB,0", "1KB,abc", "1KB,1000"} {
+		if _, err := parseBodySizeSweep(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestOptimalSweepLevel(t *testing.T) {
+	f := func(levels []report.SweepLevel, expected int) {
+		t.Helper()
+		if got := optimalSweepLevel(levels); got != expected {
+			t.Errorf("Unexpected optimal level for %+v. Got: %d; Expected: %d", levels, got, expected)
+		}
+	}
+
+	// rps saturates, so level with lower latency is preferred
+	f([]report.SweepLevel{
+		{Value: 50, Rps: 800, P99: 0.05},
+		{Value: 100, Rps: 1000, P99: 0.02},
+		{Value: 200, Rps: 995, P99: 0.04},
+	}, 1)
+	// errors outweigh rps
+	f([]report.SweepLevel{
+		{Value: 50, Rps: 800, P99: 0.05},
+		{Value: 100, Rps: 1000, P99: 0.02, ErrorRate: 1},
+	}, 0)
+}
+
+func TestRunSweepProtocols(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start listener: %s", err)
+	}
+	defer ln.Close()
+	// received counts requests by major version of HTTP
+	var received [3]atomic.Uint64
+	s := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received[r.ProtoMajor].Add(1)
+		}),
+		Protocols: new(http.Protocols),
+	}
+	s.Protocols.SetHTTP1(true)
+	s.Protocols.SetUnencryptedHTTP2(true)
+	go s.Serve(ln)
+
+	defer func(levels []sweepLevel, compare, h2 bool, cmpOut string, dur, timeout time.Duration, w io.Writer, page *report.Page) {
+		sweepLevels, *compareProtocols, *http2, *compareProtocolsOut, *d, *requestTimeout, out, r = levels, compare, h2, cmpOut, dur, timeout, w, page
+	}(sweepLevels, *compareProtocols, *http2, *compareProtocolsOut, *d, *requestTimeout, out, r)
+	defer func() { throttle = ratelimiter.NewLimiter() }()
+	sweepLevels, *compareProtocols, *d, *requestTimeout = protocolLevels, true, 500*time.Millisecond, time.Second
+	*compareProtocolsOut = filepath.Join(t.TempDir(), "protocols.html")
+	var buf bytes.Buffer
+	out = &buf
+	r = &report.Page{RequestDuration: make(map[float64][]float64)}
+	throttle = ratelimiter.NewLimiter()
+	req.SetRequestURI("http://" + ln.Addr().String() + "/")
+	runSweep(context.Background(), &loadConfig{qps: 200, c: 4})
+	defer client.Flush()
+
+	if len(r.Sweep) != 2 {
+		t.Fatalf("Unexpected number of sweep levels. Got: %d; Expected: 2", len(r.Sweep))
+	}
+	// both protocols are run with identical load. Requests in flight at the end of level are received, but aren't counted
+	for i, l := range r.Sweep {
+		proto := protocolLevels[i]
+		if got := received[proto.value].Load(); got == 0 || got < l.Requests {
+			t.Errorf("Unexpected number of %s requests received by target. Got: %d; Expected: at least %d", proto.label, got, l.Requests)
+		}
+		if l.Connections == 0 {
+			t.Errorf("Connections of %s must be counted", proto.label)
+		}
+		if l.Rps < 50 || l.Rps > 250 {
+			t.Errorf("Unexpected rps of %s. Got: %.2f; Expected: about 200", proto.label, l.Rps)
+		}
+	}
+
+	printSweep()
+	for _, substr := range []string{"Comparison of protocols", "HTTP/2 compared to HTTP/1.1", "connections", "Comparison of protocols is written to"} {
+		if !strings.Contains(buf.String(), substr) {
+			t.Errorf("Output must contain %q; got:\n%s", substr, buf.String())
+		}
+	}
+	b, err := os.ReadFile(*compareProtocolsOut)
+	if err != nil {
+		t.Fatalf("Cannot read comparison: %s", err)
+	}
+	if !strings.Contains(string(b), "HTTP/1.1 vs HTTP/2") {
+		t.Errorf("Comparison must compare HTTP/1.1 with HTTP/2")
+	}
+}
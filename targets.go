@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/report"
+	"github.com/valyala/fasthttp"
+)
+
+// urlList is a list of urls set by repeated flag or by comma-separated list
+type urlList []string
+
+func (l *urlList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *urlList) Set(s string) error {
+	for _, u := range strings.Split(s, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			*l = append(*l, u)
+		}
+	}
+	return nil
+}
+
+var (
+	urls urlList
+
+	// urlWeights are weights of urls, which are set if any url of -urls has weight
+	urlWeights []float64
+
+	// targets are requests to every url of -url and -urls. Is empty unless they are set
+	targets []*fasthttp.Request
+
+	urlsFile = flag.String("urls", "", "Set file with urls to send requests to instead of url argument, one per line. "+
+		"Url may be followed by its weight like \"http://10.0.0.1:8080/ 3\", so requests are spread by weights instead of round-robin. "+
+		"Empty lines and lines starting with # are skipped")
+)
+
+func init() {
+	flag.Var(&urls, "url", "Send requests to given urls in round-robin instead of url argument. "+
+		"Is set multiple times or as comma-separated list. Requests, errors and latency are also reported per url")
+}
+
+// parseURLs parses urls of -urls file and their weights, which are 1 if they aren't set.
+// Weights are nil if none of urls has weight
+func parseURLs(r io.Reader) ([]string, []float64, error) {
+	var us []string
+	var weights []float64
+	weighted := false
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		w := 1.0
+		switch len(fields) {
+		case 1:
+		case 2:
+			var err error
+			if w, err = strconv.ParseFloat(fields[1], 64); err != nil || w <= 0 {
+				return nil, nil, fmt.Errorf("weight at line %d must be positive number; got %q", n, fields[1])
+			}
+			weighted = true
+		default:
+			return nil, nil, fmt.Errorf("line %d must contain url optionally followed by weight", n)
+		}
+		us = append(us, fields[0])
+		weights = append(weights, w)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(us) == 0 {
+		return nil, nil, fmt.Errorf("file contains no urls")
+	}
+	if !weighted {
+		weights = nil
+	}
+	return us, weights, nil
+}
+
+// applyURLsFile adds urls of -urls file to urls of -url.
+// Urls of -url get weight 1 if urls of file are weighted
+func applyURLsFile() {
+	f, err := os.Open(*urlsFile)
+	if err != nil {
+		usageAndExit(fmt.Sprintf("cannot open -urls file: %s", err))
+	}
+	defer f.Close()
+	us, weights, err := parseURLs(f)
+	if err != nil {
+		usageAndExit(fmt.Sprintf("cannot parse -urls file %q: %s", *urlsFile, err))
+	}
+	if weights != nil {
+		for range urls {
+			urlWeights = append(urlWeights, 1)
+		}
+		urlWeights = append(urlWeights, weights...)
+	}
+	urls = append(urls, us...)
+}
+
+// applyTargets creates request for every url of -url and -urls. Requests differ from req only by url
+func applyTargets() {
+	if *curlFlag != "" || *grpcMethod != "" || *probeFile != "" || *dataFile != "" {
+		usageAndExit("-url can't be used with -curl, -grpc-method, -probe or -data")
+	}
+	if *verifyDNS || *dnsRoundRobin {
+		usageAndExit("-url can't be used with -verify-dns-distribution or -dns-round-robin")
+	}
+	scheme := string(req.URI().Scheme())
+	for _, u := range urls {
+		if isTemplate(u) {
+			usageAndExit(fmt.Sprintf("-url %q can't contain templates", u))
+		}
+		r := new(fasthttp.Request)
+		req.CopyTo(r)
+		r.SetRequestURI(encodeURL(u))
+		if len(r.URI().Host()) == 0 {
+			usageAndExit(fmt.Sprintf("-url %q must contain host", u))
+		}
+		if s := string(r.URI().Scheme()); s != scheme {
+			usageAndExit(fmt.Sprintf("all -url must have the same scheme; got %s and %s", scheme, s))
+		}
+		targets = append(targets, r)
+	}
+	targetWeights = urlWeights
+}
+
+// targetStats returns number of requests, rate, errors and latency of every url over load phase
+func targetStats() []report.TargetStat {
+	var result []report.TargetStat
+	for _, ts := range client.TargetStats() {
+		stat := report.TargetStat{
+			URL:      ts.URL,
+			Requests: ts.Requests,
+			Errors:   ts.Errors,
+			MaxQPS:   ts.MaxQPS,
+			Capped:   ts.Capped,
+			P50:      ts.Quantiles[0.5],
+			P90:      ts.Quantiles[0.9],
+			P95:      ts.Quantiles[0.95],
+			P99:      ts.Quantiles[0.99],
+		}
+		if loadElapsed > 0 {
+			stat.Rps = float64(ts.Requests) / loadElapsed.Seconds()
+		}
+		result = append(result, stat)
+	}
+	return result
+}
+
+// targetP99 returns p99 latency of every url
+func targetP99() map[string]float64 {
+	result := make(map[string]float64)
+	for _, ts := range client.TargetStats() {
+		result[ts.URL] = ts.Quantiles[0.99]
+	}
+	return result
+}
+
+// formatMaxQPS formats max rate of url and whether it was a binding constraint of its rate. Is empty if rate isn't limited
+func formatMaxQPS(ts fastclient.TargetStat) string {
+	if ts.MaxQPS == 0 {
+		return ""
+	}
+	if ts.Capped == 0 {
+		return fmt.Sprintf("; max rate %.2f rps isn't reached", ts.MaxQPS)
+	}
+	return fmt.Sprintf("; max rate %.2f rps is binding: %d requests were sent to other urls", ts.MaxQPS, ts.Capped)
+}
+
+// printTargets prints number of requests, rate, errors rate and latency of every url of stage lasted since seconds
+func printTargets(since float64) {
+	fmt.Fprintln(out, "Targets:")
+	for _, ts := range client.TargetStats() {
+		latency := "latency: insufficient data"
+		if ts.Requests >= *minSamples {
+			latency = fmt.Sprintf("p50: %s; p90: %s; p99: %s",
+				formatLatency(ts.Quantiles[0.5]), formatLatency(ts.Quantiles[0.9]), formatLatency(ts.Quantiles[0.99]))
+		}
+		var errorRate float64
+		if ts.Requests > 0 {
+			errorRate = float64(ts.Errors) / float64(ts.Requests) * 100
+		}
+		fmt.Fprintf(out, "  %s: requests %d (%.2f rps); errors %d (%.2f %%); %s%s\n",
+			ts.URL, ts.Requests, float64(ts.Requests)/since, ts.Errors, errorRate, latency, formatMaxQPS(ts))
+	}
+}
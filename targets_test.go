@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+)
+
+func TestURLListSet(t *testing.T) {
+	var l urlList
+	for _, s := range []string{"http://a/,http://b/", " http://c/ ", "http://d/,,"} {
+		if err := l.Set(s); err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+	}
+	expected := urlList{"http://a/", "http://b/", "http://c/", "http://d/"}
+	if !reflect.DeepEqual(l, expected) {
+		t.Errorf("Unexpected urls. Got: %v; Expected: %v", l, expected)
+	}
+	if s := l.String(); s != "http://a/,http://b/,http://c/,http://d/" {
+		t.Errorf("Unexpected string of urls: %q", s)
+	}
+}
+
+func TestParseURLs(t *testing.T) {
+	f := func(s string, urls []string, weights []float64) {
+		t.Helper()
+		gotURLs, gotWeights, err := parseURLs(strings.NewReader(s))
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if !reflect.DeepEqual(gotURLs, urls) {
+			t.Errorf("Unexpected urls for %q. Got: %v; Expected: %v", s, gotURLs, urls)
+		}
+		if !reflect.DeepEqual(gotWeights, weights) {
+			t.Errorf("Unexpected weights for %q. Got: %v; Expected: %v", s, gotWeights, weights)
+		}
+	}
+
+	f("http://a/\nhttp://b/\n", []string{"http://a/", "http://b/"}, nil)
+	f("# backends\n\n  http://a/ 3\nhttp://b/\n", []string{"http://a/", "http://b/"}, []float64{3, 1})
+	f("http://a/ 0.5\r\nhttp://b/ 1.5", []string{"http://a/", "http://b/"}, []float64{0.5, 1.5})
+}
+
+func TestParseURLsError(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		if _, _, err := parseURLs(strings.NewReader(s)); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+
+	f("")
+	f("# only comment\n")
+	f("http://a/ 0")
+	f("http://a/ -1")
+	f("http://a/ heavy")
+	f("http://a/ 1 2")
+}
+
+func TestFormatMaxQPS(t *testing.T) {
+	f := func(ts fastclient.TargetStat, expected string) {
+		t.Helper()
+		if s := formatMaxQPS(ts); s != expected {
+			t.Errorf("Unexpected max rate of %+v. Got: %q; Expected: %q", ts, s, expected)
+		}
+	}
+
+	f(fastclient.TargetStat{Requests: 100}, "")
+	f(fastclient.TargetStat{Requests: 100, MaxQPS: 10}, "; max rate 10.00 rps isn't reached")
+	f(fastclient.TargetStat{Requests: 100, MaxQPS: 10, Capped: 7}, "; max rate 10.00 rps is binding: 7 requests were sent to other urls")
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/valyala/fasthttp"
+)
+
+// dataSet represents rows of CSV data file,
+// which are used to fill request templates.
+// Rows are served in cycle
+type dataSet struct {
+	// columns is a map column name:column index
+	columns map[string]int
+	rows    [][]string
+
+	// timeoutColumn is an index of column with per-request timeouts.
+	// Is -1 if timeouts aren't set
+	timeoutColumn int
+	maxTimeout    time.Duration
+
+	mu   sync.Mutex
+	next int
+}
+
+func readDataSet(path string, shuffle bool, timeoutColumn string) (*dataSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open data file: %s", err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse data file %q: %s", path, err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("data file %q must contain header and at least one row", path)
+	}
+
+	ds := &dataSet{
+		columns:       make(map[string]int, len(records[0])),
+		rows:          records[1:],
+		timeoutColumn: -1,
+	}
+	for i, name := range records[0] {
+		ds.columns[strings.TrimSpace(name)] = i
+	}
+	if timeoutColumn != "" {
+		i, ok := ds.columns[timeoutColumn]
+		if !ok {
+			return nil, fmt.Errorf("timeout column %q is missing in data file header", timeoutColumn)
+		}
+		for n, row := range ds.rows {
+			timeout, err := parseTimeout(row[i])
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse timeout at line %d of data file %q: %s", n+2, path, err)
+			}
+			if timeout > ds.maxTimeout {
+				ds.maxTimeout = timeout
+			}
+		}
+		ds.timeoutColumn = i
+	}
+	if shuffle {
+		rnd := seededRand("data-shuffle", 0)
+		for i := len(ds.rows) - 1; i > 0; i-- {
+			j := rnd.Intn(i + 1)
+			ds.rows[i], ds.rows[j] = ds.rows[j], ds.rows[i]
+		}
+	}
+
+	return ds, nil
+}
+
+// parseTimeout parses per-request timeout.
+// Empty value means that default timeout must be used
+func parseTimeout(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// nextRow returns next row of data set
+// is thread-safe
+func (ds *dataSet) nextRow() []string {
+	ds.mu.Lock()
+	row := ds.rows[ds.next]
+	ds.next = (ds.next + 1) % len(ds.rows)
+	ds.mu.Unlock()
+
+	return row
+}
+
+// requestTemplate contains templates for parts of request
+// which must be rendered before every request
+type requestTemplate struct {
+	uri     *template.Template
+	body    *template.Template
+	headers map[string]*template.Template
+
+	data *dataSet
+
+	// workers is a number of workers bound to template, by which their fakers are seeded
+	workers int64
+}
+
+// templateFuncs are used only while parsing,
+// every worker binds them to its own state
+var templateFuncs = template.FuncMap{
+	"col": func(string) (string, error) { return "", nil },
+}
+
+func init() {
+	for k, f := range newFaker(0).funcs() {
+		templateFuncs[k] = f
+	}
+}
+
+// templateFiles caches non-empty lines of files read by file function of templates,
+// so every file is read once
+var templateFiles = struct {
+	sync.Mutex
+	lines map[string][]string
+}{lines: make(map[string][]string)}
+
+// fileLines returns non-empty lines of file at path. File is read by the first call,
+// which is done by dry run of templates, so missing files are reported before test starts
+func fileLines(path string) ([]string, error) {
+	templateFiles.Lock()
+	defer templateFiles.Unlock()
+	if lines, ok := templateFiles.lines[path]; ok {
+		return lines, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file of template: %s", err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("file %q of template has no lines", path)
+	}
+	templateFiles.lines[path] = lines
+	return lines, nil
+}
+
+func isTemplate(s string) bool {
+	return strings.Contains(s, "{{")
+}
+
+// newRequestTemplate parses templates from uri, body and headers of req.
+// Returns nil if req doesn't contain any of them
+func newRequestTemplate(req *fasthttp.Request, uri string, data *dataSet) (*requestTemplate, error) {
+	var err error
+	rt := &requestTemplate{
+		headers: make(map[string]*template.Template),
+		data:    data,
+	}
+	if isTemplate(uri) {
+		if rt.uri, err = parseTemplate("url", uri); err != nil {
+			return nil, err
+		}
+	}
+	if body := string(req.Body()); isTemplate(body) {
+		if rt.body, err = parseTemplate("body", body); err != nil {
+			return nil, err
+		}
+	}
+	req.Header.VisitAll(func(k, v []byte) {
+		if err != nil || !isTemplate(string(v)) {
+			return
+		}
+		rt.headers[string(k)], err = parseTemplate("header "+string(k), string(v))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rt.uri == nil && rt.body == nil && len(rt.headers) == 0 && (data == nil || data.timeoutColumn < 0) {
+		return nil, nil
+	}
+
+	// dry run to catch missing columns before test starts
+	r := new(fasthttp.Request)
+	req.CopyTo(r)
+	if err = rt.newWorker().render(r); err != nil {
+		return nil, err
+	}
+	if data != nil {
+		data.next = 0
+	}
+	// dry run must not consume numbers of seq and workers
+	lastSeq = 0
+	rt.workers = 0
+
+	return rt, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse template of %s: %s", name, err)
+	}
+	return t, nil
+}
+
+// Modifier returns fastclient.Modifier which renders templates
+// into request. Is used as fastclient.Client.NewModifier.
+// Request, which can't be rendered, isn't sent and is counted as error of class "modifier"
+func (rt *requestTemplate) Modifier() fastclient.Modifier {
+	w := rt.newWorker()
+	return func(req *fasthttp.Request) error {
+		if err := w.render(req); err != nil {
+			return fmt.Errorf("cannot render request: %s", err)
+		}
+		return nil
+	}
+}
+
+// templateWorker holds templates bound to the state of a single worker
+type templateWorker struct {
+	uri     *template.Template
+	body    *template.Template
+	headers map[string]*template.Template
+
+	data   *dataSet
+	row    []string
+	buf    bytes.Buffer
+	uriBuf []byte
+}
+
+func (rt *requestTemplate) newWorker() *templateWorker {
+	w := &templateWorker{
+		headers: make(map[string]*template.Template, len(rt.headers)),
+		data:    rt.data,
+	}
+	// workers are bound in order they are started, so every worker gets the same data in tests with the same seed
+	funcs := newFaker(derivedSeed("faker", atomic.AddInt64(&rt.workers, 1))).funcs()
+	funcs["col"] = w.col
+	w.uri = bindTemplate(rt.uri, funcs)
+	w.body = bindTemplate(rt.body, funcs)
+	for k, t := range rt.headers {
+		w.headers[k] = bindTemplate(t, funcs)
+	}
+
+	return w
+}
+
+func bindTemplate(t *template.Template, funcs template.FuncMap) *template.Template {
+	if t == nil {
+		return nil
+	}
+	t = template.Must(t.Clone())
+	return t.Funcs(funcs)
+}
+
+func (w *templateWorker) render(req *fasthttp.Request) error {
+	if w.data != nil {
+		w.row = w.data.nextRow()
+		if w.data.timeoutColumn >= 0 {
+			// values were validated while reading data set
+			timeout, _ := parseTimeout(w.row[w.data.timeoutColumn])
+			req.SetTimeout(timeout)
+		}
+	}
+	if w.uri != nil {
+		if err := w.execute(w.uri); err != nil {
+			return err
+		}
+		w.uriBuf = appendEncodedURL(w.uriBuf[:0], w.buf.Bytes())
+		req.SetRequestURIBytes(w.uriBuf)
+	}
+	if w.body != nil {
+		if err := w.execute(w.body); err != nil {
+			return err
+		}
+		req.SetBody(w.buf.Bytes())
+	}
+	for k, t := range w.headers {
+		if err := w.execute(t); err != nil {
+			return err
+		}
+		req.Header.SetBytesV(k, w.buf.Bytes())
+	}
+
+	return nil
+}
+
+func (w *templateWorker) execute(t *template.Template) error {
+	w.buf.Reset()
+	if err := t.Execute(&w.buf, nil); err != nil {
+		return fmt.Errorf("cannot execute template of %s: %s", t.Name(), err)
+	}
+	return nil
+}
+
+// col returns value of column with given name from current row
+func (w *templateWorker) col(name string) (string, error) {
+	if w.data == nil {
+		return "", fmt.Errorf("column %q is referenced, but data file is not set; use -data flag", name)
+	}
+	i, ok := w.data.columns[name]
+	if !ok {
+		return "", fmt.Errorf("column %q is missing in data file header", name)
+	}
+
+	return w.row[i], nil
+}
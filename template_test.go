@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestRequestTemplateModifierError(t *testing.T) {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	// dry run renders only the first row, so missing column of the second row fails while test runs
+	req.SetBodyString(`{{if eq (col "id") "2"}}{{col "missing"}}{{end}}id={{col "id"}}`)
+	data := &dataSet{columns: map[string]int{"id": 0}, rows: [][]string{{"1"}, {"2"}}, timeoutColumn: -1}
+	rt, err := newRequestTemplate(req, "http://localhost/", data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	modify := rt.Modifier()
+	f := func(expectedBody, expectedErr string) {
+		t.Helper()
+		r := new(fasthttp.Request)
+		req.CopyTo(r)
+		err := modify(r)
+		if expectedErr != "" {
+			if err == nil || !strings.Contains(err.Error(), expectedErr) {
+				t.Fatalf("Unexpected error. Got: %v; Expected: %q", err, expectedErr)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if got := string(r.Body()); got != expectedBody {
+			t.Errorf("Unexpected body. Got: %q; Expected: %q", got, expectedBody)
+		}
+	}
+
+	f("id=1", "")
+	f("", `column "missing" is missing in data file header`)
+	// worker keeps rendering next rows after error
+	f("id=1", "")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var thinkTimeFlag = flag.String("think-time", "", "Pause every client after response before its next request, like \"100ms\", or with jitter, like \"100ms±20%\", "+
+	"to simulate users instead of back-to-back requests. Is meant for -load-model concurrency, where achieved rps is -c divided by latency plus think time")
+
+// thinkTime and thinkTimeJitter are parsed -think-time. Jitter is a share in range [0..1]
+var (
+	thinkTime       time.Duration
+	thinkTimeJitter float64
+)
+
+// applyThinkTime parses -think-time
+func applyThinkTime() {
+	if *thinkTimeFlag == "" {
+		return
+	}
+	var err error
+	if thinkTime, thinkTimeJitter, err = parseThinkTime(*thinkTimeFlag); err != nil {
+		usageAndExit(fmt.Sprintf("cannot parse -think-time: %s", err))
+	}
+	if *loadModel == loadModelArrivalRate {
+		usageAndExit("-think-time can't be used with -load-model arrival-rate, since requests are sent regardless of responses")
+	}
+}
+
+// parseThinkTime parses duration with optional jitter in percent like "100ms±20%" or "100ms+-20%"
+func parseThinkTime(s string) (time.Duration, float64, error) {
+	s = strings.ReplaceAll(s, "+-", "±")
+	ds, js, hasJitter := strings.Cut(s, "±")
+	d, err := time.ParseDuration(strings.TrimSpace(ds))
+	if err != nil {
+		return 0, 0, err
+	}
+	if d <= 0 {
+		return 0, 0, fmt.Errorf("think time must be positive; got %s", d)
+	}
+	if !hasJitter {
+		return d, 0, nil
+	}
+	js = strings.TrimSpace(js)
+	if !strings.HasSuffix(js, "%") {
+		return 0, 0, fmt.Errorf("jitter must be in percent like \"20%%\"; got %q", js)
+	}
+	j, err := strconv.ParseFloat(strings.TrimSuffix(js, "%"), 64)
+	if err != nil || j < 0 || j > 100 {
+		return 0, 0, fmt.Errorf("jitter must be percent in range [0..100]; got %q", js)
+	}
+	return d, j / 100, nil
+}
+
+// workerRate is a number of requests and time of previous sample of printState
+var workerRate struct {
+	t        time.Time
+	requests uint64
+}
+
+// perWorkerRate returns rate of requests of single client since previous call
+func perWorkerRate() float64 {
+	now, n := time.Now(), client.RequestSum()
+	prev, prevT := workerRate.requests, workerRate.t
+	workerRate.t, workerRate.requests = now, n
+	if n < prev {
+		// counters of new client start from zero
+		prev = 0
+	}
+	workers := client.Amount()
+	if prevT.IsZero() || workers == 0 || !now.After(prevT) {
+		return 0
+	}
+	return float64(n-prev) / now.Sub(prevT).Seconds() / float64(workers)
+}
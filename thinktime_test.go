@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseThinkTime(t *testing.T) {
+	f := func(s string, d time.Duration, jitter float64, ok bool) {
+		t.Helper()
+		gotD, gotJitter, err := parseThinkTime(s)
+		if (err == nil) != ok {
+			t.Fatalf("Unexpected error for %q: %v", s, err)
+		}
+		if gotD != d || gotJitter != jitter {
+			t.Errorf("Unexpected think time of %q. Got: %s±%v; Expected: %s±%v", s, gotD, gotJitter, d, jitter)
+		}
+	}
+
+	f("100ms", 100*time.Millisecond, 0, true)
+	f("100ms±20%", 100*time.Millisecond, 0.2, true)
+	f("1s +- 50%", time.Second, 0.5, true)
+	f("2s±0%", 2*time.Second, 0, true)
+	f("0s", 0, 0, false)
+	f("-1s", 0, 0, false)
+	f("100", 0, 0, false)
+	f("100ms±20", 0, 0, false)
+	f("100ms±120%", 0, 0, false)
+	f("100ms±x%", 0, 0, false)
+}
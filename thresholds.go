@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// thresholds are limits set by -maxErrRate, -maxP99 and -minRps,
+// which final results of load phase must meet for the process to exit successfully
+type thresholds struct {
+	// maxErrRate is a max percent of failed requests. Is negative if check is disabled
+	maxErrRate float64
+
+	// maxP99 and minRps are zero if checks are disabled
+	maxP99 time.Duration
+	minRps float64
+}
+
+// gate is thresholds set by flags
+var gate = thresholds{maxErrRate: -1}
+
+// applyThresholds validates -maxErrRate, -maxP99 and -minRps
+func applyThresholds() {
+	gate.maxP99, gate.minRps = *maxP99, *minRps
+	if isFlagSet("maxErrRate") {
+		if *maxErrRate < 0 || *maxErrRate > 100 {
+			usageAndExit("-maxErrRate must be in range [0..100]")
+		}
+		gate.maxErrRate = *maxErrRate
+	}
+	if *maxP99 < 0 {
+		usageAndExit("-maxP99 can't be negative")
+	}
+	if *minRps < 0 {
+		usageAndExit("-minRps can't be negative")
+	}
+}
+
+// checks evaluates enabled thresholds against number of requests and errors,
+// p99 latency in seconds and duration of load phase
+func (t thresholds) checks(requests, errs uint64, p99 float64, elapsed time.Duration) []testCheck {
+	var checks []testCheck
+	if t.maxErrRate >= 0 {
+		c := testCheck{name: "max error rate"}
+		var rate float64
+		if requests > 0 {
+			rate = float64(errs) / float64(requests) * 100
+		}
+		if rate > t.maxErrRate {
+			c.failure = fmt.Sprintf("error rate %.3f%% exceeds -maxErrRate %.3f%%", rate, t.maxErrRate)
+		}
+		checks = append(checks, c)
+	}
+	if t.maxP99 > 0 {
+		c := testCheck{name: "max p99"}
+		if p := time.Duration(p99 * float64(time.Second)); p > t.maxP99 {
+			c.failure = fmt.Sprintf("p99 latency %s exceeds -maxP99 %s", p, t.maxP99)
+		}
+		checks = append(checks, c)
+	}
+	if t.minRps > 0 {
+		c := testCheck{name: "min rps"}
+		var rps float64
+		if elapsed > 0 {
+			rps = float64(requests) / elapsed.Seconds()
+		}
+		if rps < t.minRps {
+			c.failure = fmt.Sprintf("throughput %.2f rps is below -minRps %.2f", rps, t.minRps)
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// thresholdFailures returns reasons why results of load phase violate thresholds
+func thresholdFailures() []string {
+	var checks []testCheck
+	if distTotals != nil {
+		// p99 of merged samples is approximate
+		checks = gate.checks(distTotals.RequestSum, distTotals.Errors, distTotals.durations()[0.99], loadElapsed)
+	} else {
+		checks = gate.checks(client.RequestSum(), client.Errors(), client.RequestDuration()[0.99], loadElapsed)
+	}
+	var failures []string
+	for _, c := range checks {
+		if c.failure != "" {
+			failures = append(failures, c.failure)
+		}
+	}
+	return failures
+}
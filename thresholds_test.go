@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThresholdsChecks(t *testing.T) {
+	f := func(th thresholds, requests, errs uint64, p99 float64, elapsed time.Duration, expFailures int) {
+		t.Helper()
+		var failures int
+		for _, c := range th.checks(requests, errs, p99, elapsed) {
+			if c.failure != "" {
+				failures++
+			}
+		}
+		if failures != expFailures {
+			t.Fatalf("expected %d failures; got %d", expFailures, failures)
+		}
+	}
+	disabled := thresholds{maxErrRate: -1}
+	f(disabled, 100, 100, 10, time.Second, 0)
+	f(disabled, 0, 0, 0, 0, 0)
+
+	f(thresholds{maxErrRate: 0}, 100, 0, 0, time.Second, 0)
+	f(thresholds{maxErrRate: 0}, 100, 1, 0, time.Second, 1)
+	f(thresholds{maxErrRate: 1}, 100, 1, 0, time.Second, 0)
+	f(thresholds{maxErrRate: 1}, 100, 2, 0, time.Second, 1)
+
+	f(thresholds{maxErrRate: -1, maxP99: 100 * time.Millisecond}, 100, 0, 0.1, time.Second, 0)
+	f(thresholds{maxErrRate: -1, maxP99: 100 * time.Millisecond}, 100, 0, 0.101, time.Second, 1)
+
+	f(thresholds{maxErrRate: -1, minRps: 100}, 1000, 0, 0, 10*time.Second, 0)
+	f(thresholds{maxErrRate: -1, minRps: 100}, 999, 0, 0, 10*time.Second, 1)
+	f(thresholds{maxErrRate: -1, minRps: 100}, 0, 0, 0, 0, 1)
+
+	f(thresholds{maxErrRate: 1, maxP99: time.Millisecond, minRps: 100}, 10, 5, 1, time.Second, 3)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hagen1778/fasthttploader/fastclient"
+	"github.com/hagen1778/fasthttploader/report"
+)
+
+var (
+	// throttled is a number of throttled responses, which were seen by isThrottled last time
+	throttled uint64
+
+	// throttlingQPS is a qps limit, at which target started to throttle requests during calibrate phase.
+	// Is zero if it didn't
+	throttlingQPS float64
+)
+
+// isThrottled reports whether number of throttled responses grew since it was called last time
+func isThrottled() bool {
+	var n uint64
+	for _, v := range client.Throttled() {
+		n += v
+	}
+	if n == throttled {
+		return false
+	}
+	throttled = n
+	if throttlingQPS == 0 {
+		throttlingQPS = throttle.Limit()
+	}
+	return true
+}
+
+// retryAfterPeriods returns number of -samplePeriod, which cover Retry-After delay d
+func retryAfterPeriods(d time.Duration) int {
+	return int(math.Ceil(float64(d) / float64(*samplePeriod)))
+}
+
+// printThrottled prints number of throttled responses by status and max Retry-After.
+// Qps, at which throttling started, is printed for calibrate phase with -calibThrottling
+func printThrottled() {
+	counts := client.Throttled()
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "Throttled responses: 429: %d; 503 with Retry-After: %d", counts[fastclient.ThrottledTooManyRequests],
+		counts[fastclient.ThrottledUnavailable])
+	if d := client.RetryAfter(); d > 0 {
+		fmt.Fprintf(out, "; max Retry-After: %s", d)
+	}
+	fmt.Fprintln(out)
+	if samplePhase == report.PhaseCalibrate && throttlingQPS > 0 {
+		fmt.Fprintf(out, "Throttling started at qps: %f\n", throttlingQPS)
+	}
+}
@@ -0,0 +1,15 @@
+package main
+
+// applyTimeouts defaults -dialTimeout and -requestTimeout to -t.
+// Explicitly set -httpClientRequestTimeout is kept as timeout of connecting for compatibility
+func applyTimeouts() {
+	if *dialTimeout < 0 || *requestTimeout < 0 || *t < 0 {
+		usageAndExit("Timeouts can't be negative")
+	}
+	if *dialTimeout == 0 && !isFlagSet("httpClientRequestTimeout") {
+		*dialTimeout = *t
+	}
+	if *requestTimeout == 0 {
+		*requestTimeout = *t
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// tlsConfig is a base of TLS configuration of connections to target.
+// Is nil unless TLS flags are set
+var tlsConfig *tls.Config
+
+// newTLSConfig returns TLS configuration with client certificate loaded from certFile and keyFile.
+// Certificate is checked here, so broken certificate fails test before the first request
+func newTLSConfig(insecure bool, certFile, keyFile, serverName string) (*tls.Config, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("-clientCert and -clientKey must be set together")
+	}
+	cfg := &tls.Config{
+		InsecureSkipVerify: insecure,
+		ServerName:         serverName,
+	}
+	if certFile == "" {
+		return cfg, nil
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load client certificate %q with key %q: %s", certFile, keyFile, err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse client certificate %q: %s", certFile, err)
+	}
+	if now := time.Now(); now.After(leaf.NotAfter) || now.Before(leaf.NotBefore) {
+		return nil, fmt.Errorf("client certificate %q is valid only from %s till %s", certFile,
+			leaf.NotBefore.UTC().Format(time.RFC3339), leaf.NotAfter.UTC().Format(time.RFC3339))
+	}
+	cert.Leaf = leaf
+	cfg.Certificates = []tls.Certificate{cert}
+	return cfg, nil
+}
+
+// loadCACerts returns pool of CA certificates from PEM file
+func loadCACerts(file string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read CA certificates: %s", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in CA file %q", file)
+	}
+	return pool, nil
+}
+
+// tlsVersions maps values of -tls-min-version to TLS versions
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion parses TLS version like "1.2"
+func parseTLSVersion(s string) (uint16, error) {
+	v, ok := tlsVersions[s]
+	if !ok {
+		return 0, fmt.Errorf("unsupported TLS version %q; supported versions are 1.0, 1.1, 1.2 and 1.3", s)
+	}
+	return v, nil
+}
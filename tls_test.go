@@ -0,0 +1,110 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCert writes self-signed certificate valid in [notBefore, notAfter] and its key to dir
+func writeClientCert(t *testing.T, dir string, notBefore, notAfter time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("cannot generate key: %s", err)
+	}
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: notBefore, NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("cannot create certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("cannot marshal key: %s", err)
+	}
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatalf("cannot write certificate: %s", err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("cannot write key: %s", err)
+	}
+	return certFile, keyFile
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	cfg, err := newTLSConfig(true, "", "", "api.internal")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !cfg.InsecureSkipVerify || cfg.ServerName != "api.internal" || len(cfg.Certificates) != 0 {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	now := time.Now()
+	certFile, keyFile := writeClientCert(t, t.TempDir(), now.Add(-time.Hour), now.Add(time.Hour))
+	if cfg, err = newTLSConfig(false, certFile, keyFile, ""); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(cfg.Certificates) != 1 || cfg.Certificates[0].Leaf == nil {
+		t.Errorf("Client certificate isn't loaded: %+v", cfg.Certificates)
+	}
+}
+
+func TestNewTLSConfigError(t *testing.T) {
+	f := func(certFile, keyFile string) {
+		t.Helper()
+		if _, err := newTLSConfig(false, certFile, keyFile, ""); err == nil {
+			t.Errorf("Expected error for %q, %q", certFile, keyFile)
+		}
+	}
+
+	now := time.Now()
+	certFile, keyFile := writeClientCert(t, t.TempDir(), now.Add(-time.Hour), now.Add(time.Hour))
+	f(certFile, "")
+	f("", keyFile)
+	f(certFile, certFile)
+	f(filepath.Join(t.TempDir(), "missing.pem"), keyFile)
+
+	expiredCert, expiredKey := writeClientCert(t, t.TempDir(), now.Add(-2*time.Hour), now.Add(-time.Hour))
+	f(expiredCert, expiredKey)
+}
+
+func TestLoadCACerts(t *testing.T) {
+	now := time.Now()
+	certFile, keyFile := writeClientCert(t, t.TempDir(), now.Add(-time.Hour), now.Add(time.Hour))
+	if _, err := loadCACerts(certFile); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, err := loadCACerts(keyFile); err == nil {
+		t.Errorf("Expected error for file without certificates")
+	}
+	if _, err := loadCACerts(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	f := func(s string, expVersion uint16, expOK bool) {
+		t.Helper()
+		v, err := parseTLSVersion(s)
+		if (err == nil) != expOK {
+			t.Fatalf("Unexpected error for %q: %v", s, err)
+		}
+		if v != expVersion {
+			t.Errorf("Expected version %x for %q; got %x", expVersion, s, v)
+		}
+	}
+	f("1.2", tls.VersionTLS12, true)
+	f("1.3", tls.VersionTLS13, true)
+	f("1.4", 0, false)
+	f("TLS1.2", 0, false)
+}
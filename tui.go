@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tuiHistory is a number of samples shown by sparklines of dashboard
+	tuiHistory = 60
+
+	// tuiLogLines is a number of the last lines of output shown under dashboard
+	tuiLogLines = 10
+)
+
+// sparkBlocks are bars of sparkline from the lowest to the highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// dashboard draws full-screen status of test with -tui. Output of stages and log messages
+// are captured while it is shown, their last lines are drawn under dashboard
+// and all of them are printed once it is stopped
+type dashboard struct {
+	mu     sync.Mutex
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+	lines  []string
+
+	requests uint64
+	at       time.Time
+
+	rps, p50, p90, p99, errorRate, conns []float64
+}
+
+var tui *dashboard
+
+// newDashboard switches terminal to alternate screen and starts capturing output
+func newDashboard() *dashboard {
+	d := &dashboard{at: time.Now()}
+	out = dashboardWriter{d: d, buf: &d.stdout}
+	log.SetOutput(dashboardWriter{d: d, buf: &d.stderr})
+	// alternate screen is entered and cursor is hidden
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	return d
+}
+
+// stop restores terminal and prints captured output
+func (d *dashboard) stop() {
+	fmt.Print("\x1b[?25h\x1b[?1049l")
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out = os.Stdout
+	log.SetOutput(os.Stderr)
+	os.Stdout.Write(d.stdout.Bytes())
+	os.Stderr.Write(d.stderr.Bytes())
+	d.stdout.Reset()
+	d.stderr.Reset()
+}
+
+// dashboardWriter captures output to buf and to the last lines of dashboard
+type dashboardWriter struct {
+	d   *dashboard
+	buf *bytes.Buffer
+}
+
+func (w dashboardWriter) Write(p []byte) (int, error) {
+	w.d.mu.Lock()
+	defer w.d.mu.Unlock()
+	w.buf.Write(p)
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			w.d.lines = append(w.d.lines, line)
+		}
+	}
+	if n := len(w.d.lines); n > tuiLogLines {
+		w.d.lines = append(w.d.lines[:0], w.d.lines[n-tuiLogLines:]...)
+	}
+	return len(p), nil
+}
+
+// draw adds current sample to history and redraws dashboard.
+// Rps is measured since previous sample, while requests and errors rate are cumulative within stage
+func (d *dashboard) draw() {
+	now := time.Now()
+	requests, errs := client.RequestSum(), client.Errors()
+	if requests < d.requests {
+		// counters were reset by new stage
+		d.requests = 0
+	}
+	var rps, errorRate float64
+	if s := now.Sub(d.at).Seconds(); s > 0 {
+		rps = float64(requests-d.requests) / s
+	}
+	if requests > 0 {
+		errorRate = float64(errs) / float64(requests) * 100
+	}
+	d.requests, d.at = requests, now
+	q := client.RequestDuration()
+	d.rps = appendHistory(d.rps, rps)
+	d.p50 = appendHistory(d.p50, q[0.5])
+	d.p90 = appendHistory(d.p90, q[0.9])
+	d.p99 = appendHistory(d.p99, q[0.99])
+	d.errorRate = appendHistory(d.errorRate, errorRate)
+	d.conns = appendHistory(d.conns, float64(client.ConnOpen()))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "fasthttploader %s; elapsed: %s\n\n", req.URI().FullURI(), time.Since(testStart).Truncate(time.Second))
+	fmt.Fprintf(&b, "qps limit: %.2f; workers: %d; queued: %d; requests: %d\n\n", qpsLimit(), client.Amount(), client.Overflow(), requests)
+	row := func(name string, h []float64, value string) {
+		fmt.Fprintf(&b, "%-12s %-*s %s\n", name, tuiHistory, sparkline(h), value)
+	}
+	row("rps", d.rps, fmt.Sprintf("%.2f", rps))
+	row("p50", d.p50, formatLatency(q[0.5]))
+	row("p90", d.p90, formatLatency(q[0.9]))
+	row("p99", d.p99, formatLatency(q[0.99]))
+	row("errors", d.errorRate, fmt.Sprintf("%.2f %%", errorRate))
+	row("connections", d.conns, fmt.Sprintf("%d", client.ConnOpen()))
+	b.WriteString("\n")
+	d.mu.Lock()
+	for _, line := range d.lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	d.mu.Unlock()
+	// cursor is moved home and lines are redrawn in place without clearing whole screen, which flickers,
+	// so the rest of every line and of screen is erased
+	io.WriteString(os.Stdout, "\x1b[H"+strings.ReplaceAll(b.String(), "\n", "\x1b[K\n")+"\x1b[J")
+}
+
+// appendHistory appends v to h and keeps the last tuiHistory values
+func appendHistory(h []float64, v float64) []float64 {
+	h = append(h, v)
+	if len(h) > tuiHistory {
+		h = h[len(h)-tuiHistory:]
+	}
+	return h
+}
+
+// sparkline draws values by bars scaled from zero to max of values.
+// NaN values, e.g. percentiles without observations, are drawn by spaces
+func sparkline(values []float64) string {
+	var max float64
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			max = math.Max(max, v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case max == 0:
+			b.WriteRune(sparkBlocks[0])
+		default:
+			b.WriteRune(sparkBlocks[int(v/max*float64(len(sparkBlocks)-1))])
+		}
+	}
+	return b.String()
+}
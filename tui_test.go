@@ -0,0 +1,29 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	f := func(values []float64, exp string) {
+		t.Helper()
+		if s := sparkline(values); s != exp {
+			t.Fatalf("expected sparkline %q of %v; got %q", exp, values, s)
+		}
+	}
+	f(nil, "")
+	f([]float64{0, 0}, "▁▁")
+	f([]float64{0, 3.5, 7}, "▁▄█")
+	f([]float64{math.NaN(), 1, 2}, " ▄█")
+}
+
+func TestAppendHistory(t *testing.T) {
+	var h []float64
+	for i := 0; i < tuiHistory+5; i++ {
+		h = appendHistory(h, float64(i))
+	}
+	if len(h) != tuiHistory || h[0] != 5 || h[len(h)-1] != tuiHistory+4 {
+		t.Fatalf("expected the last %d values; got %v", tuiHistory, h)
+	}
+}
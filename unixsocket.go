@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+var unixSocket = flag.String("unix-socket", "", "Dial connections to Unix domain socket like \"/var/run/app.sock\" instead of host of url, "+
+	"which is still sent in Host header, so services behind local socket are loaded without TCP stack in the way")
+
+// applyUnixSocket checks that -unix-socket is a socket and fits other flags
+func applyUnixSocket() {
+	fi, err := os.Stat(*unixSocket)
+	if err != nil {
+		usageAndExit(fmt.Sprintf("cannot use -unix-socket: %s", err))
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		usageAndExit(fmt.Sprintf("-unix-socket %q isn't a socket", *unixSocket))
+	}
+	if proxy.addr != "" || *localAddrsFlag != "" || *dnsServer != "" || *dnsResolveInterval != 0 || len(resolveFlags) > 0 || *verifyDNS || *dnsRoundRobin {
+		usageAndExit("-unix-socket can't be used with -proxy, -local-addrs, -dns-server, -dns-resolve-interval, -resolve, " +
+			"-verify-dns-distribution or -dns-round-robin, since connections aren't dialed to address of target")
+	}
+	if *regionsFile != "" || *separateFirstRequests || *latencyBreakdown {
+		// local addresses of connections to Unix socket are the same, so connections can't be told apart by them
+		usageAndExit("-unix-socket can't be used with -regions, -first-request-latency-separation or -latency-breakdown")
+	}
+}
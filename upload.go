@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseUploadRate parses rate like "100KB/s" or "1MB" and returns it in bytes per second
+func parseUploadRate(s string) (float64, error) {
+	str := strings.TrimSpace(s)
+	if n := len(str) - len("/s"); n > 0 && strings.EqualFold(str[n:], "/s") {
+		str = str[:n]
+	}
+	n, err := parseBytes(str)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse upload rate %q: %s", s, err)
+	}
+	return float64(n), nil
+}
+
+func applyUploadRate() {
+	var err error
+	if uploadRate, err = parseUploadRate(*uploadRateFlag); err != nil {
+		usageAndExit(err.Error())
+	}
+	if *grpcMethod != "" {
+		usageAndExit("-upload-rate can't be used with -grpc-method")
+	}
+
+	size := uint64(len(req.Body())) + genBodySize
+	if form != nil {
+		size = uint64(form.Size())
+	}
+	for _, f := range targetForms {
+		if f != nil && uint64(f.Size()) > size {
+			size = uint64(f.Size())
+		}
+	}
+	for _, l := range sweepLevels {
+		if *bodySizeSweep != "" && l.value > size {
+			size = l.value
+		}
+	}
+	if size == 0 {
+		usageAndExit("-upload-rate requires request body")
+	}
+	// time of writing of request is bounded by -requestTimeout, so upload can't be slower
+	if d := time.Duration(float64(size) / uploadRate * float64(time.Second)); d >= *requestTimeout {
+		usageAndExit(fmt.Sprintf("Upload of %s body at -upload-rate %s takes %s, which exceeds request timeout %s; increase -t or -requestTimeout",
+			formatBytes(size), *uploadRateFlag, d.Round(time.Millisecond), *requestTimeout))
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseUploadRate(t *testing.T) {
+	f := func(s string, expected float64) {
+		t.Helper()
+		rate, err := parseUploadRate(s)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", s, err)
+		}
+		if rate != expected {
+			t.Errorf("Unexpected rate for %q. Got: %f; Expected: %f", s, rate, expected)
+		}
+	}
+
+	f("100KB/s", 100e3)
+	f("1.5MB/S", 1.5e6)
+	f("512", 512)
+	f(" 10kb/s ", 10e3)
+}
+
+func TestParseUploadRateError(t *testing.T) {
+	for _, s := range []string{"", "/s", "fast", "-1KB/s", "0/s"} {
+		if _, err := parseUploadRate(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
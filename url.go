@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+)
+
+// appendEncodedURL appends url to dst with percent-encoded bytes, which aren't allowed
+// in path and query of URI, like spaces and non-ASCII characters of IRI.
+// Existing percent-encoded characters and reserved characters like "/" and "&"
+// are kept as is, so server receives exactly the given path and query.
+// Host isn't changed
+func appendEncodedURL(dst, url []byte) []byte {
+	start := 0
+	if n := bytes.Index(url, []byte("://")); n >= 0 {
+		start = n + 3
+	}
+	if n := bytes.IndexAny(url[start:], "/?#"); n >= 0 {
+		start += n
+	} else {
+		start = len(url)
+	}
+	dst = append(dst, url[:start]...)
+	for i := start; i < len(url); i++ {
+		c := url[i]
+		switch {
+		case c == '%' && i+2 < len(url) && isHex(url[i+1]) && isHex(url[i+2]):
+			dst = append(dst, c)
+		case isURIChar(c):
+			dst = append(dst, c)
+		default:
+			dst = append(dst, '%', upperHex[c>>4], upperHex[c&15])
+		}
+	}
+	return dst
+}
+
+// encodeURL returns url with percent-encoded bytes which aren't allowed in URI
+func encodeURL(url string) string {
+	return string(appendEncodedURL(nil, []byte(url)))
+}
+
+const upperHex = "0123456789ABCDEF"
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// isURIChar checks whether c is unreserved or reserved character of RFC 3986
+func isURIChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	switch c {
+	case '-', '.', '_', '~', ':', '/', '?', '#', '[', ']', '@', '!', '$', '&', '\'', '(', ')', '*', '+', ',', ';', '=':
+		return true
+	}
+	return false
+}
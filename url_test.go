@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestEncodeURL(t *testing.T) {
+	f := func(url, expected string) {
+		t.Helper()
+		if got := encodeURL(url); got != expected {
+			t.Errorf("Unexpected result for %q. Got: %q; Expected: %q", url, got, expected)
+		}
+	}
+
+	f("http://localhost/search?q=caf%C3%A9&x=a b", "http://localhost/search?q=caf%C3%A9&x=a%20b")
+	f("http://localhost/café/ü?q=é", "http://localhost/caf%C3%A9/%C3%BC?q=%C3%A9")
+	f("http://localhost/a%2Fb/c?x=%20y", "http://localhost/a%2Fb/c?x=%20y")
+	f("http://localhost/100%/x", "http://localhost/100%25/x")
+	f("http://localhost:8080/x?q=a+b&r=[1]#frag", "http://localhost:8080/x?q=a+b&r=[1]#frag")
+	f("http://localhost", "http://localhost")
+	f("http://localhost?q=\"x\"", "http://localhost?q=%22x%22")
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	runtimedebug "runtime/debug"
+)
+
+// buildVersion returns version of fasthttploader module like "v1.2.0".
+// Builds from source get "(devel)" with VCS revision, which is marked "dirty" if tree was modified
+func buildVersion() string {
+	info, ok := runtimedebug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	v := info.Main.Version
+	if v == "" {
+		v = "(devel)"
+	}
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	if revision == "" || v != "(devel)" {
+		return v
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	v += " " + revision
+	if modified == "true" {
+		v += " dirty"
+	}
+	return v
+}
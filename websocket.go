@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"strings"
+)
+
+var (
+	wsFlag = flag.Bool("ws", false, "Upgrade connections of -c clients to WebSocket and send body of request set by -b as message over every of them at -ws-rate. "+
+		"Latency is a round trip until the next message of target. Url may have ws:// or wss:// scheme")
+	wsRate = flag.Float64("ws-rate", 1, "Rate of messages of every WebSocket connection per second. Is used only with -ws")
+)
+
+// applyWebSocket checks flags of -ws mode, where every of -c clients keeps its own WebSocket
+// connection and sends messages at -ws-rate, so rate isn't limited by -q
+func applyWebSocket() {
+	if !isFlagSet("c") {
+		usageAndExit("-ws requires -c, which is a number of WebSocket connections")
+	}
+	if *wsRate <= 0 {
+		usageAndExit("-ws-rate must be positive")
+	}
+	if *loadModel != "" || *q > 0 || *profileFlag != "" || *burstFlag != "" || *rampSteps > 0 || *slaFlag != "" || *concurrencySweep != "" ||
+		*bodySizeSweep != "" || *mode != "" || *correctedLatency || *loadCalibration != "" || *thinkTimeFlag != "" {
+		usageAndExit("-ws can't be used with -load-model, -q, -profile, -burst-pattern, -ramp-steps, -sla, sweeps, -mode, -corrected-latency, " +
+			"-load-calibration or -think-time, since rate of messages is set by -ws-rate")
+	}
+	if *http2 || *http10 || *grpcMethod != "" || len(urls) > 0 || *scenarioFile != "" || *probeFile != "" || *replayFile != "" || *replayHAR != "" ||
+		len(formFields) > 0 || *genBodySizeFlag != "" || *warmupRequests > 0 || *maxConnRequests > 0 || *retryPolicyFlag != "" || *compressBody != "" || isFlagSet("m") {
+		usageAndExit("-ws can't be used with -http2, -http10, -grpc-method, -url, -scenario, -probe, replay, -form, -gen-body-size, " +
+			"-warmup-requests-per-connection, -maxConnRequests, -retry-policy, -compress-body or -m, since messages are sent over upgraded connections")
+	}
+	if strings.HasPrefix(target, "ws://") {
+		target = "http://" + target[len("ws://"):]
+	} else if strings.HasPrefix(target, "wss://") {
+		target = "https://" + target[len("wss://"):]
+	}
+	// every client sends messages on its own schedule, like clients of closed-loop model
+	*loadModel = loadModelConcurrency
+}